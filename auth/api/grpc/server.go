@@ -29,6 +29,14 @@ var (
 		auth.SubscribePermission,
 	}
 
+	defTwinsFilterPermissions = []string{
+		auth.AdminPermission,
+		auth.DeletePermission,
+		auth.EditPermission,
+		auth.ViewPermission,
+		auth.SharePermission,
+	}
+
 	defGroupsFilterPermissions = []string{
 		auth.AdminPermission,
 		auth.DeletePermission,
@@ -489,6 +497,8 @@ func decodeListPermissionsRequest(_ context.Context, grpcReq interface{}) (inter
 	switch req.GetObjectType() {
 	case auth.ThingType:
 		fp = defThingsFilterPermissions
+	case auth.TwinType:
+		fp = defTwinsFilterPermissions
 	case auth.GroupType:
 		fp = defGroupsFilterPermissions
 	case auth.PlatformType:
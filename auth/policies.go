@@ -24,6 +24,7 @@ const (
 const (
 	GroupType    = "group"
 	ThingType    = "thing"
+	TwinType     = "twin"
 	UserType     = "user"
 	DomainType   = "domain"
 	PlatformType = "platform"
@@ -188,7 +188,7 @@ func (svc service) Authorize(ctx context.Context, pr PolicyReq) error {
 			return errors.Wrap(svcerr.ErrAuthentication, err)
 		}
 		if key.Subject == "" {
-			if pr.ObjectType == GroupType || pr.ObjectType == ThingType || pr.ObjectType == DomainType {
+			if pr.ObjectType == GroupType || pr.ObjectType == ThingType || pr.ObjectType == TwinType || pr.ObjectType == DomainType {
 				return errors.ErrDomainAuthorization
 			}
 			return svcerr.ErrAuthentication
@@ -203,8 +203,8 @@ func (svc service) Authorize(ctx context.Context, pr PolicyReq) error {
 }
 
 func (svc service) checkPolicy(ctx context.Context, pr PolicyReq) error {
-	// Domain status is required for if user sent authorization request on things, channels, groups and domains
-	if pr.SubjectType == UserType && (pr.ObjectType == GroupType || pr.ObjectType == ThingType || pr.ObjectType == DomainType) {
+	// Domain status is required for if user sent authorization request on things, twins, channels, groups and domains
+	if pr.SubjectType == UserType && (pr.ObjectType == GroupType || pr.ObjectType == ThingType || pr.ObjectType == TwinType || pr.ObjectType == DomainType) {
 		domainID := pr.Domain
 		if domainID == "" {
 			if pr.ObjectType != DomainType {
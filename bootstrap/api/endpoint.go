@@ -73,6 +73,28 @@ func updateCertEndpoint(svc bootstrap.Service) endpoint.Endpoint {
 	}
 }
 
+func issueCertFromCSREndpoint(svc bootstrap.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(issueCertFromCSRReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		cfg, err := svc.IssueCertFromCSR(ctx, req.token, req.thingID, req.CSR, req.TTL)
+		if err != nil {
+			return nil, err
+		}
+
+		res := updateConfigRes{
+			ThingID:    cfg.ThingID,
+			ClientCert: cfg.ClientCert,
+			CACert:     cfg.CACert,
+		}
+
+		return res, nil
+	}
+}
+
 func viewEndpoint(svc bootstrap.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(entityReq)
@@ -27,6 +27,7 @@ import (
 	"github.com/absmach/magistrala/internal/testsutil"
 	mglog "github.com/absmach/magistrala/logger"
 	"github.com/absmach/magistrala/pkg/errors"
+	svcerr "github.com/absmach/magistrala/pkg/errors/service"
 	mgsdk "github.com/absmach/magistrala/pkg/sdk/go"
 	sdkmocks "github.com/absmach/magistrala/pkg/sdk/mocks"
 	"github.com/stretchr/testify/assert"
@@ -619,6 +620,135 @@ func TestUpdateCert(t *testing.T) {
 	}
 }
 
+func TestIssueCertFromCSR(t *testing.T) {
+	svc, auth, sdk := newService()
+	bs := newBootstrapServer(svc)
+
+	c := newConfig()
+
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: validToken}).Return(&magistrala.IdentityRes{Id: validID}, nil)
+	repoCall1 := sdk.On("Thing", mock.Anything, mock.Anything).Return(mgsdk.Thing{ID: c.ThingID, Credentials: mgsdk.Credentials{Secret: c.ThingKey}}, nil)
+	repoCall2 := sdk.On("Channel", mock.Anything, mock.Anything).Return(toGroup(c.Channels[0]), nil)
+	saved, err := svc.Add(context.Background(), validToken, c)
+	assert.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
+	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
+
+	data := toJSON(map[string]string{"csr": "csr", "ttl": "1h"})
+
+	cases := []struct {
+		desc        string
+		req         string
+		id          string
+		auth        string
+		contentType string
+		sdkErr      errors.SDKError
+		status      int
+	}{
+		{
+			desc:        "issue cert from CSR with invalid token",
+			req:         data,
+			id:          saved.ThingID,
+			auth:        invalidToken,
+			contentType: contentType,
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "issue cert from CSR with an empty token",
+			req:         data,
+			id:          saved.ThingID,
+			auth:        "",
+			contentType: contentType,
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "issue cert from CSR for a valid config",
+			req:         data,
+			id:          saved.ThingID,
+			auth:        validToken,
+			contentType: contentType,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "issue cert from invalid CSR",
+			req:         data,
+			id:          saved.ThingID,
+			auth:        validToken,
+			contentType: contentType,
+			sdkErr:      errors.NewSDKErrorWithStatus(svcerr.ErrMalformedEntity, http.StatusBadRequest),
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "issue cert from CSR with failing certs service",
+			req:         data,
+			id:          saved.ThingID,
+			auth:        validToken,
+			contentType: contentType,
+			sdkErr:      errors.NewSDKErrorWithStatus(svcerr.ErrCreateEntity, http.StatusInternalServerError),
+			status:      http.StatusServiceUnavailable,
+		},
+		{
+			desc:        "issue cert from CSR with wrong content type",
+			req:         data,
+			id:          saved.ThingID,
+			auth:        validToken,
+			contentType: "",
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "issue cert from CSR for a non-existing config",
+			req:         data,
+			id:          wrongID,
+			auth:        validToken,
+			contentType: contentType,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "issue cert from CSR with invalid request format",
+			req:         "}",
+			id:          saved.ThingID,
+			auth:        validToken,
+			contentType: contentType,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "issue cert from an empty CSR",
+			req:         toJSON(map[string]string{"ttl": "1h"}),
+			id:          saved.ThingID,
+			auth:        validToken,
+			contentType: contentType,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "issue cert from CSR with invalid TTL",
+			req:         toJSON(map[string]string{"csr": "csr", "ttl": "1g"}),
+			id:          saved.ThingID,
+			auth:        validToken,
+			contentType: contentType,
+			status:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		repoCall = auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.auth}).Return(&magistrala.IdentityRes{Id: validID}, nil)
+		repoCall1 = sdk.On("IssueCertFromCSR", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mgsdk.Cert{ThingID: tc.id, ClientCert: "cert"}, tc.sdkErr)
+		req := testRequest{
+			client:      bs.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/things/configs/certs/%s/csr", bs.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		repoCall.Unset()
+		repoCall1.Unset()
+	}
+}
+
 func TestUpdateConnections(t *testing.T) {
 	svc, auth, sdk := newService()
 	bs := newBootstrapServer(svc)
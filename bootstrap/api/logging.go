@@ -104,6 +104,26 @@ func (lm *loggingMiddleware) UpdateCert(ctx context.Context, token, thingID, cli
 	return lm.svc.UpdateCert(ctx, token, thingID, clientCert, clientKey, caCert)
 }
 
+// IssueCertFromCSR logs the issue_cert_from_csr request. It logs thing ID, ttl and the time it took to complete the request.
+// If the request fails, it logs the error.
+func (lm *loggingMiddleware) IssueCertFromCSR(ctx context.Context, token, thingID, csr, ttl string) (cfg bootstrap.Config, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("thing_id", thingID),
+			slog.String("ttl", ttl),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Issue bootstrap config certificate from CSR failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Issue bootstrap config certificate from CSR completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.IssueCertFromCSR(ctx, token, thingID, csr, ttl)
+}
+
 // UpdateConnections logs the update_connections request. It logs bootstrap ID and the time it took to complete the request.
 // If the request fails, it logs the error.
 func (lm *loggingMiddleware) UpdateConnections(ctx context.Context, token, id string, connections []string) (err error) {
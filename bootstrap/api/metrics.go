@@ -70,6 +70,16 @@ func (mm *metricsMiddleware) UpdateCert(ctx context.Context, token, thingKey, cl
 	return mm.svc.UpdateCert(ctx, token, thingKey, clientCert, clientKey, caCert)
 }
 
+// IssueCertFromCSR instruments IssueCertFromCSR method with metrics.
+func (mm *metricsMiddleware) IssueCertFromCSR(ctx context.Context, token, thingID, csr, ttl string) (cfg bootstrap.Config, err error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "issue_cert_from_csr").Add(1)
+		mm.latency.With("method", "issue_cert_from_csr").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.IssueCertFromCSR(ctx, token, thingID, csr, ttl)
+}
+
 // UpdateConnections instruments UpdateConnections method with metrics.
 func (mm *metricsMiddleware) UpdateConnections(ctx context.Context, token, id string, connections []string) (err error) {
 	defer func(begin time.Time) {
@@ -4,6 +4,8 @@
 package api
 
 import (
+	"time"
+
 	"github.com/absmach/magistrala/bootstrap"
 	"github.com/absmach/magistrala/internal/apiutil"
 )
@@ -95,6 +97,33 @@ func (req updateCertReq) validate() error {
 	return nil
 }
 
+type issueCertFromCSRReq struct {
+	token   string
+	thingID string
+	CSR     string `json:"csr"`
+	TTL     string `json:"ttl"`
+}
+
+func (req issueCertFromCSRReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.thingID == "" {
+		return apiutil.ErrMissingID
+	}
+
+	if req.CSR == "" || req.TTL == "" {
+		return apiutil.ErrMissingCertData
+	}
+
+	if _, err := time.ParseDuration(req.TTL); err != nil {
+		return apiutil.ErrInvalidCertData
+	}
+
+	return nil
+}
+
 type updateConnReq struct {
 	token    string
 	id       string
@@ -83,6 +83,12 @@ func MakeHandler(svc bootstrap.Service, reader bootstrap.ConfigReader, logger *s
 				encodeResponse,
 				opts...), "update_cert").ServeHTTP)
 
+			r.Post("/certs/{certID}/csr", otelhttp.NewHandler(kithttp.NewServer(
+				issueCertFromCSREndpoint(svc),
+				decodeIssueCertFromCSRRequest,
+				encodeResponse,
+				opts...), "issue_cert_from_csr").ServeHTTP)
+
 			r.Put("/connections/{connID}", otelhttp.NewHandler(kithttp.NewServer(
 				updateConnEndpoint(svc),
 				decodeUpdateConnRequest,
@@ -165,6 +171,22 @@ func decodeUpdateCertRequest(_ context.Context, r *http.Request) (interface{}, e
 	return req, nil
 }
 
+func decodeIssueCertFromCSRRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.Wrap(apiutil.ErrValidation, apiutil.ErrUnsupportedContentType)
+	}
+
+	req := issueCertFromCSRReq{
+		token:   apiutil.ExtractBearerToken(r),
+		thingID: chi.URLParam(r, "certID"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, errors.Wrap(err, errors.ErrMalformedEntity))
+	}
+
+	return req, nil
+}
+
 func decodeUpdateConnRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
 		return nil, errors.Wrap(apiutil.ErrValidation, apiutil.ErrUnsupportedContentType)
@@ -286,6 +308,8 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 		errors.Contains(err, svcerr.ErrMalformedEntity),
 		errors.Contains(err, apiutil.ErrMissingID),
 		errors.Contains(err, apiutil.ErrBootstrapState),
+		errors.Contains(err, apiutil.ErrMissingCertData),
+		errors.Contains(err, apiutil.ErrInvalidCertData),
 		errors.Contains(err, apiutil.ErrLimitSize):
 		w.WriteHeader(http.StatusBadRequest)
 	case errors.Contains(err, svcerr.ErrNotFound):
@@ -296,7 +320,8 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 		w.WriteHeader(http.StatusForbidden)
 	case errors.Contains(err, svcerr.ErrConflict):
 		w.WriteHeader(http.StatusConflict)
-	case errors.Contains(err, bootstrap.ErrThings):
+	case errors.Contains(err, bootstrap.ErrThings),
+		errors.Contains(err, bootstrap.ErrCerts):
 		w.WriteHeader(http.StatusServiceUnavailable)
 
 	case errors.Contains(err, svcerr.ErrCreateEntity),
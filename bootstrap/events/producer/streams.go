@@ -91,6 +91,26 @@ func (es eventStore) UpdateCert(ctx context.Context, token, thingKey, clientCert
 	return cfg, nil
 }
 
+func (es eventStore) IssueCertFromCSR(ctx context.Context, token, thingID, csr, ttl string) (bootstrap.Config, error) {
+	cfg, err := es.svc.IssueCertFromCSR(ctx, token, thingID, csr, ttl)
+	if err != nil {
+		return cfg, err
+	}
+
+	ev := updateCertEvent{
+		thingKey:   thingID,
+		clientCert: cfg.ClientCert,
+		clientKey:  cfg.ClientKey,
+		caCert:     cfg.CACert,
+	}
+
+	if err := es.Publish(ctx, ev); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
 func (es *eventStore) UpdateConnections(ctx context.Context, token, id string, connections []string) error {
 	if err := es.svc.UpdateConnections(ctx, token, id, connections); err != nil {
 		return err
@@ -8,6 +8,7 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"encoding/hex"
+	"net/http"
 	"time"
 
 	"github.com/absmach/magistrala"
@@ -30,6 +31,10 @@ var (
 	// ErrBootstrap indicates error in getting bootstrap configuration.
 	ErrBootstrap = errors.New("failed to read bootstrap configuration")
 
+	// ErrCerts indicates failure to communicate with Magistrala Certs service.
+	// It can be due to networking error or invalid/unauthenticated request.
+	ErrCerts = errors.New("failed to receive response from Certs service")
+
 	errAddBootstrap       = errors.New("failed to add bootstrap configuration")
 	errUpdateConnections  = errors.New("failed to update connections")
 	errRemoveBootstrap    = errors.New("failed to remove bootstrap configuration")
@@ -43,6 +48,7 @@ var (
 	errConnectionChannels = errors.New("failed to check channels connections")
 	errThingNotFound      = errors.New("failed to find thing")
 	errUpdateCert         = errors.New("failed to update cert")
+	errIssueCert          = errors.New("failed to issue cert")
 )
 
 var _ Service = (*bootstrapService)(nil)
@@ -63,6 +69,11 @@ type Service interface {
 	// A non-nil error is returned to indicate operation failure.
 	UpdateCert(ctx context.Context, token, thingID, clientCert, clientKey, caCert string) (Config, error)
 
+	// IssueCertFromCSR issues the Config client certificate by signing the PEM
+	// encoded PKCS#10 certificate signing request of the Thing. Only the issued
+	// certificate is stored, so the Thing private key never leaves the device.
+	IssueCertFromCSR(ctx context.Context, token, thingID, csr, ttl string) (Config, error)
+
 	// UpdateConnections updates list of Channels related to given Config.
 	UpdateConnections(ctx context.Context, token, id string, connections []string) error
 
@@ -204,6 +215,32 @@ func (bs bootstrapService) UpdateCert(ctx context.Context, token, thingID, clien
 	return cfg, nil
 }
 
+func (bs bootstrapService) IssueCertFromCSR(ctx context.Context, token, thingID, csr, ttl string) (Config, error) {
+	owner, err := bs.identify(ctx, token)
+	if err != nil {
+		return Config{}, errors.Wrap(svcerr.ErrAuthentication, err)
+	}
+	cfg, err := bs.configs.RetrieveByID(ctx, owner, thingID)
+	if err != nil {
+		return Config{}, errors.Wrap(errIssueCert, err)
+	}
+
+	cert, sdkErr := bs.sdk.IssueCertFromCSR(thingID, csr, ttl, token)
+	if sdkErr != nil {
+		if sdkErr.StatusCode() == http.StatusBadRequest {
+			return Config{}, errors.Wrap(svcerr.ErrMalformedEntity, sdkErr)
+		}
+		return Config{}, errors.Wrap(ErrCerts, sdkErr)
+	}
+
+	// The private key, if any, is cleared since it does not match the certificate.
+	cfg, err = bs.configs.UpdateCert(ctx, owner, thingID, cert.ClientCert, "", cfg.CACert)
+	if err != nil {
+		return Config{}, errors.Wrap(errIssueCert, err)
+	}
+	return cfg, nil
+}
+
 func (bs bootstrapService) UpdateConnections(ctx context.Context, token, id string, connections []string) error {
 	owner, err := bs.identify(ctx, token)
 	if err != nil {
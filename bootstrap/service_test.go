@@ -11,6 +11,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
+	"net/http"
 	"sort"
 	"testing"
 
@@ -327,6 +328,96 @@ func TestUpdateCert(t *testing.T) {
 	}
 }
 
+func TestIssueCertFromCSR(t *testing.T) {
+	svc, auth, sdk := newService()
+
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: validToken}).Return(&magistrala.IdentityRes{Id: validID}, nil)
+	repoCall1 := sdk.On("Thing", mock.Anything, mock.Anything).Return(mgsdk.Thing{ID: config.ThingID, Credentials: mgsdk.Credentials{Secret: config.ThingKey}}, nil)
+	repoCall2 := sdk.On("Channel", mock.Anything, mock.Anything).Return(mgsdk.Channel{}, nil)
+	saved, err := svc.Add(context.Background(), validToken, config)
+	assert.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
+	saved, err = svc.UpdateCert(context.Background(), validToken, saved.ThingID, "oldCert", "oldKey", "caCert")
+	assert.Nil(t, err, fmt.Sprintf("Updating config cert expected to succeed: %s.\n", err))
+	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
+
+	expected := saved
+	expected.ClientCert = "newCert"
+	expected.ClientKey = ""
+
+	cases := []struct {
+		desc           string
+		token          string
+		thingID        string
+		csr            string
+		cert           mgsdk.Cert
+		sdkErr         errors.SDKError
+		expectedConfig bootstrap.Config
+		err            error
+	}{
+		{
+			desc:           "issue cert from CSR for the valid config",
+			token:          validToken,
+			thingID:        saved.ThingID,
+			csr:            "csr",
+			cert:           mgsdk.Cert{ThingID: saved.ThingID, ClientCert: "newCert"},
+			expectedConfig: expected,
+			err:            nil,
+		},
+		{
+			desc:           "issue cert from invalid CSR",
+			token:          validToken,
+			thingID:        saved.ThingID,
+			csr:            invalidToken,
+			sdkErr:         errors.NewSDKErrorWithStatus(svcerr.ErrMalformedEntity, http.StatusBadRequest),
+			expectedConfig: bootstrap.Config{},
+			err:            svcerr.ErrMalformedEntity,
+		},
+		{
+			desc:           "issue cert from CSR with failing certs service",
+			token:          validToken,
+			thingID:        saved.ThingID,
+			csr:            "csr",
+			sdkErr:         errors.NewSDKErrorWithStatus(svcerr.ErrCreateEntity, http.StatusInternalServerError),
+			expectedConfig: bootstrap.Config{},
+			err:            bootstrap.ErrCerts,
+		},
+		{
+			desc:           "issue cert from CSR for a non-existing config",
+			token:          validToken,
+			thingID:        unknown,
+			csr:            "csr",
+			expectedConfig: bootstrap.Config{},
+			err:            svcerr.ErrNotFound,
+		},
+		{
+			desc:           "issue cert from CSR with wrong credentials",
+			token:          invalidToken,
+			thingID:        saved.ThingID,
+			csr:            "csr",
+			expectedConfig: bootstrap.Config{},
+			err:            svcerr.ErrAuthentication,
+		},
+	}
+
+	for _, tc := range cases {
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: validID}, nil)
+		repoCall1 := sdk.On("IssueCertFromCSR", tc.thingID, tc.csr, mock.Anything, tc.token).Return(tc.cert, tc.sdkErr)
+		cfg, err := svc.IssueCertFromCSR(context.Background(), tc.token, tc.thingID, tc.csr, "1h")
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		sort.Slice(cfg.Channels, func(i, j int) bool {
+			return cfg.Channels[i].ID < cfg.Channels[j].ID
+		})
+		sort.Slice(tc.expectedConfig.Channels, func(i, j int) bool {
+			return tc.expectedConfig.Channels[i].ID < tc.expectedConfig.Channels[j].ID
+		})
+		assert.Equal(t, tc.expectedConfig, cfg, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.expectedConfig, cfg))
+		repoCall.Unset()
+		repoCall1.Unset()
+	}
+}
+
 func TestUpdateConnections(t *testing.T) {
 	svc, auth, sdk := newService()
 	c := config
@@ -71,6 +71,17 @@ func (tm *tracingMiddleware) UpdateCert(ctx context.Context, token, thingID, cli
 	return tm.svc.UpdateCert(ctx, token, thingID, clientCert, clientKey, caCert)
 }
 
+// IssueCertFromCSR traces the "IssueCertFromCSR" operation of the wrapped bootstrap.Service.
+func (tm *tracingMiddleware) IssueCertFromCSR(ctx context.Context, token, thingID, csr, ttl string) (bootstrap.Config, error) {
+	ctx, span := tm.tracer.Start(ctx, "svc_issue_cert_from_csr", trace.WithAttributes(
+		attribute.String("thing_id", thingID),
+		attribute.String("ttl", ttl),
+	))
+	defer span.End()
+
+	return tm.svc.IssueCertFromCSR(ctx, token, thingID, csr, ttl)
+}
+
 // UpdateConnections traces the "UpdateConnections" operation of the wrapped bootstrap.Service.
 func (tm *tracingMiddleware) UpdateConnections(ctx context.Context, token, id string, connections []string) error {
 	ctx, span := tm.tracer.Start(ctx, "svc_update_connections", trace.WithAttributes(
@@ -34,6 +34,49 @@ func issueCert(svc certs.Service) endpoint.Endpoint {
 	}
 }
 
+func issueCertFromCSR(svc certs.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(csrReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+		res, err := svc.IssueCertFromCSR(ctx, req.token, req.ThingID, req.CSR, req.TTL)
+		if err != nil {
+			return nil, err
+		}
+
+		return certsRes{
+			CertSerial: res.Serial,
+			ThingID:    res.ThingID,
+			ClientCert: res.ClientCert,
+			Expiration: res.Expire,
+			created:    true,
+		}, nil
+	}
+}
+
+func renewCert(svc certs.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(renewReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+		res, err := svc.RenewCert(ctx, req.token, req.serialID, req.TTL, req.ReuseKey)
+		if err != nil {
+			return nil, err
+		}
+
+		return certsRes{
+			CertSerial: res.Serial,
+			ThingID:    res.ThingID,
+			ClientCert: res.ClientCert,
+			ClientKey:  res.ClientKey,
+			Expiration: res.Expire,
+			created:    true,
+		}, nil
+	}
+}
+
 func listSerials(svc certs.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(listReq)
@@ -41,7 +84,7 @@ func listSerials(svc certs.Service) endpoint.Endpoint {
 			return nil, errors.Wrap(apiutil.ErrValidation, err)
 		}
 
-		page, err := svc.ListSerials(ctx, req.token, req.thingID, req.offset, req.limit)
+		page, err := svc.ListSerials(ctx, req.token, req.thingID, req.pm)
 		if err != nil {
 			return certsPageRes{}, errors.Wrap(apiutil.ErrValidation, err)
 		}
@@ -102,3 +145,46 @@ func revokeCert(svc certs.Service) endpoint.Endpoint {
 		}, nil
 	}
 }
+
+func crl(svc certs.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		data, err := svc.CRL(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return derRes{contentType: crlContentType, data: data}, nil
+	}
+}
+
+func ocsp(svc certs.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ocspReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		data, err := svc.OCSP(ctx, req.data)
+		if err != nil {
+			return nil, err
+		}
+
+		return derRes{contentType: ocspResType, data: data}, nil
+	}
+}
+
+func identifyThing(svc certs.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(identifyReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		thingID, err := svc.IdentifyThing(ctx, req.cert)
+		if err != nil {
+			return nil, err
+		}
+
+		return identifyRes{ThingID: thingID}, nil
+	}
+}
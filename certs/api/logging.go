@@ -45,8 +45,51 @@ func (lm *loggingMiddleware) IssueCert(ctx context.Context, token, thingID, ttl
 	return lm.svc.IssueCert(ctx, token, thingID, ttl)
 }
 
+// IssueCertFromCSR logs the issue_cert_from_csr request. It logs the ttl, thing ID and the time it took to complete the request.
+// If the request fails, it logs the error.
+func (lm *loggingMiddleware) IssueCertFromCSR(ctx context.Context, token, thingID, csr, ttl string) (c certs.Cert, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("thing_id", thingID),
+			slog.String("ttl", ttl),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Issue certificate from CSR failed to complete successfully", args...)
+			return
+		}
+		args = append(args, slog.String("serial_id", c.Serial))
+		lm.logger.Info("Issue certificate from CSR completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.IssueCertFromCSR(ctx, token, thingID, csr, ttl)
+}
+
+// RenewCert logs the renew_cert request. It logs the serial ID, ttl, key reuse and the time it took to complete the request.
+// If the request fails, it logs the error.
+func (lm *loggingMiddleware) RenewCert(ctx context.Context, token, serialID, ttl string, reuseKey bool) (c certs.Cert, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("serial_id", serialID),
+			slog.String("ttl", ttl),
+			slog.Bool("reuse_key", reuseKey),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Renew certificate failed to complete successfully", args...)
+			return
+		}
+		args = append(args, slog.String("renewed_serial_id", c.Serial))
+		lm.logger.Info("Renew certificate completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.RenewCert(ctx, token, serialID, ttl, reuseKey)
+}
+
 // ListCerts logs the list_certs request. It logs the thing ID and the time it took to complete the request.
-func (lm *loggingMiddleware) ListCerts(ctx context.Context, token, thingID string, offset, limit uint64) (cp certs.Page, err error) {
+func (lm *loggingMiddleware) ListCerts(ctx context.Context, token, thingID string, pm certs.PageMetadata) (cp certs.Page, err error) {
 	defer func(begin time.Time) {
 		args := []any{
 			slog.String("duration", time.Since(begin).String()),
@@ -65,12 +108,12 @@ func (lm *loggingMiddleware) ListCerts(ctx context.Context, token, thingID strin
 		lm.logger.Info("List certificates completed successfully", args...)
 	}(time.Now())
 
-	return lm.svc.ListCerts(ctx, token, thingID, offset, limit)
+	return lm.svc.ListCerts(ctx, token, thingID, pm)
 }
 
 // ListSerials logs the list_serials request. It logs the thing ID and the time it took to complete the request.
 // If the request fails, it logs the error.
-func (lm *loggingMiddleware) ListSerials(ctx context.Context, token, thingID string, offset, limit uint64) (cp certs.Page, err error) {
+func (lm *loggingMiddleware) ListSerials(ctx context.Context, token, thingID string, pm certs.PageMetadata) (cp certs.Page, err error) {
 	defer func(begin time.Time) {
 		args := []any{
 			slog.String("duration", time.Since(begin).String()),
@@ -89,7 +132,7 @@ func (lm *loggingMiddleware) ListSerials(ctx context.Context, token, thingID str
 		lm.logger.Info("List certificates serials completed successfully", args...)
 	}(time.Now())
 
-	return lm.svc.ListSerials(ctx, token, thingID, offset, limit)
+	return lm.svc.ListSerials(ctx, token, thingID, pm)
 }
 
 // ViewCert logs the view_cert request. It logs the serial ID and the time it took to complete the request.
@@ -129,3 +172,58 @@ func (lm *loggingMiddleware) RevokeCert(ctx context.Context, token, thingID stri
 
 	return lm.svc.RevokeCert(ctx, token, thingID)
 }
+
+// CRL logs the crl request. It logs the time it took to complete the request.
+// If the request fails, it logs the error.
+func (lm *loggingMiddleware) CRL(ctx context.Context) (crl []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Get certificate revocation list failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Get certificate revocation list completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.CRL(ctx)
+}
+
+// OCSP logs the ocsp request. It logs the time it took to complete the request.
+// If the request fails, it logs the error.
+func (lm *loggingMiddleware) OCSP(ctx context.Context, req []byte) (res []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Check certificate status failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Check certificate status completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.OCSP(ctx, req)
+}
+
+// IdentifyThing logs the identify_thing request. It logs the thing ID and the time it took to complete the request.
+// If the request fails, it logs the error.
+func (lm *loggingMiddleware) IdentifyThing(ctx context.Context, cert []byte) (thingID string, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Identify thing by certificate failed to complete successfully", args...)
+			return
+		}
+		args = append(args, slog.String("thing_id", thingID))
+		lm.logger.Info("Identify thing by certificate completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.IdentifyThing(ctx, cert)
+}
@@ -40,24 +40,44 @@ func (ms *metricsMiddleware) IssueCert(ctx context.Context, token, thingID, ttl
 	return ms.svc.IssueCert(ctx, token, thingID, ttl)
 }
 
+// IssueCertFromCSR instruments IssueCertFromCSR method with metrics.
+func (ms *metricsMiddleware) IssueCertFromCSR(ctx context.Context, token, thingID, csr, ttl string) (certs.Cert, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "issue_cert_from_csr").Add(1)
+		ms.latency.With("method", "issue_cert_from_csr").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.IssueCertFromCSR(ctx, token, thingID, csr, ttl)
+}
+
+// RenewCert instruments RenewCert method with metrics.
+func (ms *metricsMiddleware) RenewCert(ctx context.Context, token, serialID, ttl string, reuseKey bool) (certs.Cert, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "renew_cert").Add(1)
+		ms.latency.With("method", "renew_cert").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RenewCert(ctx, token, serialID, ttl, reuseKey)
+}
+
 // ListCerts instruments ListCerts method with metrics.
-func (ms *metricsMiddleware) ListCerts(ctx context.Context, token, thingID string, offset, limit uint64) (certs.Page, error) {
+func (ms *metricsMiddleware) ListCerts(ctx context.Context, token, thingID string, pm certs.PageMetadata) (certs.Page, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "list_certs").Add(1)
 		ms.latency.With("method", "list_certs").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.ListCerts(ctx, token, thingID, offset, limit)
+	return ms.svc.ListCerts(ctx, token, thingID, pm)
 }
 
 // ListSerials instruments ListSerials method with metrics.
-func (ms *metricsMiddleware) ListSerials(ctx context.Context, token, thingID string, offset, limit uint64) (certs.Page, error) {
+func (ms *metricsMiddleware) ListSerials(ctx context.Context, token, thingID string, pm certs.PageMetadata) (certs.Page, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "list_serials").Add(1)
 		ms.latency.With("method", "list_serials").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.ListSerials(ctx, token, thingID, offset, limit)
+	return ms.svc.ListSerials(ctx, token, thingID, pm)
 }
 
 // ViewCert instruments ViewCert method with metrics.
@@ -79,3 +99,33 @@ func (ms *metricsMiddleware) RevokeCert(ctx context.Context, token, thingID stri
 
 	return ms.svc.RevokeCert(ctx, token, thingID)
 }
+
+// CRL instruments CRL method with metrics.
+func (ms *metricsMiddleware) CRL(ctx context.Context) ([]byte, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "crl").Add(1)
+		ms.latency.With("method", "crl").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CRL(ctx)
+}
+
+// OCSP instruments OCSP method with metrics.
+func (ms *metricsMiddleware) OCSP(ctx context.Context, req []byte) ([]byte, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "ocsp").Add(1)
+		ms.latency.With("method", "ocsp").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.OCSP(ctx, req)
+}
+
+// IdentifyThing instruments IdentifyThing method with metrics.
+func (ms *metricsMiddleware) IdentifyThing(ctx context.Context, cert []byte) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "identify_thing").Add(1)
+		ms.latency.With("method", "identify_thing").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.IdentifyThing(ctx, cert)
+}
@@ -6,6 +6,7 @@ package api
 import (
 	"time"
 
+	"github.com/absmach/magistrala/certs"
 	"github.com/absmach/magistrala/internal/apiutil"
 )
 
@@ -37,18 +38,69 @@ func (req addCertsReq) validate() error {
 	return nil
 }
 
+type csrReq struct {
+	token   string
+	ThingID string `json:"thing_id"`
+	CSR     string `json:"csr"`
+	TTL     string `json:"ttl"`
+}
+
+func (req csrReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.ThingID == "" {
+		return apiutil.ErrMissingID
+	}
+
+	if req.CSR == "" || req.TTL == "" {
+		return apiutil.ErrMissingCertData
+	}
+
+	if _, err := time.ParseDuration(req.TTL); err != nil {
+		return apiutil.ErrInvalidCertData
+	}
+
+	return nil
+}
+
+type renewReq struct {
+	token    string
+	serialID string
+	TTL      string `json:"ttl"`
+	ReuseKey bool   `json:"reuse_key"`
+}
+
+func (req renewReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.serialID == "" {
+		return apiutil.ErrMissingID
+	}
+
+	if req.TTL != "" {
+		if _, err := time.ParseDuration(req.TTL); err != nil {
+			return apiutil.ErrInvalidCertData
+		}
+	}
+
+	return nil
+}
+
 type listReq struct {
 	thingID string
 	token   string
-	offset  uint64
-	limit   uint64
+	pm      certs.PageMetadata
 }
 
 func (req *listReq) validate() error {
 	if req.token == "" {
 		return apiutil.ErrBearerToken
 	}
-	if req.limit > maxLimitSize {
+	if req.pm.Limit > maxLimitSize {
 		return apiutil.ErrLimitSize
 	}
 	return nil
@@ -86,3 +138,29 @@ func (req *revokeReq) validate() error {
 
 	return nil
 }
+
+type crlReq struct{}
+
+type ocspReq struct {
+	data []byte
+}
+
+func (req ocspReq) validate() error {
+	if len(req.data) == 0 {
+		return apiutil.ErrMissingCertData
+	}
+
+	return nil
+}
+
+type identifyReq struct {
+	cert []byte
+}
+
+func (req identifyReq) validate() error {
+	if len(req.cert) == 0 {
+		return apiutil.ErrMissingCertData
+	}
+
+	return nil
+}
@@ -22,12 +22,22 @@ type certsPageRes struct {
 type certsRes struct {
 	ThingID    string    `json:"thing_id"`
 	ClientCert string    `json:"client_cert"`
-	ClientKey  string    `json:"client_key"`
+	ClientKey  string    `json:"client_key,omitempty"`
 	CertSerial string    `json:"cert_serial"`
 	Expiration time.Time `json:"expiration"`
 	created    bool
 }
 
+// derRes is the DER encoded revocation status response.
+type derRes struct {
+	contentType string
+	data        []byte
+}
+
+type identifyRes struct {
+	ThingID string `json:"thing_id"`
+}
+
 type revokeCertsRes struct {
 	RevocationTime time.Time `json:"revocation_time"`
 }
@@ -71,3 +81,15 @@ func (res revokeCertsRes) Headers() map[string]string {
 func (res revokeCertsRes) Empty() bool {
 	return false
 }
+
+func (res identifyRes) Code() int {
+	return http.StatusOK
+}
+
+func (res identifyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res identifyRes) Empty() bool {
+	return false
+}
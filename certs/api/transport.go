@@ -5,12 +5,17 @@ package api
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/absmach/magistrala"
 	"github.com/absmach/magistrala/certs"
+	"github.com/absmach/magistrala/certs/pki"
 	"github.com/absmach/magistrala/internal/apiutil"
 	"github.com/absmach/magistrala/pkg/errors"
 	svcerr "github.com/absmach/magistrala/pkg/errors/service"
@@ -18,14 +23,23 @@ import (
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	ocspcrypto "golang.org/x/crypto/ocsp"
 )
 
 const (
-	contentType = "application/json"
-	offsetKey   = "offset"
-	limitKey    = "limit"
-	defOffset   = 0
-	defLimit    = 10
+	contentType     = "application/json"
+	crlContentType  = "application/pkix-crl"
+	ocspReqType     = "application/ocsp-request"
+	ocspResType     = "application/ocsp-response"
+	certContentType = "application/pkix-cert"
+	offsetKey       = "offset"
+	limitKey        = "limit"
+	expAfterKey     = "expires_after"
+	expBeforeKey    = "expires_before"
+	defOffset       = 0
+	defLimit        = 10
+	maxOCSPReqBytes = 1 << 16
+	maxCertBytes    = 1 << 16
 )
 
 // MakeHandler returns a HTTP handler for API endpoints.
@@ -33,6 +47,9 @@ func MakeHandler(svc certs.Service, logger *slog.Logger, instanceID string) http
 	opts := []kithttp.ServerOption{
 		kithttp.ServerErrorEncoder(apiutil.LoggingErrorEncoder(logger, encodeError)),
 	}
+	ocspOpts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(apiutil.LoggingErrorEncoder(logger, encodeOCSPError)),
+	}
 
 	r := chi.NewRouter()
 
@@ -43,6 +60,18 @@ func MakeHandler(svc certs.Service, logger *slog.Logger, instanceID string) http
 			encodeResponse,
 			opts...,
 		), "issue").ServeHTTP)
+		r.Post("/csr", otelhttp.NewHandler(kithttp.NewServer(
+			issueCertFromCSR(svc),
+			decodeCSR,
+			encodeResponse,
+			opts...,
+		), "issue_from_csr").ServeHTTP)
+		r.Post("/identify", otelhttp.NewHandler(kithttp.NewServer(
+			identifyThing(svc),
+			decodeIdentifyThing,
+			encodeResponse,
+			opts...,
+		), "identify_thing").ServeHTTP)
 		r.Get("/{certID}", otelhttp.NewHandler(kithttp.NewServer(
 			viewCert(svc),
 			decodeViewCert,
@@ -55,6 +84,12 @@ func MakeHandler(svc certs.Service, logger *slog.Logger, instanceID string) http
 			encodeResponse,
 			opts...,
 		), "revoke").ServeHTTP)
+		r.Post("/{certID}/renew", otelhttp.NewHandler(kithttp.NewServer(
+			renewCert(svc),
+			decodeRenewCert,
+			encodeResponse,
+			opts...,
+		), "renew").ServeHTTP)
 	})
 	r.Get("/serials/{thingID}", otelhttp.NewHandler(kithttp.NewServer(
 		listSerials(svc),
@@ -63,6 +98,29 @@ func MakeHandler(svc certs.Service, logger *slog.Logger, instanceID string) http
 		opts...,
 	), "list_serials").ServeHTTP)
 
+	// Revocation status endpoints are public, so the relying parties can
+	// check the certificates without the credentials.
+	r.Get("/crl", otelhttp.NewHandler(kithttp.NewServer(
+		crl(svc),
+		decodeCRL,
+		encodeDERResponse,
+		opts...,
+	), "crl").ServeHTTP)
+	r.Route("/ocsp", func(r chi.Router) {
+		r.Post("/", otelhttp.NewHandler(kithttp.NewServer(
+			ocsp(svc),
+			decodeOCSP,
+			encodeDERResponse,
+			ocspOpts...,
+		), "ocsp").ServeHTTP)
+		r.Get("/*", otelhttp.NewHandler(kithttp.NewServer(
+			ocsp(svc),
+			decodeOCSPURL,
+			encodeDERResponse,
+			ocspOpts...,
+		), "ocsp").ServeHTTP)
+	})
+
 	r.Handle("/metrics", promhttp.Handler())
 	r.Get("/health", magistrala.Health("certs", instanceID))
 
@@ -87,6 +145,60 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 	return json.NewEncoder(w).Encode(response)
 }
 
+func encodeDERResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	res := response.(derRes)
+	w.Header().Set("Content-Type", res.contentType)
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(res.data)
+
+	return err
+}
+
+func decodeCRL(_ context.Context, _ *http.Request) (interface{}, error) {
+	return crlReq{}, nil
+}
+
+func decodeOCSP(_ context.Context, r *http.Request) (interface{}, error) {
+	if r.Header.Get("Content-Type") != ocspReqType {
+		return nil, errors.Wrap(apiutil.ErrValidation, apiutil.ErrUnsupportedContentType)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxOCSPReqBytes))
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	return ocspReq{data: data}, nil
+}
+
+// decodeOCSPURL decodes the base64 encoded OCSP request of the GET request
+// URL, as specified by RFC 6960.
+func decodeOCSPURL(_ context.Context, r *http.Request) (interface{}, error) {
+	enc, err := url.PathUnescape(chi.URLParam(r, "*"))
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, errors.Wrap(apiutil.ErrInvalidCertData, err))
+	}
+	data, err := base64.StdEncoding.DecodeString(enc)
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, errors.Wrap(apiutil.ErrInvalidCertData, err))
+	}
+
+	return ocspReq{data: data}, nil
+}
+
+func decodeIdentifyThing(_ context.Context, r *http.Request) (interface{}, error) {
+	if r.Header.Get("Content-Type") != certContentType {
+		return nil, errors.Wrap(apiutil.ErrValidation, apiutil.ErrUnsupportedContentType)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxCertBytes))
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	return identifyReq{cert: data}, nil
+}
+
 func decodeListCerts(_ context.Context, r *http.Request) (interface{}, error) {
 	l, err := apiutil.ReadNumQuery[uint64](r, limitKey, defLimit)
 	if err != nil {
@@ -96,16 +208,44 @@ func decodeListCerts(_ context.Context, r *http.Request) (interface{}, error) {
 	if err != nil {
 		return nil, errors.Wrap(apiutil.ErrValidation, err)
 	}
+	ea, err := readTimeQuery(r, expAfterKey)
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
+	eb, err := readTimeQuery(r, expBeforeKey)
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
 
 	req := listReq{
 		token:   apiutil.ExtractBearerToken(r),
 		thingID: chi.URLParam(r, "thingID"),
-		limit:   l,
-		offset:  o,
+		pm: certs.PageMetadata{
+			Offset:        o,
+			Limit:         l,
+			ExpiresAfter:  ea,
+			ExpiresBefore: eb,
+		},
 	}
 	return req, nil
 }
 
+// readTimeQuery reads the RFC3339 formatted time query parameter. Zero time
+// is returned if the parameter is not set.
+func readTimeQuery(r *http.Request, key string) (time.Time, error) {
+	val, err := apiutil.ReadStringQuery(r, key, "")
+	if err != nil || val == "" {
+		return time.Time{}, err
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, val)
+	if err != nil {
+		return time.Time{}, errors.Wrap(apiutil.ErrInvalidQueryParams, err)
+	}
+
+	return t, nil
+}
+
 func decodeViewCert(_ context.Context, r *http.Request) (interface{}, error) {
 	req := viewReq{
 		token:    apiutil.ExtractBearerToken(r),
@@ -115,6 +255,22 @@ func decodeViewCert(_ context.Context, r *http.Request) (interface{}, error) {
 	return req, nil
 }
 
+func decodeRenewCert(_ context.Context, r *http.Request) (interface{}, error) {
+	if r.Header.Get("Content-Type") != contentType {
+		return nil, errors.Wrap(apiutil.ErrValidation, apiutil.ErrUnsupportedContentType)
+	}
+
+	req := renewReq{
+		token:    apiutil.ExtractBearerToken(r),
+		serialID: chi.URLParam(r, "certID"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	return req, nil
+}
+
 func decodeCerts(_ context.Context, r *http.Request) (interface{}, error) {
 	if r.Header.Get("Content-Type") != contentType {
 		return nil, errors.Wrap(apiutil.ErrValidation, apiutil.ErrUnsupportedContentType)
@@ -128,6 +284,19 @@ func decodeCerts(_ context.Context, r *http.Request) (interface{}, error) {
 	return req, nil
 }
 
+func decodeCSR(_ context.Context, r *http.Request) (interface{}, error) {
+	if r.Header.Get("Content-Type") != contentType {
+		return nil, errors.Wrap(apiutil.ErrValidation, apiutil.ErrUnsupportedContentType)
+	}
+
+	req := csrReq{token: apiutil.ExtractBearerToken(r)}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	return req, nil
+}
+
 func decodeRevokeCerts(_ context.Context, r *http.Request) (interface{}, error) {
 	req := revokeReq{
 		token:  apiutil.ExtractBearerToken(r),
@@ -137,6 +306,22 @@ func decodeRevokeCerts(_ context.Context, r *http.Request) (interface{}, error)
 	return req, nil
 }
 
+// encodeOCSPError encodes the error as the OCSP error response, since OCSP
+// clients only accept OCSP responses. As specified by RFC 6960, the error
+// responses are unsigned and sent with status OK.
+func encodeOCSPError(_ context.Context, err error, w http.ResponseWriter) {
+	res := ocspcrypto.InternalErrorErrorResponse
+	if errors.Contains(err, apiutil.ErrValidation) {
+		res = ocspcrypto.MalformedRequestErrorResponse
+	}
+
+	w.Header().Set("Content-Type", ocspResType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(res); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	var wrapper error
 	if errors.Contains(err, apiutil.ErrValidation) {
@@ -153,7 +338,11 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 		errors.Contains(err, apiutil.ErrMissingID),
 		errors.Contains(err, apiutil.ErrMissingCertData),
 		errors.Contains(err, apiutil.ErrInvalidCertData),
-		errors.Contains(err, apiutil.ErrLimitSize):
+		errors.Contains(err, apiutil.ErrInvalidQueryParams),
+		errors.Contains(err, apiutil.ErrLimitSize),
+		errors.Contains(err, certs.ErrExpiredCert),
+		errors.Contains(err, certs.ErrInvalidCSR),
+		errors.Contains(err, pki.ErrKeyReuseUnsupported):
 		w.WriteHeader(http.StatusBadRequest)
 	case errors.Contains(err, svcerr.ErrConflict):
 		w.WriteHeader(http.StatusConflict)
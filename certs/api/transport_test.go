@@ -0,0 +1,50 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/absmach/magistrala/certs/pki"
+	"github.com/absmach/magistrala/internal/apiutil"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	ocspcrypto "golang.org/x/crypto/ocsp"
+)
+
+func TestEncodeOCSPError(t *testing.T) {
+	cases := []struct {
+		desc string
+		err  error
+		res  []byte
+	}{
+		{
+			desc: "encode unsupported content type",
+			err:  errors.Wrap(apiutil.ErrValidation, apiutil.ErrUnsupportedContentType),
+			res:  ocspcrypto.MalformedRequestErrorResponse,
+		},
+		{
+			desc: "encode malformed request",
+			err:  errors.Wrap(apiutil.ErrValidation, apiutil.ErrInvalidCertData),
+			res:  ocspcrypto.MalformedRequestErrorResponse,
+		},
+		{
+			desc: "encode failed revocation status",
+			err:  errors.Wrap(pki.ErrFailedRevocationStatus, errors.New("database unavailable")),
+			res:  ocspcrypto.InternalErrorErrorResponse,
+		},
+	}
+
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		encodeOCSPError(context.Background(), tc.err, w)
+		assert.Equal(t, http.StatusOK, w.Code, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, http.StatusOK, w.Code))
+		assert.Equal(t, ocspResType, w.Header().Get("Content-Type"), fmt.Sprintf("%s: expected content type %s got %s\n", tc.desc, ocspResType, w.Header().Get("Content-Type")))
+		assert.Equal(t, tc.res, w.Body.Bytes(), fmt.Sprintf("%s: expected OCSP response %v got %v\n", tc.desc, tc.res, w.Body.Bytes()))
+	}
+}
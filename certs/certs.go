@@ -9,10 +9,13 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"os"
+	"time"
 
 	"github.com/absmach/magistrala/pkg/errors"
 )
 
+const csrPEMType = "CERTIFICATE REQUEST"
+
 // ConfigsPage contains page related metadata as well as list.
 type Page struct {
 	Total  uint64
@@ -21,7 +24,20 @@ type Page struct {
 	Certs  []Cert
 }
 
-var ErrMissingCerts = errors.New("CA path or CA key path not set")
+// PageMetadata contains page parameters and filters of the listed certificates.
+// Zero expiration times are not used for filtering.
+type PageMetadata struct {
+	Offset        uint64
+	Limit         uint64
+	ExpiresAfter  time.Time
+	ExpiresBefore time.Time
+}
+
+var (
+	ErrMissingCerts = errors.New("CA path or CA key path not set")
+
+	errDecodePEM = errors.New("failed to decode PEM data")
+)
 
 // Repository specifies a Config persistence API.
 type Repository interface {
@@ -35,10 +51,21 @@ type Repository interface {
 	Remove(ctx context.Context, ownerID, thingID string) error
 
 	// RetrieveByThing retrieves issued certificates for a given thing ID
-	RetrieveByThing(ctx context.Context, ownerID, thingID string, offset, limit uint64) (Page, error)
+	RetrieveByThing(ctx context.Context, ownerID, thingID string, pm PageMetadata) (Page, error)
 
 	// RetrieveBySerial retrieves a certificate for a given serial ID
 	RetrieveBySerial(ctx context.Context, ownerID, serialID string) (Cert, error)
+
+	// RetrieveThingID retrieves the ID of the thing the certificate with a
+	// given serial ID is issued to
+	RetrieveThingID(ctx context.Context, serialID string) (string, error)
+
+	// RetrieveExpiring retrieves certificates which expire before the given
+	// time and of which the expiry is not notified yet
+	RetrieveExpiring(ctx context.Context, before time.Time, limit uint64) ([]Cert, error)
+
+	// UpdateExpiryNotified marks the expiry of the certificate as notified
+	UpdateExpiryNotified(ctx context.Context, serialID string) error
 }
 
 func LoadCertificates(caPath, caKeyPath string) (tls.Certificate, *x509.Certificate, error) {
@@ -77,8 +104,27 @@ func LoadCertificates(caPath, caKeyPath string) (tls.Certificate, *x509.Certific
 func ReadCert(b []byte) (*x509.Certificate, error) {
 	block, _ := pem.Decode(b)
 	if block == nil {
-		return nil, errors.New("failed to decode PEM data")
+		return nil, errDecodePEM
 	}
 
 	return x509.ParseCertificate(block.Bytes)
 }
+
+// ReadCSR parses the PEM encoded PKCS#10 certificate signing request and
+// verifies its signature.
+func ReadCSR(b []byte) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != csrPEMType {
+		return nil, errDecodePEM
+	}
+
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, err
+	}
+
+	return csr, nil
+}
@@ -0,0 +1,6 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package events provides the certificates events, and the scanner which
+// publishes them for the certificates nearing expiry.
+package events
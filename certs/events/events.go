@@ -0,0 +1,30 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"github.com/absmach/magistrala/certs"
+	"github.com/absmach/magistrala/pkg/events"
+)
+
+const (
+	certPrefix   = "certs."
+	certExpiring = certPrefix + "expiring"
+)
+
+var _ events.Event = (*expiringCertEvent)(nil)
+
+type expiringCertEvent struct {
+	certs.Cert
+}
+
+func (ece expiringCertEvent) Encode() (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"operation": certExpiring,
+		"serial":    ece.Serial,
+		"thing_id":  ece.ThingID,
+		"owner_id":  ece.OwnerID,
+		"expire":    ece.Expire,
+	}, nil
+}
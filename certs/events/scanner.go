@@ -0,0 +1,95 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/absmach/magistrala/certs"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/pkg/events"
+)
+
+const batchSize = 100
+
+var (
+	// ErrPublishExpiring indicates failure to publish the certificate expiry event.
+	ErrPublishExpiring = errors.New("failed to publish certificate expiry event")
+
+	// ErrScanExpiring indicates failure to retrieve the certificates nearing expiry.
+	ErrScanExpiring = errors.New("failed to scan certificates nearing expiry")
+)
+
+// ExpiryScanner publishes the events for the certificates nearing expiry.
+type ExpiryScanner interface {
+	// Scan publishes the event for each certificate which expires within the
+	// window, unless the event is already published for the certificate.
+	Scan(ctx context.Context) error
+
+	// Run scans the certificates on each interval until the context is done.
+	Run(ctx context.Context, interval time.Duration) error
+}
+
+var _ ExpiryScanner = (*expiryScanner)(nil)
+
+type expiryScanner struct {
+	repo      certs.Repository
+	publisher events.Publisher
+	window    time.Duration
+	logger    *slog.Logger
+}
+
+// NewExpiryScanner returns the scanner which publishes the certs.expiring
+// events for the certificates which expire within the window.
+func NewExpiryScanner(repo certs.Repository, publisher events.Publisher, window time.Duration, logger *slog.Logger) ExpiryScanner {
+	return &expiryScanner{
+		repo:      repo,
+		publisher: publisher,
+		window:    window,
+		logger:    logger,
+	}
+}
+
+func (es *expiryScanner) Scan(ctx context.Context) error {
+	before := time.Now().Add(es.window)
+	for {
+		cs, err := es.repo.RetrieveExpiring(ctx, before, batchSize)
+		if err != nil {
+			return errors.Wrap(ErrScanExpiring, err)
+		}
+
+		for _, c := range cs {
+			if err := es.publisher.Publish(ctx, expiringCertEvent{c}); err != nil {
+				return errors.Wrap(ErrPublishExpiring, err)
+			}
+			if err := es.repo.UpdateExpiryNotified(ctx, c.Serial); err != nil {
+				return errors.Wrap(ErrScanExpiring, err)
+			}
+		}
+
+		if len(cs) < batchSize {
+			return nil
+		}
+	}
+}
+
+func (es *expiryScanner) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := es.Scan(ctx); err != nil {
+			es.logger.Warn(fmt.Sprintf("Failed to notify certificates expiry: %s", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package events_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/certs"
+	"github.com/absmach/magistrala/certs/events"
+	"github.com/absmach/magistrala/certs/mocks"
+	mglog "github.com/absmach/magistrala/logger"
+	"github.com/absmach/magistrala/pkg/errors"
+	mgevents "github.com/absmach/magistrala/pkg/events"
+	evmocks "github.com/absmach/magistrala/pkg/events/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	ownerID = "owner"
+	thingID = "thing"
+	window  = 24 * time.Hour
+)
+
+func TestScan(t *testing.T) {
+	repo := mocks.NewCertsRepository()
+	now := time.Now()
+	expires := []time.Duration{-time.Hour, time.Hour, 2 * time.Hour, 2 * window}
+	for i, exp := range expires {
+		c := certs.Cert{
+			OwnerID: ownerID,
+			ThingID: thingID,
+			Serial:  fmt.Sprintf("serial-%d", i),
+			Expire:  now.Add(exp),
+		}
+		_, err := repo.Save(context.Background(), c)
+		require.Nil(t, err, fmt.Sprintf("unexpected cert saving error: %s\n", err))
+	}
+
+	cases := []struct {
+		desc    string
+		pubErr  error
+		serials []string
+		pending int
+		err     error
+	}{
+		{
+			desc:    "scan with failing publisher",
+			pubErr:  errors.ErrMalformedEntity,
+			serials: []string{"serial-1"},
+			pending: 2,
+			err:     events.ErrPublishExpiring,
+		},
+		{
+			desc:    "scan certs nearing expiry",
+			serials: []string{"serial-1", "serial-2"},
+			pending: 0,
+			err:     nil,
+		},
+		{
+			desc:    "scan already notified certs",
+			serials: []string{},
+			pending: 0,
+			err:     nil,
+		},
+	}
+
+	for _, tc := range cases {
+		publisher := new(evmocks.Publisher)
+		published := []string{}
+		publisher.On("Publish", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			e, err := args.Get(1).(mgevents.Event).Encode()
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected event encoding error: %s\n", tc.desc, err))
+			assert.Equal(t, "certs.expiring", e["operation"], fmt.Sprintf("%s: unexpected event operation\n", tc.desc))
+			published = append(published, e["serial"].(string))
+		}).Return(tc.pubErr)
+
+		scanner := events.NewExpiryScanner(repo, publisher, window, mglog.NewMock())
+		err := scanner.Scan(context.Background())
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.serials, published, fmt.Sprintf("%s: expected published %v got %v\n", tc.desc, tc.serials, published))
+
+		cs, err := repo.RetrieveExpiring(context.Background(), now.Add(window), 10)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected expiring certs retrieval error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.pending, len(cs), fmt.Sprintf("%s: expected %d pending certs got %d\n", tc.desc, tc.pending, len(cs)))
+	}
+}
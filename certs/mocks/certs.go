@@ -5,7 +5,9 @@ package mocks
 
 import (
 	"context"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/absmach/magistrala/certs"
 	"github.com/absmach/magistrala/pkg/errors"
@@ -18,6 +20,7 @@ type certsRepoMock struct {
 	counter        uint64
 	certsBySerial  map[string]certs.Cert
 	certsByThingID map[string]map[string][]certs.Cert
+	notified       map[string]bool
 }
 
 // NewCertsRepository creates in-memory certs repository.
@@ -25,6 +28,7 @@ func NewCertsRepository() certs.Repository {
 	return &certsRepoMock{
 		certsBySerial:  make(map[string]certs.Cert),
 		certsByThingID: make(map[string]map[string][]certs.Cert),
+		notified:       make(map[string]bool),
 	}
 }
 
@@ -37,6 +41,7 @@ func (c *certsRepoMock) Save(ctx context.Context, cert certs.Cert) (string, erro
 		ThingID: cert.ThingID,
 		Serial:  cert.Serial,
 		Expire:  cert.Expire,
+		TTL:     cert.TTL,
 	}
 
 	_, ok := c.certsByThingID[cert.OwnerID][cert.ThingID]
@@ -92,14 +97,15 @@ func (c *certsRepoMock) Remove(ctx context.Context, ownerID, serial string) erro
 		return errors.ErrNotFound
 	}
 	delete(c.certsBySerial, crt.Serial)
+	delete(c.notified, crt.Serial)
 	delete(c.certsByThingID, crt.ThingID)
 	return nil
 }
 
-func (c *certsRepoMock) RetrieveByThing(ctx context.Context, ownerID, thingID string, offset, limit uint64) (certs.Page, error) {
+func (c *certsRepoMock) RetrieveByThing(ctx context.Context, ownerID, thingID string, pm certs.PageMetadata) (certs.Page, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	if limit <= 0 {
+	if pm.Limit <= 0 {
 		return certs.Page{}, nil
 	}
 
@@ -108,18 +114,29 @@ func (c *certsRepoMock) RetrieveByThing(ctx context.Context, ownerID, thingID st
 		return certs.Page{}, errors.ErrNotFound
 	}
 
+	var filtered []certs.Cert
+	for _, v := range cs {
+		if !pm.ExpiresAfter.IsZero() && !v.Expire.After(pm.ExpiresAfter) {
+			continue
+		}
+		if !pm.ExpiresBefore.IsZero() && !v.Expire.Before(pm.ExpiresBefore) {
+			continue
+		}
+		filtered = append(filtered, v)
+	}
+
 	var crts []certs.Cert
-	for i, v := range cs {
-		if uint64(i) >= offset && uint64(i) < offset+limit {
+	for i, v := range filtered {
+		if uint64(i) >= pm.Offset && uint64(i) < pm.Offset+pm.Limit {
 			crts = append(crts, v)
 		}
 	}
 
 	page := certs.Page{
 		Certs:  crts,
-		Total:  c.counter,
-		Offset: offset,
-		Limit:  limit,
+		Total:  uint64(len(filtered)),
+		Offset: pm.Offset,
+		Limit:  pm.Limit,
 	}
 	return page, nil
 }
@@ -135,3 +152,49 @@ func (c *certsRepoMock) RetrieveBySerial(ctx context.Context, ownerID, serialID
 
 	return crt, nil
 }
+
+func (c *certsRepoMock) RetrieveThingID(ctx context.Context, serialID string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	crt, ok := c.certsBySerial[serialID]
+	if !ok {
+		return "", errors.ErrNotFound
+	}
+
+	return crt.ThingID, nil
+}
+
+func (c *certsRepoMock) RetrieveExpiring(ctx context.Context, before time.Time, limit uint64) ([]certs.Cert, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	crts := []certs.Cert{}
+	for serial, crt := range c.certsBySerial {
+		if c.notified[serial] || !crt.Expire.After(now) || crt.Expire.After(before) {
+			continue
+		}
+		crts = append(crts, crt)
+	}
+	sort.Slice(crts, func(i, j int) bool {
+		return crts[i].Expire.Before(crts[j].Expire)
+	})
+	if uint64(len(crts)) > limit {
+		crts = crts[:limit]
+	}
+
+	return crts, nil
+}
+
+func (c *certsRepoMock) UpdateExpiryNotified(ctx context.Context, serialID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.certsBySerial[serialID]; !ok {
+		return errors.ErrNotFound
+	}
+	c.notified[serialID] = true
+
+	return nil
+}
@@ -6,6 +6,7 @@ package mocks
 import (
 	"bufio"
 	"bytes"
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/rand"
 	"crypto/rsa"
@@ -19,12 +20,20 @@ import (
 
 	"github.com/absmach/magistrala/certs/pki"
 	"github.com/absmach/magistrala/pkg/errors"
+	"golang.org/x/crypto/ocsp"
 )
 
-const keyBits = 2048
+const (
+	keyBits = 2048
+
+	// ClockSkew is subtracted from the certificate validity start, as the
+	// PKI agents do.
+	ClockSkew = time.Minute
+)
 
 var (
 	errPrivateKeyEmpty           = errors.New("private key is empty")
+	errPublicKeyEmpty            = errors.New("public key is empty")
 	errPrivateKeyUnsupportedType = errors.New("private key type is unsupported")
 )
 
@@ -38,6 +47,7 @@ type agent struct {
 	mu          sync.Mutex
 	counter     uint64
 	certs       map[string]pki.Cert
+	revoked     map[string]time.Time
 }
 
 func NewPkiAgent(tlsCert tls.Certificate, caCert *x509.Certificate, ttl string, timeout time.Duration) pki.Agent {
@@ -47,6 +57,7 @@ func NewPkiAgent(tlsCert tls.Certificate, caCert *x509.Certificate, ttl string,
 		X509Cert:    caCert,
 		TTL:         ttl,
 		certs:       make(map[string]pki.Cert),
+		revoked:     make(map[string]time.Time),
 	}
 }
 
@@ -54,26 +65,76 @@ func (a *agent) IssueCert(cn, ttl string) (pki.Cert, error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	if a.X509Cert == nil {
-		return pki.Cert{}, errors.Wrap(pki.ErrFailedCertCreation, pki.ErrMissingCACertificate)
-	}
-
 	var priv interface{}
 	priv, err := rsa.GenerateKey(rand.Reader, keyBits)
 	if err != nil {
 		return pki.Cert{}, errors.Wrap(pki.ErrFailedCertCreation, err)
 	}
 
+	pubKey, err := publicKey(priv)
+	if err != nil {
+		return pki.Cert{}, errors.Wrap(pki.ErrFailedCertCreation, err)
+	}
+
+	cert, err := a.issue(cn, ttl, pubKey)
+	if err != nil {
+		return pki.Cert{}, err
+	}
+
+	var keyOut bytes.Buffer
+	buffKeyOut := bufio.NewWriter(&keyOut)
+
+	block, err := pemBlockForKey(priv)
+	if err != nil {
+		return pki.Cert{}, errors.Wrap(pki.ErrFailedCertCreation, err)
+	}
+	if err := pem.Encode(buffKeyOut, block); err != nil {
+		return pki.Cert{}, errors.Wrap(pki.ErrFailedCertCreation, err)
+	}
+	buffKeyOut.Flush()
+	cert.ClientKey = keyOut.String()
+
+	return cert, nil
+}
+
+func (a *agent) IssueCertWithKey(cn, ttl string, key crypto.PublicKey) (pki.Cert, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if key == nil {
+		return pki.Cert{}, errors.Wrap(pki.ErrFailedCertCreation, errPublicKeyEmpty)
+	}
+
+	return a.issue(cn, ttl, key)
+}
+
+func (a *agent) SignCSR(csr *x509.CertificateRequest, ttl string) (pki.Cert, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if csr == nil || csr.PublicKey == nil {
+		return pki.Cert{}, errors.Wrap(pki.ErrFailedCertCreation, errPublicKeyEmpty)
+	}
+
+	return a.issue(csr.Subject.CommonName, ttl, csr.PublicKey)
+}
+
+func (a *agent) issue(cn, ttl string, pubKey crypto.PublicKey) (pki.Cert, error) {
+	if a.X509Cert == nil {
+		return pki.Cert{}, errors.Wrap(pki.ErrFailedCertCreation, pki.ErrMissingCACertificate)
+	}
+
 	if ttl == "" {
 		ttl = a.TTL
 	}
 
-	notBefore := time.Now()
+	now := time.Now()
 	validFor, err := time.ParseDuration(ttl)
 	if err != nil {
 		return pki.Cert{}, errors.Wrap(pki.ErrFailedCertCreation, err)
 	}
-	notAfter := notBefore.Add(validFor)
+	notBefore := now.Add(-ClockSkew)
+	notAfter := now.Add(validFor)
 
 	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
 	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
@@ -96,10 +157,6 @@ func (a *agent) IssueCert(cn, ttl string) (pki.Cert, error) {
 		SubjectKeyId: []byte{1, 2, 3, 4, 6},
 	}
 
-	pubKey, err := publicKey(priv)
-	if err != nil {
-		return pki.Cert{}, errors.Wrap(pki.ErrFailedCertCreation, err)
-	}
 	derBytes, err := x509.CreateCertificate(rand.Reader, &tmpl, a.X509Cert, pubKey, a.TLSCert.PrivateKey)
 	if err != nil {
 		return pki.Cert{}, errors.Wrap(pki.ErrFailedCertCreation, err)
@@ -110,9 +167,8 @@ func (a *agent) IssueCert(cn, ttl string) (pki.Cert, error) {
 		return pki.Cert{}, errors.Wrap(pki.ErrFailedCertCreation, err)
 	}
 
-	var bw, keyOut bytes.Buffer
+	var bw bytes.Buffer
 	buffWriter := bufio.NewWriter(&bw)
-	buffKeyOut := bufio.NewWriter(&keyOut)
 
 	if err := pem.Encode(buffWriter, &pem.Block{Type: "CERTIFICATE", Bytes: derBytes}); err != nil {
 		return pki.Cert{}, errors.Wrap(pki.ErrFailedCertCreation, err)
@@ -120,25 +176,14 @@ func (a *agent) IssueCert(cn, ttl string) (pki.Cert, error) {
 	buffWriter.Flush()
 	cert := bw.String()
 
-	block, err := pemBlockForKey(priv)
-	if err != nil {
-		return pki.Cert{}, errors.Wrap(pki.ErrFailedCertCreation, err)
-	}
-	if err := pem.Encode(buffKeyOut, block); err != nil {
-		return pki.Cert{}, errors.Wrap(pki.ErrFailedCertCreation, err)
-	}
-	buffKeyOut.Flush()
-	key := keyOut.String()
-
-	a.certs[x509cert.SerialNumber.String()] = pki.Cert{
+	a.certs[pki.FormatSerial(x509cert.SerialNumber)] = pki.Cert{
 		ClientCert: cert,
 	}
 	a.counter++
 
 	return pki.Cert{
 		ClientCert: cert,
-		ClientKey:  key,
-		Serial:     x509cert.SerialNumber.String(),
+		Serial:     pki.FormatSerial(x509cert.SerialNumber),
 		Expire:     x509cert.NotAfter.Unix(),
 		IssuingCA:  x509cert.Issuer.String(),
 	}, nil
@@ -157,7 +202,78 @@ func (a *agent) Read(serial string) (pki.Cert, error) {
 }
 
 func (a *agent) Revoke(serial string) (time.Time, error) {
-	return time.Now(), nil
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	revoked := time.Now().UTC().Truncate(time.Second)
+	if _, ok := a.certs[serial]; ok {
+		a.revoked[serial] = revoked
+	}
+
+	return revoked, nil
+}
+
+func (a *agent) CRL() ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var entries []x509.RevocationListEntry
+	for serial, revoked := range a.revoked {
+		sn, err := pki.ParseSerial(serial)
+		if err != nil {
+			return nil, errors.Wrap(pki.ErrFailedRevocationStatus, err)
+		}
+		entries = append(entries, x509.RevocationListEntry{SerialNumber: sn, RevocationTime: revoked})
+	}
+
+	now := time.Now()
+	tmpl := x509.RevocationList{
+		RevokedCertificateEntries: entries,
+		Number:                    big.NewInt(now.UnixNano()),
+		ThisUpdate:                now,
+		NextUpdate:                now.Add(time.Hour),
+	}
+	signer, ok := a.TLSCert.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.Wrap(pki.ErrFailedRevocationStatus, errPrivateKeyUnsupportedType)
+	}
+
+	issuer := *a.X509Cert
+	issuer.KeyUsage |= x509.KeyUsageCRLSign
+
+	return x509.CreateRevocationList(rand.Reader, &tmpl, &issuer, signer)
+}
+
+func (a *agent) OCSP(req []byte) ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	r, err := ocsp.ParseRequest(req)
+	if err != nil {
+		return ocsp.MalformedRequestErrorResponse, nil
+	}
+
+	now := time.Now()
+	tmpl := ocsp.Response{
+		SerialNumber: r.SerialNumber,
+		Status:       ocsp.Good,
+		ThisUpdate:   now,
+		NextUpdate:   now.Add(time.Hour),
+	}
+	serial := pki.FormatSerial(r.SerialNumber)
+	if _, ok := a.certs[serial]; !ok {
+		tmpl.Status = ocsp.Unknown
+	}
+	if revoked, ok := a.revoked[serial]; ok {
+		tmpl.Status = ocsp.Revoked
+		tmpl.RevokedAt = revoked
+	}
+	signer, ok := a.TLSCert.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.Wrap(pki.ErrFailedRevocationStatus, errPrivateKeyUnsupportedType)
+	}
+
+	return ocsp.CreateResponse(a.X509Cert, a.X509Cert, tmpl, signer)
 }
 
 func publicKey(priv interface{}) (interface{}, error) {
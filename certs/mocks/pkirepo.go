@@ -0,0 +1,79 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/absmach/magistrala/certs/pki"
+	"github.com/absmach/magistrala/pkg/errors"
+)
+
+var _ pki.Repository = (*pkiRepoMock)(nil)
+
+type pkiRepoMock struct {
+	mu    sync.Mutex
+	certs map[string]pki.IssuedCert
+}
+
+// NewPKIRepository creates in-memory local PKI agent repository.
+func NewPKIRepository() pki.Repository {
+	return &pkiRepoMock{
+		certs: make(map[string]pki.IssuedCert),
+	}
+}
+
+func (r *pkiRepoMock) Save(_ context.Context, cert pki.IssuedCert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.certs[cert.Serial]; ok {
+		return errors.ErrConflict
+	}
+	r.certs[cert.Serial] = cert
+
+	return nil
+}
+
+func (r *pkiRepoMock) Retrieve(_ context.Context, serial string) (pki.IssuedCert, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.certs[serial]
+	if !ok {
+		return pki.IssuedCert{}, pki.ErrNotFoundCert
+	}
+
+	return c, nil
+}
+
+func (r *pkiRepoMock) Revoke(_ context.Context, serial string, revoked time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.certs[serial]
+	if !ok {
+		return pki.ErrNotFoundCert
+	}
+	c.Revoked = revoked
+	r.certs[serial] = c
+
+	return nil
+}
+
+func (r *pkiRepoMock) RetrieveRevoked(_ context.Context) ([]pki.IssuedCert, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var certs []pki.IssuedCert
+	for _, c := range r.certs {
+		if !c.Revoked.IsZero() && c.Expire.After(time.Now()) {
+			certs = append(certs, c)
+		}
+	}
+
+	return certs, nil
+}
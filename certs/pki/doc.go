@@ -4,5 +4,6 @@
 // Package pki contains the domain concept definitions needed to
 // support Magistrala Certs service functionality.
 // It provides the abstraction of the PKI (Public Key Infrastructure)
-// Valut service, which is used to issue and revoke certificates.
+// Valut service, which is used to issue and revoke certificates, and the
+// local PKI agent which signs the certificates with the configured CA.
 package pki
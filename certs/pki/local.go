@@ -0,0 +1,377 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package pki
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/absmach/magistrala/pkg/errors"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	keyBits    = 2048
+	keyType    = "rsa"
+	serialBits = 128
+
+	// clockSkew is subtracted from the certificate validity start, so the
+	// certificate is valid on the devices whose clocks are behind.
+	clockSkew = time.Minute
+
+	// statusValidity is the validity of the CRL and OCSP responses, after
+	// which the clients fetch the revocation status again.
+	statusValidity = time.Hour
+)
+
+var (
+	// ErrNotFoundCert indicates a non-existent certificate.
+	ErrNotFoundCert = errors.New("certificate not found")
+
+	errInvalidTTL    = errors.New("invalid certificate TTL")
+	errMissingKey    = errors.New("missing certificate public key")
+	errInvalidCAKey  = errors.New("CA private key can not sign certificates")
+	errExpiredCACert = errors.New("CA certificate is expired")
+)
+
+// IssuedCert is the certificate issued by the local PKI agent.
+type IssuedCert struct {
+	Serial      string
+	Certificate string
+	Expire      time.Time
+	Revoked     time.Time
+}
+
+// Repository persists the certificates issued by the local PKI agent.
+type Repository interface {
+	// Save stores the issued certificate.
+	Save(ctx context.Context, cert IssuedCert) error
+
+	// Retrieve returns the issued certificate for the given serial.
+	Retrieve(ctx context.Context, serial string) (IssuedCert, error)
+
+	// Revoke marks the issued certificate as revoked at the given time.
+	Revoke(ctx context.Context, serial string, revoked time.Time) error
+
+	// RetrieveRevoked returns the revoked certificates which are not expired.
+	RetrieveRevoked(ctx context.Context) ([]IssuedCert, error)
+}
+
+var _ Agent = (*localAgent)(nil)
+
+type localAgent struct {
+	mu      sync.Mutex
+	signer  crypto.Signer
+	caCert  *x509.Certificate
+	caPEM   string
+	caKey   []byte
+	repo    Repository
+	urls    URLs
+	timeout time.Duration
+}
+
+// NewLocalAgent returns the PKI agent which signs the certificates with the
+// given CA, without a 3rd party PKI. Issued certificates and their
+// revocation state are persisted to the repository, and the revocation
+// status URLs, unless empty, are embedded into the issued certificates.
+func NewLocalAgent(tlsCert tls.Certificate, caCert *x509.Certificate, repo Repository, urls URLs, timeout time.Duration) (Agent, error) {
+	if caCert == nil || len(tlsCert.Certificate) == 0 {
+		return nil, ErrMissingCACertificate
+	}
+	signer, ok := tlsCert.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, errInvalidCAKey
+	}
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw})
+
+	// OCSP requests identify the CA by the hash of its public key.
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(caCert.RawSubjectPublicKeyInfo, &spki); err != nil {
+		return nil, errors.Wrap(ErrMissingCACertificate, err)
+	}
+
+	return &localAgent{
+		signer:  signer,
+		caCert:  caCert,
+		caPEM:   string(caPEM),
+		caKey:   spki.PublicKey.RightAlign(),
+		repo:    repo,
+		urls:    urls,
+		timeout: timeout,
+	}, nil
+}
+
+func (a *localAgent) IssueCert(cn, ttl string) (Cert, error) {
+	key, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return Cert{}, errors.Wrap(ErrFailedCertCreation, err)
+	}
+
+	cert, err := a.issue(cn, ttl, &key.PublicKey)
+	if err != nil {
+		return Cert{}, err
+	}
+	cert.ClientKey = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	cert.PrivateKeyType = keyType
+
+	return cert, nil
+}
+
+func (a *localAgent) IssueCertWithKey(cn, ttl string, key crypto.PublicKey) (Cert, error) {
+	if key == nil {
+		return Cert{}, errors.Wrap(ErrFailedCertCreation, errMissingKey)
+	}
+
+	return a.issue(cn, ttl, key)
+}
+
+// SignCSR issues the certificate for the CSR public key. Only the CSR common
+// name is used, the rest of the requested subject and extensions are ignored.
+func (a *localAgent) SignCSR(csr *x509.CertificateRequest, ttl string) (Cert, error) {
+	if csr == nil || csr.PublicKey == nil {
+		return Cert{}, errors.Wrap(ErrFailedCertCreation, errMissingKey)
+	}
+
+	return a.issue(csr.Subject.CommonName, ttl, csr.PublicKey)
+}
+
+func (a *localAgent) issue(cn, ttl string, key crypto.PublicKey) (Cert, error) {
+	validFor, err := time.ParseDuration(ttl)
+	if err != nil || validFor <= 0 {
+		return Cert{}, errors.Wrap(ErrFailedCertCreation, errInvalidTTL)
+	}
+
+	now := time.Now()
+	if !now.Before(a.caCert.NotAfter) {
+		return Cert{}, errors.Wrap(ErrFailedCertCreation, errExpiredCACert)
+	}
+	// Certificates can not outlive the CA, so the validity is truncated.
+	notAfter := now.Add(validFor)
+	if notAfter.After(a.caCert.NotAfter) {
+		notAfter = a.caCert.NotAfter
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialBits))
+	if err != nil {
+		return Cert{}, errors.Wrap(ErrFailedCertCreation, err)
+	}
+
+	tmpl := x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   cn,
+			Organization: a.caCert.Subject.Organization,
+		},
+		NotBefore:   now.Add(-clockSkew),
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if a.urls.CRL != "" {
+		tmpl.CRLDistributionPoints = []string{a.urls.CRL}
+	}
+	if a.urls.OCSP != "" {
+		tmpl.OCSPServer = []string{a.urls.OCSP}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, a.caCert, key, a.signer)
+	if err != nil {
+		return Cert{}, errors.Wrap(ErrFailedCertCreation, err)
+	}
+
+	cert := Cert{
+		ClientCert: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		IssuingCA:  a.caPEM,
+		CAChain:    []string{a.caPEM},
+		Serial:     FormatSerial(serial),
+		Expire:     notAfter.Unix(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+
+	ic := IssuedCert{
+		Serial:      cert.Serial,
+		Certificate: cert.ClientCert,
+		Expire:      notAfter,
+	}
+	if err := a.repo.Save(ctx, ic); err != nil {
+		return Cert{}, errors.Wrap(ErrFailedCertCreation, err)
+	}
+
+	return cert, nil
+}
+
+func (a *localAgent) Read(serial string) (Cert, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+
+	ic, err := a.repo.Retrieve(ctx, serial)
+	if err != nil {
+		return Cert{}, err
+	}
+
+	return Cert{
+		ClientCert: ic.Certificate,
+		IssuingCA:  a.caPEM,
+		CAChain:    []string{a.caPEM},
+		Serial:     ic.Serial,
+		Expire:     ic.Expire.Unix(),
+	}, nil
+}
+
+func (a *localAgent) Revoke(serial string) (time.Time, error) {
+	// Serialize revocations, so the certificate is revoked only once.
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+
+	ic, err := a.repo.Retrieve(ctx, serial)
+	if err != nil {
+		return time.Time{}, errors.Wrap(ErrFailedCertRevocation, err)
+	}
+	if !ic.Revoked.IsZero() {
+		return ic.Revoked, nil
+	}
+
+	revoked := time.Now().UTC().Truncate(time.Second)
+	if err := a.repo.Revoke(ctx, serial, revoked); err != nil {
+		return time.Time{}, errors.Wrap(ErrFailedCertRevocation, err)
+	}
+
+	return revoked, nil
+}
+
+func (a *localAgent) CRL() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+
+	revoked, err := a.repo.RetrieveRevoked(ctx)
+	if err != nil {
+		return nil, errors.Wrap(ErrFailedRevocationStatus, err)
+	}
+
+	entries := make([]x509.RevocationListEntry, 0, len(revoked))
+	for _, ic := range revoked {
+		serial, err := ParseSerial(ic.Serial)
+		if err != nil {
+			return nil, errors.Wrap(ErrFailedRevocationStatus, err)
+		}
+		entries = append(entries, x509.RevocationListEntry{
+			SerialNumber:   serial,
+			RevocationTime: ic.Revoked,
+		})
+	}
+
+	// The CRL number must increase with each CRL, so the current time is used.
+	now := time.Now()
+	tmpl := x509.RevocationList{
+		RevokedCertificateEntries: entries,
+		Number:                    big.NewInt(now.UnixNano()),
+		ThisUpdate:                now,
+		NextUpdate:                now.Add(statusValidity),
+	}
+	// The CA certificate without the key usage extension is not restricted
+	// (RFC 5280), but the CRL creation requires the CRL sign usage.
+	issuer := a.caCert
+	if issuer.KeyUsage == 0 {
+		ca := *issuer
+		ca.KeyUsage = x509.KeyUsageCRLSign
+		issuer = &ca
+	}
+	der, err := x509.CreateRevocationList(rand.Reader, &tmpl, issuer, a.signer)
+	if err != nil {
+		return nil, errors.Wrap(ErrFailedRevocationStatus, err)
+	}
+
+	return der, nil
+}
+
+func (a *localAgent) OCSP(req []byte) ([]byte, error) {
+	r, err := ocsp.ParseRequest(req)
+	if err != nil {
+		return ocsp.MalformedRequestErrorResponse, nil
+	}
+
+	// Only the status of the certificates issued by this CA is known.
+	if !r.HashAlgorithm.Available() {
+		return ocsp.MalformedRequestErrorResponse, nil
+	}
+	h := r.HashAlgorithm.New()
+	h.Write(a.caKey)
+	if !bytes.Equal(h.Sum(nil), r.IssuerKeyHash) {
+		return ocsp.UnauthorizedErrorResponse, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+
+	now := time.Now()
+	tmpl := ocsp.Response{
+		SerialNumber: r.SerialNumber,
+		Status:       ocsp.Good,
+		ThisUpdate:   now,
+		NextUpdate:   now.Add(statusValidity),
+	}
+	ic, err := a.repo.Retrieve(ctx, FormatSerial(r.SerialNumber))
+	switch {
+	case errors.Contains(err, ErrNotFoundCert):
+		tmpl.Status = ocsp.Unknown
+	case err != nil:
+		return nil, errors.Wrap(ErrFailedRevocationStatus, err)
+	case !ic.Revoked.IsZero():
+		tmpl.Status = ocsp.Revoked
+		tmpl.RevokedAt = ic.Revoked
+		tmpl.RevocationReason = ocsp.Unspecified
+	}
+
+	res, err := ocsp.CreateResponse(a.caCert, a.caCert, tmpl, a.signer)
+	if err != nil {
+		return nil, errors.Wrap(ErrFailedRevocationStatus, err)
+	}
+
+	return res, nil
+}
+
+// FormatSerial formats the certificate serial number as the colon separated
+// hex bytes, in the same way as Vault does.
+func FormatSerial(serial *big.Int) string {
+	b := serial.Bytes()
+	if len(b) == 0 {
+		b = []byte{0}
+	}
+	parts := make([]string, len(b))
+	for i, v := range b {
+		parts[i] = fmt.Sprintf("%02x", v)
+	}
+
+	return strings.Join(parts, ":")
+}
+
+// ParseSerial parses the colon separated hex bytes certificate serial number.
+func ParseSerial(serial string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(strings.ReplaceAll(serial, ":", ""), 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid serial %s", serial)
+	}
+
+	return n, nil
+}
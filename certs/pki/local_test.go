@@ -0,0 +1,374 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package pki_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/certs"
+	"github.com/absmach/magistrala/certs/mocks"
+	"github.com/absmach/magistrala/certs/pki"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	caPath    = "../../docker/ssl/certs/ca.crt"
+	caKeyPath = "../../docker/ssl/certs/ca.key"
+	timeout   = time.Second
+	cn        = "thing-secret"
+	crlURL    = "http://localhost:9019/crl"
+	ocspURL   = "http://localhost:9019/ocsp"
+)
+
+func newLocalAgent(t *testing.T) (pki.Agent, *x509.Certificate) {
+	tlsCert, caCert, err := certs.LoadCertificates(caPath, caKeyPath)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert loading error: %s\n", err))
+
+	agent, err := pki.NewLocalAgent(tlsCert, caCert, mocks.NewPKIRepository(), pki.URLs{CRL: crlURL, OCSP: ocspURL}, timeout)
+	require.Nil(t, err, fmt.Sprintf("unexpected local agent error: %s\n", err))
+
+	return agent, caCert
+}
+
+func TestLocalIssueCert(t *testing.T) {
+	agent, caCert := newLocalAgent(t)
+
+	cases := []struct {
+		desc string
+		ttl  string
+		err  error
+	}{
+		{
+			desc: "issue cert",
+			ttl:  "1h",
+			err:  nil,
+		},
+		{
+			desc: "issue cert outliving the CA",
+			ttl:  fmt.Sprintf("%dh", int(time.Until(caCert.NotAfter).Hours())+24),
+			err:  nil,
+		},
+		{
+			desc: "issue cert with invalid ttl",
+			ttl:  "invalid",
+			err:  pki.ErrFailedCertCreation,
+		},
+		{
+			desc: "issue cert with negative ttl",
+			ttl:  "-1h",
+			err:  pki.ErrFailedCertCreation,
+		},
+	}
+
+	for _, tc := range cases {
+		cert, err := agent.IssueCert(cn, tc.ttl)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		crt, err := certs.ReadCert([]byte(cert.ClientCert))
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected cert parsing error: %s\n", tc.desc, err))
+		assert.Equal(t, cn, crt.Subject.CommonName, fmt.Sprintf("%s: expected common name %s got %s\n", tc.desc, cn, crt.Subject.CommonName))
+		assert.Equal(t, pki.FormatSerial(crt.SerialNumber), cert.Serial, fmt.Sprintf("%s: expected serial %s got %s\n", tc.desc, pki.FormatSerial(crt.SerialNumber), cert.Serial))
+		assert.False(t, crt.NotAfter.After(caCert.NotAfter), fmt.Sprintf("%s: cert expires after the CA\n", tc.desc))
+		assert.NotEmpty(t, cert.ClientKey, fmt.Sprintf("%s: expected private key\n", tc.desc))
+		assert.Equal(t, []string{crlURL}, crt.CRLDistributionPoints, fmt.Sprintf("%s: expected CRL URL %s got %v\n", tc.desc, crlURL, crt.CRLDistributionPoints))
+		assert.Equal(t, []string{ocspURL}, crt.OCSPServer, fmt.Sprintf("%s: expected OCSP URL %s got %v\n", tc.desc, ocspURL, crt.OCSPServer))
+
+		roots := x509.NewCertPool()
+		roots.AddCert(caCert)
+		_, err = crt.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected cert verification error: %s\n", tc.desc, err))
+	}
+}
+
+func TestLocalIssueCertWithKey(t *testing.T) {
+	agent, _ := newLocalAgent(t)
+
+	issued, err := agent.IssueCert(cn, "1h")
+	require.Nil(t, err, fmt.Sprintf("unexpected cert issuing error: %s\n", err))
+	crt, err := certs.ReadCert([]byte(issued.ClientCert))
+	require.Nil(t, err, fmt.Sprintf("unexpected cert parsing error: %s\n", err))
+
+	cases := []struct {
+		desc string
+		key  crypto.PublicKey
+		ttl  string
+		err  error
+	}{
+		{
+			desc: "issue cert with existing key",
+			key:  crt.PublicKey,
+			ttl:  "1h",
+			err:  nil,
+		},
+		{
+			desc: "issue cert without key",
+			key:  nil,
+			ttl:  "1h",
+			err:  pki.ErrFailedCertCreation,
+		},
+		{
+			desc: "issue cert with invalid ttl",
+			key:  crt.PublicKey,
+			ttl:  "invalid",
+			err:  pki.ErrFailedCertCreation,
+		},
+	}
+
+	for _, tc := range cases {
+		cert, err := agent.IssueCertWithKey(cn, tc.ttl, tc.key)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		renewed, err := certs.ReadCert([]byte(cert.ClientCert))
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected cert parsing error: %s\n", tc.desc, err))
+		assert.NotEqual(t, issued.Serial, cert.Serial, fmt.Sprintf("%s: expected new serial\n", tc.desc))
+		assert.Empty(t, cert.ClientKey, fmt.Sprintf("%s: unexpected private key\n", tc.desc))
+		assert.True(t, crt.PublicKey.(interface{ Equal(crypto.PublicKey) bool }).Equal(renewed.PublicKey), fmt.Sprintf("%s: expected the existing key\n", tc.desc))
+	}
+}
+
+func TestLocalSignCSR(t *testing.T) {
+	agent, _ := newLocalAgent(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err, fmt.Sprintf("unexpected key generation error: %s\n", err))
+	tmpl := &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: cn, Organization: []string{"requested"}},
+		DNSNames: []string{"example.com"},
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, tmpl, key)
+	require.Nil(t, err, fmt.Sprintf("unexpected CSR creation error: %s\n", err))
+	csr, err := x509.ParseCertificateRequest(der)
+	require.Nil(t, err, fmt.Sprintf("unexpected CSR parsing error: %s\n", err))
+
+	cases := []struct {
+		desc string
+		csr  *x509.CertificateRequest
+		ttl  string
+		err  error
+	}{
+		{
+			desc: "sign CSR",
+			csr:  csr,
+			ttl:  "1h",
+			err:  nil,
+		},
+		{
+			desc: "sign CSR without key",
+			csr:  &x509.CertificateRequest{Subject: pkix.Name{CommonName: cn}},
+			ttl:  "1h",
+			err:  pki.ErrFailedCertCreation,
+		},
+		{
+			desc: "sign CSR with invalid ttl",
+			csr:  csr,
+			ttl:  "invalid",
+			err:  pki.ErrFailedCertCreation,
+		},
+	}
+
+	for _, tc := range cases {
+		cert, err := agent.SignCSR(tc.csr, tc.ttl)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		signed, err := certs.ReadCert([]byte(cert.ClientCert))
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected cert parsing error: %s\n", tc.desc, err))
+		assert.Empty(t, cert.ClientKey, fmt.Sprintf("%s: unexpected private key\n", tc.desc))
+		assert.True(t, key.PublicKey.Equal(signed.PublicKey), fmt.Sprintf("%s: expected the CSR key\n", tc.desc))
+		assert.Equal(t, cn, signed.Subject.CommonName, fmt.Sprintf("%s: expected common name %s got %s\n", tc.desc, cn, signed.Subject.CommonName))
+		assert.Empty(t, signed.DNSNames, fmt.Sprintf("%s: unexpected requested extensions\n", tc.desc))
+		assert.NotContains(t, signed.Subject.Organization, "requested", fmt.Sprintf("%s: unexpected requested subject\n", tc.desc))
+	}
+}
+
+func TestLocalRead(t *testing.T) {
+	agent, _ := newLocalAgent(t)
+
+	cert, err := agent.IssueCert(cn, "1h")
+	require.Nil(t, err, fmt.Sprintf("unexpected cert issuing error: %s\n", err))
+
+	cases := []struct {
+		desc   string
+		serial string
+		cert   string
+		err    error
+	}{
+		{
+			desc:   "read issued cert",
+			serial: cert.Serial,
+			cert:   cert.ClientCert,
+			err:    nil,
+		},
+		{
+			desc:   "read non-existing cert",
+			serial: "00:01",
+			err:    pki.ErrNotFoundCert,
+		},
+	}
+
+	for _, tc := range cases {
+		c, err := agent.Read(tc.serial)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.cert, c.ClientCert, fmt.Sprintf("%s: expected cert %s got %s\n", tc.desc, tc.cert, c.ClientCert))
+		assert.Empty(t, c.ClientKey, fmt.Sprintf("%s: unexpected private key\n", tc.desc))
+	}
+}
+
+func TestLocalRevoke(t *testing.T) {
+	agent, _ := newLocalAgent(t)
+
+	cert, err := agent.IssueCert(cn, "1h")
+	require.Nil(t, err, fmt.Sprintf("unexpected cert issuing error: %s\n", err))
+
+	revoked, err := agent.Revoke(cert.Serial)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert revocation error: %s\n", err))
+
+	cases := []struct {
+		desc    string
+		serial  string
+		revoked time.Time
+		err     error
+	}{
+		{
+			desc:    "revoke revoked cert",
+			serial:  cert.Serial,
+			revoked: revoked,
+			err:     nil,
+		},
+		{
+			desc:   "revoke non-existing cert",
+			serial: "00:01",
+			err:    pki.ErrFailedCertRevocation,
+		},
+	}
+
+	for _, tc := range cases {
+		rev, err := agent.Revoke(tc.serial)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.revoked, rev, fmt.Sprintf("%s: expected revocation time %s got %s\n", tc.desc, tc.revoked, rev))
+	}
+}
+
+func TestLocalCRL(t *testing.T) {
+	agent, caCert := newLocalAgent(t)
+
+	valid, err := agent.IssueCert(cn, "1h")
+	require.Nil(t, err, fmt.Sprintf("unexpected cert issuing error: %s\n", err))
+	revoked, err := agent.IssueCert(cn, "1h")
+	require.Nil(t, err, fmt.Sprintf("unexpected cert issuing error: %s\n", err))
+	_, err = agent.Revoke(revoked.Serial)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert revocation error: %s\n", err))
+
+	der, err := agent.CRL()
+	require.Nil(t, err, fmt.Sprintf("unexpected CRL error: %s\n", err))
+	crl, err := x509.ParseRevocationList(der)
+	require.Nil(t, err, fmt.Sprintf("unexpected CRL parsing error: %s\n", err))
+	err = crl.CheckSignatureFrom(caCert)
+	assert.Nil(t, err, fmt.Sprintf("unexpected CRL signature error: %s\n", err))
+
+	serials := map[string]bool{}
+	for _, e := range crl.RevokedCertificateEntries {
+		serials[pki.FormatSerial(e.SerialNumber)] = true
+	}
+
+	cases := []struct {
+		desc    string
+		serial  string
+		revoked bool
+	}{
+		{
+			desc:    "revoked cert",
+			serial:  revoked.Serial,
+			revoked: true,
+		},
+		{
+			desc:    "valid cert",
+			serial:  valid.Serial,
+			revoked: false,
+		},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.revoked, serials[tc.serial], fmt.Sprintf("%s: expected listed %t got %t\n", tc.desc, tc.revoked, serials[tc.serial]))
+	}
+}
+
+func TestLocalOCSP(t *testing.T) {
+	agent, caCert := newLocalAgent(t)
+
+	valid, err := agent.IssueCert(cn, "1h")
+	require.Nil(t, err, fmt.Sprintf("unexpected cert issuing error: %s\n", err))
+	revoked, err := agent.IssueCert(cn, "1h")
+	require.Nil(t, err, fmt.Sprintf("unexpected cert issuing error: %s\n", err))
+	_, err = agent.Revoke(revoked.Serial)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert revocation error: %s\n", err))
+
+	// The unknown certificate is signed by the same CA, but not stored.
+	unknown, err := agent.IssueCert(cn, "1h")
+	require.Nil(t, err, fmt.Sprintf("unexpected cert issuing error: %s\n", err))
+	unknownCert, err := certs.ReadCert([]byte(unknown.ClientCert))
+	require.Nil(t, err, fmt.Sprintf("unexpected cert parsing error: %s\n", err))
+	unknownCert.SerialNumber.SetInt64(1)
+
+	cases := []struct {
+		desc   string
+		cert   string
+		status int
+	}{
+		{
+			desc:   "valid cert status",
+			cert:   valid.ClientCert,
+			status: ocsp.Good,
+		},
+		{
+			desc:   "revoked cert status",
+			cert:   revoked.ClientCert,
+			status: ocsp.Revoked,
+		},
+	}
+
+	for _, tc := range cases {
+		crt, err := certs.ReadCert([]byte(tc.cert))
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected cert parsing error: %s\n", tc.desc, err))
+		req, err := ocsp.CreateRequest(crt, caCert, &ocsp.RequestOptions{Hash: crypto.SHA1})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected OCSP request error: %s\n", tc.desc, err))
+
+		der, err := agent.OCSP(req)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected OCSP error: %s\n", tc.desc, err))
+		res, err := ocsp.ParseResponseForCert(der, crt, caCert)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected OCSP response parsing error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.status, res.Status, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, res.Status))
+	}
+
+	req, err := ocsp.CreateRequest(unknownCert, caCert, nil)
+	require.Nil(t, err, fmt.Sprintf("unexpected OCSP request error: %s\n", err))
+	der, err := agent.OCSP(req)
+	require.Nil(t, err, fmt.Sprintf("unexpected OCSP error: %s\n", err))
+	res, err := ocsp.ParseResponse(der, caCert)
+	require.Nil(t, err, fmt.Sprintf("unexpected OCSP response parsing error: %s\n", err))
+	assert.Equal(t, ocsp.Unknown, res.Status, fmt.Sprintf("unknown cert status: expected status %d got %d\n", ocsp.Unknown, res.Status))
+
+	der, err = agent.OCSP([]byte("malformed"))
+	require.Nil(t, err, fmt.Sprintf("unexpected OCSP error: %s\n", err))
+	assert.Equal(t, ocsp.MalformedRequestErrorResponse, der, "malformed request: expected malformed request response\n")
+}
@@ -5,7 +5,13 @@
 package pki
 
 import (
+	"context"
+	"crypto"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
 	"time"
 
 	"github.com/absmach/magistrala/pkg/errors"
@@ -14,9 +20,15 @@ import (
 )
 
 const (
-	issue  = "issue"
-	cert   = "cert"
-	revoke = "revoke"
+	issue      = "issue"
+	sign       = "sign"
+	cert       = "cert"
+	revoke     = "revoke"
+	crl        = "crl"
+	ocspPath   = "ocsp"
+	configURLs = "config/urls"
+
+	ocspContentType = "application/ocsp-request"
 )
 
 var (
@@ -29,7 +41,16 @@ var (
 	// ErrFailedCertRevocation indicates failed certificate revocation.
 	ErrFailedCertRevocation = errors.New("failed to revoke certificate")
 
+	// ErrFailedRevocationStatus indicates failed certificate revocation
+	// status retrieval.
+	ErrFailedRevocationStatus = errors.New("failed to retrieve revocation status")
+
+	// ErrKeyReuseUnsupported indicates that the PKI can not issue the
+	// certificate for the existing key.
+	ErrKeyReuseUnsupported = errors.New("issuing certificate for the existing key is not supported")
+
 	errFailedCertDecoding = errors.New("failed to decode response from vault service")
+	errFailedConfigURLs   = errors.New("failed to configure vault revocation status URLs")
 )
 
 type Cert struct {
@@ -47,11 +68,31 @@ type Agent interface {
 	// IssueCert issues certificate on PKI
 	IssueCert(cn, ttl string) (Cert, error)
 
+	// IssueCertWithKey issues certificate on PKI for the existing public key
+	IssueCertWithKey(cn, ttl string, key crypto.PublicKey) (Cert, error)
+
+	// SignCSR issues certificate on PKI for the certificate signing request
+	// key and common name, without the private key leaving the requester
+	SignCSR(csr *x509.CertificateRequest, ttl string) (Cert, error)
+
 	// Read retrieves certificate from PKI
 	Read(serial string) (Cert, error)
 
 	// Revoke revokes certificate from PKI
 	Revoke(serial string) (time.Time, error)
+
+	// CRL returns the DER encoded certificate revocation list from PKI
+	CRL() ([]byte, error)
+
+	// OCSP returns the DER encoded OCSP response to the DER encoded OCSP
+	// request
+	OCSP(req []byte) ([]byte, error)
+}
+
+// URLs are the revocation status URLs embedded into the issued certificates.
+type URLs struct {
+	CRL  string
+	OCSP string
 }
 
 type pkiAgent struct {
@@ -60,8 +101,11 @@ type pkiAgent struct {
 	role      string
 	host      string
 	issueURL  string
+	signURL   string
 	readURL   string
 	revokeURL string
+	crlURL    string
+	ocspURL   string
 	client    *api.Client
 }
 
@@ -70,12 +114,20 @@ type certReq struct {
 	TTL        string `json:"ttl"`
 }
 
+type csrReq struct {
+	CSR        string `json:"csr"`
+	CommonName string `json:"common_name"`
+	TTL        string `json:"ttl"`
+}
+
 type certRevokeReq struct {
 	SerialNumber string `json:"serial_number"`
 }
 
-// NewVaultClient instantiates a Vault client.
-func NewVaultClient(token, host, path, role string) (Agent, error) {
+// NewVaultClient instantiates a Vault client. Unless empty, the revocation
+// status URLs are configured on Vault, so they are embedded into the issued
+// certificates.
+func NewVaultClient(token, host, path, role string, urls URLs) (Agent, error) {
 	conf := api.DefaultConfig()
 	conf.Address = host
 
@@ -91,9 +143,26 @@ func NewVaultClient(token, host, path, role string) (Agent, error) {
 		path:      path,
 		client:    client,
 		issueURL:  "/" + path + "/" + issue + "/" + role,
+		signURL:   "/" + path + "/" + sign + "/" + role,
 		readURL:   "/" + path + "/" + cert + "/",
 		revokeURL: "/" + path + "/" + revoke,
+		crlURL:    "/v1/" + path + "/" + crl,
+		ocspURL:   "/v1/" + path + "/" + ocspPath,
 	}
+
+	if urls.CRL != "" || urls.OCSP != "" {
+		data := map[string]interface{}{}
+		if urls.CRL != "" {
+			data["crl_distribution_points"] = []string{urls.CRL}
+		}
+		if urls.OCSP != "" {
+			data["ocsp_servers"] = []string{urls.OCSP}
+		}
+		if _, err := client.Logical().Write("/"+path+"/"+configURLs, data); err != nil {
+			return nil, errors.Wrap(errFailedConfigURLs, err)
+		}
+	}
+
 	return &p, nil
 }
 
@@ -125,6 +194,41 @@ func (p *pkiAgent) IssueCert(cn, ttl string) (Cert, error) {
 	return cert, nil
 }
 
+// IssueCertWithKey is not supported, since Vault signs only the certificate
+// signing requests, which can not be created without the private key.
+func (p *pkiAgent) IssueCertWithKey(cn, ttl string, key crypto.PublicKey) (Cert, error) {
+	return Cert{}, ErrKeyReuseUnsupported
+}
+
+func (p *pkiAgent) SignCSR(csr *x509.CertificateRequest, ttl string) (Cert, error) {
+	cReq := csrReq{
+		CSR:        string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr.Raw})),
+		CommonName: csr.Subject.CommonName,
+		TTL:        ttl,
+	}
+
+	var certSignReq map[string]interface{}
+	data, err := json.Marshal(cReq)
+	if err != nil {
+		return Cert{}, err
+	}
+	if err := json.Unmarshal(data, &certSignReq); err != nil {
+		return Cert{}, err
+	}
+
+	s, err := p.client.Logical().Write(p.signURL, certSignReq)
+	if err != nil {
+		return Cert{}, err
+	}
+
+	cert := Cert{}
+	if err = mapstructure.Decode(s.Data, &cert); err != nil {
+		return Cert{}, errors.Wrap(errFailedCertDecoding, err)
+	}
+
+	return cert, nil
+}
+
 func (p *pkiAgent) Read(serial string) (Cert, error) {
 	s, err := p.client.Logical().Read(p.readURL + serial)
 	if err != nil {
@@ -162,3 +266,33 @@ func (p *pkiAgent) Revoke(serial string) (time.Time, error) {
 
 	return time.Unix(0, int64(rev)*int64(time.Second)), nil
 }
+
+func (p *pkiAgent) CRL() ([]byte, error) {
+	return p.raw(p.client.NewRequest(http.MethodGet, p.crlURL))
+}
+
+func (p *pkiAgent) OCSP(req []byte) ([]byte, error) {
+	r := p.client.NewRequest(http.MethodPost, p.ocspURL)
+	if r.Headers == nil {
+		r.Headers = http.Header{}
+	}
+	r.Headers.Set("Content-Type", ocspContentType)
+	r.BodyBytes = req
+
+	return p.raw(r)
+}
+
+func (p *pkiAgent) raw(r *api.Request) ([]byte, error) {
+	res, err := p.client.RawRequestWithContext(context.Background(), r)
+	if err != nil {
+		return nil, errors.Wrap(ErrFailedRevocationStatus, err)
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(ErrFailedRevocationStatus, err)
+	}
+
+	return b, nil
+}
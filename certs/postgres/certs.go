@@ -73,7 +73,7 @@ func (cr certsRepository) RetrieveAll(ctx context.Context, ownerID string, offse
 }
 
 func (cr certsRepository) Save(ctx context.Context, cert certs.Cert) (string, error) {
-	q := `INSERT INTO certs (thing_id, owner_id, serial, expire) VALUES (:thing_id, :owner_id, :serial, :expire)`
+	q := `INSERT INTO certs (thing_id, owner_id, serial, expire, ttl) VALUES (:thing_id, :owner_id, :serial, :expire, :ttl)`
 
 	tx, err := cr.db.BeginTxx(ctx, nil)
 	if err != nil {
@@ -114,9 +114,25 @@ func (cr certsRepository) Remove(ctx context.Context, ownerID, serial string) er
 	return nil
 }
 
-func (cr certsRepository) RetrieveByThing(ctx context.Context, ownerID, thingID string, offset, limit uint64) (certs.Page, error) {
-	q := `SELECT thing_id, owner_id, serial, expire FROM certs WHERE owner_id = $1 AND thing_id = $2 ORDER BY expire LIMIT $3 OFFSET $4;`
-	rows, err := cr.db.QueryContext(ctx, q, ownerID, thingID, limit, offset)
+func (cr certsRepository) RetrieveByThing(ctx context.Context, ownerID, thingID string, pm certs.PageMetadata) (certs.Page, error) {
+	query := `WHERE owner_id = :owner_id AND thing_id = :thing_id`
+	if !pm.ExpiresAfter.IsZero() {
+		query += ` AND expire > :expires_after`
+	}
+	if !pm.ExpiresBefore.IsZero() {
+		query += ` AND expire < :expires_before`
+	}
+	params := map[string]interface{}{
+		"owner_id":       ownerID,
+		"thing_id":       thingID,
+		"expires_after":  pm.ExpiresAfter,
+		"expires_before": pm.ExpiresBefore,
+		"limit":          pm.Limit,
+		"offset":         pm.Offset,
+	}
+
+	q := fmt.Sprintf(`SELECT thing_id, owner_id, serial, expire FROM certs %s ORDER BY expire LIMIT :limit OFFSET :offset;`, query)
+	rows, err := cr.db.NamedQueryContext(ctx, q, params)
 	if err != nil {
 		cr.log.Error(fmt.Sprintf("Failed to retrieve configs due to %s", err))
 		return certs.Page{}, err
@@ -133,23 +149,23 @@ func (cr certsRepository) RetrieveByThing(ctx context.Context, ownerID, thingID
 		certificates = append(certificates, c)
 	}
 
-	q = `SELECT COUNT(*) FROM certs WHERE owner_id = $1 AND thing_id = $2`
-	var total uint64
-	if err := cr.db.QueryRowxContext(ctx, q, ownerID, thingID).Scan(&total); err != nil {
+	q = fmt.Sprintf(`SELECT COUNT(*) FROM certs %s`, query)
+	total, err := postgres.Total(ctx, cr.db, q, params)
+	if err != nil {
 		cr.log.Error(fmt.Sprintf("Failed to count certs due to %s", err))
 		return certs.Page{}, err
 	}
 
 	return certs.Page{
 		Total:  total,
-		Limit:  limit,
-		Offset: offset,
+		Limit:  pm.Limit,
+		Offset: pm.Offset,
 		Certs:  certificates,
 	}, nil
 }
 
 func (cr certsRepository) RetrieveBySerial(ctx context.Context, ownerID, serialID string) (certs.Cert, error) {
-	q := `SELECT thing_id, owner_id, serial, expire FROM certs WHERE owner_id = $1 AND serial = $2`
+	q := `SELECT thing_id, owner_id, serial, expire, ttl FROM certs WHERE owner_id = $1 AND serial = $2`
 	var dbcrt dbCert
 	var c certs.Cert
 
@@ -166,6 +182,59 @@ func (cr certsRepository) RetrieveBySerial(ctx context.Context, ownerID, serialI
 	return c, nil
 }
 
+func (cr certsRepository) RetrieveThingID(ctx context.Context, serialID string) (string, error) {
+	q := `SELECT thing_id FROM certs WHERE serial = $1`
+	var thingID string
+
+	if err := cr.db.QueryRowxContext(ctx, q, serialID).Scan(&thingID); err != nil {
+		if err == sql.ErrNoRows {
+			return "", errors.Wrap(errors.ErrNotFound, err)
+		}
+
+		return "", errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	return thingID, nil
+}
+
+func (cr certsRepository) RetrieveExpiring(ctx context.Context, before time.Time, limit uint64) ([]certs.Cert, error) {
+	q := `SELECT thing_id, owner_id, serial, expire FROM certs
+		WHERE expire > NOW() AND expire <= $1 AND NOT expiry_notified ORDER BY expire LIMIT $2;`
+	rows, err := cr.db.QueryxContext(ctx, q, before, limit)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+
+	certificates := []certs.Cert{}
+	for rows.Next() {
+		var dbcrt dbCert
+		if err := rows.StructScan(&dbcrt); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		certificates = append(certificates, toCert(dbcrt))
+	}
+
+	return certificates, nil
+}
+
+func (cr certsRepository) UpdateExpiryNotified(ctx context.Context, serialID string) error {
+	q := `UPDATE certs SET expiry_notified = TRUE WHERE serial = $1`
+	res, err := cr.db.ExecContext(ctx, q, serialID)
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	if cnt == 0 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
 func (cr certsRepository) rollback(content string, tx *sqlx.Tx, err error) {
 	cr.log.Error(fmt.Sprintf("%s %s", content, err))
 
@@ -179,6 +248,7 @@ type dbCert struct {
 	Serial  string    `db:"serial"`
 	Expire  time.Time `db:"expire"`
 	OwnerID string    `db:"owner_id"`
+	TTL     string    `db:"ttl"`
 }
 
 func toDBCert(c certs.Cert) dbCert {
@@ -187,6 +257,7 @@ func toDBCert(c certs.Cert) dbCert {
 		OwnerID: c.OwnerID,
 		Serial:  c.Serial,
 		Expire:  c.Expire,
+		TTL:     c.TTL,
 	}
 }
 
@@ -196,5 +267,6 @@ func toCert(cdb dbCert) certs.Cert {
 	c.ThingID = cdb.ThingID
 	c.Serial = cdb.Serial
 	c.Expire = cdb.Expire
+	c.TTL = cdb.TTL
 	return c
 }
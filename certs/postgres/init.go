@@ -24,6 +24,40 @@ func Migration() *migrate.MemoryMigrationSource {
 					"DROP TABLE IF EXISTS certs;",
 				},
 			},
+			{
+				Id: "certs_2",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS pki_certs (
+						serial       TEXT PRIMARY KEY,
+						certificate  TEXT NOT NULL,
+						expire       TIMESTAMPTZ NOT NULL,
+						revoked      TIMESTAMPTZ
+					);`,
+				},
+				Down: []string{
+					"DROP TABLE IF EXISTS pki_certs;",
+				},
+			},
+			{
+				Id: "certs_3",
+				Up: []string{
+					`ALTER TABLE certs ADD COLUMN IF NOT EXISTS expiry_notified BOOLEAN NOT NULL DEFAULT FALSE;`,
+					`CREATE INDEX IF NOT EXISTS certs_expire_idx ON certs (expire);`,
+				},
+				Down: []string{
+					"DROP INDEX IF EXISTS certs_expire_idx;",
+					"ALTER TABLE certs DROP COLUMN IF EXISTS expiry_notified;",
+				},
+			},
+			{
+				Id: "certs_4",
+				Up: []string{
+					`ALTER TABLE certs ADD COLUMN IF NOT EXISTS ttl TEXT NOT NULL DEFAULT '';`,
+				},
+				Down: []string{
+					"ALTER TABLE certs DROP COLUMN IF EXISTS ttl;",
+				},
+			},
 		},
 	}
 }
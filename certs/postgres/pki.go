@@ -0,0 +1,123 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/absmach/magistrala/certs/pki"
+	"github.com/absmach/magistrala/internal/postgres"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var _ pki.Repository = (*pkiRepository)(nil)
+
+type pkiRepository struct {
+	db postgres.Database
+}
+
+// NewPKIRepository instantiates a PostgreSQL implementation of the local PKI
+// agent repository.
+func NewPKIRepository(db postgres.Database) pki.Repository {
+	return &pkiRepository{db: db}
+}
+
+func (pr pkiRepository) Save(ctx context.Context, cert pki.IssuedCert) error {
+	q := `INSERT INTO pki_certs (serial, certificate, expire) VALUES (:serial, :certificate, :expire)`
+
+	if _, err := pr.db.NamedExecContext(ctx, q, toDBIssuedCert(cert)); err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == pgerrcode.UniqueViolation {
+			return errors.Wrap(errors.ErrConflict, err)
+		}
+		return errors.Wrap(errors.ErrCreateEntity, err)
+	}
+
+	return nil
+}
+
+func (pr pkiRepository) Retrieve(ctx context.Context, serial string) (pki.IssuedCert, error) {
+	q := `SELECT serial, certificate, expire, revoked FROM pki_certs WHERE serial = $1`
+
+	var dbc dbIssuedCert
+	if err := pr.db.QueryRowxContext(ctx, q, serial).StructScan(&dbc); err != nil {
+		if err == sql.ErrNoRows {
+			return pki.IssuedCert{}, errors.Wrap(pki.ErrNotFoundCert, err)
+		}
+		return pki.IssuedCert{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	return toIssuedCert(dbc), nil
+}
+
+func (pr pkiRepository) Revoke(ctx context.Context, serial string, revoked time.Time) error {
+	q := `UPDATE pki_certs SET revoked = $2 WHERE serial = $1`
+
+	res, err := pr.db.ExecContext(ctx, q, serial, revoked)
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	if cnt == 0 {
+		return pki.ErrNotFoundCert
+	}
+
+	return nil
+}
+
+func (pr pkiRepository) RetrieveRevoked(ctx context.Context) ([]pki.IssuedCert, error) {
+	q := `SELECT serial, certificate, expire, revoked FROM pki_certs WHERE revoked IS NOT NULL AND expire > NOW() ORDER BY revoked`
+
+	rows, err := pr.db.QueryxContext(ctx, q)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+
+	var certs []pki.IssuedCert
+	for rows.Next() {
+		var dbc dbIssuedCert
+		if err := rows.StructScan(&dbc); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		certs = append(certs, toIssuedCert(dbc))
+	}
+
+	return certs, nil
+}
+
+type dbIssuedCert struct {
+	Serial      string       `db:"serial"`
+	Certificate string       `db:"certificate"`
+	Expire      time.Time    `db:"expire"`
+	Revoked     sql.NullTime `db:"revoked"`
+}
+
+func toDBIssuedCert(c pki.IssuedCert) dbIssuedCert {
+	return dbIssuedCert{
+		Serial:      c.Serial,
+		Certificate: c.Certificate,
+		Expire:      c.Expire,
+		Revoked:     sql.NullTime{Time: c.Revoked, Valid: !c.Revoked.IsZero()},
+	}
+}
+
+func toIssuedCert(dbc dbIssuedCert) pki.IssuedCert {
+	c := pki.IssuedCert{
+		Serial:      dbc.Serial,
+		Certificate: dbc.Certificate,
+		Expire:      dbc.Expire,
+	}
+	if dbc.Revoked.Valid {
+		c.Revoked = dbc.Revoked.Time
+	}
+
+	return c
+}
@@ -4,7 +4,9 @@
 package certs
 
 import (
+	"bytes"
 	"context"
+	"crypto/x509"
 	"time"
 
 	"github.com/absmach/magistrala"
@@ -25,6 +27,19 @@ var (
 	ErrFailedToRemoveCertFromDB = errors.New("failed to remove cert serial from db")
 
 	ErrFailedReadFromPKI = errors.New("failed to read certificate from PKI")
+
+	// ErrFailedCertRenewal failed to renew certificate.
+	ErrFailedCertRenewal = errors.New("failed to renew certificate")
+
+	// ErrExpiredCert indicates that the certificate is expired.
+	ErrExpiredCert = errors.New("certificate is expired")
+
+	// ErrInvalidCSR indicates malformed certificate signing request or the
+	// request of which the subject does not match the thing.
+	ErrInvalidCSR = errors.New("invalid certificate signing request")
+
+	errCSRSubject   = errors.New("certificate signing request subject does not match the thing")
+	errCertMismatch = errors.New("certificate does not match the issued certificate")
 )
 
 var _ Service = (*certsService)(nil)
@@ -35,17 +50,40 @@ type Service interface {
 	// IssueCert issues certificate for given thing id if access is granted with token
 	IssueCert(ctx context.Context, token, thingID, ttl string) (Cert, error)
 
+	// IssueCertFromCSR issues certificate for given thing id by signing the
+	// PEM encoded PKCS#10 certificate signing request, so the private key
+	// never leaves the device. The CSR common name must be the thing ID.
+	IssueCertFromCSR(ctx context.Context, token, thingID, csr, ttl string) (Cert, error)
+
+	// RenewCert issues a new certificate for the thing of the certificate
+	// with a given serial ID. The renewed certificate stays valid until it
+	// expires, so both certificates can be used in the meantime. Unless the
+	// TTL is set, the new certificate is issued with the TTL of the renewed
+	// one, and if reuseKey is true, it is issued for the renewed certificate
+	// key.
+	RenewCert(ctx context.Context, token, serialID, ttl string, reuseKey bool) (Cert, error)
+
 	// ListCerts lists certificates issued for a given thing ID
-	ListCerts(ctx context.Context, token, thingID string, offset, limit uint64) (Page, error)
+	ListCerts(ctx context.Context, token, thingID string, pm PageMetadata) (Page, error)
 
 	// ListSerials lists certificate serial IDs issued for a given thing ID
-	ListSerials(ctx context.Context, token, thingID string, offset, limit uint64) (Page, error)
+	ListSerials(ctx context.Context, token, thingID string, pm PageMetadata) (Page, error)
 
 	// ViewCert retrieves the certificate issued for a given serial ID
 	ViewCert(ctx context.Context, token, serialID string) (Cert, error)
 
 	// RevokeCert revokes a certificate for a given serial ID
 	RevokeCert(ctx context.Context, token, serialID string) (Revoke, error)
+
+	// CRL returns the DER encoded certificate revocation list
+	CRL(ctx context.Context) ([]byte, error)
+
+	// OCSP returns the DER encoded OCSP response to the DER encoded OCSP request
+	OCSP(ctx context.Context, req []byte) ([]byte, error)
+
+	// IdentifyThing returns the ID of the thing the DER encoded certificate
+	// is issued to. Expired and revoked certificates are not identified.
+	IdentifyThing(ctx context.Context, cert []byte) (string, error)
 }
 
 type certsService struct {
@@ -81,6 +119,7 @@ type Cert struct {
 	PrivateKeyType string    `json:"private_key_type" mapstructure:"private_key_type"`
 	Serial         string    `json:"serial" mapstructure:"serial_number"`
 	Expire         time.Time `json:"expire" mapstructure:"-"`
+	TTL            string    `json:"ttl" mapstructure:"-"`
 }
 
 func (cs *certsService) IssueCert(ctx context.Context, token, thingID, ttl string) (Cert, error) {
@@ -99,22 +138,106 @@ func (cs *certsService) IssueCert(ctx context.Context, token, thingID, ttl strin
 		return Cert{}, errors.Wrap(ErrFailedCertCreation, err)
 	}
 
-	c := Cert{
-		ThingID:        thingID,
-		OwnerID:        owner.GetId(),
-		ClientCert:     cert.ClientCert,
-		IssuingCA:      cert.IssuingCA,
-		CAChain:        cert.CAChain,
-		ClientKey:      cert.ClientKey,
-		PrivateKeyType: cert.PrivateKeyType,
-		Serial:         cert.Serial,
-		Expire:         time.Unix(0, int64(cert.Expire)*int64(time.Second)),
+	c := newCert(thingID, owner.GetId(), ttl, cert)
+
+	_, err = cs.certsRepo.Save(ctx, c)
+	return c, err
+}
+
+func (cs *certsService) IssueCertFromCSR(ctx context.Context, token, thingID, csr, ttl string) (Cert, error) {
+	owner, err := cs.auth.Identify(ctx, &magistrala.IdentityReq{Token: token})
+	if err != nil {
+		return Cert{}, errors.Wrap(svcerr.ErrAuthentication, err)
 	}
 
+	req, err := ReadCSR([]byte(csr))
+	if err != nil {
+		return Cert{}, errors.Wrap(ErrInvalidCSR, err)
+	}
+
+	thing, err := cs.sdk.Thing(thingID, token)
+	if err != nil {
+		return Cert{}, errors.Wrap(ErrFailedCertCreation, err)
+	}
+	// The CSR is bound to the thing by its ID rather than the thing key, so
+	// the secret is not embedded into the publicly presented certificate.
+	if req.Subject.CommonName != thing.ID {
+		return Cert{}, errors.Wrap(ErrInvalidCSR, errCSRSubject)
+	}
+
+	cert, err := cs.pki.SignCSR(req, ttl)
+	if err != nil {
+		return Cert{}, errors.Wrap(ErrFailedCertCreation, err)
+	}
+
+	c := newCert(thingID, owner.GetId(), ttl, cert)
+
 	_, err = cs.certsRepo.Save(ctx, c)
 	return c, err
 }
 
+func (cs *certsService) RenewCert(ctx context.Context, token, serialID, ttl string, reuseKey bool) (Cert, error) {
+	owner, err := cs.auth.Identify(ctx, &magistrala.IdentityReq{Token: token})
+	if err != nil {
+		return Cert{}, errors.Wrap(svcerr.ErrAuthentication, err)
+	}
+
+	old, err := cs.certsRepo.RetrieveBySerial(ctx, owner.GetId(), serialID)
+	if err != nil {
+		return Cert{}, errors.Wrap(repoerr.ErrNotFound, err)
+	}
+	if !old.Expire.After(time.Now()) {
+		return Cert{}, ErrExpiredCert
+	}
+
+	vcert, err := cs.pki.Read(serialID)
+	if err != nil {
+		return Cert{}, errors.Wrap(ErrFailedReadFromPKI, err)
+	}
+	x509Cert, err := ReadCert([]byte(vcert.ClientCert))
+	if err != nil {
+		return Cert{}, errors.Wrap(ErrFailedReadFromPKI, err)
+	}
+	// The certificate validity includes the PKI agent clock skew, so the
+	// TTL the certificate was issued with is reused to prevent the validity
+	// from growing on each renewal. The validity is only used for the
+	// certificates saved without the TTL.
+	if ttl == "" {
+		ttl = old.TTL
+	}
+	if ttl == "" {
+		ttl = x509Cert.NotAfter.Sub(x509Cert.NotBefore).String()
+	}
+
+	thing, err := cs.sdk.Thing(old.ThingID, token)
+	if err != nil {
+		return Cert{}, errors.Wrap(ErrFailedCertRenewal, err)
+	}
+
+	var cert pki.Cert
+	switch reuseKey {
+	case true:
+		cert, err = cs.pki.IssueCertWithKey(thing.Credentials.Secret, ttl, x509Cert.PublicKey)
+	default:
+		cert, err = cs.pki.IssueCert(thing.Credentials.Secret, ttl)
+	}
+	if err != nil {
+		return Cert{}, errors.Wrap(ErrFailedCertRenewal, err)
+	}
+
+	c := newCert(old.ThingID, owner.GetId(), ttl, cert)
+	if _, err := cs.certsRepo.Save(ctx, c); err != nil {
+		return Cert{}, err
+	}
+
+	// The renewed certificate expiry does not need to be notified anymore.
+	if err := cs.certsRepo.UpdateExpiryNotified(ctx, old.Serial); err != nil {
+		return Cert{}, errors.Wrap(ErrFailedCertRenewal, err)
+	}
+
+	return c, nil
+}
+
 func (cs *certsService) RevokeCert(ctx context.Context, token, thingID string) (Revoke, error) {
 	var revoke Revoke
 	u, err := cs.auth.Identify(ctx, &magistrala.IdentityReq{Token: token})
@@ -126,8 +249,8 @@ func (cs *certsService) RevokeCert(ctx context.Context, token, thingID string) (
 		return revoke, errors.Wrap(ErrFailedCertRevocation, err)
 	}
 
-	offset, limit := uint64(0), uint64(10000)
-	cp, err := cs.certsRepo.RetrieveByThing(ctx, u.GetId(), thing.ID, offset, limit)
+	pm := PageMetadata{Offset: 0, Limit: 10000}
+	cp, err := cs.certsRepo.RetrieveByThing(ctx, u.GetId(), thing.ID, pm)
 	if err != nil {
 		return revoke, errors.Wrap(ErrFailedCertRevocation, err)
 	}
@@ -146,13 +269,13 @@ func (cs *certsService) RevokeCert(ctx context.Context, token, thingID string) (
 	return revoke, nil
 }
 
-func (cs *certsService) ListCerts(ctx context.Context, token, thingID string, offset, limit uint64) (Page, error) {
+func (cs *certsService) ListCerts(ctx context.Context, token, thingID string, pm PageMetadata) (Page, error) {
 	u, err := cs.auth.Identify(ctx, &magistrala.IdentityReq{Token: token})
 	if err != nil {
 		return Page{}, errors.Wrap(svcerr.ErrAuthentication, err)
 	}
 
-	cp, err := cs.certsRepo.RetrieveByThing(ctx, u.GetId(), thingID, offset, limit)
+	cp, err := cs.certsRepo.RetrieveByThing(ctx, u.GetId(), thingID, pm)
 	if err != nil {
 		return Page{}, errors.Wrap(repoerr.ErrNotFound, err)
 	}
@@ -169,13 +292,13 @@ func (cs *certsService) ListCerts(ctx context.Context, token, thingID string, of
 	return cp, nil
 }
 
-func (cs *certsService) ListSerials(ctx context.Context, token, thingID string, offset, limit uint64) (Page, error) {
+func (cs *certsService) ListSerials(ctx context.Context, token, thingID string, pm PageMetadata) (Page, error) {
 	u, err := cs.auth.Identify(ctx, &magistrala.IdentityReq{Token: token})
 	if err != nil {
 		return Page{}, errors.Wrap(svcerr.ErrAuthentication, err)
 	}
 
-	return cs.certsRepo.RetrieveByThing(ctx, u.GetId(), thingID, offset, limit)
+	return cs.certsRepo.RetrieveByThing(ctx, u.GetId(), thingID, pm)
 }
 
 func (cs *certsService) ViewCert(ctx context.Context, token, serialID string) (Cert, error) {
@@ -203,3 +326,60 @@ func (cs *certsService) ViewCert(ctx context.Context, token, serialID string) (C
 
 	return c, nil
 }
+
+func (cs *certsService) CRL(ctx context.Context) ([]byte, error) {
+	// PKI agent errors are already wrapped with pki.ErrFailedRevocationStatus.
+	return cs.pki.CRL()
+}
+
+func (cs *certsService) OCSP(ctx context.Context, req []byte) ([]byte, error) {
+	return cs.pki.OCSP(req)
+}
+
+func (cs *certsService) IdentifyThing(ctx context.Context, cert []byte) (string, error) {
+	x509Cert, err := x509.ParseCertificate(cert)
+	if err != nil {
+		return "", errors.Wrap(svcerr.ErrMalformedEntity, err)
+	}
+	if !time.Now().Before(x509Cert.NotAfter) {
+		return "", ErrExpiredCert
+	}
+
+	// Only the serial of the certificate is stored, so the certificate is
+	// compared to the one issued by the PKI agent with the same serial.
+	serial := pki.FormatSerial(x509Cert.SerialNumber)
+	issued, err := cs.pki.Read(serial)
+	if err != nil {
+		return "", errors.Wrap(svcerr.ErrAuthentication, err)
+	}
+	issuedCert, err := ReadCert([]byte(issued.ClientCert))
+	if err != nil {
+		return "", errors.Wrap(svcerr.ErrAuthentication, err)
+	}
+	if !bytes.Equal(issuedCert.Raw, x509Cert.Raw) {
+		return "", errors.Wrap(svcerr.ErrAuthentication, errCertMismatch)
+	}
+
+	// Revoked certificates are removed from the repository.
+	thingID, err := cs.certsRepo.RetrieveThingID(ctx, serial)
+	if err != nil {
+		return "", errors.Wrap(svcerr.ErrAuthentication, err)
+	}
+
+	return thingID, nil
+}
+
+func newCert(thingID, ownerID, ttl string, cert pki.Cert) Cert {
+	return Cert{
+		ThingID:        thingID,
+		OwnerID:        ownerID,
+		ClientCert:     cert.ClientCert,
+		IssuingCA:      cert.IssuingCA,
+		CAChain:        cert.CAChain,
+		ClientKey:      cert.ClientKey,
+		PrivateKeyType: cert.PrivateKeyType,
+		Serial:         cert.Serial,
+		Expire:         time.Unix(0, int64(cert.Expire)*int64(time.Second)),
+		TTL:            ttl,
+	}
+}
@@ -5,6 +5,13 @@ package certs_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
 	"strings"
 	"testing"
@@ -14,6 +21,7 @@ import (
 	authmocks "github.com/absmach/magistrala/auth/mocks"
 	"github.com/absmach/magistrala/certs"
 	"github.com/absmach/magistrala/certs/mocks"
+	"github.com/absmach/magistrala/certs/pki"
 	"github.com/absmach/magistrala/pkg/errors"
 	svcerr "github.com/absmach/magistrala/pkg/errors/service"
 	mgsdk "github.com/absmach/magistrala/pkg/sdk/go"
@@ -21,6 +29,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
 )
 
 const (
@@ -109,6 +118,209 @@ func TestIssueCert(t *testing.T) {
 	}
 }
 
+func newCSR(t *testing.T, cn string) (string, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err, fmt.Sprintf("unexpected key generation error: %s\n", err))
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: cn}}, key)
+	require.Nil(t, err, fmt.Sprintf("unexpected CSR creation error: %s\n", err))
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})), key
+}
+
+func TestIssueCertFromCSR(t *testing.T) {
+	svc, auth, sdk := newService(t)
+
+	csr, key := newCSR(t, thingID)
+	mismatched, _ := newCSR(t, thingKey)
+	block, _ := pem.Decode([]byte(csr))
+	block.Bytes[len(block.Bytes)-1] ^= 0xff
+	tampered := string(pem.EncodeToMemory(block))
+
+	cases := []struct {
+		desc    string
+		token   string
+		thingID string
+		csr     string
+		ttl     string
+		err     error
+	}{
+		{
+			desc:    "issue new cert from CSR",
+			token:   token,
+			thingID: thingID,
+			csr:     csr,
+			ttl:     ttl,
+			err:     nil,
+		},
+		{
+			desc:    "issue new cert from CSR with thing key subject",
+			token:   token,
+			thingID: thingID,
+			csr:     mismatched,
+			ttl:     ttl,
+			err:     certs.ErrInvalidCSR,
+		},
+		{
+			desc:    "issue new cert from CSR with invalid signature",
+			token:   token,
+			thingID: thingID,
+			csr:     tampered,
+			ttl:     ttl,
+			err:     certs.ErrInvalidCSR,
+		},
+		{
+			desc:    "issue new cert from malformed CSR",
+			token:   token,
+			thingID: thingID,
+			csr:     invalid,
+			ttl:     ttl,
+			err:     certs.ErrInvalidCSR,
+		},
+		{
+			desc:    "issue new cert from CSR for non existing thing id",
+			token:   token,
+			thingID: "2",
+			csr:     csr,
+			ttl:     ttl,
+			err:     certs.ErrFailedCertCreation,
+		},
+		{
+			desc:    "issue new cert from CSR with invalid token",
+			token:   invalid,
+			thingID: thingID,
+			csr:     csr,
+			ttl:     ttl,
+			err:     svcerr.ErrAuthentication,
+		},
+	}
+
+	for _, tc := range cases {
+		var sdkErr error
+		if tc.thingID != thingID {
+			sdkErr = certs.ErrFailedCertCreation
+		}
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: validID}, nil)
+		repoCall1 := sdk.On("Thing", mock.Anything, mock.Anything).Return(mgsdk.Thing{ID: tc.thingID, Credentials: mgsdk.Credentials{Secret: thingKey}}, errors.NewSDKError(sdkErr))
+		c, err := svc.IssueCertFromCSR(context.Background(), tc.token, tc.thingID, tc.csr, tc.ttl)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Empty(t, c.ClientKey, fmt.Sprintf("%s: expected no private key got %s\n", tc.desc, c.ClientKey))
+			cert, err := certs.ReadCert([]byte(c.ClientCert))
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected cert parsing error: %s\n", tc.desc, err))
+			assert.Equal(t, thingKey, cert.Subject.CommonName, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, thingKey, cert.Subject.CommonName))
+			assert.True(t, key.PublicKey.Equal(cert.PublicKey), fmt.Sprintf("%s: expected cert for the CSR key\n", tc.desc))
+		}
+		repoCall.Unset()
+		repoCall1.Unset()
+	}
+}
+
+func TestRenewCert(t *testing.T) {
+	svc, auth, sdk := newService(t)
+
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := sdk.On("Thing", mock.Anything, mock.Anything).Return(mgsdk.Thing{ID: thingID, Credentials: mgsdk.Credentials{Secret: thingKey}}, nil)
+	c, err := svc.IssueCert(context.Background(), token, thingID, ttl)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert creation error: %s\n", err))
+	expired, err := svc.IssueCert(context.Background(), token, thingID, "1ns")
+	require.Nil(t, err, fmt.Sprintf("unexpected cert creation error: %s\n", err))
+	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
+
+	issued, err := certs.ReadCert([]byte(c.ClientCert))
+	require.Nil(t, err, fmt.Sprintf("unexpected cert parsing error: %s\n", err))
+
+	cases := []struct {
+		desc     string
+		token    string
+		serialID string
+		ttl      string
+		reuseKey bool
+		validFor time.Duration
+		err      error
+	}{
+		{
+			desc:     "renew cert",
+			token:    token,
+			serialID: c.Serial,
+			validFor: time.Hour,
+			err:      nil,
+		},
+		{
+			desc:     "renew cert with TTL",
+			token:    token,
+			serialID: c.Serial,
+			ttl:      "2h",
+			validFor: 2 * time.Hour,
+			err:      nil,
+		},
+		{
+			desc:     "renew cert with key reuse",
+			token:    token,
+			serialID: c.Serial,
+			reuseKey: true,
+			validFor: time.Hour,
+			err:      nil,
+		},
+		{
+			desc:     "renew expired cert",
+			token:    token,
+			serialID: expired.Serial,
+			err:      certs.ErrExpiredCert,
+		},
+		{
+			desc:     "renew non-existing cert",
+			token:    token,
+			serialID: invalid,
+			err:      svcerr.ErrNotFound,
+		},
+		{
+			desc:     "renew cert with invalid token",
+			token:    invalid,
+			serialID: c.Serial,
+			err:      svcerr.ErrAuthentication,
+		},
+	}
+
+	for _, tc := range cases {
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: validID}, nil)
+		repoCall1 := sdk.On("Thing", mock.Anything, mock.Anything).Return(mgsdk.Thing{ID: thingID, Credentials: mgsdk.Credentials{Secret: thingKey}}, nil)
+		rc, err := svc.RenewCert(context.Background(), tc.token, tc.serialID, tc.ttl, tc.reuseKey)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.NotEqual(t, tc.serialID, rc.Serial, fmt.Sprintf("%s: expected new serial got %s\n", tc.desc, rc.Serial))
+			assert.Equal(t, thingID, rc.ThingID, fmt.Sprintf("%s: expected thing ID %s got %s\n", tc.desc, thingID, rc.ThingID))
+			renewed, err := certs.ReadCert([]byte(rc.ClientCert))
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected cert parsing error: %s\n", tc.desc, err))
+			validFor := renewed.NotAfter.Sub(renewed.NotBefore) - mocks.ClockSkew
+			assert.Equal(t, tc.validFor, validFor, fmt.Sprintf("%s: expected validity %s got %s\n", tc.desc, tc.validFor, validFor))
+			assert.Equal(t, tc.reuseKey, issued.PublicKey.(*rsa.PublicKey).Equal(renewed.PublicKey), fmt.Sprintf("%s: unexpected renewed cert key reuse\n", tc.desc))
+			assert.Equal(t, tc.reuseKey, rc.ClientKey == "", fmt.Sprintf("%s: unexpected renewed cert private key\n", tc.desc))
+		}
+		repoCall.Unset()
+		repoCall1.Unset()
+	}
+
+	// The validity of the renewed certificates must not grow by the clock
+	// skew on each renewal.
+	repoCall = auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID}, nil)
+	repoCall1 = sdk.On("Thing", mock.Anything, mock.Anything).Return(mgsdk.Thing{ID: thingID, Credentials: mgsdk.Credentials{Secret: thingKey}}, nil)
+	defer repoCall.Unset()
+	defer repoCall1.Unset()
+	rc := c
+	for i := 0; i < 3; i++ {
+		rc, err = svc.RenewCert(context.Background(), token, rc.Serial, "", false)
+		require.Nil(t, err, fmt.Sprintf("unexpected cert renewal error: %s\n", err))
+		assert.Equal(t, ttl, rc.TTL, fmt.Sprintf("renewal %d: expected TTL %s got %s\n", i, ttl, rc.TTL))
+		renewed, err := certs.ReadCert([]byte(rc.ClientCert))
+		require.Nil(t, err, fmt.Sprintf("unexpected cert parsing error: %s\n", err))
+		validFor := renewed.NotAfter.Sub(renewed.NotBefore) - mocks.ClockSkew
+		assert.Equal(t, time.Hour, validFor, fmt.Sprintf("renewal %d: expected validity %s got %s\n", i, time.Hour, validFor))
+	}
+}
+
 func TestRevokeCert(t *testing.T) {
 	svc, auth, sdk := newService(t)
 
@@ -166,7 +378,11 @@ func TestListCerts(t *testing.T) {
 		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID}, nil)
 		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
 		repoCall2 := sdk.On("Thing", mock.Anything, mock.Anything).Return(mgsdk.Thing{ID: thingID, Credentials: mgsdk.Credentials{Secret: thingKey}}, nil)
-		_, err := svc.IssueCert(context.Background(), token, thingID, ttl)
+		certTTL := ttl
+		if i%2 == 0 {
+			certTTL = "2h"
+		}
+		_, err := svc.IssueCert(context.Background(), token, thingID, certTTL)
 		require.Nil(t, err, fmt.Sprintf("unexpected cert creation error: %s\n", err))
 		repoCall.Unset()
 		repoCall1.Unset()
@@ -174,13 +390,15 @@ func TestListCerts(t *testing.T) {
 	}
 
 	cases := []struct {
-		token   string
-		desc    string
-		thingID string
-		offset  uint64
-		limit   uint64
-		size    uint64
-		err     error
+		token     string
+		desc      string
+		thingID   string
+		offset    uint64
+		limit     uint64
+		expAfter  time.Time
+		expBefore time.Time
+		size      uint64
+		err       error
 	}{
 		{
 			desc:    "list all certs with valid token",
@@ -218,11 +436,42 @@ func TestListCerts(t *testing.T) {
 			size:    1,
 			err:     nil,
 		},
+		{
+			desc:      "list certs expiring before TTL",
+			token:     token,
+			thingID:   thingID,
+			offset:    0,
+			limit:     certNum,
+			expBefore: time.Now().Add(time.Hour),
+			size:      certNum / 2,
+			err:       nil,
+		},
+		{
+			desc:     "list certs expiring after TTL",
+			token:    token,
+			thingID:  thingID,
+			offset:   0,
+			limit:    certNum,
+			expAfter: time.Now().Add(time.Hour),
+			size:     certNum / 2,
+			err:      nil,
+		},
+		{
+			desc:      "list certs expiring in the past",
+			token:     token,
+			thingID:   thingID,
+			offset:    0,
+			limit:     certNum,
+			expBefore: time.Now(),
+			size:      0,
+			err:       nil,
+		},
 	}
 
 	for _, tc := range cases {
 		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: validID}, nil)
-		page, err := svc.ListCerts(context.Background(), tc.token, tc.thingID, tc.offset, tc.limit)
+		pm := certs.PageMetadata{Offset: tc.offset, Limit: tc.limit, ExpiresAfter: tc.expAfter, ExpiresBefore: tc.expBefore}
+		page, err := svc.ListCerts(context.Background(), tc.token, tc.thingID, pm)
 		size := uint64(len(page.Certs))
 		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, size))
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
@@ -302,7 +551,8 @@ func TestListSerials(t *testing.T) {
 
 	for _, tc := range cases {
 		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: validID}, nil)
-		page, err := svc.ListSerials(context.Background(), tc.token, tc.thingID, tc.offset, tc.limit)
+		pm := certs.PageMetadata{Offset: tc.offset, Limit: tc.limit}
+		page, err := svc.ListSerials(context.Background(), tc.token, tc.thingID, pm)
 		assert.Equal(t, tc.certs, page.Certs, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.certs, page.Certs))
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 		repoCall.Unset()
@@ -366,3 +616,184 @@ func TestViewCert(t *testing.T) {
 		repoCall.Unset()
 	}
 }
+
+func TestCRL(t *testing.T) {
+	svc, auth, sdk := newService(t)
+
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := sdk.On("Thing", mock.Anything, mock.Anything).Return(mgsdk.Thing{ID: thingID, Credentials: mgsdk.Credentials{Secret: thingKey}}, nil)
+	defer func() {
+		repoCall.Unset()
+		repoCall1.Unset()
+		repoCall2.Unset()
+	}()
+
+	c, err := svc.IssueCert(context.Background(), token, thingID, ttl)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert issuing error: %s\n", err))
+
+	cases := []struct {
+		desc    string
+		revoke  bool
+		entries int
+	}{
+		{
+			desc:    "retrieve CRL without revoked certs",
+			revoke:  false,
+			entries: 0,
+		},
+		{
+			desc:    "retrieve CRL with revoked cert",
+			revoke:  true,
+			entries: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		if tc.revoke {
+			_, err := svc.RevokeCert(context.Background(), token, thingID)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected cert revocation error: %s\n", tc.desc, err))
+		}
+		der, err := svc.CRL(context.Background())
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected CRL error: %s\n", tc.desc, err))
+		crl, err := x509.ParseRevocationList(der)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected CRL parsing error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.entries, len(crl.RevokedCertificateEntries), fmt.Sprintf("%s: expected %d entries got %d\n", tc.desc, tc.entries, len(crl.RevokedCertificateEntries)))
+		if tc.entries > 0 {
+			serial := pki.FormatSerial(crl.RevokedCertificateEntries[0].SerialNumber)
+			assert.Equal(t, c.Serial, serial, fmt.Sprintf("%s: expected serial %s got %s\n", tc.desc, c.Serial, serial))
+		}
+	}
+}
+
+func TestOCSP(t *testing.T) {
+	svc, auth, sdk := newService(t)
+
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := sdk.On("Thing", mock.Anything, mock.Anything).Return(mgsdk.Thing{ID: thingID, Credentials: mgsdk.Credentials{Secret: thingKey}}, nil)
+	defer func() {
+		repoCall.Unset()
+		repoCall1.Unset()
+		repoCall2.Unset()
+	}()
+
+	_, caCert, err := certs.LoadCertificates(caPath, caKeyPath)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert loading error: %s\n", err))
+
+	c, err := svc.IssueCert(context.Background(), token, thingID, ttl)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert issuing error: %s\n", err))
+	cert, err := certs.ReadCert([]byte(c.ClientCert))
+	require.Nil(t, err, fmt.Sprintf("unexpected cert parsing error: %s\n", err))
+	req, err := ocsp.CreateRequest(cert, caCert, nil)
+	require.Nil(t, err, fmt.Sprintf("unexpected OCSP request error: %s\n", err))
+
+	cases := []struct {
+		desc   string
+		revoke bool
+		status int
+	}{
+		{
+			desc:   "check status of valid cert",
+			revoke: false,
+			status: ocsp.Good,
+		},
+		{
+			desc:   "check status of revoked cert",
+			revoke: true,
+			status: ocsp.Revoked,
+		},
+	}
+
+	for _, tc := range cases {
+		if tc.revoke {
+			_, err := svc.RevokeCert(context.Background(), token, thingID)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected cert revocation error: %s\n", tc.desc, err))
+		}
+		der, err := svc.OCSP(context.Background(), req)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected OCSP error: %s\n", tc.desc, err))
+		res, err := ocsp.ParseResponseForCert(der, cert, caCert)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected OCSP response parsing error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.status, res.Status, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, res.Status))
+	}
+}
+
+func TestIdentifyThing(t *testing.T) {
+	svc, auth, sdk := newService(t)
+
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := sdk.On("Thing", mock.Anything, mock.Anything).Return(mgsdk.Thing{ID: thingID, Credentials: mgsdk.Credentials{Secret: thingKey}}, nil)
+	defer func() {
+		repoCall.Unset()
+		repoCall1.Unset()
+		repoCall2.Unset()
+	}()
+
+	c, err := svc.IssueCert(context.Background(), token, thingID, ttl)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert issuing error: %s\n", err))
+	cert, err := certs.ReadCert([]byte(c.ClientCert))
+	require.Nil(t, err, fmt.Sprintf("unexpected cert parsing error: %s\n", err))
+	expired, err := svc.IssueCert(context.Background(), token, thingID, "1ns")
+	require.Nil(t, err, fmt.Sprintf("unexpected cert issuing error: %s\n", err))
+	expiredCert, err := certs.ReadCert([]byte(expired.ClientCert))
+	require.Nil(t, err, fmt.Sprintf("unexpected cert parsing error: %s\n", err))
+
+	// The forged certificate has the serial of the issued one.
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err, fmt.Sprintf("unexpected key generation error: %s\n", err))
+	tmpl := x509.Certificate{
+		SerialNumber: cert.SerialNumber,
+		Subject:      pkix.Name{CommonName: thingKey},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	forged, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert creation error: %s\n", err))
+
+	cases := []struct {
+		desc    string
+		cert    []byte
+		revoke  bool
+		thingID string
+		err     error
+	}{
+		{
+			desc:    "identify thing with valid cert",
+			cert:    cert.Raw,
+			thingID: thingID,
+			err:     nil,
+		},
+		{
+			desc: "identify thing with forged cert",
+			cert: forged,
+			err:  svcerr.ErrAuthentication,
+		},
+		{
+			desc: "identify thing with expired cert",
+			cert: expiredCert.Raw,
+			err:  certs.ErrExpiredCert,
+		},
+		{
+			desc: "identify thing with malformed cert",
+			cert: []byte(invalid),
+			err:  svcerr.ErrMalformedEntity,
+		},
+		{
+			desc:   "identify thing with revoked cert",
+			cert:   cert.Raw,
+			revoke: true,
+			err:    svcerr.ErrAuthentication,
+		},
+	}
+
+	for _, tc := range cases {
+		if tc.revoke {
+			_, err := svc.RevokeCert(context.Background(), token, thingID)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected cert revocation error: %s\n", tc.desc, err))
+		}
+		id, err := svc.IdentifyThing(context.Background(), tc.cert)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.thingID, id, fmt.Sprintf("%s: expected thing ID %s got %s\n", tc.desc, tc.thingID, id))
+	}
+}
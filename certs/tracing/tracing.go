@@ -34,28 +34,55 @@ func (tm *tracingMiddleware) IssueCert(ctx context.Context, token, thingID, ttl
 	return tm.svc.IssueCert(ctx, token, thingID, ttl)
 }
 
+// IssueCertFromCSR traces the "IssueCertFromCSR" operation of the wrapped certs.Service.
+func (tm *tracingMiddleware) IssueCertFromCSR(ctx context.Context, token, thingID, csr, ttl string) (certs.Cert, error) {
+	ctx, span := tm.tracer.Start(ctx, "svc_issue_cert_from_csr", trace.WithAttributes(
+		attribute.String("thing_id", thingID),
+		attribute.String("ttl", ttl),
+	))
+	defer span.End()
+
+	return tm.svc.IssueCertFromCSR(ctx, token, thingID, csr, ttl)
+}
+
+// RenewCert traces the "RenewCert" operation of the wrapped certs.Service.
+func (tm *tracingMiddleware) RenewCert(ctx context.Context, token, serialID, ttl string, reuseKey bool) (certs.Cert, error) {
+	ctx, span := tm.tracer.Start(ctx, "svc_renew_cert", trace.WithAttributes(
+		attribute.String("serial_id", serialID),
+		attribute.String("ttl", ttl),
+		attribute.Bool("reuse_key", reuseKey),
+	))
+	defer span.End()
+
+	return tm.svc.RenewCert(ctx, token, serialID, ttl, reuseKey)
+}
+
 // ListCerts traces the "ListCerts" operation of the wrapped certs.Service.
-func (tm *tracingMiddleware) ListCerts(ctx context.Context, token, thingID string, offset, limit uint64) (certs.Page, error) {
+func (tm *tracingMiddleware) ListCerts(ctx context.Context, token, thingID string, pm certs.PageMetadata) (certs.Page, error) {
 	ctx, span := tm.tracer.Start(ctx, "svc_list_certs", trace.WithAttributes(
 		attribute.String("thing_id", thingID),
-		attribute.Int64("offset", int64(offset)),
-		attribute.Int64("limit", int64(limit)),
+		attribute.Int64("offset", int64(pm.Offset)),
+		attribute.Int64("limit", int64(pm.Limit)),
+		attribute.String("expires_after", pm.ExpiresAfter.String()),
+		attribute.String("expires_before", pm.ExpiresBefore.String()),
 	))
 	defer span.End()
 
-	return tm.svc.ListCerts(ctx, token, thingID, offset, limit)
+	return tm.svc.ListCerts(ctx, token, thingID, pm)
 }
 
 // ListSerials traces the "ListSerials" operation of the wrapped certs.Service.
-func (tm *tracingMiddleware) ListSerials(ctx context.Context, token, thingID string, offset, limit uint64) (certs.Page, error) {
+func (tm *tracingMiddleware) ListSerials(ctx context.Context, token, thingID string, pm certs.PageMetadata) (certs.Page, error) {
 	ctx, span := tm.tracer.Start(ctx, "svc_list_serials", trace.WithAttributes(
 		attribute.String("thing_id", thingID),
-		attribute.Int64("offset", int64(offset)),
-		attribute.Int64("limit", int64(limit)),
+		attribute.Int64("offset", int64(pm.Offset)),
+		attribute.Int64("limit", int64(pm.Limit)),
+		attribute.String("expires_after", pm.ExpiresAfter.String()),
+		attribute.String("expires_before", pm.ExpiresBefore.String()),
 	))
 	defer span.End()
 
-	return tm.svc.ListSerials(ctx, token, thingID, offset, limit)
+	return tm.svc.ListSerials(ctx, token, thingID, pm)
 }
 
 // ViewCert traces the "ViewCert" operation of the wrapped certs.Service.
@@ -77,3 +104,27 @@ func (tm *tracingMiddleware) RevokeCert(ctx context.Context, token, serialID str
 
 	return tm.svc.RevokeCert(ctx, token, serialID)
 }
+
+// CRL traces the "CRL" operation of the wrapped certs.Service.
+func (tm *tracingMiddleware) CRL(ctx context.Context) ([]byte, error) {
+	ctx, span := tm.tracer.Start(ctx, "svc_crl")
+	defer span.End()
+
+	return tm.svc.CRL(ctx)
+}
+
+// OCSP traces the "OCSP" operation of the wrapped certs.Service.
+func (tm *tracingMiddleware) OCSP(ctx context.Context, req []byte) ([]byte, error) {
+	ctx, span := tm.tracer.Start(ctx, "svc_ocsp")
+	defer span.End()
+
+	return tm.svc.OCSP(ctx, req)
+}
+
+// IdentifyThing traces the "IdentifyThing" operation of the wrapped certs.Service.
+func (tm *tracingMiddleware) IdentifyThing(ctx context.Context, cert []byte) (string, error) {
+	ctx, span := tm.tracer.Start(ctx, "svc_identify_thing")
+	defer span.End()
+
+	return tm.svc.IdentifyThing(ctx, cert)
+}
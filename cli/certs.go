@@ -4,6 +4,8 @@
 package cli
 
 import (
+	"os"
+
 	"github.com/spf13/cobra"
 )
 
@@ -55,7 +57,8 @@ var cmdCerts = []cobra.Command{
 
 // NewCertsCmd returns certificate command.
 func NewCertsCmd() *cobra.Command {
-	var ttl string
+	var ttl, csrTTL, renewTTL string
+	var reuseKey bool
 
 	issueCmd := cobra.Command{
 		Use:   "issue <thing_id> <user_auth_token> [--ttl=8760h]",
@@ -80,13 +83,62 @@ func NewCertsCmd() *cobra.Command {
 
 	issueCmd.Flags().StringVar(&ttl, "ttl", "8760h", "certificate time to live in duration")
 
+	issueCSRCmd := cobra.Command{
+		Use:   "issue-csr <thing_id> <csr_path> <user_auth_token> [--ttl=8760h]",
+		Short: "Issue certificate from CSR",
+		Long:  `Issues new certificate for a thing by signing the PEM encoded certificate signing request, so the private key never leaves the device`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 3 {
+				logUsage(cmd.Use)
+				return
+			}
+
+			csr, err := os.ReadFile(args[1])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			c, err := sdk.IssueCertFromCSR(args[0], string(csr), csrTTL, args[2])
+			if err != nil {
+				logError(err)
+				return
+			}
+			logJSON(c)
+		},
+	}
+
+	issueCSRCmd.Flags().StringVar(&csrTTL, "ttl", "8760h", "certificate time to live in duration")
+
+	renewCmd := cobra.Command{
+		Use:   "renew <cert_serial> <user_auth_token> [--ttl=8760h] [--reuse-key]",
+		Short: "Renew certificate",
+		Long:  `Issues new certificate for the thing of the certificate, which stays valid until it expires`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Use)
+				return
+			}
+
+			c, err := sdk.RenewCert(args[0], renewTTL, reuseKey, args[1])
+			if err != nil {
+				logError(err)
+				return
+			}
+			logJSON(c)
+		},
+	}
+
+	renewCmd.Flags().StringVar(&renewTTL, "ttl", "", "certificate time to live in duration, defaults to the renewed certificate validity")
+	renewCmd.Flags().BoolVar(&reuseKey, "reuse-key", false, "issue the certificate for the renewed certificate key")
+
 	cmd := cobra.Command{
-		Use:   "certs [issue | get | revoke ]",
+		Use:   "certs [issue | issue-csr | get | renew | revoke ]",
 		Short: "Certificates management",
-		Long:  `Certificates management: issue, get or revoke certificates for things"`,
+		Long:  `Certificates management: issue, get, renew or revoke certificates for things"`,
 	}
 
-	cmdCerts = append(cmdCerts, issueCmd)
+	cmdCerts = append(cmdCerts, issueCmd, issueCSRCmd, renewCmd)
 
 	for i := range cmdCerts {
 		cmd.AddCommand(&cmdCerts[i])
@@ -24,6 +24,7 @@ type remotes struct {
 	HTTPAdapterURL  string `toml:"http_adapter_url"`
 	BootstrapURL    string `toml:"bootstrap_url"`
 	CertsURL        string `toml:"certs_url"`
+	ConsumerURL     string `toml:"consumer_url"`
 	TLSVerification bool   `toml:"tls_verification"`
 }
 
@@ -91,6 +92,7 @@ func ParseConfig(sdkConf mgxsdk.Config) (mgxsdk.Config, error) {
 				HTTPAdapterURL:  "http://localhost/http:9016",
 				BootstrapURL:    "http://localhost",
 				CertsURL:        "https://localhost:9019",
+				ConsumerURL:     "http://localhost:9010",
 				TLSVerification: false,
 			},
 		}
@@ -144,6 +146,9 @@ func ParseConfig(sdkConf mgxsdk.Config) (mgxsdk.Config, error) {
 	sdkConf.HTTPAdapterURL = config.Remotes.HTTPAdapterURL
 	sdkConf.BootstrapURL = config.Remotes.BootstrapURL
 	sdkConf.CertsURL = config.Remotes.CertsURL
+	if config.Remotes.ConsumerURL != "" {
+		sdkConf.ConsumerURL = config.Remotes.ConsumerURL
+	}
 
 	return sdkConf, nil
 }
@@ -196,6 +201,7 @@ func setConfigValue(key, value string) error {
 		"http_adapter_url": &config.Remotes.HTTPAdapterURL,
 		"bootstrap_url":    &config.Remotes.BootstrapURL,
 		"certs_url":        &config.Remotes.CertsURL,
+		"consumer_url":     &config.Remotes.ConsumerURL,
 		"tls_verification": &config.Remotes.TLSVerification,
 		"offset":           &config.Filter.Offset,
 		"limit":            &config.Filter.Limit,
@@ -0,0 +1,91 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import "github.com/spf13/cobra"
+
+var cmdDeadLetters = []cobra.Command{
+	{
+		Use:   "get [all | <dead_letter_id>]",
+		Short: "Get dead letters",
+		Long: `Get dead letters of the consumer.
+				all - lists all dead letters
+				<dead_letter_id> - view dead letter of <dead_letter_id>`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				logUsage(cmd.Use)
+				return
+			}
+			if args[0] == all {
+				dp, err := sdk.ListDeadLetters()
+				if err != nil {
+					logError(err)
+					return
+				}
+				logJSON(dp)
+				return
+			}
+
+			dl, err := sdk.ViewDeadLetter(args[0])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			logJSON(dl)
+		},
+	},
+	{
+		Use:   "replay <dead_letter_id>",
+		Short: "Replay dead letter",
+		Long:  `Consumes the dead letter message once again and removes the dead letter on success`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				logUsage(cmd.Use)
+				return
+			}
+
+			dl, err := sdk.ReplayDeadLetter(args[0])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			logJSON(dl)
+		},
+	},
+	{
+		Use:   "remove <dead_letter_id>",
+		Short: "Remove dead letter",
+		Long:  `Removes the dead letter without consuming its message`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				logUsage(cmd.Use)
+				return
+			}
+
+			if err := sdk.DeleteDeadLetter(args[0]); err != nil {
+				logError(err)
+				return
+			}
+
+			logOK()
+		},
+	},
+}
+
+// NewDeadLettersCmd returns dead letters command.
+func NewDeadLettersCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "dead-letters [get | replay | remove]",
+		Short: "Consumer dead-letter queue management",
+		Long:  `Consumer dead-letter queue management: get, replay, or remove dead letters`,
+	}
+
+	for i := range cmdDeadLetters {
+		cmd.AddCommand(&cmdDeadLetters[i])
+	}
+
+	return &cmd
+}
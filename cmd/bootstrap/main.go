@@ -54,6 +54,7 @@ type config struct {
 	EncKey         string  `env:"MG_BOOTSTRAP_ENCRYPT_KEY"      envDefault:"12345678910111213141516171819202"`
 	ESConsumerName string  `env:"MG_BOOTSTRAP_EVENT_CONSUMER"   envDefault:"bootstrap"`
 	ThingsURL      string  `env:"MG_THINGS_URL"                 envDefault:"http://localhost:9000"`
+	CertsURL       string  `env:"MG_CERTS_URL"                  envDefault:"http://localhost:9019"`
 	JaegerURL      url.URL `env:"MG_JAEGER_URL"                 envDefault:"http://localhost:14268/api/traces"`
 	SendTelemetry  bool    `env:"MG_SEND_TELEMETRY"             envDefault:"true"`
 	InstanceID     string  `env:"MG_BOOTSTRAP_INSTANCE_ID"      envDefault:""`
@@ -175,6 +176,7 @@ func newService(ctx context.Context, authClient magistrala.AuthServiceClient, db
 
 	config := mgsdk.Config{
 		ThingsURL: cfg.ThingsURL,
+		CertsURL:  cfg.CertsURL,
 	}
 
 	sdk := mgsdk.NewSDK(config)
@@ -16,6 +16,7 @@ import (
 	"github.com/absmach/magistrala"
 	"github.com/absmach/magistrala/consumers"
 	consumertracing "github.com/absmach/magistrala/consumers/tracing"
+	"github.com/absmach/magistrala/consumers/writers"
 	"github.com/absmach/magistrala/consumers/writers/api"
 	"github.com/absmach/magistrala/consumers/writers/cassandra"
 	"github.com/absmach/magistrala/internal"
@@ -36,6 +37,7 @@ const (
 	svcName        = "cassandra-writer"
 	envPrefixDB    = "MG_CASSANDRA_"
 	envPrefixHTTP  = "MG_CASSANDRA_WRITER_HTTP_"
+	envPrefixBatch = "MG_CASSANDRA_WRITER_BATCH_"
 	defSvcHTTPPort = "9004"
 )
 
@@ -82,6 +84,13 @@ func main() {
 		return
 	}
 
+	batchConfig := writers.BatchConfig{}
+	if err := env.ParseWithOptions(&batchConfig, env.Options{Prefix: envPrefixBatch}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s batch configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+
 	// Create new to cassandra client
 	csdSession, err := cassandraclient.SetupDB(envPrefixDB, cassandra.Table)
 	if err != nil {
@@ -106,6 +115,7 @@ func main() {
 
 	// Create new cassandra-writer repo
 	repo := newService(csdSession, logger)
+	repo = writers.NewBatch(repo, batchConfig)
 	repo = consumertracing.NewBlocking(tracer, repo, httpServerConfig)
 
 	// Create new pub sub broker
@@ -119,13 +129,14 @@ func main() {
 	pubSub = brokerstracing.NewPubSub(httpServerConfig, tracer, pubSub)
 
 	// Start new consumer
-	if err := consumers.Start(ctx, svcName, pubSub, repo, cfg.ConfigPath, logger); err != nil {
+	dlq, err := consumers.Start(ctx, svcName, pubSub, repo, cfg.ConfigPath, logger)
+	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to create Cassandra writer: %s", err))
 		exitCode = 1
 		return
 	}
 
-	hs := httpserver.New(ctx, cancel, svcName, httpServerConfig, api.MakeHandler(svcName, cfg.InstanceID), logger)
+	hs := httpserver.New(ctx, cancel, svcName, httpServerConfig, api.MakeHandler(dlq, logger, svcName, cfg.InstanceID), logger)
 
 	if cfg.SendTelemetry {
 		chc := chclient.New(svcName, magistrala.Version, logger, cancel)
@@ -11,12 +11,14 @@ import (
 	"log/slog"
 	"net/url"
 	"os"
+	"time"
 
 	chclient "github.com/absmach/callhome/pkg/client"
 	"github.com/absmach/magistrala"
 	"github.com/absmach/magistrala/certs"
 	"github.com/absmach/magistrala/certs/api"
-	vault "github.com/absmach/magistrala/certs/pki"
+	"github.com/absmach/magistrala/certs/events"
+	"github.com/absmach/magistrala/certs/pki"
 	certspg "github.com/absmach/magistrala/certs/postgres"
 	"github.com/absmach/magistrala/certs/tracing"
 	"github.com/absmach/magistrala/internal"
@@ -27,6 +29,8 @@ import (
 	httpserver "github.com/absmach/magistrala/internal/server/http"
 	mglog "github.com/absmach/magistrala/logger"
 	"github.com/absmach/magistrala/pkg/auth"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/pkg/events/store"
 	mgsdk "github.com/absmach/magistrala/pkg/sdk/go"
 	"github.com/absmach/magistrala/pkg/uuid"
 	"github.com/caarlos0/env/v10"
@@ -42,6 +46,10 @@ const (
 	envPrefixAuth  = "MG_AUTH_GRPC_"
 	defDB          = "certs"
 	defSvcHTTPPort = "9019"
+	streamID       = "magistrala.certs"
+
+	vaultAgent = "vault"
+	localAgent = "local"
 )
 
 type config struct {
@@ -51,10 +59,23 @@ type config struct {
 	SendTelemetry bool    `env:"MG_SEND_TELEMETRY"         envDefault:"true"`
 	InstanceID    string  `env:"MG_CERTS_INSTANCE_ID"      envDefault:""`
 	TraceRatio    float64 `env:"MG_JAEGER_TRACE_RATIO"     envDefault:"1.0"`
+	ESURL         string  `env:"MG_ES_URL"                 envDefault:"nats://localhost:4222"`
+
+	// PKI agent used to issue certificates, either vault or local
+	PkiAgent string `env:"MG_CERTS_PKI_AGENT" envDefault:"vault"`
+
+	// Revocation status URLs embedded into the issued certificates
+	CRLURL  string `env:"MG_CERTS_CRL_URL"  envDefault:""`
+	OCSPURL string `env:"MG_CERTS_OCSP_URL" envDefault:""`
+
+	// Certificates expiry notifications, disabled if the interval is zero
+	ExpiryScanInterval time.Duration `env:"MG_CERTS_EXPIRY_SCAN_INTERVAL" envDefault:"1h"`
+	ExpiryWindow       time.Duration `env:"MG_CERTS_EXPIRY_WINDOW"        envDefault:"720h"`
 
 	// Sign and issue certificates without 3rd party PKI
-	SignCAPath    string `env:"MG_CERTS_SIGN_CA_PATH"        envDefault:"ca.crt"`
-	SignCAKeyPath string `env:"MG_CERTS_SIGN_CA_KEY_PATH"    envDefault:"ca.key"`
+	SignCAPath    string        `env:"MG_CERTS_SIGN_CA_PATH"        envDefault:"ca.crt"`
+	SignCAKeyPath string        `env:"MG_CERTS_SIGN_CA_KEY_PATH"    envDefault:"ca.key"`
+	SignTimeout   time.Duration `env:"MG_CERTS_SIGN_TIMEOUT"        envDefault:"5s"`
 
 	// 3rd party PKI API access settings
 	PkiHost  string `env:"MG_CERTS_VAULT_HOST"    envDefault:""`
@@ -88,19 +109,6 @@ func main() {
 		}
 	}
 
-	if cfg.PkiHost == "" {
-		logger.Error("No host specified for PKI engine")
-		exitCode = 1
-		return
-	}
-
-	pkiclient, err := vault.NewVaultClient(cfg.PkiToken, cfg.PkiHost, cfg.PkiPath, cfg.PkiRole)
-	if err != nil {
-		logger.Error("failed to configure client for PKI engine")
-		exitCode = 1
-		return
-	}
-
 	dbConfig := pgclient.Config{Name: defDB}
 	if err := env.ParseWithOptions(&dbConfig, env.Options{Prefix: envPrefixDB}); err != nil {
 		logger.Error(err.Error())
@@ -143,7 +151,31 @@ func main() {
 	}()
 	tracer := tp.Tracer(svcName)
 
-	svc := newService(authClient, db, tracer, logger, cfg, dbConfig, pkiclient)
+	pkiclient, err := newPKIAgent(cfg, db, dbConfig, tracer)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to configure client for PKI engine: %s", err))
+		exitCode = 1
+		return
+	}
+
+	database := postgres.NewDatabase(db, dbConfig, tracer)
+	certsRepo := certspg.NewRepository(database, logger)
+	svc := newService(authClient, certsRepo, tracer, logger, cfg, pkiclient)
+
+	if cfg.ExpiryScanInterval > 0 {
+		publisher, err := store.NewPublisher(ctx, cfg.ESURL, streamID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to create %s event store publisher: %s", svcName, err))
+			exitCode = 1
+			return
+		}
+		defer publisher.Close()
+
+		scanner := events.NewExpiryScanner(certsRepo, publisher, cfg.ExpiryWindow, logger)
+		g.Go(func() error {
+			return scanner.Run(ctx, cfg.ExpiryScanInterval)
+		})
+	}
 
 	httpServerConfig := server.Config{Port: defSvcHTTPPort}
 	if err := env.ParseWithOptions(&httpServerConfig, env.Options{Prefix: envPrefixHTTP}); err != nil {
@@ -171,9 +203,27 @@ func main() {
 	}
 }
 
-func newService(authClient magistrala.AuthServiceClient, db *sqlx.DB, tracer trace.Tracer, logger *slog.Logger, cfg config, dbConfig pgclient.Config, pkiAgent vault.Agent) certs.Service {
-	database := postgres.NewDatabase(db, dbConfig, tracer)
-	certsRepo := certspg.NewRepository(database, logger)
+func newPKIAgent(cfg config, db *sqlx.DB, dbConfig pgclient.Config, tracer trace.Tracer) (pki.Agent, error) {
+	urls := pki.URLs{CRL: cfg.CRLURL, OCSP: cfg.OCSPURL}
+	switch cfg.PkiAgent {
+	case vaultAgent:
+		if cfg.PkiHost == "" {
+			return nil, errors.New("no host specified for PKI engine")
+		}
+		return pki.NewVaultClient(cfg.PkiToken, cfg.PkiHost, cfg.PkiPath, cfg.PkiRole, urls)
+	case localAgent:
+		tlsCert, caCert, err := certs.LoadCertificates(cfg.SignCAPath, cfg.SignCAKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		database := postgres.NewDatabase(db, dbConfig, tracer)
+		return pki.NewLocalAgent(tlsCert, caCert, certspg.NewPKIRepository(database), urls, cfg.SignTimeout)
+	default:
+		return nil, fmt.Errorf("unknown PKI agent %s", cfg.PkiAgent)
+	}
+}
+
+func newService(authClient magistrala.AuthServiceClient, certsRepo certs.Repository, tracer trace.Tracer, logger *slog.Logger, cfg config, pkiAgent pki.Agent) certs.Service {
 	config := mgsdk.Config{
 		ThingsURL: cfg.ThingsURL,
 	}
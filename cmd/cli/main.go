@@ -21,6 +21,7 @@ const (
 	defDomainsURL     string = defURL + ":8189"
 	defCertsURL       string = defURL + ":9019"
 	defInvitationsURL string = defURL + ":9020"
+	defConsumerURL    string = defURL + ":9010"
 )
 
 func main() {
@@ -34,6 +35,7 @@ func main() {
 		CertsURL:        defCertsURL,
 		DomainsURL:      defDomainsURL,
 		InvitationsURL:  defInvitationsURL,
+		ConsumerURL:     defConsumerURL,
 		MsgContentType:  sdk.ContentType(msgContentType),
 		TLSVerification: false,
 		HostURL:         defURL,
@@ -68,6 +70,7 @@ func main() {
 	subscriptionsCmd := cli.NewSubscriptionCmd()
 	configCmd := cli.NewConfigCmd()
 	invitationsCmd := cli.NewInvitationsCmd()
+	deadLettersCmd := cli.NewDeadLettersCmd()
 
 	// Root Commands
 	rootCmd.AddCommand(healthCmd)
@@ -83,6 +86,7 @@ func main() {
 	rootCmd.AddCommand(subscriptionsCmd)
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(invitationsCmd)
+	rootCmd.AddCommand(deadLettersCmd)
 
 	// Root Flags
 	rootCmd.PersistentFlags().StringVarP(
@@ -149,6 +153,14 @@ func main() {
 		"Inivitations URL",
 	)
 
+	rootCmd.PersistentFlags().StringVarP(
+		&sdkConf.ConsumerURL,
+		"consumer-url",
+		"D",
+		sdkConf.ConsumerURL,
+		"Consumer URL",
+	)
+
 	rootCmd.PersistentFlags().StringVarP(
 		&sdkConf.HostURL,
 		"host-url",
@@ -23,6 +23,7 @@ import (
 	httpserver "github.com/absmach/magistrala/internal/server/http"
 	mglog "github.com/absmach/magistrala/logger"
 	"github.com/absmach/magistrala/pkg/auth"
+	"github.com/absmach/magistrala/pkg/certauth"
 	"github.com/absmach/magistrala/pkg/messaging"
 	"github.com/absmach/magistrala/pkg/messaging/brokers"
 	brokerstracing "github.com/absmach/magistrala/pkg/messaging/brokers/tracing"
@@ -36,12 +37,13 @@ import (
 )
 
 const (
-	svcName        = "http_adapter"
-	envPrefix      = "MG_HTTP_ADAPTER_"
-	envPrefixAuthz = "MG_THINGS_AUTH_GRPC_"
-	defSvcHTTPPort = "80"
-	targetHTTPPort = "81"
-	targetHTTPHost = "http://localhost"
+	svcName           = "http_adapter"
+	envPrefix         = "MG_HTTP_ADAPTER_"
+	envPrefixAuthz    = "MG_THINGS_AUTH_GRPC_"
+	envPrefixCertAuth = "MG_CERTS_AUTH_"
+	defSvcHTTPPort    = "80"
+	targetHTTPPort    = "81"
+	targetHTTPHost    = "http://localhost"
 )
 
 type config struct {
@@ -102,6 +104,23 @@ func main() {
 
 	logger.Info("Successfully connected to things grpc server " + authHandler.Secure())
 
+	certAuthConfig := certauth.Config{}
+	if err := env.ParseWithOptions(&certAuthConfig, env.Options{Prefix: envPrefixCertAuth}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s cert auth configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+	var certAuth certauth.Authenticator
+	if certAuthConfig.CAPath != "" {
+		certAuth, err = certauth.Setup(certAuthConfig)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to setup %s cert auth : %s", svcName, err))
+			exitCode = 1
+			return
+		}
+		logger.Info("Client certificate authentication enabled with CA " + certAuthConfig.CAPath)
+	}
+
 	tp, err := jaegerclient.NewProvider(ctx, svcName, cfg.JaegerURL, cfg.InstanceID, cfg.TraceRatio)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to init Jaeger: %s", err))
@@ -124,7 +143,7 @@ func main() {
 	defer pub.Close()
 	pub = brokerstracing.NewPublisher(httpServerConfig, tracer, pub)
 
-	svc := newService(pub, authClient, logger, tracer)
+	svc := newService(pub, authClient, certAuth, logger, tracer)
 	targetServerCfg := server.Config{Port: targetHTTPPort}
 
 	hs := httpserver.New(ctx, cancel, svcName, targetServerCfg, api.MakeHandler(cfg.InstanceID), logger)
@@ -139,7 +158,7 @@ func main() {
 	})
 
 	g.Go(func() error {
-		return proxyHTTP(ctx, httpServerConfig, logger, svc)
+		return proxyHTTP(ctx, httpServerConfig, certAuthConfig, logger, svc)
 	})
 
 	g.Go(func() error {
@@ -151,8 +170,8 @@ func main() {
 	}
 }
 
-func newService(pub messaging.Publisher, tc magistrala.AuthzServiceClient, logger *slog.Logger, tracer trace.Tracer) session.Handler {
-	svc := adapter.NewHandler(pub, logger, tc)
+func newService(pub messaging.Publisher, tc magistrala.AuthzServiceClient, certAuth certauth.Authenticator, logger *slog.Logger, tracer trace.Tracer) session.Handler {
+	svc := adapter.NewHandler(pub, logger, tc, certAuth)
 	svc = handler.NewTracing(tracer, svc)
 	svc = handler.LoggingMiddleware(svc, logger)
 	counter, latency := internal.MakeMetrics(svcName, "api")
@@ -160,25 +179,41 @@ func newService(pub messaging.Publisher, tc magistrala.AuthzServiceClient, logge
 	return svc
 }
 
-func proxyHTTP(ctx context.Context, cfg server.Config, logger *slog.Logger, sessionHandler session.Handler) error {
+func proxyHTTP(ctx context.Context, cfg server.Config, certAuthConfig certauth.Config, logger *slog.Logger, sessionHandler session.Handler) error {
 	address := fmt.Sprintf("%s:%s", "", cfg.Port)
 	target := fmt.Sprintf("%s:%s", targetHTTPHost, targetHTTPPort)
 	mp, err := mproxy.NewProxy(address, target, sessionHandler, logger)
 	if err != nil {
 		return err
 	}
-	http.HandleFunc("/", mp.Handler)
+	h, err := certauth.Handler(http.HandlerFunc(mp.Handler), certAuthConfig)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Addr:    address,
+		Handler: h,
+	}
 
 	errCh := make(chan error)
 	switch {
+	case (cfg.CertFile != "" || cfg.KeyFile != "") && certAuthConfig.CAPath != "":
+		srv.TLSConfig, err = certauth.TLSConfig(certAuthConfig.CAPath, cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return err
+		}
+		go func() {
+			errCh <- srv.ListenAndServeTLS("", "")
+		}()
+		logger.Info(fmt.Sprintf("%s service https server listening at %s:%s with TLS cert %s and key %s verifying client certificates", svcName, cfg.Host, cfg.Port, cfg.CertFile, cfg.KeyFile))
 	case cfg.CertFile != "" || cfg.KeyFile != "":
 		go func() {
-			errCh <- mp.ListenTLS(cfg.CertFile, cfg.KeyFile)
+			errCh <- srv.ListenAndServeTLS(cfg.CertFile, cfg.KeyFile)
 		}()
 		logger.Info(fmt.Sprintf("%s service https server listening at %s:%s with TLS cert %s and key %s", svcName, cfg.Host, cfg.Port, cfg.CertFile, cfg.KeyFile))
 	default:
 		go func() {
-			errCh <- mp.Listen()
+			errCh <- srv.ListenAndServe()
 		}()
 		logger.Info(fmt.Sprintf("%s service http server listening at %s:%s without TLS", svcName, cfg.Host, cfg.Port))
 	}
@@ -134,13 +134,14 @@ func main() {
 		}
 	}(logger)
 
-	if err := consumers.Start(ctx, svcName, pubSub, repo, cfg.ConfigPath, logger); err != nil {
+	dlq, err := consumers.Start(ctx, svcName, pubSub, repo, cfg.ConfigPath, logger)
+	if err != nil {
 		logger.Error(fmt.Sprintf("failed to start InfluxDB writer: %s", err))
 		exitCode = 1
 		return
 	}
 
-	hs := httpserver.New(ctx, cancel, svcName, httpServerConfig, api.MakeHandler(svcName, cfg.InstanceID), logger)
+	hs := httpserver.New(ctx, cancel, svcName, httpServerConfig, api.MakeHandler(dlq, logger, svcName, cfg.InstanceID), logger)
 
 	if cfg.SendTelemetry {
 		chc := chclient.New(svcName, magistrala.Version, logger, cancel)
@@ -44,6 +44,7 @@ const (
 	thingsRMPrefix   = "thing"
 	channelsRMPrefix = "channel"
 	connsRMPrefix    = "connection"
+	codecsPrefix     = "codec"
 	thingsStream     = "magistrala.things"
 )
 
@@ -54,6 +55,10 @@ type config struct {
 	LoraMsgPass    string        `env:"MG_LORA_ADAPTER_MESSAGES_PASS"       envDefault:""`
 	LoraMsgTopic   string        `env:"MG_LORA_ADAPTER_MESSAGES_TOPIC"      envDefault:"application/+/device/+/event/up"`
 	LoraMsgTimeout time.Duration `env:"MG_LORA_ADAPTER_MESSAGES_TIMEOUT"    envDefault:"30s"`
+	LoraEvtTopics  []string      `env:"MG_LORA_ADAPTER_EVENTS_TOPICS"       envDefault:"application/+/device/+/event/ack,application/+/device/+/event/error"`
+	DownFPort      int           `env:"MG_LORA_ADAPTER_DOWNLINK_FPORT"      envDefault:"1"`
+	DownConfirmed  bool          `env:"MG_LORA_ADAPTER_DOWNLINK_CONFIRMED"  envDefault:"false"`
+	RadioMetadata  string        `env:"MG_LORA_ADAPTER_RADIO_METADATA"      envDefault:"off"`
 	ESConsumerName string        `env:"MG_LORA_ADAPTER_EVENT_CONSUMER"      envDefault:"lora-adapter"`
 	BrokerURL      string        `env:"MG_MESSAGE_BROKER_URL"               envDefault:"nats://localhost:4222"`
 	JaegerURL      url.URL       `env:"MG_JAEGER_URL"                       envDefault:"http://localhost:14268/api/traces"`
@@ -81,6 +86,14 @@ func main() {
 	var exitCode int
 	defer mglog.ExitWithError(&exitCode)
 
+	switch cfg.RadioMetadata {
+	case lora.RadioOff, lora.RadioRecords, lora.RadioSubtopic:
+	default:
+		logger.Error(fmt.Sprintf("invalid radio metadata mode %q", cfg.RadioMetadata))
+		exitCode = 1
+		return
+	}
+
 	if cfg.InstanceID == "" {
 		if cfg.InstanceID, err = uuid.New().ID(); err != nil {
 			logger.Error(fmt.Sprintf("failed to generate instanceID: %s", err))
@@ -117,16 +130,14 @@ func main() {
 	}()
 	tracer := tp.Tracer(svcName)
 
-	pub, err := brokers.NewPublisher(ctx, cfg.BrokerURL)
+	pubSub, err := brokers.NewPubSub(ctx, cfg.BrokerURL, logger)
 	if err != nil {
 		logger.Error(fmt.Sprintf("failed to connect to message broker: %s", err))
 		exitCode = 1
 		return
 	}
-	defer pub.Close()
-	pub = brokerstracing.NewPublisher(httpServerConfig, tracer, pub)
-
-	svc := newService(pub, rmConn, thingsRMPrefix, channelsRMPrefix, connsRMPrefix, logger)
+	defer pubSub.Close()
+	pubSub = brokerstracing.NewPubSub(httpServerConfig, tracer, pubSub)
 
 	mqttConn, err := connectToMQTTBroker(cfg.LoraMsgURL, cfg.LoraMsgUser, cfg.LoraMsgPass, cfg.LoraMsgTimeout, logger)
 	if err != nil {
@@ -135,12 +146,31 @@ func main() {
 		return
 	}
 
-	if err = subscribeToLoRaBroker(svc, mqttConn, cfg.LoraMsgTimeout, cfg.LoraMsgTopic, logger); err != nil {
+	downCfg := lora.DownlinkConfig{
+		FPort:     cfg.DownFPort,
+		Confirmed: cfg.DownConfirmed,
+	}
+	downlinks := mqtt.NewPublisher(mqttConn, cfg.LoraMsgTimeout)
+
+	svc := newService(pubSub, downlinks, downCfg, cfg.RadioMetadata, rmConn, thingsRMPrefix, channelsRMPrefix, connsRMPrefix, logger)
+
+	if err = subscribeToLoRaBroker(svc, mqttConn, cfg.LoraMsgTimeout, cfg.LoraMsgTopic, cfg.LoraEvtTopics, logger); err != nil {
 		logger.Error(fmt.Sprintf("failed to subscribe to Lora MQTT broker: %s", err))
 		exitCode = 1
 		return
 	}
 
+	subCfg := messaging.SubscriberConfig{
+		ID:      svcName,
+		Topic:   brokers.SubjectAllChannels,
+		Handler: lora.NewDownlinkHandler(ctx, svc),
+	}
+	if err = pubSub.Subscribe(ctx, subCfg); err != nil {
+		logger.Error(fmt.Sprintf("failed to subscribe to message broker: %s", err))
+		exitCode = 1
+		return
+	}
+
 	if err = subscribeToThingsES(ctx, svc, cfg, logger); err != nil {
 		logger.Error(fmt.Sprintf("failed to subscribe to things event store: %s", err))
 		exitCode = 1
@@ -188,12 +218,15 @@ func connectToMQTTBroker(burl, user, password string, timeout time.Duration, log
 	return client, nil
 }
 
-func subscribeToLoRaBroker(svc lora.Service, mc mqttpaho.Client, timeout time.Duration, topic string, logger *slog.Logger) error {
+func subscribeToLoRaBroker(svc lora.Service, mc mqttpaho.Client, timeout time.Duration, topic string, evtTopics []string, logger *slog.Logger) error {
 	mqttBroker := mqtt.NewBroker(svc, mc, timeout, logger)
 	logger.Info("Subscribed to Lora MQTT broker")
 	if err := mqttBroker.Subscribe(topic); err != nil {
 		return fmt.Errorf("failed to subscribe to Lora MQTT broker: %s", err)
 	}
+	if err := mqttBroker.SubscribeEvents(evtTopics...); err != nil {
+		return fmt.Errorf("failed to subscribe to Lora MQTT broker events: %s", err)
+	}
 	return nil
 }
 
@@ -215,12 +248,13 @@ func newRouteMapRepository(client *redis.Client, prefix string, logger *slog.Log
 	return events.NewRouteMapRepository(client, prefix)
 }
 
-func newService(pub messaging.Publisher, rmConn *redis.Client, thingsRMPrefix, channelsRMPrefix, connsRMPrefix string, logger *slog.Logger) lora.Service {
+func newService(pub messaging.Publisher, downlinks lora.DownlinkPublisher, downCfg lora.DownlinkConfig, radio string, rmConn *redis.Client, thingsRMPrefix, channelsRMPrefix, connsRMPrefix string, logger *slog.Logger) lora.Service {
 	thingsRM := newRouteMapRepository(rmConn, thingsRMPrefix, logger)
 	chansRM := newRouteMapRepository(rmConn, channelsRMPrefix, logger)
 	connsRM := newRouteMapRepository(rmConn, connsRMPrefix, logger)
+	codecs := events.NewCodecRepository(rmConn, codecsPrefix)
 
-	svc := lora.New(pub, thingsRM, chansRM, connsRM)
+	svc := lora.New(pub, downlinks, thingsRM, chansRM, connsRM, codecs, downCfg, radio)
 	svc = api.LoggingMiddleware(svc, logger)
 	counter, latency := internal.MakeMetrics("lora_adapter", "api")
 	svc = api.MetricsMiddleware(svc, counter, latency)
@@ -16,6 +16,7 @@ import (
 	"github.com/absmach/magistrala"
 	"github.com/absmach/magistrala/consumers"
 	consumertracing "github.com/absmach/magistrala/consumers/tracing"
+	"github.com/absmach/magistrala/consumers/writers"
 	"github.com/absmach/magistrala/consumers/writers/api"
 	"github.com/absmach/magistrala/consumers/writers/mongodb"
 	"github.com/absmach/magistrala/internal"
@@ -36,6 +37,7 @@ const (
 	svcName        = "mongodb-writer"
 	envPrefixDB    = "MG_MONGO_"
 	envPrefixHTTP  = "MG_MONGO_WRITER_HTTP_"
+	envPrefixBatch = "MG_MONGO_WRITER_BATCH_"
 	defSvcHTTPPort = "9008"
 )
 
@@ -81,6 +83,13 @@ func main() {
 		return
 	}
 
+	batchConfig := writers.BatchConfig{}
+	if err := env.ParseWithOptions(&batchConfig, env.Options{Prefix: envPrefixBatch}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s batch configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+
 	tp, err := jaegerclient.NewProvider(ctx, svcName, cfg.JaegerURL, cfg.InstanceID, cfg.TraceRatio)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to init Jaeger: %s", err))
@@ -111,15 +120,17 @@ func main() {
 	}
 
 	repo := newService(db, logger)
+	repo = writers.NewBatch(repo, batchConfig)
 	repo = consumertracing.NewBlocking(tracer, repo, httpServerConfig)
 
-	if err := consumers.Start(ctx, svcName, pubSub, repo, cfg.ConfigPath, logger); err != nil {
+	dlq, err := consumers.Start(ctx, svcName, pubSub, repo, cfg.ConfigPath, logger)
+	if err != nil {
 		logger.Error(fmt.Sprintf("failed to start MongoDB writer: %s", err))
 		exitCode = 1
 		return
 	}
 
-	hs := httpserver.New(ctx, cancel, svcName, httpServerConfig, api.MakeHandler(svcName, cfg.InstanceID), logger)
+	hs := httpserver.New(ctx, cancel, svcName, httpServerConfig, api.MakeHandler(dlq, logger, svcName, cfg.InstanceID), logger)
 
 	if cfg.SendTelemetry {
 		chc := chclient.New(svcName, magistrala.Version, logger, cancel)
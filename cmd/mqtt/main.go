@@ -6,6 +6,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"log"
@@ -26,6 +27,7 @@ import (
 	"github.com/absmach/magistrala/mqtt/events"
 	mqtttracing "github.com/absmach/magistrala/mqtt/tracing"
 	"github.com/absmach/magistrala/pkg/auth"
+	"github.com/absmach/magistrala/pkg/certauth"
 	"github.com/absmach/magistrala/pkg/errors"
 	"github.com/absmach/magistrala/pkg/messaging/brokers"
 	brokerstracing "github.com/absmach/magistrala/pkg/messaging/brokers/tracing"
@@ -41,13 +43,17 @@ import (
 )
 
 const (
-	svcName        = "mqtt"
-	envPrefixAuthz = "MG_THINGS_AUTH_GRPC_"
+	svcName           = "mqtt"
+	envPrefixAuthz    = "MG_THINGS_AUTH_GRPC_"
+	envPrefixCertAuth = "MG_CERTS_AUTH_"
 )
 
 type config struct {
 	LogLevel              string        `env:"MG_MQTT_ADAPTER_LOG_LEVEL"                    envDefault:"info"`
 	MQTTPort              string        `env:"MG_MQTT_ADAPTER_MQTT_PORT"                    envDefault:"1883"`
+	MQTTSPort             string        `env:"MG_MQTT_ADAPTER_MQTTS_PORT"                   envDefault:"8883"`
+	ServerCert            string        `env:"MG_MQTT_ADAPTER_SERVER_CERT"                  envDefault:""`
+	ServerKey             string        `env:"MG_MQTT_ADAPTER_SERVER_KEY"                   envDefault:""`
 	MQTTTargetHost        string        `env:"MG_MQTT_ADAPTER_MQTT_TARGET_HOST"             envDefault:"localhost"`
 	MQTTTargetPort        string        `env:"MG_MQTT_ADAPTER_MQTT_TARGET_PORT"             envDefault:"1883"`
 	MQTTForwarderTimeout  time.Duration `env:"MG_MQTT_ADAPTER_FORWARDER_TIMEOUT"            envDefault:"30s"`
@@ -180,7 +186,24 @@ func main() {
 
 	logger.Info("Successfully connected to things grpc server " + authHandler.Secure())
 
-	h := mqtt.NewHandler(np, es, logger, authClient)
+	certAuthConfig := certauth.Config{}
+	if err := env.ParseWithOptions(&certAuthConfig, env.Options{Prefix: envPrefixCertAuth}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s cert auth configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+	var certAuth certauth.Authenticator
+	if certAuthConfig.CAPath != "" {
+		certAuth, err = certauth.Setup(certAuthConfig)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to setup %s cert auth : %s", svcName, err))
+			exitCode = 1
+			return
+		}
+		logger.Info("Client certificate authentication enabled with CA " + certAuthConfig.CAPath)
+	}
+
+	h := mqtt.NewHandler(np, es, logger, authClient, certAuth)
 	h = handler.NewTracing(tracer, h)
 
 	if cfg.SendTelemetry {
@@ -194,6 +217,19 @@ func main() {
 		return proxyMQTT(ctx, cfg, logger, h, interceptor)
 	})
 
+	if certAuth != nil && cfg.ServerCert != "" && cfg.ServerKey != "" {
+		tlsCfg, err := certauth.TLSConfig(certAuthConfig.CAPath, cfg.ServerCert, cfg.ServerKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to load %s TLS configuration : %s", svcName, err))
+			exitCode = 1
+			return
+		}
+		logger.Info(fmt.Sprintf("Starting MQTTS proxy on port %s verifying client certificates", cfg.MQTTSPort))
+		g.Go(func() error {
+			return proxyMQTTS(ctx, cfg, tlsCfg, logger, h, interceptor)
+		})
+	}
+
 	logger.Info(fmt.Sprintf("Starting MQTT over WS  proxy on port %s", cfg.HTTPPort))
 	g.Go(func() error {
 		return proxyWS(ctx, cfg, logger, h, interceptor)
@@ -227,6 +263,25 @@ func proxyMQTT(ctx context.Context, cfg config, logger *slog.Logger, sessionHand
 	}
 }
 
+func proxyMQTTS(ctx context.Context, cfg config, tlsCfg *tls.Config, logger *slog.Logger, sessionHandler session.Handler, interceptor session.Interceptor) error {
+	address := fmt.Sprintf(":%s", cfg.MQTTSPort)
+	target := fmt.Sprintf("%s:%s", cfg.MQTTTargetHost, cfg.MQTTTargetPort)
+	mproxy := mp.New(address, target, sessionHandler, interceptor, logger)
+
+	errCh := make(chan error)
+	go func() {
+		errCh <- mproxy.ListenTLS(ctx, tlsCfg)
+	}()
+
+	select {
+	case <-ctx.Done():
+		logger.Info(fmt.Sprintf("proxy MQTTS shutdown at %s", target))
+		return nil
+	case err := <-errCh:
+		return err
+	}
+}
+
 func proxyWS(ctx context.Context, cfg config, logger *slog.Logger, sessionHandler session.Handler, interceptor session.Interceptor) error {
 	target := fmt.Sprintf("%s:%s", cfg.HTTPTargetHost, cfg.HTTPTargetPort)
 	wp := websocket.New(target, cfg.HTTPTargetPath, "ws", sessionHandler, interceptor, logger)
@@ -26,6 +26,7 @@ import (
 	"github.com/absmach/magistrala/opcua/events"
 	"github.com/absmach/magistrala/opcua/gopcua"
 	"github.com/absmach/magistrala/pkg/events/store"
+	"github.com/absmach/magistrala/pkg/messaging"
 	"github.com/absmach/magistrala/pkg/messaging/brokers"
 	brokerstracing "github.com/absmach/magistrala/pkg/messaging/brokers/tracing"
 	"github.com/absmach/magistrala/pkg/uuid"
@@ -42,6 +43,7 @@ const (
 	thingsRMPrefix     = "thing"
 	channelsRMPrefix   = "channel"
 	connectionRMPrefix = "connection"
+	securityPrefix     = "security"
 
 	thingsStream = "magistrala.things"
 )
@@ -106,6 +108,7 @@ func main() {
 	thingRM := newRouteMapRepositoy(rmConn, thingsRMPrefix, logger)
 	chanRM := newRouteMapRepositoy(rmConn, channelsRMPrefix, logger)
 	connRM := newRouteMapRepositoy(rmConn, connectionRMPrefix, logger)
+	securityRepo := events.NewSecurityRepository(rmConn, securityPrefix)
 
 	tp, err := jaegerclient.NewProvider(ctx, svcName, cfg.JaegerURL, cfg.InstanceID, cfg.TraceRatio)
 	if err != nil {
@@ -131,10 +134,22 @@ func main() {
 
 	sub := gopcua.NewSubscriber(ctx, pubSub, thingRM, chanRM, connRM, logger)
 	browser := gopcua.NewBrowser(ctx, logger)
+	commander := gopcua.NewCommander()
 
-	svc := newService(sub, browser, thingRM, chanRM, connRM, opcConfig, logger)
+	svc := newService(sub, browser, commander, pubSub, thingRM, chanRM, connRM, securityRepo, opcConfig, logger)
 
-	go subscribeToStoredSubs(ctx, sub, opcConfig, logger)
+	subCfg := messaging.SubscriberConfig{
+		ID:      svcName,
+		Topic:   brokers.SubjectAllChannels,
+		Handler: opcua.NewCommandHandler(ctx, svc),
+	}
+	if err := pubSub.Subscribe(ctx, subCfg); err != nil {
+		logger.Error(fmt.Sprintf("failed to subscribe to message broker: %s", err))
+		exitCode = 1
+		return
+	}
+
+	go subscribeToStoredSubs(ctx, sub, chanRM, securityRepo, opcConfig, logger)
 
 	if err = subscribeToThingsES(ctx, svc, cfg, logger); err != nil {
 		logger.Error(fmt.Sprintf("failed to subscribe to things event store: %s", err))
@@ -162,7 +177,7 @@ func main() {
 	}
 }
 
-func subscribeToStoredSubs(ctx context.Context, sub opcua.Subscriber, cfg opcua.Config, logger *slog.Logger) {
+func subscribeToStoredSubs(ctx context.Context, sub opcua.Subscriber, chanRM opcua.RouteMapRepository, security opcua.SecurityRepository, opcConfig opcua.Config, logger *slog.Logger) {
 	// Get all stored subscriptions
 	nodes, err := db.ReadAll()
 	if err != nil {
@@ -170,8 +185,17 @@ func subscribeToStoredSubs(ctx context.Context, sub opcua.Subscriber, cfg opcua.
 	}
 
 	for _, n := range nodes {
-		cfg.ServerURI = n.ServerURI
-		cfg.NodeID = n.NodeID
+		// Use the security of the channel mapped to the OPC-UA Server
+		chanID, err := chanRM.Get(ctx, n.ServerURI)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Route-map not found for stored subscription to server %s", n.ServerURI))
+			continue
+		}
+		cfg, err := opcua.ServerConfig(ctx, opcConfig, security, chanID, n.ServerURI, n.NodeID)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Read security of stored subscription failed: %s", err))
+			continue
+		}
 		go func() {
 			if err := sub.Subscribe(ctx, cfg); err != nil {
 				logger.Warn(fmt.Sprintf("Subscription failed: %s", err))
@@ -198,8 +222,8 @@ func newRouteMapRepositoy(client *redis.Client, prefix string, logger *slog.Logg
 	return events.NewRouteMapRepository(client, prefix)
 }
 
-func newService(sub opcua.Subscriber, browser opcua.Browser, thingRM, chanRM, connRM opcua.RouteMapRepository, opcuaConfig opcua.Config, logger *slog.Logger) opcua.Service {
-	svc := opcua.New(sub, browser, thingRM, chanRM, connRM, opcuaConfig, logger)
+func newService(sub opcua.Subscriber, browser opcua.Browser, cmd opcua.Commander, pub messaging.Publisher, thingRM, chanRM, connRM opcua.RouteMapRepository, security opcua.SecurityRepository, opcuaConfig opcua.Config, logger *slog.Logger) opcua.Service {
+	svc := opcua.New(sub, browser, cmd, pub, thingRM, chanRM, connRM, security, opcuaConfig, logger)
 	svc = api.LoggingMiddleware(svc, logger)
 	counter, latency := internal.MakeMetrics("opc_ua_adapter", "api")
 	svc = api.MetricsMiddleware(svc, counter, latency)
@@ -16,6 +16,7 @@ import (
 	"github.com/absmach/magistrala"
 	"github.com/absmach/magistrala/consumers"
 	consumertracing "github.com/absmach/magistrala/consumers/tracing"
+	"github.com/absmach/magistrala/consumers/writers"
 	"github.com/absmach/magistrala/consumers/writers/api"
 	writerpg "github.com/absmach/magistrala/consumers/writers/postgres"
 	"github.com/absmach/magistrala/internal"
@@ -36,6 +37,7 @@ const (
 	svcName        = "postgres-writer"
 	envPrefixDB    = "MG_POSTGRES_"
 	envPrefixHTTP  = "MG_POSTGRES_WRITER_HTTP_"
+	envPrefixBatch = "MG_POSTGRES_WRITER_BATCH_"
 	defDB          = "messages"
 	defSvcHTTPPort = "9010"
 )
@@ -82,6 +84,13 @@ func main() {
 		return
 	}
 
+	batchConfig := writers.BatchConfig{}
+	if err := env.ParseWithOptions(&batchConfig, env.Options{Prefix: envPrefixBatch}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s batch configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+
 	dbConfig := pgclient.Config{Name: defDB}
 	if err := env.ParseWithOptions(&dbConfig, env.Options{Prefix: envPrefixDB}); err != nil {
 		logger.Error(fmt.Sprintf("failed to load %s Postgres configuration : %s", svcName, err))
@@ -117,15 +126,17 @@ func main() {
 	pubSub = brokerstracing.NewPubSub(httpServerConfig, tracer, pubSub)
 
 	repo := newService(db, logger)
+	repo = writers.NewBatch(repo, batchConfig)
 	repo = consumertracing.NewBlocking(tracer, repo, httpServerConfig)
 
-	if err = consumers.Start(ctx, svcName, pubSub, repo, cfg.ConfigPath, logger); err != nil {
+	dlq, err := consumers.Start(ctx, svcName, pubSub, repo, cfg.ConfigPath, logger)
+	if err != nil {
 		logger.Error(fmt.Sprintf("failed to create Postgres writer: %s", err))
 		exitCode = 1
 		return
 	}
 
-	hs := httpserver.New(ctx, cancel, svcName, httpServerConfig, api.MakeHandler(svcName, cfg.InstanceID), logger)
+	hs := httpserver.New(ctx, cancel, svcName, httpServerConfig, api.MakeHandler(dlq, logger, svcName, cfg.InstanceID), logger)
 
 	if cfg.SendTelemetry {
 		chc := chclient.New(svcName, magistrala.Version, logger, cancel)
@@ -16,6 +16,7 @@ import (
 	"github.com/absmach/magistrala"
 	"github.com/absmach/magistrala/consumers"
 	consumertracing "github.com/absmach/magistrala/consumers/tracing"
+	"github.com/absmach/magistrala/consumers/writers"
 	"github.com/absmach/magistrala/consumers/writers/api"
 	"github.com/absmach/magistrala/consumers/writers/timescale"
 	"github.com/absmach/magistrala/internal"
@@ -36,6 +37,7 @@ const (
 	svcName        = "timescaledb-writer"
 	envPrefixDB    = "MG_TIMESCALE_"
 	envPrefixHTTP  = "MG_TIMESCALE_WRITER_HTTP_"
+	envPrefixBatch = "MG_TIMESCALE_WRITER_BATCH_"
 	defDB          = "messages"
 	defSvcHTTPPort = "9012"
 )
@@ -82,6 +84,13 @@ func main() {
 		return
 	}
 
+	batchConfig := writers.BatchConfig{}
+	if err := env.ParseWithOptions(&batchConfig, env.Options{Prefix: envPrefixBatch}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s batch configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+
 	dbConfig := pgclient.Config{Name: defDB}
 	if err := env.ParseWithOptions(&dbConfig, env.Options{Prefix: envPrefixDB}); err != nil {
 		logger.Error(fmt.Sprintf("failed to load %s Postgres configuration : %s", svcName, err))
@@ -110,6 +119,7 @@ func main() {
 	tracer := tp.Tracer(svcName)
 
 	repo := newService(db, logger)
+	repo = writers.NewBatch(repo, batchConfig)
 	repo = consumertracing.NewBlocking(tracer, repo, httpServerConfig)
 
 	pubSub, err := brokers.NewPubSub(ctx, cfg.BrokerURL, logger)
@@ -121,13 +131,14 @@ func main() {
 	defer pubSub.Close()
 	pubSub = brokerstracing.NewPubSub(httpServerConfig, tracer, pubSub)
 
-	if err = consumers.Start(ctx, svcName, pubSub, repo, cfg.ConfigPath, logger); err != nil {
+	dlq, err := consumers.Start(ctx, svcName, pubSub, repo, cfg.ConfigPath, logger)
+	if err != nil {
 		logger.Error(fmt.Sprintf("failed to create Timescale writer: %s", err))
 		exitCode = 1
 		return
 	}
 
-	hs := httpserver.New(ctx, cancel, svcName, httpServerConfig, api.MakeHandler(svcName, cfg.InstanceID), logger)
+	hs := httpserver.New(ctx, cancel, svcName, httpServerConfig, api.MakeHandler(dlq, logger, svcName, cfg.InstanceID), logger)
 
 	if cfg.SendTelemetry {
 		chc := chclient.New(svcName, magistrala.Version, logger, cancel)
@@ -17,7 +17,9 @@ import (
 	"github.com/absmach/magistrala/internal"
 	jaegerclient "github.com/absmach/magistrala/internal/clients/jaeger"
 	mongoclient "github.com/absmach/magistrala/internal/clients/mongo"
+	pgclient "github.com/absmach/magistrala/internal/clients/postgres"
 	redisclient "github.com/absmach/magistrala/internal/clients/redis"
+	"github.com/absmach/magistrala/internal/postgres"
 	"github.com/absmach/magistrala/internal/server"
 	httpserver "github.com/absmach/magistrala/internal/server/http"
 	mglog "github.com/absmach/magistrala/logger"
@@ -32,10 +34,10 @@ import (
 	twapi "github.com/absmach/magistrala/twins/api/http"
 	"github.com/absmach/magistrala/twins/events"
 	twmongodb "github.com/absmach/magistrala/twins/mongodb"
+	twpostgres "github.com/absmach/magistrala/twins/postgres"
 	"github.com/absmach/magistrala/twins/tracing"
 	"github.com/caarlos0/env/v10"
 	"github.com/go-redis/redis/v8"
-	"go.mongodb.org/mongo-driver/mongo"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 )
@@ -45,11 +47,16 @@ const (
 	envPrefixDB    = "MG_TWINS_DB_"
 	envPrefixHTTP  = "MG_TWINS_HTTP_"
 	envPrefixAuth  = "MG_AUTH_GRPC_"
+	defDB          = "twins"
 	defSvcHTTPPort = "9018"
+
+	dbMongo    = "mongodb"
+	dbPostgres = "postgres"
 )
 
 type config struct {
 	LogLevel        string  `env:"MG_TWINS_LOG_LEVEL"          envDefault:"info"`
+	DBType          string  `env:"MG_TWINS_DB_TYPE"            envDefault:"mongodb"`
 	StandaloneID    string  `env:"MG_TWINS_STANDALONE_ID"      envDefault:""`
 	StandaloneToken string  `env:"MG_TWINS_STANDALONE_TOKEN"   envDefault:""`
 	ChannelID       string  `env:"MG_TWINS_CHANNEL_ID"         envDefault:""`
@@ -102,13 +109,6 @@ func main() {
 	}
 	defer cacheClient.Close()
 
-	db, err := mongoclient.Setup(envPrefixDB)
-	if err != nil {
-		logger.Error(fmt.Sprintf("failed to setup postgres database : %s", err))
-		exitCode = 1
-		return
-	}
-
 	tp, err := jaegerclient.NewProvider(ctx, svcName, cfg.JaegerURL, cfg.InstanceID, cfg.TraceRatio)
 	if err != nil {
 		logger.Error(fmt.Sprintf("failed to init Jaeger: %s", err))
@@ -122,6 +122,41 @@ func main() {
 	}()
 	tracer := tp.Tracer(svcName)
 
+	var twinRepo twins.TwinRepository
+	var stateRepo twins.StateRepository
+	switch cfg.DBType {
+	case dbMongo:
+		db, err := mongoclient.Setup(envPrefixDB)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to setup mongodb database : %s", err))
+			exitCode = 1
+			return
+		}
+		twinRepo = twmongodb.NewTwinRepository(db)
+		stateRepo = twmongodb.NewStateRepository(db)
+	case dbPostgres:
+		dbConfig := pgclient.Config{Name: defDB}
+		if err := env.ParseWithOptions(&dbConfig, env.Options{Prefix: envPrefixDB}); err != nil {
+			logger.Error(fmt.Sprintf("failed to load %s database configuration : %s", svcName, err))
+			exitCode = 1
+			return
+		}
+		db, err := pgclient.Setup(dbConfig, *twpostgres.Migration())
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to setup postgres database : %s", err))
+			exitCode = 1
+			return
+		}
+		defer db.Close()
+		database := postgres.NewDatabase(db, dbConfig, tracer)
+		twinRepo = twpostgres.NewTwinRepository(database)
+		stateRepo = twpostgres.NewStateRepository(database)
+	default:
+		logger.Error(fmt.Sprintf("unsupported %s database type %q, expected %s or %s", svcName, cfg.DBType, dbMongo, dbPostgres))
+		exitCode = 1
+		return
+	}
+
 	var authClient magistrala.AuthServiceClient
 	switch cfg.StandaloneID != "" && cfg.StandaloneToken != "" {
 	case true:
@@ -154,7 +189,7 @@ func main() {
 	defer pubSub.Close()
 	pubSub = brokerstracing.NewPubSub(httpServerConfig, tracer, pubSub)
 
-	svc, err := newService(ctx, svcName, pubSub, cfg, authClient, tracer, db, cacheClient, logger)
+	svc, err := newService(ctx, svcName, pubSub, cfg, authClient, tracer, twinRepo, stateRepo, cacheClient, logger)
 	if err != nil {
 		logger.Error(fmt.Sprintf("failed to create %s service: %s", svcName, err))
 		exitCode = 1
@@ -181,11 +216,8 @@ func main() {
 	}
 }
 
-func newService(ctx context.Context, id string, ps messaging.PubSub, cfg config, users magistrala.AuthServiceClient, tracer trace.Tracer, db *mongo.Database, cacheclient *redis.Client, logger *slog.Logger) (twins.Service, error) {
-	twinRepo := twmongodb.NewTwinRepository(db)
+func newService(ctx context.Context, id string, ps messaging.PubSub, cfg config, users magistrala.AuthServiceClient, tracer trace.Tracer, twinRepo twins.TwinRepository, stateRepo twins.StateRepository, cacheclient *redis.Client, logger *slog.Logger) (twins.Service, error) {
 	twinRepo = tracing.TwinRepositoryMiddleware(tracer, twinRepo)
-
-	stateRepo := twmongodb.NewStateRepository(db)
 	stateRepo = tracing.StateRepositoryMiddleware(tracer, stateRepo)
 
 	idProvider := uuid.New()
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"log"
 	"log/slog"
+	"net/http"
 	"net/url"
 	"os"
 
@@ -20,6 +21,7 @@ import (
 	httpserver "github.com/absmach/magistrala/internal/server/http"
 	mglog "github.com/absmach/magistrala/logger"
 	"github.com/absmach/magistrala/pkg/auth"
+	"github.com/absmach/magistrala/pkg/certauth"
 	"github.com/absmach/magistrala/pkg/messaging"
 	"github.com/absmach/magistrala/pkg/messaging/brokers"
 	brokerstracing "github.com/absmach/magistrala/pkg/messaging/brokers/tracing"
@@ -35,12 +37,13 @@ import (
 )
 
 const (
-	svcName        = "ws-adapter"
-	envPrefixHTTP  = "MG_WS_ADAPTER_HTTP_"
-	envPrefixAuthz = "MG_THINGS_AUTH_GRPC_"
-	defSvcHTTPPort = "8190"
-	targetWSPort   = "8191"
-	targetWSHost   = "localhost"
+	svcName           = "ws-adapter"
+	envPrefixHTTP     = "MG_WS_ADAPTER_HTTP_"
+	envPrefixAuthz    = "MG_THINGS_AUTH_GRPC_"
+	envPrefixCertAuth = "MG_CERTS_AUTH_"
+	defSvcHTTPPort    = "8190"
+	targetWSPort      = "8191"
+	targetWSHost      = "localhost"
 )
 
 type config struct {
@@ -106,6 +109,23 @@ func main() {
 
 	logger.Info("Successfully connected to things grpc server " + authHandler.Secure())
 
+	certAuthConfig := certauth.Config{}
+	if err := env.ParseWithOptions(&certAuthConfig, env.Options{Prefix: envPrefixCertAuth}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s cert auth configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+	var certAuth certauth.Authenticator
+	if certAuthConfig.CAPath != "" {
+		certAuth, err = certauth.Setup(certAuthConfig)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to setup %s cert auth : %s", svcName, err))
+			exitCode = 1
+			return
+		}
+		logger.Info("Client certificate authentication enabled with CA " + certAuthConfig.CAPath)
+	}
+
 	tp, err := jaegerclient.NewProvider(ctx, svcName, cfg.JaegerURL, cfg.InstanceID, cfg.TraceRatio)
 	if err != nil {
 		logger.Error(fmt.Sprintf("failed to init Jaeger: %s", err))
@@ -141,8 +161,8 @@ func main() {
 		g.Go(func() error {
 			return hs.Start()
 		})
-		handler := ws.NewHandler(nps, logger, authClient)
-		return proxyWS(ctx, httpServerConfig, targetServerConfig, logger, handler)
+		handler := ws.NewHandler(nps, logger, authClient, certAuth)
+		return proxyWS(ctx, httpServerConfig, targetServerConfig, certAuthConfig, logger, handler)
 	})
 
 	g.Go(func() error {
@@ -163,25 +183,45 @@ func newService(tc magistrala.AuthzServiceClient, nps messaging.PubSub, logger *
 	return svc
 }
 
-func proxyWS(ctx context.Context, hostConfig, targetConfig server.Config, logger *slog.Logger, handler session.Handler) error {
+func proxyWS(ctx context.Context, hostConfig, targetConfig server.Config, certAuthConfig certauth.Config, logger *slog.Logger, handler session.Handler) error {
 	target := fmt.Sprintf("ws://%s:%s", targetConfig.Host, targetConfig.Port)
 	address := fmt.Sprintf("%s:%s", hostConfig.Host, hostConfig.Port)
 	wp, err := websockets.NewProxy(address, target, logger, handler)
 	if err != nil {
 		return err
 	}
+	h, err := certauth.Handler(http.HandlerFunc(wp.Handler), certAuthConfig)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Addr:    address,
+		Handler: h,
+	}
 
 	errCh := make(chan error)
 
-	go func() {
-		if hostConfig.CertFile != "" && hostConfig.KeyFile != "" {
-			logger.Info(fmt.Sprintf("ws-adapter service http server listening at %s:%s with TLS", hostConfig.Host, hostConfig.Port))
-			errCh <- wp.ListenTLS(hostConfig.CertFile, hostConfig.KeyFile)
-		} else {
-			logger.Info(fmt.Sprintf("ws-adapter service http server listening at %s:%s without TLS", hostConfig.Host, hostConfig.Port))
-			errCh <- wp.Listen()
+	switch {
+	case hostConfig.CertFile != "" && hostConfig.KeyFile != "" && certAuthConfig.CAPath != "":
+		srv.TLSConfig, err = certauth.TLSConfig(certAuthConfig.CAPath, hostConfig.CertFile, hostConfig.KeyFile)
+		if err != nil {
+			return err
 		}
-	}()
+		logger.Info(fmt.Sprintf("ws-adapter service http server listening at %s:%s with TLS verifying client certificates", hostConfig.Host, hostConfig.Port))
+		go func() {
+			errCh <- srv.ListenAndServeTLS("", "")
+		}()
+	case hostConfig.CertFile != "" && hostConfig.KeyFile != "":
+		logger.Info(fmt.Sprintf("ws-adapter service http server listening at %s:%s with TLS", hostConfig.Host, hostConfig.Port))
+		go func() {
+			errCh <- srv.ListenAndServeTLS(hostConfig.CertFile, hostConfig.KeyFile)
+		}()
+	default:
+		logger.Info(fmt.Sprintf("ws-adapter service http server listening at %s:%s without TLS", hostConfig.Host, hostConfig.Port))
+		go func() {
+			errCh <- srv.ListenAndServe()
+		}()
+	}
 
 	select {
 	case <-ctx.Done():
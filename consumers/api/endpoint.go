@@ -0,0 +1,71 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/absmach/magistrala/consumers"
+	"github.com/absmach/magistrala/internal/apiutil"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func listDeadLettersEndpoint(dlq consumers.DeadLetterQueue) endpoint.Endpoint {
+	return func(_ context.Context, _ interface{}) (interface{}, error) {
+		letters := dlq.List()
+
+		return listDeadLettersRes{
+			Total:       len(letters),
+			DeadLetters: letters,
+		}, nil
+	}
+}
+
+func viewDeadLetterEndpoint(dlq consumers.DeadLetterQueue) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(deadLetterReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		letter, err := dlq.View(req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return deadLetterRes{letter}, nil
+	}
+}
+
+func replayDeadLetterEndpoint(dlq consumers.DeadLetterQueue) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(deadLetterReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		letter, err := dlq.Replay(ctx, req.id)
+		if err != nil {
+			return nil, errors.Wrap(errReplay, err)
+		}
+
+		return deadLetterRes{letter}, nil
+	}
+}
+
+func removeDeadLetterEndpoint(dlq consumers.DeadLetterQueue) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(deadLetterReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		if err := dlq.Remove(req.id); err != nil {
+			return nil, err
+		}
+
+		return removeDeadLetterRes{}, nil
+	}
+}
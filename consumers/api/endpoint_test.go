@@ -0,0 +1,216 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers"
+	"github.com/absmach/magistrala/consumers/api"
+	"github.com/absmach/magistrala/consumers/mocks"
+	mglog "github.com/absmach/magistrala/logger"
+	"github.com/absmach/magistrala/pkg/messaging"
+	"github.com/stretchr/testify/assert"
+)
+
+const consumerID = "writer"
+
+type testRequest struct {
+	client *http.Client
+	method string
+	url    string
+}
+
+func (tr testRequest) make() (*http.Response, error) {
+	req, err := http.NewRequest(tr.method, tr.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return tr.client.Do(req)
+}
+
+func newServer(letters []consumers.DeadLetter) *httptest.Server {
+	logger := mglog.NewMock()
+	mux := api.MakeHandler(mocks.NewDeadLetterQueue(letters), logger)
+	return httptest.NewServer(mux)
+}
+
+func deadLetters() []consumers.DeadLetter {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	return []consumers.DeadLetter{
+		{
+			ID:       "1",
+			Consumer: consumerID,
+			Error:    "failed to consume",
+			Attempts: 3,
+			FailedAt: now,
+			Message:  &messaging.Message{Channel: "1", Protocol: "http", Payload: []byte(`[{"n":"temp","v":1}]`)},
+		},
+		{
+			ID:       "2",
+			Consumer: consumerID,
+			Error:    "failed to consume",
+			Attempts: 3,
+			FailedAt: now,
+			Message:  &messaging.Message{Channel: "1", Protocol: mocks.FailingMessageProtocol, Payload: []byte(`[{"n":"temp","v":2}]`)},
+		},
+	}
+}
+
+func TestListDeadLetters(t *testing.T) {
+	letters := deadLetters()
+	ts := newServer(letters)
+	defer ts.Close()
+
+	cases := []struct {
+		desc    string
+		letters []consumers.DeadLetter
+		status  int
+	}{
+		{
+			desc:    "list dead letters",
+			letters: letters,
+			status:  http.StatusOK,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/", ts.URL),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body struct {
+			Total       int                    `json:"total"`
+			DeadLetters []consumers.DeadLetter `json:"dead_letters"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, len(tc.letters), body.Total, fmt.Sprintf("%s: expected total %d got %d", tc.desc, len(tc.letters), body.Total))
+		for i, letter := range body.DeadLetters {
+			assert.Equal(t, tc.letters[i].ID, letter.ID, fmt.Sprintf("%s: expected dead letter %s got %s", tc.desc, tc.letters[i].ID, letter.ID))
+			assert.Equal(t, tc.letters[i].Message.Payload, letter.Message.Payload, fmt.Sprintf("%s: expected equal payloads", tc.desc))
+		}
+	}
+}
+
+func TestViewDeadLetter(t *testing.T) {
+	ts := newServer(deadLetters())
+	defer ts.Close()
+
+	cases := []struct {
+		desc   string
+		id     string
+		status int
+	}{
+		{
+			desc:   "view existing dead letter",
+			id:     "1",
+			status: http.StatusOK,
+		},
+		{
+			desc:   "view non-existing dead letter",
+			id:     "unknown",
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/%s", ts.URL, tc.id),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestReplayDeadLetter(t *testing.T) {
+	ts := newServer(deadLetters())
+	defer ts.Close()
+
+	cases := []struct {
+		desc   string
+		id     string
+		status int
+	}{
+		{
+			desc:   "replay dead letter",
+			id:     "1",
+			status: http.StatusOK,
+		},
+		{
+			desc:   "replay already replayed dead letter",
+			id:     "1",
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "replay dead letter that fails to be consumed",
+			id:     "2",
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			desc:   "replay non-existing dead letter",
+			id:     "unknown",
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/%s/replay", ts.URL, tc.id),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestRemoveDeadLetter(t *testing.T) {
+	ts := newServer(deadLetters())
+	defer ts.Close()
+
+	cases := []struct {
+		desc   string
+		id     string
+		status int
+	}{
+		{
+			desc:   "remove dead letter",
+			id:     "1",
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "remove removed dead letter",
+			id:     "1",
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/%s", ts.URL, tc.id),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/absmach/magistrala/internal/apiutil"
+
+type deadLetterReq struct {
+	id string
+}
+
+func (req deadLetterReq) validate() error {
+	if req.id == "" {
+		return apiutil.ErrMissingID
+	}
+
+	return nil
+}
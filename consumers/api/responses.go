@@ -0,0 +1,64 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/absmach/magistrala"
+	"github.com/absmach/magistrala/consumers"
+)
+
+var (
+	_ magistrala.Response = (*listDeadLettersRes)(nil)
+	_ magistrala.Response = (*deadLetterRes)(nil)
+	_ magistrala.Response = (*removeDeadLetterRes)(nil)
+)
+
+type listDeadLettersRes struct {
+	Total       int                    `json:"total"`
+	DeadLetters []consumers.DeadLetter `json:"dead_letters"`
+}
+
+func (res listDeadLettersRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listDeadLettersRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listDeadLettersRes) Empty() bool {
+	return false
+}
+
+type deadLetterRes struct {
+	consumers.DeadLetter
+}
+
+func (res deadLetterRes) Code() int {
+	return http.StatusOK
+}
+
+func (res deadLetterRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res deadLetterRes) Empty() bool {
+	return false
+}
+
+type removeDeadLetterRes struct{}
+
+func (res removeDeadLetterRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeDeadLetterRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeDeadLetterRes) Empty() bool {
+	return true
+}
@@ -0,0 +1,112 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/absmach/magistrala"
+	"github.com/absmach/magistrala/consumers"
+	"github.com/absmach/magistrala/internal/apiutil"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/go-chi/chi/v5"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+const contentType = "application/json"
+
+var errReplay = errors.New("failed to replay dead letter")
+
+// MakeHandler returns a HTTP handler for dead-letter queue endpoints. Handler
+// is meant to be mounted on the consumer HTTP server, e.g. under /dlq path.
+func MakeHandler(dlq consumers.DeadLetterQueue, logger *slog.Logger) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(apiutil.LoggingErrorEncoder(logger, encodeError)),
+	}
+
+	mux := chi.NewRouter()
+
+	mux.Get("/", otelhttp.NewHandler(kithttp.NewServer(
+		listDeadLettersEndpoint(dlq),
+		kithttp.NopRequestDecoder,
+		encodeResponse,
+		opts...,
+	), "list_dead_letters").ServeHTTP)
+
+	mux.Get("/{id}", otelhttp.NewHandler(kithttp.NewServer(
+		viewDeadLetterEndpoint(dlq),
+		decodeDeadLetter,
+		encodeResponse,
+		opts...,
+	), "view_dead_letter").ServeHTTP)
+
+	mux.Post("/{id}/replay", otelhttp.NewHandler(kithttp.NewServer(
+		replayDeadLetterEndpoint(dlq),
+		decodeDeadLetter,
+		encodeResponse,
+		opts...,
+	), "replay_dead_letter").ServeHTTP)
+
+	mux.Delete("/{id}", otelhttp.NewHandler(kithttp.NewServer(
+		removeDeadLetterEndpoint(dlq),
+		decodeDeadLetter,
+		encodeResponse,
+		opts...,
+	), "remove_dead_letter").ServeHTTP)
+
+	return mux
+}
+
+func decodeDeadLetter(_ context.Context, r *http.Request) (interface{}, error) {
+	return deadLetterReq{id: chi.URLParam(r, "id")}, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	if ar, ok := response.(magistrala.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	var wrapper error
+	if errors.Contains(err, apiutil.ErrValidation) {
+		wrapper, err = errors.Unwrap(err)
+	}
+
+	switch {
+	case errors.Contains(err, apiutil.ErrMissingID):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, consumers.ErrDeadLetterNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Contains(err, errReplay):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	if wrapper != nil {
+		err = errors.Wrap(wrapper, err)
+	}
+
+	if errorVal, ok := err.(errors.Error); ok {
+		w.Header().Set("Content-Type", contentType)
+		if err := json.NewEncoder(w).Encode(errorVal); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
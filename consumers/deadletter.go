@@ -0,0 +1,339 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package consumers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/absmach/magistrala"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/pkg/messaging"
+	"github.com/cenkalti/backoff/v4"
+)
+
+// DeadLetterProtocol is the protocol of messages published to the dead-letter
+// topic. Consumers skip such messages, since dead-letter topic is a subtopic
+// of the channels subject they are usually subscribed to.
+const DeadLetterProtocol = "dead-letter"
+
+const chansPrefix = "channels"
+
+var (
+	// ErrDeadLetterNotFound indicates that the dead letter doesn't exist.
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+	errPublishDeadLetter   = errors.New("failed to publish dead letter")
+	errSubscribeDeadLetter = errors.New("failed to subscribe to dead-letter topic")
+)
+
+// DeadLetter represents a message that failed to be consumed after all the
+// retries.
+type DeadLetter struct {
+	ID       string             `json:"id"`
+	Consumer string             `json:"consumer"`
+	Error    string             `json:"error"`
+	Attempts uint64             `json:"attempts"`
+	FailedAt time.Time          `json:"failed_at"`
+	Message  *messaging.Message `json:"message"`
+}
+
+// DeadLetterQueue specifies an API for inspecting and replaying messages
+// that failed to be consumed.
+type DeadLetterQueue interface {
+	// List returns dead letters ordered from the oldest to the newest.
+	List() []DeadLetter
+
+	// View returns the dead letter with the given ID.
+	View(id string) (DeadLetter, error)
+
+	// Replay consumes the dead letter message once again. The dead letter
+	// is removed from the queue if the message is consumed successfully.
+	Replay(ctx context.Context, id string) (DeadLetter, error)
+
+	// Remove removes the dead letter from the queue without consuming it.
+	Remove(id string) error
+}
+
+type retryConfig struct {
+	MaxRetries      uint64        `toml:"max_retries"`
+	InitialInterval time.Duration `toml:"initial_interval"`
+	MaxInterval     time.Duration `toml:"max_interval"`
+	Multiplier      float64       `toml:"multiplier"`
+}
+
+type deadLetterConfig struct {
+	Topic string `toml:"topic"`
+}
+
+// backOff returns exponential back-off that stops after the configured number
+// of retries or when the context is canceled.
+func (cfg retryConfig) backOff(ctx context.Context) backoff.BackOff {
+	if cfg.MaxRetries == 0 {
+		return &backoff.StopBackOff{}
+	}
+
+	b := backoff.NewExponentialBackOff()
+	if cfg.InitialInterval > 0 {
+		b.InitialInterval = cfg.InitialInterval
+	}
+	if cfg.MaxInterval > 0 {
+		b.MaxInterval = cfg.MaxInterval
+	}
+	if cfg.Multiplier > 0 {
+		b.Multiplier = cfg.Multiplier
+	}
+	b.MaxElapsedTime = 0
+
+	return backoff.WithContext(backoff.WithMaxRetries(b, cfg.MaxRetries), ctx)
+}
+
+var (
+	_ DeadLetterQueue          = (*deadLetterQueue)(nil)
+	_ messaging.MessageHandler = (*deadLetterQueue)(nil)
+)
+
+// deadLetterRecord is the payload of the messages published to the
+// dead-letter topic. Every change of the dead letter is published as a new
+// record, so the dead-letter topic is the source of truth of the queue.
+// Resolved record marks the dead letter as replayed or removed.
+type deadLetterRecord struct {
+	DeadLetter
+	Resolved bool `json:"resolved,omitempty"`
+}
+
+// deadLetterQueue keeps the index of unresolved dead letters built from the
+// records read from the dead-letter topic.
+type deadLetterQueue struct {
+	mu       sync.Mutex
+	consumer string
+	topic    string
+	pub      messaging.Publisher
+	idp      magistrala.IDProvider
+	logger   *slog.Logger
+	consume  func(ctx context.Context, msg *messaging.Message) error
+	letters  map[string]DeadLetter
+	order    []string
+	resolved map[string]struct{}
+}
+
+func newDeadLetterQueue(consumer, topic string, pub messaging.Publisher, idp magistrala.IDProvider, consume func(ctx context.Context, msg *messaging.Message) error, logger *slog.Logger) *deadLetterQueue {
+	return &deadLetterQueue{
+		consumer: consumer,
+		topic:    topic,
+		pub:      pub,
+		idp:      idp,
+		logger:   logger,
+		consume:  consume,
+		letters:  make(map[string]DeadLetter),
+		resolved: make(map[string]struct{}),
+	}
+}
+
+// subscribe reads the records of the consumer dead letters from the
+// beginning of the dead-letter topic. The previous subscription is removed
+// first, so that the records that were already read before the restart are
+// delivered again.
+func (dlq *deadLetterQueue) subscribe(ctx context.Context, pubsub messaging.PubSub) error {
+	if dlq.topic == "" {
+		return nil
+	}
+
+	subject := fmt.Sprintf("%s.%s.%s", chansPrefix, dlq.topic, dlq.consumer)
+	if err := pubsub.Unsubscribe(ctx, dlq.consumer, subject); err != nil {
+		dlq.logger.Debug(fmt.Sprintf("Failed to remove dead-letter subscription: %s", err))
+	}
+	subCfg := messaging.SubscriberConfig{
+		ID:             dlq.consumer,
+		Topic:          subject,
+		DeliveryPolicy: messaging.DeliverAllPolicy,
+		Handler:        dlq,
+	}
+	if err := pubsub.Subscribe(ctx, subCfg); err != nil {
+		return errors.Wrap(errSubscribeDeadLetter, err)
+	}
+
+	return nil
+}
+
+// Handle applies the record read from the dead-letter topic to the index.
+func (dlq *deadLetterQueue) Handle(msg *messaging.Message) error {
+	if msg.GetProtocol() != DeadLetterProtocol || msg.GetSubtopic() != dlq.consumer {
+		return nil
+	}
+
+	var rec deadLetterRecord
+	if err := json.Unmarshal(msg.GetPayload(), &rec); err != nil {
+		return err
+	}
+
+	dlq.mu.Lock()
+	defer dlq.mu.Unlock()
+
+	dlq.apply(rec)
+
+	return nil
+}
+
+func (dlq *deadLetterQueue) Cancel() error {
+	return nil
+}
+
+// add publishes the message that failed to be consumed to the dead-letter
+// topic. If the dead-letter topic is not set or publishing fails, the
+// consume error is returned.
+func (dlq *deadLetterQueue) add(ctx context.Context, msg *messaging.Message, attempts uint64, cerr error) error {
+	if dlq.topic == "" {
+		return cerr
+	}
+
+	dlq.mu.Lock()
+	id, err := dlq.idp.ID()
+	dlq.mu.Unlock()
+	if err != nil {
+		return errors.Wrap(cerr, errors.Wrap(errPublishDeadLetter, err))
+	}
+
+	rec := deadLetterRecord{
+		DeadLetter: DeadLetter{
+			ID:       id,
+			Consumer: dlq.consumer,
+			Error:    cerr.Error(),
+			Attempts: attempts,
+			FailedAt: time.Now().UTC(),
+			Message:  msg,
+		},
+	}
+	if err := dlq.publish(ctx, rec); err != nil {
+		return errors.Wrap(cerr, err)
+	}
+
+	return nil
+}
+
+func (dlq *deadLetterQueue) List() []DeadLetter {
+	dlq.mu.Lock()
+	defer dlq.mu.Unlock()
+
+	letters := make([]DeadLetter, 0, len(dlq.order))
+	for _, id := range dlq.order {
+		letters = append(letters, dlq.letters[id])
+	}
+
+	return letters
+}
+
+func (dlq *deadLetterQueue) View(id string) (DeadLetter, error) {
+	dlq.mu.Lock()
+	defer dlq.mu.Unlock()
+
+	letter, ok := dlq.letters[id]
+	if !ok {
+		return DeadLetter{}, ErrDeadLetterNotFound
+	}
+
+	return letter, nil
+}
+
+func (dlq *deadLetterQueue) Replay(ctx context.Context, id string) (DeadLetter, error) {
+	letter, err := dlq.View(id)
+	if err != nil {
+		return DeadLetter{}, err
+	}
+
+	rec := deadLetterRecord{DeadLetter: letter}
+	if cerr := dlq.consume(ctx, letter.Message); cerr != nil {
+		rec.Attempts++
+		rec.Error = cerr.Error()
+		rec.FailedAt = time.Now().UTC()
+		if err := dlq.publish(ctx, rec); err != nil {
+			return DeadLetter{}, errors.Wrap(cerr, err)
+		}
+		return rec.DeadLetter, cerr
+	}
+
+	rec.Resolved = true
+	if err := dlq.publish(ctx, rec); err != nil {
+		return DeadLetter{}, err
+	}
+
+	return rec.DeadLetter, nil
+}
+
+func (dlq *deadLetterQueue) Remove(id string) error {
+	letter, err := dlq.View(id)
+	if err != nil {
+		return err
+	}
+
+	return dlq.publish(context.Background(), deadLetterRecord{DeadLetter: letter, Resolved: true})
+}
+
+// publish publishes the record to the dead-letter topic and applies it to
+// the index, so that the change is visible before the record is read back
+// from the topic. The lock is not held while publishing.
+func (dlq *deadLetterQueue) publish(ctx context.Context, rec deadLetterRecord) error {
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return errors.Wrap(errPublishDeadLetter, err)
+	}
+	msg := &messaging.Message{
+		Channel:   dlq.topic,
+		Subtopic:  dlq.consumer,
+		Publisher: dlq.consumer,
+		Protocol:  DeadLetterProtocol,
+		Payload:   payload,
+		Created:   time.Now().UnixNano(),
+	}
+	if err := dlq.pub.Publish(ctx, dlq.topic, msg); err != nil {
+		return errors.Wrap(errPublishDeadLetter, err)
+	}
+
+	dlq.mu.Lock()
+	defer dlq.mu.Unlock()
+
+	dlq.apply(rec)
+
+	return nil
+}
+
+// apply updates the index with the record. Records of resolved dead letters
+// and records older than the indexed one are ignored, since the record may
+// be read from the topic after the change was already applied.
+func (dlq *deadLetterQueue) apply(rec deadLetterRecord) {
+	if _, ok := dlq.resolved[rec.ID]; ok {
+		return
+	}
+	if rec.Resolved {
+		dlq.resolved[rec.ID] = struct{}{}
+		if _, ok := dlq.letters[rec.ID]; ok {
+			delete(dlq.letters, rec.ID)
+			dlq.order = removeID(dlq.order, rec.ID)
+		}
+		return
+	}
+
+	cur, ok := dlq.letters[rec.ID]
+	switch {
+	case !ok:
+		dlq.order = append(dlq.order, rec.ID)
+	case rec.Attempts < cur.Attempts:
+		return
+	}
+	dlq.letters[rec.ID] = rec.DeadLetter
+}
+
+func removeID(ids []string, id string) []string {
+	for i := range ids {
+		if ids[i] == id {
+			return append(ids[:i], ids[i+1:]...)
+		}
+	}
+
+	return ids
+}
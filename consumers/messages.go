@@ -16,7 +16,10 @@ import (
 	"github.com/absmach/magistrala/pkg/messaging/brokers"
 	"github.com/absmach/magistrala/pkg/transformers"
 	"github.com/absmach/magistrala/pkg/transformers/json"
+	"github.com/absmach/magistrala/pkg/transformers/pipeline"
 	"github.com/absmach/magistrala/pkg/transformers/senml"
+	"github.com/absmach/magistrala/pkg/ulid"
+	"github.com/cenkalti/backoff/v4"
 	"github.com/pelletier/go-toml"
 )
 
@@ -32,8 +35,12 @@ var (
 
 // Start method starts consuming messages received from Message broker.
 // This method transforms messages to SenML format before
-// using MessageRepository to store them.
-func Start(ctx context.Context, id string, sub messaging.Subscriber, consumer interface{}, configPath string, logger *slog.Logger) error {
+// using MessageRepository to store them. Failed messages are retried
+// according to the retry policy and, once the retries are exhausted,
+// published to the dead-letter topic. The returned DeadLetterQueue reads
+// the dead letters from the dead-letter topic and is used to inspect and
+// replay them.
+func Start(ctx context.Context, id string, pubsub messaging.PubSub, consumer interface{}, configPath string, logger *slog.Logger) (DeadLetterQueue, error) {
 	cfg, err := loadConfig(configPath)
 	if err != nil {
 		logger.Warn(fmt.Sprintf("Failed to load consumer config: %s", err))
@@ -41,83 +48,142 @@ func Start(ctx context.Context, id string, sub messaging.Subscriber, consumer in
 
 	transformer := makeTransformer(cfg.TransformerCfg, logger)
 
+	consume, err := makeConsume(ctx, consumer, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	h := &handler{
+		ctx:         ctx,
+		transformer: transformer,
+		consume:     consume,
+		retry:       cfg.RetryCfg,
+	}
+	h.dlq = newDeadLetterQueue(id, cfg.DeadLetterCfg.Topic, pubsub, ulid.New(), h.process, logger)
+	if err := h.dlq.subscribe(ctx, pubsub); err != nil {
+		return nil, err
+	}
+
 	for _, subject := range cfg.SubscriberCfg.Subjects {
 		subCfg := messaging.SubscriberConfig{
 			ID:             id,
 			Topic:          subject,
 			DeliveryPolicy: messaging.DeliverAllPolicy,
+			Handler:        h,
+			Concurrency:    cfg.SubscriberCfg.Concurrency,
 		}
-		switch c := consumer.(type) {
-		case AsyncConsumer:
-			subCfg.Handler = handleAsync(ctx, transformer, c)
-			if err := sub.Subscribe(ctx, subCfg); err != nil {
-				return err
-			}
-		case BlockingConsumer:
-			subCfg.Handler = handleSync(ctx, transformer, c)
-			if err := sub.Subscribe(ctx, subCfg); err != nil {
-				return err
-			}
-		default:
-			return apiutil.ErrInvalidQueryParams
+		if err := pubsub.Subscribe(ctx, subCfg); err != nil {
+			return nil, err
 		}
 	}
-	return nil
+
+	return h.dlq, nil
 }
 
-func handleSync(ctx context.Context, t transformers.Transformer, sc BlockingConsumer) handleFunc {
-	return func(msg *messaging.Message) error {
-		m := interface{}(msg)
-		var err error
-		if t != nil {
-			m, err = t.Transform(msg)
-			if err != nil {
-				return err
-			}
-		}
-		return sc.ConsumeBlocking(ctx, m)
+// makeConsume returns the function used to consume transformed messages.
+// Blocking consumption is preferred when retry or dead-letter queue is
+// configured, since the errors of asynchronous consumption can't be
+// related to the message that caused them.
+func makeConsume(ctx context.Context, consumer interface{}, cfg config, logger *slog.Logger) (func(ctx context.Context, m interface{}) error, error) {
+	bc, blocking := consumer.(BlockingConsumer)
+	ac, async := consumer.(AsyncConsumer)
+	if async && (!blocking || (cfg.RetryCfg.MaxRetries == 0 && cfg.DeadLetterCfg.Topic == "")) {
+		go drainErrors(ctx, ac.Errors(), logger)
+		return func(ctx context.Context, m interface{}) error {
+			ac.ConsumeAsync(ctx, m)
+			return nil
+		}, nil
+	}
+	if blocking {
+		return bc.ConsumeBlocking, nil
 	}
+
+	return nil, apiutil.ErrInvalidQueryParams
 }
 
-func handleAsync(ctx context.Context, t transformers.Transformer, ac AsyncConsumer) handleFunc {
-	return func(msg *messaging.Message) error {
-		m := interface{}(msg)
-		var err error
-		if t != nil {
-			m, err = t.Transform(msg)
+func drainErrors(ctx context.Context, errs <-chan error, logger *slog.Logger) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-errs:
 			if err != nil {
-				return err
+				logger.Error(fmt.Sprintf("Failed to consume message asynchronously: %s", err))
 			}
 		}
+	}
+}
+
+var _ messaging.MessageHandler = (*handler)(nil)
 
-		ac.ConsumeAsync(ctx, m)
+type handler struct {
+	ctx         context.Context
+	transformer transformers.Transformer
+	consume     func(ctx context.Context, m interface{}) error
+	retry       retryConfig
+	dlq         *deadLetterQueue
+}
+
+func (h *handler) Handle(msg *messaging.Message) error {
+	if msg.GetProtocol() == DeadLetterProtocol {
 		return nil
 	}
-}
 
-type handleFunc func(msg *messaging.Message) error
+	var attempts uint64
+	op := func() error {
+		attempts++
+		m, err := h.transform(msg)
+		if err != nil {
+			// Malformed message will not be transformed on retry.
+			return backoff.Permanent(err)
+		}
+		return h.consume(h.ctx, m)
+	}
+	if err := backoff.Retry(op, h.retry.backOff(h.ctx)); err != nil {
+		return h.dlq.add(h.ctx, msg, attempts, err)
+	}
 
-func (h handleFunc) Handle(msg *messaging.Message) error {
-	return h(msg)
+	return nil
 }
 
-func (h handleFunc) Cancel() error {
+func (h *handler) Cancel() error {
 	return nil
 }
 
+// process transforms and consumes the message without retrying.
+func (h *handler) process(ctx context.Context, msg *messaging.Message) error {
+	m, err := h.transform(msg)
+	if err != nil {
+		return err
+	}
+
+	return h.consume(ctx, m)
+}
+
+func (h *handler) transform(msg *messaging.Message) (interface{}, error) {
+	if h.transformer == nil {
+		return msg, nil
+	}
+
+	return h.transformer.Transform(msg)
+}
+
 type subscriberConfig struct {
-	Subjects []string `toml:"subjects"`
+	Subjects    []string `toml:"subjects"`
+	Concurrency uint     `toml:"concurrency"`
 }
 
 type transformerConfig struct {
-	Format      string           `toml:"format"`
-	ContentType string           `toml:"content_type"`
-	TimeFields  []json.TimeField `toml:"time_fields"`
+	Format      string                `toml:"format"`
+	ContentType string                `toml:"content_type"`
+	TimeFields  []json.TimeField      `toml:"time_fields"`
+	Steps       []pipeline.StepConfig `toml:"steps"`
 }
 
 type config struct {
 	SubscriberCfg  subscriberConfig  `toml:"subscriber"`
 	TransformerCfg transformerConfig `toml:"transformer"`
+	RetryCfg       retryConfig       `toml:"retry"`
+	DeadLetterCfg  deadLetterConfig  `toml:"dead_letter"`
 }
 
 func loadConfig(configPath string) (config, error) {
@@ -144,16 +210,30 @@ func loadConfig(configPath string) (config, error) {
 }
 
 func makeTransformer(cfg transformerConfig, logger *slog.Logger) transformers.Transformer {
+	var t transformers.Transformer
 	switch strings.ToUpper(cfg.Format) {
 	case "SENML":
 		logger.Info("Using SenML transformer")
-		return senml.New(cfg.ContentType)
+		t = senml.New(cfg.ContentType)
 	case "JSON":
 		logger.Info("Using JSON transformer")
-		return json.New(cfg.TimeFields)
+		t = json.New(cfg.TimeFields)
 	default:
 		logger.Error(fmt.Sprintf("Can't create transformer: unknown transformer type %s", cfg.Format))
 		os.Exit(1)
 		return nil
 	}
+
+	if len(cfg.Steps) == 0 {
+		return t
+	}
+	t, err := pipeline.New(t, cfg.Steps)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Can't create transformer pipeline: %s", err))
+		os.Exit(1)
+		return nil
+	}
+	logger.Info(fmt.Sprintf("Using transformer pipeline with %d steps", len(cfg.Steps)))
+
+	return t
 }
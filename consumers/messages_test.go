@@ -0,0 +1,319 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package consumers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/absmach/magistrala/consumers"
+	mglog "github.com/absmach/magistrala/logger"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/pkg/messaging"
+	"github.com/absmach/magistrala/pkg/messaging/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	consumerID = "test-consumer"
+	dlqTopic   = "dlq"
+	config     = `
+[subscriber]
+subjects = ["channels.>"]
+
+[transformer]
+format = "senml"
+content_type = "application/senml+json"
+
+[retry]
+max_retries = 2
+initial_interval = "1ms"
+max_interval = "5ms"
+
+[dead_letter]
+topic = "dlq"
+`
+)
+
+var errConsume = errors.New("failed to consume")
+
+// consumer fails to consume the given number of messages before succeeding.
+type consumer struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+}
+
+func (c *consumer) ConsumeBlocking(_ context.Context, _ interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls++
+	if c.failures > 0 {
+		c.failures--
+		return errConsume
+	}
+
+	return nil
+}
+
+var dlqSubject = fmt.Sprintf("channels.%s.%s", dlqTopic, consumerID)
+
+// start starts the consumer and returns the handler of the consumed
+// messages, the dead-letter queue and the handler of the dead-letter topic.
+func start(t *testing.T, c *consumer) (messaging.MessageHandler, consumers.DeadLetterQueue, messaging.MessageHandler, *mocks.PubSub) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	err := os.WriteFile(path, []byte(config), 0o600)
+	require.Nil(t, err, fmt.Sprintf("unexpected error writing config: %s", err))
+
+	var handler, dlqHandler messaging.MessageHandler
+	pubsub := new(mocks.PubSub)
+	pubsub.On("Subscribe", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		cfg := args.Get(1).(messaging.SubscriberConfig)
+		if cfg.Topic == dlqSubject {
+			dlqHandler = cfg.Handler
+			return
+		}
+		handler = cfg.Handler
+	}).Return(nil)
+	pubsub.On("Unsubscribe", mock.Anything, consumerID, dlqSubject).Return(nil)
+	pubsub.On("Publish", mock.Anything, dlqTopic, mock.Anything).Return(nil)
+
+	dlq, err := consumers.Start(context.Background(), consumerID, pubsub, c, path, mglog.NewMock())
+	require.Nil(t, err, fmt.Sprintf("unexpected error starting consumer: %s", err))
+	require.NotNil(t, dlqHandler, "expected subscription to the dead-letter topic")
+
+	return handler, dlq, dlqHandler, pubsub
+}
+
+func TestRetry(t *testing.T) {
+	payload := []byte(`[{"bn":"base","n":"temp","v":21.5}]`)
+
+	cases := []struct {
+		desc        string
+		failures    int
+		payload     []byte
+		protocol    string
+		calls       int
+		deadLetters int
+		attempts    uint64
+	}{
+		{
+			desc:     "consume message on first attempt",
+			payload:  payload,
+			calls:    1,
+			failures: 0,
+		},
+		{
+			desc:     "consume message after retry",
+			payload:  payload,
+			calls:    3,
+			failures: 2,
+		},
+		{
+			desc:        "consume message with exhausted retries",
+			payload:     payload,
+			calls:       3,
+			failures:    3,
+			deadLetters: 1,
+			attempts:    3,
+		},
+		{
+			desc:        "consume malformed message",
+			payload:     []byte("malformed"),
+			calls:       0,
+			deadLetters: 1,
+			attempts:    1,
+		},
+		{
+			desc:     "consume dead letter",
+			payload:  payload,
+			protocol: consumers.DeadLetterProtocol,
+			calls:    0,
+			failures: 3,
+		},
+	}
+
+	for _, tc := range cases {
+		c := &consumer{failures: tc.failures}
+		handler, dlq, _, pubsub := start(t, c)
+
+		msg := &messaging.Message{
+			Channel:  "channel",
+			Protocol: tc.protocol,
+			Payload:  tc.payload,
+		}
+		err := handler.Handle(msg)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+		assert.Equal(t, tc.calls, c.calls, fmt.Sprintf("%s: expected %d calls got %d", tc.desc, tc.calls, c.calls))
+
+		letters := dlq.List()
+		assert.Len(t, letters, tc.deadLetters, fmt.Sprintf("%s: expected %d dead letters got %d", tc.desc, tc.deadLetters, len(letters)))
+		if tc.deadLetters == 0 {
+			pubsub.AssertNotCalled(t, "Publish", mock.Anything, dlqTopic, mock.Anything)
+			continue
+		}
+		assert.Equal(t, tc.attempts, letters[0].Attempts, fmt.Sprintf("%s: expected %d attempts got %d", tc.desc, tc.attempts, letters[0].Attempts))
+		assert.Equal(t, consumerID, letters[0].Consumer, fmt.Sprintf("%s: expected consumer %s got %s", tc.desc, consumerID, letters[0].Consumer))
+		pubsub.AssertCalled(t, "Publish", mock.Anything, dlqTopic, mock.MatchedBy(func(dl *messaging.Message) bool {
+			var letter consumers.DeadLetter
+			if err := json.Unmarshal(dl.Payload, &letter); err != nil {
+				return false
+			}
+			return dl.Protocol == consumers.DeadLetterProtocol && letter.ID == letters[0].ID
+		}))
+	}
+}
+
+func TestReplay(t *testing.T) {
+	c := &consumer{failures: 4}
+	handler, dlq, _, _ := start(t, c)
+
+	err := handler.Handle(&messaging.Message{
+		Channel: "channel",
+		Payload: []byte(`[{"bn":"base","n":"temp","v":21.5}]`),
+	})
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	letters := dlq.List()
+	require.Len(t, letters, 1, "expected a dead letter")
+	id := letters[0].ID
+
+	cases := []struct {
+		desc        string
+		id          string
+		deadLetters int
+		attempts    uint64
+		err         error
+	}{
+		{
+			desc:        "replay dead letter with failing consumer",
+			id:          id,
+			deadLetters: 1,
+			attempts:    4,
+			err:         errConsume,
+		},
+		{
+			desc:        "replay non-existing dead letter",
+			id:          "unknown",
+			deadLetters: 1,
+			err:         consumers.ErrDeadLetterNotFound,
+		},
+		{
+			desc:        "replay dead letter",
+			id:          id,
+			deadLetters: 0,
+			attempts:    4,
+		},
+		{
+			desc:        "replay removed dead letter",
+			id:          id,
+			deadLetters: 0,
+			err:         consumers.ErrDeadLetterNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		letter, err := dlq.Replay(context.Background(), tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.attempts, letter.Attempts, fmt.Sprintf("%s: expected %d attempts got %d", tc.desc, tc.attempts, letter.Attempts))
+		assert.Len(t, dlq.List(), tc.deadLetters, fmt.Sprintf("%s: expected %d dead letters", tc.desc, tc.deadLetters))
+	}
+}
+
+func TestReadDeadLetters(t *testing.T) {
+	_, dlq, dlqHandler, _ := start(t, &consumer{})
+
+	record := func(id, consumer string, attempts uint64, resolved bool) *messaging.Message {
+		payload, err := json.Marshal(map[string]interface{}{
+			"id":       id,
+			"consumer": consumer,
+			"attempts": attempts,
+			"resolved": resolved,
+			"message":  &messaging.Message{Channel: "channel"},
+		})
+		require.Nil(t, err, fmt.Sprintf("unexpected error marshaling record: %s", err))
+		return &messaging.Message{
+			Channel:  dlqTopic,
+			Subtopic: consumer,
+			Protocol: consumers.DeadLetterProtocol,
+			Payload:  payload,
+		}
+	}
+
+	cases := []struct {
+		desc     string
+		msg      *messaging.Message
+		ids      []string
+		attempts []uint64
+		err      bool
+	}{
+		{
+			desc:     "read dead letter",
+			msg:      record("1", consumerID, 3, false),
+			ids:      []string{"1"},
+			attempts: []uint64{3},
+		},
+		{
+			desc:     "read another dead letter",
+			msg:      record("2", consumerID, 3, false),
+			ids:      []string{"1", "2"},
+			attempts: []uint64{3, 3},
+		},
+		{
+			desc:     "read updated dead letter",
+			msg:      record("2", consumerID, 4, false),
+			ids:      []string{"1", "2"},
+			attempts: []uint64{3, 4},
+		},
+		{
+			desc:     "read stale dead letter",
+			msg:      record("2", consumerID, 3, false),
+			ids:      []string{"1", "2"},
+			attempts: []uint64{3, 4},
+		},
+		{
+			desc:     "read resolved dead letter",
+			msg:      record("1", consumerID, 3, true),
+			ids:      []string{"2"},
+			attempts: []uint64{4},
+		},
+		{
+			desc:     "read dead letter after it was resolved",
+			msg:      record("1", consumerID, 3, false),
+			ids:      []string{"2"},
+			attempts: []uint64{4},
+		},
+		{
+			desc:     "read dead letter of another consumer",
+			msg:      record("3", "other", 3, false),
+			ids:      []string{"2"},
+			attempts: []uint64{4},
+		},
+		{
+			desc:     "read malformed dead letter",
+			msg:      &messaging.Message{Subtopic: consumerID, Protocol: consumers.DeadLetterProtocol, Payload: []byte("malformed")},
+			ids:      []string{"2"},
+			attempts: []uint64{4},
+			err:      true,
+		},
+	}
+
+	for _, tc := range cases {
+		err := dlqHandler.Handle(tc.msg)
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: expected error %t got %s", tc.desc, tc.err, err))
+		letters := dlq.List()
+		require.Len(t, letters, len(tc.ids), fmt.Sprintf("%s: expected %d dead letters got %d", tc.desc, len(tc.ids), len(letters)))
+		for i, letter := range letters {
+			assert.Equal(t, tc.ids[i], letter.ID, fmt.Sprintf("%s: expected dead letter %s got %s", tc.desc, tc.ids[i], letter.ID))
+			assert.Equal(t, tc.attempts[i], letter.Attempts, fmt.Sprintf("%s: expected %d attempts got %d", tc.desc, tc.attempts[i], letter.Attempts))
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/absmach/magistrala/consumers"
+	"github.com/absmach/magistrala/pkg/errors"
+)
+
+// FailingMessageProtocol is the protocol of messages that fail to be replayed.
+const FailingMessageProtocol = "failing"
+
+var (
+	_ consumers.DeadLetterQueue = (*deadLetterQueueMock)(nil)
+
+	errReplay = errors.New("failed to consume message")
+)
+
+type deadLetterQueueMock struct {
+	mu      sync.Mutex
+	letters []consumers.DeadLetter
+}
+
+// NewDeadLetterQueue returns mock of the dead-letter queue containing the given
+// dead letters.
+func NewDeadLetterQueue(letters []consumers.DeadLetter) consumers.DeadLetterQueue {
+	return &deadLetterQueueMock{
+		letters: append([]consumers.DeadLetter{}, letters...),
+	}
+}
+
+func (dlq *deadLetterQueueMock) List() []consumers.DeadLetter {
+	dlq.mu.Lock()
+	defer dlq.mu.Unlock()
+
+	return append([]consumers.DeadLetter{}, dlq.letters...)
+}
+
+func (dlq *deadLetterQueueMock) View(id string) (consumers.DeadLetter, error) {
+	dlq.mu.Lock()
+	defer dlq.mu.Unlock()
+
+	for _, letter := range dlq.letters {
+		if letter.ID == id {
+			return letter, nil
+		}
+	}
+
+	return consumers.DeadLetter{}, consumers.ErrDeadLetterNotFound
+}
+
+func (dlq *deadLetterQueueMock) Replay(_ context.Context, id string) (consumers.DeadLetter, error) {
+	dlq.mu.Lock()
+	defer dlq.mu.Unlock()
+
+	for i, letter := range dlq.letters {
+		if letter.ID != id {
+			continue
+		}
+		if letter.Message.GetProtocol() == FailingMessageProtocol {
+			dlq.letters[i].Attempts++
+			return dlq.letters[i], errReplay
+		}
+		dlq.letters = append(dlq.letters[:i], dlq.letters[i+1:]...)
+		return letter, nil
+	}
+
+	return consumers.DeadLetter{}, consumers.ErrDeadLetterNotFound
+}
+
+func (dlq *deadLetterQueueMock) Remove(id string) error {
+	dlq.mu.Lock()
+	defer dlq.mu.Unlock()
+
+	for i, letter := range dlq.letters {
+		if letter.ID == id {
+			dlq.letters = append(dlq.letters[:i], dlq.letters[i+1:]...)
+			return nil
+		}
+	}
+
+	return consumers.ErrDeadLetterNotFound
+}
@@ -18,9 +18,9 @@ func createSubscriptionEndpoint(svc notifiers.Service) endpoint.Endpoint {
 		if err := req.validate(); err != nil {
 			return createSubRes{}, errors.Wrap(apiutil.ErrValidation, err)
 		}
-		sub := notifiers.Subscription{
-			Contact: req.Contact,
-			Topic:   req.Topic,
+		sub, err := req.subscription()
+		if err != nil {
+			return createSubRes{}, errors.Wrap(apiutil.ErrValidation, err)
 		}
 		id, err := svc.CreateSubscription(ctx, req.token, sub)
 		if err != nil {
@@ -44,13 +44,7 @@ func viewSubscriptionEndpint(svc notifiers.Service) endpoint.Endpoint {
 		if err != nil {
 			return viewSubRes{}, err
 		}
-		res := viewSubRes{
-			ID:      sub.ID,
-			OwnerID: sub.OwnerID,
-			Contact: sub.Contact,
-			Topic:   sub.Topic,
-		}
-		return res, nil
+		return toViewSubRes(sub), nil
 	}
 }
 
@@ -76,13 +70,7 @@ func listSubscriptionsEndpoint(svc notifiers.Service) endpoint.Endpoint {
 			Total:  page.Total,
 		}
 		for _, sub := range page.Subscriptions {
-			r := viewSubRes{
-				ID:      sub.ID,
-				OwnerID: sub.OwnerID,
-				Contact: sub.Contact,
-				Topic:   sub.Topic,
-			}
-			res.Subscriptions = append(res.Subscriptions, r)
+			res.Subscriptions = append(res.Subscriptions, toViewSubRes(sub))
 		}
 
 		return res, nil
@@ -101,3 +89,21 @@ func deleteSubscriptionEndpint(svc notifiers.Service) endpoint.Endpoint {
 		return removeSubRes{}, nil
 	}
 }
+
+func toViewSubRes(sub notifiers.Subscription) viewSubRes {
+	res := viewSubRes{
+		ID:        sub.ID,
+		OwnerID:   sub.OwnerID,
+		Contact:   sub.Contact,
+		Topic:     sub.Topic,
+		Condition: sub.Condition,
+	}
+	if sub.Interval > 0 {
+		res.Interval = sub.Interval.String()
+	}
+	if sub.Digest > 0 {
+		res.Digest = sub.Digest.String()
+	}
+
+	return res
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/absmach/magistrala"
 	authmocks "github.com/absmach/magistrala/auth/mocks"
+	cmocks "github.com/absmach/magistrala/consumers/mocks"
 	"github.com/absmach/magistrala/consumers/notifiers"
 	httpapi "github.com/absmach/magistrala/consumers/notifiers/api"
 	"github.com/absmach/magistrala/consumers/notifiers/mocks"
@@ -78,7 +79,7 @@ func newService() (notifiers.Service, *authmocks.AuthClient) {
 
 func newServer(svc notifiers.Service) *httptest.Server {
 	logger := mglog.NewMock()
-	mux := httpapi.MakeHandler(svc, logger, instanceID)
+	mux := httpapi.MakeHandler(svc, cmocks.NewDeadLetterQueue(nil), logger, instanceID)
 	return httptest.NewServer(mux)
 }
 
@@ -104,6 +105,10 @@ func TestCreate(t *testing.T) {
 
 	emptyTopic := toJSON(notifiers.Subscription{Contact: contact1})
 	emptyContact := toJSON(notifiers.Subscription{Topic: "topic123"})
+	filtered := `{"topic":"filtered","contact":"email1@example.com","condition":{"name":"temp","operator":"gt","value":30},"interval":"10m"}`
+	invalidCond := `{"topic":"invalid","contact":"email1@example.com","condition":{"name":"temp","operator":"invalid"}}`
+	invalidInterval := `{"topic":"invalid","contact":"email1@example.com","interval":"invalid"}`
+	intervalAndDigest := `{"topic":"invalid","contact":"email1@example.com","interval":"1m","digest":"1h"}`
 
 	cases := []struct {
 		desc        string
@@ -145,6 +150,38 @@ func TestCreate(t *testing.T) {
 			status:      http.StatusBadRequest,
 			location:    "",
 		},
+		{
+			desc:        "add with condition and interval",
+			req:         filtered,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			location:    fmt.Sprintf("/subscriptions/%s%012d", uuid.Prefix, 3),
+		},
+		{
+			desc:        "add with invalid condition",
+			req:         invalidCond,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
+		{
+			desc:        "add with invalid interval",
+			req:         invalidInterval,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
+		{
+			desc:        "add with both interval and digest",
+			req:         intervalAndDigest,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
 		{
 			desc:        "add with invalid auth token",
 			req:         data,
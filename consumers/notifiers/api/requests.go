@@ -3,12 +3,22 @@
 
 package api
 
-import "github.com/absmach/magistrala/internal/apiutil"
+import (
+	"time"
+
+	"github.com/absmach/magistrala/consumers/notifiers"
+	"github.com/absmach/magistrala/internal/apiutil"
+	"github.com/absmach/magistrala/pkg/errors"
+	svcerr "github.com/absmach/magistrala/pkg/errors/service"
+)
 
 type createSubReq struct {
-	token   string
-	Topic   string `json:"topic,omitempty"`
-	Contact string `json:"contact,omitempty"`
+	token     string
+	Topic     string               `json:"topic,omitempty"`
+	Contact   string               `json:"contact,omitempty"`
+	Condition *notifiers.Condition `json:"condition,omitempty"`
+	Interval  string               `json:"interval,omitempty"`
+	Digest    string               `json:"digest,omitempty"`
 }
 
 func (req createSubReq) validate() error {
@@ -21,9 +31,35 @@ func (req createSubReq) validate() error {
 	if req.Contact == "" {
 		return apiutil.ErrInvalidContact
 	}
+	if _, err := req.subscription(); err != nil {
+		return errors.Wrap(svcerr.ErrMalformedEntity, err)
+	}
 	return nil
 }
 
+// subscription returns the requested subscription. Interval and digest
+// are durations, e.g. "30s" or "1h".
+func (req createSubReq) subscription() (notifiers.Subscription, error) {
+	sub := notifiers.Subscription{
+		Contact:   req.Contact,
+		Topic:     req.Topic,
+		Condition: req.Condition,
+	}
+	var err error
+	if req.Interval != "" {
+		if sub.Interval, err = time.ParseDuration(req.Interval); err != nil {
+			return notifiers.Subscription{}, errors.Wrap(notifiers.ErrInvalidSchedule, err)
+		}
+	}
+	if req.Digest != "" {
+		if sub.Digest, err = time.ParseDuration(req.Digest); err != nil {
+			return notifiers.Subscription{}, errors.Wrap(notifiers.ErrInvalidSchedule, err)
+		}
+	}
+
+	return sub, sub.Validate()
+}
+
 type subReq struct {
 	token string
 	id    string
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/absmach/magistrala"
+	"github.com/absmach/magistrala/consumers/notifiers"
 )
 
 var (
@@ -36,10 +37,13 @@ func (res createSubRes) Empty() bool {
 }
 
 type viewSubRes struct {
-	ID      string `json:"id"`
-	OwnerID string `json:"owner_id"`
-	Contact string `json:"contact"`
-	Topic   string `json:"topic"`
+	ID        string               `json:"id"`
+	OwnerID   string               `json:"owner_id"`
+	Contact   string               `json:"contact"`
+	Topic     string               `json:"topic"`
+	Condition *notifiers.Condition `json:"condition,omitempty"`
+	Interval  string               `json:"interval,omitempty"`
+	Digest    string               `json:"digest,omitempty"`
 }
 
 func (res viewSubRes) Code() int {
@@ -11,6 +11,8 @@ import (
 	"strings"
 
 	"github.com/absmach/magistrala"
+	"github.com/absmach/magistrala/consumers"
+	dlqapi "github.com/absmach/magistrala/consumers/api"
 	"github.com/absmach/magistrala/consumers/notifiers"
 	"github.com/absmach/magistrala/internal/apiutil"
 	"github.com/absmach/magistrala/pkg/errors"
@@ -32,7 +34,7 @@ const (
 )
 
 // MakeHandler returns a HTTP handler for API endpoints.
-func MakeHandler(svc notifiers.Service, logger *slog.Logger, instanceID string) http.Handler {
+func MakeHandler(svc notifiers.Service, dlq consumers.DeadLetterQueue, logger *slog.Logger, instanceID string) http.Handler {
 	opts := []kithttp.ServerOption{
 		kithttp.ServerErrorEncoder(apiutil.LoggingErrorEncoder(logger, encodeError)),
 	}
@@ -76,6 +78,7 @@ func MakeHandler(svc notifiers.Service, logger *slog.Logger, instanceID string)
 		), "delete").ServeHTTP)
 	})
 
+	mux.Mount("/dlq", dlqapi.MakeHandler(dlq, logger))
 	mux.Get("/health", magistrala.Health("notifier", instanceID))
 	mux.Handle("/metrics", promhttp.Handler())
 
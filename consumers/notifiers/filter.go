@@ -0,0 +1,98 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package notifiers
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/absmach/magistrala/pkg/conditions"
+	"github.com/absmach/magistrala/pkg/errors"
+)
+
+var (
+	// ErrInvalidCondition indicates invalid subscription condition.
+	ErrInvalidCondition = errors.New("invalid subscription condition")
+
+	// ErrInvalidSchedule indicates invalid subscription interval or digest.
+	ErrInvalidSchedule = errors.New("invalid subscription interval or digest")
+)
+
+// Condition limits the notifications to the messages having the numeric
+// value of the named field which satisfies the comparison operator and the
+// value. For
+// SenML payloads the name is the record name including the base name. For
+// JSON payloads the name is the payload field, with nested fields joined
+// using "/", e.g. "sensor/temp".
+type Condition struct {
+	Name     string  `json:"name"`
+	Operator string  `json:"operator"`
+	Value    float64 `json:"value"`
+}
+
+// Validate returns an error if the condition is not valid.
+func (c Condition) Validate() error {
+	if c.Name == "" || !conditions.Valid(c.Operator) {
+		return ErrInvalidCondition
+	}
+
+	return nil
+}
+
+// Matches reports whether any value of the named field in the payload
+// satisfies the condition. Payloads which are not SenML or JSON don't match.
+func (c Condition) Matches(payload []byte) bool {
+	for _, v := range values(payload, c.Name) {
+		if conditions.Compare(c.Operator, v, c.Value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type senmlRecord struct {
+	BaseName string   `json:"bn"`
+	Name     string   `json:"n"`
+	Value    *float64 `json:"v"`
+}
+
+// values returns the numeric values of the named field in the payload.
+func values(payload []byte, name string) []float64 {
+	var pack []senmlRecord
+	if err := json.Unmarshal(payload, &pack); err == nil {
+		var ret []float64
+		var bn string
+		for _, r := range pack {
+			// Base name applies to all the following records.
+			if r.BaseName != "" {
+				bn = r.BaseName
+			}
+			if bn+r.Name == name && r.Value != nil {
+				ret = append(ret, *r.Value)
+			}
+		}
+		return ret
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(payload, &obj); err != nil {
+		return nil
+	}
+	var val interface{} = obj
+	for _, k := range strings.Split(name, conditions.FieldSep) {
+		m, ok := val.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if val, ok = m[k]; !ok {
+			return nil
+		}
+	}
+	if v, ok := val.(float64); ok {
+		return []float64{v}
+	}
+
+	return nil
+}
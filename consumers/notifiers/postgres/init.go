@@ -23,6 +23,21 @@ func Migration() *migrate.MemoryMigrationSource {
 					"DROP TABLE IF EXISTS subscriptions",
 				},
 			},
+			{
+				Id: "subscriptions_2",
+				Up: []string{
+					`ALTER TABLE subscriptions
+                        ADD COLUMN IF NOT EXISTS condition JSONB,
+                        ADD COLUMN IF NOT EXISTS min_interval BIGINT NOT NULL DEFAULT 0,
+                        ADD COLUMN IF NOT EXISTS digest BIGINT NOT NULL DEFAULT 0`,
+				},
+				Down: []string{
+					`ALTER TABLE subscriptions
+                        DROP COLUMN IF EXISTS condition,
+                        DROP COLUMN IF EXISTS min_interval,
+                        DROP COLUMN IF EXISTS digest`,
+				},
+			},
 		},
 	}
 }
@@ -6,8 +6,10 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/absmach/magistrala/consumers/notifiers"
 	"github.com/absmach/magistrala/pkg/errors"
@@ -29,13 +31,12 @@ func New(db Database) notifiers.SubscriptionsRepository {
 }
 
 func (repo subscriptionsRepo) Save(ctx context.Context, sub notifiers.Subscription) (string, error) {
-	q := `INSERT INTO subscriptions (id, owner_id, contact, topic) VALUES (:id, :owner_id, :contact, :topic) RETURNING id`
+	q := `INSERT INTO subscriptions (id, owner_id, contact, topic, condition, min_interval, digest)
+		VALUES (:id, :owner_id, :contact, :topic, :condition, :min_interval, :digest) RETURNING id`
 
-	dbSub := dbSubscription{
-		ID:      sub.ID,
-		OwnerID: sub.OwnerID,
-		Contact: sub.Contact,
-		Topic:   sub.Topic,
+	dbSub, err := toDBSub(sub)
+	if err != nil {
+		return "", errors.Wrap(errors.ErrCreateEntity, err)
 	}
 
 	row, err := repo.db.NamedQueryContext(ctx, q, dbSub)
@@ -51,7 +52,7 @@ func (repo subscriptionsRepo) Save(ctx context.Context, sub notifiers.Subscripti
 }
 
 func (repo subscriptionsRepo) Retrieve(ctx context.Context, id string) (notifiers.Subscription, error) {
-	q := `SELECT id, owner_id, contact, topic FROM subscriptions WHERE id = $1`
+	q := `SELECT id, owner_id, contact, topic, condition, min_interval, digest FROM subscriptions WHERE id = $1`
 	sub := dbSubscription{}
 	if err := repo.db.QueryRowxContext(ctx, q, id).StructScan(&sub); err != nil {
 		if err == sql.ErrNoRows {
@@ -60,11 +61,11 @@ func (repo subscriptionsRepo) Retrieve(ctx context.Context, id string) (notifier
 		return notifiers.Subscription{}, errors.Wrap(errors.ErrViewEntity, err)
 	}
 
-	return fromDBSub(sub), nil
+	return fromDBSub(sub)
 }
 
 func (repo subscriptionsRepo) RetrieveAll(ctx context.Context, pm notifiers.PageMetadata) (notifiers.Page, error) {
-	q := `SELECT id, owner_id, contact, topic FROM subscriptions`
+	q := `SELECT id, owner_id, contact, topic, condition, min_interval, digest FROM subscriptions`
 	args := make(map[string]interface{})
 	if pm.Topic != "" {
 		args["topic"] = pm.Topic
@@ -100,7 +101,11 @@ func (repo subscriptionsRepo) RetrieveAll(ctx context.Context, pm notifiers.Page
 		if err := rows.StructScan(&sub); err != nil {
 			return notifiers.Page{}, errors.Wrap(errors.ErrViewEntity, err)
 		}
-		subs = append(subs, fromDBSub(sub))
+		s, err := fromDBSub(sub)
+		if err != nil {
+			return notifiers.Page{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		subs = append(subs, s)
 	}
 
 	if len(subs) == 0 {
@@ -147,17 +152,51 @@ func total(ctx context.Context, db Database, query string, params interface{}) (
 }
 
 type dbSubscription struct {
-	ID      string `db:"id"`
-	OwnerID string `db:"owner_id"`
-	Contact string `db:"contact"`
-	Topic   string `db:"topic"`
+	ID        string `db:"id"`
+	OwnerID   string `db:"owner_id"`
+	Contact   string `db:"contact"`
+	Topic     string `db:"topic"`
+	Condition []byte `db:"condition"`
+	Interval  int64  `db:"min_interval"`
+	Digest    int64  `db:"digest"`
+}
+
+func toDBSub(sub notifiers.Subscription) (dbSubscription, error) {
+	dbSub := dbSubscription{
+		ID:       sub.ID,
+		OwnerID:  sub.OwnerID,
+		Contact:  sub.Contact,
+		Topic:    sub.Topic,
+		Interval: int64(sub.Interval),
+		Digest:   int64(sub.Digest),
+	}
+	if sub.Condition != nil {
+		cond, err := json.Marshal(sub.Condition)
+		if err != nil {
+			return dbSubscription{}, err
+		}
+		dbSub.Condition = cond
+	}
+
+	return dbSub, nil
 }
 
-func fromDBSub(sub dbSubscription) notifiers.Subscription {
-	return notifiers.Subscription{
-		ID:      sub.ID,
-		OwnerID: sub.OwnerID,
-		Contact: sub.Contact,
-		Topic:   sub.Topic,
+func fromDBSub(sub dbSubscription) (notifiers.Subscription, error) {
+	ret := notifiers.Subscription{
+		ID:       sub.ID,
+		OwnerID:  sub.OwnerID,
+		Contact:  sub.Contact,
+		Topic:    sub.Topic,
+		Interval: time.Duration(sub.Interval),
+		Digest:   time.Duration(sub.Digest),
+	}
+	if len(sub.Condition) > 0 {
+		var cond notifiers.Condition
+		if err := json.Unmarshal(sub.Condition, &cond); err != nil {
+			return notifiers.Subscription{}, err
+		}
+		ret.Condition = &cond
 	}
+
+	return ret, nil
 }
@@ -7,9 +7,11 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/absmach/magistrala/consumers/notifiers"
 	"github.com/absmach/magistrala/consumers/notifiers/postgres"
+	"github.com/absmach/magistrala/pkg/conditions"
 	"github.com/absmach/magistrala/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -78,10 +80,12 @@ func TestView(t *testing.T) {
 	require.Nil(t, err, fmt.Sprintf("got an error creating id: %s", err))
 
 	sub := notifiers.Subscription{
-		OwnerID: id,
-		ID:      id,
-		Contact: owner,
-		Topic:   "view.subtopic",
+		OwnerID:   id,
+		ID:        id,
+		Contact:   owner,
+		Topic:     "view.subtopic",
+		Condition: &notifiers.Condition{Name: "temp", Operator: conditions.GreaterThan, Value: 30},
+		Interval:  time.Minute,
 	}
 
 	ret, err := repo.Save(context.Background(), sub)
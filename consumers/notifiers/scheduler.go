@@ -0,0 +1,155 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package notifiers
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/absmach/magistrala/pkg/messaging"
+)
+
+const (
+	// DigestProtocol is the protocol of the digest summary messages.
+	DigestProtocol = "digest"
+
+	// maxDigestSize limits the number of messages kept in the summary. The
+	// summary count includes the messages received after the limit.
+	maxDigestSize = 100
+)
+
+// Digest is the payload of the summary message.
+type Digest struct {
+	Topic    string          `json:"topic"`
+	Count    int             `json:"count"`
+	Messages []DigestMessage `json:"messages"`
+}
+
+// DigestMessage is the message included into the summary.
+type DigestMessage struct {
+	Publisher string          `json:"publisher,omitempty"`
+	Protocol  string          `json:"protocol,omitempty"`
+	Created   int64           `json:"created"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+type digest struct {
+	first *messaging.Message
+	Digest
+}
+
+// scheduler keeps the time of the last notification of the rate limited
+// subscriptions and the pending digests.
+type scheduler struct {
+	mu      sync.Mutex
+	last    map[string]time.Time
+	digests map[string]*digest
+}
+
+func newScheduler() *scheduler {
+	return &scheduler{
+		last:    make(map[string]time.Time),
+		digests: make(map[string]*digest),
+	}
+}
+
+// due reports whether the subscriber can be notified at the given time.
+func (s *scheduler) due(sub Subscription, now time.Time) bool {
+	if sub.Interval <= 0 {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last, ok := s.last[sub.ID]
+
+	return !ok || now.Sub(last) >= sub.Interval
+}
+
+// sent records the notification of the rate limited subscribers.
+func (s *scheduler) sent(subs []Subscription, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sub := range subs {
+		if sub.Interval > 0 {
+			s.last[sub.ID] = now
+		}
+	}
+}
+
+// collect adds the message to the subscription digest. The first message
+// of the digest starts the period after which the summary is sent.
+func (s *scheduler) collect(sub Subscription, msg *messaging.Message, send func(Subscription, *messaging.Message)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.digests[sub.ID]
+	if !ok {
+		d = &digest{
+			first:  msg,
+			Digest: Digest{Topic: sub.Topic},
+		}
+		s.digests[sub.ID] = d
+		time.AfterFunc(sub.Digest, func() {
+			if msg, ok := s.flush(sub.ID); ok {
+				send(sub, msg)
+			}
+		})
+	}
+
+	d.Count++
+	if len(d.Messages) < maxDigestSize {
+		d.Messages = append(d.Messages, DigestMessage{
+			Publisher: msg.GetPublisher(),
+			Protocol:  msg.GetProtocol(),
+			Created:   msg.GetCreated(),
+			Payload:   rawPayload(msg.GetPayload()),
+		})
+	}
+}
+
+// flush removes the subscription digest and returns its summary message.
+func (s *scheduler) flush(id string) (*messaging.Message, bool) {
+	s.mu.Lock()
+	d, ok := s.digests[id]
+	delete(s.digests, id)
+	s.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	payload, err := json.Marshal(d.Digest)
+	if err != nil {
+		return nil, false
+	}
+
+	return &messaging.Message{
+		Channel:  d.first.GetChannel(),
+		Subtopic: d.first.GetSubtopic(),
+		Protocol: DigestProtocol,
+		Payload:  payload,
+		Created:  time.Now().UnixNano(),
+	}, true
+}
+
+// forget removes the state of the removed subscription.
+func (s *scheduler) forget(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.last, id)
+	delete(s.digests, id)
+}
+
+// rawPayload returns JSON payload as is and any other payload as JSON string.
+func rawPayload(payload []byte) json.RawMessage {
+	if json.Valid(payload) {
+		return payload
+	}
+	ret, _ := json.Marshal(string(payload))
+
+	return ret
+}
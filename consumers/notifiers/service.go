@@ -6,6 +6,7 @@ package notifiers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/absmach/magistrala"
 	"github.com/absmach/magistrala/consumers"
@@ -45,6 +46,7 @@ type notifierService struct {
 	notifier Notifier
 	errCh    chan error
 	from     string
+	sched    *scheduler
 }
 
 // New instantiates the subscriptions service implementation.
@@ -56,6 +58,7 @@ func New(auth magistrala.AuthServiceClient, subs SubscriptionsRepository, idp ma
 		notifier: notifier,
 		errCh:    make(chan error, 1),
 		from:     from,
+		sched:    newScheduler(),
 	}
 }
 
@@ -94,7 +97,12 @@ func (ns *notifierService) RemoveSubscription(ctx context.Context, token, id str
 		return err
 	}
 
-	return ns.subs.Remove(ctx, id)
+	if err := ns.subs.Remove(ctx, id); err != nil {
+		return err
+	}
+	ns.sched.forget(id)
+
+	return nil
 }
 
 func (ns *notifierService) ConsumeBlocking(ctx context.Context, message interface{}) error {
@@ -102,32 +110,8 @@ func (ns *notifierService) ConsumeBlocking(ctx context.Context, message interfac
 	if !ok {
 		return ErrMessage
 	}
-	topic := msg.GetChannel()
-	if msg.GetSubtopic() != "" {
-		topic = fmt.Sprintf("%s.%s", msg.GetChannel(), msg.GetSubtopic())
-	}
-	pm := PageMetadata{
-		Topic:  topic,
-		Offset: 0,
-		Limit:  -1,
-	}
-	page, err := ns.subs.RetrieveAll(ctx, pm)
-	if err != nil {
-		return err
-	}
-
-	var to []string
-	for _, sub := range page.Subscriptions {
-		to = append(to, sub.Contact)
-	}
-	if len(to) > 0 {
-		err := ns.notifier.Notify(ns.from, to, msg)
-		if err != nil {
-			return errors.Wrap(ErrNotify, err)
-		}
-	}
 
-	return nil
+	return ns.notify(ctx, msg)
 }
 
 func (ns *notifierService) ConsumeAsync(ctx context.Context, message interface{}) {
@@ -136,6 +120,19 @@ func (ns *notifierService) ConsumeAsync(ctx context.Context, message interface{}
 		ns.errCh <- ErrMessage
 		return
 	}
+	if err := ns.notify(ctx, msg); err != nil {
+		ns.errCh <- err
+	}
+}
+
+func (ns *notifierService) Errors() <-chan error {
+	return ns.errCh
+}
+
+// notify sends the message to the topic subscribers whose condition is met.
+// Rate limited subscribers are skipped and the digest subscribers receive
+// the message in the next summary.
+func (ns *notifierService) notify(ctx context.Context, msg *messaging.Message) error {
 	topic := msg.GetChannel()
 	if msg.GetSubtopic() != "" {
 		topic = fmt.Sprintf("%s.%s", msg.GetChannel(), msg.GetSubtopic())
@@ -147,21 +144,43 @@ func (ns *notifierService) ConsumeAsync(ctx context.Context, message interface{}
 	}
 	page, err := ns.subs.RetrieveAll(ctx, pm)
 	if err != nil {
-		ns.errCh <- err
-		return
+		return err
 	}
 
+	now := time.Now()
 	var to []string
+	var subs []Subscription
 	for _, sub := range page.Subscriptions {
+		if sub.Condition != nil && !sub.Condition.Matches(msg.GetPayload()) {
+			continue
+		}
+		if sub.Digest > 0 {
+			ns.sched.collect(sub, msg, ns.sendDigest)
+			continue
+		}
+		if !ns.sched.due(sub, now) {
+			continue
+		}
 		to = append(to, sub.Contact)
+		subs = append(subs, sub)
 	}
 	if len(to) > 0 {
 		if err := ns.notifier.Notify(ns.from, to, msg); err != nil {
-			ns.errCh <- errors.Wrap(ErrNotify, err)
+			return errors.Wrap(ErrNotify, err)
 		}
+		ns.sched.sent(subs, now)
 	}
+
+	return nil
 }
 
-func (ns *notifierService) Errors() <-chan error {
-	return ns.errCh
+// sendDigest sends the summary to the subscriber. It's called once the
+// digest period expires, so the errors are reported using errors channel.
+func (ns *notifierService) sendDigest(sub Subscription, msg *messaging.Message) {
+	if err := ns.notifier.Notify(ns.from, []string{sub.Contact}, msg); err != nil {
+		select {
+		case ns.errCh <- errors.Wrap(ErrNotify, err):
+		default:
+		}
+	}
 }
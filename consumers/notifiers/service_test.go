@@ -5,14 +5,18 @@ package notifiers_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/absmach/magistrala"
 	authmocks "github.com/absmach/magistrala/auth/mocks"
 	"github.com/absmach/magistrala/consumers/notifiers"
 	"github.com/absmach/magistrala/consumers/notifiers/mocks"
 	"github.com/absmach/magistrala/internal/testsutil"
+	"github.com/absmach/magistrala/pkg/conditions"
 	"github.com/absmach/magistrala/pkg/errors"
 	svcerr "github.com/absmach/magistrala/pkg/errors/service"
 	"github.com/absmach/magistrala/pkg/messaging"
@@ -350,3 +354,100 @@ func TestConsume(t *testing.T) {
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
+
+type recorder struct {
+	mu   sync.Mutex
+	msgs map[string][]*messaging.Message
+}
+
+func (r *recorder) Notify(_ string, to []string, msg *messaging.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range to {
+		r.msgs[c] = append(r.msgs[c], msg)
+	}
+	return nil
+}
+
+func (r *recorder) received(contact string) []*messaging.Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.msgs[contact]
+}
+
+func TestConsumeFiltered(t *testing.T) {
+	rec := &recorder{msgs: make(map[string][]*messaging.Message)}
+	repo := mocks.NewRepo(make(map[string]notifiers.Subscription))
+	auth := new(authmocks.AuthClient)
+	svc := notifiers.New(auth, repo, uuid.NewMock(), rec, "exampleFrom")
+
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: exampleUser1}).Return(&magistrala.IdentityRes{Id: validID}, nil)
+	defer repoCall.Unset()
+
+	subs := []notifiers.Subscription{
+		{Contact: "all@example.com", Topic: "topic"},
+		{Contact: "hot@example.com", Topic: "topic", Condition: &notifiers.Condition{Name: "dev:temp", Operator: conditions.GreaterThan, Value: 30}},
+		{Contact: "nested@example.com", Topic: "topic", Condition: &notifiers.Condition{Name: "sensor/hum", Operator: conditions.LowerEqual, Value: 20}},
+		{Contact: "limited@example.com", Topic: "topic", Interval: time.Hour},
+		{Contact: "digest@example.com", Topic: "topic", Digest: 50 * time.Millisecond},
+	}
+	for _, sub := range subs {
+		_, err := svc.CreateSubscription(context.Background(), exampleUser1, sub)
+		require.Nil(t, err, fmt.Sprintf("saving subscription %s must succeed: %s", sub.Contact, err))
+	}
+
+	payloads := []string{
+		`[{"bn":"dev:","n":"temp","v":25}]`,
+		`[{"bn":"dev:","n":"hum","v":40},{"n":"temp","v":35}]`,
+		`{"sensor":{"hum":15}}`,
+		`not json`,
+	}
+	for _, p := range payloads {
+		msg := &messaging.Message{Channel: "topic", Payload: []byte(p)}
+		err := svc.ConsumeBlocking(context.Background(), msg)
+		assert.Nil(t, err, fmt.Sprintf("consuming %s unexpected error: %s", p, err))
+	}
+
+	cases := []struct {
+		desc    string
+		contact string
+		count   int
+	}{
+		{
+			desc:    "subscription without filters",
+			contact: "all@example.com",
+			count:   len(payloads),
+		},
+		{
+			desc:    "subscription with SenML condition",
+			contact: "hot@example.com",
+			count:   1,
+		},
+		{
+			desc:    "subscription with JSON condition",
+			contact: "nested@example.com",
+			count:   1,
+		},
+		{
+			desc:    "rate limited subscription",
+			contact: "limited@example.com",
+			count:   1,
+		},
+	}
+
+	for _, tc := range cases {
+		count := len(rec.received(tc.contact))
+		assert.Equal(t, tc.count, count, fmt.Sprintf("%s: expected %d notifications got %d", tc.desc, tc.count, count))
+	}
+
+	assert.Empty(t, rec.received("digest@example.com"), "digest must not be sent before the digest period expires")
+	assert.Eventually(t, func() bool { return len(rec.received("digest@example.com")) == 1 }, time.Second, 10*time.Millisecond, "digest must be sent once the digest period expires")
+
+	msg := rec.received("digest@example.com")[0]
+	assert.Equal(t, notifiers.DigestProtocol, msg.Protocol, fmt.Sprintf("expected digest protocol got %s", msg.Protocol))
+	var digest notifiers.Digest
+	err := json.Unmarshal(msg.Payload, &digest)
+	assert.Nil(t, err, fmt.Sprintf("decoding digest unexpected error: %s", err))
+	assert.Equal(t, len(payloads), digest.Count, fmt.Sprintf("expected %d digest messages got %d", len(payloads), digest.Count))
+	assert.Len(t, digest.Messages, len(payloads), fmt.Sprintf("expected %d digest messages got %d", len(payloads), len(digest.Messages)))
+}
@@ -3,7 +3,10 @@
 
 package notifiers
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Subscription represents a user Subscription.
 type Subscription struct {
@@ -11,6 +14,30 @@ type Subscription struct {
 	OwnerID string
 	Contact string
 	Topic   string
+	// Condition, if set, limits the notifications to the messages
+	// satisfying the condition.
+	Condition *Condition `json:"condition,omitempty"`
+	// Interval is the minimum time between two notifications. Messages
+	// received in between are not notified.
+	Interval time.Duration `json:"interval,omitempty"`
+	// Digest, if set, batches the messages into a summary notification
+	// sent once per digest period.
+	Digest time.Duration `json:"digest,omitempty"`
+}
+
+// Validate returns an error if the subscription condition or schedule is
+// not valid. Interval and digest are mutually exclusive.
+func (sub Subscription) Validate() error {
+	if sub.Condition != nil {
+		if err := sub.Condition.Validate(); err != nil {
+			return err
+		}
+	}
+	if sub.Interval < 0 || sub.Digest < 0 || (sub.Interval > 0 && sub.Digest > 0) {
+		return ErrInvalidSchedule
+	}
+
+	return nil
 }
 
 // Page represents page metadata with content.
@@ -0,0 +1,18 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import "time"
+
+// Config represents webhook notifier configuration. Empty secret disables
+// the payload signing and empty template sends the message as JSON.
+type Config struct {
+	Secret          string        `env:"MG_WEBHOOK_SECRET"           envDefault:""`
+	Template        string        `env:"MG_WEBHOOK_TEMPLATE"         envDefault:""`
+	ContentType     string        `env:"MG_WEBHOOK_CONTENT_TYPE"     envDefault:"application/json"`
+	Timeout         time.Duration `env:"MG_WEBHOOK_TIMEOUT"          envDefault:"5s"`
+	MaxRetries      uint64        `env:"MG_WEBHOOK_MAX_RETRIES"      envDefault:"3"`
+	InitialInterval time.Duration `env:"MG_WEBHOOK_INITIAL_INTERVAL" envDefault:"500ms"`
+	MaxInterval     time.Duration `env:"MG_WEBHOOK_MAX_INTERVAL"     envDefault:"10s"`
+}
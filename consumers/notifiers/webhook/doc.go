@@ -0,0 +1,6 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package webhook contains the domain concept definitions needed to
+// support Magistrala HTTP webhook notifications.
+package webhook
@@ -0,0 +1,197 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/notifiers"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/pkg/messaging"
+	"github.com/cenkalti/backoff/v4"
+)
+
+const (
+	// SignatureHeader contains the hex encoded HMAC-SHA256 of the timestamp
+	// and the request body joined with ".", prefixed with "sha256=".
+	SignatureHeader = "X-Magistrala-Signature"
+	// TimestampHeader contains the Unix time of the request in seconds.
+	TimestampHeader = "X-Magistrala-Timestamp"
+
+	signaturePrefix = "sha256="
+)
+
+var (
+	errParseTemplate = errors.New("failed to parse webhook template")
+	errExecTemplate  = errors.New("failed to execute webhook template")
+	errInvalidURL    = errors.New("invalid webhook URL")
+	errStatus        = errors.New("unexpected webhook response status")
+)
+
+var _ notifiers.Notifier = (*notifier)(nil)
+
+// data is passed to the webhook template.
+type data struct {
+	From      string
+	Channel   string
+	Subtopic  string
+	Publisher string
+	Protocol  string
+	Payload   string
+	Created   int64
+}
+
+type notifier struct {
+	cfg    Config
+	tmpl   *template.Template
+	client *http.Client
+}
+
+// New instantiates webhook message notifier. Subscription contacts are the
+// URLs the notifications are sent to using HTTP POST requests.
+func New(cfg Config) (notifiers.Notifier, error) {
+	n := &notifier{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+	if cfg.Template != "" {
+		tmpl, err := template.ParseFiles(cfg.Template)
+		if err != nil {
+			return nil, errors.Wrap(errParseTemplate, err)
+		}
+		n.tmpl = tmpl
+	}
+
+	return n, nil
+}
+
+func (n *notifier) Notify(from string, to []string, msg *messaging.Message) error {
+	body, err := n.body(from, msg)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, u := range to {
+		if err := n.send(u, body); err != nil {
+			errs = errors.Wrap(errors.Wrap(errors.New(u), err), errs)
+		}
+	}
+
+	return errs
+}
+
+func (n *notifier) body(from string, msg *messaging.Message) ([]byte, error) {
+	if n.tmpl == nil {
+		payload := json.RawMessage(msg.GetPayload())
+		if !json.Valid(payload) {
+			// Non-JSON payload is sent as a JSON string.
+			var err error
+			if payload, err = json.Marshal(string(msg.GetPayload())); err != nil {
+				return nil, err
+			}
+		}
+		return json.Marshal(map[string]interface{}{
+			"from":      from,
+			"channel":   msg.GetChannel(),
+			"subtopic":  msg.GetSubtopic(),
+			"publisher": msg.GetPublisher(),
+			"protocol":  msg.GetProtocol(),
+			"payload":   payload,
+			"created":   msg.GetCreated(),
+		})
+	}
+
+	d := data{
+		From:      from,
+		Channel:   msg.GetChannel(),
+		Subtopic:  msg.GetSubtopic(),
+		Publisher: msg.GetPublisher(),
+		Protocol:  msg.GetProtocol(),
+		Payload:   string(msg.GetPayload()),
+		Created:   msg.GetCreated(),
+	}
+	buff := new(bytes.Buffer)
+	if err := n.tmpl.Execute(buff, d); err != nil {
+		return nil, errors.Wrap(errExecTemplate, err)
+	}
+
+	return buff.Bytes(), nil
+}
+
+// send posts the body to the URL, retrying the failed requests with
+// exponential back-off. Client errors other than 429 are not retried.
+func (n *notifier) send(u string, body []byte) error {
+	parsed, err := url.Parse(u)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errInvalidURL
+	}
+
+	op := func() error {
+		req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		req.Header.Set("Content-Type", n.cfg.ContentType)
+		if n.cfg.Secret != "" {
+			ts := strconv.FormatInt(time.Now().Unix(), 10)
+			req.Header.Set(TimestampHeader, ts)
+			req.Header.Set(SignatureHeader, signaturePrefix+Sign(n.cfg.Secret, ts, body))
+		}
+
+		res, err := n.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+
+		switch {
+		case res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices:
+			return nil
+		case res.StatusCode == http.StatusTooManyRequests, res.StatusCode >= http.StatusInternalServerError:
+			return errors.Wrap(errStatus, errors.New(res.Status))
+		default:
+			return backoff.Permanent(errors.Wrap(errStatus, errors.New(res.Status)))
+		}
+	}
+
+	return backoff.Retry(op, n.backOff())
+}
+
+func (n *notifier) backOff() backoff.BackOff {
+	if n.cfg.MaxRetries == 0 {
+		return &backoff.StopBackOff{}
+	}
+
+	b := backoff.NewExponentialBackOff()
+	if n.cfg.InitialInterval > 0 {
+		b.InitialInterval = n.cfg.InitialInterval
+	}
+	if n.cfg.MaxInterval > 0 {
+		b.MaxInterval = n.cfg.MaxInterval
+	}
+	b.MaxElapsedTime = 0
+
+	return backoff.WithMaxRetries(b, n.cfg.MaxRetries)
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the timestamp and the body,
+// which webhook receivers use to verify the notification.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
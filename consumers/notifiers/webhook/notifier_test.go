@@ -0,0 +1,152 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/notifiers/webhook"
+	"github.com/absmach/magistrala/pkg/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	secret = "secret"
+	from   = "magistrala"
+)
+
+var msg = &messaging.Message{
+	Channel:   "channel",
+	Subtopic:  "subtopic",
+	Publisher: "publisher",
+	Protocol:  "http",
+	Payload:   []byte(`{"temp":25}`),
+	Created:   1700000000000000000,
+}
+
+func newConfig() webhook.Config {
+	return webhook.Config{
+		Secret:          secret,
+		ContentType:     "application/json",
+		Timeout:         time.Second,
+		MaxRetries:      2,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+	}
+}
+
+func TestNotify(t *testing.T) {
+	var body []byte
+	var sig, ts string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		sig = r.Header.Get(webhook.SignatureHeader)
+		ts = r.Header.Get(webhook.TimestampHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	n, err := webhook.New(newConfig())
+	require.Nil(t, err, fmt.Sprintf("create notifier unexpected error: %s", err))
+
+	err = n.Notify(from, []string{srv.URL}, msg)
+	assert.Nil(t, err, fmt.Sprintf("notify unexpected error: %s", err))
+
+	var res map[string]interface{}
+	err = json.Unmarshal(body, &res)
+	assert.Nil(t, err, fmt.Sprintf("decode body unexpected error: %s", err))
+	assert.Equal(t, msg.Channel, res["channel"], fmt.Sprintf("expected channel %s got %v", msg.Channel, res["channel"]))
+	assert.Equal(t, map[string]interface{}{"temp": float64(25)}, res["payload"], fmt.Sprintf("expected JSON payload got %v", res["payload"]))
+	assert.Equal(t, "sha256="+webhook.Sign(secret, ts, body), sig, fmt.Sprintf("expected valid signature got %s", sig))
+}
+
+func TestNotifyTemplate(t *testing.T) {
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tmpl := filepath.Join(t.TempDir(), "webhook.tmpl")
+	err := os.WriteFile(tmpl, []byte(`{{.Publisher}} sent {{.Payload}} to {{.Channel}}`), 0o600)
+	require.Nil(t, err, fmt.Sprintf("write template unexpected error: %s", err))
+
+	cfg := newConfig()
+	cfg.Template = tmpl
+	n, err := webhook.New(cfg)
+	require.Nil(t, err, fmt.Sprintf("create notifier unexpected error: %s", err))
+
+	err = n.Notify(from, []string{srv.URL}, msg)
+	assert.Nil(t, err, fmt.Sprintf("notify unexpected error: %s", err))
+	expected := `publisher sent {"temp":25} to channel`
+	assert.Equal(t, expected, string(body), fmt.Sprintf("expected body %s got %s", expected, body))
+
+	cfg.Template = filepath.Join(t.TempDir(), "unknown.tmpl")
+	_, err = webhook.New(cfg)
+	assert.NotNil(t, err, "create notifier with missing template: expected error")
+}
+
+func TestNotifyRetry(t *testing.T) {
+	cases := []struct {
+		desc     string
+		statuses []int
+		requests int32
+		err      bool
+	}{
+		{
+			desc:     "notify after server recovers",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			requests: 3,
+			err:      false,
+		},
+		{
+			desc:     "notify failing server",
+			statuses: []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			requests: 3,
+			err:      true,
+		},
+		{
+			desc:     "notify with client error",
+			statuses: []int{http.StatusBadRequest, http.StatusOK},
+			requests: 1,
+			err:      true,
+		},
+	}
+
+	for _, tc := range cases {
+		var requests int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			i := atomic.AddInt32(&requests, 1) - 1
+			w.WriteHeader(tc.statuses[i])
+		}))
+
+		n, err := webhook.New(newConfig())
+		require.Nil(t, err, fmt.Sprintf("%s: create notifier unexpected error: %s", tc.desc, err))
+
+		err = n.Notify(from, []string{srv.URL}, msg)
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: expected error %t got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.requests, atomic.LoadInt32(&requests), fmt.Sprintf("%s: expected %d requests got %d", tc.desc, tc.requests, requests))
+		srv.Close()
+	}
+}
+
+func TestNotifyInvalidURL(t *testing.T) {
+	n, err := webhook.New(newConfig())
+	require.Nil(t, err, fmt.Sprintf("create notifier unexpected error: %s", err))
+
+	err = n.Notify(from, []string{"user@example.com"}, msg)
+	assert.True(t, err != nil && strings.Contains(err.Error(), "user@example.com"), fmt.Sprintf("notify invalid URL: expected error got %s", err))
+}
@@ -4,16 +4,21 @@
 package api
 
 import (
+	"log/slog"
 	"net/http"
 
 	"github.com/absmach/magistrala"
+	"github.com/absmach/magistrala/consumers"
+	dlqapi "github.com/absmach/magistrala/consumers/api"
 	"github.com/go-chi/chi/v5"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-// MakeHandler returns a HTTP API handler with health check and metrics.
-func MakeHandler(svcName, instanceID string) http.Handler {
+// MakeHandler returns a HTTP API handler with health check, metrics
+// and dead-letter queue endpoints.
+func MakeHandler(dlq consumers.DeadLetterQueue, logger *slog.Logger, svcName, instanceID string) http.Handler {
 	r := chi.NewRouter()
+	r.Mount("/dlq", dlqapi.MakeHandler(dlq, logger))
 	r.Get("/health", magistrala.Health(svcName, instanceID))
 	r.Handle("/metrics", promhttp.Handler())
 
@@ -0,0 +1,150 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/absmach/magistrala/consumers"
+	"github.com/absmach/magistrala/pkg/errors"
+	mgjson "github.com/absmach/magistrala/pkg/transformers/json"
+	"github.com/absmach/magistrala/pkg/transformers/senml"
+)
+
+// BatchConfig represents batching configuration.
+type BatchConfig struct {
+	// Size is the number of messages that triggers the flush.
+	// Batching is disabled if the size is lower than 2.
+	Size int `env:"SIZE"           envDefault:"1"`
+	// FlushInterval is the maximum time the message waits to be flushed.
+	FlushInterval time.Duration `env:"FLUSH_INTERVAL" envDefault:"1s"`
+}
+
+var _ consumers.BlockingConsumer = (*batchConsumer)(nil)
+
+type batchConsumer struct {
+	mu       sync.Mutex
+	consumer consumers.BlockingConsumer
+	cfg      BatchConfig
+	current  *batch
+}
+
+// NewBatch returns BlockingConsumer that accumulates messages and writes
+// them using the wrapped consumer once the batch size is reached or the flush
+// interval expires, whichever happens first. ConsumeBlocking returns only
+// after the batch containing the messages is written, so the messages are
+// acknowledged to the broker only once they are stored. Since the batch is
+// written at once, the failure of a single message fails the whole batch.
+// To fill the batches, messages should be consumed concurrently.
+func NewBatch(consumer consumers.BlockingConsumer, cfg BatchConfig) consumers.BlockingConsumer {
+	if cfg.Size < 2 {
+		return consumer
+	}
+
+	return &batchConsumer{
+		consumer: consumer,
+		cfg:      cfg,
+	}
+}
+
+func (bc *batchConsumer) ConsumeBlocking(ctx context.Context, messages interface{}) error {
+	switch messages.(type) {
+	case []senml.Message, mgjson.Messages:
+	default:
+		// Let the wrapped consumer handle unsupported messages.
+		return bc.consumer.ConsumeBlocking(ctx, messages)
+	}
+
+	bc.mu.Lock()
+	b := bc.current
+	if b == nil {
+		b = newBatch()
+		bc.current = b
+		b.timer = time.AfterFunc(bc.cfg.FlushInterval, func() {
+			if bc.detach(b) {
+				bc.flush(b)
+			}
+		})
+	}
+	b.add(messages)
+	full := b.size >= bc.cfg.Size
+	if full {
+		bc.current = nil
+		b.timer.Stop()
+	}
+	bc.mu.Unlock()
+
+	if full {
+		bc.flush(b)
+	}
+
+	select {
+	case <-b.done:
+		return b.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// detach removes the batch from accumulation, reporting whether the batch
+// was still accumulating messages.
+func (bc *batchConsumer) detach(b *batch) bool {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	if bc.current != b {
+		return false
+	}
+	bc.current = nil
+
+	return true
+}
+
+// flush writes the batch using the wrapped consumer. Flush is not bound to
+// the context of any of the callers, since the batch is written on behalf
+// of all of them.
+func (bc *batchConsumer) flush(b *batch) {
+	defer close(b.done)
+
+	ctx := context.Background()
+	if len(b.senml) > 0 {
+		if err := bc.consumer.ConsumeBlocking(ctx, b.senml); err != nil {
+			b.err = err
+		}
+	}
+	for format, msgs := range b.json {
+		if err := bc.consumer.ConsumeBlocking(ctx, mgjson.Messages{Data: msgs, Format: format}); err != nil {
+			b.err = errors.Wrap(err, b.err)
+		}
+	}
+}
+
+type batch struct {
+	senml []senml.Message
+	json  map[string][]mgjson.Message
+	size  int
+	timer *time.Timer
+	done  chan struct{}
+	err   error
+}
+
+func newBatch() *batch {
+	return &batch{
+		json: make(map[string][]mgjson.Message),
+		done: make(chan struct{}),
+	}
+}
+
+func (b *batch) add(messages interface{}) {
+	switch m := messages.(type) {
+	case []senml.Message:
+		b.senml = append(b.senml, m...)
+		b.size += len(m)
+	case mgjson.Messages:
+		b.json[m.Format] = append(b.json[m.Format], m.Data...)
+		b.size += len(m.Data)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package writers_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers"
+	"github.com/absmach/magistrala/consumers/writers"
+	"github.com/absmach/magistrala/pkg/errors"
+	mgjson "github.com/absmach/magistrala/pkg/transformers/json"
+	"github.com/absmach/magistrala/pkg/transformers/senml"
+	"github.com/stretchr/testify/assert"
+)
+
+var errWrite = errors.New("failed to write")
+
+// consumer records the messages written in each ConsumeBlocking call.
+type consumer struct {
+	mu    sync.Mutex
+	err   error
+	calls []interface{}
+}
+
+func (c *consumer) ConsumeBlocking(_ context.Context, messages interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls = append(c.calls, messages)
+
+	return c.err
+}
+
+func consume(bc consumers.BlockingConsumer, messages []interface{}) []error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(messages))
+	for i, msg := range messages {
+		wg.Add(1)
+		go func(i int, msg interface{}) {
+			defer wg.Done()
+			errs[i] = bc.ConsumeBlocking(context.Background(), msg)
+		}(i, msg)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+func TestBatch(t *testing.T) {
+	senmlMsg := []senml.Message{{Name: "temp"}}
+	jsonMsg := func(format string) mgjson.Messages {
+		return mgjson.Messages{Data: []mgjson.Message{{Channel: "1"}}, Format: format}
+	}
+
+	cases := []struct {
+		desc     string
+		cfg      writers.BatchConfig
+		messages []interface{}
+		err      error
+		calls    int
+		records  int
+	}{
+		{
+			desc:     "write batch once size is reached",
+			cfg:      writers.BatchConfig{Size: 3, FlushInterval: time.Hour},
+			messages: []interface{}{senmlMsg, senmlMsg, senmlMsg},
+			calls:    1,
+			records:  3,
+		},
+		{
+			desc:     "write batch once flush interval expires",
+			cfg:      writers.BatchConfig{Size: 10, FlushInterval: 10 * time.Millisecond},
+			messages: []interface{}{senmlMsg, senmlMsg},
+			calls:    1,
+			records:  2,
+		},
+		{
+			desc:     "write batch of JSON messages grouped by format",
+			cfg:      writers.BatchConfig{Size: 4, FlushInterval: time.Hour},
+			messages: []interface{}{jsonMsg("a"), jsonMsg("b"), jsonMsg("a"), jsonMsg("b")},
+			calls:    2,
+			records:  4,
+		},
+		{
+			desc:     "write batch with failing consumer",
+			cfg:      writers.BatchConfig{Size: 2, FlushInterval: time.Hour},
+			messages: []interface{}{senmlMsg, senmlMsg},
+			err:      errWrite,
+			calls:    1,
+			records:  2,
+		},
+		{
+			desc:     "write without batching",
+			cfg:      writers.BatchConfig{Size: 1, FlushInterval: time.Hour},
+			messages: []interface{}{senmlMsg, senmlMsg},
+			calls:    2,
+			records:  2,
+		},
+	}
+
+	for _, tc := range cases {
+		c := &consumer{err: tc.err}
+		bc := writers.NewBatch(c, tc.cfg)
+
+		errs := consume(bc, tc.messages)
+		for _, err := range errs {
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		}
+		assert.Len(t, c.calls, tc.calls, fmt.Sprintf("%s: expected %d writes got %d", tc.desc, tc.calls, len(c.calls)))
+
+		records := 0
+		for _, call := range c.calls {
+			switch m := call.(type) {
+			case []senml.Message:
+				records += len(m)
+			case mgjson.Messages:
+				records += len(m.Data)
+			}
+		}
+		assert.Equal(t, tc.records, records, fmt.Sprintf("%s: expected %d records got %d", tc.desc, tc.records, records))
+	}
+}
@@ -15,10 +15,15 @@ import (
 	"github.com/gocql/gocql"
 )
 
+// maxBatchSize limits the number of statements in a single unlogged batch,
+// since Cassandra rejects batches larger than batch_size_fail_threshold.
+const maxBatchSize = 100
+
 var (
 	errSaveMessage = errors.New("failed to save message to cassandra database")
 	errNoTable     = errors.New("table does not exist")
 )
+
 var _ consumers.BlockingConsumer = (*cassandraRepository)(nil)
 
 type cassandraRepository struct {
@@ -48,15 +53,19 @@ func (cr *cassandraRepository) saveSenml(messages interface{}) error {
             name, unit, value, string_value, bool_value, data_value, sum,
             time, update_time)
             VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-	id := gocql.TimeUUID()
 
-	for _, msg := range msgs {
-		err := cr.session.Query(cql, id, msg.Channel, msg.Subtopic, msg.Publisher,
+	batch := cr.session.NewBatch(gocql.UnloggedBatch)
+	for i, msg := range msgs {
+		batch.Query(cql, gocql.TimeUUID(), msg.Channel, msg.Subtopic, msg.Publisher,
 			msg.Protocol, msg.Name, msg.Unit, msg.Value, msg.StringValue,
-			msg.BoolValue, msg.DataValue, msg.Sum, msg.Time, msg.UpdateTime).Exec()
-		if err != nil {
+			msg.BoolValue, msg.DataValue, msg.Sum, msg.Time, msg.UpdateTime)
+		if batch.Size() < maxBatchSize && i < len(msgs)-1 {
+			continue
+		}
+		if err := cr.session.ExecuteBatch(batch); err != nil {
 			return errors.Wrap(errSaveMessage, err)
 		}
+		batch = cr.session.NewBatch(gocql.UnloggedBatch)
 	}
 
 	return nil
@@ -78,21 +87,26 @@ func (cr *cassandraRepository) saveJSON(msgs mgjson.Messages) error {
 func (cr *cassandraRepository) insertJSON(msgs mgjson.Messages) error {
 	cql := `INSERT INTO %s (id, channel, created, subtopic, publisher, protocol, payload) VALUES (?, ?, ?, ?, ?, ?, ?)`
 	cql = fmt.Sprintf(cql, msgs.Format)
-	for _, msg := range msgs.Data {
+
+	batch := cr.session.NewBatch(gocql.UnloggedBatch)
+	for i, msg := range msgs.Data {
 		pld, err := json.Marshal(msg.Payload)
 		if err != nil {
 			return err
 		}
-		id := gocql.TimeUUID()
-
-		err = cr.session.Query(cql, id, msg.Channel, msg.Created, msg.Subtopic, msg.Publisher, msg.Protocol, string(pld)).Exec()
-		if err != nil {
+		batch.Query(cql, gocql.TimeUUID(), msg.Channel, msg.Created, msg.Subtopic, msg.Publisher, msg.Protocol, string(pld))
+		if batch.Size() < maxBatchSize && i < len(msgs.Data)-1 {
+			continue
+		}
+		if err := cr.session.ExecuteBatch(batch); err != nil {
 			if err.Error() == fmt.Sprintf("unconfigured table %s", msgs.Format) {
 				return errNoTable
 			}
 			return errors.Wrap(errSaveMessage, err)
 		}
+		batch = cr.session.NewBatch(gocql.UnloggedBatch)
 	}
+
 	return nil
 }
 
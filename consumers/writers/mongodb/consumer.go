@@ -58,6 +58,9 @@ func (repo *mongoRepo) saveSenml(ctx context.Context, messages interface{}) erro
 			dbMsgs = append(dbMsgs, msg)
 		}
 	}
+	if len(dbMsgs) == 0 {
+		return nil
+	}
 
 	_, err := coll.InsertMany(ctx, dbMsgs)
 	if err != nil {
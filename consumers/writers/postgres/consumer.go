@@ -14,15 +14,27 @@ import (
 	"github.com/absmach/magistrala/pkg/transformers/senml"
 	"github.com/gofrs/uuid"
 	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx" // required for DB access
 )
 
 var (
-	errInvalidMessage = errors.New("invalid message representation")
-	errSaveMessage    = errors.New("failed to save message to postgres database")
-	errTransRollback  = errors.New("failed to rollback transaction")
-	errNoTable        = errors.New("relation does not exist")
+	errInvalidMessage    = errors.New("invalid message representation")
+	errSaveMessage       = errors.New("failed to save message to postgres database")
+	errNoTable           = errors.New("relation does not exist")
+	errUnsupportedDriver = errors.New("unsupported database driver")
+)
+
+const senmlTable = "messages"
+
+var (
+	senmlColumns = []string{
+		"id", "channel", "subtopic", "publisher", "protocol", "name", "unit",
+		"value", "string_value", "bool_value", "data_value", "sum", "time", "update_time",
+	}
+	jsonColumns = []string{"id", "channel", "created", "subtopic", "publisher", "protocol", "payload"}
 )
 
 var _ consumers.BlockingConsumer = (*postgresRepo)(nil)
@@ -36,7 +48,7 @@ func New(db *sqlx.DB) consumers.BlockingConsumer {
 	return &postgresRepo{db: db}
 }
 
-func (pr postgresRepo) ConsumeBlocking(ctx context.Context, message interface{}) (err error) {
+func (pr postgresRepo) ConsumeBlocking(ctx context.Context, message interface{}) error {
 	switch m := message.(type) {
 	case mgjson.Messages:
 		return pr.saveJSON(ctx, m)
@@ -45,53 +57,39 @@ func (pr postgresRepo) ConsumeBlocking(ctx context.Context, message interface{})
 	}
 }
 
-func (pr postgresRepo) saveSenml(ctx context.Context, messages interface{}) (err error) {
+func (pr postgresRepo) saveSenml(ctx context.Context, messages interface{}) error {
 	msgs, ok := messages.([]senml.Message)
 	if !ok {
 		return errSaveMessage
 	}
-	q := `INSERT INTO messages (id, channel, subtopic, publisher, protocol,
-          name, unit, value, string_value, bool_value, data_value, sum,
-          time, update_time)
-          VALUES (:id, :channel, :subtopic, :publisher, :protocol, :name, :unit,
-          :value, :string_value, :bool_value, :data_value, :sum,
-          :time, :update_time);`
-
-	tx, err := pr.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return errors.Wrap(errSaveMessage, err)
-	}
-	defer func() {
-		if err != nil {
-			if txErr := tx.Rollback(); txErr != nil {
-				err = errors.Wrap(err, errors.Wrap(errTransRollback, txErr))
-			}
-			return
-		}
-
-		if err = tx.Commit(); err != nil {
-			err = errors.Wrap(errSaveMessage, err)
-		}
-	}()
 
+	rows := make([][]interface{}, 0, len(msgs))
 	for _, msg := range msgs {
 		id, err := uuid.NewV4()
 		if err != nil {
 			return err
 		}
-		m := senmlMessage{Message: msg, ID: id.String()}
-		if _, err := tx.NamedExec(q, m); err != nil {
-			pgErr, ok := err.(*pgconn.PgError)
-			if ok {
-				if pgErr.Code == pgerrcode.InvalidTextRepresentation {
-					return errors.Wrap(errSaveMessage, errInvalidMessage)
-				}
-			}
+		var data []byte
+		if msg.DataValue != nil {
+			data = []byte(*msg.DataValue)
+		}
+		rows = append(rows, []interface{}{
+			id.String(), msg.Channel, msg.Subtopic, msg.Publisher, msg.Protocol,
+			msg.Name, msg.Unit, msg.Value, msg.StringValue, msg.BoolValue, data,
+			msg.Sum, msg.Time, msg.UpdateTime,
+		})
+	}
 
-			return errors.Wrap(errSaveMessage, err)
+	if err := pr.copyFrom(ctx, senmlTable, senmlColumns, rows); err != nil {
+		pgErr, ok := err.(*pgconn.PgError)
+		if ok && pgErr.Code == pgerrcode.InvalidTextRepresentation {
+			return errors.Wrap(errSaveMessage, errInvalidMessage)
 		}
+
+		return errors.Wrap(errSaveMessage, err)
 	}
-	return err
+
+	return nil
 }
 
 func (pr postgresRepo) saveJSON(ctx context.Context, msgs mgjson.Messages) error {
@@ -108,50 +106,54 @@ func (pr postgresRepo) saveJSON(ctx context.Context, msgs mgjson.Messages) error
 }
 
 func (pr postgresRepo) insertJSON(ctx context.Context, msgs mgjson.Messages) error {
-	tx, err := pr.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return errors.Wrap(errSaveMessage, err)
-	}
-	defer func() {
-		if err != nil {
-			if txErr := tx.Rollback(); txErr != nil {
-				err = errors.Wrap(err, errors.Wrap(errTransRollback, txErr))
-			}
-			return
-		}
-
-		if err = tx.Commit(); err != nil {
-			err = errors.Wrap(errSaveMessage, err)
-		}
-	}()
-
-	q := `INSERT INTO %s (id, channel, created, subtopic, publisher, protocol, payload)
-          VALUES (:id, :channel, :created, :subtopic, :publisher, :protocol, :payload);`
-	q = fmt.Sprintf(q, msgs.Format)
-
+	rows := make([][]interface{}, 0, len(msgs.Data))
 	for _, m := range msgs.Data {
-		var dbmsg jsonMessage
-		dbmsg, err = toJSONMessage(m)
+		id, err := uuid.NewV4()
 		if err != nil {
 			return errors.Wrap(errSaveMessage, err)
 		}
+		payload, err := jsonPayload(m)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, []interface{}{id.String(), m.Channel, m.Created, m.Subtopic, m.Publisher, m.Protocol, payload})
+	}
 
-		if _, err = tx.NamedExec(q, dbmsg); err != nil {
-			pgErr, ok := err.(*pgconn.PgError)
-			if ok {
-				switch pgErr.Code {
-				case pgerrcode.InvalidTextRepresentation:
-					return errors.Wrap(errSaveMessage, errInvalidMessage)
-				case pgerrcode.UndefinedTable:
-					return errNoTable
-				}
+	if err := pr.copyFrom(ctx, msgs.Format, jsonColumns, rows); err != nil {
+		pgErr, ok := err.(*pgconn.PgError)
+		if ok {
+			switch pgErr.Code {
+			case pgerrcode.InvalidTextRepresentation:
+				return errors.Wrap(errSaveMessage, errInvalidMessage)
+			case pgerrcode.UndefinedTable:
+				return errNoTable
 			}
-			return err
 		}
+		return errors.Wrap(errSaveMessage, err)
 	}
+
 	return nil
 }
 
+// copyFrom writes rows using the COPY protocol, which is considerably faster
+// than inserting the rows one by one.
+func (pr postgresRepo) copyFrom(ctx context.Context, table string, columns []string, rows [][]interface{}) error {
+	conn, err := pr.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn interface{}) error {
+		c, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errUnsupportedDriver
+		}
+		_, err := c.Conn().CopyFrom(ctx, pgx.Identifier{table}, columns, pgx.CopyFromRows(rows))
+		return err
+	})
+}
+
 func (pr postgresRepo) createTable(name string) error {
 	q := `CREATE TABLE IF NOT EXISTS %s (
             id            UUID,
@@ -169,45 +171,14 @@ func (pr postgresRepo) createTable(name string) error {
 	return err
 }
 
-type senmlMessage struct {
-	senml.Message
-	ID string `db:"id"`
-}
-
-type jsonMessage struct {
-	ID        string `db:"id"`
-	Channel   string `db:"channel"`
-	Created   int64  `db:"created"`
-	Subtopic  string `db:"subtopic"`
-	Publisher string `db:"publisher"`
-	Protocol  string `db:"protocol"`
-	Payload   []byte `db:"payload"`
-}
-
-func toJSONMessage(msg mgjson.Message) (jsonMessage, error) {
-	id, err := uuid.NewV4()
-	if err != nil {
-		return jsonMessage{}, err
+func jsonPayload(msg mgjson.Message) ([]byte, error) {
+	if msg.Payload == nil {
+		return []byte("{}"), nil
 	}
-
-	data := []byte("{}")
-	if msg.Payload != nil {
-		b, err := json.Marshal(msg.Payload)
-		if err != nil {
-			return jsonMessage{}, errors.Wrap(errSaveMessage, err)
-		}
-		data = b
-	}
-
-	m := jsonMessage{
-		ID:        id.String(),
-		Channel:   msg.Channel,
-		Created:   msg.Created,
-		Subtopic:  msg.Subtopic,
-		Publisher: msg.Publisher,
-		Protocol:  msg.Protocol,
-		Payload:   data,
+	data, err := json.Marshal(msg.Payload)
+	if err != nil {
+		return nil, errors.Wrap(errSaveMessage, err)
 	}
 
-	return m, nil
+	return data, nil
 }
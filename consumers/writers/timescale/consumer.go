@@ -13,15 +13,27 @@ import (
 	mgjson "github.com/absmach/magistrala/pkg/transformers/json"
 	"github.com/absmach/magistrala/pkg/transformers/senml"
 	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx" // required for DB access
 )
 
 var (
-	errInvalidMessage = errors.New("invalid message representation")
-	errSaveMessage    = errors.New("failed to save message to timescale database")
-	errTransRollback  = errors.New("failed to rollback transaction")
-	errNoTable        = errors.New("relation does not exist")
+	errInvalidMessage    = errors.New("invalid message representation")
+	errSaveMessage       = errors.New("failed to save message to timescale database")
+	errNoTable           = errors.New("relation does not exist")
+	errUnsupportedDriver = errors.New("unsupported database driver")
+)
+
+const senmlTable = "messages"
+
+var (
+	senmlColumns = []string{
+		"channel", "subtopic", "publisher", "protocol", "name", "unit",
+		"value", "string_value", "bool_value", "data_value", "sum", "time", "update_time",
+	}
+	jsonColumns = []string{"channel", "created", "subtopic", "publisher", "protocol", "payload"}
 )
 
 var _ consumers.BlockingConsumer = (*timescaleRepo)(nil)
@@ -35,7 +47,7 @@ func New(db *sqlx.DB) consumers.BlockingConsumer {
 	return &timescaleRepo{db: db}
 }
 
-func (tr *timescaleRepo) ConsumeBlocking(ctx context.Context, message interface{}) (err error) {
+func (tr *timescaleRepo) ConsumeBlocking(ctx context.Context, message interface{}) error {
 	switch m := message.(type) {
 	case mgjson.Messages:
 		return tr.saveJSON(ctx, m)
@@ -44,49 +56,35 @@ func (tr *timescaleRepo) ConsumeBlocking(ctx context.Context, message interface{
 	}
 }
 
-func (tr timescaleRepo) saveSenml(ctx context.Context, messages interface{}) (err error) {
+func (tr timescaleRepo) saveSenml(ctx context.Context, messages interface{}) error {
 	msgs, ok := messages.([]senml.Message)
 	if !ok {
 		return errSaveMessage
 	}
-	q := `INSERT INTO messages (channel, subtopic, publisher, protocol,
-          name, unit, value, string_value, bool_value, data_value, sum,
-          time, update_time)
-          VALUES (:channel, :subtopic, :publisher, :protocol, :name, :unit,
-          :value, :string_value, :bool_value, :data_value, :sum,
-          :time, :update_time);`
-
-	tx, err := tr.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return errors.Wrap(errSaveMessage, err)
-	}
-	defer func() {
-		if err != nil {
-			if txErr := tx.Rollback(); txErr != nil {
-				err = errors.Wrap(err, errors.Wrap(errTransRollback, txErr))
-			}
-			return
-		}
-
-		if err = tx.Commit(); err != nil {
-			err = errors.Wrap(errSaveMessage, err)
-		}
-	}()
 
+	rows := make([][]interface{}, 0, len(msgs))
 	for _, msg := range msgs {
-		m := senmlMessage{Message: msg}
-		if _, err := tx.NamedExec(q, m); err != nil {
-			pgErr, ok := err.(*pgconn.PgError)
-			if ok {
-				if pgErr.Code == pgerrcode.InvalidTextRepresentation {
-					return errors.Wrap(errSaveMessage, errInvalidMessage)
-				}
-			}
+		var data []byte
+		if msg.DataValue != nil {
+			data = []byte(*msg.DataValue)
+		}
+		rows = append(rows, []interface{}{
+			msg.Channel, msg.Subtopic, msg.Publisher, msg.Protocol,
+			msg.Name, msg.Unit, msg.Value, msg.StringValue, msg.BoolValue, data,
+			msg.Sum, msg.Time, msg.UpdateTime,
+		})
+	}
 
-			return errors.Wrap(errSaveMessage, err)
+	if err := tr.copyFrom(ctx, senmlTable, senmlColumns, rows); err != nil {
+		pgErr, ok := err.(*pgconn.PgError)
+		if ok && pgErr.Code == pgerrcode.InvalidTextRepresentation {
+			return errors.Wrap(errSaveMessage, errInvalidMessage)
 		}
+
+		return errors.Wrap(errSaveMessage, err)
 	}
-	return err
+
+	return nil
 }
 
 func (tr timescaleRepo) saveJSON(ctx context.Context, msgs mgjson.Messages) error {
@@ -103,49 +101,50 @@ func (tr timescaleRepo) saveJSON(ctx context.Context, msgs mgjson.Messages) erro
 }
 
 func (tr timescaleRepo) insertJSON(ctx context.Context, msgs mgjson.Messages) error {
-	tx, err := tr.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return errors.Wrap(errSaveMessage, err)
-	}
-	defer func() {
-		if err != nil {
-			if txErr := tx.Rollback(); txErr != nil {
-				err = errors.Wrap(err, errors.Wrap(errTransRollback, txErr))
-			}
-			return
-		}
-
-		if err = tx.Commit(); err != nil {
-			err = errors.Wrap(errSaveMessage, err)
-		}
-	}()
-
-	q := `INSERT INTO %s (channel, created, subtopic, publisher, protocol, payload)
-          VALUES (:channel, :created, :subtopic, :publisher, :protocol, :payload);`
-	q = fmt.Sprintf(q, msgs.Format)
-
+	rows := make([][]interface{}, 0, len(msgs.Data))
 	for _, m := range msgs.Data {
-		var dbmsg jsonMessage
-		dbmsg, err = toJSONMessage(m)
+		payload, err := jsonPayload(m)
 		if err != nil {
-			return errors.Wrap(errSaveMessage, err)
+			return err
 		}
-		if _, err = tx.NamedExec(q, dbmsg); err != nil {
-			pgErr, ok := err.(*pgconn.PgError)
-			if ok {
-				switch pgErr.Code {
-				case pgerrcode.InvalidTextRepresentation:
-					return errors.Wrap(errSaveMessage, errInvalidMessage)
-				case pgerrcode.UndefinedTable:
-					return errNoTable
-				}
+		rows = append(rows, []interface{}{m.Channel, m.Created, m.Subtopic, m.Publisher, m.Protocol, payload})
+	}
+
+	if err := tr.copyFrom(ctx, msgs.Format, jsonColumns, rows); err != nil {
+		pgErr, ok := err.(*pgconn.PgError)
+		if ok {
+			switch pgErr.Code {
+			case pgerrcode.InvalidTextRepresentation:
+				return errors.Wrap(errSaveMessage, errInvalidMessage)
+			case pgerrcode.UndefinedTable:
+				return errNoTable
 			}
-			return err
 		}
+		return errors.Wrap(errSaveMessage, err)
 	}
+
 	return nil
 }
 
+// copyFrom writes rows using the COPY protocol, which is considerably faster
+// than inserting the rows one by one.
+func (tr timescaleRepo) copyFrom(ctx context.Context, table string, columns []string, rows [][]interface{}) error {
+	conn, err := tr.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn interface{}) error {
+		c, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errUnsupportedDriver
+		}
+		_, err := c.Conn().CopyFrom(ctx, pgx.Identifier{table}, columns, pgx.CopyFromRows(rows))
+		return err
+	})
+}
+
 func (tr timescaleRepo) createTable(name string) error {
 	q := `CREATE TABLE IF NOT EXISTS %s (
             created       BIGINT NOT NULL,
@@ -162,37 +161,14 @@ func (tr timescaleRepo) createTable(name string) error {
 	return err
 }
 
-type senmlMessage struct {
-	senml.Message
-}
-
-type jsonMessage struct {
-	Channel   string `db:"channel"`
-	Created   int64  `db:"created"`
-	Subtopic  string `db:"subtopic"`
-	Publisher string `db:"publisher"`
-	Protocol  string `db:"protocol"`
-	Payload   []byte `db:"payload"`
-}
-
-func toJSONMessage(msg mgjson.Message) (jsonMessage, error) {
-	data := []byte("{}")
-	if msg.Payload != nil {
-		b, err := json.Marshal(msg.Payload)
-		if err != nil {
-			return jsonMessage{}, errors.Wrap(errSaveMessage, err)
-		}
-		data = b
+func jsonPayload(msg mgjson.Message) ([]byte, error) {
+	if msg.Payload == nil {
+		return []byte("{}"), nil
 	}
-
-	m := jsonMessage{
-		Channel:   msg.Channel,
-		Created:   msg.Created,
-		Subtopic:  msg.Subtopic,
-		Publisher: msg.Publisher,
-		Protocol:  msg.Protocol,
-		Payload:   data,
+	data, err := json.Marshal(msg.Payload)
+	if err != nil {
+		return nil, errors.Wrap(errSaveMessage, err)
 	}
 
-	return m, nil
+	return data, nil
 }
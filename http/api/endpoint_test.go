@@ -4,31 +4,44 @@
 package api_test
 
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
 	"io"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/absmach/magistrala"
+	"github.com/absmach/magistrala/auth"
 	authmocks "github.com/absmach/magistrala/auth/mocks"
 	server "github.com/absmach/magistrala/http"
 	"github.com/absmach/magistrala/http/api"
 	"github.com/absmach/magistrala/http/mocks"
 	"github.com/absmach/magistrala/internal/apiutil"
 	mglog "github.com/absmach/magistrala/logger"
+	"github.com/absmach/magistrala/pkg/certauth"
+	certauthmocks "github.com/absmach/magistrala/pkg/certauth/mocks"
 	mproxy "github.com/absmach/mproxy/pkg/http"
 	"github.com/absmach/mproxy/pkg/session"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 const instanceID = "5de9b29a-feb9-11ed-be56-0242ac120002"
 
 func newService(auth magistrala.AuthzServiceClient) session.Handler {
 	pub := mocks.NewPublisher()
-	return server.NewHandler(pub, mglog.NewMock(), auth)
+	return server.NewHandler(pub, mglog.NewMock(), auth, nil)
 }
 
 func newTargetHTTPServer() *httptest.Server {
@@ -44,6 +57,66 @@ func newProxyHTPPServer(svc session.Handler, targetServer *httptest.Server) (*ht
 	return httptest.NewServer(http.HandlerFunc(mp.Handler)), nil
 }
 
+// newProxyHTTPSServer starts the proxy behind the TLS listener which verifies
+// the client certificates issued by the CA, the same way the adapter does.
+func newProxyHTTPSServer(svc session.Handler, targetServer *httptest.Server, ca *x509.Certificate) (*httptest.Server, error) {
+	mp, err := mproxy.NewProxy("", targetServer.URL, svc, mglog.NewMock())
+	if err != nil {
+		return nil, err
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	h, err := certauth.Handler(http.HandlerFunc(mp.Handler), certauth.Config{})
+	if err != nil {
+		return nil, err
+	}
+	ts := httptest.NewUnstartedServer(h)
+	ts.TLS = &tls.Config{
+		ClientAuth: tls.VerifyClientCertIfGiven,
+		ClientCAs:  roots,
+	}
+	ts.StartTLS()
+
+	return ts, nil
+}
+
+func newCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err, fmt.Sprintf("unexpected key generation error: %s\n", err))
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert creation error: %s\n", err))
+	ca, err := x509.ParseCertificate(der)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert parsing error: %s\n", err))
+
+	return ca, key
+}
+
+func newClientCert(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err, fmt.Sprintf("unexpected key generation error: %s\n", err))
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "thing"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert creation error: %s\n", err))
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
 type testRequest struct {
 	client      *http.Client
 	method      string
@@ -186,3 +259,77 @@ func TestPublish(t *testing.T) {
 		})
 	}
 }
+
+func TestPublishCert(t *testing.T) {
+	authClient := new(authmocks.AuthClient)
+	certAuth := new(certauthmocks.Authenticator)
+	chanID := "1"
+	thingID := "5384fb1c-d0ae-4cbe-be52-c54223150fe0"
+	ctSenmlJSON := "application/senml+json"
+	msg := `[{"n":"current","t":-1,"v":1.6}]`
+
+	ca, caKey := newCA(t)
+	cert := newClientCert(t, ca, caKey)
+	otherCA, otherCAKey := newCA(t)
+	unknownCert := newClientCert(t, otherCA, otherCAKey)
+
+	svc := server.NewHandler(mocks.NewPublisher(), mglog.NewMock(), authClient, certAuth)
+	target := newTargetHTTPServer()
+	defer target.Close()
+	ts, err := newProxyHTTPSServer(svc, target, ca)
+	require.Nil(t, err, fmt.Sprintf("failed to create proxy server with err: %v", err))
+	defer ts.Close()
+
+	isCert := mock.MatchedBy(func(c *x509.Certificate) bool {
+		return bytes.Equal(c.Raw, cert.Certificate[0])
+	})
+	certAuth.On("Authenticate", mock.Anything, isCert).Return(thingID, nil)
+	authClient.On("Authorize", mock.Anything, &magistrala.AuthorizeReq{Subject: thingID, SubjectKind: auth.ThingsKind, Object: chanID, SubjectType: auth.ThingType, Permission: auth.PublishPermission, ObjectType: auth.GroupType}).Return(&magistrala.AuthorizeRes{Authorized: true, Id: thingID}, nil)
+	authClient.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: false, Id: ""}, nil)
+
+	cases := []struct {
+		desc   string
+		cert   *tls.Certificate
+		status int
+		err    bool
+	}{
+		{
+			desc:   "publish message with client certificate",
+			cert:   &cert,
+			status: http.StatusAccepted,
+		},
+		{
+			desc:   "publish message without client certificate",
+			status: http.StatusBadGateway,
+		},
+		{
+			desc: "publish message with client certificate of unknown CA",
+			cert: &unknownCert,
+			err:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			client := ts.Client()
+			transport := client.Transport.(*http.Transport).Clone()
+			if tc.cert != nil {
+				transport.TLSClientConfig.Certificates = []tls.Certificate{*tc.cert}
+			}
+			req := testRequest{
+				client:      &http.Client{Transport: transport},
+				method:      http.MethodPost,
+				url:         fmt.Sprintf("%s/channels/%s/messages", ts.URL, chanID),
+				contentType: ctSenmlJSON,
+				body:        strings.NewReader(msg),
+			}
+			res, err := req.make()
+			if tc.err {
+				assert.NotNil(t, err, fmt.Sprintf("%s: expected TLS handshake error", tc.desc))
+				return
+			}
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/absmach/magistrala"
 	"github.com/absmach/magistrala/auth"
 	"github.com/absmach/magistrala/internal/apiutil"
+	"github.com/absmach/magistrala/pkg/certauth"
 	"github.com/absmach/magistrala/pkg/errors"
 	"github.com/absmach/magistrala/pkg/messaging"
 	"github.com/absmach/mproxy/pkg/session"
@@ -52,15 +53,20 @@ var channelRegExp = regexp.MustCompile(`^\/?channels\/([\w\-]+)\/messages(\/[^?]
 type handler struct {
 	publisher messaging.Publisher
 	auth      magistrala.AuthzServiceClient
+	certAuth  certauth.Authenticator
 	logger    *slog.Logger
 }
 
-// NewHandler creates new Handler entity.
-func NewHandler(publisher messaging.Publisher, logger *slog.Logger, authClient magistrala.AuthzServiceClient) session.Handler {
+// NewHandler creates new Handler entity. Unless the certificate authenticator
+// is nil, the things presenting the client certificate are authenticated
+// using the certificate instead of the thing key, and authorized by the ID of
+// the thing the certificate is issued to.
+func NewHandler(publisher messaging.Publisher, logger *slog.Logger, authClient magistrala.AuthzServiceClient, certAuth certauth.Authenticator) session.Handler {
 	return &handler{
 		logger:    logger,
 		publisher: publisher,
 		auth:      authClient,
+		certAuth:  certAuth,
 	}
 }
 
@@ -72,14 +78,9 @@ func (h *handler) AuthConnect(ctx context.Context) error {
 		return ErrClientNotInitialized
 	}
 
-	var tok string
-	switch {
-	case string(s.Password) == "":
-		return errors.Wrap(apiutil.ErrValidation, apiutil.ErrBearerKey)
-	case strings.HasPrefix(string(s.Password), "Thing"):
-		tok = extractThingKey(string(s.Password))
-	default:
-		tok = string(s.Password)
+	tok, _, err := h.subject(ctx, s)
+	if err != nil {
+		return err
 	}
 
 	h.logger.Info(fmt.Sprintf(LogInfoConnected, tok))
@@ -135,17 +136,13 @@ func (h *handler) Publish(ctx context.Context, topic *string, payload *[]byte) e
 		Payload:  *payload,
 		Created:  time.Now().UnixNano(),
 	}
-	var tok string
-	switch {
-	case string(s.Password) == "":
-		return errors.Wrap(apiutil.ErrValidation, apiutil.ErrBearerKey)
-	case strings.HasPrefix(string(s.Password), "Thing"):
-		tok = extractThingKey(string(s.Password))
-	default:
-		tok = string(s.Password)
+	tok, kind, err := h.subject(ctx, s)
+	if err != nil {
+		return err
 	}
 	ar := &magistrala.AuthorizeReq{
 		Subject:     tok,
+		SubjectKind: kind,
 		Object:      msg.Channel,
 		SubjectType: auth.ThingType,
 		Permission:  auth.PublishPermission,
@@ -182,6 +179,28 @@ func (h *handler) Disconnect(ctx context.Context) error {
 	return nil
 }
 
+// subject returns the authorization subject and subject kind of the session
+// thing. Things presenting the client certificate are identified by the ID
+// of the thing the certificate is issued to, and the rest by the thing key
+// of the session password.
+func (h *handler) subject(ctx context.Context, s *session.Session) (string, string, error) {
+	cert := certauth.ClientCert(ctx, s.Cert)
+	switch {
+	case h.certAuth != nil && cert != nil:
+		id, err := h.certAuth.Authenticate(ctx, cert)
+		if err != nil {
+			return "", "", err
+		}
+		return id, auth.ThingsKind, nil
+	case string(s.Password) == "":
+		return "", "", errors.Wrap(apiutil.ErrValidation, apiutil.ErrBearerKey)
+	case strings.HasPrefix(string(s.Password), "Thing"):
+		return extractThingKey(string(s.Password)), "", nil
+	default:
+		return string(s.Password), "", nil
+	}
+}
+
 func parseSubtopic(subtopic string) (string, error) {
 	if subtopic == "" {
 		return subtopic, nil
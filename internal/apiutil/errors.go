@@ -105,6 +105,21 @@ var (
 	// ErrInvalidComparator indicates an invalid comparator.
 	ErrInvalidComparator = errors.New("invalid comparator")
 
+	// ErrInvalidAggregation indicates an invalid aggregation.
+	ErrInvalidAggregation = errors.New("invalid aggregation")
+
+	// ErrInvalidInterval indicates an invalid aggregation interval.
+	ErrInvalidInterval = errors.New("invalid aggregation interval")
+
+	// ErrInvalidExportOutput indicates an invalid messages export output.
+	ErrInvalidExportOutput = errors.New("invalid export output")
+
+	// ErrInvalidCursor indicates an invalid page cursor.
+	ErrInvalidCursor = errors.New("invalid page cursor")
+
+	// ErrInvalidFilter indicates an invalid messages filter expression.
+	ErrInvalidFilter = errors.New("invalid filter expression")
+
 	// ErrMissingMemberType indicates missing group member type.
 	ErrMissingMemberType = errors.New("missing group member type")
 
@@ -7,11 +7,12 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"time"
 
+	"github.com/absmach/magistrala/pkg/errors"
 	"github.com/absmach/magistrala/pkg/messaging"
+	"github.com/absmach/senml"
 )
 
 const protocol = "lora"
@@ -59,24 +60,48 @@ type Service interface {
 
 	// Publish forwards messages from the LoRa MQTT broker to Magistrala Message Broker
 	Publish(ctx context.Context, msg *Message) error
+
+	// Downlink forwards messages from Magistrala Message Broker to the LoRa
+	// device through the LoRa MQTT broker
+	Downlink(ctx context.Context, msg *messaging.Message) error
+
+	// DownlinkEvent forwards downlink ack and error events from the LoRa MQTT
+	// broker to Magistrala Message Broker
+	DownlinkEvent(ctx context.Context, ev *DownlinkEvent) error
+
+	// SaveCodec stores the payload codec of the thing or channel
+	SaveCodec(ctx context.Context, id string, cfg CodecConfig) error
+
+	// RemoveCodec removes the payload codec of the thing or channel
+	RemoveCodec(ctx context.Context, id string) error
 }
 
 var _ Service = (*adapterService)(nil)
 
 type adapterService struct {
 	publisher  messaging.Publisher
+	downlinks  DownlinkPublisher
 	thingsRM   RouteMapRepository
 	channelsRM RouteMapRepository
 	connectRM  RouteMapRepository
+	codecs     CodecRepository
+	downCfg    DownlinkConfig
+	radio      string
 }
 
-// New instantiates the LoRa adapter implementation.
-func New(publisher messaging.Publisher, thingsRM, channelsRM, connectRM RouteMapRepository) Service {
+// New instantiates the LoRa adapter implementation. The radio is the mode of
+// forwarding of the uplink radio metadata, one of RadioOff, RadioRecords and
+// RadioSubtopic.
+func New(publisher messaging.Publisher, downlinks DownlinkPublisher, thingsRM, channelsRM, connectRM RouteMapRepository, codecs CodecRepository, downCfg DownlinkConfig, radio string) Service {
 	return &adapterService{
 		publisher:  publisher,
+		downlinks:  downlinks,
 		thingsRM:   thingsRM,
 		channelsRM: channelsRM,
 		connectRM:  connectRM,
+		codecs:     codecs,
+		downCfg:    downCfg,
+		radio:      radio,
 	}
 }
 
@@ -100,14 +125,19 @@ func (as *adapterService) Publish(ctx context.Context, m *Message) error {
 	}
 
 	// Use the SenML message decoded on LoRa Server application if
-	// field Object isn't empty. Otherwise, decode standard field Data.
+	// field Object isn't empty. Otherwise, decode standard field Data
+	// using the thing or channel codec, if any.
 	var payload []byte
 	switch m.Object {
 	case nil:
-		payload, err = base64.StdEncoding.DecodeString(m.Data)
+		data, err := base64.StdEncoding.DecodeString(m.Data)
 		if err != nil {
 			return ErrMalformedMessage
 		}
+		payload, err = as.decode(ctx, thingID, chanID, data)
+		if err != nil {
+			return err
+		}
 	default:
 		jo, err := json.Marshal(m.Object)
 		if err != nil {
@@ -116,6 +146,11 @@ func (as *adapterService) Publish(ctx context.Context, m *Message) error {
 		payload = jo
 	}
 
+	payload, radio, err := withRadio(as.radio, m, payload)
+	if err != nil {
+		return err
+	}
+
 	// Publish on Magistrala Message broker
 	msg := messaging.Message{
 		Publisher: thingID,
@@ -124,10 +159,148 @@ func (as *adapterService) Publish(ctx context.Context, m *Message) error {
 		Payload:   payload,
 		Created:   time.Now().UnixNano(),
 	}
+	if err := as.publisher.Publish(ctx, msg.Channel, &msg); err != nil {
+		return err
+	}
+	if radio == nil {
+		return nil
+	}
+
+	rmsg := messaging.Message{
+		Publisher: thingID,
+		Protocol:  protocol,
+		Channel:   chanID,
+		Subtopic:  RadioTopic,
+		Payload:   radio,
+		Created:   msg.Created,
+	}
+
+	return as.publisher.Publish(ctx, rmsg.Channel, &rmsg)
+}
+
+// Downlink forwards messages from Magistrala Message broker to Lora MQTT broker.
+func (as *adapterService) Downlink(ctx context.Context, msg *messaging.Message) error {
+	dl, err := parseDownlink(msg.GetSubtopic(), as.downCfg)
+	if err != nil {
+		return err
+	}
+
+	// Get route map of lora device
+	devEUI, err := as.thingsRM.Get(ctx, dl.thingID)
+	if err != nil {
+		return ErrNotFoundDev
+	}
+
+	// Get route map of lora application
+	appID, err := as.channelsRM.Get(ctx, msg.GetChannel())
+	if err != nil {
+		return ErrNotFoundApp
+	}
+
+	c := fmt.Sprintf("%s:%s", msg.GetChannel(), dl.thingID)
+	if _, err := as.connectRM.Get(ctx, c); err != nil {
+		return ErrNotConnected
+	}
+
+	payload, err := json.Marshal(DownlinkMessage{
+		Confirmed: dl.confirmed,
+		FPort:     dl.fPort,
+		Data:      base64.StdEncoding.EncodeToString(msg.GetPayload()),
+	})
+	if err != nil {
+		return err
+	}
+
+	topic := fmt.Sprintf("application/%s/device/%s/command/down", appID, devEUI)
+
+	return as.downlinks.Publish(ctx, topic, payload)
+}
+
+// DownlinkEvent forwards downlink events from Lora MQTT broker to Magistrala
+// Message broker. The event is published to the channel subtopic
+// down.<thingID>.<event>.
+func (as *adapterService) DownlinkEvent(ctx context.Context, ev *DownlinkEvent) error {
+	if ev.Event != AckEvent && ev.Event != ErrorEvent {
+		return ErrMalformedMessage
+	}
+
+	thingID, err := as.thingsRM.Get(ctx, ev.DevEUI)
+	if err != nil {
+		return ErrNotFoundDev
+	}
+
+	chanID, err := as.channelsRM.Get(ctx, ev.ApplicationID)
+	if err != nil {
+		return ErrNotFoundApp
+	}
+
+	c := fmt.Sprintf("%s:%s", chanID, thingID)
+	if _, err := as.connectRM.Get(ctx, c); err != nil {
+		return ErrNotConnected
+	}
+
+	payload, err := json.Marshal(downlinkEvent{
+		Event:        ev.Event,
+		Acknowledged: ev.Acknowledged,
+		FCnt:         ev.FCnt,
+		Type:         ev.Type,
+		Error:        ev.Error,
+	})
+	if err != nil {
+		return err
+	}
+
+	msg := messaging.Message{
+		Publisher: thingID,
+		Protocol:  protocol,
+		Channel:   chanID,
+		Subtopic:  fmt.Sprintf("%s.%s.%s", DownlinkSubtopic, thingID, ev.Event),
+		Payload:   payload,
+		Created:   time.Now().UnixNano(),
+	}
 
 	return as.publisher.Publish(ctx, msg.Channel, &msg)
 }
 
+// decode decodes the payload to SenML using the codec of the thing, or the
+// codec of the channel if the thing has none. The payload is returned as is
+// if neither has a codec.
+func (as *adapterService) decode(ctx context.Context, thingID, chanID string, data []byte) ([]byte, error) {
+	cfg, err := as.codecs.Get(ctx, thingID)
+	if errors.Contains(err, ErrNotFoundCodec) {
+		cfg, err = as.codecs.Get(ctx, chanID)
+	}
+	switch {
+	case errors.Contains(err, ErrNotFoundCodec):
+		return data, nil
+	case err != nil:
+		return nil, err
+	}
+
+	codec, err := NewCodec(cfg)
+	if err != nil {
+		return nil, err
+	}
+	recs, err := codec.Decode(data)
+	if err != nil {
+		return nil, errors.Wrap(ErrMalformedMessage, err)
+	}
+
+	return senml.Encode(senml.Pack{Records: recs}, senml.JSON)
+}
+
+func (as *adapterService) SaveCodec(ctx context.Context, id string, cfg CodecConfig) error {
+	if _, err := NewCodec(cfg); err != nil {
+		return err
+	}
+
+	return as.codecs.Save(ctx, id, cfg)
+}
+
+func (as *adapterService) RemoveCodec(ctx context.Context, id string) error {
+	return as.codecs.Remove(ctx, id)
+}
+
 func (as *adapterService) CreateThing(ctx context.Context, thingID, devEUI string) error {
 	return as.thingsRM.Save(ctx, thingID, devEUI)
 }
@@ -137,6 +310,10 @@ func (as *adapterService) UpdateThing(ctx context.Context, thingID, devEUI strin
 }
 
 func (as *adapterService) RemoveThing(ctx context.Context, thingID string) error {
+	if err := as.codecs.Remove(ctx, thingID); err != nil {
+		return err
+	}
+
 	return as.thingsRM.Remove(ctx, thingID)
 }
 
@@ -149,6 +326,10 @@ func (as *adapterService) UpdateChannel(ctx context.Context, chanID, appID strin
 }
 
 func (as *adapterService) RemoveChannel(ctx context.Context, chanID string) error {
+	if err := as.codecs.Remove(ctx, chanID); err != nil {
+		return err
+	}
+
 	return as.channelsRM.Remove(ctx, chanID)
 }
 
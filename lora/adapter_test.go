@@ -6,12 +6,15 @@ package lora_test
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"testing"
 
 	"github.com/absmach/magistrala/lora"
 	"github.com/absmach/magistrala/lora/mocks"
 	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/pkg/messaging"
+	"github.com/absmach/senml"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -25,20 +28,24 @@ const (
 	chanID2  = "chanID-2"
 	devEUI2  = "devEUI-2"
 	appID2   = "appID-2"
+	thingID3 = "thingID-3"
+	devEUI3  = "devEUI-3"
 	msg      = `[{"bn":"msg-base-name","n":"temperature","v": 17},{"n":"humidity","v": 56}]`
 )
 
-func newService() lora.Service {
+func newService() (lora.Service, *mocks.DownlinkPublisher) {
 	pub := mocks.NewPublisher()
+	downlinks := mocks.NewDownlinkPublisher()
 	thingsRM := mocks.NewRouteMap()
 	channelsRM := mocks.NewRouteMap()
 	connsRM := mocks.NewRouteMap()
+	codecs := mocks.NewCodecRepository()
 
-	return lora.New(pub, thingsRM, channelsRM, connsRM)
+	return lora.New(pub, downlinks, thingsRM, channelsRM, connsRM, codecs, lora.DownlinkConfig{FPort: 1}, lora.RadioOff), downlinks
 }
 
 func TestPublish(t *testing.T) {
-	svc := newService()
+	svc, _ := newService()
 
 	err := svc.CreateChannel(context.Background(), chanID, appID)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
@@ -55,13 +62,33 @@ func TestPublish(t *testing.T) {
 	err = svc.CreateThing(context.Background(), thingID2, devEUI2)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 
+	err = svc.CreateThing(context.Background(), thingID3, devEUI3)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.ConnectThing(context.Background(), chanID, thingID3)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.SaveCodec(context.Background(), thingID3, lora.CodecConfig{Type: lora.CayenneLPP})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
 	msgBase64 := base64.StdEncoding.EncodeToString([]byte(msg))
+	lppBase64 := base64.StdEncoding.EncodeToString([]byte{0x03, 0x67, 0x01, 0x10})
+	truncatedBase64 := base64.StdEncoding.EncodeToString([]byte{0x03, 0x67, 0x01})
 
 	cases := []struct {
 		desc string
 		err  error
 		msg  lora.Message
 	}{
+		{
+			desc: "publish message with existing route-map and valid codec Data",
+			err:  nil,
+			msg: lora.Message{
+				ApplicationID: appID,
+				DevEUI:        devEUI3,
+				Data:          lppBase64,
+			},
+		},
 		{
 			desc: "publish message with existing route-map and valid Data",
 			err:  nil,
@@ -72,7 +99,7 @@ func TestPublish(t *testing.T) {
 			},
 		},
 		{
-			desc: "publish message with existing route-map and invalid Data",
+			desc: "publish message with existing route-map and invalid base64 Data",
 			err:  lora.ErrMalformedMessage,
 			msg: lora.Message{
 				ApplicationID: appID,
@@ -80,6 +107,15 @@ func TestPublish(t *testing.T) {
 				Data:          "wrong",
 			},
 		},
+		{
+			desc: "publish message with existing route-map and invalid codec Data",
+			err:  lora.ErrMalformedMessage,
+			msg: lora.Message{
+				ApplicationID: appID,
+				DevEUI:        devEUI3,
+				Data:          truncatedBase64,
+			},
+		},
 		{
 			desc: "publish message with non existing appID route-map",
 			err:  lora.ErrNotFoundApp,
@@ -111,3 +147,358 @@ func TestPublish(t *testing.T) {
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
+
+func TestDownlink(t *testing.T) {
+	svc, downlinks := newService()
+
+	err := svc.CreateChannel(context.Background(), chanID, appID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.CreateThing(context.Background(), thingID, devEUI)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.ConnectThing(context.Background(), chanID, thingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.CreateChannel(context.Background(), chanID2, appID2)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.CreateThing(context.Background(), thingID2, devEUI2)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	topic := fmt.Sprintf("application/%s/device/%s/command/down", appID, devEUI)
+	data := base64.StdEncoding.EncodeToString([]byte(msg))
+
+	cases := []struct {
+		desc     string
+		channel  string
+		subtopic string
+		downlink lora.DownlinkMessage
+		err      error
+	}{
+		{
+			desc:     "send downlink with default fPort",
+			channel:  chanID,
+			subtopic: fmt.Sprintf("down.%s", thingID),
+			downlink: lora.DownlinkMessage{FPort: 1, Data: data},
+			err:      nil,
+		},
+		{
+			desc:     "send downlink with fPort",
+			channel:  chanID,
+			subtopic: fmt.Sprintf("down.%s.10", thingID),
+			downlink: lora.DownlinkMessage{FPort: 10, Data: data},
+			err:      nil,
+		},
+		{
+			desc:     "send confirmed downlink with fPort",
+			channel:  chanID,
+			subtopic: fmt.Sprintf("down.%s.10.confirmed", thingID),
+			downlink: lora.DownlinkMessage{Confirmed: true, FPort: 10, Data: data},
+			err:      nil,
+		},
+		{
+			desc:     "send downlink with invalid fPort",
+			channel:  chanID,
+			subtopic: fmt.Sprintf("down.%s.224", thingID),
+			err:      lora.ErrMalformedMessage,
+		},
+		{
+			desc:     "send downlink without thing",
+			channel:  chanID,
+			subtopic: "down",
+			err:      lora.ErrMalformedMessage,
+		},
+		{
+			desc:     "send downlink with non existing devEUI route-map",
+			channel:  chanID,
+			subtopic: "down.wrong",
+			err:      lora.ErrNotFoundDev,
+		},
+		{
+			desc:     "send downlink with non existing appID route-map",
+			channel:  "wrong",
+			subtopic: fmt.Sprintf("down.%s", thingID),
+			err:      lora.ErrNotFoundApp,
+		},
+		{
+			desc:     "send downlink with non existing connection route-map",
+			channel:  chanID2,
+			subtopic: fmt.Sprintf("down.%s", thingID2),
+			err:      lora.ErrNotConnected,
+		},
+	}
+
+	for _, tc := range cases {
+		m := messaging.Message{
+			Channel:  tc.channel,
+			Subtopic: tc.subtopic,
+			Payload:  []byte(msg),
+		}
+		err := svc.Downlink(context.Background(), &m)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			var dl lora.DownlinkMessage
+			err := json.Unmarshal(downlinks.Payload(topic), &dl)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+			assert.Equal(t, tc.downlink, dl, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.downlink, dl))
+		}
+	}
+}
+
+func TestDownlinkEvent(t *testing.T) {
+	svc, _ := newService()
+
+	err := svc.CreateChannel(context.Background(), chanID, appID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.CreateThing(context.Background(), thingID, devEUI)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.ConnectThing(context.Background(), chanID, thingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.CreateChannel(context.Background(), chanID2, appID2)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.CreateThing(context.Background(), thingID2, devEUI2)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		event lora.DownlinkEvent
+		err   error
+	}{
+		{
+			desc: "publish ack event",
+			event: lora.DownlinkEvent{
+				ApplicationID: appID,
+				DevEUI:        devEUI,
+				Acknowledged:  true,
+				FCnt:          1,
+				Event:         lora.AckEvent,
+			},
+			err: nil,
+		},
+		{
+			desc: "publish error event",
+			event: lora.DownlinkEvent{
+				ApplicationID: appID,
+				DevEUI:        devEUI,
+				Type:          "DOWNLINK_PAYLOAD_SIZE",
+				Error:         "payload exceeds max size",
+				Event:         lora.ErrorEvent,
+			},
+			err: nil,
+		},
+		{
+			desc: "publish unknown event",
+			event: lora.DownlinkEvent{
+				ApplicationID: appID,
+				DevEUI:        devEUI,
+				Event:         "up",
+			},
+			err: lora.ErrMalformedMessage,
+		},
+		{
+			desc: "publish event with non existing devEUI route-map",
+			event: lora.DownlinkEvent{
+				ApplicationID: appID,
+				DevEUI:        "wrong",
+				Event:         lora.AckEvent,
+			},
+			err: lora.ErrNotFoundDev,
+		},
+		{
+			desc: "publish event with non existing appID route-map",
+			event: lora.DownlinkEvent{
+				ApplicationID: "wrong",
+				DevEUI:        devEUI,
+				Event:         lora.AckEvent,
+			},
+			err: lora.ErrNotFoundApp,
+		},
+		{
+			desc: "publish event with non existing connection route-map",
+			event: lora.DownlinkEvent{
+				ApplicationID: appID2,
+				DevEUI:        devEUI2,
+				Event:         lora.AckEvent,
+			},
+			err: lora.ErrNotConnected,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.DownlinkEvent(context.Background(), &tc.event)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestSaveCodec(t *testing.T) {
+	svc, _ := newService()
+
+	cases := []struct {
+		desc  string
+		codec lora.CodecConfig
+		err   error
+	}{
+		{
+			desc:  "save Cayenne LPP codec",
+			codec: lora.CodecConfig{Type: lora.CayenneLPP},
+			err:   nil,
+		},
+		{
+			desc: "save byte layout codec",
+			codec: lora.CodecConfig{
+				Type:   lora.ByteLayout,
+				Layout: []lora.LayoutField{{Name: "temperature", Type: "i16", Offset: 0}},
+			},
+			err: nil,
+		},
+		{
+			desc:  "save byte layout codec without layout",
+			codec: lora.CodecConfig{Type: lora.ByteLayout},
+			err:   lora.ErrInvalidCodec,
+		},
+		{
+			desc:  "save script codec",
+			codec: lora.CodecConfig{Type: lora.Script, Script: "temperature:Cel = i16(0) / 10"},
+			err:   nil,
+		},
+		{
+			desc:  "save script codec with invalid script",
+			codec: lora.CodecConfig{Type: lora.Script, Script: "temperature = i16(0"},
+			err:   lora.ErrInvalidCodec,
+		},
+		{
+			desc:  "save unknown codec",
+			codec: lora.CodecConfig{Type: "unknown"},
+			err:   lora.ErrInvalidCodec,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.SaveCodec(context.Background(), thingID, tc.codec)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+type recordingPublisher struct {
+	msgs []*messaging.Message
+}
+
+func (pub *recordingPublisher) Publish(_ context.Context, _ string, msg *messaging.Message) error {
+	pub.msgs = append(pub.msgs, msg)
+	return nil
+}
+
+func (pub *recordingPublisher) Close() error {
+	return nil
+}
+
+func TestPublishRadio(t *testing.T) {
+	m := lora.Message{
+		ApplicationID: appID,
+		DevEUI:        devEUI,
+		RxInfo: lora.RxInfo{
+			{Mac: "0101010101010101", Rssi: -100, LoRaSNR: 5},
+			{Mac: "0202020202020202", Rssi: -90, LoRaSNR: 7.5, Latitude: 44.8, Longitude: 20.5, Altitude: 117},
+		},
+		TxInfo: lora.TxInfo{
+			Frequency: 868100000,
+			DataRate:  lora.DataRate{Modulation: "LORA", Bandwidth: 125, SpreadFactor: 7},
+		},
+		FCnt:                10,
+		DeviceStatusBattery: "254",
+		DeviceStatusMrgin:   "5",
+	}
+	radio := []senml.Record{
+		record("rssi", "dBm", -90),
+		record("snr", "dB", 7.5),
+		record("gateways", "", 2),
+		record("frequency", "Hz", 868100000),
+		record("spreading_factor", "", 7),
+		record("bandwidth", "Hz", 125000),
+		record("f_cnt", "", 10),
+		record("battery", "%EL", 100),
+		record("margin", "dB", 5),
+		record("gateway_0101010101010101_rssi", "dBm", -100),
+		record("gateway_0101010101010101_snr", "dB", 5),
+		record("gateway_0202020202020202_rssi", "dBm", -90),
+		record("gateway_0202020202020202_snr", "dB", 7.5),
+		record("gateway_0202020202020202_lat", "lat", 44.8),
+		record("gateway_0202020202020202_lon", "lon", 20.5),
+		record("gateway_0202020202020202_alt", "m", 117),
+	}
+	senmlData := base64.StdEncoding.EncodeToString([]byte(`[{"n":"temperature","v":17}]`))
+	rawData := base64.StdEncoding.EncodeToString([]byte{0x01, 0x02})
+
+	cases := []struct {
+		desc    string
+		mode    string
+		data    string
+		payload []senml.Record
+		radio   []senml.Record
+	}{
+		{
+			desc: "publish SenML message without radio metadata",
+			mode: lora.RadioOff,
+			data: senmlData,
+		},
+		{
+			desc:    "publish SenML message with radio metadata records",
+			mode:    lora.RadioRecords,
+			data:    senmlData,
+			payload: append([]senml.Record{record("temperature", "", 17)}, radio...),
+		},
+		{
+			desc:  "publish raw message with radio metadata records",
+			mode:  lora.RadioRecords,
+			data:  rawData,
+			radio: radio,
+		},
+		{
+			desc:  "publish SenML message with radio metadata subtopic",
+			mode:  lora.RadioSubtopic,
+			data:  senmlData,
+			radio: radio,
+		},
+	}
+
+	for _, tc := range cases {
+		pub := &recordingPublisher{}
+		thingsRM := mocks.NewRouteMap()
+		channelsRM := mocks.NewRouteMap()
+		svc := lora.New(pub, mocks.NewDownlinkPublisher(), thingsRM, channelsRM, mocks.NewRouteMap(), mocks.NewCodecRepository(), lora.DownlinkConfig{FPort: 1}, tc.mode)
+
+		err := svc.CreateChannel(context.Background(), chanID, appID)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		err = svc.CreateThing(context.Background(), thingID, devEUI)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		err = svc.ConnectThing(context.Background(), chanID, thingID)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+
+		msg := m
+		msg.Data = tc.data
+		err = svc.Publish(context.Background(), &msg)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+
+		count := 1
+		if tc.radio != nil {
+			count = 2
+		}
+		require.Len(t, pub.msgs, count, fmt.Sprintf("%s: expected %d messages got %d\n", tc.desc, count, len(pub.msgs)))
+		if tc.payload != nil {
+			p, err := senml.Decode(pub.msgs[0].GetPayload(), senml.JSON)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+			assert.Equal(t, tc.payload, p.Records, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.payload, p.Records))
+		}
+		if tc.radio != nil {
+			assert.Equal(t, lora.RadioTopic, pub.msgs[1].GetSubtopic(), fmt.Sprintf("%s: expected subtopic %s got %s\n", tc.desc, lora.RadioTopic, pub.msgs[1].GetSubtopic()))
+			p, err := senml.Decode(pub.msgs[1].GetPayload(), senml.JSON)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+			assert.Equal(t, tc.radio, p.Records, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.radio, p.Records))
+		}
+	}
+}
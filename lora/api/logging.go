@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/absmach/magistrala/lora"
+	"github.com/absmach/magistrala/pkg/messaging"
 )
 
 var _ lora.Service = (*loggingMiddleware)(nil)
@@ -187,3 +188,79 @@ func (lm loggingMiddleware) Publish(ctx context.Context, msg *lora.Message) (err
 
 	return lm.svc.Publish(ctx, msg)
 }
+
+func (lm loggingMiddleware) Downlink(ctx context.Context, msg *messaging.Message) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.Group("message",
+				slog.String("channel_id", msg.GetChannel()),
+				slog.String("subtopic", msg.GetSubtopic()),
+			),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Downlink failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Downlink completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.Downlink(ctx, msg)
+}
+
+func (lm loggingMiddleware) DownlinkEvent(ctx context.Context, ev *lora.DownlinkEvent) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.Group("event",
+				slog.String("application_id", ev.ApplicationID),
+				slog.String("device_eui", ev.DevEUI),
+				slog.String("event", ev.Event),
+			),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Downlink event failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Downlink event completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.DownlinkEvent(ctx, ev)
+}
+
+func (lm loggingMiddleware) SaveCodec(ctx context.Context, id string, cfg lora.CodecConfig) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("id", id),
+			slog.String("codec", cfg.Type),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Save codec failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Save codec completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.SaveCodec(ctx, id, cfg)
+}
+
+func (lm loggingMiddleware) RemoveCodec(ctx context.Context, id string) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("id", id),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Remove codec failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Remove codec completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.RemoveCodec(ctx, id)
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/absmach/magistrala/lora"
+	"github.com/absmach/magistrala/pkg/messaging"
 	"github.com/go-kit/kit/metrics"
 )
 
@@ -110,3 +111,39 @@ func (mm *metricsMiddleware) Publish(ctx context.Context, msg *lora.Message) err
 
 	return mm.svc.Publish(ctx, msg)
 }
+
+func (mm *metricsMiddleware) Downlink(ctx context.Context, msg *messaging.Message) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "downlink").Add(1)
+		mm.latency.With("method", "downlink").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Downlink(ctx, msg)
+}
+
+func (mm *metricsMiddleware) DownlinkEvent(ctx context.Context, ev *lora.DownlinkEvent) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "downlink_event").Add(1)
+		mm.latency.With("method", "downlink_event").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.DownlinkEvent(ctx, ev)
+}
+
+func (mm *metricsMiddleware) SaveCodec(ctx context.Context, id string, cfg lora.CodecConfig) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "save_codec").Add(1)
+		mm.latency.With("method", "save_codec").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.SaveCodec(ctx, id, cfg)
+}
+
+func (mm *metricsMiddleware) RemoveCodec(ctx context.Context, id string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "remove_codec").Add(1)
+		mm.latency.With("method", "remove_codec").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RemoveCodec(ctx, id)
+}
@@ -0,0 +1,122 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package lora
+
+import (
+	"fmt"
+
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/senml"
+)
+
+var errCayenneType = errors.New("unsupported Cayenne LPP data type")
+
+// lppType describes Cayenne LPP data type. Values are signed or unsigned big
+// endian integers of the given size, divided by the resolution.
+type lppType struct {
+	name       string
+	unit       string
+	size       int
+	signed     bool
+	resolution float64
+	// axes are the names of the values of multi-value types.
+	axes []string
+}
+
+// lppTypes maps Cayenne LPP data type IDs to data types
+// (https://github.com/myDevicesIoT/cayenne-docs/blob/master/docs/LORA.md).
+var lppTypes = map[byte]lppType{
+	0:   {name: "digital_input", size: 1, resolution: 1},
+	1:   {name: "digital_output", size: 1, resolution: 1},
+	2:   {name: "analog_input", size: 2, signed: true, resolution: 100},
+	3:   {name: "analog_output", size: 2, signed: true, resolution: 100},
+	101: {name: "illuminance", unit: "lx", size: 2, resolution: 1},
+	102: {name: "presence", size: 1, resolution: 1},
+	103: {name: "temperature", unit: "Cel", size: 2, signed: true, resolution: 10},
+	104: {name: "humidity", unit: "%RH", size: 1, resolution: 2},
+	113: {name: "accelerometer", unit: "g", size: 2, signed: true, resolution: 1000, axes: []string{"x", "y", "z"}},
+	115: {name: "barometer", unit: "hPa", size: 2, resolution: 10},
+	134: {name: "gyrometer", unit: "deg/s", size: 2, signed: true, resolution: 100, axes: []string{"x", "y", "z"}},
+}
+
+// gpsType is Cayenne LPP GPS location data type, which has a different
+// resolution for the altitude.
+const gpsType = 136
+
+type cayenneLPP struct{}
+
+func newCayenneLPP(_ CodecConfig) (Codec, error) {
+	return cayenneLPP{}, nil
+}
+
+// Decode decodes Cayenne LPP payload. Each value is prefixed with the channel
+// and the data type, and is decoded to the record named <type>_<channel>.
+func (c cayenneLPP) Decode(data []byte) ([]senml.Record, error) {
+	var recs []senml.Record
+	for len(data) > 0 {
+		if len(data) < 2 {
+			return nil, ErrMalformedMessage
+		}
+		ch, id := data[0], data[1]
+		data = data[2:]
+
+		if id == gpsType {
+			if len(data) < 9 {
+				return nil, ErrMalformedMessage
+			}
+			name := fmt.Sprintf("gps_%d", ch)
+			recs = append(recs,
+				numRecord(name+"_lat", "lat", float64(readInt(data[0:3], true))/10000),
+				numRecord(name+"_lon", "lon", float64(readInt(data[3:6], true))/10000),
+				numRecord(name+"_alt", "m", float64(readInt(data[6:9], true))/100),
+			)
+			data = data[9:]
+			continue
+		}
+
+		t, ok := lppTypes[id]
+		if !ok {
+			return nil, errors.Wrap(errCayenneType, fmt.Errorf("type %d", id))
+		}
+		axes := t.axes
+		if len(axes) == 0 {
+			axes = []string{""}
+		}
+		if len(data) < len(axes)*t.size {
+			return nil, ErrMalformedMessage
+		}
+		for _, axis := range axes {
+			name := fmt.Sprintf("%s_%d", t.name, ch)
+			if axis != "" {
+				name = fmt.Sprintf("%s_%s", name, axis)
+			}
+			v := float64(readInt(data[:t.size], t.signed)) / t.resolution
+			recs = append(recs, numRecord(name, t.unit, v))
+			data = data[t.size:]
+		}
+	}
+
+	return recs, nil
+}
+
+// readInt reads big endian integer, sign extending it if signed.
+func readInt(b []byte, signed bool) int64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	if signed && len(b) > 0 && b[0]&0x80 != 0 {
+		return int64(v) - 1<<(8*len(b))
+	}
+
+	return int64(v)
+}
+
+func numRecord(name, unit string, v float64) senml.Record {
+	return senml.Record{
+		Name:  name,
+		Unit:  unit,
+		Value: &v,
+	}
+}
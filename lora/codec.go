@@ -0,0 +1,90 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package lora
+
+import (
+	"context"
+
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/senml"
+)
+
+const (
+	// CayenneLPP is the Cayenne Low Power Payload codec.
+	CayenneLPP = "cayenne_lpp"
+
+	// ByteLayout is the codec decoding the payload fields at the configured
+	// byte offsets.
+	ByteLayout = "byte_layout"
+
+	// Script is the codec decoding the payload using the decoder script.
+	Script = "script"
+)
+
+var (
+	// ErrInvalidCodec indicates malformed or unsupported codec configuration.
+	ErrInvalidCodec = errors.New("invalid payload codec")
+
+	// ErrNotFoundCodec indicates a non-existent codec for a thing or channel.
+	ErrNotFoundCodec = errors.New("codec not found")
+)
+
+// Codec decodes the LoRa device payload to the SenML records.
+type Codec interface {
+	// Decode decodes the raw device payload.
+	Decode(data []byte) ([]senml.Record, error)
+}
+
+// CodecConfig contains the codec type and its type specific configuration.
+type CodecConfig struct {
+	Type   string        `json:"type"`
+	Layout []LayoutField `json:"layout,omitempty"`
+	Script string        `json:"script,omitempty"`
+}
+
+// LayoutField describes the payload field decoded by the byte layout codec.
+type LayoutField struct {
+	Name string `json:"name"`
+	Unit string `json:"unit,omitempty"`
+	// Type is one of u8, i8, u16, i16, u32, i32, f32 and bool.
+	Type         string `json:"type"`
+	Offset       int    `json:"offset"`
+	LittleEndian bool   `json:"little_endian,omitempty"`
+	// Scale multiplies the numeric field value. Zero stands for no scaling.
+	Scale float64 `json:"scale,omitempty"`
+}
+
+// codecs is the registry of the supported codecs.
+var codecs = map[string]func(cfg CodecConfig) (Codec, error){
+	CayenneLPP: newCayenneLPP,
+	ByteLayout: newByteLayout,
+	Script:     newScript,
+}
+
+// NewCodec returns the codec of the given configuration.
+func NewCodec(cfg CodecConfig) (Codec, error) {
+	newCodec, ok := codecs[cfg.Type]
+	if !ok {
+		return nil, ErrInvalidCodec
+	}
+	c, err := newCodec(cfg)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidCodec, err)
+	}
+
+	return c, nil
+}
+
+// CodecRepository stores the codecs of the things and channels. The codec of
+// the thing takes precedence over the codec of the channel.
+type CodecRepository interface {
+	// Save stores the codec of the thing or channel.
+	Save(ctx context.Context, id string, cfg CodecConfig) error
+
+	// Get returns the codec of the thing or channel.
+	Get(ctx context.Context, id string) (CodecConfig, error)
+
+	// Remove removes the codec of the thing or channel.
+	Remove(ctx context.Context, id string) error
+}
@@ -0,0 +1,181 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package lora_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/absmach/magistrala/lora"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/senml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func record(name, unit string, v float64) senml.Record {
+	return senml.Record{Name: name, Unit: unit, Value: &v}
+}
+
+func TestCodecDecode(t *testing.T) {
+	alarm := true
+
+	cases := []struct {
+		desc    string
+		codec   lora.CodecConfig
+		data    []byte
+		records []senml.Record
+		err     error
+	}{
+		{
+			desc:  "decode Cayenne LPP payload",
+			codec: lora.CodecConfig{Type: lora.CayenneLPP},
+			data:  []byte{0x03, 0x67, 0x01, 0x10, 0x05, 0x68, 0x50},
+			records: []senml.Record{
+				record("temperature_3", "Cel", 27.2),
+				record("humidity_5", "%RH", 40),
+			},
+		},
+		{
+			desc:  "decode Cayenne LPP multi-value payload",
+			codec: lora.CodecConfig{Type: lora.CayenneLPP},
+			data:  []byte{0x06, 0x71, 0x04, 0xd2, 0xfb, 0x2e, 0x00, 0x00},
+			records: []senml.Record{
+				record("accelerometer_6_x", "g", 1.234),
+				record("accelerometer_6_y", "g", -1.234),
+				record("accelerometer_6_z", "g", 0),
+			},
+		},
+		{
+			desc:  "decode Cayenne LPP GPS payload",
+			codec: lora.CodecConfig{Type: lora.CayenneLPP},
+			data:  []byte{0x01, 0x88, 0x06, 0x76, 0x5f, 0xf2, 0x96, 0x0a, 0x00, 0x03, 0xe8},
+			records: []senml.Record{
+				record("gps_1_lat", "lat", 42.3519),
+				record("gps_1_lon", "lon", -87.9094),
+				record("gps_1_alt", "m", 10),
+			},
+		},
+		{
+			desc:  "decode truncated Cayenne LPP payload",
+			codec: lora.CodecConfig{Type: lora.CayenneLPP},
+			data:  []byte{0x03, 0x67, 0x01},
+			err:   lora.ErrMalformedMessage,
+		},
+		{
+			desc: "decode byte layout payload",
+			codec: lora.CodecConfig{
+				Type: lora.ByteLayout,
+				Layout: []lora.LayoutField{
+					{Name: "temperature", Unit: "Cel", Type: "i16", Offset: 0, Scale: 0.5},
+					{Name: "pressure", Unit: "Pa", Type: "u16", Offset: 2, LittleEndian: true},
+					{Name: "alarm", Type: "bool", Offset: 5},
+				},
+			},
+			data: []byte{0x00, 0xfa, 0x01, 0x2c, 0x00, 0x01},
+			records: []senml.Record{
+				record("temperature", "Cel", 125),
+				record("pressure", "Pa", 11265),
+				{Name: "alarm", BoolValue: &alarm},
+			},
+		},
+		{
+			desc: "decode truncated byte layout payload",
+			codec: lora.CodecConfig{
+				Type:   lora.ByteLayout,
+				Layout: []lora.LayoutField{{Name: "pressure", Type: "u32", Offset: 2}},
+			},
+			data: []byte{0x00, 0xfa, 0x01, 0x2c},
+			err:  lora.ErrMalformedMessage,
+		},
+		{
+			desc: "decode script payload",
+			codec: lora.CodecConfig{
+				Type:   lora.Script,
+				Script: "_raw = u16(0)\ntemperature:Cel = (_raw & 0x7fff) / 10; alarm = _raw >> 15\nbattery:%EL = u8(2)",
+			},
+			data: []byte{0x80, 0xfa, 0x5a},
+			records: []senml.Record{
+				record("temperature", "Cel", 25),
+				record("alarm", "", 1),
+				record("battery", "%EL", 90),
+			},
+		},
+		{
+			desc: "decode script payload out of range",
+			codec: lora.CodecConfig{
+				Type:   lora.Script,
+				Script: "temperature = i16(len() - 1)",
+			},
+			data: []byte{0x80, 0xfa, 0x5a},
+			err:  errors.New("temperature: i16(2) is out of payload range"),
+		},
+		{
+			desc: "decode script payload with division by zero",
+			codec: lora.CodecConfig{
+				Type:   lora.Script,
+				Script: "ratio = u8(0) / u8(1)",
+			},
+			data: []byte{0x01, 0x00},
+			err:  errors.New("ratio: division by zero"),
+		},
+	}
+
+	for _, tc := range cases {
+		codec, err := lora.NewCodec(tc.codec)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		records, err := codec.Decode(tc.data)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.records, records, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.records, records))
+	}
+}
+
+func TestNewScriptCodec(t *testing.T) {
+	cases := []struct {
+		desc   string
+		script string
+		err    error
+	}{
+		{
+			desc:   "create script codec",
+			script: "_t = i16le(0); temperature:Cel = round(_t / 10); level = max(u8(2), 10, 20) % 7 | 1 << 4",
+			err:    nil,
+		},
+		{
+			desc:   "create script codec without assignment",
+			script: "u8(0)",
+			err:    lora.ErrInvalidCodec,
+		},
+		{
+			desc:   "create script codec with undefined name",
+			script: "temperature = humidity * 2",
+			err:    lora.ErrInvalidCodec,
+		},
+		{
+			desc:   "create script codec with unknown function",
+			script: "temperature = exec(0)",
+			err:    lora.ErrInvalidCodec,
+		},
+		{
+			desc:   "create script codec with invalid number of arguments",
+			script: "temperature = u8(0, 1)",
+			err:    lora.ErrInvalidCodec,
+		},
+		{
+			desc:   "create script codec with function name",
+			script: "len = u8(0)",
+			err:    lora.ErrInvalidCodec,
+		},
+		{
+			desc:   "create empty script codec",
+			script: " ; ",
+			err:    lora.ErrInvalidCodec,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := lora.NewCodec(lora.CodecConfig{Type: lora.Script, Script: tc.script})
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package lora
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/absmach/magistrala/pkg/messaging"
+)
+
+const (
+	// DownlinkSubtopic is the Magistrala subtopic prefix of the messages
+	// forwarded to the LoRa devices. The full subtopic is of the form
+	// down.<thingID>, optionally followed by the LoRaWAN fPort and the
+	// confirmed flag, e.g. down.<thingID>.10.confirmed.
+	DownlinkSubtopic = "down"
+
+	// AckEvent is the LoRa Server event of the acknowledged confirmed downlink.
+	AckEvent = "ack"
+
+	// ErrorEvent is the LoRa Server event of the failed downlink.
+	ErrorEvent = "error"
+
+	confirmed = "confirmed"
+	maxFPort  = 223
+)
+
+// DownlinkPublisher publishes downlinks to the LoRa MQTT broker.
+type DownlinkPublisher interface {
+	// Publish publishes the payload to the given topic.
+	Publish(ctx context.Context, topic string, payload []byte) error
+}
+
+// DownlinkConfig contains the defaults of the downlinks sent to the devices.
+type DownlinkConfig struct {
+	FPort     int
+	Confirmed bool
+}
+
+// downlinkEvent is the payload of the downlink event published to Magistrala.
+type downlinkEvent struct {
+	Event        string `json:"event"`
+	Acknowledged bool   `json:"acknowledged,omitempty"`
+	FCnt         int    `json:"f_cnt"`
+	Type         string `json:"type,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// downlink contains the downlink parameters parsed from the subtopic.
+type downlink struct {
+	thingID   string
+	fPort     int
+	confirmed bool
+}
+
+// parseDownlink parses the downlink subtopic, using the default fPort and
+// confirmed flag unless provided by the subtopic.
+func parseDownlink(subtopic string, cfg DownlinkConfig) (downlink, error) {
+	parts := strings.Split(subtopic, ".")
+	if len(parts) < 2 || len(parts) > 4 || parts[0] != DownlinkSubtopic || parts[1] == "" {
+		return downlink{}, ErrMalformedMessage
+	}
+
+	dl := downlink{
+		thingID:   parts[1],
+		fPort:     cfg.FPort,
+		confirmed: cfg.Confirmed,
+	}
+	for _, part := range parts[2:] {
+		if part == confirmed {
+			dl.confirmed = true
+			continue
+		}
+		fPort, err := strconv.Atoi(part)
+		if err != nil {
+			return downlink{}, ErrMalformedMessage
+		}
+		dl.fPort = fPort
+	}
+	if dl.fPort < 1 || dl.fPort > maxFPort {
+		return downlink{}, ErrMalformedMessage
+	}
+
+	return dl, nil
+}
+
+// isDownlink checks if the Magistrala message should be forwarded to the
+// LoRa device. Messages published by the adapter itself are never forwarded.
+func isDownlink(msg *messaging.Message) bool {
+	if msg.GetProtocol() == protocol {
+		return false
+	}
+	subtopic := msg.GetSubtopic()
+
+	return subtopic == DownlinkSubtopic || strings.HasPrefix(subtopic, DownlinkSubtopic+".")
+}
+
+type downlinkHandler struct {
+	ctx context.Context
+	svc Service
+}
+
+// NewDownlinkHandler returns the Magistrala message handler which forwards
+// the downlinks to the LoRa devices and ignores the other messages.
+func NewDownlinkHandler(ctx context.Context, svc Service) messaging.MessageHandler {
+	return downlinkHandler{
+		ctx: ctx,
+		svc: svc,
+	}
+}
+
+func (h downlinkHandler) Handle(msg *messaging.Message) error {
+	if !isDownlink(msg) {
+		return nil
+	}
+
+	return h.svc.Downlink(h.ctx, msg)
+}
+
+func (h downlinkHandler) Cancel() error {
+	return nil
+}
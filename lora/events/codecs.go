@@ -0,0 +1,61 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/absmach/magistrala/lora"
+	"github.com/go-redis/redis/v8"
+)
+
+var _ lora.CodecRepository = (*codecRepository)(nil)
+
+type codecRepository struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewCodecRepository returns redis codec repository implementation.
+func NewCodecRepository(client *redis.Client, prefix string) lora.CodecRepository {
+	return &codecRepository{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (cr *codecRepository) Save(ctx context.Context, id string, cfg lora.CodecConfig) error {
+	val, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("%s:%s", cr.prefix, id)
+	return cr.client.Set(ctx, key, val, 0).Err()
+}
+
+func (cr *codecRepository) Get(ctx context.Context, id string) (lora.CodecConfig, error) {
+	key := fmt.Sprintf("%s:%s", cr.prefix, id)
+	val, err := cr.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return lora.CodecConfig{}, lora.ErrNotFoundCodec
+		}
+		return lora.CodecConfig{}, err
+	}
+
+	var cfg lora.CodecConfig
+	if err := json.Unmarshal(val, &cfg); err != nil {
+		return lora.CodecConfig{}, err
+	}
+
+	return cfg, nil
+}
+
+func (cr *codecRepository) Remove(ctx context.Context, id string) error {
+	key := fmt.Sprintf("%s:%s", cr.prefix, id)
+	return cr.client.Del(ctx, key).Err()
+}
@@ -3,9 +3,12 @@
 
 package events
 
+import "github.com/absmach/magistrala/lora"
+
 type createThingEvent struct {
 	id         string
 	loraDevEUI string
+	codec      *lora.CodecConfig
 }
 
 type removeThingEvent struct {
@@ -15,6 +18,7 @@ type removeThingEvent struct {
 type createChannelEvent struct {
 	id        string
 	loraAppID string
+	codec     *lora.CodecConfig
 }
 
 type removeChannelEvent struct {
@@ -16,6 +16,7 @@ const (
 	keyType   = "lora"
 	keyDevEUI = "dev_eui"
 	keyAppID  = "app_id"
+	keyCodec  = "codec"
 
 	thingPrefix     = "thing."
 	thingCreate     = thingPrefix + "create"
@@ -38,6 +39,8 @@ var (
 	errMetadataAppID = errors.New("application ID not found in channel metadatada")
 
 	errMetadataDevEUI = errors.New("device EUI not found in thing metadatada")
+
+	errMetadataCodec = errors.New("malformed codec in metadata")
 )
 
 type eventHandler struct {
@@ -64,14 +67,20 @@ func (es *eventHandler) Handle(ctx context.Context, event events.Event) error {
 			err = derr
 			break
 		}
-		err = es.svc.CreateThing(ctx, cte.id, cte.loraDevEUI)
+		if err = es.svc.CreateThing(ctx, cte.id, cte.loraDevEUI); err != nil {
+			break
+		}
+		err = es.saveCodec(ctx, cte.id, cte.codec)
 	case thingUpdate:
 		ute, derr := decodeCreateThing(msg)
 		if derr != nil {
 			err = derr
 			break
 		}
-		err = es.svc.CreateThing(ctx, ute.id, ute.loraDevEUI)
+		if err = es.svc.CreateThing(ctx, ute.id, ute.loraDevEUI); err != nil {
+			break
+		}
+		err = es.saveCodec(ctx, ute.id, ute.codec)
 
 	case channelCreate:
 		cce, derr := decodeCreateChannel(msg)
@@ -79,14 +88,20 @@ func (es *eventHandler) Handle(ctx context.Context, event events.Event) error {
 			err = derr
 			break
 		}
-		err = es.svc.CreateChannel(ctx, cce.id, cce.loraAppID)
+		if err = es.svc.CreateChannel(ctx, cce.id, cce.loraAppID); err != nil {
+			break
+		}
+		err = es.saveCodec(ctx, cce.id, cce.codec)
 	case channelUpdate:
 		uce, derr := decodeCreateChannel(msg)
 		if derr != nil {
 			err = derr
 			break
 		}
-		err = es.svc.CreateChannel(ctx, uce.id, uce.loraAppID)
+		if err = es.svc.CreateChannel(ctx, uce.id, uce.loraAppID); err != nil {
+			break
+		}
+		err = es.saveCodec(ctx, uce.id, uce.codec)
 	case thingRemove:
 		rte := decodeRemoveThing(msg)
 		err = es.svc.RemoveThing(ctx, rte.id)
@@ -107,6 +122,16 @@ func (es *eventHandler) Handle(ctx context.Context, event events.Event) error {
 	return nil
 }
 
+// saveCodec saves the codec of the thing or channel, removing the codec
+// removed from the metadata.
+func (es *eventHandler) saveCodec(ctx context.Context, id string, codec *lora.CodecConfig) error {
+	if codec == nil {
+		return es.svc.RemoveCodec(ctx, id)
+	}
+
+	return es.svc.SaveCodec(ctx, id, *codec)
+}
+
 func decodeCreateThing(event map[string]interface{}) (createThingEvent, error) {
 	strmeta := read(event, "metadata", "{}")
 	var metadata map[string]interface{}
@@ -134,6 +159,13 @@ func decodeCreateThing(event map[string]interface{}) (createThingEvent, error) {
 	}
 
 	cte.loraDevEUI = val
+
+	codec, err := decodeCodec(lm)
+	if err != nil {
+		return createThingEvent{}, err
+	}
+	cte.codec = codec
+
 	return cte, nil
 }
 
@@ -170,9 +202,34 @@ func decodeCreateChannel(event map[string]interface{}) (createChannelEvent, erro
 	}
 
 	cce.loraAppID = val
+
+	codec, err := decodeCodec(lm)
+	if err != nil {
+		return createChannelEvent{}, err
+	}
+	cce.codec = codec
+
 	return cce, nil
 }
 
+func decodeCodec(metadata map[string]interface{}) (*lora.CodecConfig, error) {
+	m, ok := metadata[keyCodec]
+	if !ok {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, errMetadataCodec
+	}
+	var codec lora.CodecConfig
+	if err := json.Unmarshal(b, &codec); err != nil {
+		return nil, errMetadataCodec
+	}
+
+	return &codec, nil
+}
+
 func decodeConnectionThing(event map[string]interface{}) connectionThingEvent {
 	return connectionThingEvent{
 		chanID:  read(event, "chan_id", ""),
@@ -0,0 +1,100 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package lora
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/absmach/senml"
+)
+
+// fieldSizes maps byte layout field types to their sizes in bytes.
+var fieldSizes = map[string]int{
+	"u8":   1,
+	"i8":   1,
+	"bool": 1,
+	"u16":  2,
+	"i16":  2,
+	"u32":  4,
+	"i32":  4,
+	"f32":  4,
+}
+
+type byteLayout struct {
+	fields []LayoutField
+}
+
+func newByteLayout(cfg CodecConfig) (Codec, error) {
+	if len(cfg.Layout) == 0 {
+		return nil, fmt.Errorf("empty byte layout")
+	}
+	for _, f := range cfg.Layout {
+		if f.Name == "" {
+			return nil, fmt.Errorf("missing field name")
+		}
+		if _, ok := fieldSizes[f.Type]; !ok {
+			return nil, fmt.Errorf("field %s has unsupported type %q", f.Name, f.Type)
+		}
+		if f.Offset < 0 {
+			return nil, fmt.Errorf("field %s has negative offset", f.Name)
+		}
+	}
+
+	return byteLayout{fields: cfg.Layout}, nil
+}
+
+// Decode decodes each of the layout fields to the record of the same name.
+func (l byteLayout) Decode(data []byte) ([]senml.Record, error) {
+	recs := make([]senml.Record, 0, len(l.fields))
+	for _, f := range l.fields {
+		end := f.Offset + fieldSizes[f.Type]
+		if end > len(data) {
+			return nil, ErrMalformedMessage
+		}
+		b := data[f.Offset:end]
+
+		if f.Type == "bool" {
+			v := b[0] != 0
+			recs = append(recs, senml.Record{Name: f.Name, Unit: f.Unit, BoolValue: &v})
+			continue
+		}
+
+		v := readField(f.Type, b, f.LittleEndian)
+		if f.Scale != 0 {
+			v *= f.Scale
+		}
+		recs = append(recs, numRecord(f.Name, f.Unit, v))
+	}
+
+	return recs, nil
+}
+
+// readField reads the numeric field of the given type.
+func readField(typ string, b []byte, littleEndian bool) float64 {
+	var order binary.ByteOrder = binary.BigEndian
+	if littleEndian {
+		order = binary.LittleEndian
+	}
+
+	switch typ {
+	case "u8":
+		return float64(b[0])
+	case "i8":
+		return float64(int8(b[0]))
+	case "u16":
+		return float64(order.Uint16(b))
+	case "i16":
+		return float64(int16(order.Uint16(b)))
+	case "u32":
+		return float64(order.Uint32(b))
+	case "i32":
+		return float64(int32(order.Uint32(b)))
+	case "f32":
+		return float64(math.Float32frombits(order.Uint32(b)))
+	default:
+		return 0
+	}
+}
@@ -45,3 +45,22 @@ type Message struct {
 	Data                string      `json:"data"`
 	Object              interface{} `json:"object"`
 }
+
+// DownlinkMessage lora downlink command (https://www.chirpstack.io/application-server/integrations/mqtt).
+type DownlinkMessage struct {
+	Confirmed bool   `json:"confirmed"`
+	FPort     int    `json:"fPort"`
+	Data      string `json:"data"`
+}
+
+// DownlinkEvent lora ack or error event (https://www.chirpstack.io/application-server/integrations/events).
+type DownlinkEvent struct {
+	ApplicationID string `json:"applicationID"`
+	DevEUI        string `json:"devEUI"`
+	Acknowledged  bool   `json:"acknowledged"`
+	FCnt          int    `json:"fCnt"`
+	Type          string `json:"type"`
+	Error         string `json:"error"`
+	// Event is the kind of the event, taken from the event topic.
+	Event string `json:"-"`
+}
@@ -0,0 +1,53 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/absmach/magistrala/lora"
+)
+
+var _ lora.CodecRepository = (*codecRepositoryMock)(nil)
+
+type codecRepositoryMock struct {
+	mu     sync.Mutex
+	codecs map[string]lora.CodecConfig
+}
+
+// NewCodecRepository returns mock codec repository instance.
+func NewCodecRepository() lora.CodecRepository {
+	return &codecRepositoryMock{
+		codecs: make(map[string]lora.CodecConfig),
+	}
+}
+
+func (crm *codecRepositoryMock) Save(_ context.Context, id string, cfg lora.CodecConfig) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	crm.codecs[id] = cfg
+	return nil
+}
+
+func (crm *codecRepositoryMock) Get(_ context.Context, id string) (lora.CodecConfig, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	cfg, ok := crm.codecs[id]
+	if !ok {
+		return lora.CodecConfig{}, lora.ErrNotFoundCodec
+	}
+
+	return cfg, nil
+}
+
+func (crm *codecRepositoryMock) Remove(_ context.Context, id string) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	delete(crm.codecs, id)
+	return nil
+}
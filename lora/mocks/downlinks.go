@@ -0,0 +1,44 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/absmach/magistrala/lora"
+)
+
+var _ lora.DownlinkPublisher = (*DownlinkPublisher)(nil)
+
+// DownlinkPublisher is mock LoRa MQTT downlink publisher, which keeps the
+// last payload published to each topic.
+type DownlinkPublisher struct {
+	mu       sync.Mutex
+	payloads map[string][]byte
+}
+
+// NewDownlinkPublisher returns mock downlink publisher.
+func NewDownlinkPublisher() *DownlinkPublisher {
+	return &DownlinkPublisher{
+		payloads: make(map[string][]byte),
+	}
+}
+
+func (pub *DownlinkPublisher) Publish(_ context.Context, topic string, payload []byte) error {
+	pub.mu.Lock()
+	defer pub.mu.Unlock()
+
+	pub.payloads[topic] = payload
+
+	return nil
+}
+
+// Payload returns the last payload published to the topic.
+func (pub *DownlinkPublisher) Payload(topic string) []byte {
+	pub.mu.Lock()
+	defer pub.mu.Unlock()
+
+	return pub.payloads[topic]
+}
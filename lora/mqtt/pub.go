@@ -0,0 +1,40 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/absmach/magistrala/lora"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// errPublishTimeout indicates that the downlink was not published in time.
+var errPublishTimeout = errors.New("failed to publish downlink due to timeout")
+
+var _ lora.DownlinkPublisher = (*publisher)(nil)
+
+type publisher struct {
+	client  mqtt.Client
+	timeout time.Duration
+}
+
+// NewPublisher returns new Lora MQTT downlink publisher.
+func NewPublisher(client mqtt.Client, timeout time.Duration) lora.DownlinkPublisher {
+	return publisher{
+		client:  client,
+		timeout: timeout,
+	}
+}
+
+func (pub publisher) Publish(_ context.Context, topic string, payload []byte) error {
+	token := pub.client.Publish(topic, 0, false, payload)
+	if !token.WaitTimeout(pub.timeout) {
+		return errPublishTimeout
+	}
+
+	return token.Error()
+}
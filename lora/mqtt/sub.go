@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/absmach/magistrala/lora"
@@ -19,6 +20,10 @@ import (
 type Subscriber interface {
 	// Subscribes to given subject and receives events.
 	Subscribe(string) error
+
+	// SubscribeEvents subscribes to given subjects and receives downlink
+	// ack and error events.
+	SubscribeEvents(...string) error
 }
 
 type broker struct {
@@ -60,3 +65,39 @@ func (b broker) handleMsg(c mqtt.Client, msg mqtt.Message) {
 		b.logger.Error(fmt.Sprintf("got error while publishing messages: %s", err))
 	}
 }
+
+// SubscribeEvents subscribes to the Lora MQTT message broker downlink events.
+// Subjects must not match uplink messages, since the broker may deliver the
+// message once per matching subscription.
+func (b broker) SubscribeEvents(subjects ...string) error {
+	filters := make(map[string]byte, len(subjects))
+	for _, subject := range subjects {
+		filters[subject] = 0
+	}
+	s := b.client.SubscribeMultiple(filters, b.handleEvent)
+	if err := s.Error(); s.WaitTimeout(b.timeout) && err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// handleEvent triggered when new downlink event is received on Lora MQTT broker.
+// The event kind is the last segment of the event topic.
+func (b broker) handleEvent(c mqtt.Client, msg mqtt.Message) {
+	event := msg.Topic()[strings.LastIndex(msg.Topic(), "/")+1:]
+	if event != lora.AckEvent && event != lora.ErrorEvent {
+		return
+	}
+
+	ev := lora.DownlinkEvent{}
+	if err := json.Unmarshal(msg.Payload(), &ev); err != nil {
+		b.logger.Warn(fmt.Sprintf("Failed to unmarshal event: %s", err.Error()))
+		return
+	}
+	ev.Event = event
+
+	if err := b.svc.DownlinkEvent(context.Background(), &ev); err != nil {
+		b.logger.Error(fmt.Sprintf("got error while publishing downlink event: %s", err))
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	mglog "github.com/absmach/magistrala/logger"
+	"github.com/absmach/magistrala/lora"
+	"github.com/absmach/magistrala/lora/mocks"
+	"github.com/absmach/magistrala/lora/mqtt"
+	"github.com/absmach/magistrala/pkg/messaging"
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	thingID  = "thingID-1"
+	chanID   = "chanID-1"
+	devEUI   = "devEUI-1"
+	appID    = "appID-1"
+	msgTopic = "application/+/device/+/event/up"
+	payload  = `[{"n":"temperature","v":17}]`
+)
+
+var evtTopics = []string{"application/+/device/+/event/ack", "application/+/device/+/event/error"}
+
+// publisher counts the published messages.
+type publisher struct {
+	messages []*messaging.Message
+}
+
+func (pub *publisher) Publish(_ context.Context, _ string, msg *messaging.Message) error {
+	pub.messages = append(pub.messages, msg)
+	return nil
+}
+
+func (pub *publisher) Close() error {
+	return nil
+}
+
+// client delivers the message once per matching subscription, the same as
+// MQTT brokers do for the overlapping subscriptions of the client.
+type client struct {
+	paho.Client
+	handlers map[string]paho.MessageHandler
+}
+
+func (c *client) Subscribe(topic string, _ byte, handler paho.MessageHandler) paho.Token {
+	c.handlers[topic] = handler
+	return &paho.DummyToken{}
+}
+
+func (c *client) SubscribeMultiple(filters map[string]byte, handler paho.MessageHandler) paho.Token {
+	for topic := range filters {
+		c.handlers[topic] = handler
+	}
+	return &paho.DummyToken{}
+}
+
+func (c *client) deliver(topic string, payload []byte) {
+	for filter, handler := range c.handlers {
+		if matches(filter, topic) {
+			handler(c, message{topic: topic, payload: payload})
+		}
+	}
+}
+
+func matches(filter, topic string) bool {
+	fs, ts := strings.Split(filter, "/"), strings.Split(topic, "/")
+	if len(fs) != len(ts) {
+		return false
+	}
+	for i := range fs {
+		if fs[i] != "+" && fs[i] != ts[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+type message struct {
+	paho.Message
+	topic   string
+	payload []byte
+}
+
+func (m message) Topic() string {
+	return m.topic
+}
+
+func (m message) Payload() []byte {
+	return m.payload
+}
+
+func TestSubscribe(t *testing.T) {
+	pub := &publisher{}
+	svc := lora.New(pub, mocks.NewDownlinkPublisher(), mocks.NewRouteMap(), mocks.NewRouteMap(), mocks.NewRouteMap(), mocks.NewCodecRepository(), lora.DownlinkConfig{FPort: 1}, lora.RadioOff)
+
+	err := svc.CreateChannel(context.Background(), chanID, appID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.CreateThing(context.Background(), thingID, devEUI)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.ConnectThing(context.Background(), chanID, thingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	c := &client{handlers: map[string]paho.MessageHandler{}}
+	broker := mqtt.NewBroker(svc, c, time.Second, mglog.NewMock())
+	err = broker.Subscribe(msgTopic)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = broker.SubscribeEvents(evtTopics...)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	up, err := json.Marshal(lora.Message{
+		ApplicationID: appID,
+		DevEUI:        devEUI,
+		Data:          base64.StdEncoding.EncodeToString([]byte(payload)),
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	c.deliver(fmt.Sprintf("application/%s/device/%s/event/up", appID, devEUI), up)
+	assert.Len(t, pub.messages, 1, fmt.Sprintf("expected uplink to be published once, got %d times", len(pub.messages)))
+
+	ack, err := json.Marshal(lora.DownlinkEvent{ApplicationID: appID, DevEUI: devEUI, Acknowledged: true})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	c.deliver(fmt.Sprintf("application/%s/device/%s/event/ack", appID, devEUI), ack)
+	require.Len(t, pub.messages, 2, fmt.Sprintf("expected ack event to be published once, got %d messages", len(pub.messages)-1))
+	assert.Equal(t, fmt.Sprintf("down.%s.%s", thingID, lora.AckEvent), pub.messages[1].Subtopic, fmt.Sprintf("expected ack event subtopic, got %s", pub.messages[1].Subtopic))
+}
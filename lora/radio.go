@@ -0,0 +1,134 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package lora
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/absmach/senml"
+)
+
+const (
+	// RadioOff disables forwarding of the radio metadata.
+	RadioOff = "off"
+
+	// RadioRecords appends the radio metadata records to the SenML uplinks.
+	// Metadata of the uplinks which are not SenML is published to the radio
+	// subtopic.
+	RadioRecords = "records"
+
+	// RadioSubtopic publishes the radio metadata to the radio subtopic.
+	RadioSubtopic = "subtopic"
+
+	// RadioTopic is the Magistrala subtopic of the radio metadata messages.
+	RadioTopic = "radio"
+
+	// batteryUnknown is the device battery level reported when the device is
+	// not able to measure it, and zero is reported for the external power.
+	batteryUnknown = 254
+)
+
+// radioRecords returns the link quality and gateway metadata of the uplink.
+// The link quality is the best one among the receiving gateways, and the
+// metadata of each gateway is named with the gateway_<mac>_ prefix.
+func radioRecords(m *Message) []senml.Record {
+	var recs []senml.Record
+
+	if len(m.RxInfo) > 0 {
+		rssi, snr := math.Inf(-1), math.Inf(-1)
+		for _, rx := range m.RxInfo {
+			rssi = math.Max(rssi, rx.Rssi)
+			snr = math.Max(snr, rx.LoRaSNR)
+		}
+		recs = append(recs,
+			numRecord("rssi", "dBm", rssi),
+			numRecord("snr", "dB", snr),
+			numRecord("gateways", "", float64(len(m.RxInfo))),
+		)
+	}
+
+	if m.TxInfo.Frequency != 0 {
+		recs = append(recs, numRecord("frequency", "Hz", m.TxInfo.Frequency))
+	}
+	if m.TxInfo.DataRate.SpreadFactor != 0 {
+		recs = append(recs, numRecord("spreading_factor", "", float64(m.TxInfo.DataRate.SpreadFactor)))
+	}
+	if m.TxInfo.DataRate.Bandwidth != 0 {
+		// LoRa Server reports the bandwidth in kHz.
+		recs = append(recs, numRecord("bandwidth", "Hz", m.TxInfo.DataRate.Bandwidth*1000))
+	}
+	recs = append(recs, numRecord("f_cnt", "", float64(m.FCnt)))
+
+	if v, err := strconv.ParseFloat(m.DeviceStatusBattery, 64); err == nil && v > 0 && v <= batteryUnknown {
+		recs = append(recs, numRecord("battery", "%EL", math.Round(v/batteryUnknown*100)))
+	}
+	if v, err := strconv.ParseFloat(m.DeviceStatusMrgin, 64); err == nil {
+		recs = append(recs, numRecord("margin", "dB", v))
+	}
+
+	for _, rx := range m.RxInfo {
+		if rx.Mac == "" {
+			continue
+		}
+		name := fmt.Sprintf("gateway_%s_", strings.ToLower(rx.Mac))
+		recs = append(recs,
+			numRecord(name+"rssi", "dBm", rx.Rssi),
+			numRecord(name+"snr", "dB", rx.LoRaSNR),
+		)
+		if rx.Latitude != 0 || rx.Longitude != 0 {
+			recs = append(recs,
+				numRecord(name+"lat", "lat", rx.Latitude),
+				numRecord(name+"lon", "lon", rx.Longitude),
+				numRecord(name+"alt", "m", rx.Altitude),
+			)
+		}
+	}
+
+	return recs
+}
+
+// withRadio returns the uplink payload and the radio subtopic payload, if
+// any, depending on the radio metadata forwarding mode.
+func withRadio(mode string, m *Message, payload []byte) ([]byte, []byte, error) {
+	if mode != RadioRecords && mode != RadioSubtopic {
+		return payload, nil, nil
+	}
+
+	recs := radioRecords(m)
+	if mode == RadioRecords {
+		if sp, ok := appendRadio(payload, recs); ok {
+			return sp, nil, nil
+		}
+	}
+	radio, err := senml.Encode(senml.Pack{Records: recs}, senml.JSON)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return payload, radio, nil
+}
+
+// appendRadio appends the radio metadata records to the SenML payload. The
+// payload is normalized first, so that its base fields do not apply to the
+// appended records. False is returned if the payload is not SenML.
+func appendRadio(payload []byte, recs []senml.Record) ([]byte, bool) {
+	p, err := senml.Decode(payload, senml.JSON)
+	if err != nil {
+		return nil, false
+	}
+	if p, err = senml.Normalize(p); err != nil {
+		return nil, false
+	}
+	p.Records = append(p.Records, recs...)
+
+	b, err := senml.Encode(p, senml.JSON)
+	if err != nil {
+		return nil, false
+	}
+
+	return b, true
+}
@@ -0,0 +1,186 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package lora
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/absmach/magistrala/pkg/expr"
+	"github.com/absmach/senml"
+)
+
+const (
+	maxScriptSize  = 4096
+	maxScriptShift = 63
+)
+
+// scriptLang is the language of the decoder script expressions. Byte read
+// functions take the payload offset, and the big endian order is used unless
+// the function name ends with "le". Operators %, <<, >>, &, | and ^ operate on
+// integers.
+var scriptLang = expr.Language{
+	Ops: map[string]expr.Op{
+		"*":  expr.Arithmetic["*"],
+		"/":  expr.Arithmetic["/"],
+		"%":  {Prec: 2, Eval: mod},
+		"<<": {Prec: 2, Eval: shiftOp(func(l, r int64) int64 { return l << r })},
+		">>": {Prec: 2, Eval: shiftOp(func(l, r int64) int64 { return l >> r })},
+		"&":  {Prec: 2, Eval: intOp(func(l, r int64) int64 { return l & r })},
+		"+":  expr.Arithmetic["+"],
+		"-":  expr.Arithmetic["-"],
+		"|":  {Prec: 1, Eval: intOp(func(l, r int64) int64 { return l | r })},
+		"^":  {Prec: 1, Eval: intOp(func(l, r int64) int64 { return l ^ r })},
+	},
+	Funcs: map[string]expr.Func{
+		"u8":    readFunc("u8"),
+		"i8":    readFunc("i8"),
+		"u16":   readFunc("u16"),
+		"i16":   readFunc("i16"),
+		"u32":   readFunc("u32"),
+		"i32":   readFunc("i32"),
+		"f32":   readFunc("f32"),
+		"u16le": readFunc("u16le"),
+		"i16le": readFunc("i16le"),
+		"u32le": readFunc("u32le"),
+		"i32le": readFunc("i32le"),
+		"f32le": readFunc("f32le"),
+		"len":   {Arity: 0, Eval: payloadLen},
+		"abs":   expr.Math["abs"],
+		"round": expr.Math["round"],
+		"min":   {Arity: -2, Eval: expr.Math["min"].Eval},
+		"max":   {Arity: -2, Eval: expr.Math["max"].Eval},
+	},
+}
+
+// scriptCodec decodes the payload using the decoder script. The script is a
+// list of assignments, separated by new lines or semicolons, of the form
+// name[:unit] = expression. Each assignment is decoded to the record of the
+// same name, except for the names starting with underscore which are only
+// available to the subsequent expressions. The script has no loops nor
+// access to anything but the payload, so it is safe to run untrusted
+// scripts.
+type scriptCodec struct {
+	stmts []scriptStmt
+}
+
+type scriptStmt struct {
+	name string
+	unit string
+	x    expr.Node
+}
+
+func newScript(cfg CodecConfig) (Codec, error) {
+	if len(cfg.Script) > maxScriptSize {
+		return nil, fmt.Errorf("script exceeds %d bytes", maxScriptSize)
+	}
+
+	var stmts []scriptStmt
+	defined := map[string]bool{}
+	lines := strings.FieldsFunc(cfg.Script, func(r rune) bool { return r == '\n' || r == ';' })
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lhs, rhs, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("missing assignment in %q", line)
+		}
+		name, unit, _ := strings.Cut(lhs, ":")
+		name, unit = strings.TrimSpace(name), strings.TrimSpace(unit)
+		if _, ok := scriptLang.Funcs[name]; ok || !expr.IsIdent(name) {
+			return nil, fmt.Errorf("invalid name %q", name)
+		}
+		x, err := scriptLang.Parse(rhs)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for _, v := range expr.Vars(x) {
+			if !defined[v] {
+				return nil, fmt.Errorf("%s: undefined name %q", name, v)
+			}
+		}
+		defined[name] = true
+		stmts = append(stmts, scriptStmt{name: name, unit: unit, x: x})
+	}
+	if len(stmts) == 0 {
+		return nil, fmt.Errorf("empty script")
+	}
+
+	return scriptCodec{stmts: stmts}, nil
+}
+
+func (s scriptCodec) Decode(data []byte) ([]senml.Record, error) {
+	env := scriptEnv{Values: expr.Values{}, data: data}
+
+	var recs []senml.Record
+	for _, stmt := range s.stmts {
+		v, err := scriptLang.Eval(stmt.x, env)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", stmt.name, err)
+		}
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("%s: result is not a finite number", stmt.name)
+		}
+		env.Values[stmt.name] = v
+		if !strings.HasPrefix(stmt.name, "_") {
+			recs = append(recs, numRecord(stmt.name, stmt.unit, v))
+		}
+	}
+
+	return recs, nil
+}
+
+// scriptEnv provides the payload and the decoded values to the expressions.
+type scriptEnv struct {
+	expr.Values
+	data []byte
+}
+
+// intOp returns the operator evaluated on the integer operands.
+func intOp(op func(l, r int64) int64) func(l, r float64) (float64, error) {
+	return func(l, r float64) (float64, error) {
+		return float64(op(int64(l), int64(r))), nil
+	}
+}
+
+func mod(l, r float64) (float64, error) {
+	if int64(r) == 0 {
+		return 0, expr.ErrDivisionByZero
+	}
+
+	return float64(int64(l) % int64(r)), nil
+}
+
+// shiftOp returns the shift operator, limiting the shift count.
+func shiftOp(op func(l, r int64) int64) func(l, r float64) (float64, error) {
+	return func(l, r float64) (float64, error) {
+		if int64(r) < 0 || int64(r) > maxScriptShift {
+			return 0, fmt.Errorf("invalid shift count %d", int64(r))
+		}
+		return float64(op(int64(l), int64(r))), nil
+	}
+}
+
+func payloadLen(env expr.Env, _ []float64) (float64, error) {
+	return float64(len(env.(scriptEnv).data)), nil
+}
+
+// readFunc returns the function reading the payload field at the offset.
+func readFunc(fn string) expr.Func {
+	typ := strings.TrimSuffix(fn, "le")
+	return expr.Func{
+		Arity: 1,
+		Eval: func(env expr.Env, args []float64) (float64, error) {
+			data := env.(scriptEnv).data
+			off := int(args[0])
+			end := off + fieldSizes[typ]
+			if float64(off) != args[0] || off < 0 || end > len(data) {
+				return 0, fmt.Errorf("%s(%v) is out of payload range", fn, args[0])
+			}
+			return readField(typ, data[off:end], typ != fn), nil
+		},
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/absmach/magistrala"
 	"github.com/absmach/magistrala/auth"
 	"github.com/absmach/magistrala/mqtt/events"
+	"github.com/absmach/magistrala/pkg/certauth"
 	"github.com/absmach/magistrala/pkg/errors"
 	"github.com/absmach/magistrala/pkg/messaging"
 	"github.com/absmach/mproxy/pkg/session"
@@ -58,17 +59,22 @@ var channelRegExp = regexp.MustCompile(`^\/?channels\/([\w\-]+)\/messages(\/[^?]
 type handler struct {
 	publisher messaging.Publisher
 	auth      magistrala.AuthzServiceClient
+	certAuth  certauth.Authenticator
 	logger    *slog.Logger
 	es        events.EventStore
 }
 
-// NewHandler creates new Handler entity.
-func NewHandler(publisher messaging.Publisher, es events.EventStore, logger *slog.Logger, authClient magistrala.AuthzServiceClient) session.Handler {
+// NewHandler creates new Handler entity. Unless the certificate authenticator
+// is nil, the things presenting the client certificate are authenticated
+// using the certificate instead of the password, and authorized by the ID of
+// the thing the certificate is issued to.
+func NewHandler(publisher messaging.Publisher, es events.EventStore, logger *slog.Logger, authClient magistrala.AuthzServiceClient, certAuth certauth.Authenticator) session.Handler {
 	return &handler{
 		es:        es,
 		logger:    logger,
 		publisher: publisher,
 		auth:      authClient,
+		certAuth:  certAuth,
 	}
 }
 
@@ -84,9 +90,12 @@ func (h *handler) AuthConnect(ctx context.Context) error {
 		return ErrMissingClientID
 	}
 
-	pwd := string(s.Password)
+	thing, _, err := h.subject(ctx, s)
+	if err != nil {
+		return err
+	}
 
-	if err := h.es.Connect(ctx, pwd); err != nil {
+	if err := h.es.Connect(ctx, thing); err != nil {
 		h.logger.Error(errors.Wrap(ErrFailedPublishConnectEvent, err).Error())
 	}
 
@@ -104,7 +113,12 @@ func (h *handler) AuthPublish(ctx context.Context, topic *string, payload *[]byt
 		return ErrClientNotInitialized
 	}
 
-	return h.authAccess(ctx, string(s.Password), *topic, auth.PublishPermission)
+	thing, kind, err := h.subject(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	return h.authAccess(ctx, thing, kind, *topic, auth.PublishPermission)
 }
 
 // AuthSubscribe is called on device subscribe,
@@ -118,8 +132,13 @@ func (h *handler) AuthSubscribe(ctx context.Context, topics *[]string) error {
 		return ErrMissingTopicSub
 	}
 
+	thing, kind, err := h.subject(ctx, s)
+	if err != nil {
+		return err
+	}
+
 	for _, v := range *topics {
-		if err := h.authAccess(ctx, string(s.Password), v, auth.SubscribePermission); err != nil {
+		if err := h.authAccess(ctx, thing, kind, v, auth.SubscribePermission); err != nil {
 			return err
 		}
 	}
@@ -203,13 +222,36 @@ func (h *handler) Disconnect(ctx context.Context) error {
 		return errors.Wrap(ErrFailedDisconnect, ErrClientNotInitialized)
 	}
 	h.logger.Error(fmt.Sprintf(LogInfoDisconnected, s.ID, s.Password))
-	if err := h.es.Disconnect(ctx, string(s.Password)); err != nil {
+	// The certificate is authenticated again instead of trusting its common
+	// name, so the event is not published if the certificate is revoked in
+	// the meantime.
+	thing, _, err := h.subject(ctx, s)
+	if err != nil {
+		return errors.Wrap(ErrFailedPublishDisconnectEvent, err)
+	}
+	if err := h.es.Disconnect(ctx, thing); err != nil {
 		return errors.Wrap(ErrFailedPublishDisconnectEvent, err)
 	}
 	return nil
 }
 
-func (h *handler) authAccess(ctx context.Context, password, topic, action string) error {
+// subject returns the authorization subject and subject kind of the session
+// thing. Things presenting the client certificate are identified by the ID
+// of the thing the certificate is issued to, and the rest by the password.
+func (h *handler) subject(ctx context.Context, s *session.Session) (string, string, error) {
+	if h.certAuth == nil || len(s.Cert.Raw) == 0 {
+		return string(s.Password), "", nil
+	}
+
+	id, err := h.certAuth.Authenticate(ctx, &s.Cert)
+	if err != nil {
+		return "", "", err
+	}
+
+	return id, auth.ThingsKind, nil
+}
+
+func (h *handler) authAccess(ctx context.Context, subject, kind, topic, action string) error {
 	// Topics are in the format:
 	// channels/<channel_id>/messages/<subtopic>/.../ct/<content_type>
 	if !channelRegExp.MatchString(topic) {
@@ -225,8 +267,9 @@ func (h *handler) authAccess(ctx context.Context, password, topic, action string
 
 	ar := &magistrala.AuthorizeReq{
 		SubjectType: auth.ThingType,
+		SubjectKind: kind,
 		Permission:  action,
-		Subject:     password,
+		Subject:     subject,
 		Object:      chanID,
 		ObjectType:  auth.GroupType,
 	}
@@ -6,6 +6,8 @@ package mqtt_test
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
 	"log"
 	"testing"
@@ -16,6 +18,8 @@ import (
 	mglog "github.com/absmach/magistrala/logger"
 	"github.com/absmach/magistrala/mqtt"
 	"github.com/absmach/magistrala/mqtt/mocks"
+	"github.com/absmach/magistrala/pkg/certauth"
+	certmocks "github.com/absmach/magistrala/pkg/certauth/mocks"
 	"github.com/absmach/magistrala/pkg/errors"
 	"github.com/absmach/mproxy/pkg/session"
 	"github.com/stretchr/testify/assert"
@@ -441,6 +445,103 @@ func TestDisconnect(t *testing.T) {
 	}
 }
 
+func TestAuthPublishCert(t *testing.T) {
+	logger, err := mglog.New(&logBuffer, "debug")
+	if err != nil {
+		log.Fatalf("failed to create logger: %s", err)
+	}
+	auth := new(authmocks.AuthClient)
+	certAuth := new(certmocks.Authenticator)
+	handler := mqtt.NewHandler(mocks.NewPublisher(), mocks.NewEventStore(), logger, auth, certAuth)
+
+	cert := x509.Certificate{Raw: []byte("cert"), Subject: pkix.Name{CommonName: password}}
+
+	cases := []struct {
+		desc    string
+		session *session.Session
+		subject string
+		kind    string
+		authErr error
+		err     error
+	}{
+		{
+			desc:    "publish with valid cert",
+			session: &session.Session{ID: clientID, Cert: cert},
+			subject: thingID,
+			kind:    "things",
+			err:     nil,
+		},
+		{
+			desc:    "publish with revoked cert",
+			session: &session.Session{ID: clientID, Password: []byte(password), Cert: cert},
+			authErr: certauth.ErrRevokedCert,
+			err:     certauth.ErrRevokedCert,
+		},
+		{
+			desc:    "publish without cert",
+			session: &sessionClient,
+			subject: password,
+			err:     nil,
+		},
+	}
+
+	for _, tc := range cases {
+		certCall := certAuth.On("Authenticate", mock.Anything, &tc.session.Cert).Return(tc.subject, tc.authErr)
+		authCall := auth.On("Authorize", mock.Anything, &magistrala.AuthorizeReq{
+			SubjectType: "thing",
+			SubjectKind: tc.kind,
+			Permission:  "publish",
+			Subject:     tc.subject,
+			Object:      chanID,
+			ObjectType:  "group",
+		}).Return(&magistrala.AuthorizeRes{Authorized: true, Id: thingID}, nil)
+		ctx := session.NewContext(context.TODO(), tc.session)
+		err := handler.AuthPublish(ctx, &topic, &payload)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		certCall.Unset()
+		authCall.Unset()
+	}
+}
+
+func TestDisconnectCert(t *testing.T) {
+	logger, err := mglog.New(&logBuffer, "debug")
+	if err != nil {
+		log.Fatalf("failed to create logger: %s", err)
+	}
+	certAuth := new(certmocks.Authenticator)
+	handler := mqtt.NewHandler(mocks.NewPublisher(), mocks.NewEventStore(), logger, new(authmocks.AuthClient), certAuth)
+
+	cert := x509.Certificate{Raw: []byte("cert"), Subject: pkix.Name{CommonName: password}}
+
+	cases := []struct {
+		desc    string
+		session *session.Session
+		authErr error
+		err     error
+	}{
+		{
+			desc:    "disconnect with valid cert",
+			session: &session.Session{ID: clientID, Cert: cert},
+			err:     nil,
+		},
+		{
+			desc:    "disconnect with cert revoked in the meantime",
+			session: &session.Session{ID: clientID, Cert: cert},
+			authErr: certauth.ErrRevokedCert,
+			err:     mqtt.ErrFailedPublishDisconnectEvent,
+		},
+	}
+
+	for _, tc := range cases {
+		certCall := certAuth.On("Authenticate", mock.Anything, &tc.session.Cert).Return(thingID, tc.authErr)
+		ctx := session.NewContext(context.TODO(), tc.session)
+		err := handler.Disconnect(ctx)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		certAuth.AssertCalled(t, "Authenticate", mock.Anything, &tc.session.Cert)
+		certCall.Unset()
+	}
+}
+
 func newHandler() (session.Handler, *authmocks.AuthClient) {
 	logger, err := mglog.New(&logBuffer, "debug")
 	if err != nil {
@@ -448,5 +549,5 @@ func newHandler() (session.Handler, *authmocks.AuthClient) {
 	}
 	auth := new(authmocks.AuthClient)
 	eventStore := mocks.NewEventStore()
-	return mqtt.NewHandler(mocks.NewPublisher(), eventStore, logger, auth), auth
+	return mqtt.NewHandler(mocks.NewPublisher(), eventStore, logger, auth, nil), auth
 }
@@ -7,8 +7,11 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/absmach/magistrala/opcua/db"
+	"github.com/absmach/magistrala/pkg/messaging"
+	"github.com/absmach/senml"
 )
 
 // Service specifies an API that must be fullfiled by the domain service
@@ -38,19 +41,37 @@ type Service interface {
 	// DisconnectThing removes thingID:channelID route-map
 	DisconnectThing(ctx context.Context, chanID, thingID string) error
 
+	// SaveSecurity stores the OPC-UA Server security of the channel
+	SaveSecurity(ctx context.Context, chanID string, sec Security) error
+
+	// RemoveSecurity removes the OPC-UA Server security of the channel
+	RemoveSecurity(ctx context.Context, chanID string) error
+
 	// Browse browses available nodes for a given OPC-UA Server URI and NodeID
 	Browse(ctx context.Context, serverURI, namespace, identifier string) ([]BrowsedNode, error)
+
+	// Write writes the message value to the OPC-UA node of the thing and
+	// publishes the result to Magistrala Message Broker
+	Write(ctx context.Context, msg *messaging.Message) error
+
+	// Call calls the method of the OPC-UA object node of the thing and
+	// publishes the result to Magistrala Message Broker
+	Call(ctx context.Context, msg *messaging.Message) error
 }
 
 // Config OPC-UA Server.
 type Config struct {
 	ServerURI string
 	NodeID    string
-	Interval  string `env:"MG_OPCUA_ADAPTER_INTERVAL_MS"   envDefault:"1000"`
-	Policy    string `env:"MG_OPCUA_ADAPTER_POLICY"        envDefault:""`
-	Mode      string `env:"MG_OPCUA_ADAPTER_MODE"          envDefault:""`
-	CertFile  string `env:"MG_OPCUA_ADAPTER_CERT_FILE"     envDefault:""`
-	KeyFile   string `env:"MG_OPCUA_ADAPTER_KEY_FILE"      envDefault:""`
+	Interval  string `env:"MG_OPCUA_ADAPTER_INTERVAL_MS"           envDefault:"1000"`
+	Reconnect string `env:"MG_OPCUA_ADAPTER_RECONNECT_INTERVAL_MS" envDefault:"5000"`
+	Policy    string `env:"MG_OPCUA_ADAPTER_POLICY"                envDefault:""`
+	Mode      string `env:"MG_OPCUA_ADAPTER_MODE"                  envDefault:""`
+	Auth      string `env:"MG_OPCUA_ADAPTER_AUTH"                  envDefault:"anonymous"`
+	CertFile  string `env:"MG_OPCUA_ADAPTER_CERT_FILE"             envDefault:""`
+	KeyFile   string `env:"MG_OPCUA_ADAPTER_KEY_FILE"              envDefault:""`
+	Username  string `env:"MG_OPCUA_ADAPTER_USERNAME"              envDefault:""`
+	Password  string `env:"MG_OPCUA_ADAPTER_PASSWORD"              envDefault:""`
 }
 
 var _ Service = (*adapterService)(nil)
@@ -58,21 +79,27 @@ var _ Service = (*adapterService)(nil)
 type adapterService struct {
 	subscriber Subscriber
 	browser    Browser
+	commander  Commander
+	publisher  messaging.Publisher
 	thingsRM   RouteMapRepository
 	channelsRM RouteMapRepository
 	connectRM  RouteMapRepository
+	security   SecurityRepository
 	cfg        Config
 	logger     *slog.Logger
 }
 
 // New instantiates the OPC-UA adapter implementation.
-func New(sub Subscriber, brow Browser, thingsRM, channelsRM, connectRM RouteMapRepository, cfg Config, log *slog.Logger) Service {
+func New(sub Subscriber, brow Browser, cmd Commander, pub messaging.Publisher, thingsRM, channelsRM, connectRM RouteMapRepository, security SecurityRepository, cfg Config, log *slog.Logger) Service {
 	return &adapterService{
 		subscriber: sub,
 		browser:    brow,
+		commander:  cmd,
+		publisher:  pub,
 		thingsRM:   thingsRM,
 		channelsRM: channelsRM,
 		connectRM:  connectRM,
+		security:   security,
 		cfg:        cfg,
 		logger:     log,
 	}
@@ -99,9 +126,25 @@ func (as *adapterService) UpdateChannel(ctx context.Context, chanID, serverURI s
 }
 
 func (as *adapterService) RemoveChannel(ctx context.Context, chanID string) error {
+	if err := as.security.Remove(ctx, chanID); err != nil {
+		return err
+	}
+
 	return as.channelsRM.Remove(ctx, chanID)
 }
 
+func (as *adapterService) SaveSecurity(ctx context.Context, chanID string, sec Security) error {
+	if err := sec.Validate(); err != nil {
+		return err
+	}
+
+	return as.security.Save(ctx, chanID, sec)
+}
+
+func (as *adapterService) RemoveSecurity(ctx context.Context, chanID string) error {
+	return as.security.Remove(ctx, chanID)
+}
+
 func (as *adapterService) ConnectThing(ctx context.Context, chanID, thingID string) error {
 	serverURI, err := as.channelsRM.Get(ctx, chanID)
 	if err != nil {
@@ -113,8 +156,10 @@ func (as *adapterService) ConnectThing(ctx context.Context, chanID, thingID stri
 		return err
 	}
 
-	as.cfg.NodeID = nodeID
-	as.cfg.ServerURI = serverURI
+	cfg, err := ServerConfig(ctx, as.cfg, as.security, chanID, serverURI, nodeID)
+	if err != nil {
+		return err
+	}
 
 	c := fmt.Sprintf("%s:%s", chanID, thingID)
 	if err := as.connectRM.Save(ctx, c, c); err != nil {
@@ -122,7 +167,7 @@ func (as *adapterService) ConnectThing(ctx context.Context, chanID, thingID stri
 	}
 
 	go func() {
-		if err := as.subscriber.Subscribe(ctx, as.cfg); err != nil {
+		if err := as.subscriber.Subscribe(ctx, cfg); err != nil {
 			as.logger.Warn(fmt.Sprintf("subscription failed: %s", err))
 		}
 	}()
@@ -143,5 +188,113 @@ func (as *adapterService) Browse(ctx context.Context, serverURI, namespace, iden
 
 func (as *adapterService) DisconnectThing(ctx context.Context, chanID, thingID string) error {
 	c := fmt.Sprintf("%s:%s", chanID, thingID)
-	return as.connectRM.Remove(ctx, c)
+	if err := as.connectRM.Remove(ctx, c); err != nil {
+		return err
+	}
+
+	// Subscriptions of the removed things and channels are not restored, so
+	// there is nothing to remove if the route-map is gone.
+	serverURI, err := as.channelsRM.Get(ctx, chanID)
+	if err != nil {
+		return nil
+	}
+	nodeID, err := as.thingsRM.Get(ctx, thingID)
+	if err != nil {
+		return nil
+	}
+
+	// Remove subscription details, so it is not restored
+	return db.Remove(serverURI, nodeID)
+}
+
+func (as *adapterService) Write(ctx context.Context, msg *messaging.Message) error {
+	cfg, thingID, err := as.commandConfig(ctx, msg, WriteSubtopic)
+	if err != nil {
+		return err
+	}
+
+	value, err := writeValue(msg.GetPayload())
+	if err != nil {
+		return err
+	}
+
+	err = as.commander.Write(ctx, cfg, value)
+	if perr := as.publishResult(ctx, msg.GetChannel(), thingID, WriteSubtopic, nil, err); perr != nil {
+		return perr
+	}
+
+	return err
+}
+
+func (as *adapterService) Call(ctx context.Context, msg *messaging.Message) error {
+	cfg, thingID, err := as.commandConfig(ctx, msg, CallSubtopic)
+	if err != nil {
+		return err
+	}
+
+	mc, err := methodCall(msg.GetPayload())
+	if err != nil {
+		return err
+	}
+
+	outputs, err := as.commander.Call(ctx, cfg, mc.Method, mc.Args)
+	if perr := as.publishResult(ctx, msg.GetChannel(), thingID, CallSubtopic, outputs, err); perr != nil {
+		return perr
+	}
+
+	return err
+}
+
+// commandConfig returns the OPC-UA Server config of the command message and
+// the thing ID, checking that the thing is connected to the channel.
+func (as *adapterService) commandConfig(ctx context.Context, msg *messaging.Message, command string) (Config, string, error) {
+	cmd, thingID, err := parseCommand(msg.GetSubtopic())
+	if err != nil {
+		return Config{}, "", err
+	}
+	if cmd != command {
+		return Config{}, "", ErrMalformedCommand
+	}
+
+	serverURI, err := as.channelsRM.Get(ctx, msg.GetChannel())
+	if err != nil {
+		return Config{}, "", ErrNotFoundServerURI
+	}
+
+	nodeID, err := as.thingsRM.Get(ctx, thingID)
+	if err != nil {
+		return Config{}, "", ErrNotFoundNodeID
+	}
+
+	c := fmt.Sprintf("%s:%s", msg.GetChannel(), thingID)
+	if _, err := as.connectRM.Get(ctx, c); err != nil {
+		return Config{}, "", ErrNotConnected
+	}
+
+	cfg, err := ServerConfig(ctx, as.cfg, as.security, msg.GetChannel(), serverURI, nodeID)
+	if err != nil {
+		return Config{}, "", err
+	}
+
+	return cfg, thingID, nil
+}
+
+// publishResult publishes the command result to the channel subtopic
+// <command>.<thingID>.result.
+func (as *adapterService) publishResult(ctx context.Context, chanID, thingID, command string, outputs []interface{}, cmdErr error) error {
+	payload, err := senml.Encode(senml.Pack{Records: resultRecords(outputs, cmdErr)}, senml.JSON)
+	if err != nil {
+		return err
+	}
+
+	msg := messaging.Message{
+		Publisher: thingID,
+		Protocol:  protocol,
+		Channel:   chanID,
+		Subtopic:  fmt.Sprintf("%s.%s.%s", command, thingID, ResultSubtopic),
+		Payload:   payload,
+		Created:   time.Now().UnixNano(),
+	}
+
+	return as.publisher.Publish(ctx, msg.Channel, &msg)
 }
@@ -0,0 +1,234 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package opcua_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	mglog "github.com/absmach/magistrala/logger"
+	"github.com/absmach/magistrala/opcua"
+	"github.com/absmach/magistrala/opcua/mocks"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/pkg/messaging"
+	pubmocks "github.com/absmach/magistrala/pkg/messaging/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	thingID   = "thingID-1"
+	chanID    = "chanID-1"
+	nodeID    = "ns=2;i=1"
+	serverURI = "opc.tcp://opcua.example.com:4840"
+	writeMsg  = `[{"n":"setpoint","v":21.5}]`
+	callMsg   = `{"method":"ns=2;i=10","args":[{"type":"int32","value":5}]}`
+)
+
+var (
+	errCommand = errors.New("bad node id")
+	errPublish = errors.New("failed to publish")
+	serverCfg  = opcua.Config{ServerURI: serverURI, NodeID: nodeID, Interval: "1000", Reconnect: "5000", Auth: opcua.AuthAnonymous}
+)
+
+type mockers struct {
+	commander *mocks.Commander
+	publisher *pubmocks.PubSub
+	thingsRM  *mocks.RouteMapRepository
+	chansRM   *mocks.RouteMapRepository
+	connsRM   *mocks.RouteMapRepository
+	security  *mocks.SecurityRepository
+}
+
+func newService(t *testing.T) (opcua.Service, mockers) {
+	m := mockers{
+		commander: mocks.NewCommander(t),
+		publisher: pubmocks.NewPubSub(t),
+		thingsRM:  mocks.NewRouteMapRepository(t),
+		chansRM:   mocks.NewRouteMapRepository(t),
+		connsRM:   mocks.NewRouteMapRepository(t),
+		security:  mocks.NewSecurityRepository(t),
+	}
+	cfg := opcua.Config{Interval: "1000", Reconnect: "5000", Auth: opcua.AuthAnonymous}
+	svc := opcua.New(nil, nil, m.commander, m.publisher, m.thingsRM, m.chansRM, m.connsRM, m.security, cfg, mglog.NewMock())
+
+	return svc, m
+}
+
+// resultSubtopic matches the command result message of the thing.
+func resultSubtopic(subtopic string) interface{} {
+	return mock.MatchedBy(func(msg *messaging.Message) bool {
+		return msg.GetSubtopic() == subtopic && msg.GetChannel() == chanID && msg.GetProtocol() == "opcua"
+	})
+}
+
+func TestWrite(t *testing.T) {
+	cases := []struct {
+		desc     string
+		subtopic string
+		payload  string
+		chanErr  error
+		thingErr error
+		connErr  error
+		write    bool
+		writeErr error
+		publish  bool
+		pubErr   error
+		err      error
+	}{
+		{
+			desc:     "write value",
+			subtopic: "write." + thingID,
+			payload:  writeMsg,
+			write:    true,
+			publish:  true,
+		},
+		{
+			desc:     "write value with failed write",
+			subtopic: "write." + thingID,
+			payload:  writeMsg,
+			write:    true,
+			writeErr: errCommand,
+			publish:  true,
+			err:      errCommand,
+		},
+		{
+			desc:     "write value with failed result publish",
+			subtopic: "write." + thingID,
+			payload:  writeMsg,
+			write:    true,
+			publish:  true,
+			pubErr:   errPublish,
+			err:      errPublish,
+		},
+		{
+			desc:     "write value with call subtopic",
+			subtopic: "call." + thingID,
+			payload:  writeMsg,
+			err:      opcua.ErrMalformedCommand,
+		},
+		{
+			desc:     "write value with malformed subtopic",
+			subtopic: "write",
+			payload:  writeMsg,
+			err:      opcua.ErrMalformedCommand,
+		},
+		{
+			desc:     "write value to channel without route-map",
+			subtopic: "write." + thingID,
+			payload:  writeMsg,
+			chanErr:  opcua.ErrNotFoundServerURI,
+			err:      opcua.ErrNotFoundServerURI,
+		},
+		{
+			desc:     "write value to thing without route-map",
+			subtopic: "write." + thingID,
+			payload:  writeMsg,
+			thingErr: opcua.ErrNotFoundNodeID,
+			err:      opcua.ErrNotFoundNodeID,
+		},
+		{
+			desc:     "write value to disconnected thing",
+			subtopic: "write." + thingID,
+			payload:  writeMsg,
+			connErr:  opcua.ErrNotConnected,
+			err:      opcua.ErrNotConnected,
+		},
+		{
+			desc:     "write value with malformed payload",
+			subtopic: "write." + thingID,
+			payload:  `[{"n":"setpoint"}]`,
+			err:      opcua.ErrMalformedCommand,
+		},
+	}
+
+	for _, tc := range cases {
+		svc, m := newService(t)
+		m.chansRM.On("Get", mock.Anything, chanID).Return(serverURI, tc.chanErr).Maybe()
+		m.thingsRM.On("Get", mock.Anything, thingID).Return(nodeID, tc.thingErr).Maybe()
+		m.connsRM.On("Get", mock.Anything, chanID+":"+thingID).Return(chanID+":"+thingID, tc.connErr).Maybe()
+		m.security.On("Get", mock.Anything, chanID).Return(opcua.Security{}, opcua.ErrNotFoundSecurity).Maybe()
+		if tc.write {
+			m.commander.On("Write", mock.Anything, serverCfg, 21.5).Return(tc.writeErr)
+		}
+		if tc.publish {
+			m.publisher.On("Publish", mock.Anything, chanID, resultSubtopic("write."+thingID+".result")).Return(tc.pubErr)
+		}
+
+		msg := messaging.Message{Channel: chanID, Subtopic: tc.subtopic, Payload: []byte(tc.payload)}
+		err := svc.Write(context.Background(), &msg)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestCall(t *testing.T) {
+	cases := []struct {
+		desc     string
+		subtopic string
+		payload  string
+		connErr  error
+		call     bool
+		outputs  []interface{}
+		callErr  error
+		publish  bool
+		err      error
+	}{
+		{
+			desc:     "call method",
+			subtopic: "call." + thingID,
+			payload:  callMsg,
+			call:     true,
+			outputs:  []interface{}{int32(1)},
+			publish:  true,
+		},
+		{
+			desc:     "call method with failed call",
+			subtopic: "call." + thingID,
+			payload:  callMsg,
+			call:     true,
+			callErr:  errCommand,
+			publish:  true,
+			err:      errCommand,
+		},
+		{
+			desc:     "call method with write subtopic",
+			subtopic: "write." + thingID,
+			payload:  callMsg,
+			err:      opcua.ErrMalformedCommand,
+		},
+		{
+			desc:     "call method of disconnected thing",
+			subtopic: "call." + thingID,
+			payload:  callMsg,
+			connErr:  opcua.ErrNotConnected,
+			err:      opcua.ErrNotConnected,
+		},
+		{
+			desc:     "call method without method",
+			subtopic: "call." + thingID,
+			payload:  `{"args":[]}`,
+			err:      opcua.ErrMalformedCommand,
+		},
+	}
+
+	args := []opcua.Argument{{Type: "int32", Value: 5.0}}
+	for _, tc := range cases {
+		svc, m := newService(t)
+		m.chansRM.On("Get", mock.Anything, chanID).Return(serverURI, nil).Maybe()
+		m.thingsRM.On("Get", mock.Anything, thingID).Return(nodeID, nil).Maybe()
+		m.connsRM.On("Get", mock.Anything, chanID+":"+thingID).Return(chanID+":"+thingID, tc.connErr).Maybe()
+		m.security.On("Get", mock.Anything, chanID).Return(opcua.Security{}, opcua.ErrNotFoundSecurity).Maybe()
+		if tc.call {
+			m.commander.On("Call", mock.Anything, serverCfg, "ns=2;i=10", args).Return(tc.outputs, tc.callErr)
+		}
+		if tc.publish {
+			m.publisher.On("Publish", mock.Anything, chanID, resultSubtopic("call."+thingID+".result")).Return(nil)
+		}
+
+		msg := messaging.Message{Channel: chanID, Subtopic: tc.subtopic, Payload: []byte(tc.payload)}
+		err := svc.Call(context.Background(), &msg)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
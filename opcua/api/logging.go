@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/absmach/magistrala/opcua"
+	"github.com/absmach/magistrala/pkg/messaging"
 )
 
 var _ opcua.Service = (*loggingMiddleware)(nil)
@@ -170,6 +171,42 @@ func (lm loggingMiddleware) DisconnectThing(ctx context.Context, mgxChanID, mgxT
 	return lm.svc.DisconnectThing(ctx, mgxChanID, mgxThingID)
 }
 
+func (lm loggingMiddleware) SaveSecurity(ctx context.Context, mgxChanID string, sec opcua.Security) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("channel_id", mgxChanID),
+			slog.String("mode", sec.Mode),
+			slog.String("auth", sec.Auth),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Save channel security failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Save channel security completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.SaveSecurity(ctx, mgxChanID, sec)
+}
+
+func (lm loggingMiddleware) RemoveSecurity(ctx context.Context, mgxChanID string) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("channel_id", mgxChanID),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Remove channel security failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Remove channel security completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.RemoveSecurity(ctx, mgxChanID)
+}
+
 func (lm loggingMiddleware) Browse(ctx context.Context, serverURI, namespace, identifier string) (nodes []opcua.BrowsedNode, err error) {
 	defer func(begin time.Time) {
 		args := []any{
@@ -188,3 +225,39 @@ func (lm loggingMiddleware) Browse(ctx context.Context, serverURI, namespace, id
 
 	return lm.svc.Browse(ctx, serverURI, namespace, identifier)
 }
+
+func (lm loggingMiddleware) Write(ctx context.Context, msg *messaging.Message) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("channel_id", msg.GetChannel()),
+			slog.String("subtopic", msg.GetSubtopic()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Write node value failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Write node value completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.Write(ctx, msg)
+}
+
+func (lm loggingMiddleware) Call(ctx context.Context, msg *messaging.Message) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("channel_id", msg.GetChannel()),
+			slog.String("subtopic", msg.GetSubtopic()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Call method failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Call method completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.Call(ctx, msg)
+}
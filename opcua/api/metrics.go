@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/absmach/magistrala/opcua"
+	"github.com/absmach/magistrala/pkg/messaging"
 	"github.com/go-kit/kit/metrics"
 )
 
@@ -102,6 +103,24 @@ func (mm *metricsMiddleware) DisconnectThing(ctx context.Context, mgxChanID, mgx
 	return mm.svc.DisconnectThing(ctx, mgxChanID, mgxThingID)
 }
 
+func (mm *metricsMiddleware) SaveSecurity(ctx context.Context, mgxChanID string, sec opcua.Security) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "save_security").Add(1)
+		mm.latency.With("method", "save_security").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.SaveSecurity(ctx, mgxChanID, sec)
+}
+
+func (mm *metricsMiddleware) RemoveSecurity(ctx context.Context, mgxChanID string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "remove_security").Add(1)
+		mm.latency.With("method", "remove_security").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RemoveSecurity(ctx, mgxChanID)
+}
+
 func (mm *metricsMiddleware) Browse(ctx context.Context, serverURI, namespace, identifier string) ([]opcua.BrowsedNode, error) {
 	defer func(begin time.Time) {
 		mm.counter.With("method", "browse").Add(1)
@@ -110,3 +129,21 @@ func (mm *metricsMiddleware) Browse(ctx context.Context, serverURI, namespace, i
 
 	return mm.svc.Browse(ctx, serverURI, namespace, identifier)
 }
+
+func (mm *metricsMiddleware) Write(ctx context.Context, msg *messaging.Message) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "write").Add(1)
+		mm.latency.With("method", "write").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Write(ctx, msg)
+}
+
+func (mm *metricsMiddleware) Call(ctx context.Context, msg *messaging.Message) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "call").Add(1)
+		mm.latency.With("method", "call").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Call(ctx, msg)
+}
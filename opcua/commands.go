@@ -0,0 +1,214 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package opcua
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/pkg/messaging"
+	"github.com/absmach/senml"
+)
+
+const (
+	// WriteSubtopic is the Magistrala subtopic prefix of the messages written
+	// to the OPC-UA node of the thing. The full subtopic is write.<thingID>.
+	WriteSubtopic = "write"
+
+	// CallSubtopic is the Magistrala subtopic prefix of the messages calling
+	// the method of the OPC-UA object node of the thing. The full subtopic is
+	// call.<thingID>.
+	CallSubtopic = "call"
+
+	// ResultSubtopic is the Magistrala subtopic suffix of the command results.
+	ResultSubtopic = "result"
+
+	protocol = "opcua"
+)
+
+var (
+	// ErrMalformedCommand indicates malformed write or method call message.
+	ErrMalformedCommand = errors.New("malformed command")
+
+	// ErrNotFoundServerURI indicates a non-existent route map for a channel.
+	ErrNotFoundServerURI = errors.New("route map not found for this channel")
+
+	// ErrNotFoundNodeID indicates a non-existent route map for a thing.
+	ErrNotFoundNodeID = errors.New("route map not found for this thing")
+
+	// ErrNotConnected indicates a non-existent route map for a connection.
+	ErrNotConnected = errors.New("route map not found for this connection")
+)
+
+// MethodCall is the payload of the method call message.
+type MethodCall struct {
+	// Method is the NodeID of the called method.
+	Method string     `json:"method"`
+	Args   []Argument `json:"args,omitempty"`
+}
+
+// Argument is the method input argument. The type is one of bool, string,
+// byte, int8, int16, int32, int64, uint16, uint32, uint64, float32 and
+// float64. Unless set, the type is inferred from the JSON value.
+type Argument struct {
+	Type  string      `json:"type,omitempty"`
+	Value interface{} `json:"value"`
+}
+
+// Commander executes the commands on the OPC-UA Server.
+//
+//go:generate mockery --name Commander --output=./mocks --filename commander.go --quiet --note "Copyright (c) Abstract Machines"
+type Commander interface {
+	// Write writes the value to the configured node.
+	Write(ctx context.Context, cfg Config, value interface{}) error
+
+	// Call calls the method of the configured object node and returns the
+	// method output arguments.
+	Call(ctx context.Context, cfg Config, methodID string, args []Argument) ([]interface{}, error)
+}
+
+// parseCommand returns the command and the thing ID of the subtopic of the
+// form <command>.<thingID>.
+func parseCommand(subtopic string) (string, string, error) {
+	cmd, thingID, ok := strings.Cut(subtopic, ".")
+	if !ok || thingID == "" || strings.Contains(thingID, ".") {
+		return "", "", ErrMalformedCommand
+	}
+
+	return cmd, thingID, nil
+}
+
+// writeValue returns the value of the first record of the SenML payload.
+func writeValue(payload []byte) (interface{}, error) {
+	p, err := senml.Decode(payload, senml.JSON)
+	if err != nil {
+		return nil, errors.Wrap(ErrMalformedCommand, err)
+	}
+	if len(p.Records) == 0 {
+		return nil, ErrMalformedCommand
+	}
+
+	r := p.Records[0]
+	switch {
+	case r.Value != nil:
+		return *r.Value, nil
+	case r.BoolValue != nil:
+		return *r.BoolValue, nil
+	case r.StringValue != nil:
+		return *r.StringValue, nil
+	case r.DataValue != nil:
+		return *r.DataValue, nil
+	default:
+		return nil, ErrMalformedCommand
+	}
+}
+
+func methodCall(payload []byte) (MethodCall, error) {
+	var mc MethodCall
+	if err := json.Unmarshal(payload, &mc); err != nil {
+		return MethodCall{}, errors.Wrap(ErrMalformedCommand, err)
+	}
+	if mc.Method == "" {
+		return MethodCall{}, ErrMalformedCommand
+	}
+
+	return mc, nil
+}
+
+// resultRecords returns the SenML records of the command result. The status
+// record contains the error, if any, and the output arguments are named
+// output_<index>.
+func resultRecords(outputs []interface{}, cmdErr error) []senml.Record {
+	status := "OK"
+	if cmdErr != nil {
+		status = cmdErr.Error()
+	}
+	recs := []senml.Record{{Name: "status", StringValue: &status}}
+
+	for i, out := range outputs {
+		r := senml.Record{Name: "output_" + strconv.Itoa(i)}
+		switch v := out.(type) {
+		case bool:
+			r.BoolValue = &v
+		case string:
+			r.StringValue = &v
+		default:
+			if f, ok := toFloat(v); ok {
+				r.Value = &f
+				break
+			}
+			s := fmt.Sprint(v)
+			r.StringValue = &s
+		}
+		recs = append(recs, r)
+	}
+
+	return recs
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+type commandHandler struct {
+	ctx context.Context
+	svc Service
+}
+
+// NewCommandHandler returns the Magistrala message handler which forwards the
+// write and method call messages to the OPC-UA Server and ignores the other
+// messages.
+func NewCommandHandler(ctx context.Context, svc Service) messaging.MessageHandler {
+	return commandHandler{
+		ctx: ctx,
+		svc: svc,
+	}
+}
+
+func (h commandHandler) Handle(msg *messaging.Message) error {
+	// Messages published by the adapter itself are never forwarded.
+	if msg.GetProtocol() == protocol {
+		return nil
+	}
+
+	switch {
+	case strings.HasPrefix(msg.GetSubtopic(), WriteSubtopic+"."):
+		return h.svc.Write(h.ctx, msg)
+	case strings.HasPrefix(msg.GetSubtopic(), CallSubtopic+"."):
+		return h.svc.Call(h.ctx, msg)
+	default:
+		return nil
+	}
+}
+
+func (h commandHandler) Cancel() error {
+	return nil
+}
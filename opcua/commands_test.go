@@ -0,0 +1,191 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package opcua
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/senml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommand(t *testing.T) {
+	cases := []struct {
+		desc     string
+		subtopic string
+		cmd      string
+		thingID  string
+		err      error
+	}{
+		{
+			desc:     "parse write command",
+			subtopic: "write.thingID",
+			cmd:      WriteSubtopic,
+			thingID:  "thingID",
+		},
+		{
+			desc:     "parse call command",
+			subtopic: "call.thingID",
+			cmd:      CallSubtopic,
+			thingID:  "thingID",
+		},
+		{
+			desc:     "parse command without thing ID",
+			subtopic: "write",
+			err:      ErrMalformedCommand,
+		},
+		{
+			desc:     "parse command with empty thing ID",
+			subtopic: "write.",
+			err:      ErrMalformedCommand,
+		},
+		{
+			desc:     "parse result subtopic",
+			subtopic: "write.thingID.result",
+			err:      ErrMalformedCommand,
+		},
+	}
+
+	for _, tc := range cases {
+		cmd, thingID, err := parseCommand(tc.subtopic)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.cmd, cmd, fmt.Sprintf("%s: expected command %s got %s\n", tc.desc, tc.cmd, cmd))
+		assert.Equal(t, tc.thingID, thingID, fmt.Sprintf("%s: expected thing ID %s got %s\n", tc.desc, tc.thingID, thingID))
+	}
+}
+
+func TestWriteValue(t *testing.T) {
+	cases := []struct {
+		desc    string
+		payload string
+		value   interface{}
+		err     error
+	}{
+		{
+			desc:    "write numeric value",
+			payload: `[{"n":"setpoint","v":21.5}]`,
+			value:   21.5,
+		},
+		{
+			desc:    "write bool value",
+			payload: `[{"n":"enabled","vb":true}]`,
+			value:   true,
+		},
+		{
+			desc:    "write string value",
+			payload: `[{"n":"mode","vs":"auto"}]`,
+			value:   "auto",
+		},
+		{
+			desc:    "write data value",
+			payload: `[{"n":"blob","vd":"AQI="}]`,
+			value:   "AQI=",
+		},
+		{
+			desc:    "write value of the first record",
+			payload: `[{"n":"setpoint","v":1},{"n":"setpoint","v":2}]`,
+			value:   1.0,
+		},
+		{
+			desc:    "write empty payload",
+			payload: `[]`,
+			err:     ErrMalformedCommand,
+		},
+		{
+			desc:    "write record without value",
+			payload: `[{"n":"setpoint"}]`,
+			err:     ErrMalformedCommand,
+		},
+		{
+			desc:    "write malformed payload",
+			payload: `{"v":1`,
+			err:     ErrMalformedCommand,
+		},
+	}
+
+	for _, tc := range cases {
+		value, err := writeValue([]byte(tc.payload))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.value, value, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.value, value))
+	}
+}
+
+func TestMethodCall(t *testing.T) {
+	cases := []struct {
+		desc    string
+		payload string
+		call    MethodCall
+		err     error
+	}{
+		{
+			desc:    "parse method call",
+			payload: `{"method":"ns=2;i=10","args":[{"type":"int32","value":5},{"value":"on"}]}`,
+			call: MethodCall{
+				Method: "ns=2;i=10",
+				Args:   []Argument{{Type: "int32", Value: 5.0}, {Value: "on"}},
+			},
+		},
+		{
+			desc:    "parse method call without arguments",
+			payload: `{"method":"ns=2;i=10"}`,
+			call:    MethodCall{Method: "ns=2;i=10"},
+		},
+		{
+			desc:    "parse method call without method",
+			payload: `{"args":[{"value":1}]}`,
+			err:     ErrMalformedCommand,
+		},
+		{
+			desc:    "parse malformed method call",
+			payload: `{"method":`,
+			err:     ErrMalformedCommand,
+		},
+	}
+
+	for _, tc := range cases {
+		call, err := methodCall([]byte(tc.payload))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.call, call, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.call, call))
+	}
+}
+
+func TestResultRecords(t *testing.T) {
+	ok, failed, on := "OK", "failed", "on"
+	num, flag, str := 5.0, true, "[1 2]"
+
+	cases := []struct {
+		desc    string
+		outputs []interface{}
+		err     error
+		recs    []senml.Record
+	}{
+		{
+			desc: "result of successful command",
+			recs: []senml.Record{{Name: "status", StringValue: &ok}},
+		},
+		{
+			desc: "result of failed command",
+			err:  errors.New(failed),
+			recs: []senml.Record{{Name: "status", StringValue: &failed}},
+		},
+		{
+			desc:    "result with outputs",
+			outputs: []interface{}{int32(5), true, "on", []int{1, 2}},
+			recs: []senml.Record{
+				{Name: "status", StringValue: &ok},
+				{Name: "output_0", Value: &num},
+				{Name: "output_1", BoolValue: &flag},
+				{Name: "output_2", StringValue: &on},
+				{Name: "output_3", StringValue: &str},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		recs := resultRecords(tc.outputs, tc.err)
+		assert.Equal(t, tc.recs, recs, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.recs, recs))
+	}
+}
@@ -11,10 +11,10 @@ import (
 	"github.com/absmach/magistrala/pkg/errors"
 )
 
-const (
-	columns = 2
-	path    = "/store/nodes.csv"
-)
+const columns = 2
+
+// path is the file storing the subscriptions.
+var path = "/store/nodes.csv"
 
 var (
 	errNotFound  = errors.New("file not found")
@@ -30,12 +30,24 @@ type Node struct {
 	NodeID    string
 }
 
-// Save stores a successful subscription.
+// Save stores a successful subscription, unless it is already stored.
 func Save(serverURI, nodeID string) error {
+	nodes, err := ReadAll()
+	if err != nil && !errors.Contains(err, errNotFound) {
+		return err
+	}
+	for _, n := range nodes {
+		if n.ServerURI == serverURI && n.NodeID == nodeID {
+			return nil
+		}
+	}
+
 	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, os.ModePerm)
 	if err != nil {
 		return errors.Wrap(errWriteFile, err)
 	}
+	defer file.Close()
+
 	csvWriter := csv.NewWriter(file)
 	err = csvWriter.Write([]string{serverURI, nodeID})
 	csvWriter.Flush()
@@ -78,3 +90,47 @@ func ReadAll() ([]Node, error) {
 
 	return nodes, nil
 }
+
+// Remove removes the stored subscription.
+func Remove(serverURI, nodeID string) error {
+	nodes, err := ReadAll()
+	if err != nil {
+		if errors.Contains(err, errNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	records := [][]string{}
+	for _, n := range nodes {
+		if n.ServerURI == serverURI && n.NodeID == nodeID {
+			continue
+		}
+		records = append(records, []string{n.ServerURI, n.NodeID})
+	}
+	if len(records) == len(nodes) {
+		return nil
+	}
+
+	// Replace the file at once, so the subscriptions are not lost if the
+	// write fails.
+	tmp := path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.ModePerm)
+	if err != nil {
+		return errors.Wrap(errWriteFile, err)
+	}
+	csvWriter := csv.NewWriter(file)
+	err = csvWriter.WriteAll(records)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return errors.Wrap(errWriteFile, err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Wrap(errWriteFile, err)
+	}
+
+	return nil
+}
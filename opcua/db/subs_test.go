@@ -0,0 +1,72 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package db
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	serverURI  = "opc.tcp://opcua.example.com:4840"
+	serverURI2 = "opc.tcp://opcua2.example.com:4840"
+	nodeID     = "ns=2;i=1"
+	nodeID2    = "ns=2;i=2"
+)
+
+func TestRemove(t *testing.T) {
+	path = filepath.Join(t.TempDir(), "nodes.csv")
+
+	err := Remove(serverURI, nodeID)
+	assert.Nil(t, err, fmt.Sprintf("remove subscription without file: unexpected error %s", err))
+
+	for _, n := range []Node{{serverURI, nodeID}, {serverURI, nodeID2}, {serverURI2, nodeID}} {
+		err := Save(n.ServerURI, n.NodeID)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc      string
+		serverURI string
+		nodeID    string
+		nodes     []Node
+	}{
+		{
+			desc:      "remove stored subscription",
+			serverURI: serverURI,
+			nodeID:    nodeID2,
+			nodes:     []Node{{serverURI, nodeID}, {serverURI2, nodeID}},
+		},
+		{
+			desc:      "remove already removed subscription",
+			serverURI: serverURI,
+			nodeID:    nodeID2,
+			nodes:     []Node{{serverURI, nodeID}, {serverURI2, nodeID}},
+		},
+		{
+			desc:      "remove subscription of another server",
+			serverURI: serverURI2,
+			nodeID:    nodeID,
+			nodes:     []Node{{serverURI, nodeID}},
+		},
+		{
+			desc:      "remove last subscription",
+			serverURI: serverURI,
+			nodeID:    nodeID,
+			nodes:     []Node{},
+		},
+	}
+
+	for _, tc := range cases {
+		err := Remove(tc.serverURI, tc.nodeID)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		nodes, err := ReadAll()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.nodes, nodes, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.nodes, nodes))
+	}
+}
@@ -3,6 +3,8 @@
 
 package events
 
+import "github.com/absmach/magistrala/opcua"
+
 type createThingEvent struct {
 	id          string
 	opcuaNodeID string
@@ -20,6 +22,7 @@ type connectThingEvent struct {
 type createChannelEvent struct {
 	id             string
 	opcuaServerURI string
+	security       *opcua.Security
 }
 
 type removeChannelEvent struct {
@@ -0,0 +1,62 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/absmach/magistrala/opcua"
+	"github.com/go-redis/redis/v8"
+)
+
+var _ opcua.SecurityRepository = (*securityRepository)(nil)
+
+type securityRepository struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewSecurityRepository returns redis channel security repository
+// implementation.
+func NewSecurityRepository(client *redis.Client, prefix string) opcua.SecurityRepository {
+	return &securityRepository{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (sr *securityRepository) Save(ctx context.Context, chanID string, sec opcua.Security) error {
+	val, err := json.Marshal(sec)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("%s:%s", sr.prefix, chanID)
+	return sr.client.Set(ctx, key, val, 0).Err()
+}
+
+func (sr *securityRepository) Get(ctx context.Context, chanID string) (opcua.Security, error) {
+	key := fmt.Sprintf("%s:%s", sr.prefix, chanID)
+	val, err := sr.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return opcua.Security{}, opcua.ErrNotFoundSecurity
+		}
+		return opcua.Security{}, err
+	}
+
+	var sec opcua.Security
+	if err := json.Unmarshal(val, &sec); err != nil {
+		return opcua.Security{}, err
+	}
+
+	return sec, nil
+}
+
+func (sr *securityRepository) Remove(ctx context.Context, chanID string) error {
+	key := fmt.Sprintf("%s:%s", sr.prefix, chanID)
+	return sr.client.Del(ctx, key).Err()
+}
@@ -0,0 +1,128 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package events_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/absmach/magistrala/opcua"
+	"github.com/absmach/magistrala/opcua/events"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	securityPrefix = "security"
+	chanID         = "chanID-1"
+	chanID2        = "chanID-2"
+)
+
+var security = opcua.Security{
+	Policy:       "Basic256Sha256",
+	Mode:         "SignAndEncrypt",
+	Auth:         opcua.AuthUsername,
+	Username:     "operator",
+	PasswordFile: "/secrets/opcua-password",
+}
+
+func TestSecuritySave(t *testing.T) {
+	redisClient.FlushAll(context.Background())
+	repo := events.NewSecurityRepository(redisClient, securityPrefix)
+
+	cases := []struct {
+		desc   string
+		chanID string
+		sec    opcua.Security
+	}{
+		{
+			desc:   "save channel security",
+			chanID: chanID,
+			sec:    security,
+		},
+		{
+			desc:   "save existing channel security",
+			chanID: chanID,
+			sec:    opcua.Security{Auth: opcua.AuthAnonymous},
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(context.Background(), tc.chanID, tc.sec)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		sec, err := repo.Get(context.Background(), tc.chanID)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		assert.Equal(t, tc.sec, sec, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.sec, sec))
+	}
+}
+
+func TestSecurityGet(t *testing.T) {
+	redisClient.FlushAll(context.Background())
+	repo := events.NewSecurityRepository(redisClient, securityPrefix)
+
+	err := repo.Save(context.Background(), chanID, security)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = redisClient.Set(context.Background(), fmt.Sprintf("%s:%s", securityPrefix, chanID2), "{", 0).Err()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc   string
+		chanID string
+		sec    opcua.Security
+		err    error
+	}{
+		{
+			desc:   "get channel security",
+			chanID: chanID,
+			sec:    security,
+		},
+		{
+			desc:   "get non-existent channel security",
+			chanID: "non-existent",
+			err:    opcua.ErrNotFoundSecurity,
+		},
+		{
+			desc:   "get malformed channel security",
+			chanID: chanID2,
+			err:    errors.New("unexpected end of JSON input"),
+		},
+	}
+
+	for _, tc := range cases {
+		sec, err := repo.Get(context.Background(), tc.chanID)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.sec, sec, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.sec, sec))
+	}
+}
+
+func TestSecurityRemove(t *testing.T) {
+	redisClient.FlushAll(context.Background())
+	repo := events.NewSecurityRepository(redisClient, securityPrefix)
+
+	err := repo.Save(context.Background(), chanID, security)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc   string
+		chanID string
+	}{
+		{
+			desc:   "remove channel security",
+			chanID: chanID,
+		},
+		{
+			desc:   "remove non-existent channel security",
+			chanID: chanID,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Remove(context.Background(), tc.chanID)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		_, err = repo.Get(context.Background(), tc.chanID)
+		assert.True(t, errors.Contains(err, opcua.ErrNotFoundSecurity), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, opcua.ErrNotFoundSecurity, err))
+	}
+}
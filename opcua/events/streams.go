@@ -16,6 +16,14 @@ const (
 	keyType      = "opcua"
 	keyNodeID    = "node_id"
 	keyServerURI = "server_uri"
+	keyPolicy    = "policy"
+	keyMode      = "mode"
+	keyAuth      = "auth"
+	keyCertFile  = "cert_file"
+	keyKeyFile   = "key_file"
+	keyUsername  = "username"
+	keyPassword  = "password"
+	keyPassFile  = "password_file"
 
 	thingPrefix     = "thing."
 	thingCreate     = thingPrefix + "create"
@@ -38,6 +46,10 @@ var (
 	errMetadataServerURI = errors.New("ServerURI not found in channel metadatada")
 
 	errMetadataNodeID = errors.New("NodeID not found in thing metadatada")
+
+	errMetadataSecurity = errors.New("malformed security in channel metadata")
+
+	errMetadataPassword = errors.New("channel metadata must reference the password using password_file")
 )
 
 type eventHandler struct {
@@ -81,14 +93,20 @@ func (es *eventHandler) Handle(ctx context.Context, event events.Event) error {
 			err = e
 			break
 		}
-		err = es.svc.CreateChannel(ctx, cce.id, cce.opcuaServerURI)
+		if err = es.svc.CreateChannel(ctx, cce.id, cce.opcuaServerURI); err != nil {
+			break
+		}
+		err = es.saveSecurity(ctx, cce.id, cce.security)
 	case channelUpdate:
 		uce, e := decodeCreateChannel(msg)
 		if e != nil {
 			err = e
 			break
 		}
-		err = es.svc.CreateChannel(ctx, uce.id, uce.opcuaServerURI)
+		if err = es.svc.CreateChannel(ctx, uce.id, uce.opcuaServerURI); err != nil {
+			break
+		}
+		err = es.saveSecurity(ctx, uce.id, uce.security)
 	case channelRemove:
 		rce := decodeRemoveChannel(msg)
 		err = es.svc.RemoveChannel(ctx, rce.id)
@@ -106,6 +124,16 @@ func (es *eventHandler) Handle(ctx context.Context, event events.Event) error {
 	return nil
 }
 
+// saveSecurity saves the security of the channel, removing the security
+// if the channel metadata has none.
+func (es *eventHandler) saveSecurity(ctx context.Context, chanID string, sec *opcua.Security) error {
+	if sec == nil {
+		return es.svc.RemoveSecurity(ctx, chanID)
+	}
+
+	return es.svc.SaveSecurity(ctx, chanID, *sec)
+}
+
 func decodeCreateThing(event map[string]interface{}) (createThingEvent, error) {
 	strmeta := read(event, "metadata", "{}")
 	var metadata map[string]interface{}
@@ -169,9 +197,52 @@ func decodeCreateChannel(event map[string]interface{}) (createChannelEvent, erro
 	}
 
 	cce.opcuaServerURI = val
+
+	sec, err := decodeSecurity(metadataVal)
+	if err != nil {
+		return createChannelEvent{}, err
+	}
+	cce.security = sec
+
 	return cce, nil
 }
 
+// decodeSecurity returns the channel security, or nil if the metadata has
+// no security fields.
+func decodeSecurity(metadata map[string]interface{}) (*opcua.Security, error) {
+	var sec opcua.Security
+	fields := map[string]*string{
+		keyPolicy:   &sec.Policy,
+		keyMode:     &sec.Mode,
+		keyAuth:     &sec.Auth,
+		keyCertFile: &sec.CertFile,
+		keyKeyFile:  &sec.KeyFile,
+		keyUsername: &sec.Username,
+		keyPassFile: &sec.PasswordFile,
+	}
+	// Plain text password would be exposed by the channel metadata and
+	// stored in the route-map database.
+	if _, ok := metadata[keyPassword]; ok {
+		return nil, errMetadataPassword
+	}
+	for key, dst := range fields {
+		v, ok := metadata[key]
+		if !ok {
+			continue
+		}
+		s, ok := v.(string)
+		if !ok {
+			return nil, errMetadataSecurity
+		}
+		*dst = s
+	}
+	if sec == (opcua.Security{}) {
+		return nil, nil
+	}
+
+	return &sec, nil
+}
+
 func decodeRemoveChannel(event map[string]interface{}) removeChannelEvent {
 	return removeChannelEvent{
 		id: read(event, "id", ""),
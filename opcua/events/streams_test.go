@@ -0,0 +1,57 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/absmach/magistrala/opcua"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeSecurity(t *testing.T) {
+	cases := []struct {
+		desc     string
+		metadata map[string]interface{}
+		sec      *opcua.Security
+		err      error
+	}{
+		{
+			desc:     "decode metadata without security",
+			metadata: map[string]interface{}{keyServerURI: "opc.tcp://opcua.example.com:4840"},
+		},
+		{
+			desc: "decode security",
+			metadata: map[string]interface{}{
+				keyMode:     "Sign",
+				keyAuth:     opcua.AuthUsername,
+				keyUsername: "operator",
+				keyPassFile: "/secrets/opcua-password",
+			},
+			sec: &opcua.Security{Mode: "Sign", Auth: opcua.AuthUsername, Username: "operator", PasswordFile: "/secrets/opcua-password"},
+		},
+		{
+			desc: "decode security with plain text password",
+			metadata: map[string]interface{}{
+				keyAuth:     opcua.AuthUsername,
+				keyUsername: "operator",
+				keyPassword: "secret",
+			},
+			err: errMetadataPassword,
+		},
+		{
+			desc:     "decode malformed security",
+			metadata: map[string]interface{}{keyAuth: 1},
+			err:      errMetadataSecurity,
+		},
+	}
+
+	for _, tc := range cases {
+		sec, err := decodeSecurity(tc.metadata)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.sec, sec, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.sec, sec))
+	}
+}
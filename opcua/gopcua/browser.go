@@ -32,6 +32,24 @@ type NodeDef struct {
 	Max         string
 }
 
+// dataTypes maps the OPC-UA data type IDs to the names of the Go types.
+var dataTypes = map[uint32]string{
+	id.DateTime: "time.Time",
+	id.Boolean:  "bool",
+	id.SByte:    "int8",
+	id.Int16:    "int16",
+	id.Int32:    "int32",
+	id.Int64:    "int64",
+	id.Byte:     "byte",
+	id.UInt16:   "uint16",
+	id.UInt32:   "uint32",
+	id.UInt64:   "uint64",
+	id.UtcTime:  "time.Time",
+	id.String:   "string",
+	id.Float:    "float32",
+	id.Double:   "float64",
+}
+
 var _ opcua.Browser = (*browser)(nil)
 
 type browser struct {
@@ -140,34 +158,11 @@ func browse(oc *opcuagocpua.Client, nodeID, path string, level int) ([]NodeDef,
 
 	switch err := attrs[4].Status; err {
 	case uagocpua.StatusOK:
-		switch v := attrs[4].Value.NodeID().IntID(); v {
-		case id.DateTime:
-			def.DataType = "time.Time"
-		case id.Boolean:
-			def.DataType = "bool"
-		case id.SByte:
-			def.DataType = "int8"
-		case id.Int16:
-			def.DataType = "int16"
-		case id.Int32:
-			def.DataType = "int32"
-		case id.Byte:
-			def.DataType = "byte"
-		case id.UInt16:
-			def.DataType = "uint16"
-		case id.UInt32:
-			def.DataType = "uint32"
-		case id.UtcTime:
-			def.DataType = "time.Time"
-		case id.String:
-			def.DataType = "string"
-		case id.Float:
-			def.DataType = "float32"
-		case id.Double:
-			def.DataType = "float64"
-		default:
-			def.DataType = attrs[4].Value.NodeID().String()
+		dt, ok := dataTypes[attrs[4].Value.NodeID().IntID()]
+		if !ok {
+			dt = attrs[4].Value.NodeID().String()
 		}
+		def.DataType = dt
 	case uagocpua.StatusBadAttributeIDInvalid:
 		// ignore
 	default:
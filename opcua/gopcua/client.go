@@ -0,0 +1,113 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package gopcua
+
+import (
+	"context"
+	"encoding/pem"
+	"os"
+
+	"github.com/absmach/magistrala/opcua"
+	"github.com/absmach/magistrala/pkg/errors"
+	opcuagopcua "github.com/gopcua/opcua"
+	uagopcua "github.com/gopcua/opcua/ua"
+)
+
+const securityNone = "None"
+
+var errFailedReadCert = errors.New("failed to read certificate")
+
+// connect connects to the configured OPC-UA Server.
+func connect(ctx context.Context, cfg opcua.Config) (*opcuagopcua.Client, error) {
+	opts, err := clientOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	oc := opcuagopcua.NewClient(cfg.ServerURI, opts...)
+	if err := oc.Connect(ctx); err != nil {
+		return nil, errors.Wrap(errFailedConn, err)
+	}
+
+	return oc, nil
+}
+
+// clientOptions returns the OPC-UA client options of the configured security
+// policy, mode and user authentication.
+func clientOptions(cfg opcua.Config) ([]opcuagopcua.Option, error) {
+	anonymous := cfg.Auth == "" || cfg.Auth == opcua.AuthAnonymous
+	if cfg.Mode == "" && anonymous {
+		return []opcuagopcua.Option{
+			opcuagopcua.SecurityMode(uagopcua.MessageSecurityModeNone),
+		}, nil
+	}
+
+	mode := cfg.Mode
+	if mode == "" {
+		mode = securityNone
+	}
+	policy := cfg.Policy
+	if policy == "" {
+		policy = securityNone
+	}
+
+	endpoints, err := opcuagopcua.GetEndpoints(cfg.ServerURI)
+	if err != nil {
+		return nil, errors.Wrap(errFailedFetchEndpoint, err)
+	}
+
+	ep := opcuagopcua.SelectEndpoint(endpoints, policy, uagopcua.MessageSecurityModeFromString(mode))
+	if ep == nil {
+		return nil, errFailedFindEndpoint
+	}
+
+	opts := []opcuagopcua.Option{
+		opcuagopcua.SecurityPolicy(policy),
+		opcuagopcua.SecurityModeString(mode),
+	}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		opts = append(opts,
+			opcuagopcua.CertificateFile(cfg.CertFile),
+			opcuagopcua.PrivateKeyFile(cfg.KeyFile),
+		)
+	}
+
+	switch cfg.Auth {
+	case opcua.AuthUsername:
+		opts = append(opts,
+			opcuagopcua.AuthUsername(cfg.Username, cfg.Password),
+			opcuagopcua.SecurityFromEndpoint(ep, uagopcua.UserTokenTypeUserName),
+		)
+	case opcua.AuthCertificate:
+		cert, err := readCertificate(cfg.CertFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts,
+			opcuagopcua.AuthCertificate(cert),
+			opcuagopcua.SecurityFromEndpoint(ep, uagopcua.UserTokenTypeCertificate),
+		)
+	default:
+		opts = append(opts,
+			opcuagopcua.AuthAnonymous(),
+			opcuagopcua.SecurityFromEndpoint(ep, uagopcua.UserTokenTypeAnonymous),
+		)
+	}
+
+	return opts, nil
+}
+
+// readCertificate returns the DER encoded certificate of the PEM or DER
+// certificate file.
+func readCertificate(file string) ([]byte, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(errFailedReadCert, err)
+	}
+	if block, _ := pem.Decode(b); block != nil {
+		return block.Bytes, nil
+	}
+
+	return b, nil
+}
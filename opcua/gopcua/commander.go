@@ -0,0 +1,239 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package gopcua
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/absmach/magistrala/opcua"
+	"github.com/absmach/magistrala/pkg/errors"
+	opcuagopcua "github.com/gopcua/opcua"
+	uagopcua "github.com/gopcua/opcua/ua"
+)
+
+var (
+	errFailedWrite     = errors.New("failed to write node value")
+	errFailedCall      = errors.New("failed to call method")
+	errFailedDataType  = errors.New("failed to read node data type")
+	errUnsupportedType = errors.New("unsupported data type")
+	errInvalidValue    = errors.New("invalid value for data type")
+)
+
+// intRanges contains the ranges of the integer data types.
+var intRanges = map[string][2]float64{
+	"byte":   {0, math.MaxUint8},
+	"int8":   {math.MinInt8, math.MaxInt8},
+	"int16":  {math.MinInt16, math.MaxInt16},
+	"int32":  {math.MinInt32, math.MaxInt32},
+	"int64":  {math.MinInt64, math.MaxInt64},
+	"uint16": {0, math.MaxUint16},
+	"uint32": {0, math.MaxUint32},
+	"uint64": {0, math.MaxUint64},
+}
+
+var _ opcua.Commander = (*commander)(nil)
+
+type commander struct{}
+
+// NewCommander returns new OPC-UA commander instance.
+func NewCommander() opcua.Commander {
+	return commander{}
+}
+
+// Write writes the value to the node, converting it to the node data type.
+func (c commander) Write(ctx context.Context, cfg opcua.Config, value interface{}) error {
+	nodeID, err := uagopcua.ParseNodeID(cfg.NodeID)
+	if err != nil {
+		return errors.Wrap(errFailedParseNodeID, err)
+	}
+
+	oc, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer oc.Close()
+
+	typ, err := dataType(oc, nodeID)
+	if err != nil {
+		return err
+	}
+	v, err := variant(typ, value)
+	if err != nil {
+		return err
+	}
+
+	req := &uagopcua.WriteRequest{
+		NodesToWrite: []*uagopcua.WriteValue{
+			{
+				NodeID:      nodeID,
+				AttributeID: uagopcua.AttributeIDValue,
+				Value: &uagopcua.DataValue{
+					EncodingMask: uagopcua.DataValueValue,
+					Value:        v,
+				},
+			},
+		},
+	}
+	res, err := oc.Write(req)
+	if err != nil {
+		return errors.Wrap(errFailedWrite, err)
+	}
+	if len(res.Results) == 0 || res.Results[0] != uagopcua.StatusOK {
+		return errors.Wrap(errFailedWrite, resultStatus(res.Results))
+	}
+
+	return nil
+}
+
+// Call calls the method of the object node.
+func (c commander) Call(ctx context.Context, cfg opcua.Config, methodID string, args []opcua.Argument) ([]interface{}, error) {
+	objID, err := uagopcua.ParseNodeID(cfg.NodeID)
+	if err != nil {
+		return nil, errors.Wrap(errFailedParseNodeID, err)
+	}
+	methID, err := uagopcua.ParseNodeID(methodID)
+	if err != nil {
+		return nil, errors.Wrap(errFailedParseNodeID, err)
+	}
+
+	in := make([]*uagopcua.Variant, len(args))
+	for i, arg := range args {
+		typ := arg.Type
+		if typ == "" {
+			typ = inferType(arg.Value)
+		}
+		v, err := variant(typ, arg.Value)
+		if err != nil {
+			return nil, err
+		}
+		in[i] = v
+	}
+
+	oc, err := connect(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer oc.Close()
+
+	req := &uagopcua.CallMethodRequest{
+		ObjectID:       objID,
+		MethodID:       methID,
+		InputArguments: in,
+	}
+	res, err := oc.Call(req)
+	if err != nil {
+		return nil, errors.Wrap(errFailedCall, err)
+	}
+	if res.StatusCode != uagopcua.StatusOK {
+		return nil, errors.Wrap(errFailedCall, res.StatusCode)
+	}
+
+	out := make([]interface{}, len(res.OutputArguments))
+	for i, v := range res.OutputArguments {
+		out[i] = v.Value()
+	}
+
+	return out, nil
+}
+
+// dataType reads the name of the node data type.
+func dataType(oc *opcuagopcua.Client, nodeID *uagopcua.NodeID) (string, error) {
+	attrs, err := oc.Node(nodeID).Attributes(uagopcua.AttributeIDDataType)
+	if err != nil {
+		return "", errors.Wrap(errFailedDataType, err)
+	}
+	if attrs[0].Status != uagopcua.StatusOK {
+		return "", errors.Wrap(errFailedDataType, attrs[0].Status)
+	}
+
+	typ, ok := dataTypes[attrs[0].Value.NodeID().IntID()]
+	if !ok {
+		return "", errUnsupportedType
+	}
+
+	return typ, nil
+}
+
+func resultStatus(results []uagopcua.StatusCode) error {
+	if len(results) == 0 {
+		return errResponseStatus
+	}
+
+	return results[0]
+}
+
+// inferType returns the data type of the JSON value.
+func inferType(value interface{}) string {
+	switch value.(type) {
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	default:
+		return "float64"
+	}
+}
+
+// variant converts the JSON value to the variant of the data type.
+func variant(typ string, value interface{}) (*uagopcua.Variant, error) {
+	var v interface{}
+	switch typ {
+	case "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, errInvalidValue
+		}
+		v = b
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, errInvalidValue
+		}
+		v = s
+	case "float32", "float64":
+		f, ok := value.(float64)
+		if !ok {
+			return nil, errInvalidValue
+		}
+		v = f
+		if typ == "float32" {
+			v = float32(f)
+		}
+	default:
+		r, ok := intRanges[typ]
+		if !ok {
+			return nil, errors.Wrap(errUnsupportedType, fmt.Errorf("%s", typ))
+		}
+		f, ok := value.(float64)
+		if !ok || f != math.Trunc(f) || f < r[0] || f > r[1] {
+			return nil, errInvalidValue
+		}
+		v = integer(typ, f)
+	}
+
+	return uagopcua.NewVariant(v)
+}
+
+func integer(typ string, f float64) interface{} {
+	switch typ {
+	case "byte":
+		return uint8(f)
+	case "int8":
+		return int8(f)
+	case "int16":
+		return int16(f)
+	case "int32":
+		return int32(f)
+	case "int64":
+		return int64(f)
+	case "uint16":
+		return uint16(f)
+	case "uint32":
+		return uint32(f)
+	default:
+		return uint64(f)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package gopcua
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariant(t *testing.T) {
+	cases := []struct {
+		desc  string
+		typ   string
+		value interface{}
+		res   interface{}
+		err   error
+	}{
+		{
+			desc:  "convert bool",
+			typ:   "bool",
+			value: true,
+			res:   true,
+		},
+		{
+			desc:  "convert string",
+			typ:   "string",
+			value: "on",
+			res:   "on",
+		},
+		{
+			desc:  "convert float32",
+			typ:   "float32",
+			value: 1.5,
+			res:   float32(1.5),
+		},
+		{
+			desc:  "convert float64",
+			typ:   "float64",
+			value: 1.5,
+			res:   1.5,
+		},
+		{
+			desc:  "convert byte",
+			typ:   "byte",
+			value: 255.0,
+			res:   uint8(255),
+		},
+		{
+			desc:  "convert int16",
+			typ:   "int16",
+			value: -5.0,
+			res:   int16(-5),
+		},
+		{
+			desc:  "convert uint32",
+			typ:   "uint32",
+			value: 5.0,
+			res:   uint32(5),
+		},
+		{
+			desc:  "convert int64",
+			typ:   "int64",
+			value: 5.0,
+			res:   int64(5),
+		},
+		{
+			desc:  "convert fraction to integer",
+			typ:   "int32",
+			value: 1.5,
+			err:   errInvalidValue,
+		},
+		{
+			desc:  "convert out of range integer",
+			typ:   "int8",
+			value: 128.0,
+			err:   errInvalidValue,
+		},
+		{
+			desc:  "convert negative unsigned integer",
+			typ:   "uint16",
+			value: -1.0,
+			err:   errInvalidValue,
+		},
+		{
+			desc:  "convert string to bool",
+			typ:   "bool",
+			value: "true",
+			err:   errInvalidValue,
+		},
+		{
+			desc:  "convert bool to string",
+			typ:   "string",
+			value: true,
+			err:   errInvalidValue,
+		},
+		{
+			desc:  "convert string to number",
+			typ:   "float64",
+			value: "1.5",
+			err:   errInvalidValue,
+		},
+		{
+			desc:  "convert to unsupported type",
+			typ:   "datetime",
+			value: 1.0,
+			err:   errUnsupportedType,
+		},
+	}
+
+	for _, tc := range cases {
+		v, err := variant(tc.typ, tc.value)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+		assert.Equal(t, tc.res, v.Value(), fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.res, v.Value()))
+	}
+}
+
+func TestInferType(t *testing.T) {
+	cases := []struct {
+		desc  string
+		value interface{}
+		typ   string
+	}{
+		{
+			desc:  "infer bool",
+			value: false,
+			typ:   "bool",
+		},
+		{
+			desc:  "infer string",
+			value: "on",
+			typ:   "string",
+		},
+		{
+			desc:  "infer number",
+			value: 1.0,
+			typ:   "float64",
+		},
+	}
+
+	for _, tc := range cases {
+		typ := inferType(tc.value)
+		assert.Equal(t, tc.typ, typ, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.typ, typ))
+	}
+}
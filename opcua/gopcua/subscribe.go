@@ -14,6 +14,7 @@ import (
 	"github.com/absmach/magistrala/pkg/errors"
 	"github.com/absmach/magistrala/pkg/messaging"
 	opcuagopcua "github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/id"
 	uagopcua "github.com/gopcua/opcua/ua"
 )
 
@@ -35,8 +36,14 @@ var (
 	errFailedParseNodeID   = errors.New("failed to parse NodeID")
 	errFailedCreateReq     = errors.New("failed to create request")
 	errResponseStatus      = errors.New("response status not OK")
+
+	errFailedParseReconnect = errors.New("failed to parse reconnect interval")
+	errConnLost             = errors.New("connection lost")
 )
 
+// serverStateID is the NodeID of the OPC-UA Server state variable.
+var serverStateID = uagopcua.NewNumericNodeID(0, id.Server_ServerStatus_State)
+
 var _ opcua.Subscriber = (*client)(nil)
 
 type client struct {
@@ -69,64 +76,103 @@ func NewSubscriber(ctx context.Context, publisher messaging.Publisher, thingsRM,
 	}
 }
 
-// Subscribe subscribes to the OPC-UA Server.
+// Subscribe subscribes to the OPC-UA Server. The connection is checked at
+// the reconnect interval, and the subscription is restored once the server
+// is available again.
 func (c client) Subscribe(ctx context.Context, cfg opcua.Config) error {
-	opts := []opcuagopcua.Option{
-		opcuagopcua.SecurityMode(uagopcua.MessageSecurityModeNone),
+	reconnect, err := parseMillis(cfg.Reconnect)
+	if err != nil {
+		return errors.Wrap(errFailedParseReconnect, err)
+	}
+	interval, err := parseMillis(cfg.Interval)
+	if err != nil {
+		return errors.Wrap(errFailedParseInterval, err)
 	}
 
-	if cfg.Mode != "" {
-		endpoints, err := opcuagopcua.GetEndpoints(cfg.ServerURI)
-		if err != nil {
-			return errors.Wrap(errFailedFetchEndpoint, err)
-		}
+	node := fmt.Sprintf("%s.%s", cfg.ServerURI, cfg.NodeID)
+	return c.retry(ctx, node, reconnect, func() error {
+		return c.subscribe(ctx, cfg, interval, reconnect)
+	})
+}
 
-		ep := opcuagopcua.SelectEndpoint(endpoints, cfg.Policy, uagopcua.MessageSecurityModeFromString(cfg.Mode))
-		if ep == nil {
-			return errFailedFindEndpoint
+// retry runs the subscription until it ends for a reason other than the
+// unavailable OPC-UA Server, waiting for the reconnect interval between the
+// attempts.
+func (c client) retry(ctx context.Context, node string, reconnect time.Duration, subscribe func() error) error {
+	for {
+		err := subscribe()
+		if !reconnectable(err) {
+			return err
 		}
+		c.logger.Warn(fmt.Sprintf("Lost OPC-UA node %s, reconnecting in %s: %s", node, reconnect, err))
 
-		opts = []opcuagopcua.Option{
-			opcuagopcua.SecurityPolicy(cfg.Policy),
-			opcuagopcua.SecurityModeString(cfg.Mode),
-			opcuagopcua.CertificateFile(cfg.CertFile),
-			opcuagopcua.PrivateKeyFile(cfg.KeyFile),
-			opcuagopcua.AuthAnonymous(),
-			opcuagopcua.SecurityFromEndpoint(ep, uagopcua.UserTokenTypeAnonymous),
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.ctx.Done():
+			return nil
+		case <-time.After(reconnect):
 		}
 	}
+}
 
-	oc := opcuagopcua.NewClient(cfg.ServerURI, opts...)
-	if err := oc.Connect(ctx); err != nil {
-		return errors.Wrap(errFailedConn, err)
+// parseMillis parses the positive duration in milliseconds.
+func parseMillis(s string) (time.Duration, error) {
+	ms, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
 	}
-	defer oc.Close()
+	if ms <= 0 {
+		return 0, fmt.Errorf("duration must be positive, got %d", ms)
+	}
+
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// reconnectable returns true if the subscription failed because the OPC-UA
+// Server is not available.
+func reconnectable(err error) bool {
+	for _, e := range []error{errFailedConn, errFailedFetchEndpoint, errFailedSub, errConnLost} {
+		if errors.Contains(err, e) {
+			return true
+		}
+	}
+
+	return false
+}
 
-	i, err := strconv.Atoi(cfg.Interval)
+func (c client) subscribe(ctx context.Context, cfg opcua.Config, interval, check time.Duration) error {
+	oc, err := connect(ctx, cfg)
 	if err != nil {
-		return errors.Wrap(errFailedParseInterval, err)
+		return err
 	}
+	defer oc.Close()
 
 	sub, err := oc.Subscribe(&opcuagopcua.SubscriptionParameters{
-		Interval: time.Duration(i) * time.Millisecond,
+		Interval: interval,
 	})
 	if err != nil {
 		return errors.Wrap(errFailedSub, err)
 	}
 	defer func() {
-		if err = sub.Cancel(); err != nil {
+		if err := sub.Cancel(); err != nil {
 			c.logger.Error(fmt.Sprintf("subscription could not be cancelled: %s", err))
 		}
 	}()
 
-	if err := c.runHandler(ctx, sub, cfg.ServerURI, cfg.NodeID); err != nil {
+	err = c.runHandler(ctx, oc, sub, cfg.ServerURI, cfg.NodeID, check)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Contains(err, errConnLost):
+		return err
+	default:
 		c.logger.Warn(fmt.Sprintf("Unsubscribed from OPC-UA node %s.%s: %s", cfg.ServerURI, cfg.NodeID, err))
+		return nil
 	}
-
-	return nil
 }
 
-func (c client) runHandler(ctx context.Context, sub *opcuagopcua.Subscription, uri, node string) error {
+func (c client) runHandler(ctx context.Context, oc *opcuagopcua.Client, sub *opcuagopcua.Subscription, uri, node string, check time.Duration) error {
 	nodeID, err := uagopcua.ParseNodeID(node)
 	if err != nil {
 		return errors.Wrap(errFailedParseNodeID, err)
@@ -147,10 +193,19 @@ func (c client) runHandler(ctx context.Context, sub *opcuagopcua.Subscription, u
 
 	c.logger.Info(fmt.Sprintf("subscribed to server %s and node_id %s", uri, node))
 
+	ticker := time.NewTicker(check)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-c.ctx.Done():
 			return nil
+		case <-ticker.C:
+			// The subscription does not report the lost connection reliably,
+			// so the server state is read to detect it.
+			if _, err := oc.Node(serverStateID).Value(); err != nil {
+				return errors.Wrap(errConnLost, err)
+			}
 		case res := <-sub.Notifs:
 			if res.Error != nil {
 				c.logger.Error(res.Error.Error())
@@ -0,0 +1,109 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package gopcua
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	mglog "github.com/absmach/magistrala/logger"
+	"github.com/absmach/magistrala/opcua"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribeConfig(t *testing.T) {
+	sub := NewSubscriber(context.Background(), nil, nil, nil, nil, mglog.NewMock())
+
+	cases := []struct {
+		desc string
+		cfg  opcua.Config
+		err  error
+	}{
+		{
+			desc: "subscribe with invalid reconnect interval",
+			cfg:  opcua.Config{ServerURI: "opc.tcp://localhost:4840", NodeID: "ns=2;i=1", Reconnect: "invalid", Interval: "1000"},
+			err:  errFailedParseReconnect,
+		},
+		{
+			desc: "subscribe with zero reconnect interval",
+			cfg:  opcua.Config{ServerURI: "opc.tcp://localhost:4840", NodeID: "ns=2;i=1", Reconnect: "0", Interval: "1000"},
+			err:  errFailedParseReconnect,
+		},
+		{
+			desc: "subscribe with negative reconnect interval",
+			cfg:  opcua.Config{ServerURI: "opc.tcp://localhost:4840", NodeID: "ns=2;i=1", Reconnect: "-1", Interval: "1000"},
+			err:  errFailedParseReconnect,
+		},
+		{
+			desc: "subscribe with invalid subscription interval",
+			cfg:  opcua.Config{ServerURI: "opc.tcp://localhost:4840", NodeID: "ns=2;i=1", Reconnect: "1000", Interval: "invalid"},
+			err:  errFailedParseInterval,
+		},
+		{
+			desc: "subscribe with zero subscription interval",
+			cfg:  opcua.Config{ServerURI: "opc.tcp://localhost:4840", NodeID: "ns=2;i=1", Reconnect: "1000", Interval: "0"},
+			err:  errFailedParseInterval,
+		},
+	}
+
+	for _, tc := range cases {
+		err := sub.Subscribe(context.Background(), tc.cfg)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestRetry(t *testing.T) {
+	errUnsubscribed := errors.New("unsubscribed")
+
+	cases := []struct {
+		desc     string
+		errs     []error
+		cancel   bool
+		attempts int
+		err      error
+	}{
+		{
+			desc:     "retry successful subscription",
+			errs:     []error{nil},
+			attempts: 1,
+		},
+		{
+			desc:     "retry lost connection until subscribed",
+			errs:     []error{errConnLost, errFailedConn, errFailedFetchEndpoint, errFailedSub, nil},
+			attempts: 5,
+		},
+		{
+			desc:     "retry not reconnectable error",
+			errs:     []error{errConnLost, errUnsubscribed},
+			attempts: 2,
+			err:      errUnsubscribed,
+		},
+		{
+			desc:     "retry cancelled subscription",
+			errs:     []error{errConnLost},
+			cancel:   true,
+			attempts: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		ctx, cancel := context.WithCancel(context.Background())
+		c := client{ctx: context.Background(), logger: mglog.NewMock()}
+		attempts := 0
+		err := c.retry(ctx, "node", time.Millisecond, func() error {
+			err := tc.errs[attempts]
+			attempts++
+			if tc.cancel {
+				cancel()
+			}
+			return err
+		})
+		cancel()
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.attempts, attempts, fmt.Sprintf("%s: expected %d attempts got %d\n", tc.desc, tc.attempts, attempts))
+	}
+}
@@ -6,6 +6,8 @@ package opcua
 import "context"
 
 // RouteMapRepository store route-map between the OPC-UA Server and Magistrala.
+//
+//go:generate mockery --name RouteMapRepository --output=./mocks --filename routemap.go --quiet --note "Copyright (c) Abstract Machines"
 type RouteMapRepository interface {
 	// Save stores/routes pair OPC-UA Server & Magistrala.
 	Save(context.Context, string, string) error
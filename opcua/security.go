@@ -0,0 +1,132 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package opcua
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/absmach/magistrala/pkg/errors"
+)
+
+const (
+	// AuthAnonymous authenticates the adapter as an anonymous user.
+	AuthAnonymous = "anonymous"
+
+	// AuthUsername authenticates the adapter with the username and password.
+	AuthUsername = "username"
+
+	// AuthCertificate authenticates the adapter with the X.509 certificate
+	// and private key.
+	AuthCertificate = "certificate"
+)
+
+var (
+	// ErrInvalidSecurity indicates invalid channel security configuration.
+	ErrInvalidSecurity = errors.New("invalid security configuration")
+
+	// ErrNotFoundSecurity indicates a non-existent channel security
+	// configuration.
+	ErrNotFoundSecurity = errors.New("security configuration not found")
+
+	errFailedReadPassword = errors.New("failed to read password file")
+)
+
+// Security is the OPC-UA Server connection security of a channel. Empty
+// fields fall back to the adapter configuration. The password is referenced
+// by the path of the file containing it, the same as the certificate and key,
+// so that it is neither stored in the channel metadata nor in the route-map
+// database.
+type Security struct {
+	Policy       string `json:"policy,omitempty"`
+	Mode         string `json:"mode,omitempty"`
+	Auth         string `json:"auth,omitempty"`
+	CertFile     string `json:"cert_file,omitempty"`
+	KeyFile      string `json:"key_file,omitempty"`
+	Username     string `json:"username,omitempty"`
+	PasswordFile string `json:"password_file,omitempty"`
+}
+
+// Validate checks that the security configuration is complete.
+func (s Security) Validate() error {
+	switch s.Auth {
+	case "", AuthAnonymous:
+	case AuthUsername:
+		if s.Username == "" {
+			return errors.Wrap(ErrInvalidSecurity, errors.New("missing username"))
+		}
+	case AuthCertificate:
+		if s.CertFile == "" || s.KeyFile == "" {
+			return errors.Wrap(ErrInvalidSecurity, errors.New("missing certificate or key file"))
+		}
+	default:
+		return errors.Wrap(ErrInvalidSecurity, errors.New("unknown authentication "+s.Auth))
+	}
+
+	return nil
+}
+
+// Apply returns the config with the security fields which are set.
+func (s Security) Apply(cfg Config) Config {
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	set(&cfg.Policy, s.Policy)
+	set(&cfg.Mode, s.Mode)
+	set(&cfg.Auth, s.Auth)
+	set(&cfg.CertFile, s.CertFile)
+	set(&cfg.KeyFile, s.KeyFile)
+	set(&cfg.Username, s.Username)
+
+	return cfg
+}
+
+// password reads the password from the password file. Trailing new line is
+// not considered to be part of the password.
+func (s Security) password() (string, error) {
+	b, err := os.ReadFile(s.PasswordFile)
+	if err != nil {
+		return "", errors.Wrap(errFailedReadPassword, err)
+	}
+
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+// SecurityRepository stores the OPC-UA Server security of the channels.
+//
+//go:generate mockery --name SecurityRepository --output=./mocks --filename security.go --quiet --note "Copyright (c) Abstract Machines"
+type SecurityRepository interface {
+	// Save stores the security configuration of the channel.
+	Save(ctx context.Context, chanID string, sec Security) error
+
+	// Get returns the security configuration of the channel.
+	Get(ctx context.Context, chanID string) (Security, error)
+
+	// Remove removes the security configuration of the channel.
+	Remove(ctx context.Context, chanID string) error
+}
+
+// ServerConfig returns the config of the OPC-UA Server of the channel, using
+// the channel security configuration, if any.
+func ServerConfig(ctx context.Context, cfg Config, security SecurityRepository, chanID, serverURI, nodeID string) (Config, error) {
+	sec, err := security.Get(ctx, chanID)
+	switch {
+	case err == nil:
+		cfg = sec.Apply(cfg)
+		if sec.PasswordFile != "" {
+			if cfg.Password, err = sec.password(); err != nil {
+				return Config{}, err
+			}
+		}
+	case !errors.Contains(err, ErrNotFoundSecurity):
+		return Config{}, err
+	}
+	cfg.ServerURI = serverURI
+	cfg.NodeID = nodeID
+
+	return cfg, nil
+}
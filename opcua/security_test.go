@@ -0,0 +1,110 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package opcua_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/absmach/magistrala/opcua"
+	"github.com/absmach/magistrala/opcua/mocks"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityValidate(t *testing.T) {
+	cases := []struct {
+		desc string
+		sec  opcua.Security
+		err  error
+	}{
+		{
+			desc: "validate empty security",
+			sec:  opcua.Security{},
+		},
+		{
+			desc: "validate anonymous security",
+			sec:  opcua.Security{Policy: "Basic256Sha256", Mode: "SignAndEncrypt", Auth: opcua.AuthAnonymous},
+		},
+		{
+			desc: "validate username security",
+			sec:  opcua.Security{Auth: opcua.AuthUsername, Username: "operator", PasswordFile: "/secrets/password"},
+		},
+		{
+			desc: "validate username security without username",
+			sec:  opcua.Security{Auth: opcua.AuthUsername, PasswordFile: "/secrets/password"},
+			err:  opcua.ErrInvalidSecurity,
+		},
+		{
+			desc: "validate certificate security",
+			sec:  opcua.Security{Auth: opcua.AuthCertificate, CertFile: "/certs/client.pem", KeyFile: "/certs/client.key"},
+		},
+		{
+			desc: "validate certificate security without key",
+			sec:  opcua.Security{Auth: opcua.AuthCertificate, CertFile: "/certs/client.pem"},
+			err:  opcua.ErrInvalidSecurity,
+		},
+		{
+			desc: "validate unknown authentication",
+			sec:  opcua.Security{Auth: "token"},
+			err:  opcua.ErrInvalidSecurity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := tc.sec.Validate()
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestServerConfig(t *testing.T) {
+	passFile := filepath.Join(t.TempDir(), "password")
+	err := os.WriteFile(passFile, []byte("secret\n"), 0o600)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cfg := opcua.Config{Interval: "1000", Reconnect: "5000", Auth: opcua.AuthAnonymous, Username: "adapter", Password: "default"}
+	errRepo := errors.New("repository error")
+	errReadPassword := errors.New("failed to read password file")
+
+	cases := []struct {
+		desc   string
+		sec    opcua.Security
+		secErr error
+		cfg    opcua.Config
+		err    error
+	}{
+		{
+			desc:   "server config without channel security",
+			secErr: opcua.ErrNotFoundSecurity,
+			cfg:    opcua.Config{ServerURI: serverURI, NodeID: nodeID, Interval: "1000", Reconnect: "5000", Auth: opcua.AuthAnonymous, Username: "adapter", Password: "default"},
+		},
+		{
+			desc: "server config with channel security",
+			sec:  opcua.Security{Mode: "Sign", Auth: opcua.AuthUsername, Username: "operator", PasswordFile: passFile},
+			cfg:  opcua.Config{ServerURI: serverURI, NodeID: nodeID, Interval: "1000", Reconnect: "5000", Mode: "Sign", Auth: opcua.AuthUsername, Username: "operator", Password: "secret"},
+		},
+		{
+			desc: "server config with missing password file",
+			sec:  opcua.Security{Auth: opcua.AuthUsername, Username: "operator", PasswordFile: filepath.Join(t.TempDir(), "missing")},
+			err:  errReadPassword,
+		},
+		{
+			desc:   "server config with failed security retrieval",
+			secErr: errRepo,
+			err:    errRepo,
+		},
+	}
+
+	for _, tc := range cases {
+		security := mocks.NewSecurityRepository(t)
+		security.On("Get", context.Background(), chanID).Return(tc.sec, tc.secErr)
+
+		res, err := opcua.ServerConfig(context.Background(), cfg, security, chanID, serverURI, nodeID)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.cfg, res, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.cfg, res))
+	}
+}
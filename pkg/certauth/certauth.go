@@ -0,0 +1,282 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package certauth
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/absmach/magistrala/pkg/errors"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	ocspReqType       = "application/ocsp-request"
+	certReqType       = "application/pkix-cert"
+	identifyPath      = "/certs/identify"
+	maxOCSPResBytes   = 1 << 16
+	errStatusMessage  = "unexpected OCSP responder status %d"
+	errIdentifyStatus = "unexpected certs service status %d"
+)
+
+var (
+	// ErrMissingCert indicates that the client did not present the certificate.
+	ErrMissingCert = errors.New("missing client certificate")
+
+	// ErrMissingCA indicates missing CA certificate.
+	ErrMissingCA = errors.New("missing CA certificate")
+
+	// ErrInvalidCert indicates the certificate not issued by the CA.
+	ErrInvalidCert = errors.New("invalid client certificate")
+
+	// ErrExpiredCert indicates expired or not yet valid certificate.
+	ErrExpiredCert = errors.New("client certificate is expired")
+
+	// ErrRevokedCert indicates revoked certificate.
+	ErrRevokedCert = errors.New("client certificate is revoked")
+
+	// ErrRevocationStatus indicates failure to check the certificate
+	// revocation status.
+	ErrRevocationStatus = errors.New("failed to check client certificate revocation status")
+
+	// ErrUnknownThing indicates the certificate not issued to any thing.
+	ErrUnknownThing = errors.New("client certificate thing is unknown")
+
+	// ErrIdentifyThing indicates failure to identify the thing of the
+	// certificate.
+	ErrIdentifyThing = errors.New("failed to identify client certificate thing")
+
+	errMissingOCSPURL = errors.New("missing OCSP URL")
+	errUnknownStatus  = errors.New("unknown certificate status")
+)
+
+// Config is the client certificate authentication configuration.
+type Config struct {
+	CAPath         string        `env:"CA_PATH"         envDefault:""`
+	URL            string        `env:"URL"             envDefault:"http://localhost:9019"`
+	OCSPURL        string        `env:"OCSP_URL"        envDefault:""`
+	Timeout        time.Duration `env:"TIMEOUT"         envDefault:"1s"`
+	CertHeader     string        `env:"CERT_HEADER"     envDefault:""`
+	TrustedProxies string        `env:"TRUSTED_PROXIES" envDefault:""`
+}
+
+// Authenticator authenticates things using the client certificates.
+//
+//go:generate mockery --name Authenticator --output=./mocks --filename authenticator.go --quiet --note "Copyright (c) Abstract Machines"
+type Authenticator interface {
+	// Authenticate verifies the client certificate and returns the ID of the
+	// thing the certificate was issued to.
+	Authenticate(ctx context.Context, cert *x509.Certificate) (string, error)
+}
+
+var _ Authenticator = (*authenticator)(nil)
+
+type status struct {
+	revoked    bool
+	thingID    string
+	nextUpdate time.Time
+}
+
+type authenticator struct {
+	roots   *x509.CertPool
+	url     string
+	ocspURL string
+	client  *http.Client
+
+	mu     sync.Mutex
+	status map[string]status
+}
+
+// Setup loads the CA certificates and creates new Authenticator.
+//
+// For example:
+//
+//	certAuth, err := certauth.Setup(certauth.Config{})
+func Setup(cfg Config) (Authenticator, error) {
+	if cfg.CAPath == "" {
+		return nil, ErrMissingCA
+	}
+	b, err := os.ReadFile(cfg.CAPath)
+	if err != nil {
+		return nil, errors.Wrap(ErrMissingCA, err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(b) {
+		return nil, ErrMissingCA
+	}
+
+	return New(roots, cfg.URL, cfg.OCSPURL, cfg.Timeout), nil
+}
+
+// New returns the Authenticator which accepts the certificates issued by the
+// roots CA certificates. The revocation status is checked using the OCSP URL,
+// or the OCSP server of the certificate if the URL is empty, and the thing is
+// identified by the certs service of the URL. Both are cached until the OCSP
+// response next update.
+func New(roots *x509.CertPool, url, ocspURL string, timeout time.Duration) Authenticator {
+	return &authenticator{
+		roots:   roots,
+		url:     url,
+		ocspURL: ocspURL,
+		client:  &http.Client{Timeout: timeout},
+		status:  make(map[string]status),
+	}
+}
+
+func (a *authenticator) Authenticate(ctx context.Context, cert *x509.Certificate) (string, error) {
+	if cert == nil || len(cert.Raw) == 0 {
+		return "", ErrMissingCert
+	}
+
+	now := time.Now()
+	if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return "", ErrExpiredCert
+	}
+
+	chains, err := cert.Verify(x509.VerifyOptions{
+		Roots:       a.roots,
+		CurrentTime: now,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return "", errors.Wrap(ErrInvalidCert, err)
+	}
+
+	// The certificate is self-signed if the chain contains only the certificate.
+	issuer := chains[0][0]
+	if len(chains[0]) > 1 {
+		issuer = chains[0][1]
+	}
+	st, err := a.certStatus(ctx, cert, issuer)
+	if err != nil {
+		return "", err
+	}
+	if st.revoked {
+		return "", ErrRevokedCert
+	}
+
+	return st.thingID, nil
+}
+
+func (a *authenticator) certStatus(ctx context.Context, cert, issuer *x509.Certificate) (status, error) {
+	serial := cert.SerialNumber.String()
+
+	a.mu.Lock()
+	st, ok := a.status[serial]
+	a.mu.Unlock()
+	if ok && time.Now().Before(st.nextUpdate) {
+		return st, nil
+	}
+
+	res, err := a.ocsp(ctx, cert, issuer)
+	if err != nil {
+		return status{}, errors.Wrap(ErrRevocationStatus, err)
+	}
+
+	switch res.Status {
+	case ocsp.Good:
+		// The certificate common name is not used, since it can be set by
+		// anyone able to sign the certificate request, so the thing is
+		// identified by the certs service which issued the certificate.
+		thingID, err := a.identify(ctx, cert)
+		if err != nil {
+			return status{}, err
+		}
+		st = status{revoked: false, thingID: thingID, nextUpdate: res.NextUpdate}
+	case ocsp.Revoked:
+		st = status{revoked: true, nextUpdate: res.NextUpdate}
+	default:
+		return status{}, errors.Wrap(ErrRevocationStatus, errUnknownStatus)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now()
+	for s, cached := range a.status {
+		if !now.Before(cached.nextUpdate) {
+			delete(a.status, s)
+		}
+	}
+	a.status[serial] = st
+
+	return st, nil
+}
+
+func (a *authenticator) identify(ctx context.Context, cert *x509.Certificate) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url+identifyPath, bytes.NewReader(cert.Raw))
+	if err != nil {
+		return "", errors.Wrap(ErrIdentifyThing, err)
+	}
+	req.Header.Set("Content-Type", certReqType)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", errors.Wrap(ErrIdentifyThing, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return "", ErrUnknownThing
+	default:
+		return "", errors.Wrap(ErrIdentifyThing, fmt.Errorf(errIdentifyStatus, resp.StatusCode))
+	}
+
+	var res struct {
+		ThingID string `json:"thing_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return "", errors.Wrap(ErrIdentifyThing, err)
+	}
+	if res.ThingID == "" {
+		return "", ErrUnknownThing
+	}
+
+	return res.ThingID, nil
+}
+
+func (a *authenticator) ocsp(ctx context.Context, cert, issuer *x509.Certificate) (*ocsp.Response, error) {
+	url := a.ocspURL
+	if url == "" && len(cert.OCSPServer) > 0 {
+		url = cert.OCSPServer[0]
+	}
+	if url == "" {
+		return nil, errMissingOCSPURL
+	}
+
+	body, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", ocspReqType)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(errStatusMessage, resp.StatusCode)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxOCSPResBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	return ocsp.ParseResponseForCert(b, cert, issuer)
+}
@@ -0,0 +1,252 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package certauth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/certs"
+	"github.com/absmach/magistrala/certs/mocks"
+	"github.com/absmach/magistrala/certs/pki"
+	"github.com/absmach/magistrala/pkg/certauth"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	caPath    = "../../docker/ssl/certs/ca.crt"
+	caKeyPath = "../../docker/ssl/certs/ca.key"
+	thingKey  = "thing-key"
+	thingID   = "c30b8842-507c-4bcd-973c-74008cef3be5"
+	timeout   = time.Second
+)
+
+// newCertsServer returns the certs service stub, which checks the revocation
+// status using the agent and identifies the things of the given certificates.
+func newCertsServer(t *testing.T, agent pki.Agent, things map[string]string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ocsp", func(w http.ResponseWriter, r *http.Request) {
+		req, err := io.ReadAll(r.Body)
+		require.Nil(t, err, fmt.Sprintf("unexpected OCSP request reading error: %s\n", err))
+		res, err := agent.OCSP(req)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(res)
+	})
+	mux.HandleFunc("/certs/identify", func(w http.ResponseWriter, r *http.Request) {
+		der, err := io.ReadAll(r.Body)
+		require.Nil(t, err, fmt.Sprintf("unexpected identify request reading error: %s\n", err))
+		id, ok := things[string(der)]
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"thing_id":"%s"}`, id)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func issue(t *testing.T, agent pki.Agent) *x509.Certificate {
+	c, err := agent.IssueCert(thingKey, "1h")
+	require.Nil(t, err, fmt.Sprintf("unexpected cert issuing error: %s\n", err))
+	cert, err := certs.ReadCert([]byte(c.ClientCert))
+	require.Nil(t, err, fmt.Sprintf("unexpected cert parsing error: %s\n", err))
+
+	return cert
+}
+
+func sign(t *testing.T, tmpl, parent *x509.Certificate, signer interface{}) *x509.Certificate {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err, fmt.Sprintf("unexpected key generation error: %s\n", err))
+	if signer == nil {
+		parent, signer = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, signer)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert creation error: %s\n", err))
+	cert, err := x509.ParseCertificate(der)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert parsing error: %s\n", err))
+
+	return cert
+}
+
+func TestAuthenticate(t *testing.T) {
+	tlsCert, caCert, err := certs.LoadCertificates(caPath, caKeyPath)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert loading error: %s\n", err))
+	agent, err := pki.NewLocalAgent(tlsCert, caCert, mocks.NewPKIRepository(), pki.URLs{}, timeout)
+	require.Nil(t, err, fmt.Sprintf("unexpected local agent error: %s\n", err))
+
+	valid := issue(t, agent)
+	unknown := issue(t, agent)
+	revoked := issue(t, agent)
+	_, err = agent.Revoke(pki.FormatSerial(revoked.SerialNumber))
+	require.Nil(t, err, fmt.Sprintf("unexpected cert revocation error: %s\n", err))
+
+	things := map[string]string{
+		string(valid.Raw):   thingID,
+		string(revoked.Raw): thingID,
+	}
+	certsServer := newCertsServer(t, agent, things)
+	defer certsServer.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	auth := certauth.New(roots, certsServer.URL, certsServer.URL+"/ocsp", timeout)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: thingKey},
+		NotBefore:    time.Now().Add(-2 * time.Hour),
+		NotAfter:     time.Now().Add(-time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	expired := sign(t, tmpl, caCert, tlsCert.PrivateKey)
+	tmpl.NotAfter = time.Now().Add(time.Hour)
+	selfSigned := sign(t, tmpl, nil, nil)
+
+	cases := []struct {
+		desc string
+		cert *x509.Certificate
+		id   string
+		err  error
+	}{
+		{
+			desc: "authenticate with valid cert",
+			cert: valid,
+			id:   thingID,
+			err:  nil,
+		},
+		{
+			desc: "authenticate with cached valid cert status",
+			cert: valid,
+			id:   thingID,
+			err:  nil,
+		},
+		{
+			desc: "authenticate with cert of unknown thing",
+			cert: unknown,
+			err:  certauth.ErrUnknownThing,
+		},
+		{
+			desc: "authenticate with revoked cert",
+			cert: revoked,
+			err:  certauth.ErrRevokedCert,
+		},
+		{
+			desc: "authenticate with expired cert",
+			cert: expired,
+			err:  certauth.ErrExpiredCert,
+		},
+		{
+			desc: "authenticate with self-signed cert",
+			cert: selfSigned,
+			err:  certauth.ErrInvalidCert,
+		},
+		{
+			desc: "authenticate without cert",
+			cert: &x509.Certificate{},
+			err:  certauth.ErrMissingCert,
+		},
+	}
+
+	for _, tc := range cases {
+		id, err := auth.Authenticate(context.Background(), tc.cert)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.id, id, fmt.Sprintf("%s: expected thing ID %s got %s\n", tc.desc, tc.id, id))
+	}
+}
+
+func TestAuthenticateRevocationStatus(t *testing.T) {
+	tlsCert, caCert, err := certs.LoadCertificates(caPath, caKeyPath)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert loading error: %s\n", err))
+	agent, err := pki.NewLocalAgent(tlsCert, caCert, mocks.NewPKIRepository(), pki.URLs{}, timeout)
+	require.Nil(t, err, fmt.Sprintf("unexpected local agent error: %s\n", err))
+	cert := issue(t, agent)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+
+	cases := []struct {
+		desc    string
+		ocspURL string
+		err     error
+	}{
+		{
+			desc:    "authenticate with failing OCSP responder",
+			ocspURL: failing.URL,
+			err:     certauth.ErrRevocationStatus,
+		},
+		{
+			desc:    "authenticate without OCSP URL",
+			ocspURL: "",
+			err:     certauth.ErrRevocationStatus,
+		},
+	}
+
+	for _, tc := range cases {
+		auth := certauth.New(roots, failing.URL, tc.ocspURL, timeout)
+		_, err := auth.Authenticate(context.Background(), cert)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestAuthenticateIdentifyThing(t *testing.T) {
+	tlsCert, caCert, err := certs.LoadCertificates(caPath, caKeyPath)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert loading error: %s\n", err))
+	agent, err := pki.NewLocalAgent(tlsCert, caCert, mocks.NewPKIRepository(), pki.URLs{}, timeout)
+	require.Nil(t, err, fmt.Sprintf("unexpected local agent error: %s\n", err))
+	cert := issue(t, agent)
+
+	certsServer := newCertsServer(t, agent, map[string]string{})
+	defer certsServer.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+
+	cases := []struct {
+		desc string
+		url  string
+		err  error
+	}{
+		{
+			desc: "authenticate with failing certs service",
+			url:  failing.URL,
+			err:  certauth.ErrIdentifyThing,
+		},
+		{
+			desc: "authenticate with unreachable certs service",
+			url:  "http://localhost:0",
+			err:  certauth.ErrIdentifyThing,
+		},
+	}
+
+	for _, tc := range cases {
+		auth := certauth.New(roots, tc.url, certsServer.URL+"/ocsp", timeout)
+		_, err := auth.Authenticate(context.Background(), cert)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
@@ -0,0 +1,7 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package certauth authenticates things using the client certificates
+// issued by the certs service, so the protocol adapters authorize them
+// without the thing key.
+package certauth
@@ -0,0 +1,171 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package certauth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/absmach/magistrala/pkg/errors"
+)
+
+// CertAuthorization is the Authorization header value of the requests
+// presenting only the client certificate.
+const CertAuthorization = "Cert"
+
+const authorizationHeader = "Authorization"
+
+var (
+	// ErrServerCert indicates failure to load the server certificate.
+	ErrServerCert = errors.New("failed to load server certificate")
+
+	// ErrMissingTrustedProxies indicates the certificate header configured
+	// without the trusted proxies.
+	ErrMissingTrustedProxies = errors.New("missing trusted proxies of the certificate header")
+
+	// ErrInvalidTrustedProxy indicates malformed trusted proxy CIDR.
+	ErrInvalidTrustedProxy = errors.New("invalid trusted proxy CIDR")
+)
+
+type certKey struct{}
+
+// TLSConfig returns the server TLS configuration which verifies the client
+// certificates against the CA certificates of the CA path. Clients without
+// the certificate are accepted as well, so the things can keep using the
+// thing key.
+func TLSConfig(caPath, certFile, keyFile string) (*tls.Config, error) {
+	b, err := os.ReadFile(caPath)
+	if err != nil {
+		return nil, errors.Wrap(ErrMissingCA, err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(b) {
+		return nil, ErrMissingCA
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, errors.Wrap(ErrServerCert, err)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+		ClientCAs:    roots,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// NewContext returns the context with the client certificate.
+func NewContext(ctx context.Context, cert *x509.Certificate) context.Context {
+	return context.WithValue(ctx, certKey{}, cert)
+}
+
+// FromContext returns the client certificate of the context.
+func FromContext(ctx context.Context) (*x509.Certificate, bool) {
+	cert, ok := ctx.Value(certKey{}).(*x509.Certificate)
+	return cert, ok && cert != nil
+}
+
+// ClientCert returns the session client certificate, or the client
+// certificate of the context if the session has none. It returns nil if
+// neither is present.
+func ClientCert(ctx context.Context, sessionCert x509.Certificate) *x509.Certificate {
+	if len(sessionCert.Raw) > 0 {
+		return &sessionCert
+	}
+	if cert, ok := FromContext(ctx); ok {
+		return cert
+	}
+
+	return nil
+}
+
+// Handler stores the client certificate of the request in the request context.
+// The certificate is taken from the verified TLS connection or, if the
+// configuration header is set, from the header the TLS terminating reverse
+// proxy forwards the URL escaped PEM certificate in. Since the certificates
+// are public, the header is accepted only from the trusted proxies addresses.
+// The certificate is verified by the Authenticator in both cases.
+//
+// The requests presenting only the certificate get the Authorization header
+// set to CertAuthorization, since the protocol proxies reject the requests
+// without credentials before the session handler authenticates the
+// certificate.
+func Handler(h http.Handler, cfg Config) (http.Handler, error) {
+	if cfg.CertHeader != "" && cfg.TrustedProxies == "" {
+		return nil, ErrMissingTrustedProxies
+	}
+	var proxies []*net.IPNet
+	for _, cidr := range strings.Split(cfg.TrustedProxies, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		_, proxy, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidTrustedProxy, err)
+		}
+		proxies = append(proxies, proxy)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cert := requestCert(r, cfg.CertHeader, proxies); cert != nil {
+			r = r.Clone(NewContext(r.Context(), cert))
+			if r.Header.Get(authorizationHeader) == "" {
+				r.Header.Set(authorizationHeader, CertAuthorization)
+			}
+		}
+		h.ServeHTTP(w, r)
+	}), nil
+}
+
+func requestCert(r *http.Request, header string, proxies []*net.IPNet) *x509.Certificate {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return r.TLS.VerifiedChains[0][0]
+	}
+	if header == "" || !trusted(r.RemoteAddr, proxies) {
+		return nil
+	}
+
+	v, err := url.QueryUnescape(r.Header.Get(header))
+	if err != nil || v == "" {
+		return nil
+	}
+	block, _ := pem.Decode([]byte(v))
+	if block == nil {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil
+	}
+
+	return cert
+}
+
+func trusted(addr string, proxies []*net.IPNet) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range proxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
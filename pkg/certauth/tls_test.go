@@ -0,0 +1,176 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package certauth_test
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/absmach/magistrala/certs"
+	"github.com/absmach/magistrala/pkg/certauth"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const certHeader = "X-SSL-Client-Cert"
+
+func TestTLSConfig(t *testing.T) {
+	cases := []struct {
+		desc     string
+		caPath   string
+		certPath string
+		keyPath  string
+		err      error
+	}{
+		{
+			desc:     "load TLS config",
+			caPath:   caPath,
+			certPath: caPath,
+			keyPath:  caKeyPath,
+		},
+		{
+			desc:     "load TLS config with missing CA",
+			caPath:   "",
+			certPath: caPath,
+			keyPath:  caKeyPath,
+			err:      certauth.ErrMissingCA,
+		},
+		{
+			desc:     "load TLS config with invalid server key",
+			caPath:   caPath,
+			certPath: caPath,
+			keyPath:  caPath,
+			err:      certauth.ErrServerCert,
+		},
+	}
+
+	for _, tc := range cases {
+		cfg, err := certauth.TLSConfig(tc.caPath, tc.certPath, tc.keyPath)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tls.VerifyClientCertIfGiven, cfg.ClientAuth, fmt.Sprintf("%s: expected client certificates to be verified", tc.desc))
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	tlsCert, _, err := certs.LoadCertificates(caPath, caKeyPath)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert loading error: %s\n", err))
+	cert, err := x509.ParseCertificate(tlsCert.Certificate[0])
+	require.Nil(t, err, fmt.Sprintf("unexpected cert parsing error: %s\n", err))
+	escaped := url.QueryEscape(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})))
+	proxyCfg := certauth.Config{CertHeader: certHeader, TrustedProxies: "10.0.0.0/8, 192.0.2.1/32"}
+
+	cases := []struct {
+		desc       string
+		cfg        certauth.Config
+		tls        *tls.ConnectionState
+		remoteAddr string
+		value      string
+		auth       string
+		cert       *x509.Certificate
+		wantAuth   string
+		err        error
+	}{
+		{
+			desc:     "request with verified TLS client certificate",
+			tls:      &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+			cert:     cert,
+			wantAuth: certauth.CertAuthorization,
+		},
+		{
+			desc:     "request with verified TLS client certificate and thing key",
+			tls:      &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+			auth:     thingKey,
+			cert:     cert,
+			wantAuth: thingKey,
+		},
+		{
+			desc: "request with unverified TLS client certificate",
+			tls:  &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+		},
+		{
+			desc:       "request with forwarded client certificate from trusted proxy",
+			cfg:        proxyCfg,
+			remoteAddr: "10.1.2.3:4321",
+			value:      escaped,
+			cert:       cert,
+			wantAuth:   certauth.CertAuthorization,
+		},
+		{
+			desc:       "request with forwarded client certificate from untrusted address",
+			cfg:        proxyCfg,
+			remoteAddr: "203.0.113.7:4321",
+			value:      escaped,
+		},
+		{
+			desc:  "request with forwarded client certificate and no header configured",
+			value: escaped,
+		},
+		{
+			desc:       "request with invalid forwarded client certificate",
+			cfg:        proxyCfg,
+			remoteAddr: "10.1.2.3:4321",
+			value:      "invalid",
+		},
+		{
+			desc:       "request without client certificate",
+			cfg:        proxyCfg,
+			remoteAddr: "10.1.2.3:4321",
+		},
+		{
+			desc: "handler with certificate header and no trusted proxies",
+			cfg:  certauth.Config{CertHeader: certHeader},
+			err:  certauth.ErrMissingTrustedProxies,
+		},
+		{
+			desc: "handler with invalid trusted proxy",
+			cfg:  certauth.Config{CertHeader: certHeader, TrustedProxies: "10.0.0.0"},
+			err:  certauth.ErrInvalidTrustedProxy,
+		},
+	}
+
+	for _, tc := range cases {
+		var got *x509.Certificate
+		var gotAuth string
+		h, err := certauth.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, _ = certauth.FromContext(r.Context())
+			gotAuth = r.Header.Get("Authorization")
+		}), tc.cfg)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.TLS = tc.tls
+		if tc.remoteAddr != "" {
+			req.RemoteAddr = tc.remoteAddr
+		}
+		if tc.value != "" {
+			req.Header.Set(certHeader, tc.value)
+		}
+		if tc.auth != "" {
+			req.Header.Set("Authorization", tc.auth)
+		}
+		h.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, tc.wantAuth, gotAuth, fmt.Sprintf("%s: expected authorization %q got %q", tc.desc, tc.wantAuth, gotAuth))
+		switch tc.cert {
+		case nil:
+			assert.Nil(t, got, fmt.Sprintf("%s: expected no client certificate", tc.desc))
+		default:
+			require.NotNil(t, got, fmt.Sprintf("%s: expected client certificate", tc.desc))
+			assert.True(t, bytes.Equal(tc.cert.Raw, got.Raw), fmt.Sprintf("%s: expected client certificate %s", tc.desc, tc.cert.Subject))
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package conditions
+
+// Comparison operators.
+const (
+	GreaterThan  = "gt"
+	GreaterEqual = "ge"
+	LowerThan    = "lt"
+	LowerEqual   = "le"
+	Equal        = "eq"
+	NotEqual     = "ne"
+)
+
+// FieldSep separates the names of the nested JSON payload fields, e.g.
+// "sensor/temp".
+const FieldSep = "/"
+
+// Valid reports whether the operator is a comparison operator.
+func Valid(op string) bool {
+	switch op {
+	case GreaterThan, GreaterEqual, LowerThan, LowerEqual, Equal, NotEqual:
+		return true
+	default:
+		return false
+	}
+}
+
+// Compare reports whether the value satisfies the operator and the
+// threshold. Unknown operators are never satisfied.
+func Compare(op string, value, threshold float64) bool {
+	switch op {
+	case GreaterThan:
+		return value > threshold
+	case GreaterEqual:
+		return value >= threshold
+	case LowerThan:
+		return value < threshold
+	case LowerEqual:
+		return value <= threshold
+	case Equal:
+		return value == threshold
+	case NotEqual:
+		return value != threshold
+	default:
+		return false
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package conditions_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/absmach/magistrala/pkg/conditions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	cases := []struct {
+		desc      string
+		op        string
+		value     float64
+		threshold float64
+		valid     bool
+		res       bool
+	}{
+		{
+			desc:      "greater than",
+			op:        conditions.GreaterThan,
+			value:     2,
+			threshold: 1,
+			valid:     true,
+			res:       true,
+		},
+		{
+			desc:      "greater than equal value",
+			op:        conditions.GreaterThan,
+			value:     1,
+			threshold: 1,
+			valid:     true,
+			res:       false,
+		},
+		{
+			desc:      "greater or equal",
+			op:        conditions.GreaterEqual,
+			value:     1,
+			threshold: 1,
+			valid:     true,
+			res:       true,
+		},
+		{
+			desc:      "lower than",
+			op:        conditions.LowerThan,
+			value:     0,
+			threshold: 1,
+			valid:     true,
+			res:       true,
+		},
+		{
+			desc:      "lower or equal",
+			op:        conditions.LowerEqual,
+			value:     2,
+			threshold: 1,
+			valid:     true,
+			res:       false,
+		},
+		{
+			desc:      "equal",
+			op:        conditions.Equal,
+			value:     1,
+			threshold: 1,
+			valid:     true,
+			res:       true,
+		},
+		{
+			desc:      "not equal",
+			op:        conditions.NotEqual,
+			value:     1,
+			threshold: 1,
+			valid:     true,
+			res:       false,
+		},
+		{
+			desc:      "unknown operator",
+			op:        "in",
+			value:     1,
+			threshold: 1,
+			valid:     false,
+			res:       false,
+		},
+	}
+
+	for _, tc := range cases {
+		valid := conditions.Valid(tc.op)
+		assert.Equal(t, tc.valid, valid, fmt.Sprintf("%s: expected valid %t got %t", tc.desc, tc.valid, valid))
+		res := conditions.Compare(tc.op, tc.value, tc.threshold)
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %t got %t", tc.desc, tc.res, res))
+	}
+}
@@ -0,0 +1,6 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package conditions contains the comparison operators used by the services
+// evaluating conditions over the numeric values of the message fields.
+package conditions
@@ -0,0 +1,9 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package expr contains the lexer, parser and evaluator of the expressions
+// embedded in the service configurations and queries, such as the twins
+// computed attributes, LoRa decoder scripts and message filters. The
+// operators and functions of the expression language are defined by the
+// user of the package.
+package expr
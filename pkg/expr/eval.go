@@ -0,0 +1,150 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package expr
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/absmach/magistrala/pkg/errors"
+)
+
+var (
+	// ErrMissingValue indicates that the referenced value doesn't exist.
+	ErrMissingValue = errors.New("missing value")
+
+	// ErrDivisionByZero indicates division by zero.
+	ErrDivisionByZero = errors.New("division by zero")
+)
+
+// Env provides the named values to the evaluated expression.
+type Env interface {
+	// Value returns the value of the given name.
+	Value(name string) (float64, error)
+}
+
+// Values is the environment of the values keyed by their names.
+type Values map[string]float64
+
+// Value returns ErrMissingValue if the value doesn't exist.
+func (vals Values) Value(name string) (float64, error) {
+	v, ok := vals[name]
+	if !ok {
+		return 0, ErrMissingValue
+	}
+
+	return v, nil
+}
+
+// Arithmetic contains the operators +, -, * and /.
+var Arithmetic = map[string]Op{
+	"+": {Prec: 1, Eval: func(l, r float64) (float64, error) { return l + r, nil }},
+	"-": {Prec: 1, Eval: func(l, r float64) (float64, error) { return l - r, nil }},
+	"*": {Prec: 2, Eval: func(l, r float64) (float64, error) { return l * r, nil }},
+	"/": {Prec: 2, Eval: func(l, r float64) (float64, error) {
+		if r == 0 {
+			return 0, ErrDivisionByZero
+		}
+		return l / r, nil
+	}},
+}
+
+// Math contains the functions abs, round, sqrt, pow, min and max.
+var Math = map[string]Func{
+	"abs":   {Arity: 1, Eval: func(_ Env, args []float64) (float64, error) { return math.Abs(args[0]), nil }},
+	"round": {Arity: 1, Eval: func(_ Env, args []float64) (float64, error) { return math.Round(args[0]), nil }},
+	"sqrt":  {Arity: 1, Eval: func(_ Env, args []float64) (float64, error) { return math.Sqrt(args[0]), nil }},
+	"pow":   {Arity: 2, Eval: func(_ Env, args []float64) (float64, error) { return math.Pow(args[0], args[1]), nil }},
+	"min": {Arity: -1, Eval: func(_ Env, args []float64) (float64, error) {
+		v := args[0]
+		for _, arg := range args[1:] {
+			v = math.Min(v, arg)
+		}
+		return v, nil
+	}},
+	"max": {Arity: -1, Eval: func(_ Env, args []float64) (float64, error) {
+		v := args[0]
+		for _, arg := range args[1:] {
+			v = math.Max(v, arg)
+		}
+		return v, nil
+	}},
+}
+
+// Eval evaluates the numeric expression parsed using the same language.
+func (lang Language) Eval(n Node, env Env) (float64, error) {
+	switch n := n.(type) {
+	case Num:
+		return float64(n), nil
+	case Var:
+		return env.Value(string(n))
+	case Unary:
+		v, err := lang.Eval(n.X, env)
+		return -v, err
+	case Binary:
+		op, ok := lang.Ops[n.Op]
+		if !ok || op.Eval == nil {
+			return 0, fmt.Errorf("unsupported operator %q", n.Op)
+		}
+		l, err := lang.Eval(n.L, env)
+		if err != nil {
+			return 0, err
+		}
+		r, err := lang.Eval(n.R, env)
+		if err != nil {
+			return 0, err
+		}
+		return op.Eval(l, r)
+	case Call:
+		fn, ok := lang.Funcs[n.Fn]
+		if !ok || fn.Eval == nil {
+			return 0, fmt.Errorf("unsupported function %q", n.Fn)
+		}
+		args := make([]float64, len(n.Args))
+		for i, arg := range n.Args {
+			v, err := lang.Eval(arg, env)
+			if err != nil {
+				return 0, err
+			}
+			args[i] = v
+		}
+		return fn.Eval(env, args)
+	default:
+		return 0, fmt.Errorf("unexpected %v in numeric expression", n)
+	}
+}
+
+// Vars returns the names referenced by the expression, in the order of
+// appearance and without duplicates.
+func Vars(n Node) []string {
+	var names []string
+	seen := make(map[string]bool)
+	walk(n, func(n Node) {
+		if v, ok := n.(Var); ok && !seen[string(v)] {
+			seen[string(v)] = true
+			names = append(names, string(v))
+		}
+	})
+
+	return names
+}
+
+func walk(n Node, fn func(Node)) {
+	fn(n)
+	switch n := n.(type) {
+	case Unary:
+		walk(n.X, fn)
+	case Binary:
+		walk(n.L, fn)
+		walk(n.R, fn)
+	case Call:
+		for _, arg := range n.Args {
+			walk(arg, fn)
+		}
+	case List:
+		for _, x := range n {
+			walk(x, fn)
+		}
+	}
+}
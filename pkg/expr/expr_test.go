@@ -0,0 +1,246 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package expr_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/pkg/expr"
+	"github.com/stretchr/testify/assert"
+)
+
+var lang = expr.Language{
+	Ops:   expr.Arithmetic,
+	Funcs: expr.Math,
+}
+
+func TestParse(t *testing.T) {
+	filters := expr.Language{
+		Ops: map[string]expr.Op{
+			"or":  {Prec: 1},
+			"and": {Prec: 2},
+			"eq":  {Prec: 3},
+			"in":  {Prec: 3},
+		},
+		Strings: true,
+		Lists:   true,
+	}
+	quoted := expr.Language{Ops: expr.Arithmetic, QuotedVars: true}
+
+	cases := []struct {
+		desc string
+		lang expr.Language
+		expr string
+		node expr.Node
+		err  bool
+	}{
+		{
+			desc: "parse precedence",
+			lang: lang,
+			expr: "a + b * 2",
+			node: expr.Binary{Op: "+", L: expr.Var("a"), R: expr.Binary{Op: "*", L: expr.Var("b"), R: expr.Num(2)}},
+		},
+		{
+			desc: "parse left associativity",
+			lang: lang,
+			expr: "a - b - c",
+			node: expr.Binary{Op: "-", L: expr.Binary{Op: "-", L: expr.Var("a"), R: expr.Var("b")}, R: expr.Var("c")},
+		},
+		{
+			desc: "parse parentheses and unary minus",
+			lang: lang,
+			expr: "-(a + 1)",
+			node: expr.Unary{Op: "-", X: expr.Binary{Op: "+", L: expr.Var("a"), R: expr.Num(1)}},
+		},
+		{
+			desc: "parse hexadecimal and exponent numbers",
+			lang: lang,
+			expr: "0xff + 1.5e2",
+			node: expr.Binary{Op: "+", L: expr.Num(255), R: expr.Num(150)},
+		},
+		{
+			desc: "parse function call",
+			lang: lang,
+			expr: "MAX(a, 1, 2)",
+			node: expr.Call{Fn: "max", Args: []expr.Node{expr.Var("a"), expr.Num(1), expr.Num(2)}},
+		},
+		{
+			desc: "parse keyword operators and list",
+			lang: filters,
+			expr: `a eq "x" AND b in (1, 2)`,
+			node: expr.Binary{
+				Op: "and",
+				L:  expr.Binary{Op: "eq", L: expr.Var("a"), R: expr.Str("x")},
+				R:  expr.Binary{Op: "in", L: expr.Var("b"), R: expr.List{expr.Num(1), expr.Num(2)}},
+			},
+		},
+		{
+			desc: "parse quoted names",
+			lang: quoted,
+			expr: `"temp/in" - "temp/out"`,
+			node: expr.Binary{Op: "-", L: expr.Var("temp/in"), R: expr.Var("temp/out")},
+		},
+		{
+			desc: "parse string without strings enabled",
+			lang: lang,
+			expr: `a + "b"`,
+			err:  true,
+		},
+		{
+			desc: "parse list without lists enabled",
+			lang: lang,
+			expr: "(1, 2)",
+			err:  true,
+		},
+		{
+			desc: "parse unknown function",
+			lang: lang,
+			expr: "foo(1)",
+			err:  true,
+		},
+		{
+			desc: "parse function with wrong arity",
+			lang: lang,
+			expr: "pow(1)",
+			err:  true,
+		},
+		{
+			desc: "parse function without enough arguments",
+			lang: lang,
+			expr: "min()",
+			err:  true,
+		},
+		{
+			desc: "parse missing closing parenthesis",
+			lang: lang,
+			expr: "(a + 1",
+			err:  true,
+		},
+		{
+			desc: "parse missing operand",
+			lang: lang,
+			expr: "a +",
+			err:  true,
+		},
+		{
+			desc: "parse trailing token",
+			lang: lang,
+			expr: "a b",
+			err:  true,
+		},
+		{
+			desc: "parse unterminated string",
+			lang: quoted,
+			expr: `"temp + 1`,
+			err:  true,
+		},
+		{
+			desc: "parse unknown operator",
+			lang: lang,
+			expr: "a % 2",
+			err:  true,
+		},
+		{
+			desc: "parse empty expression",
+			lang: lang,
+			expr: "",
+			err:  true,
+		},
+		{
+			desc: "parse expression nested up to max depth",
+			lang: lang,
+			expr: strings.Repeat("(", expr.MaxDepth-1) + "a" + strings.Repeat(")", expr.MaxDepth-1),
+			node: expr.Var("a"),
+		},
+		{
+			desc: "parse expression nested deeper than max depth",
+			lang: lang,
+			expr: strings.Repeat("(", expr.MaxDepth) + "a" + strings.Repeat(")", expr.MaxDepth),
+			err:  true,
+		},
+		{
+			desc: "parse unary minus chain deeper than max depth",
+			lang: lang,
+			expr: strings.Repeat("-", expr.MaxDepth+1) + "a",
+			err:  true,
+		},
+		{
+			desc: "parse deeply nested function calls",
+			lang: lang,
+			expr: strings.Repeat("abs(", expr.MaxDepth) + "a" + strings.Repeat(")", expr.MaxDepth),
+			err:  true,
+		},
+		{
+			desc: "parse expression longer than max length",
+			lang: lang,
+			expr: "a" + strings.Repeat(" + a", expr.MaxLen/4),
+			err:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		node, err := tc.lang.Parse(tc.expr)
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: expected error %t got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.node, node, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.node, node))
+	}
+}
+
+func TestEval(t *testing.T) {
+	vals := expr.Values{"a": 4, "b": 2}
+
+	cases := []struct {
+		desc string
+		expr string
+		res  float64
+		err  error
+	}{
+		{
+			desc: "evaluate arithmetic",
+			expr: "a + b * 3 - 1",
+			res:  9,
+		},
+		{
+			desc: "evaluate division",
+			expr: "a / b",
+			res:  2,
+		},
+		{
+			desc: "evaluate unary minus",
+			expr: "-a + 1",
+			res:  -3,
+		},
+		{
+			desc: "evaluate functions",
+			expr: "max(abs(-a), sqrt(a), pow(b, 3), min(a, b))",
+			res:  8,
+		},
+		{
+			desc: "evaluate division by zero",
+			expr: "a / (b - 2)",
+			err:  expr.ErrDivisionByZero,
+		},
+		{
+			desc: "evaluate missing value",
+			expr: "a + c",
+			err:  expr.ErrMissingValue,
+		},
+	}
+
+	for _, tc := range cases {
+		node, err := lang.Parse(tc.expr)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected parse error %s\n", tc.desc, err))
+		res, err := lang.Eval(node, vals)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.res, res))
+	}
+}
+
+func TestVars(t *testing.T) {
+	node, err := lang.Parse("a + max(b, a) * -c")
+	assert.Nil(t, err, fmt.Sprintf("unexpected parse error %s", err))
+	assert.Equal(t, []string{"a", "b", "c"}, expr.Vars(node))
+}
@@ -0,0 +1,163 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind is the kind of the token.
+type Kind int
+
+// Token kinds.
+const (
+	EOF Kind = iota
+	Number
+	Ident
+	String
+	Punct
+)
+
+// puncts are punctuations consisting of more than one character.
+var puncts = []string{"<<", ">>", "<=", ">=", "==", "!=", "&&", "||"}
+
+// Token is the lexical token of the expression.
+type Token struct {
+	Kind Kind
+	// Lit is the token text. The value of the string token is unquoted.
+	Lit string
+	// Pos is the byte offset of the token in the expression.
+	Pos int
+}
+
+// Lexer splits the expression into tokens. Numbers are decimal, optionally
+// with the fraction and exponent, or hexadecimal integers prefixed by 0x.
+// Identifiers consist of letters, digits and underscores and don't start
+// with a digit. Strings are double-quoted using Go escape sequences.
+type Lexer struct {
+	src string
+	pos int
+}
+
+// NewLexer returns lexer of the given expression.
+func NewLexer(src string) *Lexer {
+	return &Lexer{src: src}
+}
+
+// Next returns the next token. EOF token is returned at the end of the
+// expression.
+func (l *Lexer) Next() (Token, error) {
+	for l.pos < len(l.src) && isSpace(l.src[l.pos]) {
+		l.pos++
+	}
+	start := l.pos
+	if l.pos >= len(l.src) {
+		return Token{Kind: EOF, Pos: start}, nil
+	}
+
+	c := l.src[l.pos]
+	switch {
+	case isDigit(c) || (c == '.' && l.pos+1 < len(l.src) && isDigit(l.src[l.pos+1])):
+		l.number()
+		return Token{Kind: Number, Lit: l.src[start:l.pos], Pos: start}, nil
+	case isLetter(c):
+		for l.pos < len(l.src) && (isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		return Token{Kind: Ident, Lit: l.src[start:l.pos], Pos: start}, nil
+	case c == '"':
+		return l.string()
+	}
+
+	for _, p := range puncts {
+		if strings.HasPrefix(l.src[l.pos:], p) {
+			l.pos += len(p)
+			return Token{Kind: Punct, Lit: p, Pos: start}, nil
+		}
+	}
+	_, size := utf8.DecodeRuneInString(l.src[l.pos:])
+	l.pos += size
+
+	return Token{Kind: Punct, Lit: l.src[start:l.pos], Pos: start}, nil
+}
+
+func (l *Lexer) number() {
+	if l.src[l.pos] == '0' && l.pos+1 < len(l.src) && (l.src[l.pos+1] == 'x' || l.src[l.pos+1] == 'X') {
+		l.pos += 2
+		for l.pos < len(l.src) && isHex(l.src[l.pos]) {
+			l.pos++
+		}
+		return
+	}
+
+	for l.pos < len(l.src) && (isDigit(l.src[l.pos]) || l.src[l.pos] == '.') {
+		l.pos++
+	}
+	// Exponent is consumed only if it is followed by digits.
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		end := l.pos + 1
+		if end < len(l.src) && (l.src[end] == '+' || l.src[end] == '-') {
+			end++
+		}
+		if end < len(l.src) && isDigit(l.src[end]) {
+			for end < len(l.src) && isDigit(l.src[end]) {
+				end++
+			}
+			l.pos = end
+		}
+	}
+}
+
+func (l *Lexer) string() (Token, error) {
+	start := l.pos
+	for l.pos++; l.pos < len(l.src); l.pos++ {
+		switch l.src[l.pos] {
+		case '\\':
+			l.pos++
+		case '"':
+			l.pos++
+			s, err := strconv.Unquote(l.src[start:l.pos])
+			if err != nil {
+				return Token{}, fmt.Errorf("invalid string %s", l.src[start:l.pos])
+			}
+			return Token{Kind: String, Lit: s, Pos: start}, nil
+		}
+	}
+
+	return Token{}, fmt.Errorf("unterminated string %s", l.src[start:])
+}
+
+// IsIdent reports whether the string is a valid identifier.
+func IsIdent(s string) bool {
+	if s == "" || !isLetter(s[0]) {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if !isLetter(s[i]) && !isDigit(s[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isSpace(c byte) bool {
+	return unicode.IsSpace(rune(c))
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHex(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
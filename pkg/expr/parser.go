@@ -0,0 +1,315 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxLen is the maximum length of the expression in bytes.
+	MaxLen = 4096
+	// MaxDepth is the maximum nesting depth of the expression, counting
+	// the parentheses, function calls and unary minuses.
+	MaxDepth = 64
+)
+
+// Node is the parsed expression.
+type Node interface {
+	node()
+}
+
+// Num is the number literal.
+type Num float64
+
+// Str is the string literal.
+type Str string
+
+// Var is the reference to the named value.
+type Var string
+
+// Unary is the negation of the expression.
+type Unary struct {
+	Op string
+	X  Node
+}
+
+// Binary is the binary operation.
+type Binary struct {
+	Op   string
+	L, R Node
+}
+
+// Call is the function call.
+type Call struct {
+	Fn   string
+	Args []Node
+}
+
+// List is the parenthesized, comma-separated list of expressions.
+type List []Node
+
+func (Num) node()    {}
+func (Str) node()    {}
+func (Var) node()    {}
+func (Unary) node()  {}
+func (Binary) node() {}
+func (Call) node()   {}
+func (List) node()   {}
+
+// Op is the binary operator.
+type Op struct {
+	// Prec is the operator precedence. Operators of higher precedence bind
+	// tighter, and the precedence must be positive.
+	Prec int
+	// Eval evaluates the operator. Operators without Eval are only parsed.
+	Eval func(l, r float64) (float64, error)
+}
+
+// Func is the function of the expression language.
+type Func struct {
+	// Arity is the number of the function arguments. Negative arity stands
+	// for at least that many arguments.
+	Arity int
+	// Eval evaluates the function. Functions without Eval are only parsed.
+	Eval func(env Env, args []float64) (float64, error)
+}
+
+// Language defines the operators and functions of the expressions. Besides
+// them, expressions consist of numbers, names, unary minus and parentheses.
+// Operators which are identifiers, e.g. "and", and function names are case
+// insensitive, and must be defined in lower case.
+type Language struct {
+	Ops   map[string]Op
+	Funcs map[string]Func
+	// Strings enables string literals.
+	Strings bool
+	// Lists enables parenthesized lists of expressions, e.g. ("a", "b").
+	Lists bool
+	// QuotedVars parses strings as names, so that names which are not valid
+	// identifiers can be used, e.g. "temp/in".
+	QuotedVars bool
+}
+
+// Parse parses the expression using precedence climbing. Expressions longer
+// than MaxLen or nested deeper than MaxDepth are rejected, since they are
+// parsed recursively and can come from the untrusted queries.
+func (lang Language) Parse(src string) (Node, error) {
+	if len(src) > MaxLen {
+		return nil, fmt.Errorf("expression longer than %d bytes", MaxLen)
+	}
+	p := parser{lang: lang, lex: NewLexer(src)}
+	p.next()
+	x, err := p.binary(1)
+	if p.err != nil {
+		return nil, p.err
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.Kind != EOF {
+		return nil, fmt.Errorf("unexpected %q", p.tok.Lit)
+	}
+
+	return x, nil
+}
+
+type parser struct {
+	lang Language
+	lex  *Lexer
+	tok  Token
+	// err is the lexer error. Once it occurs, the parser sees the end of
+	// the expression.
+	err error
+	// depth is the nesting depth of the parsed unary expression.
+	depth int
+}
+
+func (p *parser) next() {
+	if p.err != nil {
+		return
+	}
+	if p.tok, p.err = p.lex.Next(); p.err != nil {
+		p.tok = Token{Kind: EOF}
+	}
+}
+
+func (p *parser) punct(lit string) bool {
+	return p.tok.Kind == Punct && p.tok.Lit == lit
+}
+
+// op returns the binary operator of the current token.
+func (p *parser) op() (string, Op, bool) {
+	var name string
+	switch p.tok.Kind {
+	case Punct:
+		name = p.tok.Lit
+	case Ident:
+		name = strings.ToLower(p.tok.Lit)
+	default:
+		return "", Op{}, false
+	}
+	op, ok := p.lang.Ops[name]
+
+	return name, op, ok
+}
+
+// binary parses the binary expression of at least the given precedence.
+func (p *parser) binary(prec int) (Node, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		name, op, ok := p.op()
+		if !ok || op.Prec < prec {
+			return x, nil
+		}
+		p.next()
+		y, err := p.binary(op.Prec + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = Binary{Op: name, L: x, R: y}
+	}
+}
+
+func (p *parser) unary() (Node, error) {
+	// Every nested parenthesis, function argument and unary minus parses
+	// the unary expression, so its depth limits the recursion.
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > MaxDepth {
+		return nil, fmt.Errorf("expression nested deeper than %d levels", MaxDepth)
+	}
+
+	if p.punct("-") {
+		p.next()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return Unary{Op: "-", X: x}, nil
+	}
+
+	return p.primary()
+}
+
+func (p *parser) primary() (Node, error) {
+	tok := p.tok
+	switch tok.Kind {
+	case Number:
+		p.next()
+		v, err := parseNumber(tok.Lit)
+		if err != nil {
+			return nil, err
+		}
+		return Num(v), nil
+	case String:
+		p.next()
+		switch {
+		case p.lang.QuotedVars:
+			return Var(tok.Lit), nil
+		case p.lang.Strings:
+			return Str(tok.Lit), nil
+		default:
+			return nil, fmt.Errorf("unexpected string %q", tok.Lit)
+		}
+	case Ident:
+		p.next()
+		if p.punct("(") {
+			return p.call(tok.Lit)
+		}
+		return Var(tok.Lit), nil
+	case Punct:
+		if tok.Lit != "(" {
+			return nil, fmt.Errorf("unexpected %q", tok.Lit)
+		}
+		return p.parens()
+	default:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+}
+
+// parens parses the parenthesized expression or the list of expressions.
+func (p *parser) parens() (Node, error) {
+	// Skip opening parenthesis.
+	p.next()
+	x, err := p.binary(1)
+	if err != nil {
+		return nil, err
+	}
+	if p.lang.Lists && p.punct(",") {
+		list := List{x}
+		for p.punct(",") {
+			p.next()
+			y, err := p.binary(1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, y)
+		}
+		x = list
+	}
+	if !p.punct(")") {
+		return nil, fmt.Errorf("missing closing parenthesis")
+	}
+	p.next()
+
+	return x, nil
+}
+
+func (p *parser) call(name string) (Node, error) {
+	fn := strings.ToLower(name)
+	f, ok := p.lang.Funcs[fn]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q", name)
+	}
+
+	// Skip opening parenthesis.
+	p.next()
+	c := Call{Fn: fn}
+	for !p.punct(")") {
+		if len(c.Args) > 0 {
+			if !p.punct(",") {
+				return nil, fmt.Errorf("missing comma in %s arguments", name)
+			}
+			p.next()
+		}
+		arg, err := p.binary(1)
+		if err != nil {
+			return nil, err
+		}
+		c.Args = append(c.Args, arg)
+	}
+	p.next()
+
+	switch {
+	case f.Arity >= 0 && len(c.Args) != f.Arity:
+		return nil, fmt.Errorf("%s expects %d arguments", name, f.Arity)
+	case f.Arity < 0 && len(c.Args) < -f.Arity:
+		return nil, fmt.Errorf("%s expects at least %d arguments", name, -f.Arity)
+	}
+
+	return c, nil
+}
+
+// parseNumber parses decimal and hexadecimal numbers.
+func parseNumber(lit string) (float64, error) {
+	if strings.HasPrefix(lit, "0x") || strings.HasPrefix(lit, "0X") {
+		v, err := strconv.ParseUint(lit[2:], 16, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", lit)
+		}
+		return float64(v), nil
+	}
+	v, err := strconv.ParseFloat(lit, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", lit)
+	}
+
+	return v, nil
+}
@@ -94,7 +94,7 @@ func (ps *pubsub) Subscribe(ctx context.Context, cfg messaging.SubscriberConfig)
 		return ErrEmptyTopic
 	}
 
-	nh := ps.natsHandler(cfg.Handler)
+	nh := ps.natsHandler(cfg.Handler, cfg.Concurrency)
 
 	consumerConfig := jetstream.ConsumerConfig{
 		Name:          formatConsumerName(cfg.Topic, cfg.ID),
@@ -140,8 +140,8 @@ func (ps *pubsub) Unsubscribe(ctx context.Context, id, topic string) error {
 	}
 }
 
-func (ps *pubsub) natsHandler(h messaging.MessageHandler) func(m jetstream.Msg) {
-	return func(m jetstream.Msg) {
+func (ps *pubsub) natsHandler(h messaging.MessageHandler, concurrency uint) func(m jetstream.Msg) {
+	handle := func(m jetstream.Msg) {
 		var msg messaging.Message
 		if err := proto.Unmarshal(m.Data(), &msg); err != nil {
 			ps.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
@@ -156,6 +156,20 @@ func (ps *pubsub) natsHandler(h messaging.MessageHandler) func(m jetstream.Msg)
 			ps.logger.Warn(fmt.Sprintf("Failed to ack message: %s", err))
 		}
 	}
+	if concurrency <= 1 {
+		return handle
+	}
+
+	// Messages are acknowledged individually, so handling them concurrently
+	// doesn't affect acknowledgement of the other messages.
+	sem := make(chan struct{}, concurrency)
+	return func(m jetstream.Msg) {
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem }()
+			handle(m)
+		}()
+	}
 }
 
 func formatConsumerName(topic, id string) string {
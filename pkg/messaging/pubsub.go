@@ -39,6 +39,10 @@ type SubscriberConfig struct {
 	Topic          string
 	Handler        MessageHandler
 	DeliveryPolicy DeliveryPolicy
+	// Concurrency is the maximum number of messages handled concurrently.
+	// Messages are handled one by one if it's not set. Handler must be
+	// safe for concurrent use if Concurrency is greater than 1.
+	Concurrency uint
 }
 
 // Subscriber specifies message subscription API.
@@ -126,7 +126,7 @@ func (ps *pubsub) Subscribe(ctx context.Context, cfg messaging.SubscriberConfig)
 	if err != nil {
 		return err
 	}
-	go ps.handle(msgs, cfg.Handler)
+	go ps.handle(msgs, cfg.Handler, cfg.Concurrency)
 	s[cfg.ID] = subscription{
 		cancel: func() error {
 			if err := ps.channel.Cancel(clientID, false); err != nil {
@@ -176,16 +176,36 @@ func (ps *pubsub) Unsubscribe(ctx context.Context, id, topic string) error {
 	return nil
 }
 
-func (ps *pubsub) handle(deliveries <-chan amqp.Delivery, h messaging.MessageHandler) {
-	for d := range deliveries {
-		var msg messaging.Message
-		if err := proto.Unmarshal(d.Body, &msg); err != nil {
-			ps.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
-			return
-		}
-		if err := h.Handle(&msg); err != nil {
-			ps.logger.Warn(fmt.Sprintf("Failed to handle Magistrala message: %s", err))
-			return
+func (ps *pubsub) handle(deliveries <-chan amqp.Delivery, h messaging.MessageHandler, concurrency uint) {
+	if concurrency <= 1 {
+		for d := range deliveries {
+			if err := ps.handleDelivery(d, h); err != nil {
+				return
+			}
 		}
+		return
+	}
+
+	sem := make(chan struct{}, concurrency)
+	for d := range deliveries {
+		sem <- struct{}{}
+		go func(d amqp.Delivery) {
+			defer func() { <-sem }()
+			_ = ps.handleDelivery(d, h)
+		}(d)
+	}
+}
+
+func (ps *pubsub) handleDelivery(d amqp.Delivery, h messaging.MessageHandler) error {
+	var msg messaging.Message
+	if err := proto.Unmarshal(d.Body, &msg); err != nil {
+		ps.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
+		return err
 	}
+	if err := h.Handle(&msg); err != nil {
+		ps.logger.Warn(fmt.Sprintf("Failed to handle Magistrala message: %s", err))
+		return err
+	}
+
+	return nil
 }
@@ -196,6 +196,31 @@ func (sdk mgSDK) UpdateBootstrapCerts(id, clientCert, clientKey, ca, token strin
 	return bc, sdkerr
 }
 
+func (sdk mgSDK) IssueBootstrapCert(id, csr, valid, token string) (BootstrapConfig, errors.SDKError) {
+	url := fmt.Sprintf("%s/%s/%s/csr", sdk.bootstrapURL, bootstrapCertsEndpoint, id)
+	request := bootstrapCSRReq{
+		CSR:   csr,
+		Valid: valid,
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		return BootstrapConfig{}, errors.NewSDKError(err)
+	}
+
+	_, body, sdkerr := sdk.processRequest(http.MethodPost, url, token, data, nil, http.StatusOK)
+	if sdkerr != nil {
+		return BootstrapConfig{}, sdkerr
+	}
+
+	var bc BootstrapConfig
+	if err := json.Unmarshal(body, &bc); err != nil {
+		return BootstrapConfig{}, errors.NewSDKError(err)
+	}
+
+	return bc, nil
+}
+
 func (sdk mgSDK) UpdateBootstrapConnection(id string, channels []string, token string) errors.SDKError {
 	url := fmt.Sprintf("%s/%s/%s", sdk.bootstrapURL, bootstrapConnEndpoint, id)
 	request := map[string][]string{
@@ -248,3 +273,8 @@ func (sdk mgSDK) BootstrapSecure(externalID, externalKey string) (BootstrapConfi
 
 	return bc, nil
 }
+
+type bootstrapCSRReq struct {
+	CSR   string `json:"csr"`
+	Valid string `json:"ttl"`
+}
@@ -51,6 +51,32 @@ func (sdk mgSDK) IssueCert(thingID, valid, token string) (Cert, errors.SDKError)
 	return c, nil
 }
 
+func (sdk mgSDK) IssueCertFromCSR(thingID, csr, valid, token string) (Cert, errors.SDKError) {
+	r := csrCertReq{
+		ThingID: thingID,
+		CSR:     csr,
+		Valid:   valid,
+	}
+	d, err := json.Marshal(r)
+	if err != nil {
+		return Cert{}, errors.NewSDKError(err)
+	}
+
+	url := fmt.Sprintf("%s/%s/csr", sdk.certsURL, certsEndpoint)
+
+	_, body, sdkerr := sdk.processRequest(http.MethodPost, url, token, d, nil, http.StatusCreated)
+	if sdkerr != nil {
+		return Cert{}, sdkerr
+	}
+
+	var c Cert
+	if err := json.Unmarshal(body, &c); err != nil {
+		return Cert{}, errors.NewSDKError(err)
+	}
+
+	return c, nil
+}
+
 func (sdk mgSDK) ViewCert(id, token string) (Cert, errors.SDKError) {
 	url := fmt.Sprintf("%s/%s/%s", sdk.certsURL, certsEndpoint, id)
 
@@ -67,6 +93,31 @@ func (sdk mgSDK) ViewCert(id, token string) (Cert, errors.SDKError) {
 	return cert, nil
 }
 
+func (sdk mgSDK) RenewCert(certID, valid string, reuseKey bool, token string) (Cert, errors.SDKError) {
+	r := renewCertReq{
+		Valid:    valid,
+		ReuseKey: reuseKey,
+	}
+	d, err := json.Marshal(r)
+	if err != nil {
+		return Cert{}, errors.NewSDKError(err)
+	}
+
+	url := fmt.Sprintf("%s/%s/%s/renew", sdk.certsURL, certsEndpoint, certID)
+
+	_, body, sdkerr := sdk.processRequest(http.MethodPost, url, token, d, nil, http.StatusCreated)
+	if sdkerr != nil {
+		return Cert{}, sdkerr
+	}
+
+	var c Cert
+	if err := json.Unmarshal(body, &c); err != nil {
+		return Cert{}, errors.NewSDKError(err)
+	}
+
+	return c, nil
+}
+
 func (sdk mgSDK) ViewCertByThing(thingID, token string) (CertSerials, errors.SDKError) {
 	url := fmt.Sprintf("%s/%s/%s", sdk.certsURL, serialsEndpoint, thingID)
 
@@ -103,3 +154,14 @@ type certReq struct {
 	ThingID string `json:"thing_id"`
 	Valid   string `json:"ttl"`
 }
+
+type csrCertReq struct {
+	ThingID string `json:"thing_id"`
+	CSR     string `json:"csr"`
+	Valid   string `json:"ttl"`
+}
+
+type renewCertReq struct {
+	Valid    string `json:"ttl,omitempty"`
+	ReuseKey bool   `json:"reuse_key"`
+}
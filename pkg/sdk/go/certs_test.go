@@ -4,6 +4,12 @@
 package sdk_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -35,6 +41,7 @@ var (
 	caKeyPath         = "../../../docker/ssl/certs/ca.key"
 	cfgAuthTimeout    = "1s"
 	cfgSignHoursValid = "24h"
+	certThingKey      = "thing-key"
 )
 
 func setupCerts() (*httptest.Server, *authmocks.AuthClient, *thmocks.Repository, error) {
@@ -158,6 +165,120 @@ func TestIssueCert(t *testing.T) {
 	}
 }
 
+func newCSR(t *testing.T, cn string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err, fmt.Sprintf("unexpected key generation error: %s", err))
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: cn}}, key)
+	require.Nil(t, err, fmt.Sprintf("unexpected CSR creation error: %s", err))
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
+
+func TestIssueCertFromCSR(t *testing.T) {
+	ts, auth, trepo, err := setupCerts()
+	require.Nil(t, err, fmt.Sprintf("unexpected error during creating service: %s", err))
+	defer ts.Close()
+
+	sdkConf := sdk.Config{
+		CertsURL:        ts.URL,
+		MsgContentType:  contentType,
+		TLSVerification: false,
+	}
+
+	mgsdk := sdk.NewSDK(sdkConf)
+
+	csr := newCSR(t, thingID)
+
+	cases := []struct {
+		desc     string
+		thingID  string
+		csr      string
+		duration string
+		token    string
+		err      errors.SDKError
+	}{
+		{
+			desc:     "create new cert from CSR",
+			thingID:  thingID,
+			csr:      csr,
+			duration: "10h",
+			token:    validToken,
+			err:      nil,
+		},
+		{
+			desc:     "create new cert from CSR with thing key subject",
+			thingID:  thingID,
+			csr:      newCSR(t, certThingKey),
+			duration: "10h",
+			token:    validToken,
+			err:      errors.NewSDKErrorWithStatus(errors.Wrap(certs.ErrInvalidCSR, errors.New("certificate signing request subject does not match the thing")), http.StatusBadRequest),
+		},
+		{
+			desc:     "create new cert from malformed CSR",
+			thingID:  thingID,
+			csr:      "invalid",
+			duration: "10h",
+			token:    validToken,
+			err:      errors.NewSDKErrorWithStatus(errors.Wrap(certs.ErrInvalidCSR, errors.New("failed to decode PEM data")), http.StatusBadRequest),
+		},
+		{
+			desc:     "create new cert from empty CSR",
+			thingID:  thingID,
+			csr:      "",
+			duration: "10h",
+			token:    validToken,
+			err:      errors.NewSDKErrorWithStatus(errors.Wrap(apiutil.ErrValidation, apiutil.ErrMissingCertData), http.StatusBadRequest),
+		},
+		{
+			desc:     "create new cert from CSR with empty thing id",
+			thingID:  "",
+			csr:      csr,
+			duration: "10h",
+			token:    validToken,
+			err:      errors.NewSDKErrorWithStatus(errors.Wrap(apiutil.ErrValidation, apiutil.ErrMissingID), http.StatusBadRequest),
+		},
+		{
+			desc:     "create new cert from CSR with malformed duration",
+			thingID:  thingID,
+			csr:      csr,
+			duration: "10g",
+			token:    validToken,
+			err:      errors.NewSDKErrorWithStatus(errors.Wrap(apiutil.ErrValidation, apiutil.ErrInvalidCertData), http.StatusBadRequest),
+		},
+		{
+			desc:     "create new cert from CSR with empty token",
+			thingID:  thingID,
+			csr:      csr,
+			duration: "10h",
+			token:    "",
+			err:      errors.NewSDKErrorWithStatus(errors.Wrap(apiutil.ErrValidation, apiutil.ErrBearerToken), http.StatusUnauthorized),
+		},
+		{
+			desc:     "create new cert from CSR with invalid token",
+			thingID:  thingID,
+			csr:      csr,
+			duration: "10h",
+			token:    authmocks.InvalidValue,
+			err:      errors.NewSDKErrorWithStatus(errors.Wrap(svcerr.ErrAuthentication, svcerr.ErrAuthentication), http.StatusUnauthorized),
+		},
+	}
+
+	for _, tc := range cases {
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: validID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		repoCall2 := trepo.On("RetrieveByID", mock.Anything, mock.Anything).Return(clients.Client{ID: tc.thingID, Credentials: clients.Credentials{Secret: certThingKey}}, nil)
+		cert, err := mgsdk.IssueCertFromCSR(tc.thingID, tc.csr, tc.duration, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		if err == nil {
+			assert.NotEmpty(t, cert.ClientCert, fmt.Sprintf("%s: got empty cert", tc.desc))
+			assert.Empty(t, cert.ClientKey, fmt.Sprintf("%s: unexpected cert private key", tc.desc))
+		}
+		repoCall.Unset()
+		repoCall1.Unset()
+		repoCall2.Unset()
+	}
+}
+
 func TestViewCert(t *testing.T) {
 	ts, auth, trepo, err := setupCerts()
 	require.Nil(t, err, fmt.Sprintf("unexpected error during creating service: %s", err))
@@ -221,6 +342,106 @@ func TestViewCert(t *testing.T) {
 	}
 }
 
+func TestRenewCert(t *testing.T) {
+	ts, auth, trepo, err := setupCerts()
+	require.Nil(t, err, fmt.Sprintf("unexpected error during creating service: %s", err))
+	defer ts.Close()
+
+	sdkConf := sdk.Config{
+		CertsURL:        ts.URL,
+		MsgContentType:  contentType,
+		TLSVerification: false,
+	}
+
+	mgsdk := sdk.NewSDK(sdkConf)
+
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := trepo.On("RetrieveByID", mock.Anything, mock.Anything).Return(clients.Client{ID: thingID}, nil)
+	cert, err := mgsdk.IssueCert(thingID, "10h", token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error during creating cert: %s", err))
+	expired, err := mgsdk.IssueCert(thingID, "1ns", token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error during creating cert: %s", err))
+	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
+
+	cases := []struct {
+		desc     string
+		certID   string
+		duration string
+		reuseKey bool
+		token    string
+		err      errors.SDKError
+	}{
+		{
+			desc:     "renew cert",
+			certID:   cert.CertSerial,
+			duration: "",
+			token:    token,
+			err:      nil,
+		},
+		{
+			desc:     "renew cert with duration and key reuse",
+			certID:   cert.CertSerial,
+			duration: "20h",
+			reuseKey: true,
+			token:    token,
+			err:      nil,
+		},
+		{
+			desc:     "renew cert with malformed duration",
+			certID:   cert.CertSerial,
+			duration: "10g",
+			token:    token,
+			err:      errors.NewSDKErrorWithStatus(errors.Wrap(apiutil.ErrValidation, apiutil.ErrInvalidCertData), http.StatusBadRequest),
+		},
+		{
+			desc:     "renew expired cert",
+			certID:   expired.CertSerial,
+			duration: "",
+			token:    token,
+			err:      errors.NewSDKErrorWithStatus(certs.ErrExpiredCert, http.StatusBadRequest),
+		},
+		{
+			desc:     "renew non-existent cert",
+			certID:   "43",
+			duration: "",
+			token:    token,
+			err:      errors.NewSDKErrorWithStatus(errors.Wrap(svcerr.ErrNotFound, svcerr.ErrNotFound), http.StatusInternalServerError),
+		},
+		{
+			desc:     "renew cert with invalid token",
+			certID:   cert.CertSerial,
+			duration: "",
+			token:    authmocks.InvalidValue,
+			err:      errors.NewSDKErrorWithStatus(errors.Wrap(svcerr.ErrAuthentication, svcerr.ErrAuthentication), http.StatusUnauthorized),
+		},
+		{
+			desc:     "renew cert with empty token",
+			certID:   cert.CertSerial,
+			duration: "",
+			token:    "",
+			err:      errors.NewSDKErrorWithStatus(errors.Wrap(apiutil.ErrValidation, apiutil.ErrBearerToken), http.StatusUnauthorized),
+		},
+	}
+
+	for _, tc := range cases {
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: validID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		repoCall2 := trepo.On("RetrieveByID", mock.Anything, mock.Anything).Return(clients.Client{ID: thingID}, nil)
+		c, err := mgsdk.RenewCert(tc.certID, tc.duration, tc.reuseKey, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		if err == nil {
+			assert.NotEqual(t, tc.certID, c.CertSerial, fmt.Sprintf("%s: expected new cert serial", tc.desc))
+			assert.Equal(t, tc.reuseKey, c.ClientKey == "", fmt.Sprintf("%s: unexpected cert private key", tc.desc))
+		}
+		repoCall.Unset()
+		repoCall1.Unset()
+		repoCall2.Unset()
+	}
+}
+
 func TestViewCertByThing(t *testing.T) {
 	ts, auth, trepo, err := setupCerts()
 	require.Nil(t, err, fmt.Sprintf("unexpected error during creating service: %s", err))
@@ -11,6 +11,9 @@ import (
 
 	"github.com/absmach/magistrala"
 	authmocks "github.com/absmach/magistrala/auth/mocks"
+	"github.com/absmach/magistrala/consumers"
+	dlqapi "github.com/absmach/magistrala/consumers/api"
+	cmocks "github.com/absmach/magistrala/consumers/mocks"
 	"github.com/absmach/magistrala/consumers/notifiers"
 	httpapi "github.com/absmach/magistrala/consumers/notifiers/api"
 	"github.com/absmach/magistrala/consumers/notifiers/mocks"
@@ -18,8 +21,10 @@ import (
 	mglog "github.com/absmach/magistrala/logger"
 	"github.com/absmach/magistrala/pkg/errors"
 	svcerr "github.com/absmach/magistrala/pkg/errors/service"
+	"github.com/absmach/magistrala/pkg/messaging"
 	sdk "github.com/absmach/magistrala/pkg/sdk/go"
 	"github.com/absmach/magistrala/pkg/uuid"
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -43,7 +48,7 @@ func setupSubscriptions() (*httptest.Server, *authmocks.AuthClient) {
 
 	svc := notifiers.New(auth, repo, idp, notifier, from)
 	logger := mglog.NewMock()
-	mux := httpapi.MakeHandler(svc, logger, instanceID)
+	mux := httpapi.MakeHandler(svc, cmocks.NewDeadLetterQueue(nil), logger, instanceID)
 
 	return httptest.NewServer(mux), auth
 }
@@ -277,3 +282,138 @@ func TestDeleteSubscription(t *testing.T) {
 		repoCall.Unset()
 	}
 }
+
+func setupDeadLetters() *httptest.Server {
+	letters := []consumers.DeadLetter{
+		{
+			ID:       "1",
+			Consumer: "writer",
+			Error:    "failed to consume",
+			Attempts: 3,
+			Message:  &messaging.Message{Channel: "channel", Protocol: "http", Payload: []byte(`[{"n":"temp","v":1}]`)},
+		},
+		{
+			ID:       "2",
+			Consumer: "writer",
+			Error:    "failed to consume",
+			Attempts: 3,
+			Message:  &messaging.Message{Channel: "channel", Protocol: cmocks.FailingMessageProtocol, Payload: []byte(`[{"n":"temp","v":2}]`)},
+		},
+	}
+	logger := mglog.NewMock()
+	mux := chi.NewRouter()
+	mux.Mount("/dlq", dlqapi.MakeHandler(cmocks.NewDeadLetterQueue(letters), logger))
+
+	return httptest.NewServer(mux)
+}
+
+func TestListDeadLetters(t *testing.T) {
+	ts := setupDeadLetters()
+	defer ts.Close()
+
+	mgsdk := sdk.NewSDK(sdk.Config{ConsumerURL: ts.URL})
+
+	page, err := mgsdk.ListDeadLetters()
+	assert.Nil(t, err, fmt.Sprintf("list dead letters: unexpected error %s", err))
+	assert.Equal(t, uint64(2), page.Total, fmt.Sprintf("list dead letters: expected total 2 got %d", page.Total))
+	for i, letter := range page.DeadLetters {
+		id := fmt.Sprintf("%d", i+1)
+		assert.Equal(t, id, letter.ID, fmt.Sprintf("list dead letters: expected dead letter %s got %s", id, letter.ID))
+		assert.Equal(t, "channel", letter.Message.Channel, fmt.Sprintf("list dead letters: expected channel channel got %s", letter.Message.Channel))
+	}
+}
+
+func TestViewDeadLetter(t *testing.T) {
+	ts := setupDeadLetters()
+	defer ts.Close()
+
+	mgsdk := sdk.NewSDK(sdk.Config{ConsumerURL: ts.URL})
+
+	cases := []struct {
+		desc     string
+		id       string
+		err      errors.SDKError
+		attempts uint64
+	}{
+		{
+			desc:     "view existing dead letter",
+			id:       "1",
+			err:      nil,
+			attempts: 3,
+		},
+		{
+			desc: "view non-existing dead letter",
+			id:   "unknown",
+			err:  errors.NewSDKErrorWithStatus(consumers.ErrDeadLetterNotFound, http.StatusNotFound),
+		},
+	}
+
+	for _, tc := range cases {
+		letter, err := mgsdk.ViewDeadLetter(tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.attempts, letter.Attempts, fmt.Sprintf("%s: expected %d attempts, got %d", tc.desc, tc.attempts, letter.Attempts))
+	}
+}
+
+func TestReplayDeadLetter(t *testing.T) {
+	ts := setupDeadLetters()
+	defer ts.Close()
+
+	mgsdk := sdk.NewSDK(sdk.Config{ConsumerURL: ts.URL})
+
+	cases := []struct {
+		desc string
+		id   string
+		err  errors.SDKError
+	}{
+		{
+			desc: "replay dead letter",
+			id:   "1",
+			err:  nil,
+		},
+		{
+			desc: "replay already replayed dead letter",
+			id:   "1",
+			err:  errors.NewSDKErrorWithStatus(errors.Wrap(errors.New("failed to replay dead letter"), consumers.ErrDeadLetterNotFound), http.StatusNotFound),
+		},
+		{
+			desc: "replay dead letter that fails to be consumed",
+			id:   "2",
+			err:  errors.NewSDKErrorWithStatus(errors.Wrap(errors.New("failed to replay dead letter"), errors.New("failed to consume message")), http.StatusUnprocessableEntity),
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := mgsdk.ReplayDeadLetter(tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestDeleteDeadLetter(t *testing.T) {
+	ts := setupDeadLetters()
+	defer ts.Close()
+
+	mgsdk := sdk.NewSDK(sdk.Config{ConsumerURL: ts.URL})
+
+	cases := []struct {
+		desc string
+		id   string
+		err  errors.SDKError
+	}{
+		{
+			desc: "delete existing dead letter",
+			id:   "1",
+			err:  nil,
+		},
+		{
+			desc: "delete deleted dead letter",
+			id:   "1",
+			err:  errors.NewSDKErrorWithStatus(consumers.ErrDeadLetterNotFound, http.StatusNotFound),
+		},
+	}
+
+	for _, tc := range cases {
+		err := mgsdk.DeleteDeadLetter(tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/absmach/magistrala/pkg/errors"
+)
+
+const (
+	deadLettersEndpoint = "dlq"
+	replayEndpoint      = "replay"
+)
+
+// DeadLetter represents a message that failed to be consumed by the consumer.
+type DeadLetter struct {
+	ID       string            `json:"id"`
+	Consumer string            `json:"consumer"`
+	Error    string            `json:"error"`
+	Attempts uint64            `json:"attempts"`
+	FailedAt time.Time         `json:"failed_at"`
+	Message  DeadLetterMessage `json:"message"`
+}
+
+// DeadLetterMessage represents the message that failed to be consumed.
+type DeadLetterMessage struct {
+	Channel   string `json:"channel,omitempty"`
+	Subtopic  string `json:"subtopic,omitempty"`
+	Publisher string `json:"publisher,omitempty"`
+	Protocol  string `json:"protocol,omitempty"`
+	Payload   []byte `json:"payload,omitempty"`
+	Created   int64  `json:"created,omitempty"`
+}
+
+func (sdk mgSDK) ListDeadLetters() (DeadLetterPage, errors.SDKError) {
+	url := fmt.Sprintf("%s/%s", sdk.consumerURL, deadLettersEndpoint)
+
+	_, body, sdkerr := sdk.processRequest(http.MethodGet, url, "", nil, nil, http.StatusOK)
+	if sdkerr != nil {
+		return DeadLetterPage{}, sdkerr
+	}
+
+	var dp DeadLetterPage
+	if err := json.Unmarshal(body, &dp); err != nil {
+		return DeadLetterPage{}, errors.NewSDKError(err)
+	}
+
+	return dp, nil
+}
+
+func (sdk mgSDK) ViewDeadLetter(id string) (DeadLetter, errors.SDKError) {
+	url := fmt.Sprintf("%s/%s/%s", sdk.consumerURL, deadLettersEndpoint, id)
+
+	_, body, sdkerr := sdk.processRequest(http.MethodGet, url, "", nil, nil, http.StatusOK)
+	if sdkerr != nil {
+		return DeadLetter{}, sdkerr
+	}
+
+	var dl DeadLetter
+	if err := json.Unmarshal(body, &dl); err != nil {
+		return DeadLetter{}, errors.NewSDKError(err)
+	}
+
+	return dl, nil
+}
+
+func (sdk mgSDK) ReplayDeadLetter(id string) (DeadLetter, errors.SDKError) {
+	url := fmt.Sprintf("%s/%s/%s/%s", sdk.consumerURL, deadLettersEndpoint, id, replayEndpoint)
+
+	_, body, sdkerr := sdk.processRequest(http.MethodPost, url, "", nil, nil, http.StatusOK)
+	if sdkerr != nil {
+		return DeadLetter{}, sdkerr
+	}
+
+	var dl DeadLetter
+	if err := json.Unmarshal(body, &dl); err != nil {
+		return DeadLetter{}, errors.NewSDKError(err)
+	}
+
+	return dl, nil
+}
+
+func (sdk mgSDK) DeleteDeadLetter(id string) errors.SDKError {
+	url := fmt.Sprintf("%s/%s/%s", sdk.consumerURL, deadLettersEndpoint, id)
+
+	_, _, sdkerr := sdk.processRequest(http.MethodDelete, url, "", nil, nil, http.StatusNoContent)
+
+	return sdkerr
+}
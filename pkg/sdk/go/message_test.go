@@ -27,7 +27,7 @@ import (
 func setupMessages() (*httptest.Server, *authmocks.AuthClient) {
 	auth := new(authmocks.AuthClient)
 	pub := mocks.NewPublisher()
-	handler := adapter.NewHandler(pub, mglog.NewMock(), auth)
+	handler := adapter.NewHandler(pub, mglog.NewMock(), auth, nil)
 
 	mux := api.MakeHandler("")
 	target := httptest.NewServer(mux)
@@ -37,7 +37,8 @@ type ChannelsPage struct {
 
 // MessagesPage contains list of messages in a page with proper metadata.
 type MessagesPage struct {
-	Messages []senml.Message `json:"messages,omitempty"`
+	Messages   []senml.Message `json:"messages,omitempty"`
+	NextCursor string          `json:"next_cursor,omitempty"`
 	pageRes
 }
 
@@ -83,6 +84,11 @@ type SubscriptionPage struct {
 	pageRes
 }
 
+type DeadLetterPage struct {
+	Total       uint64       `json:"total"`
+	DeadLetters []DeadLetter `json:"dead_letters"`
+}
+
 type identifyThingResp struct {
 	ID string `json:"id,omitempty"`
 }
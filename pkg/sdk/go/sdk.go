@@ -98,6 +98,12 @@ type PageMetadata struct {
 	UserID          string   `json:"user_id,omitempty"`
 	DomainID        string   `json:"domain_id,omitempty"`
 	Relation        string   `json:"relation,omitempty"`
+	Aggregation     string   `json:"aggregation,omitempty"`
+	Interval        string   `json:"interval,omitempty"`
+	Cursor          string   `json:"cursor,omitempty"`
+	SkipTotal       bool     `json:"skip_total,omitempty"`
+	Filter          string   `json:"filter,omitempty"`
+	Channels        []string `json:"channels,omitempty"`
 }
 
 // Credentials represent client credentials: it contains
@@ -892,6 +898,14 @@ type SDK interface {
 	//  fmt.Println(err)
 	UpdateBootstrapCerts(id string, clientCert, clientKey, ca string, token string) (BootstrapConfig, errors.SDKError)
 
+	// IssueBootstrapCert issues bootstrap config client certificate by signing
+	// the thing certificate signing request. The config private key is cleared.
+	//
+	// example:
+	//  cfg, _ := sdk.IssueBootstrapCert("id", "csr", "valid", "token")
+	//  fmt.Println(cfg)
+	IssueBootstrapCert(id, csr, valid, token string) (BootstrapConfig, errors.SDKError)
+
 	// UpdateBootstrapConnection updates connections performs update of the channel list corresponding Thing is connected to.
 	//
 	// example:
@@ -952,6 +966,16 @@ type SDK interface {
 	//  fmt.Println(cert)
 	IssueCert(thingID, valid, token string) (Cert, errors.SDKError)
 
+	// IssueCertFromCSR issues a certificate for a thing by signing the PEM
+	// encoded PKCS#10 certificate signing request, so the thing private key
+	// is not generated nor returned by the certs service. The CSR common name
+	// must be the thing ID.
+	//
+	// example:
+	//  cert, _ := sdk.IssueCertFromCSR("thingID", "csr", "valid", "token")
+	//  fmt.Println(cert)
+	IssueCertFromCSR(thingID, csr, valid, token string) (Cert, errors.SDKError)
+
 	// ViewCert returns a certificate given certificate ID
 	//
 	// example:
@@ -959,6 +983,16 @@ type SDK interface {
 	//  fmt.Println(cert)
 	ViewCert(certID, token string) (Cert, errors.SDKError)
 
+	// RenewCert issues a new certificate for the thing of the certificate
+	// with certID. The renewed certificate stays valid until it expires.
+	// Empty valid keeps the validity of the renewed certificate, and
+	// reuseKey issues the new certificate for the renewed certificate key.
+	//
+	// example:
+	//  cert, _ := sdk.RenewCert("certID", "valid", false, "token")
+	//  fmt.Println(cert)
+	RenewCert(certID, valid string, reuseKey bool, token string) (Cert, errors.SDKError)
+
 	// ViewCertByThing retrieves a list of certificates' serial IDs for a given thing ID.
 	//
 	// example:
@@ -1005,6 +1039,36 @@ type SDK interface {
 	//  fmt.Println(err)
 	DeleteSubscription(id, token string) errors.SDKError
 
+	// ListDeadLetters lists messages that failed to be consumed by the
+	// consumer.
+	//
+	// example:
+	//  page, _ := sdk.ListDeadLetters()
+	//  fmt.Println(page)
+	ListDeadLetters() (DeadLetterPage, errors.SDKError)
+
+	// ViewDeadLetter retrieves a dead letter with the provided id.
+	//
+	// example:
+	//  letter, _ := sdk.ViewDeadLetter("id")
+	//  fmt.Println(letter)
+	ViewDeadLetter(id string) (DeadLetter, errors.SDKError)
+
+	// ReplayDeadLetter consumes the dead letter message once again. The dead
+	// letter is removed if the message is consumed successfully.
+	//
+	// example:
+	//  letter, _ := sdk.ReplayDeadLetter("id")
+	//  fmt.Println(letter)
+	ReplayDeadLetter(id string) (DeadLetter, errors.SDKError)
+
+	// DeleteDeadLetter removes a dead letter without consuming it.
+	//
+	// example:
+	//  err := sdk.DeleteDeadLetter("id")
+	//  fmt.Println(err)
+	DeleteDeadLetter(id string) errors.SDKError
+
 	// CreateDomain creates new domain and returns its details.
 	//
 	// example:
@@ -1163,6 +1227,7 @@ type SDK interface {
 type mgSDK struct {
 	bootstrapURL   string
 	certsURL       string
+	consumerURL    string
 	httpAdapterURL string
 	readerURL      string
 	thingsURL      string
@@ -1179,6 +1244,7 @@ type mgSDK struct {
 type Config struct {
 	BootstrapURL   string
 	CertsURL       string
+	ConsumerURL    string
 	HTTPAdapterURL string
 	ReaderURL      string
 	ThingsURL      string
@@ -1196,6 +1262,7 @@ func NewSDK(conf Config) SDK {
 	return &mgSDK{
 		bootstrapURL:   conf.BootstrapURL,
 		certsURL:       conf.CertsURL,
+		consumerURL:    conf.ConsumerURL,
 		httpAdapterURL: conf.HTTPAdapterURL,
 		readerURL:      conf.ReaderURL,
 		thingsURL:      conf.ThingsURL,
@@ -1353,6 +1420,24 @@ func (pm PageMetadata) query() (string, error) {
 	if pm.Relation != "" {
 		q.Add("relation", pm.Relation)
 	}
+	if pm.Aggregation != "" {
+		q.Add("aggregation", pm.Aggregation)
+	}
+	if pm.Interval != "" {
+		q.Add("interval", pm.Interval)
+	}
+	if pm.Cursor != "" {
+		q.Add("cursor", pm.Cursor)
+	}
+	if pm.SkipTotal {
+		q.Add("skip_total", strconv.FormatBool(pm.SkipTotal))
+	}
+	if pm.Filter != "" {
+		q.Add("filter", pm.Filter)
+	}
+	if len(pm.Channels) > 0 {
+		q.Add("channels", strings.Join(pm.Channels, ","))
+	}
 
 	return q.Encode(), nil
 }
@@ -720,6 +720,26 @@ func (_m *SDK) DeleteChannel(id string, token string) errors.SDKError {
 	return r0
 }
 
+// DeleteDeadLetter provides a mock function with given fields: id
+func (_m *SDK) DeleteDeadLetter(id string) errors.SDKError {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDeadLetter")
+	}
+
+	var r0 errors.SDKError
+	if rf, ok := ret.Get(0).(func(string) errors.SDKError); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(errors.SDKError)
+		}
+	}
+
+	return r0
+}
+
 // DeleteGroup provides a mock function with given fields: id, token
 func (_m *SDK) DeleteGroup(id string, token string) errors.SDKError {
 	ret := _m.Called(id, token)
@@ -1414,6 +1434,36 @@ func (_m *SDK) Invitations(pm sdk.PageMetadata, token string) (sdk.InvitationPag
 	return r0, r1
 }
 
+// IssueBootstrapCert provides a mock function with given fields: id, csr, valid, token
+func (_m *SDK) IssueBootstrapCert(id string, csr string, valid string, token string) (sdk.BootstrapConfig, errors.SDKError) {
+	ret := _m.Called(id, csr, valid, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueBootstrapCert")
+	}
+
+	var r0 sdk.BootstrapConfig
+	var r1 errors.SDKError
+	if rf, ok := ret.Get(0).(func(string, string, string, string) (sdk.BootstrapConfig, errors.SDKError)); ok {
+		return rf(id, csr, valid, token)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string, string) sdk.BootstrapConfig); ok {
+		r0 = rf(id, csr, valid, token)
+	} else {
+		r0 = ret.Get(0).(sdk.BootstrapConfig)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string, string) errors.SDKError); ok {
+		r1 = rf(id, csr, valid, token)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errors.SDKError)
+		}
+	}
+
+	return r0, r1
+}
+
 // IssueCert provides a mock function with given fields: thingID, valid, token
 func (_m *SDK) IssueCert(thingID string, valid string, token string) (sdk.Cert, errors.SDKError) {
 	ret := _m.Called(thingID, valid, token)
@@ -1444,6 +1494,36 @@ func (_m *SDK) IssueCert(thingID string, valid string, token string) (sdk.Cert,
 	return r0, r1
 }
 
+// IssueCertFromCSR provides a mock function with given fields: thingID, csr, valid, token
+func (_m *SDK) IssueCertFromCSR(thingID string, csr string, valid string, token string) (sdk.Cert, errors.SDKError) {
+	ret := _m.Called(thingID, csr, valid, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueCertFromCSR")
+	}
+
+	var r0 sdk.Cert
+	var r1 errors.SDKError
+	if rf, ok := ret.Get(0).(func(string, string, string, string) (sdk.Cert, errors.SDKError)); ok {
+		return rf(thingID, csr, valid, token)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string, string) sdk.Cert); ok {
+		r0 = rf(thingID, csr, valid, token)
+	} else {
+		r0 = ret.Get(0).(sdk.Cert)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string, string) errors.SDKError); ok {
+		r1 = rf(thingID, csr, valid, token)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errors.SDKError)
+		}
+	}
+
+	return r0, r1
+}
+
 // ListChannelUserGroups provides a mock function with given fields: channelID, pm, token
 func (_m *SDK) ListChannelUserGroups(channelID string, pm sdk.PageMetadata, token string) (sdk.GroupsPage, errors.SDKError) {
 	ret := _m.Called(channelID, pm, token)
@@ -1504,6 +1584,36 @@ func (_m *SDK) ListChannelUsers(channelID string, pm sdk.PageMetadata, token str
 	return r0, r1
 }
 
+// ListDeadLetters provides a mock function with given fields:
+func (_m *SDK) ListDeadLetters() (sdk.DeadLetterPage, errors.SDKError) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeadLetters")
+	}
+
+	var r0 sdk.DeadLetterPage
+	var r1 errors.SDKError
+	if rf, ok := ret.Get(0).(func() (sdk.DeadLetterPage, errors.SDKError)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() sdk.DeadLetterPage); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(sdk.DeadLetterPage)
+	}
+
+	if rf, ok := ret.Get(1).(func() errors.SDKError); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errors.SDKError)
+		}
+	}
+
+	return r0, r1
+}
+
 // ListDomainUsers provides a mock function with given fields: domainID, pm, token
 func (_m *SDK) ListDomainUsers(domainID string, pm sdk.PageMetadata, token string) (sdk.UsersPage, errors.SDKError) {
 	ret := _m.Called(domainID, pm, token)
@@ -1964,6 +2074,66 @@ func (_m *SDK) RemoveUserGroupFromChannel(channelID string, req sdk.UserGroupsRe
 	return r0
 }
 
+// RenewCert provides a mock function with given fields: certID, valid, reuseKey, token
+func (_m *SDK) RenewCert(certID string, valid string, reuseKey bool, token string) (sdk.Cert, errors.SDKError) {
+	ret := _m.Called(certID, valid, reuseKey, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenewCert")
+	}
+
+	var r0 sdk.Cert
+	var r1 errors.SDKError
+	if rf, ok := ret.Get(0).(func(string, string, bool, string) (sdk.Cert, errors.SDKError)); ok {
+		return rf(certID, valid, reuseKey, token)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, bool, string) sdk.Cert); ok {
+		r0 = rf(certID, valid, reuseKey, token)
+	} else {
+		r0 = ret.Get(0).(sdk.Cert)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, bool, string) errors.SDKError); ok {
+		r1 = rf(certID, valid, reuseKey, token)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errors.SDKError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ReplayDeadLetter provides a mock function with given fields: id
+func (_m *SDK) ReplayDeadLetter(id string) (sdk.DeadLetter, errors.SDKError) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplayDeadLetter")
+	}
+
+	var r0 sdk.DeadLetter
+	var r1 errors.SDKError
+	if rf, ok := ret.Get(0).(func(string) (sdk.DeadLetter, errors.SDKError)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) sdk.DeadLetter); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(sdk.DeadLetter)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) errors.SDKError); ok {
+		r1 = rf(id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errors.SDKError)
+		}
+	}
+
+	return r0, r1
+}
+
 // ResetPassword provides a mock function with given fields: password, confPass, token
 func (_m *SDK) ResetPassword(password string, confPass string, token string) errors.SDKError {
 	ret := _m.Called(password, confPass, token)
@@ -2832,6 +3002,36 @@ func (_m *SDK) ViewCertByThing(thingID string, token string) (sdk.CertSerials, e
 	return r0, r1
 }
 
+// ViewDeadLetter provides a mock function with given fields: id
+func (_m *SDK) ViewDeadLetter(id string) (sdk.DeadLetter, errors.SDKError) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ViewDeadLetter")
+	}
+
+	var r0 sdk.DeadLetter
+	var r1 errors.SDKError
+	if rf, ok := ret.Get(0).(func(string) (sdk.DeadLetter, errors.SDKError)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) sdk.DeadLetter); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(sdk.DeadLetter)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) errors.SDKError); ok {
+		r1 = rf(id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errors.SDKError)
+		}
+	}
+
+	return r0, r1
+}
+
 // ViewSubscription provides a mock function with given fields: id, token
 func (_m *SDK) ViewSubscription(id string, token string) (sdk.Subscription, errors.SDKError) {
 	ret := _m.Called(id, token)
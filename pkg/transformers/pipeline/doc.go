@@ -0,0 +1,6 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pipeline contains the transformer that applies a configurable
+// sequence of steps to messages produced by SenML or JSON transformer.
+package pipeline
@@ -0,0 +1,42 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/pkg/expr"
+)
+
+// ErrInvalidExpression indicates malformed arithmetic expression.
+var ErrInvalidExpression = errors.New("invalid expression")
+
+// exprLang is the language of arithmetic expressions consisting of numbers,
+// names, operators +, -, *, / and parentheses, e.g. (temp - 32) / 1.8. Names
+// that are not valid identifiers are written as quoted strings, e.g.
+// "temp/in".
+var exprLang = expr.Language{
+	Ops:        expr.Arithmetic,
+	QuotedVars: true,
+}
+
+// expression is an arithmetic expression over named values.
+type expression struct {
+	node expr.Node
+}
+
+func parseExpr(s string) (expression, error) {
+	n, err := exprLang.Parse(s)
+	if err != nil {
+		return expression{}, errors.Wrap(ErrInvalidExpression, err)
+	}
+
+	return expression{node: n}, nil
+}
+
+// eval evaluates the expression, reporting false if any of the referenced
+// values is missing.
+func (e expression) eval(vars map[string]float64) (float64, bool) {
+	v, err := exprLang.Eval(e.node, expr.Values(vars))
+	return v, err == nil
+}
@@ -0,0 +1,157 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/pkg/messaging"
+	"github.com/absmach/magistrala/pkg/transformers"
+	mgjson "github.com/absmach/magistrala/pkg/transformers/json"
+	"github.com/absmach/magistrala/pkg/transformers/senml"
+)
+
+// Step types.
+const (
+	// RenameStep renames SenML records or JSON payload fields.
+	RenameStep = "rename"
+	// DropStep removes SenML record attributes or JSON payload fields.
+	DropStep = "drop"
+	// ScaleStep converts the value using factor and offset.
+	ScaleStep = "scale"
+	// DeriveStep adds the value computed from the other values.
+	DeriveStep = "derive"
+	// FilterStep keeps or removes SenML records or JSON payload fields by name.
+	FilterStep = "filter"
+)
+
+var (
+	// ErrInvalidStep indicates invalid pipeline step configuration.
+	ErrInvalidStep = errors.New("invalid pipeline step")
+
+	errUnsupportedMessage = errors.New("unsupported message type")
+)
+
+// StepConfig represents configuration of the pipeline step. Names refer to
+// SenML record names for SenML messages and payload fields for JSON
+// messages. Nested JSON fields are referred to by joining the keys using
+// "/", e.g. "sensor/temp".
+type StepConfig struct {
+	// Type is one of rename, drop, scale, derive and filter.
+	Type string `toml:"type"`
+	// Mapping maps the current to the new names for rename step.
+	Mapping map[string]string `toml:"mapping"`
+	// Fields lists the names used by drop and filter steps. For SenML
+	// messages, drop step removes record attributes: unit, value,
+	// string_value, data_value, bool_value, sum and update_time, and other
+	// names are ignored.
+	Fields []string `toml:"fields"`
+	// Exclude makes filter step remove the listed names instead of keeping them.
+	Exclude bool `toml:"exclude"`
+	// Field is the name of scaled or derived value.
+	Field string `toml:"field"`
+	// Factor and Offset convert the scaled value to Factor * value + Offset.
+	Factor float64 `toml:"factor"`
+	Offset float64 `toml:"offset"`
+	// Expression computes the derived value, e.g. "(temp - 32) / 1.8".
+	Expression string `toml:"expression"`
+	// Unit is set to scaled and derived SenML records, if not empty.
+	Unit string `toml:"unit"`
+}
+
+// step transforms messages produced by the base transformer.
+type step interface {
+	applySenML(msgs []senml.Message) []senml.Message
+	applyJSON(msgs mgjson.Messages) mgjson.Messages
+}
+
+var _ transformers.Transformer = (*pipeline)(nil)
+
+type pipeline struct {
+	base  transformers.Transformer
+	steps []step
+}
+
+// New returns transformer that transforms messages using the base transformer
+// and applies the configured steps to the result in order.
+func New(base transformers.Transformer, cfgs []StepConfig) (transformers.Transformer, error) {
+	if len(cfgs) == 0 {
+		return base, nil
+	}
+
+	steps := make([]step, len(cfgs))
+	for i, cfg := range cfgs {
+		s, err := newStep(cfg)
+		if err != nil {
+			return nil, err
+		}
+		steps[i] = s
+	}
+
+	return &pipeline{
+		base:  base,
+		steps: steps,
+	}, nil
+}
+
+func (p *pipeline) Transform(msg *messaging.Message) (interface{}, error) {
+	res, err := p.base.Transform(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch msgs := res.(type) {
+	case []senml.Message:
+		for _, s := range p.steps {
+			msgs = s.applySenML(msgs)
+		}
+		return msgs, nil
+	case mgjson.Messages:
+		for _, s := range p.steps {
+			msgs = s.applyJSON(msgs)
+		}
+		return msgs, nil
+	default:
+		return nil, errUnsupportedMessage
+	}
+}
+
+func newStep(cfg StepConfig) (step, error) {
+	switch cfg.Type {
+	case RenameStep:
+		if len(cfg.Mapping) == 0 {
+			return nil, errors.Wrap(ErrInvalidStep, errors.New("rename step requires mapping"))
+		}
+		return renameStep{mapping: cfg.Mapping}, nil
+	case DropStep:
+		if len(cfg.Fields) == 0 {
+			return nil, errors.Wrap(ErrInvalidStep, errors.New("drop step requires fields"))
+		}
+		return dropStep{fields: cfg.Fields}, nil
+	case ScaleStep:
+		if cfg.Field == "" || cfg.Factor == 0 {
+			return nil, errors.Wrap(ErrInvalidStep, errors.New("scale step requires field and factor"))
+		}
+		return scaleStep{field: cfg.Field, factor: cfg.Factor, offset: cfg.Offset, unit: cfg.Unit}, nil
+	case DeriveStep:
+		if cfg.Field == "" {
+			return nil, errors.Wrap(ErrInvalidStep, errors.New("derive step requires field"))
+		}
+		e, err := parseExpr(cfg.Expression)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidStep, err)
+		}
+		return deriveStep{field: cfg.Field, expr: e, unit: cfg.Unit}, nil
+	case FilterStep:
+		if len(cfg.Fields) == 0 {
+			return nil, errors.Wrap(ErrInvalidStep, errors.New("filter step requires fields"))
+		}
+		names := make(map[string]bool, len(cfg.Fields))
+		for _, f := range cfg.Fields {
+			names[f] = true
+		}
+		return filterStep{names: names, exclude: cfg.Exclude}, nil
+	default:
+		return nil, errors.Wrap(ErrInvalidStep, errors.New("unknown step type "+cfg.Type))
+	}
+}
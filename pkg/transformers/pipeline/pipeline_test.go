@@ -0,0 +1,271 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/pkg/messaging"
+	mgjson "github.com/absmach/magistrala/pkg/transformers/json"
+	"github.com/absmach/magistrala/pkg/transformers/pipeline"
+	"github.com/absmach/magistrala/pkg/transformers/senml"
+	"github.com/stretchr/testify/assert"
+)
+
+var errTransform = errors.New("failed to transform")
+
+// transformer returns the result of the function, so each call produces
+// messages that are not shared between the test cases.
+type transformer func() (interface{}, error)
+
+func (t transformer) Transform(*messaging.Message) (interface{}, error) {
+	return t()
+}
+
+func value(v float64) *float64 {
+	return &v
+}
+
+func senmlMessages() (interface{}, error) {
+	return []senml.Message{
+		{Channel: "1", Name: "temp", Unit: "F", Time: 1, Value: value(212)},
+		{Channel: "1", Name: "hum", Unit: "%", Time: 1, Value: value(40), UpdateTime: 5},
+		{Channel: "1", Name: "temp", Unit: "F", Time: 2, Value: value(32)},
+	}, nil
+}
+
+func jsonMessages() (interface{}, error) {
+	return mgjson.Messages{
+		Data: []mgjson.Message{
+			{
+				Channel: "1",
+				Payload: map[string]interface{}{
+					"temp":   212.0,
+					"status": "ok",
+					"sensor": map[string]interface{}{"hum": 40.0},
+				},
+			},
+		},
+		Format: "measurements",
+	}, nil
+}
+
+func TestNew(t *testing.T) {
+	base := transformer(senmlMessages)
+
+	cases := []struct {
+		desc string
+		cfgs []pipeline.StepConfig
+		err  error
+	}{
+		{
+			desc: "create pipeline without steps",
+		},
+		{
+			desc: "create pipeline with valid steps",
+			cfgs: []pipeline.StepConfig{
+				{Type: pipeline.RenameStep, Mapping: map[string]string{"temp": "temperature"}},
+				{Type: pipeline.DropStep, Fields: []string{"unit"}},
+				{Type: pipeline.ScaleStep, Field: "temperature", Factor: 0.1},
+				{Type: pipeline.DeriveStep, Field: "heat", Expression: `("temperature" - 32) / 1.8 * -hum`},
+				{Type: pipeline.FilterStep, Fields: []string{"heat"}},
+			},
+		},
+		{
+			desc: "create pipeline with unknown step",
+			cfgs: []pipeline.StepConfig{{Type: "unknown"}},
+			err:  pipeline.ErrInvalidStep,
+		},
+		{
+			desc: "create pipeline with rename step without mapping",
+			cfgs: []pipeline.StepConfig{{Type: pipeline.RenameStep}},
+			err:  pipeline.ErrInvalidStep,
+		},
+		{
+			desc: "create pipeline with scale step without factor",
+			cfgs: []pipeline.StepConfig{{Type: pipeline.ScaleStep, Field: "temp"}},
+			err:  pipeline.ErrInvalidStep,
+		},
+		{
+			desc: "create pipeline with filter step without fields",
+			cfgs: []pipeline.StepConfig{{Type: pipeline.FilterStep}},
+			err:  pipeline.ErrInvalidStep,
+		},
+		{
+			desc: "create pipeline with invalid expression",
+			cfgs: []pipeline.StepConfig{{Type: pipeline.DeriveStep, Field: "heat", Expression: "(temp - 32"}},
+			err:  pipeline.ErrInvalidExpression,
+		},
+		{
+			desc: "create pipeline with empty expression",
+			cfgs: []pipeline.StepConfig{{Type: pipeline.DeriveStep, Field: "heat"}},
+			err:  pipeline.ErrInvalidExpression,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := pipeline.New(base, tc.cfgs)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestTransformSenML(t *testing.T) {
+	cases := []struct {
+		desc string
+		base transformer
+		cfgs []pipeline.StepConfig
+		msgs interface{}
+		err  error
+	}{
+		{
+			desc: "rename records",
+			base: senmlMessages,
+			cfgs: []pipeline.StepConfig{{Type: pipeline.RenameStep, Mapping: map[string]string{"temp": "temperature"}}},
+			msgs: []senml.Message{
+				{Channel: "1", Name: "temperature", Unit: "F", Time: 1, Value: value(212)},
+				{Channel: "1", Name: "hum", Unit: "%", Time: 1, Value: value(40), UpdateTime: 5},
+				{Channel: "1", Name: "temperature", Unit: "F", Time: 2, Value: value(32)},
+			},
+		},
+		{
+			desc: "drop record attributes",
+			base: senmlMessages,
+			cfgs: []pipeline.StepConfig{{Type: pipeline.DropStep, Fields: []string{"unit", "update_time", "unknown"}}},
+			msgs: []senml.Message{
+				{Channel: "1", Name: "temp", Time: 1, Value: value(212)},
+				{Channel: "1", Name: "hum", Time: 1, Value: value(40)},
+				{Channel: "1", Name: "temp", Time: 2, Value: value(32)},
+			},
+		},
+		{
+			desc: "scale and filter records",
+			base: senmlMessages,
+			cfgs: []pipeline.StepConfig{
+				{Type: pipeline.ScaleStep, Field: "temp", Factor: 0.5, Offset: -6, Unit: "dF"},
+				{Type: pipeline.FilterStep, Fields: []string{"temp"}},
+			},
+			msgs: []senml.Message{
+				{Channel: "1", Name: "temp", Unit: "dF", Time: 1, Value: value(100)},
+				{Channel: "1", Name: "temp", Unit: "dF", Time: 2, Value: value(10)},
+			},
+		},
+		{
+			desc: "exclude records",
+			base: senmlMessages,
+			cfgs: []pipeline.StepConfig{{Type: pipeline.FilterStep, Fields: []string{"temp"}, Exclude: true}},
+			msgs: []senml.Message{
+				{Channel: "1", Name: "hum", Unit: "%", Time: 1, Value: value(40), UpdateTime: 5},
+			},
+		},
+		{
+			desc: "derive record from records with the same time",
+			base: senmlMessages,
+			cfgs: []pipeline.StepConfig{{Type: pipeline.DeriveStep, Field: "index", Expression: "temp / 2 + hum", Unit: "idx"}},
+			msgs: []senml.Message{
+				{Channel: "1", Name: "temp", Unit: "F", Time: 1, Value: value(212)},
+				{Channel: "1", Name: "hum", Unit: "%", Time: 1, Value: value(40), UpdateTime: 5},
+				{Channel: "1", Name: "temp", Unit: "F", Time: 2, Value: value(32)},
+				{Channel: "1", Name: "index", Unit: "idx", Time: 1, Value: value(146)},
+			},
+		},
+		{
+			desc: "transform with failing base transformer",
+			base: func() (interface{}, error) { return nil, errTransform },
+			cfgs: []pipeline.StepConfig{{Type: pipeline.FilterStep, Fields: []string{"temp"}}},
+			err:  errTransform,
+		},
+	}
+
+	for _, tc := range cases {
+		tr, err := pipeline.New(tc.base, tc.cfgs)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		msgs, err := tr.Transform(&messaging.Message{})
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.msgs, msgs, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.msgs, msgs))
+	}
+}
+
+func TestTransformJSON(t *testing.T) {
+	cases := []struct {
+		desc    string
+		cfgs    []pipeline.StepConfig
+		payload mgjson.Payload
+	}{
+		{
+			desc: "rename nested fields",
+			cfgs: []pipeline.StepConfig{{Type: pipeline.RenameStep, Mapping: map[string]string{"sensor/hum": "humidity", "temp": "sensor/temp"}}},
+			payload: map[string]interface{}{
+				"status":   "ok",
+				"humidity": 40.0,
+				"sensor":   map[string]interface{}{"temp": 212.0},
+			},
+		},
+		{
+			desc: "drop fields",
+			cfgs: []pipeline.StepConfig{{Type: pipeline.DropStep, Fields: []string{"status", "sensor/hum", "unknown/field"}}},
+			payload: map[string]interface{}{
+				"temp":   212.0,
+				"sensor": map[string]interface{}{},
+			},
+		},
+		{
+			desc: "scale numeric fields",
+			cfgs: []pipeline.StepConfig{
+				{Type: pipeline.ScaleStep, Field: "temp", Factor: 0.5, Offset: -6},
+				{Type: pipeline.ScaleStep, Field: "status", Factor: 2},
+			},
+			payload: map[string]interface{}{
+				"temp":   100.0,
+				"status": "ok",
+				"sensor": map[string]interface{}{"hum": 40.0},
+			},
+		},
+		{
+			desc: "derive field from nested fields",
+			cfgs: []pipeline.StepConfig{{Type: pipeline.DeriveStep, Field: "sensor/index", Expression: `temp / 2 + "sensor/hum"`}},
+			payload: map[string]interface{}{
+				"temp":   212.0,
+				"status": "ok",
+				"sensor": map[string]interface{}{"hum": 40.0, "index": 146.0},
+			},
+		},
+		{
+			desc: "derive field from missing fields",
+			cfgs: []pipeline.StepConfig{{Type: pipeline.DeriveStep, Field: "index", Expression: "temp + pressure"}},
+			payload: map[string]interface{}{
+				"temp":   212.0,
+				"status": "ok",
+				"sensor": map[string]interface{}{"hum": 40.0},
+			},
+		},
+		{
+			desc: "filter fields",
+			cfgs: []pipeline.StepConfig{{Type: pipeline.FilterStep, Fields: []string{"temp", "sensor/hum"}}},
+			payload: map[string]interface{}{
+				"temp":   212.0,
+				"sensor": map[string]interface{}{"hum": 40.0},
+			},
+		},
+		{
+			desc: "exclude fields",
+			cfgs: []pipeline.StepConfig{{Type: pipeline.FilterStep, Fields: []string{"temp", "sensor"}, Exclude: true}},
+			payload: map[string]interface{}{
+				"status": "ok",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tr, err := pipeline.New(transformer(jsonMessages), tc.cfgs)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		res, err := tr.Transform(&messaging.Message{})
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		msgs, ok := res.(mgjson.Messages)
+		assert.True(t, ok, fmt.Sprintf("%s: expected JSON messages got %T", tc.desc, res))
+		assert.Len(t, msgs.Data, 1, fmt.Sprintf("%s: expected 1 message got %d", tc.desc, len(msgs.Data)))
+		assert.Equal(t, tc.payload, msgs.Data[0].Payload, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.payload, msgs.Data[0].Payload))
+	}
+}
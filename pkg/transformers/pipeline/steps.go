@@ -0,0 +1,279 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"strings"
+
+	mgjson "github.com/absmach/magistrala/pkg/transformers/json"
+	"github.com/absmach/magistrala/pkg/transformers/senml"
+)
+
+const sep = "/"
+
+var (
+	_ step = (*renameStep)(nil)
+	_ step = (*dropStep)(nil)
+	_ step = (*scaleStep)(nil)
+	_ step = (*deriveStep)(nil)
+	_ step = (*filterStep)(nil)
+)
+
+type renameStep struct {
+	mapping map[string]string
+}
+
+func (s renameStep) applySenML(msgs []senml.Message) []senml.Message {
+	for i := range msgs {
+		if name, ok := s.mapping[msgs[i].Name]; ok {
+			msgs[i].Name = name
+		}
+	}
+
+	return msgs
+}
+
+func (s renameStep) applyJSON(msgs mgjson.Messages) mgjson.Messages {
+	for _, msg := range msgs.Data {
+		// Remove all the renamed fields first, so the mapping may swap names.
+		vals := make(map[string]interface{}, len(s.mapping))
+		for from := range s.mapping {
+			if val, ok := lookup(msg.Payload, from); ok {
+				vals[from] = val
+				remove(msg.Payload, from)
+			}
+		}
+		for from, val := range vals {
+			store(msg.Payload, s.mapping[from], val)
+		}
+	}
+
+	return msgs
+}
+
+type dropStep struct {
+	fields []string
+}
+
+func (s dropStep) applySenML(msgs []senml.Message) []senml.Message {
+	for i := range msgs {
+		for _, f := range s.fields {
+			switch f {
+			case "unit":
+				msgs[i].Unit = ""
+			case "value":
+				msgs[i].Value = nil
+			case "string_value":
+				msgs[i].StringValue = nil
+			case "data_value":
+				msgs[i].DataValue = nil
+			case "bool_value":
+				msgs[i].BoolValue = nil
+			case "sum":
+				msgs[i].Sum = nil
+			case "update_time":
+				msgs[i].UpdateTime = 0
+			}
+		}
+	}
+
+	return msgs
+}
+
+func (s dropStep) applyJSON(msgs mgjson.Messages) mgjson.Messages {
+	for _, msg := range msgs.Data {
+		for _, f := range s.fields {
+			remove(msg.Payload, f)
+		}
+	}
+
+	return msgs
+}
+
+type scaleStep struct {
+	field  string
+	factor float64
+	offset float64
+	unit   string
+}
+
+func (s scaleStep) applySenML(msgs []senml.Message) []senml.Message {
+	for i := range msgs {
+		if msgs[i].Name != s.field || msgs[i].Value == nil {
+			continue
+		}
+		v := *msgs[i].Value*s.factor + s.offset
+		msgs[i].Value = &v
+		if s.unit != "" {
+			msgs[i].Unit = s.unit
+		}
+	}
+
+	return msgs
+}
+
+func (s scaleStep) applyJSON(msgs mgjson.Messages) mgjson.Messages {
+	for _, msg := range msgs.Data {
+		val, ok := lookup(msg.Payload, s.field)
+		if !ok {
+			continue
+		}
+		// Non-numeric values are left as they are.
+		if v, ok := val.(float64); ok {
+			store(msg.Payload, s.field, v*s.factor+s.offset)
+		}
+	}
+
+	return msgs
+}
+
+type deriveStep struct {
+	field string
+	expr  expression
+	unit  string
+}
+
+// applySenML computes the value for each group of records sharing the same
+// time, appending the derived record to the messages. Records are grouped
+// by time since the pack may contain multiple measurements of the same names.
+func (s deriveStep) applySenML(msgs []senml.Message) []senml.Message {
+	var times []float64
+	groups := make(map[float64]map[string]float64)
+	first := make(map[float64]senml.Message)
+	for _, msg := range msgs {
+		if msg.Value == nil {
+			continue
+		}
+		vars, ok := groups[msg.Time]
+		if !ok {
+			vars = make(map[string]float64)
+			groups[msg.Time] = vars
+			first[msg.Time] = msg
+			times = append(times, msg.Time)
+		}
+		vars[msg.Name] = *msg.Value
+	}
+
+	for _, t := range times {
+		v, ok := s.expr.eval(groups[t])
+		if !ok {
+			continue
+		}
+		m := first[t]
+		msgs = append(msgs, senml.Message{
+			Channel:   m.Channel,
+			Subtopic:  m.Subtopic,
+			Publisher: m.Publisher,
+			Protocol:  m.Protocol,
+			Name:      s.field,
+			Unit:      s.unit,
+			Time:      t,
+			Value:     &v,
+		})
+	}
+
+	return msgs
+}
+
+func (s deriveStep) applyJSON(msgs mgjson.Messages) mgjson.Messages {
+	for _, msg := range msgs.Data {
+		vars := make(map[string]float64)
+		flatten(msg.Payload, "", vars)
+		if v, ok := s.expr.eval(vars); ok {
+			store(msg.Payload, s.field, v)
+		}
+	}
+
+	return msgs
+}
+
+type filterStep struct {
+	names   map[string]bool
+	exclude bool
+}
+
+func (s filterStep) applySenML(msgs []senml.Message) []senml.Message {
+	ret := msgs[:0]
+	for _, msg := range msgs {
+		if s.names[msg.Name] != s.exclude {
+			ret = append(ret, msg)
+		}
+	}
+
+	return ret
+}
+
+func (s filterStep) applyJSON(msgs mgjson.Messages) mgjson.Messages {
+	for i, msg := range msgs.Data {
+		if s.exclude {
+			for name := range s.names {
+				remove(msg.Payload, name)
+			}
+			continue
+		}
+		payload := make(map[string]interface{})
+		for name := range s.names {
+			if val, ok := lookup(msg.Payload, name); ok {
+				store(payload, name, val)
+			}
+		}
+		msgs.Data[i].Payload = payload
+	}
+
+	return msgs
+}
+
+// lookup returns the value of the nested payload field.
+func lookup(payload map[string]interface{}, path string) (interface{}, bool) {
+	keys := strings.Split(path, sep)
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := payload[k].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		payload = next
+	}
+	val, ok := payload[keys[len(keys)-1]]
+
+	return val, ok
+}
+
+// store sets the nested payload field, creating the missing parents.
+func store(payload map[string]interface{}, path string, val interface{}) {
+	keys := strings.Split(path, sep)
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := payload[k].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			payload[k] = next
+		}
+		payload = next
+	}
+	payload[keys[len(keys)-1]] = val
+}
+
+// remove deletes the nested payload field.
+func remove(payload map[string]interface{}, path string) {
+	keys := strings.Split(path, sep)
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := payload[k].(map[string]interface{})
+		if !ok {
+			return
+		}
+		payload = next
+	}
+	delete(payload, keys[len(keys)-1])
+}
+
+// flatten collects numeric payload fields keyed by their paths.
+func flatten(payload map[string]interface{}, prefix string, vars map[string]float64) {
+	for k, v := range payload {
+		switch val := v.(type) {
+		case float64:
+			vars[prefix+k] = val
+		case map[string]interface{}:
+			flatten(val, prefix+k+sep, vars)
+		}
+	}
+}
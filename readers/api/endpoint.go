@@ -20,8 +20,15 @@ func listMessagesEndpoint(svc readers.MessageRepository, uauth magistrala.AuthSe
 			return nil, errors.Wrap(apiutil.ErrValidation, err)
 		}
 
-		if err := authorize(ctx, req, uauth, taauth); err != nil {
-			return nil, errors.Wrap(errors.ErrAuthorization, err)
+		// Messages are read only if all the requested channels are accessible.
+		for _, chanID := range readers.ChannelIDs(req.chanID, req.pageMeta) {
+			if err := authorize(ctx, chanID, req.token, req.key, uauth, taauth); err != nil {
+				return nil, errors.Wrap(errors.ErrAuthorization, err)
+			}
+		}
+
+		if err := parseFilter(req.filter, &req.pageMeta); err != nil {
+			return nil, err
 		}
 
 		page, err := svc.ReadAll(req.chanID, req.pageMeta)
@@ -32,7 +39,36 @@ func listMessagesEndpoint(svc readers.MessageRepository, uauth magistrala.AuthSe
 		return pageRes{
 			PageMetadata: page.PageMetadata,
 			Total:        page.Total,
+			NextCursor:   page.NextCursor,
 			Messages:     page.Messages,
 		}, nil
 	}
 }
+
+func exportMessagesEndpoint(svc readers.MessageRepository, uauth magistrala.AuthServiceClient, taauth magistrala.AuthzServiceClient) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(exportMessagesReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		if err := authorize(ctx, req.chanID, req.token, req.key, uauth, taauth); err != nil {
+			return nil, errors.Wrap(errors.ErrAuthorization, err)
+		}
+
+		if err := parseFilter(req.filter, &req.pageMeta); err != nil {
+			return nil, err
+		}
+
+		iter, err := svc.Iterate(req.chanID, req.pageMeta)
+		if err != nil {
+			return nil, err
+		}
+
+		return exportRes{
+			output: req.output,
+			format: req.pageMeta.Format,
+			iter:   iter,
+		}, nil
+	}
+}
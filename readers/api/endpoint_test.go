@@ -6,8 +6,12 @@ package api_test
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -22,6 +26,7 @@ import (
 	thmocks "github.com/absmach/magistrala/things/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -30,6 +35,7 @@ const (
 	userToken     = "token"
 	email         = "user@example.com"
 	invalid       = "invalid"
+	secsPerDay    = 24 * 60 * 60
 	numOfMessages = 100
 	valueFields   = 5
 	subtopic      = "topic"
@@ -125,6 +131,21 @@ func TestReadAll(t *testing.T) {
 		messages = append(messages, msg)
 	}
 
+	var dailyCount []senml.Message
+	for _, msg := range valueMsgs {
+		day := math.Floor(msg.Time/secsPerDay) * secsPerDay
+		n := len(dailyCount)
+		if n == 0 || dailyCount[n-1].Time != day {
+			dailyCount = append(dailyCount, senml.Message{Channel: chanID, Name: msg.Name, Time: day, Value: new(float64)})
+			n++
+		}
+		*dailyCount[n-1].Value++
+	}
+
+	// Mocked repository cursor is the index of the first message on the page.
+	cursor, err := readers.EncodeCursor(10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error while encoding cursor: %s", err))
+
 	repo := mocks.NewMessageRepository(chanID, fromSenml(messages))
 	auth := new(authmocks.AuthClient)
 	tauth := new(thmocks.ThingAuthzService)
@@ -431,6 +452,106 @@ func TestReadAll(t *testing.T) {
 				Messages: messages[5:15],
 			},
 		},
+		{
+			desc:   "read page with count aggregation as thing",
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=%s&interval=1d", ts.URL, chanID, readers.CountAggregation),
+			key:    thingToken,
+			status: http.StatusOK,
+			res: pageRes{
+				Total:    uint64(len(dailyCount)),
+				Messages: dailyCount,
+			},
+		},
+		{
+			desc:   "read page with filter as thing",
+			url:    fmt.Sprintf("%s/channels/%s/messages?limit=%d&filter=%s", ts.URL, chanID, numOfMessages, url.QueryEscape(fmt.Sprintf(`vb eq true or protocol eq "%s"`, httpProt))),
+			key:    thingToken,
+			status: http.StatusOK,
+			res: pageRes{
+				Total:    uint64(len(boolMsgs) + len(queryMsgs)),
+				Messages: append(append([]senml.Message{}, boolMsgs...), queryMsgs...),
+			},
+		},
+		{
+			desc:   "read page with invalid filter as thing",
+			url:    fmt.Sprintf("%s/channels/%s/messages?filter=%s", ts.URL, chanID, url.QueryEscape(`name gt "name"`)),
+			key:    thingToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read page with filter and json format as thing",
+			url:    fmt.Sprintf("%s/channels/%s/messages?format=json&filter=%s", ts.URL, chanID, url.QueryEscape(`vb eq true`)),
+			key:    thingToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read page with invalid filter and invalid token",
+			url:    fmt.Sprintf("%s/channels/%s/messages?filter=%s", ts.URL, chanID, url.QueryEscape(strings.Repeat("(", 1000))),
+			token:  authmocks.InvalidValue,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "read page of multiple channels as thing",
+			url:    fmt.Sprintf("%s/messages?channels=%s,%s&limit=%d", ts.URL, chanID, testsutil.GenerateUUID(t), numOfMessages),
+			key:    thingToken,
+			status: http.StatusOK,
+			res: pageRes{
+				Total:    uint64(len(messages)),
+				Messages: messages,
+			},
+		},
+		{
+			desc:   "read page of multiple channels without channels as thing",
+			url:    fmt.Sprintf("%s/messages", ts.URL),
+			key:    thingToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read page with cursor as thing",
+			url:    fmt.Sprintf("%s/channels/%s/messages?cursor=%s&limit=10", ts.URL, chanID, cursor),
+			key:    thingToken,
+			status: http.StatusOK,
+			res: pageRes{
+				Total:    uint64(len(messages)),
+				Messages: messages[10:20],
+			},
+		},
+		{
+			desc:   "read page with invalid cursor as thing",
+			url:    fmt.Sprintf("%s/channels/%s/messages?cursor=%s", ts.URL, chanID, invalid),
+			key:    thingToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read page with cursor and aggregation as thing",
+			url:    fmt.Sprintf("%s/channels/%s/messages?cursor=%s&aggregation=%s&interval=1d", ts.URL, chanID, cursor, readers.CountAggregation),
+			key:    thingToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read page with invalid aggregation as thing",
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=%s&interval=1d", ts.URL, chanID, invalid),
+			key:    thingToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read page with aggregation and missing interval as thing",
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=%s", ts.URL, chanID, readers.MaxAggregation),
+			key:    thingToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read page with aggregation and invalid interval as thing",
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=%s&interval=1y", ts.URL, chanID, readers.MaxAggregation),
+			key:    thingToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read page with aggregation and JSON format as thing",
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=%s&interval=1h&format=json", ts.URL, chanID, readers.AvgAggregation),
+			key:    thingToken,
+			status: http.StatusBadRequest,
+		},
 		{
 			desc:   "read page with valid offset and limit as user",
 			url:    fmt.Sprintf("%s/channels/%s/messages?offset=0&limit=10", ts.URL, chanID),
@@ -742,6 +863,114 @@ func TestReadAll(t *testing.T) {
 	}
 }
 
+func TestExport(t *testing.T) {
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+	pubID2 := testsutil.GenerateUUID(t)
+
+	now := time.Now().Unix()
+
+	var messages []senml.Message
+	var queryMsgs []senml.Message
+	for i := 0; i < numOfMessages; i++ {
+		msg := senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Time:      float64(now - int64(i)),
+			Name:      "name",
+			Value:     &v,
+		}
+		if i%valueFields == 0 {
+			msg.Publisher = pubID2
+			queryMsgs = append(queryMsgs, msg)
+		}
+		messages = append(messages, msg)
+	}
+
+	repo := mocks.NewMessageRepository(chanID, fromSenml(messages))
+	auth := new(authmocks.AuthClient)
+	tauth := new(thmocks.ThingAuthzService)
+	ts := newServer(repo, auth, tauth)
+	defer ts.Close()
+
+	cases := []struct {
+		desc        string
+		url         string
+		token       string
+		key         string
+		status      int
+		contentType string
+		lines       int
+	}{
+		{
+			desc:        "export messages as NDJSON as user",
+			url:         fmt.Sprintf("%s/channels/%s/messages/export", ts.URL, chanID),
+			token:       userToken,
+			status:      http.StatusOK,
+			contentType: "application/x-ndjson",
+			lines:       len(messages),
+		},
+		{
+			desc:        "export messages as CSV as thing",
+			url:         fmt.Sprintf("%s/channels/%s/messages/export?output=csv", ts.URL, chanID),
+			key:         thingToken,
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			lines:       len(messages) + 1,
+		},
+		{
+			desc:        "export messages with publisher as user",
+			url:         fmt.Sprintf("%s/channels/%s/messages/export?output=ndjson&publisher=%s", ts.URL, chanID, pubID2),
+			token:       userToken,
+			status:      http.StatusOK,
+			contentType: "application/x-ndjson",
+			lines:       len(queryMsgs),
+		},
+		{
+			desc:   "export messages with invalid output as user",
+			url:    fmt.Sprintf("%s/channels/%s/messages/export?output=xml", ts.URL, chanID),
+			token:  userToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "export messages with invalid comparator as user",
+			url:    fmt.Sprintf("%s/channels/%s/messages/export?v=1&comparator=wrong", ts.URL, chanID),
+			token:  userToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "export messages without credentials",
+			url:    fmt.Sprintf("%s/channels/%s/messages/export", ts.URL, chanID),
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		repoCall := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		repoCall1 := tauth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.token,
+			key:    tc.key,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status == http.StatusOK {
+			assert.Equal(t, tc.contentType, res.Header.Get("Content-Type"), fmt.Sprintf("%s: expected content type %s got %s", tc.desc, tc.contentType, res.Header.Get("Content-Type")))
+			body, err := io.ReadAll(res.Body)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error while reading response body: %s", tc.desc, err))
+			lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+			assert.Equal(t, tc.lines, len(lines), fmt.Sprintf("%s: expected %d lines got %d", tc.desc, tc.lines, len(lines)))
+		}
+		repoCall.Unset()
+		repoCall1.Unset()
+	}
+}
+
 type pageRes struct {
 	readers.PageMetadata
 	Total    uint64          `json:"total"`
@@ -0,0 +1,167 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/pkg/transformers/senml"
+	"github.com/absmach/magistrala/readers"
+)
+
+const (
+	csvOutput         = "csv"
+	ndjsonOutput      = "ndjson"
+	csvContentType    = "text/csv"
+	ndjsonContentType = "application/x-ndjson"
+
+	// flushSize is the number of messages written before
+	// the response is flushed to the client.
+	flushSize = 100
+)
+
+var errUnsupportedMessage = errors.New("unsupported message type")
+
+var (
+	senmlHeader = []string{"channel", "subtopic", "publisher", "protocol", "name", "unit", "time", "update_time", "value", "string_value", "bool_value", "data_value", "sum"}
+	jsonHeader  = []string{"channel", "subtopic", "publisher", "protocol", "created", "payload"}
+)
+
+// encodeExport streams messages from the iterator. Since the response is
+// written using chunked transfer encoding, the status code is already sent
+// once the error occurs during the iteration.
+func encodeExport(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	res := response.(exportRes)
+	defer res.iter.Close()
+
+	contentType := ndjsonContentType
+	if res.output == csvOutput {
+		contentType = csvContentType
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("messages.%s", res.output)))
+	w.WriteHeader(http.StatusOK)
+
+	switch res.output {
+	case csvOutput:
+		return writeCSV(w, res.format, res.iter)
+	default:
+		return writeNDJSON(w, res.iter)
+	}
+}
+
+func writeNDJSON(w http.ResponseWriter, iter readers.MessageIterator) error {
+	enc := json.NewEncoder(w)
+	for n := 1; iter.Next(); n++ {
+		if err := enc.Encode(iter.Message()); err != nil {
+			return err
+		}
+		if n%flushSize == 0 {
+			flush(w)
+		}
+	}
+
+	return iter.Err()
+}
+
+func writeCSV(w http.ResponseWriter, format string, iter readers.MessageIterator) error {
+	cw := csv.NewWriter(w)
+
+	header := jsonHeader
+	if format == "" || format == defFormat {
+		header = senmlHeader
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for n := 1; iter.Next(); n++ {
+		record, err := csvRecord(iter.Message())
+		if err != nil {
+			return err
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+		if n%flushSize == 0 {
+			cw.Flush()
+			flush(w)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+
+	return iter.Err()
+}
+
+func csvRecord(msg readers.Message) ([]string, error) {
+	switch m := msg.(type) {
+	case senml.Message:
+		return []string{
+			m.Channel,
+			m.Subtopic,
+			m.Publisher,
+			m.Protocol,
+			m.Name,
+			m.Unit,
+			formatFloat(&m.Time),
+			formatFloat(&m.UpdateTime),
+			formatFloat(m.Value),
+			formatString(m.StringValue),
+			formatBool(m.BoolValue),
+			formatString(m.DataValue),
+			formatFloat(m.Sum),
+		}, nil
+	case map[string]interface{}:
+		payload, err := json.Marshal(m["payload"])
+		if err != nil {
+			return nil, err
+		}
+		return []string{
+			fmt.Sprint(m["channel"]),
+			fmt.Sprint(m["subtopic"]),
+			fmt.Sprint(m["publisher"]),
+			fmt.Sprint(m["protocol"]),
+			fmt.Sprint(m["created"]),
+			string(payload),
+		}, nil
+	default:
+		return nil, errUnsupportedMessage
+	}
+}
+
+func formatFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+func formatString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+func formatBool(v *bool) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatBool(*v)
+}
+
+func flush(w http.ResponseWriter) {
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -44,6 +44,15 @@ func (lm *loggingMiddleware) ReadAll(chanID string, rpm readers.PageMetadata) (p
 		if rpm.Publisher != "" {
 			args = append(args, slog.String("publisher", rpm.Publisher))
 		}
+		if len(rpm.Channels) > 0 {
+			args = append(args, slog.Any("channels", rpm.Channels))
+		}
+		if rpm.Aggregation != "" {
+			args = append(args, slog.Group("aggregation",
+				slog.String("function", rpm.Aggregation),
+				slog.String("interval", rpm.Interval),
+			))
+		}
 		if err != nil {
 			args = append(args, slog.Any("error", err))
 			lm.logger.Warn("Read all failed to complete successfully", args...)
@@ -54,3 +63,26 @@ func (lm *loggingMiddleware) ReadAll(chanID string, rpm readers.PageMetadata) (p
 
 	return lm.svc.ReadAll(chanID, rpm)
 }
+
+func (lm *loggingMiddleware) Iterate(chanID string, rpm readers.PageMetadata) (iter readers.MessageIterator, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("channel_id", chanID),
+		}
+		if rpm.Subtopic != "" {
+			args = append(args, slog.String("subtopic", rpm.Subtopic))
+		}
+		if rpm.Publisher != "" {
+			args = append(args, slog.String("publisher", rpm.Publisher))
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Iterate messages failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Iterate messages completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.Iterate(chanID, rpm)
+}
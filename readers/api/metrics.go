@@ -37,3 +37,12 @@ func (mm *metricsMiddleware) ReadAll(chanID string, rpm readers.PageMetadata) (r
 
 	return mm.svc.ReadAll(chanID, rpm)
 }
+
+func (mm *metricsMiddleware) Iterate(chanID string, rpm readers.PageMetadata) (readers.MessageIterator, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "iterate").Add(1)
+		mm.latency.With("method", "iterate").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Iterate(chanID, rpm)
+}
@@ -5,6 +5,7 @@ package api
 
 import (
 	"github.com/absmach/magistrala/internal/apiutil"
+	"github.com/absmach/magistrala/pkg/errors"
 	"github.com/absmach/magistrala/readers"
 )
 
@@ -14,6 +15,7 @@ type listMessagesReq struct {
 	chanID   string
 	token    string
 	key      string
+	filter   string
 	pageMeta readers.PageMetadata
 }
 
@@ -22,7 +24,7 @@ func (req listMessagesReq) validate() error {
 		return apiutil.ErrBearerToken
 	}
 
-	if req.chanID == "" {
+	if req.chanID == "" && len(req.pageMeta.Channels) == 0 {
 		return apiutil.ErrMissingID
 	}
 
@@ -30,14 +32,102 @@ func (req listMessagesReq) validate() error {
 		return apiutil.ErrLimitSize
 	}
 
-	if req.pageMeta.Comparator != "" &&
-		req.pageMeta.Comparator != readers.EqualKey &&
-		req.pageMeta.Comparator != readers.LowerThanKey &&
-		req.pageMeta.Comparator != readers.LowerThanEqualKey &&
-		req.pageMeta.Comparator != readers.GreaterThanKey &&
-		req.pageMeta.Comparator != readers.GreaterThanEqualKey {
+	if err := validateComparator(req.pageMeta.Comparator); err != nil {
+		return err
+	}
+
+	if req.pageMeta.Aggregation != "" {
+		if !readers.IsValidAggregation(req.pageMeta.Aggregation) {
+			return apiutil.ErrInvalidAggregation
+		}
+		// Aggregation is applied on numeric values of SenML messages only.
+		if req.pageMeta.Format != defFormat {
+			return apiutil.ErrInvalidAggregation
+		}
+		if _, err := readers.ParseInterval(req.pageMeta.Interval); err != nil {
+			return apiutil.ErrInvalidInterval
+		}
+	}
+
+	if err := validateFilter(req.filter, req.pageMeta); err != nil {
+		return err
+	}
+
+	if req.pageMeta.Cursor != "" {
+		// Cursor points to the last SenML message of the previous page,
+		// so it can't be used for the aggregated or JSON messages.
+		if req.pageMeta.Aggregation != "" || req.pageMeta.Format != defFormat {
+			return apiutil.ErrInvalidCursor
+		}
+	}
+
+	return nil
+}
+
+type exportMessagesReq struct {
+	chanID   string
+	token    string
+	key      string
+	output   string
+	filter   string
+	pageMeta readers.PageMetadata
+}
+
+func (req exportMessagesReq) validate() error {
+	if req.token == "" && req.key == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.chanID == "" {
+		return apiutil.ErrMissingID
+	}
+
+	if req.output != csvOutput && req.output != ndjsonOutput {
+		return apiutil.ErrInvalidExportOutput
+	}
+
+	if err := validateFilter(req.filter, req.pageMeta); err != nil {
+		return err
+	}
+
+	return validateComparator(req.pageMeta.Comparator)
+}
+
+func validateComparator(comparator string) error {
+	if comparator != "" &&
+		comparator != readers.EqualKey &&
+		comparator != readers.LowerThanKey &&
+		comparator != readers.LowerThanEqualKey &&
+		comparator != readers.GreaterThanKey &&
+		comparator != readers.GreaterThanEqualKey {
 		return apiutil.ErrInvalidComparator
 	}
 
 	return nil
 }
+
+// validateFilter checks that the filter expression is applied to SenML
+// messages, since it refers to SenML message fields.
+func validateFilter(filter string, pm readers.PageMetadata) error {
+	if filter != "" && pm.Format != defFormat {
+		return apiutil.ErrInvalidFilter
+	}
+
+	return nil
+}
+
+// parseFilter parses the filter expression into the page metadata. It is
+// called once the request is authorized, so the anonymous requests can't make
+// the service parse arbitrary expressions.
+func parseFilter(filter string, pm *readers.PageMetadata) error {
+	if filter == "" {
+		return nil
+	}
+	f, err := readers.ParseFilter(filter)
+	if err != nil {
+		return errors.Wrap(apiutil.ErrValidation, errors.Wrap(apiutil.ErrInvalidFilter, err))
+	}
+	pm.Filter = &f
+
+	return nil
+}
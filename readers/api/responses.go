@@ -14,8 +14,9 @@ var _ magistrala.Response = (*pageRes)(nil)
 
 type pageRes struct {
 	readers.PageMetadata
-	Total    uint64            `json:"total"`
-	Messages []readers.Message `json:"messages,omitempty"`
+	Total      uint64            `json:"total"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	Messages   []readers.Message `json:"messages,omitempty"`
 }
 
 func (res pageRes) Headers() map[string]string {
@@ -29,3 +30,9 @@ func (res pageRes) Code() int {
 func (res pageRes) Empty() bool {
 	return false
 }
+
+type exportRes struct {
+	output string
+	format string
+	iter   readers.MessageIterator
+}
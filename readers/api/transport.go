@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strings"
 
 	"github.com/absmach/magistrala"
 	"github.com/absmach/magistrala/internal/apiutil"
@@ -36,6 +37,13 @@ const (
 	comparatorKey  = "comparator"
 	fromKey        = "from"
 	toKey          = "to"
+	aggregationKey = "aggregation"
+	intervalKey    = "interval"
+	outputKey      = "output"
+	cursorKey      = "cursor"
+	skipTotalKey   = "skip_total"
+	channelsKey    = "channels"
+	filterKey      = "filter"
 	defLimit       = 10
 	defOffset      = 0
 	defFormat      = "messages"
@@ -64,6 +72,20 @@ func MakeHandler(svc readers.MessageRepository, uauth magistrala.AuthServiceClie
 		opts...,
 	).ServeHTTP)
 
+	mux.Get("/messages", kithttp.NewServer(
+		listMessagesEndpoint(svc, uauth, taauth),
+		decodeList,
+		encodeResponse,
+		opts...,
+	).ServeHTTP)
+
+	mux.Get("/channels/{chanID}/messages/export", kithttp.NewServer(
+		exportMessagesEndpoint(svc, uauth, taauth),
+		decodeExport,
+		encodeExport,
+		opts...,
+	).ServeHTTP)
+
 	mux.Get("/health", magistrala.Health(svcName, instanceID))
 	mux.Handle("/metrics", promhttp.Handler())
 
@@ -81,88 +103,165 @@ func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 		return nil, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
-	format, err := apiutil.ReadStringQuery(r, formatKey, defFormat)
+	channels, err := apiutil.ReadStringQuery(r, channelsKey, "")
 	if err != nil {
 		return nil, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
-	subtopic, err := apiutil.ReadStringQuery(r, subtopicKey, "")
+	filter, err := apiutil.ReadStringQuery(r, filterKey, "")
 	if err != nil {
 		return nil, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
-	publisher, err := apiutil.ReadStringQuery(r, publisherKey, "")
+	pm, err := decodePageMeta(r)
+	if err != nil {
+		return nil, err
+	}
+	pm.Offset = offset
+	pm.Limit = limit
+	for _, id := range strings.Split(channels, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			pm.Channels = append(pm.Channels, id)
+		}
+	}
+
+	req := listMessagesReq{
+		chanID:   chi.URLParam(r, "chanID"),
+		token:    apiutil.ExtractBearerToken(r),
+		key:      apiutil.ExtractThingKey(r),
+		filter:   filter,
+		pageMeta: pm,
+	}
+	return req, nil
+}
+
+func decodeExport(_ context.Context, r *http.Request) (interface{}, error) {
+	output, err := apiutil.ReadStringQuery(r, outputKey, ndjsonOutput)
 	if err != nil {
 		return nil, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
-	protocol, err := apiutil.ReadStringQuery(r, protocolKey, "")
+	filter, err := apiutil.ReadStringQuery(r, filterKey, "")
 	if err != nil {
 		return nil, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
+	pm, err := decodePageMeta(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := exportMessagesReq{
+		chanID:   chi.URLParam(r, "chanID"),
+		token:    apiutil.ExtractBearerToken(r),
+		key:      apiutil.ExtractThingKey(r),
+		output:   output,
+		filter:   filter,
+		pageMeta: pm,
+	}
+	return req, nil
+}
+
+func decodePageMeta(r *http.Request) (readers.PageMetadata, error) {
+	format, err := apiutil.ReadStringQuery(r, formatKey, defFormat)
+	if err != nil {
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	subtopic, err := apiutil.ReadStringQuery(r, subtopicKey, "")
+	if err != nil {
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	publisher, err := apiutil.ReadStringQuery(r, publisherKey, "")
+	if err != nil {
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	protocol, err := apiutil.ReadStringQuery(r, protocolKey, "")
+	if err != nil {
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
 	name, err := apiutil.ReadStringQuery(r, nameKey, "")
 	if err != nil {
-		return nil, errors.Wrap(apiutil.ErrValidation, err)
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
 	v, err := apiutil.ReadNumQuery[float64](r, valueKey, 0)
 	if err != nil {
-		return nil, errors.Wrap(apiutil.ErrValidation, err)
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
 	comparator, err := apiutil.ReadStringQuery(r, comparatorKey, "")
 	if err != nil {
-		return nil, errors.Wrap(apiutil.ErrValidation, err)
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
 	vs, err := apiutil.ReadStringQuery(r, stringValueKey, "")
 	if err != nil {
-		return nil, errors.Wrap(apiutil.ErrValidation, err)
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
 	vd, err := apiutil.ReadStringQuery(r, dataValueKey, "")
 	if err != nil {
-		return nil, errors.Wrap(apiutil.ErrValidation, err)
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
 	vb, err := apiutil.ReadBoolQuery(r, boolValueKey, false)
 	if err != nil && err != apiutil.ErrNotFoundParam {
-		return nil, err
+		return readers.PageMetadata{}, err
 	}
 
 	from, err := apiutil.ReadNumQuery[float64](r, fromKey, 0)
 	if err != nil {
-		return nil, errors.Wrap(apiutil.ErrValidation, err)
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
 	to, err := apiutil.ReadNumQuery[float64](r, toKey, 0)
 	if err != nil {
-		return nil, errors.Wrap(apiutil.ErrValidation, err)
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
-	req := listMessagesReq{
-		chanID: chi.URLParam(r, "chanID"),
-		token:  apiutil.ExtractBearerToken(r),
-		key:    apiutil.ExtractThingKey(r),
-		pageMeta: readers.PageMetadata{
-			Offset:      offset,
-			Limit:       limit,
-			Format:      format,
-			Subtopic:    subtopic,
-			Publisher:   publisher,
-			Protocol:    protocol,
-			Name:        name,
-			Value:       v,
-			Comparator:  comparator,
-			StringValue: vs,
-			DataValue:   vd,
-			BoolValue:   vb,
-			From:        from,
-			To:          to,
-		},
+	aggregation, err := apiutil.ReadStringQuery(r, aggregationKey, "")
+	if err != nil {
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
-	return req, nil
+
+	interval, err := apiutil.ReadStringQuery(r, intervalKey, "")
+	if err != nil {
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	cursor, err := apiutil.ReadStringQuery(r, cursorKey, "")
+	if err != nil {
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	skipTotal, err := apiutil.ReadBoolQuery(r, skipTotalKey, false)
+	if err != nil {
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	pm := readers.PageMetadata{
+		Format:      format,
+		Subtopic:    subtopic,
+		Publisher:   publisher,
+		Protocol:    protocol,
+		Name:        name,
+		Value:       v,
+		Comparator:  comparator,
+		StringValue: vs,
+		DataValue:   vd,
+		BoolValue:   vb,
+		From:        from,
+		To:          to,
+		Aggregation: aggregation,
+		Interval:    interval,
+		Cursor:      cursor,
+		SkipTotal:   skipTotal,
+	}
+	return pm, nil
 }
 
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
@@ -196,7 +295,13 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 		errors.Contains(err, apiutil.ErrMissingID),
 		errors.Contains(err, apiutil.ErrLimitSize),
 		errors.Contains(err, apiutil.ErrOffsetSize),
-		errors.Contains(err, apiutil.ErrInvalidComparator):
+		errors.Contains(err, apiutil.ErrInvalidComparator),
+		errors.Contains(err, apiutil.ErrInvalidAggregation),
+		errors.Contains(err, apiutil.ErrInvalidInterval),
+		errors.Contains(err, apiutil.ErrInvalidExportOutput),
+		errors.Contains(err, apiutil.ErrInvalidCursor),
+		errors.Contains(err, readers.ErrInvalidCursor),
+		errors.Contains(err, apiutil.ErrInvalidFilter):
 		w.WriteHeader(http.StatusBadRequest)
 	case errors.Contains(err, svcerr.ErrAuthentication),
 		errors.Contains(err, svcerr.ErrAuthorization),
@@ -219,16 +324,16 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	}
 }
 
-func authorize(ctx context.Context, req listMessagesReq, uauth magistrala.AuthServiceClient, taauth magistrala.AuthzServiceClient) (err error) {
+func authorize(ctx context.Context, chanID, token, key string, uauth magistrala.AuthServiceClient, taauth magistrala.AuthzServiceClient) (err error) {
 	switch {
-	case req.token != "":
+	case token != "":
 		if _, err = uauth.Authorize(ctx, &magistrala.AuthorizeReq{
 			SubjectType: userType,
 			SubjectKind: tokenKind,
-			Subject:     req.token,
+			Subject:     token,
 			Permission:  viewPermission,
 			ObjectType:  groupType,
-			Object:      req.chanID,
+			Object:      chanID,
 		}); err != nil {
 			e, ok := status.FromError(err)
 			if ok && e.Code() == codes.PermissionDenied {
@@ -237,12 +342,12 @@ func authorize(ctx context.Context, req listMessagesReq, uauth magistrala.AuthSe
 			return err
 		}
 		return nil
-	case req.key != "":
+	case key != "":
 		if _, err = taauth.Authorize(ctx, &magistrala.AuthorizeReq{
 			SubjectType: groupType,
-			Subject:     req.key,
+			Subject:     key,
 			ObjectType:  thingType,
-			Object:      req.chanID,
+			Object:      chanID,
 			Permission:  subscribePermission,
 		}); err != nil {
 			e, ok := status.FromError(err)
@@ -6,6 +6,8 @@ package cassandra
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
 
 	"github.com/absmach/magistrala/pkg/errors"
 	"github.com/absmach/magistrala/pkg/transformers/senml"
@@ -19,10 +21,15 @@ const (
 
 	// Error code for Undefined table error.
 	undefinedTableCode = 8704
+
+	// Number of rows read at once when messages are filtered in-process.
+	filterPageSize = 1000
 )
 
 var _ readers.MessageRepository = (*cassandraRepository)(nil)
 
+var errInvalidAggregation = errors.New("invalid aggregation")
+
 type cassandraRepository struct {
 	session *gocql.Session
 }
@@ -42,19 +49,37 @@ func (cr cassandraRepository) ReadAll(chanID string, rpm readers.PageMetadata) (
 
 	q, vals := buildQuery(chanID, rpm)
 
+	if rpm.Aggregation != "" {
+		return cr.readAggregated(rpm, q, vals[:len(vals)-1])
+	}
+	if rpm.Filter != nil && format == defTable {
+		return cr.readFiltered(rpm, q, vals[:len(vals)-1])
+	}
+
+	// SenML messages are read using Cassandra paging, so the page state
+	// of the query is used as the cursor of the next page.
 	selectCQL := fmt.Sprintf(`SELECT channel, subtopic, publisher, protocol, name, unit,
 		value, string_value, bool_value, data_value, sum, time,
-		update_time FROM messages WHERE channel = ? %s LIMIT ?
-		ALLOW FILTERING`, q)
-	countCQL := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE channel = ? %s ALLOW FILTERING`, format, q)
+		update_time FROM messages WHERE %s ALLOW FILTERING`, q)
+	countCQL := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s ALLOW FILTERING`, format, q)
+	query := cr.session.Query(selectCQL, vals[:len(vals)-1]...).PageSize(int(rpm.Offset + rpm.Limit))
 
 	if format != defTable {
-		selectCQL = fmt.Sprintf(`SELECT channel, subtopic, publisher, protocol, created, payload FROM %s WHERE channel = ? %s LIMIT ?
+		selectCQL = fmt.Sprintf(`SELECT channel, subtopic, publisher, protocol, created, payload FROM %s WHERE %s LIMIT ?
 			ALLOW FILTERING`, format, q)
-		countCQL = fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE channel = ? %s ALLOW FILTERING`, format, q)
+		countCQL = fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s ALLOW FILTERING`, format, q)
+		query = cr.session.Query(selectCQL, vals...)
 	}
 
-	iter := cr.session.Query(selectCQL, vals...).Iter()
+	if rpm.Cursor != "" {
+		var pos position
+		if err := readers.DecodeCursor(rpm.Cursor, &pos); err != nil || format != defTable {
+			return readers.MessagesPage{}, readers.ErrInvalidCursor
+		}
+		query = query.PageState(pos.PageState)
+	}
+
+	iter := query.Iter()
 	defer iter.Close()
 	scanner := iter.Scanner()
 
@@ -72,11 +97,9 @@ func (cr cassandraRepository) ReadAll(chanID string, rpm readers.PageMetadata) (
 
 	switch format {
 	case defTable:
-		for scanner.Next() {
-			var msg senml.Message
-			if err := scanner.Scan(&msg.Channel, &msg.Subtopic, &msg.Publisher, &msg.Protocol,
-				&msg.Name, &msg.Unit, &msg.Value, &msg.StringValue, &msg.BoolValue,
-				&msg.DataValue, &msg.Sum, &msg.Time, &msg.UpdateTime); err != nil {
+		for uint64(len(page.Messages)) < rpm.Limit && scanner.Next() {
+			msg, err := scanSenml(scanner)
+			if err != nil {
 				if e, ok := err.(gocql.RequestError); ok {
 					if e.Code() == undefinedTableCode {
 						return readers.MessagesPage{}, nil
@@ -86,6 +109,13 @@ func (cr cassandraRepository) ReadAll(chanID string, rpm readers.PageMetadata) (
 			}
 			page.Messages = append(page.Messages, msg)
 		}
+		if state := iter.PageState(); uint64(len(page.Messages)) == rpm.Limit && len(state) > 0 {
+			cursor, err := readers.EncodeCursor(position{PageState: state})
+			if err != nil {
+				return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+			}
+			page.NextCursor = cursor
+		}
 	default:
 		for scanner.Next() {
 			var msg jsonMessage
@@ -105,6 +135,10 @@ func (cr cassandraRepository) ReadAll(chanID string, rpm readers.PageMetadata) (
 		}
 	}
 
+	if rpm.SkipTotal {
+		return page, nil
+	}
+
 	if err := cr.session.Query(countCQL, vals[:len(vals)-1]...).Scan(&page.Total); err != nil {
 		if e, ok := err.(gocql.RequestError); ok {
 			if e.Code() == undefinedTableCode {
@@ -117,9 +151,217 @@ func (cr cassandraRepository) ReadAll(chanID string, rpm readers.PageMetadata) (
 	return page, nil
 }
 
+// readFiltered reads SenML messages that match the filter expression. Since
+// CQL doesn't support disjunctions nor prefix matching, the filter is evaluated
+// in-process and messages are read page by page. Cursor points to the last
+// message as the state of its page and the number of rows read from the page.
+func (cr cassandraRepository) readFiltered(rpm readers.PageMetadata, q string, vals []interface{}) (readers.MessagesPage, error) {
+	var pos position
+	if rpm.Cursor != "" {
+		if err := readers.DecodeCursor(rpm.Cursor, &pos); err != nil {
+			return readers.MessagesPage{}, readers.ErrInvalidCursor
+		}
+	}
+
+	selectCQL := fmt.Sprintf(`SELECT channel, subtopic, publisher, protocol, name, unit,
+		value, string_value, bool_value, data_value, sum, time,
+		update_time FROM messages WHERE %s ALLOW FILTERING`, q)
+
+	page := readers.MessagesPage{
+		PageMetadata: rpm,
+		Messages:     []readers.Message{},
+	}
+
+	var matched uint64
+	state, skip := pos.PageState, pos.Skip
+	for {
+		iter := cr.session.Query(selectCQL, vals...).PageSize(filterPageSize).PageState(state).Iter()
+		next := iter.PageState()
+		scanner := iter.Scanner()
+		for row := 1; scanner.Next(); row++ {
+			if row <= skip {
+				continue
+			}
+			msg, err := scanSenml(scanner)
+			if err != nil {
+				iter.Close()
+				if e, ok := err.(gocql.RequestError); ok {
+					if e.Code() == undefinedTableCode {
+						return readers.MessagesPage{}, nil
+					}
+				}
+				return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+			}
+			if !rpm.Filter.Match(msg) {
+				continue
+			}
+			if matched++; matched <= rpm.Offset || uint64(len(page.Messages)) == rpm.Limit {
+				continue
+			}
+			page.Messages = append(page.Messages, msg)
+			if uint64(len(page.Messages)) == rpm.Limit {
+				cursor, err := readers.EncodeCursor(position{PageState: state, Skip: row})
+				if err != nil {
+					iter.Close()
+					return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+				}
+				page.NextCursor = cursor
+				if rpm.SkipTotal {
+					break
+				}
+			}
+		}
+		if err := iter.Close(); err != nil {
+			if e, ok := err.(gocql.RequestError); ok {
+				if e.Code() == undefinedTableCode {
+					return readers.MessagesPage{}, nil
+				}
+			}
+			return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+		}
+		// Remaining messages are read only to be counted.
+		if len(next) == 0 || (page.NextCursor != "" && rpm.SkipTotal) {
+			break
+		}
+		state, skip = next, 0
+	}
+
+	if !rpm.SkipTotal {
+		page.Total = matched
+	}
+
+	return page, nil
+}
+
+// position represents Cassandra page state of the next page. In case of
+// the filtered messages, it's the state of the current page along with the
+// number of its rows that are already read.
+type position struct {
+	PageState []byte `json:"page_state"`
+	Skip      int    `json:"skip,omitempty"`
+}
+
+// Iterate scans the messages of the channel in storage order. Messages are
+// partitioned by publisher, so ordering them by time would require loading all
+// the channel messages into memory.
+func (cr cassandraRepository) Iterate(chanID string, rpm readers.PageMetadata) (readers.MessageIterator, error) {
+	format := defTable
+	if rpm.Format != "" {
+		format = rpm.Format
+	}
+
+	q, vals := buildQuery(chanID, rpm)
+
+	selectCQL := fmt.Sprintf(`SELECT channel, subtopic, publisher, protocol, name, unit,
+		value, string_value, bool_value, data_value, sum, time,
+		update_time FROM messages WHERE %s ALLOW FILTERING`, q)
+	if format != defTable {
+		selectCQL = fmt.Sprintf(`SELECT channel, subtopic, publisher, protocol, created, payload FROM %s WHERE %s
+			ALLOW FILTERING`, format, q)
+	}
+
+	iter := cr.session.Query(selectCQL, vals[:len(vals)-1]...).Iter()
+
+	return &messageIterator{
+		iter:    iter,
+		scanner: iter.Scanner(),
+		format:  format,
+		filter:  rpm.Filter,
+	}, nil
+}
+
+// readAggregated groups SenML values by name into time buckets of the given
+// interval. Since CQL does not support grouping by arbitrary expressions, the
+// aggregation is calculated in-process over all the messages that match the query.
+func (cr cassandraRepository) readAggregated(rpm readers.PageMetadata, q string, vals []interface{}) (readers.MessagesPage, error) {
+	if !readers.IsValidAggregation(rpm.Aggregation) {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, errInvalidAggregation)
+	}
+	interval, err := readers.ParseInterval(rpm.Interval)
+	if err != nil {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	secs := interval.Seconds()
+
+	selectCQL := fmt.Sprintf(`SELECT channel, subtopic, publisher, protocol, name, unit,
+		value, string_value, bool_value, data_value, sum, time,
+		update_time FROM %s WHERE %s ALLOW FILTERING`, defTable, q)
+	iter := cr.session.Query(selectCQL, vals...).Iter()
+	scanner := iter.Scanner()
+
+	var keys []bucketKey
+	buckets := make(map[bucketKey]*bucket)
+	for scanner.Next() {
+		msg, err := scanSenml(scanner)
+		if err != nil {
+			iter.Close()
+			if e, ok := err.(gocql.RequestError); ok {
+				if e.Code() == undefinedTableCode {
+					return readers.MessagesPage{}, nil
+				}
+			}
+			return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+		}
+		if msg.Value == nil || (rpm.Filter != nil && !rpm.Filter.Match(msg)) {
+			continue
+		}
+		key := bucketKey{
+			channel: msg.Channel,
+			name:    msg.Name,
+			time:    math.Floor(msg.Time/secs) * secs,
+		}
+		b, ok := buckets[key]
+		if !ok {
+			b = &bucket{min: *msg.Value, max: *msg.Value}
+			buckets[key] = b
+			keys = append(keys, key)
+		}
+		b.add(*msg.Value)
+	}
+	if err := iter.Close(); err != nil {
+		if e, ok := err.(gocql.RequestError); ok {
+			if e.Code() == undefinedTableCode {
+				return readers.MessagesPage{}, nil
+			}
+		}
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].time == keys[j].time {
+			return keys[i].name < keys[j].name
+		}
+		return keys[i].time > keys[j].time
+	})
+
+	page := readers.MessagesPage{
+		PageMetadata: rpm,
+		Total:        uint64(len(keys)),
+		Messages:     []readers.Message{},
+	}
+	for i := rpm.Offset; i < rpm.Offset+rpm.Limit && i < uint64(len(keys)); i++ {
+		key := keys[i]
+		val := buckets[key].value(rpm.Aggregation)
+		page.Messages = append(page.Messages, senml.Message{
+			Channel: key.channel,
+			Name:    key.name,
+			Time:    key.time,
+			Value:   &val,
+		})
+	}
+
+	return page, nil
+}
+
 func buildQuery(chanID string, rpm readers.PageMetadata) (string, []interface{}) {
-	var condCQL string
+	condCQL := `channel = ?`
 	vals := []interface{}{chanID}
+	if ids := readers.ChannelIDs(chanID, rpm); len(ids) > 1 {
+		condCQL = `channel IN ?`
+		vals = []interface{}{ids}
+	} else if len(ids) == 1 {
+		vals = []interface{}{ids[0]}
+	}
 
 	var query map[string]interface{}
 	meta, err := json.Marshal(rpm)
@@ -166,6 +408,114 @@ func buildQuery(chanID string, rpm readers.PageMetadata) (string, []interface{})
 	return condCQL, vals
 }
 
+type messageIterator struct {
+	iter    *gocql.Iter
+	scanner gocql.Scanner
+	format  string
+	filter  *readers.Filter
+	current readers.Message
+	err     error
+}
+
+func (it *messageIterator) Next() bool {
+	for it.err == nil && it.scanner.Next() {
+		switch it.format {
+		case defTable:
+			msg, err := scanSenml(it.scanner)
+			if err != nil {
+				it.err = errors.Wrap(readers.ErrReadMessages, err)
+				return false
+			}
+			if it.filter != nil && !it.filter.Match(msg) {
+				continue
+			}
+			it.current = msg
+		default:
+			var msg jsonMessage
+			if err := it.scanner.Scan(&msg.Channel, &msg.Subtopic, &msg.Publisher, &msg.Protocol, &msg.Created, &msg.Payload); err != nil {
+				it.err = errors.Wrap(readers.ErrReadMessages, err)
+				return false
+			}
+			m, err := msg.toMap()
+			if err != nil {
+				it.err = errors.Wrap(readers.ErrReadMessages, err)
+				return false
+			}
+			it.current = m
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func (it *messageIterator) Message() readers.Message {
+	return it.current
+}
+
+func (it *messageIterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	if err := it.scanner.Err(); err != nil {
+		if e, ok := err.(gocql.RequestError); ok && e.Code() == undefinedTableCode {
+			return nil
+		}
+		return errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	return nil
+}
+
+func (it *messageIterator) Close() error {
+	return it.iter.Close()
+}
+
+func scanSenml(scanner gocql.Scanner) (senml.Message, error) {
+	var msg senml.Message
+	err := scanner.Scan(&msg.Channel, &msg.Subtopic, &msg.Publisher, &msg.Protocol,
+		&msg.Name, &msg.Unit, &msg.Value, &msg.StringValue, &msg.BoolValue,
+		&msg.DataValue, &msg.Sum, &msg.Time, &msg.UpdateTime)
+
+	return msg, err
+}
+
+type bucketKey struct {
+	channel string
+	name    string
+	time    float64
+}
+
+type bucket struct {
+	min   float64
+	max   float64
+	sum   float64
+	count uint64
+}
+
+func (b *bucket) add(val float64) {
+	b.min = math.Min(b.min, val)
+	b.max = math.Max(b.max, val)
+	b.sum += val
+	b.count++
+}
+
+func (b *bucket) value(aggregation string) float64 {
+	switch aggregation {
+	case readers.MinAggregation:
+		return b.min
+	case readers.MaxAggregation:
+		return b.max
+	case readers.AvgAggregation:
+		return b.sum / float64(b.count)
+	case readers.CountAggregation:
+		return float64(b.count)
+	default:
+		return b.sum
+	}
+}
+
 type jsonMessage struct {
 	ID        string
 	Channel   string
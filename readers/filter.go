@@ -0,0 +1,321 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package readers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/absmach/magistrala/pkg/expr"
+	"github.com/absmach/magistrala/pkg/transformers/senml"
+)
+
+const (
+	// AndOperator represents the logical conjunction of the filters.
+	AndOperator = "and"
+	// OrOperator represents the logical disjunction of the filters.
+	OrOperator = "or"
+	// NotEqualKey represents the not-equal comparison operator key.
+	NotEqualKey = "ne"
+	// InKey represents the operator key that matches any of the listed values.
+	InKey = "in"
+	// PrefixKey represents the string prefix match operator key.
+	PrefixKey = "prefix"
+)
+
+// maxFilterConditions limits the number of comparisons in a single filter
+// expression, since each of them is translated into a database condition.
+const maxFilterConditions = 32
+
+// ErrInvalidFilter indicates malformed filter expression.
+var ErrInvalidFilter = errors.New("invalid filter expression")
+
+type fieldType int
+
+const (
+	stringField fieldType = iota
+	numberField
+	boolField
+)
+
+// filterFields maps the fields used in filter expressions to the stored SenML
+// message fields. Value fields are named the same as the query parameters.
+var filterFields = map[string]struct {
+	name string
+	typ  fieldType
+}{
+	"subtopic":  {"subtopic", stringField},
+	"publisher": {"publisher", stringField},
+	"protocol":  {"protocol", stringField},
+	"name":      {"name", stringField},
+	"unit":      {"unit", stringField},
+	"time":      {"time", numberField},
+	"v":         {"value", numberField},
+	"vs":        {"string_value", stringField},
+	"vd":        {"data_value", stringField},
+	"vb":        {"bool_value", boolField},
+	"sum":       {"sum", numberField},
+}
+
+// Filter represents boolean expression over SenML message fields. Filter is
+// either a logical operator combining the nested filters, or a comparison
+// of the message field with the given values. Values are strings, float64
+// numbers or booleans, depending on the field type.
+type Filter struct {
+	Operator string        `json:"operator"`
+	Field    string        `json:"field,omitempty"`
+	Values   []interface{} `json:"values,omitempty"`
+	Filters  []Filter      `json:"filters,omitempty"`
+}
+
+// filterLang is the language of the filter expressions. Operators are only
+// parsed, since filters are translated into the database conditions.
+var filterLang = expr.Language{
+	Ops: map[string]expr.Op{
+		OrOperator:          {Prec: 1},
+		AndOperator:         {Prec: 2},
+		EqualKey:            {Prec: 3},
+		NotEqualKey:         {Prec: 3},
+		LowerThanKey:        {Prec: 3},
+		LowerThanEqualKey:   {Prec: 3},
+		GreaterThanKey:      {Prec: 3},
+		GreaterThanEqualKey: {Prec: 3},
+		InKey:               {Prec: 3},
+		PrefixKey:           {Prec: 3},
+	},
+	Strings: true,
+	Lists:   true,
+}
+
+// ParseFilter parses filter expression such as:
+//
+//	(name eq "temp" and v gt 30) or (name eq "humidity" and v lt 10)
+//
+// Comparisons consist of the field, operator and value. Supported fields are
+// subtopic, publisher, protocol, name, unit, time, v, vs, vd, vb and sum.
+// Supported operators are eq, ne, lt, le, gt, ge, in and prefix. Operator in
+// takes a parenthesized list of values, e.g. name in ("temp", "humidity").
+// Comparisons are combined using and/or and grouped using parentheses.
+func ParseFilter(s string) (Filter, error) {
+	x, err := filterLang.Parse(s)
+	if err != nil {
+		return Filter{}, fmt.Errorf("%w: %s", ErrInvalidFilter, err)
+	}
+
+	var conds int
+	f, err := toFilter(x, &conds)
+	if err != nil {
+		return Filter{}, fmt.Errorf("%w: %s", ErrInvalidFilter, err)
+	}
+
+	return f, nil
+}
+
+// toFilter translates the parsed expression into the filter. Chains of the
+// same logical operator are merged into a single filter.
+func toFilter(x expr.Node, conds *int) (Filter, error) {
+	b, ok := x.(expr.Binary)
+	if !ok {
+		return Filter{}, fmt.Errorf("expected comparison")
+	}
+	if b.Op != AndOperator && b.Op != OrOperator {
+		return toComparison(b, conds)
+	}
+
+	ret := Filter{Operator: b.Op}
+	for _, operand := range []expr.Node{b.L, b.R} {
+		f, err := toFilter(operand, conds)
+		if err != nil {
+			return Filter{}, err
+		}
+		if f.Operator == b.Op {
+			ret.Filters = append(ret.Filters, f.Filters...)
+			continue
+		}
+		ret.Filters = append(ret.Filters, f)
+	}
+
+	return ret, nil
+}
+
+func toComparison(b expr.Binary, conds *int) (Filter, error) {
+	name, ok := b.L.(expr.Var)
+	if !ok {
+		return Filter{}, fmt.Errorf("expected field")
+	}
+	field, ok := filterFields[strings.ToLower(string(name))]
+	if !ok {
+		return Filter{}, fmt.Errorf("unknown field %q", name)
+	}
+	if *conds++; *conds > maxFilterConditions {
+		return Filter{}, fmt.Errorf("more than %d conditions", maxFilterConditions)
+	}
+	if !isValidOperator(b.Op, field.typ) {
+		return Filter{}, fmt.Errorf("invalid operator %q for field %q", b.Op, field.name)
+	}
+
+	values := []expr.Node{b.R}
+	if list, ok := b.R.(expr.List); ok && b.Op == InKey {
+		values = list
+	}
+	f := Filter{
+		Operator: b.Op,
+		Field:    field.name,
+	}
+	for _, x := range values {
+		v, err := toValue(x, field.typ)
+		if err != nil {
+			return Filter{}, err
+		}
+		f.Values = append(f.Values, v)
+	}
+
+	return f, nil
+}
+
+func toValue(x expr.Node, typ fieldType) (interface{}, error) {
+	switch typ {
+	case stringField:
+		if v, ok := x.(expr.Str); ok {
+			return string(v), nil
+		}
+	case boolField:
+		if v, ok := x.(expr.Var); ok {
+			switch strings.ToLower(string(v)) {
+			case "true":
+				return true, nil
+			case "false":
+				return false, nil
+			}
+		}
+	case numberField:
+		switch v := x.(type) {
+		case expr.Num:
+			return float64(v), nil
+		case expr.Unary:
+			if n, ok := v.X.(expr.Num); ok {
+				return -float64(n), nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("invalid value %v", x)
+}
+
+func isValidOperator(op string, typ fieldType) bool {
+	switch op {
+	case EqualKey, NotEqualKey:
+		return true
+	case LowerThanKey, LowerThanEqualKey, GreaterThanKey, GreaterThanEqualKey:
+		return typ == numberField
+	case InKey:
+		return typ != boolField
+	case PrefixKey:
+		return typ == stringField
+	default:
+		return false
+	}
+}
+
+// Match reports whether the SenML message matches the filter. It is used by
+// the repositories that are not able to evaluate the filter in the database.
+// Same as in databases, comparison of the missing message value never matches.
+func (f Filter) Match(msg senml.Message) bool {
+	switch f.Operator {
+	case AndOperator:
+		for _, nested := range f.Filters {
+			if !nested.Match(msg) {
+				return false
+			}
+		}
+		return true
+	case OrOperator:
+		for _, nested := range f.Filters {
+			if nested.Match(msg) {
+				return true
+			}
+		}
+		return false
+	}
+
+	val := fieldValue(msg, f.Field)
+	if val == nil {
+		return false
+	}
+	switch f.Operator {
+	case InKey:
+		for _, v := range f.Values {
+			if val == v {
+				return true
+			}
+		}
+		return false
+	case PrefixKey:
+		s, _ := val.(string)
+		prefix, _ := f.Values[0].(string)
+		return strings.HasPrefix(s, prefix)
+	case EqualKey:
+		return val == f.Values[0]
+	case NotEqualKey:
+		return val != f.Values[0]
+	}
+
+	v, ok := val.(float64)
+	ref, ok2 := f.Values[0].(float64)
+	if !ok || !ok2 {
+		return false
+	}
+	switch f.Operator {
+	case LowerThanKey:
+		return v < ref
+	case LowerThanEqualKey:
+		return v <= ref
+	case GreaterThanKey:
+		return v > ref
+	case GreaterThanEqualKey:
+		return v >= ref
+	default:
+		return false
+	}
+}
+
+func fieldValue(msg senml.Message, field string) interface{} {
+	switch field {
+	case "subtopic":
+		return msg.Subtopic
+	case "publisher":
+		return msg.Publisher
+	case "protocol":
+		return msg.Protocol
+	case "name":
+		return msg.Name
+	case "unit":
+		return msg.Unit
+	case "time":
+		return msg.Time
+	case "value":
+		if msg.Value != nil {
+			return *msg.Value
+		}
+	case "string_value":
+		if msg.StringValue != nil {
+			return *msg.StringValue
+		}
+	case "data_value":
+		if msg.DataValue != nil {
+			return *msg.DataValue
+		}
+	case "bool_value":
+		if msg.BoolValue != nil {
+			return *msg.BoolValue
+		}
+	case "sum":
+		if msg.Sum != nil {
+			return *msg.Sum
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package readers_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/absmach/magistrala/pkg/transformers/senml"
+	"github.com/absmach/magistrala/readers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilter(t *testing.T) {
+	cases := []struct {
+		desc   string
+		expr   string
+		filter readers.Filter
+		err    error
+	}{
+		{
+			desc: "parse comparison",
+			expr: `name eq "temp"`,
+			filter: readers.Filter{
+				Operator: readers.EqualKey,
+				Field:    "name",
+				Values:   []interface{}{"temp"},
+			},
+		},
+		{
+			desc: "parse negative number comparison",
+			expr: `v ge -1.5`,
+			filter: readers.Filter{
+				Operator: readers.GreaterThanEqualKey,
+				Field:    "value",
+				Values:   []interface{}{-1.5},
+			},
+		},
+		{
+			desc: "parse in list",
+			expr: `name in ("temp", "humidity")`,
+			filter: readers.Filter{
+				Operator: readers.InKey,
+				Field:    "name",
+				Values:   []interface{}{"temp", "humidity"},
+			},
+		},
+		{
+			desc: "parse prefix",
+			expr: `subtopic prefix "devices/"`,
+			filter: readers.Filter{
+				Operator: readers.PrefixKey,
+				Field:    "subtopic",
+				Values:   []interface{}{"devices/"},
+			},
+		},
+		{
+			desc: "parse combined expression",
+			expr: `name eq "temp" AND v gt 30 or (name eq "humidity" and v lt 10) or vb eq true`,
+			filter: readers.Filter{
+				Operator: readers.OrOperator,
+				Filters: []readers.Filter{
+					{
+						Operator: readers.AndOperator,
+						Filters: []readers.Filter{
+							{Operator: readers.EqualKey, Field: "name", Values: []interface{}{"temp"}},
+							{Operator: readers.GreaterThanKey, Field: "value", Values: []interface{}{30.0}},
+						},
+					},
+					{
+						Operator: readers.AndOperator,
+						Filters: []readers.Filter{
+							{Operator: readers.EqualKey, Field: "name", Values: []interface{}{"humidity"}},
+							{Operator: readers.LowerThanKey, Field: "value", Values: []interface{}{10.0}},
+						},
+					},
+					{Operator: readers.EqualKey, Field: "bool_value", Values: []interface{}{true}},
+				},
+			},
+		},
+		{
+			desc: "parse empty expression",
+			expr: "",
+			err:  readers.ErrInvalidFilter,
+		},
+		{
+			desc: "parse unknown field",
+			expr: `channel eq "1"`,
+			err:  readers.ErrInvalidFilter,
+		},
+		{
+			desc: "parse invalid operator for field",
+			expr: `name gt "temp"`,
+			err:  readers.ErrInvalidFilter,
+		},
+		{
+			desc: "parse invalid value type",
+			expr: `v eq "30"`,
+			err:  readers.ErrInvalidFilter,
+		},
+		{
+			desc: "parse unbalanced parentheses",
+			expr: `(name eq "temp" and v gt 30`,
+			err:  readers.ErrInvalidFilter,
+		},
+		{
+			desc: "parse trailing tokens",
+			expr: `name eq "temp" v gt 30`,
+			err:  readers.ErrInvalidFilter,
+		},
+	}
+
+	for _, tc := range cases {
+		filter, err := readers.ParseFilter(tc.expr)
+		assert.True(t, errors.Is(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.filter, filter, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.filter, filter))
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	v := 35.0
+	vs := "on"
+	msg := senml.Message{
+		Subtopic: "devices/1",
+		Name:     "temp",
+		Time:     100,
+		Value:    &v,
+	}
+
+	cases := []struct {
+		desc  string
+		expr  string
+		msg   senml.Message
+		match bool
+	}{
+		{
+			desc:  "match combined expression",
+			expr:  `(name eq "temp" and v gt 30) or (name eq "humidity" and v lt 10)`,
+			msg:   msg,
+			match: true,
+		},
+		{
+			desc:  "match combined expression with not matching value",
+			expr:  `name eq "temp" and v gt 40`,
+			msg:   msg,
+			match: false,
+		},
+		{
+			desc:  "match in list and prefix",
+			expr:  `name in ("humidity", "temp") and subtopic prefix "devices/"`,
+			msg:   msg,
+			match: true,
+		},
+		{
+			desc:  "match time range",
+			expr:  `time ge 100 and time lt 200`,
+			msg:   msg,
+			match: true,
+		},
+		{
+			desc:  "match missing value",
+			expr:  `vs ne "off"`,
+			msg:   msg,
+			match: false,
+		},
+		{
+			desc:  "match string value",
+			expr:  `vs ne "off"`,
+			msg:   senml.Message{StringValue: &vs},
+			match: true,
+		},
+	}
+
+	for _, tc := range cases {
+		filter, err := readers.ParseFilter(tc.expr)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.match, filter.Match(tc.msg), fmt.Sprintf("%s: expected match to be %t", tc.desc, tc.match))
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 	"unicode"
@@ -16,6 +17,7 @@ import (
 	"github.com/absmach/magistrala/pkg/transformers/senml"
 	"github.com/absmach/magistrala/readers"
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api"
 )
 
 const (
@@ -25,12 +27,49 @@ const (
 
 var _ readers.MessageRepository = (*influxRepository)(nil)
 
-var errResultTime = errors.New("invalid result time")
+var (
+	errResultTime         = errors.New("invalid result time")
+	errInvalidAggregation = errors.New("invalid aggregation")
+)
+
+// aggregations maps aggregation keys to Flux aggregate functions.
+var aggregations = map[string]string{
+	readers.MinAggregation:   "min",
+	readers.MaxAggregation:   "max",
+	readers.AvgAggregation:   "mean",
+	readers.CountAggregation: "count",
+	readers.SumAggregation:   "sum",
+}
 
 type RepoConfig struct {
 	Bucket string
 	Org    string
 }
+
+// filterColumns maps SenML message fields to the measurement columns.
+var filterColumns = map[string]string{
+	"subtopic":     "subtopic",
+	"publisher":    "publisher",
+	"protocol":     "protocol",
+	"name":         "name",
+	"unit":         "unit",
+	"value":        "value",
+	"string_value": "stringValue",
+	"data_value":   "dataValue",
+	"bool_value":   "boolValue",
+	"sum":          "sum",
+}
+
+// fluxOperators maps filter comparison keys to Flux operators.
+var fluxOperators = map[string]string{
+	readers.EqualKey:            "==",
+	readers.NotEqualKey:         "!=",
+	readers.LowerThanKey:        "<",
+	readers.LowerThanEqualKey:   "<=",
+	readers.GreaterThanKey:      ">",
+	readers.GreaterThanEqualKey: ">=",
+}
+
 type influxRepository struct {
 	cfg    RepoConfig
 	client influxdb2.Client
@@ -50,9 +89,33 @@ func (repo *influxRepository) ReadAll(chanID string, rpm readers.PageMetadata) (
 		format = rpm.Format
 	}
 
+	if rpm.Aggregation != "" {
+		return repo.readAggregated(chanID, rpm)
+	}
+
 	queryAPI := repo.client.QueryAPI(repo.cfg.Org)
 	condition, timeRange := fmtCondition(chanID, rpm)
 
+	// SenML message position is determined by time and series tags. Since
+	// empty tags are not stored, they are filled in to be comparable.
+	pageCondition := condition
+	sortColumns := `["_time"]`
+	if format == defMeasurement {
+		pageCondition = fmt.Sprintf(`%s
+	|> map(fn: (r) => ({r with publisher: if exists r.publisher then r.publisher else "", subtopic: if exists r.subtopic then r.subtopic else "", name: if exists r.name then r.name else ""}))`, condition)
+		sortColumns = `["_time", "publisher", "subtopic", "name"]`
+	}
+
+	if rpm.Cursor != "" {
+		var pos position
+		if err := readers.DecodeCursor(rpm.Cursor, &pos); err != nil || format != defMeasurement {
+			return readers.MessagesPage{}, readers.ErrInvalidCursor
+		}
+		pageCondition = fmt.Sprintf(`%s
+	|> filter(fn: (r) => r._time < time(v: %d) or (r._time == time(v: %d) and (r.publisher < %q or (r.publisher == %q and (r.subtopic < %q or (r.subtopic == %q and r.name < %q))))))`,
+			pageCondition, pos.Time, pos.Time, pos.Publisher, pos.Publisher, pos.Subtopic, pos.Subtopic, pos.Name)
+	}
+
 	query := fmt.Sprintf(`
 	import "influxdata/influxdb/v1"
 	import "strings"
@@ -62,13 +125,14 @@ func (repo *influxRepository) ReadAll(chanID string, rpm readers.PageMetadata) (
 	|> group()
 	|> filter(fn: (r) => r._measurement == "%s")
 	%s
-	|> sort(columns: ["_time"], desc: true)
+	|> sort(columns: %s, desc: true)
 	|> limit(n:%d,offset:%d)
 	|> yield(name: "sort")`,
 		repo.cfg.Bucket,
 		timeRange,
 		format,
-		condition,
+		pageCondition,
+		sortColumns,
 		rpm.Limit, rpm.Offset,
 	)
 
@@ -91,16 +155,150 @@ func (repo *influxRepository) ReadAll(chanID string, rpm readers.PageMetadata) (
 		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, resp.Err())
 	}
 
+	page := readers.MessagesPage{
+		PageMetadata: rpm,
+		Messages:     messages,
+	}
+
+	if format == defMeasurement && rpm.Limit > 0 && uint64(len(messages)) == rpm.Limit {
+		// Position is taken from the raw record since parsed
+		// message time doesn't keep nanosecond precision.
+		t, ok := valueMap["_time"].(time.Time)
+		if !ok {
+			return readers.MessagesPage{}, errResultTime
+		}
+		pos := position{Time: t.UnixNano()}
+		pos.Publisher, _ = valueMap["publisher"].(string)
+		pos.Subtopic, _ = valueMap["subtopic"].(string)
+		pos.Name, _ = valueMap["name"].(string)
+		if page.NextCursor, err = readers.EncodeCursor(pos); err != nil {
+			return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+		}
+	}
+
+	if rpm.SkipTotal {
+		return page, nil
+	}
+
 	total, err := repo.count(format, condition, timeRange)
 	if err != nil {
 		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
 	}
+	page.Total = total
+
+	return page, nil
+}
+
+func (repo *influxRepository) Iterate(chanID string, rpm readers.PageMetadata) (readers.MessageIterator, error) {
+	format := defMeasurement
+	if rpm.Format != "" {
+		format = rpm.Format
+	}
+
+	condition, timeRange := fmtCondition(chanID, rpm)
+	query := fmt.Sprintf(`
+	import "influxdata/influxdb/v1"
+	import "strings"
+	from(bucket: "%s")
+	%s
+	|> v1.fieldsAsCols()
+	|> group()
+	|> filter(fn: (r) => r._measurement == "%s")
+	%s
+	|> sort(columns: ["_time"])
+	|> yield(name: "sort")`,
+		repo.cfg.Bucket,
+		timeRange,
+		format,
+		condition,
+	)
+
+	queryAPI := repo.client.QueryAPI(repo.cfg.Org)
+	resp, err := queryAPI.Query(context.Background(), query)
+	if err != nil {
+		return nil, errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	return &messageIterator{
+		resp:   resp,
+		format: format,
+	}, nil
+}
+
+// readAggregated groups SenML values by name into time windows of the given
+// interval using Flux aggregateWindow function.
+func (repo *influxRepository) readAggregated(chanID string, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	fn, ok := aggregations[rpm.Aggregation]
+	if !ok {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, errInvalidAggregation)
+	}
+	interval, err := readers.ParseInterval(rpm.Interval)
+	if err != nil {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	condition, timeRange := fmtCondition(chanID, rpm)
+	windows := fmt.Sprintf(`
+	import "influxdata/influxdb/v1"
+	import "strings"
+	from(bucket: "%s")
+	%s
+	|> v1.fieldsAsCols()
+	|> group()
+	|> filter(fn: (r) => r._measurement == "%s")
+	%s
+	|> filter(fn: (r) => exists r.value)
+	|> group(columns: ["channel", "name"])
+	|> aggregateWindow(every: %ds, fn: %s, column: "value", timeSrc: "_start", createEmpty: false)
+	|> group()`,
+		repo.cfg.Bucket,
+		timeRange,
+		defMeasurement,
+		condition,
+		int64(interval.Seconds()),
+		fn,
+	)
+
+	query := fmt.Sprintf(`%s
+	|> sort(columns: ["_time", "name"], desc: true)
+	|> limit(n:%d,offset:%d)
+	|> yield(name: "sort")`, windows, rpm.Limit, rpm.Offset)
+
+	queryAPI := repo.client.QueryAPI(repo.cfg.Org)
+	resp, err := queryAPI.Query(context.Background(), query)
+	if err != nil {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	messages := []readers.Message{}
+	for resp.Next() {
+		msg, err := parseAggregated(resp.Record().Values())
+		if err != nil {
+			return readers.MessagesPage{}, err
+		}
+		messages = append(messages, msg)
+	}
+	if resp.Err() != nil {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, resp.Err())
+	}
+
+	query = fmt.Sprintf(`%s
+	|> count(column: "value")
+	|> yield(name: "count")`, windows)
+	resp, err = queryAPI.Query(context.Background(), query)
+	if err != nil {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
 
 	page := readers.MessagesPage{
 		PageMetadata: rpm,
-		Total:        total,
 		Messages:     messages,
 	}
+	if resp.Next() {
+		if val, ok := resp.Record().Values()["value"].(int64); ok {
+			page.Total = uint64(val)
+		}
+	}
 
 	return page, nil
 }
@@ -147,7 +345,15 @@ func (repo *influxRepository) count(measurement, condition, timeRange string) (u
 func fmtCondition(chanID string, rpm readers.PageMetadata) (string, string) {
 	var timeRange string
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf(`|> filter(fn: (r) => r["channel"] == "%s" )`, chanID))
+	ids := readers.ChannelIDs(chanID, rpm)
+	channels := make([]string, len(ids))
+	for i, id := range ids {
+		channels[i] = fmt.Sprintf(`r["channel"] == "%s"`, id)
+	}
+	sb.WriteString(fmt.Sprintf(`|> filter(fn: (r) => %s )`, strings.Join(channels, " or ")))
+	if rpm.Filter != nil {
+		sb.WriteString(fmt.Sprintf(`|> filter(fn: (r) => %s)`, fmtFilter(*rpm.Filter)))
+	}
 
 	var query map[string]interface{}
 	meta, err := json.Marshal(rpm)
@@ -226,6 +432,63 @@ func fmtCondition(chanID string, rpm readers.PageMetadata) (string, string) {
 	return sb.String(), timeRange
 }
 
+// fmtFilter translates the filter expression into the Flux predicate.
+func fmtFilter(f readers.Filter) string {
+	switch f.Operator {
+	case readers.AndOperator, readers.OrOperator:
+		preds := make([]string, len(f.Filters))
+		for i, nested := range f.Filters {
+			preds[i] = fmtFilter(nested)
+		}
+		return fmt.Sprintf(`(%s)`, strings.Join(preds, fmt.Sprintf(` %s `, f.Operator)))
+	}
+
+	// SenML time is stored as the record time.
+	col := "r._time"
+	if f.Field != "time" {
+		col = fmt.Sprintf("r.%s", filterColumns[f.Field])
+	}
+
+	var pred string
+	switch f.Operator {
+	case readers.InKey:
+		preds := make([]string, len(f.Values))
+		for i, v := range f.Values {
+			preds[i] = fmt.Sprintf(`%s == %s`, col, fluxValue(f.Field, v))
+		}
+		pred = strings.Join(preds, " or ")
+	case readers.PrefixKey:
+		pred = fmt.Sprintf(`strings.hasPrefix(v: %s, prefix: %s)`, col, fluxValue(f.Field, f.Values[0]))
+	default:
+		pred = fmt.Sprintf(`%s %s %s`, col, fluxOperators[f.Operator], fluxValue(f.Field, f.Values[0]))
+	}
+	if f.Field == "time" {
+		return fmt.Sprintf(`(%s)`, pred)
+	}
+
+	return fmt.Sprintf(`(exists %s and (%s))`, col, pred)
+}
+
+// fluxValue formats the filter value as a Flux literal.
+func fluxValue(field string, v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		if field == "time" {
+			return fmt.Sprintf(`time(v: %d)`, int64(v*1e9))
+		}
+		// Float literal must contain the decimal point.
+		lit := strconv.FormatFloat(v, 'f', -1, 64)
+		if !strings.Contains(lit, ".") {
+			lit += ".0"
+		}
+		return lit
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
 func parseMessage(measurement string, valueMap map[string]interface{}) (interface{}, error) {
 	switch measurement {
 	case defMeasurement:
@@ -235,6 +498,74 @@ func parseMessage(measurement string, valueMap map[string]interface{}) (interfac
 	}
 }
 
+// position represents position of the SenML message in the measurement.
+type position struct {
+	Time      int64  `json:"time"`
+	Publisher string `json:"publisher"`
+	Subtopic  string `json:"subtopic"`
+	Name      string `json:"name"`
+}
+
+type messageIterator struct {
+	resp    *api.QueryTableResult
+	format  string
+	current readers.Message
+	err     error
+}
+
+func (it *messageIterator) Next() bool {
+	if it.err != nil || !it.resp.Next() {
+		return false
+	}
+	it.current, it.err = parseMessage(it.format, it.resp.Record().Values())
+
+	return it.err == nil
+}
+
+func (it *messageIterator) Message() readers.Message {
+	return it.current
+}
+
+func (it *messageIterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	if err := it.resp.Err(); err != nil {
+		return errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	return nil
+}
+
+func (it *messageIterator) Close() error {
+	return it.resp.Close()
+}
+
+func parseAggregated(valueMap map[string]interface{}) (senml.Message, error) {
+	t, ok := valueMap["_time"].(time.Time)
+	if !ok {
+		return senml.Message{}, errResultTime
+	}
+	msg := senml.Message{
+		Time: float64(t.UnixNano()) / 1e9,
+	}
+	msg.Channel, _ = valueMap["channel"].(string)
+	msg.Name, _ = valueMap["name"].(string)
+
+	var val float64
+	switch v := valueMap["value"].(type) {
+	case float64:
+		val = v
+	case int64:
+		val = float64(v)
+	case uint64:
+		val = float64(v)
+	}
+	msg.Value = &val
+
+	return msg, nil
+}
+
 func underscore(name string) string {
 	var buff []rune
 	idx := 0
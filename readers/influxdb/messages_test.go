@@ -6,6 +6,7 @@ package influxdb_test
 import (
 	"context"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -21,6 +22,7 @@ import (
 )
 
 const (
+	secsPerDay  = 24 * 60 * 60
 	subtopic    = "topic"
 	msgsNum     = 100
 	limit       = 10
@@ -113,6 +115,8 @@ func TestReadSenml(t *testing.T) {
 
 	reader := ireader.New(client, repoCfg)
 
+	dailyCount := countByDay(valueMsgs)
+
 	cases := []struct {
 		desc     string
 		chanID   string
@@ -546,6 +550,20 @@ func TestReadSenml(t *testing.T) {
 				Messages: fromSenml(messages[0+1 : 5+1]),
 			},
 		},
+		{
+			desc:   "read message with count aggregation",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:      0,
+				Limit:       msgsNum,
+				Aggregation: readers.CountAggregation,
+				Interval:    "1d",
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(dailyCount)),
+				Messages: fromSenml(dailyCount),
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -724,3 +742,17 @@ func toMap(msg json.Message) map[string]interface{} {
 		"payload":   map[string]interface{}(msg.Payload),
 	}
 }
+
+func countByDay(msgs []senml.Message) []senml.Message {
+	var ret []senml.Message
+	for _, msg := range msgs {
+		day := math.Floor(msg.Time/secsPerDay) * secsPerDay
+		n := len(ret)
+		if n == 0 || ret[n-1].Time != day {
+			ret = append(ret, senml.Message{Channel: msg.Channel, Name: msg.Name, Time: day, Value: new(float64)})
+			n++
+		}
+		*ret[n-1].Value++
+	}
+	return ret
+}
@@ -3,7 +3,13 @@
 
 package readers
 
-import "errors"
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
 
 const (
 	// EqualKey represents the equal comparison operator key.
@@ -18,14 +24,64 @@ const (
 	GreaterThanEqualKey = "ge"
 )
 
+const (
+	// MinAggregation represents the minimum value aggregation key.
+	MinAggregation = "min"
+	// MaxAggregation represents the maximum value aggregation key.
+	MaxAggregation = "max"
+	// AvgAggregation represents the average value aggregation key.
+	AvgAggregation = "avg"
+	// CountAggregation represents the values count aggregation key.
+	CountAggregation = "count"
+	// SumAggregation represents the values sum aggregation key.
+	SumAggregation = "sum"
+)
+
 // ErrReadMessages indicates failure occurred while reading messages from database.
 var ErrReadMessages = errors.New("failed to read messages from database")
 
+var (
+	// ErrInvalidInterval indicates malformed aggregation interval.
+	ErrInvalidInterval = errors.New("invalid aggregation interval")
+
+	// ErrInvalidCursor indicates malformed page cursor.
+	ErrInvalidCursor = errors.New("invalid page cursor")
+)
+
 // MessageRepository specifies message reader API.
 type MessageRepository interface {
 	// ReadAll skips given number of messages for given channel and returns next
-	// limited number of messages.
+	// limited number of messages. Messages of the channels listed in page
+	// metadata are read as well and SenML messages are matched against the
+	// filter expression, if any. If cursor is set in page metadata, messages
+	// are read starting right after the position the cursor points to. If
+	// aggregation is set in page metadata, SenML values are grouped by name
+	// into time buckets of the given interval and each message of the page
+	// represents a single aggregated bucket.
 	ReadAll(chanID string, pm PageMetadata) (MessagesPage, error)
+
+	// Iterate returns iterator over all the messages of the given channel
+	// that match page metadata filter. Messages are ordered by time ascending
+	// if the database supports it; Cassandra returns them in storage order.
+	// Offset, limit and aggregation are ignored.
+	Iterate(chanID string, pm PageMetadata) (MessageIterator, error)
+}
+
+// MessageIterator iterates over stored messages without loading them into
+// memory at once. It must be closed once it is no longer used.
+type MessageIterator interface {
+	// Next advances the iterator to the next message. It returns false when
+	// there are no more messages or when an error occurs.
+	Next() bool
+
+	// Message returns the message iterator currently points to.
+	Message() Message
+
+	// Err returns the error that stopped the iteration, if any.
+	Err() error
+
+	// Close releases the resources held by the iterator.
+	Close() error
 }
 
 // Message represents any message format.
@@ -35,26 +91,106 @@ type Message interface{}
 // belong to this page.
 type MessagesPage struct {
 	PageMetadata
-	Total    uint64
-	Messages []Message
+	Total      uint64
+	NextCursor string
+	Messages   []Message
 }
 
 // PageMetadata represents the parameters used to create database queries.
 type PageMetadata struct {
-	Offset      uint64  `json:"offset"`
-	Limit       uint64  `json:"limit"`
-	Subtopic    string  `json:"subtopic,omitempty"`
-	Publisher   string  `json:"publisher,omitempty"`
-	Protocol    string  `json:"protocol,omitempty"`
-	Name        string  `json:"name,omitempty"`
-	Value       float64 `json:"v,omitempty"`
-	Comparator  string  `json:"comparator,omitempty"`
-	BoolValue   bool    `json:"vb,omitempty"`
-	StringValue string  `json:"vs,omitempty"`
-	DataValue   string  `json:"vd,omitempty"`
-	From        float64 `json:"from,omitempty"`
-	To          float64 `json:"to,omitempty"`
-	Format      string  `json:"format,omitempty"`
+	Offset      uint64   `json:"offset"`
+	Limit       uint64   `json:"limit"`
+	Subtopic    string   `json:"subtopic,omitempty"`
+	Publisher   string   `json:"publisher,omitempty"`
+	Protocol    string   `json:"protocol,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Value       float64  `json:"v,omitempty"`
+	Comparator  string   `json:"comparator,omitempty"`
+	BoolValue   bool     `json:"vb,omitempty"`
+	StringValue string   `json:"vs,omitempty"`
+	DataValue   string   `json:"vd,omitempty"`
+	From        float64  `json:"from,omitempty"`
+	To          float64  `json:"to,omitempty"`
+	Format      string   `json:"format,omitempty"`
+	Aggregation string   `json:"aggregation,omitempty"`
+	Interval    string   `json:"interval,omitempty"`
+	Cursor      string   `json:"cursor,omitempty"`
+	SkipTotal   bool     `json:"skip_total,omitempty"`
+	Channels    []string `json:"channels,omitempty"`
+	Filter      *Filter  `json:"filter,omitempty"`
+}
+
+// ChannelIDs returns IDs of the channels messages are read from. Channels
+// listed in the page metadata are read along with the given channel.
+func ChannelIDs(chanID string, pm PageMetadata) []string {
+	if chanID == "" {
+		return pm.Channels
+	}
+	ids := []string{chanID}
+	for _, id := range pm.Channels {
+		if id != chanID {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// EncodeCursor encodes position of the last message of the page into an
+// opaque cursor token. The position is specific to the message repository.
+func EncodeCursor(pos interface{}) (string, error) {
+	data, err := json.Marshal(pos)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes the cursor token into the message repository position.
+func DecodeCursor(cursor string, pos interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, pos); err != nil {
+		return ErrInvalidCursor
+	}
+
+	return nil
+}
+
+// NewSliceIterator returns iterator over the given messages.
+func NewSliceIterator(msgs []Message) MessageIterator {
+	return &sliceIterator{
+		msgs: msgs,
+		idx:  -1,
+	}
+}
+
+type sliceIterator struct {
+	msgs []Message
+	idx  int
+}
+
+func (it *sliceIterator) Next() bool {
+	if it.idx+1 >= len(it.msgs) {
+		return false
+	}
+	it.idx++
+	return true
+}
+
+func (it *sliceIterator) Message() Message {
+	return it.msgs[it.idx]
+}
+
+func (it *sliceIterator) Err() error {
+	return nil
+}
+
+func (it *sliceIterator) Close() error {
+	return nil
 }
 
 // ParseValueComparator convert comparison operator keys into mathematic anotation.
@@ -78,3 +214,44 @@ func ParseValueComparator(query map[string]interface{}) string {
 
 	return comparator
 }
+
+// IsValidAggregation checks if the given key is a supported aggregation.
+func IsValidAggregation(aggregation string) bool {
+	switch aggregation {
+	case MinAggregation, MaxAggregation, AvgAggregation, CountAggregation, SumAggregation:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseInterval converts aggregation interval such as 30s, 1m, 1h, 1d or 1w
+// into duration.
+func ParseInterval(interval string) (time.Duration, error) {
+	if len(interval) < 2 {
+		return 0, ErrInvalidInterval
+	}
+
+	var unit time.Duration
+	switch interval[len(interval)-1] {
+	case 's':
+		unit = time.Second
+	case 'm':
+		unit = time.Minute
+	case 'h':
+		unit = time.Hour
+	case 'd':
+		unit = 24 * time.Hour
+	case 'w':
+		unit = 7 * 24 * time.Hour
+	default:
+		return 0, ErrInvalidInterval
+	}
+
+	n, err := strconv.ParseUint(interval[:len(interval)-1], 10, 32)
+	if err != nil || n == 0 {
+		return 0, ErrInvalidInterval
+	}
+
+	return time.Duration(n) * unit, nil
+}
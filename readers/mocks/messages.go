@@ -5,6 +5,8 @@ package mocks
 
 import (
 	"encoding/json"
+	"math"
+	"sort"
 	"sync"
 
 	"github.com/absmach/magistrala/pkg/transformers/senml"
@@ -38,20 +40,99 @@ func (repo *messageRepositoryMock) ReadAll(chanID string, rpm readers.PageMetada
 		return readers.MessagesPage{}, nil
 	}
 
+	msgs, err := repo.filter(chanID, rpm)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+
+	if rpm.Aggregation != "" {
+		msgs = aggregate(msgs, rpm)
+	}
+
+	total := uint64(len(msgs))
+
+	// Mocked cursor is the index of the first message of the next page.
+	var start uint64
+	if rpm.Cursor != "" {
+		if err := readers.DecodeCursor(rpm.Cursor, &start); err != nil || start > total {
+			return readers.MessagesPage{}, readers.ErrInvalidCursor
+		}
+	}
+	msgs = msgs[start:]
+
+	numOfMessages := uint64(len(msgs))
+
+	if rpm.Offset >= numOfMessages {
+		return readers.MessagesPage{}, nil
+	}
+
+	if rpm.Limit < 1 {
+		return readers.MessagesPage{}, nil
+	}
+
+	end := rpm.Offset + rpm.Limit
+	if rpm.Offset+rpm.Limit > numOfMessages {
+		end = numOfMessages
+	}
+
+	page := readers.MessagesPage{
+		PageMetadata: rpm,
+		Messages:     msgs[rpm.Offset:end],
+	}
+	if !rpm.SkipTotal {
+		page.Total = total
+	}
+	if end < numOfMessages {
+		page.NextCursor, err = readers.EncodeCursor(start + end)
+		if err != nil {
+			return readers.MessagesPage{}, err
+		}
+	}
+
+	return page, nil
+}
+
+func (repo *messageRepositoryMock) Iterate(chanID string, rpm readers.PageMetadata) (readers.MessageIterator, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	if rpm.Format != "" && rpm.Format != "messages" {
+		return readers.NewSliceIterator(nil), nil
+	}
+
+	msgs, err := repo.filter(chanID, rpm)
+	if err != nil {
+		return nil, err
+	}
+
+	// Messages are stored in descending time order.
+	for i, j := 0, len(msgs)-1; i < j; i, j = i+1, j-1 {
+		msgs[i], msgs[j] = msgs[j], msgs[i]
+	}
+
+	return readers.NewSliceIterator(msgs), nil
+}
+
+func (repo *messageRepositoryMock) filter(chanID string, rpm readers.PageMetadata) ([]readers.Message, error) {
 	var query map[string]interface{}
 	meta, err := json.Marshal(rpm)
 	if err != nil {
-		return readers.MessagesPage{}, err
+		return nil, err
 	}
 	if err := json.Unmarshal(meta, &query); err != nil {
-		return readers.MessagesPage{}, err
+		return nil, err
+	}
+
+	var all []readers.Message
+	for _, id := range readers.ChannelIDs(chanID, rpm) {
+		all = append(all, repo.messages[id]...)
 	}
 
 	var msgs []readers.Message
-	for _, m := range repo.messages[chanID] {
+	for _, m := range all {
 		msg := m.(senml.Message)
 
-		ok := true
+		ok := rpm.Filter == nil || rpm.Filter.Match(msg)
 
 		for name := range query {
 			switch name {
@@ -145,24 +226,71 @@ func (repo *messageRepositoryMock) ReadAll(chanID string, rpm readers.PageMetada
 		}
 	}
 
-	numOfMessages := uint64(len(msgs))
+	return msgs, nil
+}
 
-	if rpm.Offset >= numOfMessages {
-		return readers.MessagesPage{}, nil
+func aggregate(msgs []readers.Message, rpm readers.PageMetadata) []readers.Message {
+	interval, err := readers.ParseInterval(rpm.Interval)
+	if err != nil {
+		return []readers.Message{}
 	}
+	secs := interval.Seconds()
 
-	if rpm.Limit < 1 {
-		return readers.MessagesPage{}, nil
+	type bucket struct {
+		name string
+		time float64
 	}
+	var keys []bucket
+	values := make(map[bucket][]float64)
+	for _, m := range msgs {
+		msg := m.(senml.Message)
+		if msg.Value == nil {
+			continue
+		}
+		b := bucket{name: msg.Name, time: math.Floor(msg.Time/secs) * secs}
+		if _, ok := values[b]; !ok {
+			keys = append(keys, b)
+		}
+		values[b] = append(values[b], *msg.Value)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].time == keys[j].time {
+			return keys[i].name < keys[j].name
+		}
+		return keys[i].time > keys[j].time
+	})
 
-	end := rpm.Offset + rpm.Limit
-	if rpm.Offset+rpm.Limit > numOfMessages {
-		end = numOfMessages
+	ret := []readers.Message{}
+	for _, b := range keys {
+		vals := values[b]
+		val := vals[0]
+		switch rpm.Aggregation {
+		case readers.MinAggregation:
+			for _, v := range vals {
+				val = math.Min(val, v)
+			}
+		case readers.MaxAggregation:
+			for _, v := range vals {
+				val = math.Max(val, v)
+			}
+		case readers.CountAggregation:
+			val = float64(len(vals))
+		case readers.SumAggregation, readers.AvgAggregation:
+			val = 0
+			for _, v := range vals {
+				val += v
+			}
+			if rpm.Aggregation == readers.AvgAggregation {
+				val /= float64(len(vals))
+			}
+		}
+		ret = append(ret, senml.Message{
+			Channel: msgs[0].(senml.Message).Channel,
+			Name:    b.name,
+			Time:    b.time,
+			Value:   &val,
+		})
 	}
 
-	return readers.MessagesPage{
-		PageMetadata: rpm,
-		Total:        uint64(len(msgs)),
-		Messages:     msgs[rpm.Offset:end],
-	}, nil
+	return ret
 }
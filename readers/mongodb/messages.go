@@ -6,11 +6,13 @@ package mongodb
 import (
 	"context"
 	"encoding/json"
+	"regexp"
 
 	"github.com/absmach/magistrala/pkg/errors"
 	"github.com/absmach/magistrala/pkg/transformers/senml"
 	"github.com/absmach/magistrala/readers"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -20,6 +22,26 @@ const defCollection = "messages"
 
 var _ readers.MessageRepository = (*mongoRepository)(nil)
 
+var errInvalidAggregation = errors.New("invalid aggregation")
+
+// aggregations maps aggregation keys to MongoDB group accumulators.
+var aggregations = map[string]string{
+	readers.MinAggregation:   "$min",
+	readers.MaxAggregation:   "$max",
+	readers.AvgAggregation:   "$avg",
+	readers.CountAggregation: "$sum",
+	readers.SumAggregation:   "$sum",
+}
+
+// mongoOperators maps filter comparison keys to MongoDB query operators.
+var mongoOperators = map[string]string{
+	readers.EqualKey:            "$eq",
+	readers.LowerThanKey:        "$lt",
+	readers.LowerThanEqualKey:   "$lte",
+	readers.GreaterThanKey:      "$gt",
+	readers.GreaterThanEqualKey: "$gte",
+}
+
 type mongoRepository struct {
 	db *mongo.Database
 }
@@ -41,18 +63,41 @@ func (repo mongoRepository) ReadAll(chanID string, rpm readers.PageMetadata) (re
 
 	col := repo.db.Collection(format)
 
-	sortMap := map[string]interface{}{
-		order: -1,
+	if rpm.Aggregation != "" {
+		return repo.readAggregated(chanID, rpm)
+	}
+
+	sortMap := bson.D{
+		{Key: order, Value: -1},
+		{Key: "_id", Value: -1},
 	}
 	// Remove format filter and format the rest properly.
 	filter := fmtCondition(chanID, rpm)
-	cursor, err := col.Find(context.Background(), filter, options.Find().SetSort(sortMap).SetLimit(int64(rpm.Limit)).SetSkip(int64(rpm.Offset)))
+
+	pageFilter := filter
+	if rpm.Cursor != "" {
+		var pos position
+		if err := readers.DecodeCursor(rpm.Cursor, &pos); err != nil || format != defCollection {
+			return readers.MessagesPage{}, readers.ErrInvalidCursor
+		}
+		id, err := primitive.ObjectIDFromHex(pos.ID)
+		if err != nil {
+			return readers.MessagesPage{}, readers.ErrInvalidCursor
+		}
+		pageFilter = append(filter[:len(filter):len(filter)], bson.E{Key: "$or", Value: bson.A{
+			bson.M{order: bson.M{"$lt": pos.Time}},
+			bson.M{order: pos.Time, "_id": bson.M{"$lt": id}},
+		}})
+	}
+
+	cursor, err := col.Find(context.Background(), pageFilter, options.Find().SetSort(sortMap).SetLimit(int64(rpm.Limit)).SetSkip(int64(rpm.Offset)))
 	if err != nil {
 		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
 	}
 	defer cursor.Close(context.Background())
 
 	var messages []readers.Message
+	var lastID primitive.ObjectID
 	switch format {
 	case defCollection:
 		for cursor.Next(context.Background()) {
@@ -60,6 +105,7 @@ func (repo mongoRepository) ReadAll(chanID string, rpm readers.PageMetadata) (re
 			if err := cursor.Decode(&m); err != nil {
 				return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
 			}
+			lastID, _ = cursor.Current.Lookup("_id").ObjectIDOK()
 
 			messages = append(messages, m)
 		}
@@ -74,27 +120,150 @@ func (repo mongoRepository) ReadAll(chanID string, rpm readers.PageMetadata) (re
 		}
 	}
 
+	mp := readers.MessagesPage{
+		PageMetadata: rpm,
+		Messages:     messages,
+	}
+
+	if format == defCollection && rpm.Limit > 0 && uint64(len(messages)) == rpm.Limit {
+		last := messages[len(messages)-1].(senml.Message)
+		pos := position{
+			Time: last.Time,
+			ID:   lastID.Hex(),
+		}
+		if mp.NextCursor, err = readers.EncodeCursor(pos); err != nil {
+			return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+		}
+	}
+
+	if rpm.SkipTotal {
+		return mp, nil
+	}
+
 	total, err := col.CountDocuments(context.Background(), filter)
 	if err != nil {
 		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
 	}
+	mp.Total = uint64(total)
 
-	mp := readers.MessagesPage{
+	return mp, nil
+}
+
+func (repo mongoRepository) Iterate(chanID string, rpm readers.PageMetadata) (readers.MessageIterator, error) {
+	format := defCollection
+	order := "time"
+	if rpm.Format != "" && rpm.Format != defCollection {
+		order = "created"
+		format = rpm.Format
+	}
+
+	col := repo.db.Collection(format)
+	cursor, err := col.Find(context.Background(), fmtCondition(chanID, rpm), options.Find().SetSort(map[string]interface{}{order: 1}))
+	if err != nil {
+		return nil, errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	return &messageIterator{
+		cursor: cursor,
+		format: format,
+	}, nil
+}
+
+// readAggregated groups SenML values by name into time buckets of the given
+// interval using MongoDB aggregation pipeline.
+func (repo mongoRepository) readAggregated(chanID string, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	accumulator, ok := aggregations[rpm.Aggregation]
+	if !ok {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, errInvalidAggregation)
+	}
+	interval, err := readers.ParseInterval(rpm.Interval)
+	if err != nil {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	secs := interval.Seconds()
+
+	var value interface{} = "$value"
+	if rpm.Aggregation == readers.CountAggregation {
+		value = 1
+	}
+
+	buckets := mongo.Pipeline{
+		{{Key: "$match", Value: fmtCondition(chanID, rpm)}},
+		{{Key: "$match", Value: bson.D{{Key: "value", Value: bson.M{"$ne": nil}}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{
+				{Key: "channel", Value: "$channel"},
+				{Key: "name", Value: "$name"},
+				{Key: "bucket", Value: bson.M{"$subtract": bson.A{"$time", bson.M{"$mod": bson.A{"$time", secs}}}}},
+			}},
+			{Key: "value", Value: bson.M{accumulator: value}},
+		}}},
+	}
+
+	pipeline := append(buckets,
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "_id.bucket", Value: -1}, {Key: "_id.name", Value: 1}}}},
+		bson.D{{Key: "$skip", Value: int64(rpm.Offset)}},
+		bson.D{{Key: "$limit", Value: int64(rpm.Limit)}},
+	)
+
+	col := repo.db.Collection(defCollection)
+	cursor, err := col.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer cursor.Close(context.Background())
+
+	messages := []readers.Message{}
+	for cursor.Next(context.Background()) {
+		var b aggregatedBucket
+		if err := cursor.Decode(&b); err != nil {
+			return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+		}
+		messages = append(messages, b.toSenml())
+	}
+
+	countPipeline := append(buckets[:len(buckets):len(buckets)], bson.D{{Key: "$count", Value: "total"}})
+	countCursor, err := col.Aggregate(context.Background(), countPipeline)
+	if err != nil {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer countCursor.Close(context.Background())
+
+	page := readers.MessagesPage{
 		PageMetadata: rpm,
-		Total:        uint64(total),
 		Messages:     messages,
 	}
+	if countCursor.Next(context.Background()) {
+		var res struct {
+			Total int64 `bson:"total"`
+		}
+		if err := countCursor.Decode(&res); err != nil {
+			return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+		}
+		page.Total = uint64(res.Total)
+	}
 
-	return mp, nil
+	return page, nil
 }
 
 func fmtCondition(chanID string, rpm readers.PageMetadata) bson.D {
+	var channel interface{} = chanID
+	if ids := readers.ChannelIDs(chanID, rpm); len(ids) > 1 {
+		channel = bson.M{"$in": ids}
+	} else if len(ids) == 1 {
+		channel = ids[0]
+	}
 	filter := bson.D{
 		bson.E{
 			Key:   "channel",
-			Value: chanID,
+			Value: channel,
 		},
 	}
+	if rpm.Filter != nil {
+		// Filter expression is nested to avoid clashing with the other
+		// top level logical operators.
+		filter = append(filter, bson.E{Key: "$and", Value: bson.A{fmtFilter(*rpm.Filter)}})
+	}
 
 	var query map[string]interface{}
 	meta, err := json.Marshal(rpm)
@@ -147,3 +316,100 @@ func fmtCondition(chanID string, rpm readers.PageMetadata) bson.D {
 
 	return filter
 }
+
+// fmtFilter translates the filter expression into the MongoDB query.
+func fmtFilter(f readers.Filter) bson.M {
+	switch f.Operator {
+	case readers.AndOperator, readers.OrOperator:
+		filters := bson.A{}
+		for _, nested := range f.Filters {
+			filters = append(filters, fmtFilter(nested))
+		}
+		return bson.M{"$" + f.Operator: filters}
+	case readers.InKey:
+		return bson.M{f.Field: bson.M{"$in": f.Values}}
+	case readers.PrefixKey:
+		prefix, _ := f.Values[0].(string)
+		return bson.M{f.Field: primitive.Regex{Pattern: "^" + regexp.QuoteMeta(prefix)}}
+	case readers.NotEqualKey:
+		// Unlike SQL, $ne matches documents that don't contain the field.
+		return bson.M{f.Field: bson.M{"$ne": f.Values[0], "$exists": true}}
+	default:
+		return bson.M{f.Field: bson.M{mongoOperators[f.Operator]: f.Values[0]}}
+	}
+}
+
+// position represents position of the document in the messages collection.
+type position struct {
+	Time float64 `json:"time"`
+	ID   string  `json:"id"`
+}
+
+type aggregatedBucket struct {
+	ID struct {
+		Channel string  `bson:"channel"`
+		Name    string  `bson:"name"`
+		Bucket  float64 `bson:"bucket"`
+	} `bson:"_id"`
+	Value float64 `bson:"value"`
+}
+
+func (b aggregatedBucket) toSenml() senml.Message {
+	return senml.Message{
+		Channel: b.ID.Channel,
+		Name:    b.ID.Name,
+		Time:    b.ID.Bucket,
+		Value:   &b.Value,
+	}
+}
+
+type messageIterator struct {
+	cursor  *mongo.Cursor
+	format  string
+	current readers.Message
+	err     error
+}
+
+func (it *messageIterator) Next() bool {
+	if it.err != nil || !it.cursor.Next(context.Background()) {
+		return false
+	}
+
+	switch it.format {
+	case defCollection:
+		var m senml.Message
+		if err := it.cursor.Decode(&m); err != nil {
+			it.err = errors.Wrap(readers.ErrReadMessages, err)
+			return false
+		}
+		it.current = m
+	default:
+		var m map[string]interface{}
+		if err := it.cursor.Decode(&m); err != nil {
+			it.err = errors.Wrap(readers.ErrReadMessages, err)
+			return false
+		}
+		it.current = m
+	}
+
+	return true
+}
+
+func (it *messageIterator) Message() readers.Message {
+	return it.current
+}
+
+func (it *messageIterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	if err := it.cursor.Err(); err != nil {
+		return errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	return nil
+}
+
+func (it *messageIterator) Close() error {
+	return it.cursor.Close(context.Background())
+}
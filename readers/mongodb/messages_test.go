@@ -6,6 +6,7 @@ package mongodb_test
 import (
 	"context"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 )
 
 const (
+	secsPerDay  = 24 * 60 * 60
 	testDB      = "test"
 	subtopic    = "subtopic"
 	msgsNum     = 100
@@ -106,6 +108,8 @@ func TestReadSenml(t *testing.T) {
 	require.Nil(t, err, fmt.Sprintf("failed to store message to MongoDB: %s", err))
 	reader := mreader.New(db)
 
+	dailyCount := countByDay(valueMsgs)
+
 	cases := map[string]struct {
 		chanID   string
 		pageMeta readers.PageMetadata
@@ -364,6 +368,19 @@ func TestReadSenml(t *testing.T) {
 				Messages: fromSenml(messages[1:6]),
 			},
 		},
+		"read message with count aggregation": {
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:      0,
+				Limit:       msgsNum,
+				Aggregation: readers.CountAggregation,
+				Interval:    "1d",
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(dailyCount)),
+				Messages: fromSenml(dailyCount),
+			},
+		},
 	}
 
 	for desc, tc := range cases {
@@ -547,3 +564,17 @@ func toMap(msg json.Message) map[string]interface{} {
 		"payload":   map[string]interface{}(msg.Payload),
 	}
 }
+
+func countByDay(msgs []senml.Message) []senml.Message {
+	var ret []senml.Message
+	for _, msg := range msgs {
+		day := math.Floor(msg.Time/secsPerDay) * secsPerDay
+		n := len(ret)
+		if n == 0 || ret[n-1].Time != day {
+			ret = append(ret, senml.Message{Channel: msg.Channel, Name: msg.Name, Time: day, Value: new(float64)})
+			n++
+		}
+		*ret[n-1].Value++
+	}
+	return ret
+}
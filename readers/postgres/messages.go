@@ -6,6 +6,7 @@ package postgres
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/absmach/magistrala/pkg/errors"
 	"github.com/absmach/magistrala/pkg/transformers/senml"
@@ -17,6 +18,30 @@ import (
 
 var _ readers.MessageRepository = (*postgresRepository)(nil)
 
+var errInvalidAggregation = errors.New("invalid aggregation")
+
+// sqlOperators maps filter comparison keys to SQL operators.
+var sqlOperators = map[string]string{
+	readers.EqualKey:            "=",
+	readers.NotEqualKey:         "<>",
+	readers.LowerThanKey:        "<",
+	readers.LowerThanEqualKey:   "<=",
+	readers.GreaterThanKey:      ">",
+	readers.GreaterThanEqualKey: ">=",
+}
+
+// likeReplacer escapes LIKE pattern wildcards.
+var likeReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// aggregations maps aggregation keys to SQL aggregate functions.
+var aggregations = map[string]string{
+	readers.MinAggregation:   "MIN(value)",
+	readers.MaxAggregation:   "MAX(value)",
+	readers.AvgAggregation:   "AVG(value)",
+	readers.CountAggregation: "COUNT(value)",
+	readers.SumAggregation:   "SUM(value)",
+}
+
 type postgresRepository struct {
 	db *sqlx.DB
 }
@@ -36,27 +61,39 @@ func (tr postgresRepository) ReadAll(chanID string, rpm readers.PageMetadata) (r
 		order = "created"
 		format = rpm.Format
 	}
-	cond := fmtCondition(chanID, rpm)
+	params := queryParams(chanID, rpm)
+	cond := fmtCondition(chanID, rpm, params)
 
-	q := fmt.Sprintf(`SELECT * FROM %s
-    WHERE %s ORDER BY %s DESC
-	LIMIT :limit OFFSET :offset;`, format, cond, order)
+	if rpm.Aggregation != "" {
+		return tr.readAggregated(rpm, cond, params)
+	}
 
-	params := map[string]interface{}{
-		"channel":      chanID,
-		"limit":        rpm.Limit,
-		"offset":       rpm.Offset,
-		"subtopic":     rpm.Subtopic,
-		"publisher":    rpm.Publisher,
-		"name":         rpm.Name,
-		"protocol":     rpm.Protocol,
-		"value":        rpm.Value,
-		"bool_value":   rpm.BoolValue,
-		"string_value": rpm.StringValue,
-		"data_value":   rpm.DataValue,
-		"from":         rpm.From,
-		"to":           rpm.To,
+	sort := fmt.Sprintf("%s DESC", order)
+	if format == defTable {
+		// Time, publisher, subtopic and name are the primary key of SenML
+		// messages table, so they are used to determine message position.
+		sort = "time DESC, publisher DESC, subtopic DESC, name DESC"
 	}
+
+	pageCond := cond
+	if rpm.Cursor != "" {
+		// Cursor is supported for SenML messages only since JSON messages
+		// don't have the unique position.
+		var pos position
+		if err := readers.DecodeCursor(rpm.Cursor, &pos); err != nil || format != defTable {
+			return readers.MessagesPage{}, readers.ErrInvalidCursor
+		}
+		pageCond = fmt.Sprintf(`%s AND (time, publisher, subtopic, name) < (:cursor_time, CAST(:cursor_publisher AS UUID), :cursor_subtopic, :cursor_name)`, cond)
+		params["cursor_time"] = pos.Time
+		params["cursor_publisher"] = pos.Publisher
+		params["cursor_subtopic"] = pos.Subtopic
+		params["cursor_name"] = pos.Name
+	}
+
+	q := fmt.Sprintf(`SELECT * FROM %s
+    WHERE %s ORDER BY %s
+	LIMIT :limit OFFSET :offset;`, format, pageCond, sort)
+
 	rows, err := tr.db.NamedQuery(q, params)
 	if err != nil {
 		if pgErr, ok := err.(*pgconn.PgError); ok {
@@ -72,30 +109,31 @@ func (tr postgresRepository) ReadAll(chanID string, rpm readers.PageMetadata) (r
 		PageMetadata: rpm,
 		Messages:     []readers.Message{},
 	}
-	switch format {
-	case defTable:
-		for rows.Next() {
-			msg := senmlMessage{Message: senml.Message{}}
-			if err := rows.StructScan(&msg); err != nil {
-				return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
-			}
+	for rows.Next() {
+		msg, err := scanMessage(rows, format)
+		if err != nil {
+			return readers.MessagesPage{}, err
+		}
+		page.Messages = append(page.Messages, msg)
+	}
 
-			page.Messages = append(page.Messages, msg.Message)
+	if format == defTable && rpm.Limit > 0 && uint64(len(page.Messages)) == rpm.Limit {
+		last := page.Messages[len(page.Messages)-1].(senml.Message)
+		c := position{
+			Time:      last.Time,
+			Publisher: last.Publisher,
+			Subtopic:  last.Subtopic,
+			Name:      last.Name,
 		}
-	default:
-		for rows.Next() {
-			msg := jsonMessage{}
-			if err := rows.StructScan(&msg); err != nil {
-				return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
-			}
-			m, err := msg.toMap()
-			if err != nil {
-				return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
-			}
-			page.Messages = append(page.Messages, m)
+		if page.NextCursor, err = readers.EncodeCursor(c); err != nil {
+			return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
 		}
 	}
 
+	if rpm.SkipTotal {
+		return page, nil
+	}
+
 	q = fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s;`, format, cond)
 	rows, err = tr.db.NamedQuery(q, params)
 	if err != nil {
@@ -114,8 +152,182 @@ func (tr postgresRepository) ReadAll(chanID string, rpm readers.PageMetadata) (r
 	return page, nil
 }
 
-func fmtCondition(chanID string, rpm readers.PageMetadata) string {
+func (tr postgresRepository) Iterate(chanID string, rpm readers.PageMetadata) (readers.MessageIterator, error) {
+	order := "time"
+	format := defTable
+
+	if rpm.Format != "" && rpm.Format != defTable {
+		order = "created"
+		format = rpm.Format
+	}
+
+	params := queryParams(chanID, rpm)
+	q := fmt.Sprintf(`SELECT * FROM %s WHERE %s ORDER BY %s ASC;`, format, fmtCondition(chanID, rpm, params), order)
+	rows, err := tr.db.NamedQuery(q, params)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok {
+			if pgErr.Code == pgerrcode.UndefinedTable {
+				return readers.NewSliceIterator(nil), nil
+			}
+		}
+		return nil, errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	return &messageIterator{
+		rows:   rows,
+		format: format,
+	}, nil
+}
+
+// readAggregated groups SenML values by name into time buckets of the given
+// interval. Message time is stored in seconds, so buckets are calculated as
+// the interval multiples.
+func (tr postgresRepository) readAggregated(rpm readers.PageMetadata, cond string, params map[string]interface{}) (readers.MessagesPage, error) {
+	fn, ok := aggregations[rpm.Aggregation]
+	if !ok {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, errInvalidAggregation)
+	}
+	interval, err := readers.ParseInterval(rpm.Interval)
+	if err != nil {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	params["interval"] = interval.Seconds()
+
+	cond = fmt.Sprintf(`%s AND value IS NOT NULL`, cond)
+	buckets := fmt.Sprintf(`SELECT channel, name, FLOOR(time / :interval) * :interval AS bucket, %s AS value
+	FROM %s WHERE %s GROUP BY channel, name, bucket`, fn, defTable, cond)
+
+	q := fmt.Sprintf(`%s ORDER BY bucket DESC, name LIMIT :limit OFFSET :offset;`, buckets)
+	rows, err := tr.db.NamedQuery(q, params)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok {
+			if pgErr.Code == pgerrcode.UndefinedTable {
+				return readers.MessagesPage{}, nil
+			}
+		}
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer rows.Close()
+
+	page := readers.MessagesPage{
+		PageMetadata: rpm,
+		Messages:     []readers.Message{},
+	}
+	for rows.Next() {
+		var b aggregatedBucket
+		if err := rows.StructScan(&b); err != nil {
+			return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+		}
+		page.Messages = append(page.Messages, b.toSenml())
+	}
+
+	q = fmt.Sprintf(`SELECT COUNT(*) FROM (%s) AS buckets;`, buckets)
+	rows, err = tr.db.NamedQuery(q, params)
+	if err != nil {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&page.Total); err != nil {
+			return page, err
+		}
+	}
+
+	return page, nil
+}
+
+func queryParams(chanID string, rpm readers.PageMetadata) map[string]interface{} {
+	return map[string]interface{}{
+		"channel":      chanID,
+		"limit":        rpm.Limit,
+		"offset":       rpm.Offset,
+		"subtopic":     rpm.Subtopic,
+		"publisher":    rpm.Publisher,
+		"name":         rpm.Name,
+		"protocol":     rpm.Protocol,
+		"value":        rpm.Value,
+		"bool_value":   rpm.BoolValue,
+		"string_value": rpm.StringValue,
+		"data_value":   rpm.DataValue,
+		"from":         rpm.From,
+		"to":           rpm.To,
+	}
+}
+
+func scanMessage(rows *sqlx.Rows, format string) (readers.Message, error) {
+	switch format {
+	case defTable:
+		msg := senmlMessage{Message: senml.Message{}}
+		if err := rows.StructScan(&msg); err != nil {
+			return nil, errors.Wrap(readers.ErrReadMessages, err)
+		}
+		return msg.Message, nil
+	default:
+		msg := jsonMessage{}
+		if err := rows.StructScan(&msg); err != nil {
+			return nil, errors.Wrap(readers.ErrReadMessages, err)
+		}
+		m, err := msg.toMap()
+		if err != nil {
+			return nil, errors.Wrap(readers.ErrReadMessages, err)
+		}
+		return m, nil
+	}
+}
+
+type messageIterator struct {
+	rows    *sqlx.Rows
+	format  string
+	current readers.Message
+	err     error
+}
+
+func (it *messageIterator) Next() bool {
+	if it.err != nil || !it.rows.Next() {
+		return false
+	}
+	it.current, it.err = scanMessage(it.rows, it.format)
+
+	return it.err == nil
+}
+
+func (it *messageIterator) Message() readers.Message {
+	return it.current
+}
+
+func (it *messageIterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	if err := it.rows.Err(); err != nil {
+		return errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	return nil
+}
+
+func (it *messageIterator) Close() error {
+	return it.rows.Close()
+}
+
+// fmtCondition formats the query condition. Channels and filter expression
+// values are added to the query parameters.
+func fmtCondition(chanID string, rpm readers.PageMetadata, params map[string]interface{}) string {
 	condition := `channel = :channel`
+	if ids := readers.ChannelIDs(chanID, rpm); len(ids) > 1 {
+		names := make([]string, len(ids))
+		for i, id := range ids {
+			names[i] = fmt.Sprintf(":channel_%d", i)
+			params[names[i][1:]] = id
+		}
+		condition = fmt.Sprintf(`channel IN (%s)`, strings.Join(names, ", "))
+	} else if len(ids) == 1 {
+		params["channel"] = ids[0]
+	}
+	if rpm.Filter != nil {
+		condition = fmt.Sprintf(`%s AND %s`, condition, fmtFilter(*rpm.Filter, params))
+	}
 
 	var query map[string]interface{}
 	meta, err := json.Marshal(rpm)
@@ -165,6 +377,62 @@ func fmtCondition(chanID string, rpm readers.PageMetadata) string {
 	return condition
 }
 
+// fmtFilter translates the filter expression into the SQL condition.
+func fmtFilter(f readers.Filter, params map[string]interface{}) string {
+	switch f.Operator {
+	case readers.AndOperator, readers.OrOperator:
+		conds := make([]string, len(f.Filters))
+		for i, nested := range f.Filters {
+			conds[i] = fmtFilter(nested, params)
+		}
+		return fmt.Sprintf(`(%s)`, strings.Join(conds, fmt.Sprintf(` %s `, strings.ToUpper(f.Operator))))
+	}
+
+	// Parameters count is used to generate unique parameter names.
+	names := make([]string, len(f.Values))
+	for i, v := range f.Values {
+		names[i] = fmt.Sprintf(":filter_%d", len(params))
+		params[names[i][1:]] = v
+	}
+
+	switch f.Operator {
+	case readers.InKey:
+		return fmt.Sprintf(`%s IN (%s)`, f.Field, strings.Join(names, ", "))
+	case readers.PrefixKey:
+		params[names[0][1:]] = likeReplacer.Replace(f.Values[0].(string)) + "%"
+		if f.Field == "publisher" {
+			return fmt.Sprintf(`CAST(publisher AS TEXT) LIKE %s`, names[0])
+		}
+		return fmt.Sprintf(`%s LIKE %s`, f.Field, names[0])
+	default:
+		return fmt.Sprintf(`%s %s %s`, f.Field, sqlOperators[f.Operator], names[0])
+	}
+}
+
+// position represents position of the message in the SenML messages table.
+type position struct {
+	Time      float64 `json:"time"`
+	Publisher string  `json:"publisher"`
+	Subtopic  string  `json:"subtopic"`
+	Name      string  `json:"name"`
+}
+
+type aggregatedBucket struct {
+	Channel string  `db:"channel"`
+	Name    string  `db:"name"`
+	Bucket  float64 `db:"bucket"`
+	Value   float64 `db:"value"`
+}
+
+func (b aggregatedBucket) toSenml() senml.Message {
+	return senml.Message{
+		Channel: b.Channel,
+		Name:    b.Name,
+		Time:    b.Bucket,
+		Value:   &b.Value,
+	}
+}
+
 type senmlMessage struct {
 	ID string `db:"id"`
 	senml.Message
@@ -5,7 +5,9 @@ package postgres_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -20,6 +22,7 @@ import (
 )
 
 const (
+	secsPerDay  = 24 * 60 * 60
 	subtopic    = "subtopic"
 	msgsNum     = 100
 	limit       = 10
@@ -101,6 +104,11 @@ func TestReadSenml(t *testing.T) {
 	// Since messages are not saved in natural order,
 	// cases that return subset of messages are only
 	// checking data result set size, but not content.
+	dailyCount := countByDay(valueMsgs)
+
+	filter, err := readers.ParseFilter(fmt.Sprintf(`vb eq true or (name eq "%s" and subtopic prefix "%s")`, msgName, subtopic[:3]))
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
 	cases := []struct {
 		desc     string
 		chanID   string
@@ -508,6 +516,46 @@ func TestReadSenml(t *testing.T) {
 				Messages: fromSenml(messages[1:6]),
 			},
 		},
+		{
+			desc:   "read message with count aggregation",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:      0,
+				Limit:       msgsNum,
+				Aggregation: readers.CountAggregation,
+				Interval:    "1d",
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(dailyCount)),
+				Messages: fromSenml(dailyCount),
+			},
+		},
+		{
+			desc:   "read message with filter expression",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset: 0,
+				Limit:  msgsNum,
+				Filter: &filter,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(boolMsgs) + len(queryMsgs)),
+				Messages: fromSenml(append(append([]senml.Message{}, boolMsgs...), queryMsgs...)),
+			},
+		},
+		{
+			desc:   "read message of multiple channels",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:   0,
+				Limit:    msgsNum,
+				Channels: []string{wrongID},
+			},
+			page: readers.MessagesPage{
+				Total:    msgsNum,
+				Messages: fromSenml(messages),
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -518,6 +566,50 @@ func TestReadSenml(t *testing.T) {
 	}
 }
 
+func TestReadSenmlCursor(t *testing.T) {
+	writer := pwriter.New(db)
+
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+
+	messages := []senml.Message{}
+	now := float64(time.Now().Unix())
+	for i := 0; i < 25; i++ {
+		messages = append(messages, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Time:      now - float64(i),
+			Value:     &v,
+		})
+	}
+
+	err := writer.ConsumeBlocking(context.TODO(), messages)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := preader.New(db)
+
+	var pages int
+	var read []readers.Message
+	pm := readers.PageMetadata{Limit: limit, SkipTotal: true}
+	for {
+		page, err := reader.ReadAll(chanID, pm)
+		require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+		assert.Equal(t, uint64(0), page.Total, fmt.Sprintf("expected total to be skipped got %d", page.Total))
+		read = append(read, page.Messages...)
+		pages++
+		if page.NextCursor == "" {
+			break
+		}
+		pm.Cursor = page.NextCursor
+	}
+	assert.Equal(t, 3, pages, fmt.Sprintf("expected %d pages got %d", 3, pages))
+	assert.Equal(t, fromSenml(messages), read, "got incorrect list of senml Messages using cursor")
+
+	_, err = reader.ReadAll(chanID, readers.PageMetadata{Limit: limit, Cursor: "invalid"})
+	assert.True(t, errors.Is(err, readers.ErrInvalidCursor), fmt.Sprintf("expected %s got %s", readers.ErrInvalidCursor, err))
+}
+
 func TestReadJSON(t *testing.T) {
 	writer := pwriter.New(db)
 
@@ -685,3 +777,17 @@ func toMap(msg json.Message) map[string]interface{} {
 		"payload":   map[string]interface{}(msg.Payload),
 	}
 }
+
+func countByDay(msgs []senml.Message) []senml.Message {
+	var ret []senml.Message
+	for _, msg := range msgs {
+		day := math.Floor(msg.Time/secsPerDay) * secsPerDay
+		n := len(ret)
+		if n == 0 || ret[n-1].Time != day {
+			ret = append(ret, senml.Message{Channel: msg.Channel, Name: msg.Name, Time: day, Value: new(float64)})
+			n++
+		}
+		*ret[n-1].Value++
+	}
+	return ret
+}
@@ -6,6 +6,7 @@ package timescale_test
 import (
 	"context"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 )
 
 const (
+	secsPerDay  = 24 * 60 * 60
 	subtopic    = "subtopic"
 	msgsNum     = 100
 	limit       = 10
@@ -101,6 +103,8 @@ func TestReadSenml(t *testing.T) {
 	// Since messages are not saved in natural order,
 	// cases that return subset of messages are only
 	// checking data result set size, but not content.
+	dailyCount := countByDay(valueMsgs)
+
 	cases := []struct {
 		desc     string
 		chanID   string
@@ -508,6 +512,20 @@ func TestReadSenml(t *testing.T) {
 				Messages: fromSenml(messages[1:6]),
 			},
 		},
+		{
+			desc:   "read message with count aggregation",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:      0,
+				Limit:       msgsNum,
+				Aggregation: readers.CountAggregation,
+				Interval:    "1d",
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(dailyCount)),
+				Messages: fromSenml(dailyCount),
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -682,3 +700,17 @@ func toMap(msg json.Message) map[string]interface{} {
 		"payload":   map[string]interface{}(msg.Payload),
 	}
 }
+
+func countByDay(msgs []senml.Message) []senml.Message {
+	var ret []senml.Message
+	for _, msg := range msgs {
+		day := math.Floor(msg.Time/secsPerDay) * secsPerDay
+		n := len(ret)
+		if n == 0 || ret[n-1].Time != day {
+			ret = append(ret, senml.Message{Channel: msg.Channel, Name: msg.Name, Time: day, Value: new(float64)})
+			n++
+		}
+		*ret[n-1].Value++
+	}
+	return ret
+}
@@ -0,0 +1,181 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/notifiers"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/pkg/messaging"
+)
+
+// Protocol is the protocol of the messages published by the rules, used to
+// prevent the rules from triggering on their own alerts.
+const Protocol = "rules"
+
+// Action types.
+const (
+	// PublishAction publishes the alert to the channel.
+	PublishAction = "publish"
+	// NotifyAction sends the alert to the contacts using the notifier.
+	NotifyAction = "notify"
+	// WebhookAction sends the alert to the URL using HTTP POST request.
+	WebhookAction = "webhook"
+)
+
+var (
+	// ErrInvalidAction indicates invalid rule action.
+	ErrInvalidAction = errors.New("invalid rule action")
+
+	errMissingChannel  = errors.New("missing action channel")
+	errMissingContacts = errors.New("missing action contacts")
+	errInvalidURL      = errors.New("invalid action URL")
+	errUnknownAction   = errors.New("unknown action type")
+	errWebhookStatus   = errors.New("unexpected webhook response status")
+	errForbiddenTarget = errors.New("webhook target address is not allowed")
+)
+
+// Action represents the action triggered once the rule condition is met.
+type Action struct {
+	Type     string   `json:"type"`
+	Channel  string   `json:"channel,omitempty"`
+	Subtopic string   `json:"subtopic,omitempty"`
+	Contacts []string `json:"contacts,omitempty"`
+	URL      string   `json:"url,omitempty"`
+}
+
+// Validate returns an error if the action is not valid.
+func (a Action) Validate() error {
+	switch a.Type {
+	case PublishAction:
+		if a.Channel == "" {
+			return errors.Wrap(ErrInvalidAction, errMissingChannel)
+		}
+	case NotifyAction:
+		if len(a.Contacts) == 0 {
+			return errors.Wrap(ErrInvalidAction, errMissingContacts)
+		}
+	case WebhookAction:
+		u, err := url.Parse(a.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.Wrap(ErrInvalidAction, errInvalidURL)
+		}
+	default:
+		return errors.Wrap(ErrInvalidAction, errUnknownAction)
+	}
+
+	return nil
+}
+
+// Alert is the payload sent by the actions once the rule condition is met.
+type Alert struct {
+	RuleID    string  `json:"rule_id"`
+	RuleName  string  `json:"rule_name,omitempty"`
+	Channel   string  `json:"channel"`
+	Subtopic  string  `json:"subtopic,omitempty"`
+	Publisher string  `json:"publisher,omitempty"`
+	Field     string  `json:"field"`
+	Value     float64 `json:"value"`
+	Time      float64 `json:"time"`
+}
+
+// newWebhookClient returns the HTTP client used by the webhook actions. Unless
+// allowPrivate is set, the client refuses to connect to loopback, private,
+// link-local, multicast and unspecified addresses. The check is done on the
+// resolved address at dial time, so it also covers host names, DNS rebinding
+// and redirects.
+func newWebhookClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: webhookTimeout}
+	if !allowPrivate {
+		dialer.Control = refusePrivate
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Connecting through a proxy would check the proxy address instead of
+	// the webhook target.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: transport,
+	}
+}
+
+func refusePrivate(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errors.Wrap(errForbiddenTarget, err)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return errors.Wrap(errForbiddenTarget, errors.New(host))
+	}
+
+	return nil
+}
+
+// actor performs the rule actions.
+type actor struct {
+	publisher messaging.Publisher
+	notifier  notifiers.Notifier
+	from      string
+	client    *http.Client
+}
+
+func (ac actor) perform(ctx context.Context, a Action, alert Alert) error {
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	switch a.Type {
+	case PublishAction:
+		msg := &messaging.Message{
+			Channel:   a.Channel,
+			Subtopic:  a.Subtopic,
+			Publisher: alert.RuleID,
+			Protocol:  Protocol,
+			Payload:   payload,
+			Created:   time.Now().UnixNano(),
+		}
+		return ac.publisher.Publish(ctx, a.Channel, msg)
+	case NotifyAction:
+		msg := &messaging.Message{
+			Channel:   alert.Channel,
+			Subtopic:  alert.Subtopic,
+			Publisher: alert.RuleID,
+			Protocol:  Protocol,
+			Payload:   payload,
+			Created:   time.Now().UnixNano(),
+		}
+		return ac.notifier.Notify(ac.from, a.Contacts, msg)
+	case WebhookAction:
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.URL, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		res, err := ac.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+			return errors.Wrap(errWebhookStatus, errors.New(res.Status))
+		}
+		return nil
+	default:
+		return errUnknownAction
+	}
+}
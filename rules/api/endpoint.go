@@ -0,0 +1,112 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/absmach/magistrala/internal/apiutil"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/rules"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func addRuleEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(addRuleReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		r := rules.Rule{
+			Name:      req.Name,
+			Channel:   req.Channel,
+			Subtopic:  req.Subtopic,
+			Condition: req.Condition,
+			Actions:   req.Actions,
+		}
+		saved, err := svc.AddRule(ctx, req.token, r)
+		if err != nil {
+			return nil, err
+		}
+
+		return ruleRes{Rule: saved, created: true}, nil
+	}
+}
+
+func viewRuleEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ruleReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		r, err := svc.ViewRule(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return ruleRes{Rule: r}, nil
+	}
+}
+
+func listRulesEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listRulesReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		pm := rules.PageMetadata{
+			Offset:  req.offset,
+			Limit:   req.limit,
+			Channel: req.channel,
+			Name:    req.name,
+		}
+		page, err := svc.ListRules(ctx, req.token, pm)
+		if err != nil {
+			return nil, err
+		}
+
+		return rulesPageRes{Page: page}, nil
+	}
+}
+
+func updateRuleEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateRuleReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		r := rules.Rule{
+			ID:        req.id,
+			Name:      req.Name,
+			Subtopic:  req.Subtopic,
+			Condition: req.Condition,
+			Actions:   req.Actions,
+		}
+		updated, err := svc.UpdateRule(ctx, req.token, r)
+		if err != nil {
+			return nil, err
+		}
+
+		return ruleRes{Rule: updated}, nil
+	}
+}
+
+func removeRuleEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ruleReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		if err := svc.RemoveRule(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRuleRes{}, nil
+	}
+}
@@ -0,0 +1,463 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/absmach/magistrala"
+	authmocks "github.com/absmach/magistrala/auth/mocks"
+	cmocks "github.com/absmach/magistrala/consumers/mocks"
+	notmocks "github.com/absmach/magistrala/consumers/notifiers/mocks"
+	"github.com/absmach/magistrala/internal/apiutil"
+	mglog "github.com/absmach/magistrala/logger"
+	"github.com/absmach/magistrala/pkg/conditions"
+	svcerr "github.com/absmach/magistrala/pkg/errors/service"
+	pubsubmocks "github.com/absmach/magistrala/pkg/messaging/mocks"
+	"github.com/absmach/magistrala/pkg/uuid"
+	"github.com/absmach/magistrala/rules"
+	httpapi "github.com/absmach/magistrala/rules/api"
+	"github.com/absmach/magistrala/rules/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	contentType = "application/json"
+	token       = "token"
+	instanceID  = "5de9b29a-feb9-11ed-be56-0242ac120002"
+	validID     = "d4ebb847-5d0e-4e46-bdd9-b6aceaaa3a22"
+	domainID    = "9a4b8b8f-7d3e-4c5c-9f41-2e1c9d0c8a11"
+	channelID   = "0f3c8e4e-6a0b-4b69-8f1c-4f2a7f0e5d3c"
+)
+
+var rule = rules.Rule{
+	Name:    "overheat",
+	Channel: channelID,
+	Condition: rules.Condition{
+		Type:     rules.ThresholdCondition,
+		Field:    "temp",
+		Operator: conditions.GreaterThan,
+		Value:    30,
+	},
+	Actions: []rules.Action{
+		{Type: rules.WebhookAction, URL: "http://example.com/alerts"},
+	},
+}
+
+type testRequest struct {
+	client      *http.Client
+	method      string
+	url         string
+	contentType string
+	token       string
+	body        io.Reader
+}
+
+func (tr testRequest) make() (*http.Response, error) {
+	req, err := http.NewRequest(tr.method, tr.url, tr.body)
+	if err != nil {
+		return nil, err
+	}
+	if tr.token != "" {
+		req.Header.Set("Authorization", apiutil.BearerPrefix+tr.token)
+	}
+	if tr.contentType != "" {
+		req.Header.Set("Content-Type", tr.contentType)
+	}
+	return tr.client.Do(req)
+}
+
+func newService() (rules.Service, *authmocks.AuthClient) {
+	auth := new(authmocks.AuthClient)
+	svc := rules.New(auth, mocks.NewRepository(), uuid.NewMock(), new(pubsubmocks.PubSub), notmocks.NewNotifier(), "rules@example.com", true, mglog.NewMock())
+
+	auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, DomainId: domainID}, nil)
+	auth.On("Identify", mock.Anything, mock.Anything).Return(&magistrala.IdentityRes{}, svcerr.ErrAuthentication)
+	auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+
+	return svc, auth
+}
+
+func newServer(svc rules.Service) *httptest.Server {
+	logger := mglog.NewMock()
+	mux := httpapi.MakeHandler(svc, cmocks.NewDeadLetterQueue(nil), logger, instanceID)
+	return httptest.NewServer(mux)
+}
+
+func toJSON(data interface{}) string {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return ""
+	}
+	return string(jsonData)
+}
+
+func TestAddRule(t *testing.T) {
+	svc, _ := newService()
+	ss := newServer(svc)
+	defer ss.Close()
+
+	missingChannel := rule
+	missingChannel.Channel = ""
+	invalidCond := rule
+	invalidCond.Condition.Operator = "invalid"
+	missingActions := rule
+	missingActions.Actions = nil
+	longName := rule
+	longName.Name = strings.Repeat("a", 1025)
+	invalidURL := rule
+	invalidURL.Actions = []rules.Action{{Type: rules.WebhookAction, URL: "file:///etc/passwd"}}
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		token       string
+		status      int
+	}{
+		{
+			desc:        "add rule",
+			req:         toJSON(rule),
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusCreated,
+		},
+		{
+			desc:        "add rule with invalid token",
+			req:         toJSON(rule),
+			contentType: contentType,
+			token:       "invalid",
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "add rule with empty token",
+			req:         toJSON(rule),
+			contentType: contentType,
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "add rule without channel",
+			req:         toJSON(missingChannel),
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "add rule with invalid condition",
+			req:         toJSON(invalidCond),
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "add rule without actions",
+			req:         toJSON(missingActions),
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "add rule with invalid webhook URL scheme",
+			req:         toJSON(invalidURL),
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "add rule with too long name",
+			req:         toJSON(longName),
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "add rule with invalid request body",
+			req:         "}",
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "add rule with invalid content type",
+			req:         toJSON(rule),
+			contentType: "text/plain",
+			token:       token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ss.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/rules", ss.URL),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status == http.StatusCreated {
+			assert.True(t, strings.HasPrefix(res.Header.Get("Location"), "/rules/"), fmt.Sprintf("%s: expected rule location", tc.desc))
+		}
+	}
+}
+
+func TestViewRule(t *testing.T) {
+	svc, _ := newService()
+	ss := newServer(svc)
+	defer ss.Close()
+
+	saved, err := svc.AddRule(context.Background(), token, rule)
+	require.Nil(t, err, fmt.Sprintf("add rule unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		token  string
+		status int
+	}{
+		{
+			desc:   "view existing rule",
+			id:     saved.ID,
+			token:  token,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "view non-existing rule",
+			id:     "unknown",
+			token:  token,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "view rule with invalid token",
+			id:     saved.ID,
+			token:  "invalid",
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "view rule with empty token",
+			id:     saved.ID,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ss.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/rules/%s", ss.URL, tc.id),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status == http.StatusOK {
+			var body rules.Rule
+			err := json.NewDecoder(res.Body).Decode(&body)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			assert.Equal(t, saved.ID, body.ID, fmt.Sprintf("%s: expected rule %s got %s", tc.desc, saved.ID, body.ID))
+		}
+	}
+}
+
+func TestListRules(t *testing.T) {
+	svc, _ := newService()
+	ss := newServer(svc)
+	defer ss.Close()
+
+	num := 5
+	for i := 0; i < num; i++ {
+		_, err := svc.AddRule(context.Background(), token, rule)
+		require.Nil(t, err, fmt.Sprintf("add rule unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		query  string
+		token  string
+		status int
+		size   int
+	}{
+		{
+			desc:   "list rules",
+			token:  token,
+			status: http.StatusOK,
+			size:   num,
+		},
+		{
+			desc:   "list rules with limit",
+			query:  "limit=2",
+			token:  token,
+			status: http.StatusOK,
+			size:   2,
+		},
+		{
+			desc:   "list rules by channel",
+			query:  "channel=unknown",
+			token:  token,
+			status: http.StatusOK,
+			size:   0,
+		},
+		{
+			desc:   "list rules with invalid limit",
+			query:  "limit=invalid",
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "list rules with too big limit",
+			query:  "limit=1000",
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "list rules with empty token",
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ss.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/rules?%s", ss.URL, tc.query),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status == http.StatusOK {
+			var body rules.Page
+			err := json.NewDecoder(res.Body).Decode(&body)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			assert.Len(t, body.Rules, tc.size, fmt.Sprintf("%s: expected %d rules got %d", tc.desc, tc.size, len(body.Rules)))
+		}
+	}
+}
+
+func TestUpdateRule(t *testing.T) {
+	svc, _ := newService()
+	ss := newServer(svc)
+	defer ss.Close()
+
+	saved, err := svc.AddRule(context.Background(), token, rule)
+	require.Nil(t, err, fmt.Sprintf("add rule unexpected error: %s", err))
+
+	update := rule
+	update.Name = "updated"
+	invalid := rule
+	invalid.Actions = []rules.Action{{Type: rules.PublishAction}}
+
+	cases := []struct {
+		desc        string
+		id          string
+		req         string
+		contentType string
+		token       string
+		status      int
+	}{
+		{
+			desc:        "update existing rule",
+			id:          saved.ID,
+			req:         toJSON(update),
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "update non-existing rule",
+			id:          "unknown",
+			req:         toJSON(update),
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "update rule with invalid action",
+			id:          saved.ID,
+			req:         toJSON(invalid),
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update rule with empty token",
+			id:          saved.ID,
+			req:         toJSON(update),
+			contentType: contentType,
+			status:      http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ss.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/rules/%s", ss.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestRemoveRule(t *testing.T) {
+	svc, _ := newService()
+	ss := newServer(svc)
+	defer ss.Close()
+
+	saved, err := svc.AddRule(context.Background(), token, rule)
+	require.Nil(t, err, fmt.Sprintf("add rule unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		token  string
+		status int
+	}{
+		{
+			desc:   "remove rule with empty token",
+			id:     saved.ID,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "remove existing rule",
+			id:     saved.ID,
+			token:  token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "remove removed rule",
+			id:     saved.ID,
+			token:  token,
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ss.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/rules/%s", ss.URL, tc.id),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/absmach/magistrala/rules"
+)
+
+var _ rules.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger *slog.Logger
+	svc    rules.Service
+}
+
+// LoggingMiddleware adds logging facilities to the rules service.
+func LoggingMiddleware(svc rules.Service, logger *slog.Logger) rules.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+// AddRule logs the add_rule request. It logs the rule ID and the time it took to complete the request.
+// If the request fails, it logs the error.
+func (lm *loggingMiddleware) AddRule(ctx context.Context, token string, r rules.Rule) (saved rules.Rule, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("rule_id", saved.ID),
+			slog.String("channel", r.Channel),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Add rule failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Add rule completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.AddRule(ctx, token, r)
+}
+
+// ViewRule logs the view_rule request. It logs the rule ID and the time it took to complete the request.
+// If the request fails, it logs the error.
+func (lm *loggingMiddleware) ViewRule(ctx context.Context, token, id string) (r rules.Rule, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("rule_id", id),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("View rule failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("View rule completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.ViewRule(ctx, token, id)
+}
+
+// ListRules logs the list_rules request. It logs the page metadata and the time it took to complete the request.
+// If the request fails, it logs the error.
+func (lm *loggingMiddleware) ListRules(ctx context.Context, token string, pm rules.PageMetadata) (page rules.Page, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.Group("page",
+				slog.Uint64("offset", pm.Offset),
+				slog.Uint64("limit", pm.Limit),
+				slog.Uint64("total", page.Total),
+			),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("List rules failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("List rules completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.ListRules(ctx, token, pm)
+}
+
+// UpdateRule logs the update_rule request. It logs the rule ID and the time it took to complete the request.
+// If the request fails, it logs the error.
+func (lm *loggingMiddleware) UpdateRule(ctx context.Context, token string, r rules.Rule) (updated rules.Rule, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("rule_id", r.ID),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Update rule failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Update rule completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.UpdateRule(ctx, token, r)
+}
+
+// RemoveRule logs the remove_rule request. It logs the rule ID and the time it took to complete the request.
+// If the request fails, it logs the error.
+func (lm *loggingMiddleware) RemoveRule(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("rule_id", id),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Remove rule failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Remove rule completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.RemoveRule(ctx, token, id)
+}
+
+// ConsumeBlocking logs the consume_blocking request. It logs the time it took to complete the request.
+// If the request fails, it logs the error.
+func (lm *loggingMiddleware) ConsumeBlocking(ctx context.Context, msgs interface{}) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Blocking consumer failed to consume messages successfully", args...)
+			return
+		}
+		lm.logger.Info("Blocking consumer consumed messages successfully", args...)
+	}(time.Now())
+
+	return lm.svc.ConsumeBlocking(ctx, msgs)
+}
@@ -0,0 +1,91 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/absmach/magistrala/rules"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ rules.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     rules.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and latency.
+func MetricsMiddleware(svc rules.Service, counter metrics.Counter, latency metrics.Histogram) rules.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+// AddRule instruments AddRule method with metrics.
+func (mm *metricsMiddleware) AddRule(ctx context.Context, token string, r rules.Rule) (rules.Rule, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "add_rule").Add(1)
+		mm.latency.With("method", "add_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.AddRule(ctx, token, r)
+}
+
+// ViewRule instruments ViewRule method with metrics.
+func (mm *metricsMiddleware) ViewRule(ctx context.Context, token, id string) (rules.Rule, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "view_rule").Add(1)
+		mm.latency.With("method", "view_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ViewRule(ctx, token, id)
+}
+
+// ListRules instruments ListRules method with metrics.
+func (mm *metricsMiddleware) ListRules(ctx context.Context, token string, pm rules.PageMetadata) (rules.Page, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "list_rules").Add(1)
+		mm.latency.With("method", "list_rules").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ListRules(ctx, token, pm)
+}
+
+// UpdateRule instruments UpdateRule method with metrics.
+func (mm *metricsMiddleware) UpdateRule(ctx context.Context, token string, r rules.Rule) (rules.Rule, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "update_rule").Add(1)
+		mm.latency.With("method", "update_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.UpdateRule(ctx, token, r)
+}
+
+// RemoveRule instruments RemoveRule method with metrics.
+func (mm *metricsMiddleware) RemoveRule(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "remove_rule").Add(1)
+		mm.latency.With("method", "remove_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RemoveRule(ctx, token, id)
+}
+
+// ConsumeBlocking instruments ConsumeBlocking method with metrics.
+func (mm *metricsMiddleware) ConsumeBlocking(ctx context.Context, msgs interface{}) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "consume").Add(1)
+		mm.latency.With("method", "consume").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ConsumeBlocking(ctx, msgs)
+}
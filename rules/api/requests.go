@@ -0,0 +1,107 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"github.com/absmach/magistrala/internal/api"
+	"github.com/absmach/magistrala/internal/apiutil"
+	"github.com/absmach/magistrala/pkg/errors"
+	svcerr "github.com/absmach/magistrala/pkg/errors/service"
+	"github.com/absmach/magistrala/rules"
+)
+
+type addRuleReq struct {
+	token     string
+	Name      string          `json:"name,omitempty"`
+	Channel   string          `json:"channel"`
+	Subtopic  string          `json:"subtopic,omitempty"`
+	Condition rules.Condition `json:"condition"`
+	Actions   []rules.Action  `json:"actions"`
+}
+
+func (req addRuleReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+	if len(req.Name) > api.MaxNameSize {
+		return apiutil.ErrNameSize
+	}
+	r := rules.Rule{
+		Channel:   req.Channel,
+		Condition: req.Condition,
+		Actions:   req.Actions,
+	}
+	if err := r.Validate(); err != nil {
+		return errors.Wrap(svcerr.ErrMalformedEntity, err)
+	}
+
+	return nil
+}
+
+type updateRuleReq struct {
+	token     string
+	id        string
+	Name      string          `json:"name,omitempty"`
+	Subtopic  string          `json:"subtopic,omitempty"`
+	Condition rules.Condition `json:"condition"`
+	Actions   []rules.Action  `json:"actions"`
+}
+
+func (req updateRuleReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+	if req.id == "" {
+		return apiutil.ErrMissingID
+	}
+	if len(req.Name) > api.MaxNameSize {
+		return apiutil.ErrNameSize
+	}
+	// Channel can't be updated, so any non-empty value passes validation.
+	r := rules.Rule{
+		Channel:   req.id,
+		Condition: req.Condition,
+		Actions:   req.Actions,
+	}
+	if err := r.Validate(); err != nil {
+		return errors.Wrap(svcerr.ErrMalformedEntity, err)
+	}
+
+	return nil
+}
+
+type ruleReq struct {
+	token string
+	id    string
+}
+
+func (req ruleReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+	if req.id == "" {
+		return apiutil.ErrMissingID
+	}
+
+	return nil
+}
+
+type listRulesReq struct {
+	token   string
+	offset  uint64
+	limit   uint64
+	channel string
+	name    string
+}
+
+func (req listRulesReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+	if req.limit > api.MaxLimitSize || req.limit < 1 {
+		return apiutil.ErrLimitSize
+	}
+
+	return nil
+}
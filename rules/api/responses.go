@@ -0,0 +1,75 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/absmach/magistrala"
+	"github.com/absmach/magistrala/rules"
+)
+
+var (
+	_ magistrala.Response = (*ruleRes)(nil)
+	_ magistrala.Response = (*rulesPageRes)(nil)
+	_ magistrala.Response = (*removeRuleRes)(nil)
+)
+
+type ruleRes struct {
+	rules.Rule
+	created bool
+}
+
+func (res ruleRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res ruleRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/rules/%s", res.ID),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res ruleRes) Empty() bool {
+	return false
+}
+
+type rulesPageRes struct {
+	rules.Page
+}
+
+func (res rulesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res rulesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res rulesPageRes) Empty() bool {
+	return false
+}
+
+type removeRuleRes struct{}
+
+func (res removeRuleRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRuleRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRuleRes) Empty() bool {
+	return true
+}
@@ -0,0 +1,145 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/absmach/magistrala"
+	"github.com/absmach/magistrala/consumers"
+	dlqapi "github.com/absmach/magistrala/consumers/api"
+	"github.com/absmach/magistrala/internal/api"
+	"github.com/absmach/magistrala/internal/apiutil"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/rules"
+	"github.com/go-chi/chi/v5"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+const channelKey = "channel"
+
+// MakeHandler returns a HTTP handler for rules API endpoints.
+func MakeHandler(svc rules.Service, dlq consumers.DeadLetterQueue, logger *slog.Logger, instanceID string) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(apiutil.LoggingErrorEncoder(logger, api.EncodeError)),
+	}
+
+	r := chi.NewRouter()
+
+	r.Route("/rules", func(r chi.Router) {
+		r.Post("/", otelhttp.NewHandler(kithttp.NewServer(
+			addRuleEndpoint(svc),
+			decodeAddRule,
+			api.EncodeResponse,
+			opts...,
+		), "add_rule").ServeHTTP)
+
+		r.Get("/", otelhttp.NewHandler(kithttp.NewServer(
+			listRulesEndpoint(svc),
+			decodeListRules,
+			api.EncodeResponse,
+			opts...,
+		), "list_rules").ServeHTTP)
+
+		r.Get("/{ruleID}", otelhttp.NewHandler(kithttp.NewServer(
+			viewRuleEndpoint(svc),
+			decodeRule,
+			api.EncodeResponse,
+			opts...,
+		), "view_rule").ServeHTTP)
+
+		r.Put("/{ruleID}", otelhttp.NewHandler(kithttp.NewServer(
+			updateRuleEndpoint(svc),
+			decodeUpdateRule,
+			api.EncodeResponse,
+			opts...,
+		), "update_rule").ServeHTTP)
+
+		r.Delete("/{ruleID}", otelhttp.NewHandler(kithttp.NewServer(
+			removeRuleEndpoint(svc),
+			decodeRule,
+			api.EncodeResponse,
+			opts...,
+		), "remove_rule").ServeHTTP)
+	})
+
+	r.Get("/health", magistrala.Health("rules", instanceID))
+	r.Handle("/metrics", promhttp.Handler())
+	r.Mount("/dlq", dlqapi.MakeHandler(dlq, logger))
+
+	return r
+}
+
+func decodeAddRule(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), api.ContentType) {
+		return nil, errors.Wrap(apiutil.ErrValidation, apiutil.ErrUnsupportedContentType)
+	}
+
+	req := addRuleReq{token: apiutil.ExtractBearerToken(r)}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, errors.Wrap(errors.ErrMalformedEntity, err))
+	}
+
+	return req, nil
+}
+
+func decodeUpdateRule(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), api.ContentType) {
+		return nil, errors.Wrap(apiutil.ErrValidation, apiutil.ErrUnsupportedContentType)
+	}
+
+	req := updateRuleReq{
+		token: apiutil.ExtractBearerToken(r),
+		id:    chi.URLParam(r, "ruleID"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, errors.Wrap(errors.ErrMalformedEntity, err))
+	}
+
+	return req, nil
+}
+
+func decodeRule(_ context.Context, r *http.Request) (interface{}, error) {
+	req := ruleReq{
+		token: apiutil.ExtractBearerToken(r),
+		id:    chi.URLParam(r, "ruleID"),
+	}
+
+	return req, nil
+}
+
+func decodeListRules(_ context.Context, r *http.Request) (interface{}, error) {
+	o, err := apiutil.ReadNumQuery[uint64](r, api.OffsetKey, api.DefOffset)
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
+	l, err := apiutil.ReadNumQuery[uint64](r, api.LimitKey, api.DefLimit)
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
+	c, err := apiutil.ReadStringQuery(r, channelKey, "")
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
+	n, err := apiutil.ReadStringQuery(r, api.NameKey, "")
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	req := listRulesReq{
+		token:   apiutil.ExtractBearerToken(r),
+		offset:  o,
+		limit:   l,
+		channel: c,
+		name:    n,
+	}
+
+	return req, nil
+}
@@ -0,0 +1,216 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/absmach/magistrala/pkg/conditions"
+	"github.com/absmach/magistrala/pkg/errors"
+)
+
+// Condition types.
+const (
+	// ThresholdCondition compares the value with the threshold.
+	ThresholdCondition = "threshold"
+	// RangeCondition checks if the value is within or outside of the range.
+	RangeCondition = "range"
+	// RateCondition compares the rate of change per second between the
+	// consecutive values with the threshold.
+	RateCondition = "rate"
+	// WindowCondition compares the aggregate of the values received within
+	// the time window with the threshold.
+	WindowCondition = "window"
+)
+
+// Range condition operators. The other conditions use the comparison
+// operators of the conditions package.
+const (
+	Inside  = "in"
+	Outside = "out"
+)
+
+// Window aggregations.
+const (
+	Avg   = "avg"
+	Min   = "min"
+	Max   = "max"
+	Sum   = "sum"
+	Count = "count"
+)
+
+var (
+	// ErrInvalidCondition indicates invalid rule condition.
+	ErrInvalidCondition = errors.New("invalid rule condition")
+
+	errMissingField   = errors.New("missing condition field")
+	errUnknownType    = errors.New("unknown condition type")
+	errInvalidOp      = errors.New("invalid condition operator")
+	errInvalidRange   = errors.New("range minimum is greater than maximum")
+	errInvalidWindow  = errors.New("window must be positive")
+	errInvalidAggrFun = errors.New("invalid window aggregation")
+)
+
+// Condition represents the condition evaluated over the numeric values of
+// the field. The field is the SenML record name or the JSON payload field,
+// with nested JSON fields joined using "/", e.g. "sensor/temp".
+type Condition struct {
+	Type     string  `json:"type"`
+	Field    string  `json:"field"`
+	Operator string  `json:"operator"`
+	Value    float64 `json:"value,omitempty"`
+	Min      float64 `json:"min,omitempty"`
+	Max      float64 `json:"max,omitempty"`
+	// Window is the window duration in seconds.
+	Window      uint64 `json:"window,omitempty"`
+	Aggregation string `json:"aggregation,omitempty"`
+}
+
+// Validate returns an error if the condition is not valid.
+func (c Condition) Validate() error {
+	if c.Field == "" {
+		return errors.Wrap(ErrInvalidCondition, errMissingField)
+	}
+
+	switch c.Type {
+	case ThresholdCondition, RateCondition:
+		if !conditions.Valid(c.Operator) {
+			return errors.Wrap(ErrInvalidCondition, errInvalidOp)
+		}
+	case RangeCondition:
+		if c.Operator != Inside && c.Operator != Outside {
+			return errors.Wrap(ErrInvalidCondition, errInvalidOp)
+		}
+		if c.Min > c.Max {
+			return errors.Wrap(ErrInvalidCondition, errInvalidRange)
+		}
+	case WindowCondition:
+		if !conditions.Valid(c.Operator) {
+			return errors.Wrap(ErrInvalidCondition, errInvalidOp)
+		}
+		if c.Window == 0 {
+			return errors.Wrap(ErrInvalidCondition, errInvalidWindow)
+		}
+		switch c.Aggregation {
+		case Avg, Min, Max, Sum, Count:
+		default:
+			return errors.Wrap(ErrInvalidCondition, errInvalidAggrFun)
+		}
+	default:
+		return errors.Wrap(ErrInvalidCondition, errUnknownType)
+	}
+
+	return nil
+}
+
+// sample is the value of the field received at the time in seconds.
+type sample struct {
+	value float64
+	time  float64
+}
+
+// evaluator evaluates the conditions, keeping the history of the values
+// needed by the rate and window conditions. The history is kept per rule,
+// publisher and field, so the values of different devices are not mixed.
+type evaluator struct {
+	mu     sync.Mutex
+	series map[string][]sample
+}
+
+func newEvaluator() *evaluator {
+	return &evaluator{
+		series: make(map[string][]sample),
+	}
+}
+
+// eval reports whether the sample meets the rule condition.
+func (e *evaluator) eval(r Rule, publisher string, s sample) bool {
+	c := r.Condition
+	switch c.Type {
+	case ThresholdCondition:
+		return conditions.Compare(c.Operator, s.value, c.Value)
+	case RangeCondition:
+		in := s.value >= c.Min && s.value <= c.Max
+		return in == (c.Operator == Inside)
+	case RateCondition:
+		key := r.ID + "/" + publisher + "/" + c.Field
+		e.mu.Lock()
+		prev, ok := e.series[key]
+		e.series[key] = []sample{s}
+		e.mu.Unlock()
+		if !ok || s.time <= prev[0].time {
+			return false
+		}
+		rate := (s.value - prev[0].value) / (s.time - prev[0].time)
+		return conditions.Compare(c.Operator, rate, c.Value)
+	case WindowCondition:
+		key := r.ID + "/" + publisher + "/" + c.Field
+		e.mu.Lock()
+		samples := append(e.series[key], s)
+		start := s.time - float64(c.Window)
+		for len(samples) > 0 && samples[0].time <= start {
+			samples = samples[1:]
+		}
+		e.series[key] = samples
+		aggr := aggregate(c.Aggregation, samples)
+		e.mu.Unlock()
+		return conditions.Compare(c.Operator, aggr, c.Value)
+	default:
+		return false
+	}
+}
+
+// forget removes the history of the rule values.
+func (e *evaluator) forget(ruleID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	prefix := ruleID + "/"
+	for key := range e.series {
+		if strings.HasPrefix(key, prefix) {
+			delete(e.series, key)
+		}
+	}
+}
+
+func aggregate(fn string, samples []sample) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	switch fn {
+	case Count:
+		return float64(len(samples))
+	case Min:
+		ret := math.Inf(1)
+		for _, s := range samples {
+			ret = math.Min(ret, s.value)
+		}
+		return ret
+	case Max:
+		ret := math.Inf(-1)
+		for _, s := range samples {
+			ret = math.Max(ret, s.value)
+		}
+		return ret
+	default:
+		var sum float64
+		for _, s := range samples {
+			sum += s.value
+		}
+		if fn == Avg {
+			return sum / float64(len(samples))
+		}
+		return sum
+	}
+}
+
+// now returns the current time in seconds, used for the values received
+// without the time.
+func now() float64 {
+	return float64(time.Now().UnixNano()) / 1e9
+}
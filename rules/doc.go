@@ -0,0 +1,8 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package rules contains the domain concept definitions needed to support
+// Magistrala rules engine functionality. Rule is a condition evaluated over
+// the messages received on a channel and the list of actions triggered once
+// the condition is met.
+package rules
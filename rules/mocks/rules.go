@@ -0,0 +1,135 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	repoerr "github.com/absmach/magistrala/pkg/errors/repository"
+	"github.com/absmach/magistrala/rules"
+)
+
+var _ rules.Repository = (*rulesRepoMock)(nil)
+
+type rulesRepoMock struct {
+	mu    sync.Mutex
+	rules map[string]rules.Rule
+}
+
+// NewRepository returns a new rules repository mock.
+func NewRepository() rules.Repository {
+	return &rulesRepoMock{
+		rules: make(map[string]rules.Rule),
+	}
+}
+
+func (rrm *rulesRepoMock) Save(_ context.Context, r rules.Rule) (rules.Rule, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	if _, ok := rrm.rules[r.ID]; ok {
+		return rules.Rule{}, repoerr.ErrConflict
+	}
+	rrm.rules[r.ID] = r
+
+	return r, nil
+}
+
+func (rrm *rulesRepoMock) Retrieve(_ context.Context, domainID, id string) (rules.Rule, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	r, ok := rrm.rules[id]
+	if !ok || r.DomainID != domainID {
+		return rules.Rule{}, repoerr.ErrNotFound
+	}
+
+	return r, nil
+}
+
+func (rrm *rulesRepoMock) RetrieveAll(_ context.Context, pm rules.PageMetadata) (rules.Page, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	var all []rules.Rule
+	for _, r := range rrm.rules {
+		if r.DomainID != pm.DomainID {
+			continue
+		}
+		if pm.Channel != "" && r.Channel != pm.Channel {
+			continue
+		}
+		if pm.Name != "" && !strings.Contains(r.Name, pm.Name) {
+			continue
+		}
+		all = append(all, r)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].ID < all[j].ID
+	})
+
+	pm.Total = uint64(len(all))
+	page := rules.Page{
+		PageMetadata: pm,
+		Rules:        []rules.Rule{},
+	}
+	if pm.Offset >= pm.Total {
+		return page, nil
+	}
+	end := pm.Offset + pm.Limit
+	if pm.Limit == 0 || end > pm.Total {
+		end = pm.Total
+	}
+	page.Rules = all[pm.Offset:end]
+
+	return page, nil
+}
+
+func (rrm *rulesRepoMock) RetrieveByChannel(_ context.Context, channel string) ([]rules.Rule, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	var ret []rules.Rule
+	for _, r := range rrm.rules {
+		if r.Channel == channel {
+			ret = append(ret, r)
+		}
+	}
+
+	return ret, nil
+}
+
+func (rrm *rulesRepoMock) Update(_ context.Context, r rules.Rule) (rules.Rule, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	current, ok := rrm.rules[r.ID]
+	if !ok || current.DomainID != r.DomainID {
+		return rules.Rule{}, repoerr.ErrNotFound
+	}
+	current.Name = r.Name
+	current.Subtopic = r.Subtopic
+	current.Condition = r.Condition
+	current.Actions = r.Actions
+	current.UpdatedAt = r.UpdatedAt
+	rrm.rules[r.ID] = current
+
+	return current, nil
+}
+
+func (rrm *rulesRepoMock) Remove(_ context.Context, domainID, id string) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	r, ok := rrm.rules[id]
+	if !ok || r.DomainID != domainID {
+		return repoerr.ErrNotFound
+	}
+	delete(rrm.rules, id)
+
+	return nil
+}
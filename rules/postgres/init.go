@@ -0,0 +1,36 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import migrate "github.com/rubenv/sql-migrate"
+
+// Migration of rules service.
+func Migration() *migrate.MemoryMigrationSource {
+	return &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "rules_01",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS rules (
+						id          VARCHAR(36) PRIMARY KEY,
+						domain_id   VARCHAR(36) NOT NULL,
+						name        VARCHAR(1024),
+						channel     VARCHAR(36) NOT NULL,
+						subtopic    TEXT,
+						condition   JSONB NOT NULL,
+						actions     JSONB NOT NULL,
+						created_by  VARCHAR(254),
+						created_at  TIMESTAMP,
+						updated_at  TIMESTAMP
+					)`,
+					`CREATE INDEX IF NOT EXISTS rules_channel_idx ON rules (channel)`,
+					`CREATE INDEX IF NOT EXISTS rules_domain_idx ON rules (domain_id)`,
+				},
+				Down: []string{
+					"DROP TABLE IF EXISTS rules",
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/absmach/magistrala/internal/postgres"
+	"github.com/absmach/magistrala/pkg/errors"
+	repoerr "github.com/absmach/magistrala/pkg/errors/repository"
+	"github.com/absmach/magistrala/rules"
+)
+
+const columns = `id, domain_id, COALESCE(name, '') AS name, channel, COALESCE(subtopic, '') AS subtopic,
+	condition, actions, COALESCE(created_by, '') AS created_by, created_at, updated_at`
+
+var _ rules.Repository = (*rulesRepository)(nil)
+
+type rulesRepository struct {
+	db postgres.Database
+}
+
+// NewRepository instantiates a PostgreSQL implementation of rules repository.
+func NewRepository(db postgres.Database) rules.Repository {
+	return &rulesRepository{db: db}
+}
+
+func (repo *rulesRepository) Save(ctx context.Context, r rules.Rule) (rules.Rule, error) {
+	q := fmt.Sprintf(`INSERT INTO rules (id, domain_id, name, channel, subtopic, condition, actions, created_by, created_at, updated_at)
+		VALUES (:id, :domain_id, :name, :channel, :subtopic, :condition, :actions, :created_by, :created_at, :updated_at)
+		RETURNING %s`, columns)
+
+	dbr, err := toDBRule(r)
+	if err != nil {
+		return rules.Rule{}, errors.Wrap(repoerr.ErrCreateEntity, err)
+	}
+
+	return repo.queryRule(ctx, q, dbr, repoerr.ErrCreateEntity)
+}
+
+func (repo *rulesRepository) Retrieve(ctx context.Context, domainID, id string) (rules.Rule, error) {
+	q := fmt.Sprintf(`SELECT %s FROM rules WHERE id = :id AND domain_id = :domain_id`, columns)
+
+	dbr := dbRule{
+		ID:       id,
+		DomainID: domainID,
+	}
+
+	return repo.queryRule(ctx, q, dbr, repoerr.ErrViewEntity)
+}
+
+func (repo *rulesRepository) RetrieveAll(ctx context.Context, pm rules.PageMetadata) (rules.Page, error) {
+	conds := []string{"domain_id = :domain_id"}
+	if pm.Channel != "" {
+		conds = append(conds, "channel = :channel")
+	}
+	if pm.Name != "" {
+		conds = append(conds, "name ILIKE '%' || :name || '%'")
+	}
+	where := fmt.Sprintf("WHERE %s", strings.Join(conds, " AND "))
+
+	q := fmt.Sprintf(`SELECT %s FROM rules %s ORDER BY created_at LIMIT :limit OFFSET :offset`, columns, where)
+	params := map[string]interface{}{
+		"domain_id": pm.DomainID,
+		"channel":   pm.Channel,
+		"name":      pm.Name,
+		"limit":     pm.Limit,
+		"offset":    pm.Offset,
+	}
+
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return rules.Page{}, postgres.HandleError(repoerr.ErrViewEntity, err)
+	}
+	defer rows.Close()
+
+	items := []rules.Rule{}
+	for rows.Next() {
+		dbr := dbRule{}
+		if err := rows.StructScan(&dbr); err != nil {
+			return rules.Page{}, errors.Wrap(repoerr.ErrViewEntity, err)
+		}
+		r, err := toRule(dbr)
+		if err != nil {
+			return rules.Page{}, errors.Wrap(repoerr.ErrViewEntity, err)
+		}
+		items = append(items, r)
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM rules %s`, where)
+	total, err := postgres.Total(ctx, repo.db, cq, params)
+	if err != nil {
+		return rules.Page{}, errors.Wrap(repoerr.ErrViewEntity, err)
+	}
+	pm.Total = total
+
+	return rules.Page{
+		PageMetadata: pm,
+		Rules:        items,
+	}, nil
+}
+
+func (repo *rulesRepository) RetrieveByChannel(ctx context.Context, channel string) ([]rules.Rule, error) {
+	q := fmt.Sprintf(`SELECT %s FROM rules WHERE channel = :channel`, columns)
+
+	rows, err := repo.db.NamedQueryContext(ctx, q, dbRule{Channel: channel})
+	if err != nil {
+		return nil, postgres.HandleError(repoerr.ErrViewEntity, err)
+	}
+	defer rows.Close()
+
+	var items []rules.Rule
+	for rows.Next() {
+		dbr := dbRule{}
+		if err := rows.StructScan(&dbr); err != nil {
+			return nil, errors.Wrap(repoerr.ErrViewEntity, err)
+		}
+		r, err := toRule(dbr)
+		if err != nil {
+			return nil, errors.Wrap(repoerr.ErrViewEntity, err)
+		}
+		items = append(items, r)
+	}
+
+	return items, nil
+}
+
+func (repo *rulesRepository) Update(ctx context.Context, r rules.Rule) (rules.Rule, error) {
+	q := fmt.Sprintf(`UPDATE rules SET name = :name, subtopic = :subtopic, condition = :condition, actions = :actions, updated_at = :updated_at
+		WHERE id = :id AND domain_id = :domain_id
+		RETURNING %s`, columns)
+
+	dbr, err := toDBRule(r)
+	if err != nil {
+		return rules.Rule{}, errors.Wrap(repoerr.ErrUpdateEntity, err)
+	}
+
+	return repo.queryRule(ctx, q, dbr, repoerr.ErrUpdateEntity)
+}
+
+func (repo *rulesRepository) Remove(ctx context.Context, domainID, id string) error {
+	q := `DELETE FROM rules WHERE id = $1 AND domain_id = $2`
+
+	res, err := repo.db.ExecContext(ctx, q, id, domainID)
+	if err != nil {
+		return postgres.HandleError(repoerr.ErrRemoveEntity, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repoerr.ErrNotFound
+	}
+
+	return nil
+}
+
+// queryRule runs the query returning a single rule.
+func (repo *rulesRepository) queryRule(ctx context.Context, q string, dbr dbRule, wrapper error) (rules.Rule, error) {
+	rows, err := repo.db.NamedQueryContext(ctx, q, dbr)
+	if err != nil {
+		return rules.Rule{}, postgres.HandleError(wrapper, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return rules.Rule{}, repoerr.ErrNotFound
+	}
+	dbr = dbRule{}
+	if err := rows.StructScan(&dbr); err != nil {
+		return rules.Rule{}, errors.Wrap(wrapper, err)
+	}
+
+	return toRule(dbr)
+}
+
+type dbRule struct {
+	ID        string    `db:"id"`
+	DomainID  string    `db:"domain_id"`
+	Name      string    `db:"name"`
+	Channel   string    `db:"channel"`
+	Subtopic  string    `db:"subtopic"`
+	Condition []byte    `db:"condition"`
+	Actions   []byte    `db:"actions"`
+	CreatedBy string    `db:"created_by"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func toDBRule(r rules.Rule) (dbRule, error) {
+	cond, err := json.Marshal(r.Condition)
+	if err != nil {
+		return dbRule{}, err
+	}
+	actions, err := json.Marshal(r.Actions)
+	if err != nil {
+		return dbRule{}, err
+	}
+
+	return dbRule{
+		ID:        r.ID,
+		DomainID:  r.DomainID,
+		Name:      r.Name,
+		Channel:   r.Channel,
+		Subtopic:  r.Subtopic,
+		Condition: cond,
+		Actions:   actions,
+		CreatedBy: r.CreatedBy,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}, nil
+}
+
+func toRule(dbr dbRule) (rules.Rule, error) {
+	r := rules.Rule{
+		ID:        dbr.ID,
+		DomainID:  dbr.DomainID,
+		Name:      dbr.Name,
+		Channel:   dbr.Channel,
+		Subtopic:  dbr.Subtopic,
+		CreatedBy: dbr.CreatedBy,
+		CreatedAt: dbr.CreatedAt,
+		UpdatedAt: dbr.UpdatedAt,
+	}
+	if err := json.Unmarshal(dbr.Condition, &r.Condition); err != nil {
+		return rules.Rule{}, err
+	}
+	if err := json.Unmarshal(dbr.Actions, &r.Actions); err != nil {
+		return rules.Rule{}, err
+	}
+
+	return r, nil
+}
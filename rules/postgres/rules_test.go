@@ -0,0 +1,283 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/internal/testsutil"
+	"github.com/absmach/magistrala/pkg/conditions"
+	"github.com/absmach/magistrala/pkg/errors"
+	repoerr "github.com/absmach/magistrala/pkg/errors/repository"
+	"github.com/absmach/magistrala/rules"
+	"github.com/absmach/magistrala/rules/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	condition = rules.Condition{
+		Type:     rules.ThresholdCondition,
+		Field:    "temp",
+		Operator: conditions.GreaterThan,
+		Value:    30,
+	}
+	actions = []rules.Action{
+		{Type: rules.WebhookAction, URL: "http://example.com/alerts"},
+	}
+)
+
+func newRule(t *testing.T, domainID, channel string) rules.Rule {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	return rules.Rule{
+		ID:        testsutil.GenerateUUID(t),
+		DomainID:  domainID,
+		Name:      "rule",
+		Channel:   channel,
+		Condition: condition,
+		Actions:   actions,
+		CreatedBy: testsutil.GenerateUUID(t),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func TestRulesSave(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM rules")
+		require.Nil(t, err, fmt.Sprintf("clean rules unexpected error: %s", err))
+	})
+	repo := postgres.NewRepository(database)
+
+	r := newRule(t, testsutil.GenerateUUID(t), testsutil.GenerateUUID(t))
+
+	cases := []struct {
+		desc string
+		rule rules.Rule
+		err  error
+	}{
+		{
+			desc: "save new rule",
+			rule: r,
+			err:  nil,
+		},
+		{
+			desc: "save rule with duplicate ID",
+			rule: r,
+			err:  repoerr.ErrConflict,
+		},
+	}
+
+	for _, tc := range cases {
+		saved, err := repo.Save(context.Background(), tc.rule)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.rule, saved, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.rule, saved))
+		}
+	}
+}
+
+func TestRulesRetrieve(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM rules")
+		require.Nil(t, err, fmt.Sprintf("clean rules unexpected error: %s", err))
+	})
+	repo := postgres.NewRepository(database)
+
+	r, err := repo.Save(context.Background(), newRule(t, testsutil.GenerateUUID(t), testsutil.GenerateUUID(t)))
+	require.Nil(t, err, fmt.Sprintf("save rule unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		domainID string
+		id       string
+		err      error
+	}{
+		{
+			desc:     "retrieve existing rule",
+			domainID: r.DomainID,
+			id:       r.ID,
+			err:      nil,
+		},
+		{
+			desc:     "retrieve rule from another domain",
+			domainID: testsutil.GenerateUUID(t),
+			id:       r.ID,
+			err:      repoerr.ErrNotFound,
+		},
+		{
+			desc:     "retrieve non-existing rule",
+			domainID: r.DomainID,
+			id:       testsutil.GenerateUUID(t),
+			err:      repoerr.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := repo.Retrieve(context.Background(), tc.domainID, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestRulesRetrieveAll(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM rules")
+		require.Nil(t, err, fmt.Sprintf("clean rules unexpected error: %s", err))
+	})
+	repo := postgres.NewRepository(database)
+
+	domainID := testsutil.GenerateUUID(t)
+	channel := testsutil.GenerateUUID(t)
+	num := 10
+	for i := 0; i < num; i++ {
+		ch := channel
+		if i%2 == 1 {
+			ch = testsutil.GenerateUUID(t)
+		}
+		_, err := repo.Save(context.Background(), newRule(t, domainID, ch))
+		require.Nil(t, err, fmt.Sprintf("save rule unexpected error: %s", err))
+	}
+	_, err := repo.Save(context.Background(), newRule(t, testsutil.GenerateUUID(t), channel))
+	require.Nil(t, err, fmt.Sprintf("save rule unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		pm    rules.PageMetadata
+		size  int
+		total uint64
+	}{
+		{
+			desc:  "retrieve all domain rules",
+			pm:    rules.PageMetadata{DomainID: domainID, Limit: 100},
+			size:  num,
+			total: uint64(num),
+		},
+		{
+			desc:  "retrieve page of domain rules",
+			pm:    rules.PageMetadata{DomainID: domainID, Offset: 8, Limit: 5},
+			size:  2,
+			total: uint64(num),
+		},
+		{
+			desc:  "retrieve domain rules by channel",
+			pm:    rules.PageMetadata{DomainID: domainID, Channel: channel, Limit: 100},
+			size:  num / 2,
+			total: uint64(num / 2),
+		},
+		{
+			desc:  "retrieve domain rules by name",
+			pm:    rules.PageMetadata{DomainID: domainID, Name: "ul", Limit: 100},
+			size:  num,
+			total: uint64(num),
+		},
+		{
+			desc:  "retrieve rules of unknown domain",
+			pm:    rules.PageMetadata{DomainID: testsutil.GenerateUUID(t), Limit: 100},
+			size:  0,
+			total: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := repo.RetrieveAll(context.Background(), tc.pm)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Len(t, page.Rules, tc.size, fmt.Sprintf("%s: expected %d rules got %d", tc.desc, tc.size, len(page.Rules)))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d", tc.desc, tc.total, page.Total))
+	}
+
+	rs, err := repo.RetrieveByChannel(context.Background(), channel)
+	assert.Nil(t, err, fmt.Sprintf("retrieve rules by channel unexpected error: %s", err))
+	assert.Len(t, rs, num/2+1, fmt.Sprintf("expected %d rules got %d", num/2+1, len(rs)))
+}
+
+func TestRulesUpdate(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM rules")
+		require.Nil(t, err, fmt.Sprintf("clean rules unexpected error: %s", err))
+	})
+	repo := postgres.NewRepository(database)
+
+	r, err := repo.Save(context.Background(), newRule(t, testsutil.GenerateUUID(t), testsutil.GenerateUUID(t)))
+	require.Nil(t, err, fmt.Sprintf("save rule unexpected error: %s", err))
+
+	updated := r
+	updated.Name = "updated"
+	updated.Subtopic = "temperature"
+	updated.Condition = rules.Condition{Type: rules.RangeCondition, Field: "hum", Operator: rules.Outside, Min: 20, Max: 60}
+	updated.Actions = []rules.Action{{Type: rules.PublishAction, Channel: testsutil.GenerateUUID(t)}}
+	updated.UpdatedAt = r.UpdatedAt.Add(time.Minute)
+
+	unknown := updated
+	unknown.DomainID = testsutil.GenerateUUID(t)
+
+	cases := []struct {
+		desc string
+		rule rules.Rule
+		err  error
+	}{
+		{
+			desc: "update existing rule",
+			rule: updated,
+			err:  nil,
+		},
+		{
+			desc: "update rule from another domain",
+			rule: unknown,
+			err:  repoerr.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := repo.Update(context.Background(), tc.rule)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.rule, res, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.rule, res))
+		}
+	}
+}
+
+func TestRulesRemove(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM rules")
+		require.Nil(t, err, fmt.Sprintf("clean rules unexpected error: %s", err))
+	})
+	repo := postgres.NewRepository(database)
+
+	r, err := repo.Save(context.Background(), newRule(t, testsutil.GenerateUUID(t), testsutil.GenerateUUID(t)))
+	require.Nil(t, err, fmt.Sprintf("save rule unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		domainID string
+		id       string
+		err      error
+	}{
+		{
+			desc:     "remove rule from another domain",
+			domainID: testsutil.GenerateUUID(t),
+			id:       r.ID,
+			err:      repoerr.ErrNotFound,
+		},
+		{
+			desc:     "remove existing rule",
+			domainID: r.DomainID,
+			id:       r.ID,
+			err:      nil,
+		},
+		{
+			desc:     "remove removed rule",
+			domainID: r.DomainID,
+			id:       r.ID,
+			err:      repoerr.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Remove(context.Background(), tc.domainID, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"context"
+	"time"
+
+	"github.com/absmach/magistrala/pkg/errors"
+)
+
+var (
+	// ErrMissingChannel indicates missing rule input channel.
+	ErrMissingChannel = errors.New("missing rule channel")
+
+	// ErrMissingActions indicates rule without actions.
+	ErrMissingActions = errors.New("missing rule actions")
+)
+
+// Rule represents the condition evaluated over the messages received on the
+// input channel and the actions triggered once the condition is met. Empty
+// subtopic matches messages published to any subtopic of the channel.
+type Rule struct {
+	ID        string    `json:"id"`
+	DomainID  string    `json:"domain_id"`
+	Name      string    `json:"name,omitempty"`
+	Channel   string    `json:"channel"`
+	Subtopic  string    `json:"subtopic,omitempty"`
+	Condition Condition `json:"condition"`
+	Actions   []Action  `json:"actions"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// Validate returns an error if the rule is not valid.
+func (r Rule) Validate() error {
+	if r.Channel == "" {
+		return ErrMissingChannel
+	}
+	if err := r.Condition.Validate(); err != nil {
+		return err
+	}
+	if len(r.Actions) == 0 {
+		return ErrMissingActions
+	}
+	for _, a := range r.Actions {
+		if err := a.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Matches reports whether the rule applies to the messages published to the
+// channel and subtopic.
+func (r Rule) Matches(channel, subtopic string) bool {
+	return r.Channel == channel && (r.Subtopic == "" || r.Subtopic == subtopic)
+}
+
+// PageMetadata contains page metadata that helps navigation.
+type PageMetadata struct {
+	Total    uint64 `json:"total"`
+	Offset   uint64 `json:"offset"`
+	Limit    uint64 `json:"limit"`
+	DomainID string `json:"domain_id,omitempty"`
+	Channel  string `json:"channel,omitempty"`
+	Name     string `json:"name,omitempty"`
+}
+
+// Page contains a page of rules.
+type Page struct {
+	PageMetadata
+	Rules []Rule `json:"rules"`
+}
+
+// Repository specifies a rule persistence API.
+type Repository interface {
+	// Save persists the rule.
+	Save(ctx context.Context, r Rule) (Rule, error)
+
+	// Retrieve retrieves the rule having the provided ID within the domain.
+	Retrieve(ctx context.Context, domainID, id string) (Rule, error)
+
+	// RetrieveAll retrieves the subset of rules matching the page metadata.
+	RetrieveAll(ctx context.Context, pm PageMetadata) (Page, error)
+
+	// RetrieveByChannel retrieves all the rules having the provided input
+	// channel, regardless of the domain.
+	RetrieveByChannel(ctx context.Context, channel string) ([]Rule, error)
+
+	// Update updates the rule name, subtopic, condition and actions.
+	Update(ctx context.Context, r Rule) (Rule, error)
+
+	// Remove removes the rule having the provided ID within the domain.
+	Remove(ctx context.Context, domainID, id string) error
+}
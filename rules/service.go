@@ -0,0 +1,348 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/absmach/magistrala"
+	"github.com/absmach/magistrala/auth"
+	"github.com/absmach/magistrala/consumers"
+	"github.com/absmach/magistrala/consumers/notifiers"
+	"github.com/absmach/magistrala/pkg/conditions"
+	"github.com/absmach/magistrala/pkg/errors"
+	svcerr "github.com/absmach/magistrala/pkg/errors/service"
+	"github.com/absmach/magistrala/pkg/messaging"
+	mgjson "github.com/absmach/magistrala/pkg/transformers/json"
+	"github.com/absmach/magistrala/pkg/transformers/senml"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// ErrMessage indicates unsupported message type.
+var ErrMessage = errors.New("unsupported message type")
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// AddRule adds the rule to the domain of the user identified by the token.
+	AddRule(ctx context.Context, token string, r Rule) (Rule, error)
+
+	// ViewRule retrieves the rule having the provided ID.
+	ViewRule(ctx context.Context, token, id string) (Rule, error)
+
+	// ListRules retrieves the subset of the domain rules.
+	ListRules(ctx context.Context, token string, pm PageMetadata) (Page, error)
+
+	// UpdateRule updates the rule name, subtopic, condition and actions. The
+	// input channel of the rule can't be changed.
+	UpdateRule(ctx context.Context, token string, r Rule) (Rule, error)
+
+	// RemoveRule removes the rule having the provided ID.
+	RemoveRule(ctx context.Context, token, id string) error
+
+	// ConsumeBlocking evaluates the rules over the transformed messages and
+	// performs the actions of the rules whose condition is met. Failed actions
+	// are logged and don't fail the consumption.
+	consumers.BlockingConsumer
+}
+
+var _ Service = (*rulesService)(nil)
+
+type rulesService struct {
+	auth   magistrala.AuthServiceClient
+	rules  Repository
+	idp    magistrala.IDProvider
+	eval   *evaluator
+	actor  actor
+	logger *slog.Logger
+}
+
+// New instantiates the rules service implementation. Webhook actions are
+// allowed to target loopback and private addresses only if allowPrivate is set.
+func New(authClient magistrala.AuthServiceClient, rules Repository, idp magistrala.IDProvider, publisher messaging.Publisher, notifier notifiers.Notifier, from string, allowPrivate bool, logger *slog.Logger) Service {
+	return &rulesService{
+		auth:  authClient,
+		rules: rules,
+		idp:   idp,
+		eval:  newEvaluator(),
+		actor: actor{
+			publisher: publisher,
+			notifier:  notifier,
+			from:      from,
+			client:    newWebhookClient(allowPrivate),
+		},
+		logger: logger,
+	}
+}
+
+func (svc *rulesService) AddRule(ctx context.Context, token string, r Rule) (Rule, error) {
+	user, err := svc.identify(ctx, token)
+	if err != nil {
+		return Rule{}, err
+	}
+	if err := svc.authorizeChannels(ctx, token, r); err != nil {
+		return Rule{}, err
+	}
+
+	r.ID, err = svc.idp.ID()
+	if err != nil {
+		return Rule{}, errors.Wrap(svcerr.ErrUniqueID, err)
+	}
+	r.DomainID = user.GetDomainId()
+	r.CreatedBy = user.GetId()
+	r.CreatedAt = time.Now()
+	r.UpdatedAt = r.CreatedAt
+
+	saved, err := svc.rules.Save(ctx, r)
+	if err != nil {
+		return Rule{}, errors.Wrap(svcerr.ErrCreateEntity, err)
+	}
+
+	return saved, nil
+}
+
+func (svc *rulesService) ViewRule(ctx context.Context, token, id string) (Rule, error) {
+	user, err := svc.identify(ctx, token)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	r, err := svc.rules.Retrieve(ctx, user.GetDomainId(), id)
+	if err != nil {
+		return Rule{}, errors.Wrap(svcerr.ErrViewEntity, err)
+	}
+
+	return r, nil
+}
+
+func (svc *rulesService) ListRules(ctx context.Context, token string, pm PageMetadata) (Page, error) {
+	user, err := svc.identify(ctx, token)
+	if err != nil {
+		return Page{}, err
+	}
+
+	pm.DomainID = user.GetDomainId()
+	page, err := svc.rules.RetrieveAll(ctx, pm)
+	if err != nil {
+		return Page{}, errors.Wrap(svcerr.ErrViewEntity, err)
+	}
+
+	return page, nil
+}
+
+func (svc *rulesService) UpdateRule(ctx context.Context, token string, r Rule) (Rule, error) {
+	user, err := svc.identify(ctx, token)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	current, err := svc.rules.Retrieve(ctx, user.GetDomainId(), r.ID)
+	if err != nil {
+		return Rule{}, errors.Wrap(svcerr.ErrViewEntity, err)
+	}
+	r.Channel = current.Channel
+	if err := svc.authorizeChannels(ctx, token, r); err != nil {
+		return Rule{}, err
+	}
+
+	r.DomainID = current.DomainID
+	r.UpdatedAt = time.Now()
+	updated, err := svc.rules.Update(ctx, r)
+	if err != nil {
+		return Rule{}, errors.Wrap(svcerr.ErrUpdateEntity, err)
+	}
+	// History of the old condition values is not relevant anymore.
+	svc.eval.forget(r.ID)
+
+	return updated, nil
+}
+
+func (svc *rulesService) RemoveRule(ctx context.Context, token, id string) error {
+	user, err := svc.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	if err := svc.rules.Remove(ctx, user.GetDomainId(), id); err != nil {
+		return errors.Wrap(svcerr.ErrRemoveEntity, err)
+	}
+	svc.eval.forget(id)
+
+	return nil
+}
+
+func (svc *rulesService) ConsumeBlocking(ctx context.Context, messages interface{}) error {
+	recs, err := records(messages)
+	if err != nil {
+		return err
+	}
+
+	// Messages are usually published to a single channel, so the rules are
+	// retrieved once per channel.
+	chanRules := make(map[string][]Rule)
+	for _, rec := range recs {
+		rs, ok := chanRules[rec.channel]
+		if !ok {
+			if rs, err = svc.rules.RetrieveByChannel(ctx, rec.channel); err != nil {
+				return errors.Wrap(svcerr.ErrViewEntity, err)
+			}
+			chanRules[rec.channel] = rs
+		}
+
+		for _, r := range rs {
+			if !r.Matches(rec.channel, rec.subtopic) || r.Condition.Field != rec.name {
+				continue
+			}
+			if !svc.eval.eval(r, rec.publisher, rec.sample) {
+				continue
+			}
+			alert := Alert{
+				RuleID:    r.ID,
+				RuleName:  r.Name,
+				Channel:   rec.channel,
+				Subtopic:  rec.subtopic,
+				Publisher: rec.publisher,
+				Field:     rec.name,
+				Value:     rec.value,
+				Time:      rec.time,
+			}
+			// Failed actions are logged rather than returned, since returning
+			// an error would redeliver the messages and repeat the actions that
+			// already succeeded.
+			for _, a := range r.Actions {
+				if err := svc.actor.perform(ctx, a, alert); err != nil {
+					svc.logger.Warn(fmt.Sprintf("Failed to perform %s action of rule %s: %s", a.Type, r.ID, err))
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (svc *rulesService) identify(ctx context.Context, token string) (*magistrala.IdentityRes, error) {
+	res, err := svc.auth.Identify(ctx, &magistrala.IdentityReq{Token: token})
+	if err != nil {
+		return nil, errors.Wrap(svcerr.ErrAuthentication, err)
+	}
+	if res.GetId() == "" || res.GetDomainId() == "" {
+		return nil, errors.ErrDomainAuthorization
+	}
+	if err := svc.authorize(ctx, auth.UsersKind, res.GetId(), auth.MembershipPermission, auth.DomainType, res.GetDomainId()); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// authorizeChannels checks if the user can read the messages from the rule
+// input channel and publish the alerts to the rule output channels.
+func (svc *rulesService) authorizeChannels(ctx context.Context, token string, r Rule) error {
+	if err := svc.authorize(ctx, auth.TokenKind, token, auth.ViewPermission, auth.GroupType, r.Channel); err != nil {
+		return err
+	}
+	for _, a := range r.Actions {
+		if a.Type != PublishAction {
+			continue
+		}
+		if err := svc.authorize(ctx, auth.TokenKind, token, auth.EditPermission, auth.GroupType, a.Channel); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (svc *rulesService) authorize(ctx context.Context, subjKind, subj, perm, objType, obj string) error {
+	req := &magistrala.AuthorizeReq{
+		SubjectType: auth.UserType,
+		SubjectKind: subjKind,
+		Subject:     subj,
+		Permission:  perm,
+		ObjectType:  objType,
+		Object:      obj,
+	}
+	res, err := svc.auth.Authorize(ctx, req)
+	if err != nil {
+		return errors.Wrap(svcerr.ErrAuthorization, err)
+	}
+	if !res.GetAuthorized() {
+		return svcerr.ErrAuthorization
+	}
+
+	return nil
+}
+
+// record is the numeric value extracted from the message.
+type record struct {
+	channel   string
+	subtopic  string
+	publisher string
+	name      string
+	sample
+}
+
+func records(messages interface{}) ([]record, error) {
+	var recs []record
+	switch msgs := messages.(type) {
+	case []senml.Message:
+		for _, msg := range msgs {
+			if msg.Protocol == Protocol || msg.Value == nil {
+				continue
+			}
+			t := msg.Time
+			if t == 0 {
+				t = now()
+			}
+			recs = append(recs, record{
+				channel:   msg.Channel,
+				subtopic:  msg.Subtopic,
+				publisher: msg.Publisher,
+				name:      msg.Name,
+				sample:    sample{value: *msg.Value, time: t},
+			})
+		}
+	case mgjson.Messages:
+		for _, msg := range msgs.Data {
+			if msg.Protocol == Protocol {
+				continue
+			}
+			t := float64(msg.Created) / 1e9
+			if t == 0 {
+				t = now()
+			}
+			vals := make(map[string]float64)
+			flatten(msg.Payload, "", vals)
+			for name, val := range vals {
+				recs = append(recs, record{
+					channel:   msg.Channel,
+					subtopic:  msg.Subtopic,
+					publisher: msg.Publisher,
+					name:      name,
+					sample:    sample{value: val, time: t},
+				})
+			}
+		}
+	default:
+		return nil, ErrMessage
+	}
+
+	return recs, nil
+}
+
+// flatten collects numeric payload fields keyed by their paths.
+func flatten(payload map[string]interface{}, prefix string, vals map[string]float64) {
+	for k, v := range payload {
+		switch val := v.(type) {
+		case float64:
+			vals[prefix+k] = val
+		case map[string]interface{}:
+			flatten(val, prefix+k+conditions.FieldSep, vals)
+		}
+	}
+}
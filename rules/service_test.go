@@ -0,0 +1,279 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package rules_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/absmach/magistrala"
+	authmocks "github.com/absmach/magistrala/auth/mocks"
+	notmocks "github.com/absmach/magistrala/consumers/notifiers/mocks"
+	"github.com/absmach/magistrala/internal/testsutil"
+	mglog "github.com/absmach/magistrala/logger"
+	"github.com/absmach/magistrala/pkg/conditions"
+	"github.com/absmach/magistrala/pkg/errors"
+	svcerr "github.com/absmach/magistrala/pkg/errors/service"
+	"github.com/absmach/magistrala/pkg/messaging"
+	pubsubmocks "github.com/absmach/magistrala/pkg/messaging/mocks"
+	"github.com/absmach/magistrala/pkg/transformers/senml"
+	"github.com/absmach/magistrala/pkg/uuid"
+	"github.com/absmach/magistrala/rules"
+	"github.com/absmach/magistrala/rules/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	validToken   = "token"
+	invalidToken = "invalid"
+	field        = "temp"
+)
+
+var (
+	userID   = testsutil.GenerateUUID(&testing.T{})
+	domainID = testsutil.GenerateUUID(&testing.T{})
+	channel  = testsutil.GenerateUUID(&testing.T{})
+	output   = testsutil.GenerateUUID(&testing.T{})
+)
+
+func newService() (rules.Service, *authmocks.AuthClient, *pubsubmocks.PubSub) {
+	auth := new(authmocks.AuthClient)
+	pubsub := new(pubsubmocks.PubSub)
+	svc := rules.New(auth, mocks.NewRepository(), uuid.NewMock(), pubsub, notmocks.NewNotifier(), "rules@example.com", true, mglog.NewMock())
+
+	auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: validToken}).Return(&magistrala.IdentityRes{Id: userID, DomainId: domainID}, nil)
+	auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: invalidToken}).Return(&magistrala.IdentityRes{}, svcerr.ErrAuthentication)
+	auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+
+	return svc, auth, pubsub
+}
+
+func newRule(cond rules.Condition, actions ...rules.Action) rules.Rule {
+	return rules.Rule{
+		Name:      "rule",
+		Channel:   channel,
+		Condition: cond,
+		Actions:   actions,
+	}
+}
+
+func senmlMsg(value, t float64) senml.Message {
+	return senml.Message{
+		Channel:   channel,
+		Publisher: "publisher",
+		Name:      field,
+		Value:     &value,
+		Time:      t,
+	}
+}
+
+func TestAddRule(t *testing.T) {
+	svc, _, _ := newService()
+
+	r := newRule(rules.Condition{Type: rules.ThresholdCondition, Field: field, Operator: conditions.GreaterThan, Value: 30}, rules.Action{Type: rules.PublishAction, Channel: output})
+
+	cases := []struct {
+		desc  string
+		token string
+		rule  rules.Rule
+		err   error
+	}{
+		{
+			desc:  "add rule",
+			token: validToken,
+			rule:  r,
+			err:   nil,
+		},
+		{
+			desc:  "add rule with invalid token",
+			token: invalidToken,
+			rule:  r,
+			err:   svcerr.ErrAuthentication,
+		},
+	}
+
+	for _, tc := range cases {
+		saved, err := svc.AddRule(context.Background(), tc.token, tc.rule)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if err == nil {
+			assert.NotEmpty(t, saved.ID, fmt.Sprintf("%s: expected non-empty ID", tc.desc))
+			assert.Equal(t, domainID, saved.DomainID, fmt.Sprintf("%s: expected domain %s got %s", tc.desc, domainID, saved.DomainID))
+			assert.Equal(t, userID, saved.CreatedBy, fmt.Sprintf("%s: expected creator %s got %s", tc.desc, userID, saved.CreatedBy))
+		}
+	}
+}
+
+func TestAddRuleUnauthorized(t *testing.T) {
+	auth := new(authmocks.AuthClient)
+	svc := rules.New(auth, mocks.NewRepository(), uuid.NewMock(), new(pubsubmocks.PubSub), notmocks.NewNotifier(), "rules@example.com", true, mglog.NewMock())
+
+	auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: validToken}).Return(&magistrala.IdentityRes{Id: userID, DomainId: domainID}, nil)
+	auth.On("Authorize", mock.Anything, mock.MatchedBy(func(req *magistrala.AuthorizeReq) bool { return req.Object == output })).Return(&magistrala.AuthorizeRes{Authorized: false}, nil)
+	auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+
+	r := newRule(rules.Condition{Type: rules.ThresholdCondition, Field: field, Operator: conditions.GreaterThan, Value: 30}, rules.Action{Type: rules.PublishAction, Channel: output})
+	_, err := svc.AddRule(context.Background(), validToken, r)
+	assert.True(t, errors.Contains(err, svcerr.ErrAuthorization), fmt.Sprintf("add rule publishing to unauthorized channel: expected %s got %s", svcerr.ErrAuthorization, err))
+}
+
+func TestViewUpdateRemoveRule(t *testing.T) {
+	svc, _, _ := newService()
+
+	r := newRule(rules.Condition{Type: rules.ThresholdCondition, Field: field, Operator: conditions.GreaterThan, Value: 30}, rules.Action{Type: rules.NotifyAction, Contacts: []string{"user@example.com"}})
+	saved, err := svc.AddRule(context.Background(), validToken, r)
+	require.Nil(t, err, fmt.Sprintf("add rule unexpected error: %s", err))
+
+	viewed, err := svc.ViewRule(context.Background(), validToken, saved.ID)
+	assert.Nil(t, err, fmt.Sprintf("view rule unexpected error: %s", err))
+	assert.Equal(t, saved, viewed, fmt.Sprintf("view rule: expected %v got %v", saved, viewed))
+
+	update := saved
+	update.Channel = testsutil.GenerateUUID(t)
+	update.Condition.Value = 40
+	updated, err := svc.UpdateRule(context.Background(), validToken, update)
+	assert.Nil(t, err, fmt.Sprintf("update rule unexpected error: %s", err))
+	assert.Equal(t, saved.Channel, updated.Channel, fmt.Sprintf("update rule: expected channel %s got %s", saved.Channel, updated.Channel))
+	assert.Equal(t, float64(40), updated.Condition.Value, fmt.Sprintf("update rule: expected threshold 40 got %f", updated.Condition.Value))
+
+	page, err := svc.ListRules(context.Background(), validToken, rules.PageMetadata{Limit: 10})
+	assert.Nil(t, err, fmt.Sprintf("list rules unexpected error: %s", err))
+	assert.Equal(t, uint64(1), page.Total, fmt.Sprintf("list rules: expected total 1 got %d", page.Total))
+
+	err = svc.RemoveRule(context.Background(), validToken, saved.ID)
+	assert.Nil(t, err, fmt.Sprintf("remove rule unexpected error: %s", err))
+
+	_, err = svc.ViewRule(context.Background(), validToken, saved.ID)
+	assert.True(t, errors.Contains(err, svcerr.ErrNotFound), fmt.Sprintf("view removed rule: expected %s got %s", svcerr.ErrNotFound, err))
+}
+
+func TestConsumeBlocking(t *testing.T) {
+	cases := []struct {
+		desc      string
+		condition rules.Condition
+		msgs      []senml.Message
+		triggered int32
+	}{
+		{
+			desc:      "threshold condition met",
+			condition: rules.Condition{Type: rules.ThresholdCondition, Field: field, Operator: conditions.GreaterThan, Value: 30},
+			msgs:      []senml.Message{senmlMsg(25, 1), senmlMsg(35, 2)},
+			triggered: 1,
+		},
+		{
+			desc:      "threshold condition on another field",
+			condition: rules.Condition{Type: rules.ThresholdCondition, Field: "hum", Operator: conditions.GreaterThan, Value: 30},
+			msgs:      []senml.Message{senmlMsg(35, 1)},
+			triggered: 0,
+		},
+		{
+			desc:      "outside range condition met",
+			condition: rules.Condition{Type: rules.RangeCondition, Field: field, Operator: rules.Outside, Min: 10, Max: 30},
+			msgs:      []senml.Message{senmlMsg(5, 1), senmlMsg(20, 2), senmlMsg(31, 3)},
+			triggered: 2,
+		},
+		{
+			desc:      "rate of change condition met",
+			condition: rules.Condition{Type: rules.RateCondition, Field: field, Operator: conditions.GreaterEqual, Value: 5},
+			msgs:      []senml.Message{senmlMsg(20, 1), senmlMsg(22, 2), senmlMsg(32, 4)},
+			triggered: 1,
+		},
+		{
+			desc:      "window average condition met",
+			condition: rules.Condition{Type: rules.WindowCondition, Field: field, Operator: conditions.GreaterThan, Value: 20, Window: 10, Aggregation: rules.Avg},
+			msgs:      []senml.Message{senmlMsg(10, 1), senmlMsg(20, 2), senmlMsg(40, 3), senmlMsg(45, 20)},
+			triggered: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		var triggered int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			atomic.AddInt32(&triggered, 1)
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		svc, _, _ := newService()
+		_, err := svc.AddRule(context.Background(), validToken, newRule(tc.condition, rules.Action{Type: rules.WebhookAction, URL: ts.URL}))
+		require.Nil(t, err, fmt.Sprintf("%s: add rule unexpected error: %s", tc.desc, err))
+
+		err = svc.ConsumeBlocking(context.Background(), tc.msgs)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.triggered, atomic.LoadInt32(&triggered), fmt.Sprintf("%s: expected %d triggers got %d", tc.desc, tc.triggered, triggered))
+		ts.Close()
+	}
+}
+
+func TestConsumeBlockingPrivateWebhook(t *testing.T) {
+	var triggered int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&triggered, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	auth := new(authmocks.AuthClient)
+	svc := rules.New(auth, mocks.NewRepository(), uuid.NewMock(), new(pubsubmocks.PubSub), notmocks.NewNotifier(), "rules@example.com", false, mglog.NewMock())
+	auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: validToken}).Return(&magistrala.IdentityRes{Id: userID, DomainId: domainID}, nil)
+	auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+
+	r := newRule(rules.Condition{Type: rules.ThresholdCondition, Field: field, Operator: conditions.GreaterThan, Value: 30}, rules.Action{Type: rules.WebhookAction, URL: ts.URL})
+	_, err := svc.AddRule(context.Background(), validToken, r)
+	require.Nil(t, err, fmt.Sprintf("add rule unexpected error: %s", err))
+
+	err = svc.ConsumeBlocking(context.Background(), []senml.Message{senmlMsg(35, 1)})
+	assert.Nil(t, err, fmt.Sprintf("consume messages unexpected error: %s", err))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&triggered), fmt.Sprintf("expected loopback webhook to be refused, got %d triggers", triggered))
+}
+
+func TestConsumeBlockingPublish(t *testing.T) {
+	svc, _, pubsub := newService()
+
+	r := newRule(rules.Condition{Type: rules.ThresholdCondition, Field: field, Operator: conditions.GreaterThan, Value: 30}, rules.Action{Type: rules.PublishAction, Channel: output})
+	_, err := svc.AddRule(context.Background(), validToken, r)
+	require.Nil(t, err, fmt.Sprintf("add rule unexpected error: %s", err))
+
+	repoCall := pubsub.On("Publish", mock.Anything, output, mock.MatchedBy(func(msg *messaging.Message) bool {
+		return msg.Channel == output && msg.Protocol == rules.Protocol
+	})).Return(nil)
+	err = svc.ConsumeBlocking(context.Background(), []senml.Message{senmlMsg(35, 1)})
+	assert.Nil(t, err, fmt.Sprintf("consume messages unexpected error: %s", err))
+	repoCall.Parent.AssertNumberOfCalls(t, "Publish", 1)
+
+	// Alerts published by the rules must not trigger the rules again.
+	msg := senmlMsg(35, 2)
+	msg.Protocol = rules.Protocol
+	err = svc.ConsumeBlocking(context.Background(), []senml.Message{msg})
+	assert.Nil(t, err, fmt.Sprintf("consume alerts unexpected error: %s", err))
+	repoCall.Parent.AssertNumberOfCalls(t, "Publish", 1)
+
+	err = svc.ConsumeBlocking(context.Background(), "invalid")
+	assert.True(t, errors.Contains(err, rules.ErrMessage), fmt.Sprintf("consume invalid messages: expected %s got %s", rules.ErrMessage, err))
+}
+
+func TestConsumeBlockingFailedAction(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	svc, _, pubsub := newService()
+	r := newRule(rules.Condition{Type: rules.ThresholdCondition, Field: field, Operator: conditions.GreaterThan, Value: 30},
+		rules.Action{Type: rules.WebhookAction, URL: ts.URL},
+		rules.Action{Type: rules.PublishAction, Channel: output})
+	_, err := svc.AddRule(context.Background(), validToken, r)
+	require.Nil(t, err, fmt.Sprintf("add rule unexpected error: %s", err))
+
+	// A failed action must neither fail the consumption, which would redeliver
+	// the messages, nor prevent the remaining actions.
+	repoCall := pubsub.On("Publish", mock.Anything, output, mock.Anything).Return(nil)
+	err = svc.ConsumeBlocking(context.Background(), []senml.Message{senmlMsg(35, 1)})
+	assert.Nil(t, err, fmt.Sprintf("consume messages unexpected error: %s", err))
+	repoCall.Parent.AssertNumberOfCalls(t, "Publish", 1)
+}
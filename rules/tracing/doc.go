@@ -0,0 +1,12 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tracing provides tracing instrumentation for Magistrala Rules service.
+//
+// This package provides tracing middleware for Magistrala Rules service.
+// It can be used to trace incoming requests and add tracing capabilities to
+// Magistrala Rules service.
+//
+// For more details about tracing instrumentation for Magistrala messaging refer
+// to the documentation at https://docs.mainflux.io/tracing/.
+package tracing
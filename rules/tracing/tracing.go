@@ -0,0 +1,89 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/absmach/magistrala/rules"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ rules.Service = (*tracingMiddleware)(nil)
+
+type tracingMiddleware struct {
+	tracer trace.Tracer
+	svc    rules.Service
+}
+
+// New returns a new rules service with tracing capabilities.
+func New(svc rules.Service, tracer trace.Tracer) rules.Service {
+	return &tracingMiddleware{tracer, svc}
+}
+
+// AddRule traces the "AddRule" operation of the wrapped rules.Service.
+func (tm *tracingMiddleware) AddRule(ctx context.Context, token string, r rules.Rule) (rules.Rule, error) {
+	ctx, span := tm.tracer.Start(ctx, "svc_add_rule", trace.WithAttributes(
+		attribute.String("name", r.Name),
+		attribute.String("channel", r.Channel),
+		attribute.String("condition", r.Condition.Type),
+	))
+	defer span.End()
+
+	return tm.svc.AddRule(ctx, token, r)
+}
+
+// ViewRule traces the "ViewRule" operation of the wrapped rules.Service.
+func (tm *tracingMiddleware) ViewRule(ctx context.Context, token, id string) (rules.Rule, error) {
+	ctx, span := tm.tracer.Start(ctx, "svc_view_rule", trace.WithAttributes(
+		attribute.String("id", id),
+	))
+	defer span.End()
+
+	return tm.svc.ViewRule(ctx, token, id)
+}
+
+// ListRules traces the "ListRules" operation of the wrapped rules.Service.
+func (tm *tracingMiddleware) ListRules(ctx context.Context, token string, pm rules.PageMetadata) (rules.Page, error) {
+	ctx, span := tm.tracer.Start(ctx, "svc_list_rules", trace.WithAttributes(
+		attribute.Int64("offset", int64(pm.Offset)),
+		attribute.Int64("limit", int64(pm.Limit)),
+		attribute.String("channel", pm.Channel),
+		attribute.String("name", pm.Name),
+	))
+	defer span.End()
+
+	return tm.svc.ListRules(ctx, token, pm)
+}
+
+// UpdateRule traces the "UpdateRule" operation of the wrapped rules.Service.
+func (tm *tracingMiddleware) UpdateRule(ctx context.Context, token string, r rules.Rule) (rules.Rule, error) {
+	ctx, span := tm.tracer.Start(ctx, "svc_update_rule", trace.WithAttributes(
+		attribute.String("id", r.ID),
+		attribute.String("name", r.Name),
+		attribute.String("condition", r.Condition.Type),
+	))
+	defer span.End()
+
+	return tm.svc.UpdateRule(ctx, token, r)
+}
+
+// RemoveRule traces the "RemoveRule" operation of the wrapped rules.Service.
+func (tm *tracingMiddleware) RemoveRule(ctx context.Context, token, id string) error {
+	ctx, span := tm.tracer.Start(ctx, "svc_remove_rule", trace.WithAttributes(
+		attribute.String("id", id),
+	))
+	defer span.End()
+
+	return tm.svc.RemoveRule(ctx, token, id)
+}
+
+// ConsumeBlocking traces the "ConsumeBlocking" operation of the wrapped rules.Service.
+func (tm *tracingMiddleware) ConsumeBlocking(ctx context.Context, messages interface{}) error {
+	ctx, span := tm.tracer.Start(ctx, "svc_consume_messages")
+	defer span.End()
+
+	return tm.svc.ConsumeBlocking(ctx, messages)
+}
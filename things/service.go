@@ -42,7 +42,7 @@ func NewService(uauth magistrala.AuthServiceClient, c postgres.Repository, grepo
 }
 
 func (svc service) Authorize(ctx context.Context, req *magistrala.AuthorizeReq) (string, error) {
-	thingID, err := svc.Identify(ctx, req.GetSubject())
+	thingID, err := svc.authenticate(ctx, req)
 	if err != nil {
 		return "", err
 	}
@@ -584,6 +584,23 @@ func (svc service) Identify(ctx context.Context, key string) (string, error) {
 	return client.ID, nil
 }
 
+// authenticate returns the ID of the authorization request subject thing.
+func (svc service) authenticate(ctx context.Context, req *magistrala.AuthorizeReq) (string, error) {
+	if req.GetSubjectKind() != auth.ThingsKind {
+		return svc.Identify(ctx, req.GetSubject())
+	}
+
+	client, err := svc.clients.RetrieveByID(ctx, req.GetSubject())
+	if err != nil {
+		return "", errors.Wrap(svcerr.ErrAuthorization, err)
+	}
+	if client.Status != mgclients.EnabledStatus {
+		return "", svcerr.ErrAuthorization
+	}
+
+	return client.ID, nil
+}
+
 func (svc service) identify(ctx context.Context, token string) (*magistrala.IdentityRes, error) {
 	res, err := svc.auth.Identify(ctx, &magistrala.IdentityReq{Token: token})
 	if err != nil {
@@ -2023,6 +2023,8 @@ func TestAuthorize(t *testing.T) {
 		err         error
 		identifyErr error
 		authErr     error
+		retrieveRes mgclients.Client
+		retrieveErr error
 	}{
 		{
 			desc:     "authorize client with valid key",
@@ -2056,6 +2058,27 @@ func TestAuthorize(t *testing.T) {
 			authErr:  nil,
 			err:      svcerr.ErrAuthorization,
 		},
+		{
+			desc:        "authorize client with valid ID",
+			request:     &magistrala.AuthorizeReq{SubjectKind: authsvc.ThingsKind, Subject: client.ID, Object: valid, Permission: "publish"},
+			response:    &magistrala.AuthorizeRes{Authorized: true},
+			retrieveRes: mgclients.Client{ID: client.ID, Status: mgclients.EnabledStatus},
+			err:         nil,
+		},
+		{
+			desc:        "authorize disabled client with ID",
+			request:     &magistrala.AuthorizeReq{SubjectKind: authsvc.ThingsKind, Subject: client.ID, Object: valid, Permission: "publish"},
+			response:    &magistrala.AuthorizeRes{Authorized: true},
+			retrieveRes: mgclients.Client{ID: client.ID, Status: mgclients.DisabledStatus},
+			err:         svcerr.ErrAuthorization,
+		},
+		{
+			desc:        "authorize client with invalid ID",
+			request:     &magistrala.AuthorizeReq{SubjectKind: authsvc.ThingsKind, Subject: invalid, Object: valid, Permission: "publish"},
+			response:    &magistrala.AuthorizeRes{Authorized: true},
+			retrieveErr: repoerr.ErrNotFound,
+			err:         svcerr.ErrAuthorization,
+		},
 	}
 
 	for _, tc := range cases {
@@ -2063,12 +2086,14 @@ func TestAuthorize(t *testing.T) {
 		repoCall1 := cRepo.On("RetrieveBySecret", mock.Anything, mock.Anything).Return(mgclients.Client{ID: tc.clientID}, tc.identifyErr)
 		repoCall2 := cache.On("Save", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 		repoCall3 := auth.On("Authorize", mock.Anything, mock.Anything).Return(tc.response, tc.authErr)
+		repoCall4 := cRepo.On("RetrieveByID", mock.Anything, tc.request.GetSubject()).Return(tc.retrieveRes, tc.retrieveErr)
 		_, err := svc.Authorize(context.Background(), tc.request)
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 		repoCall.Unset()
 		repoCall1.Unset()
 		repoCall2.Unset()
 		repoCall3.Unset()
+		repoCall4.Unset()
 	}
 }
 
@@ -58,6 +58,8 @@ type Service interface {
 	Identify(ctx context.Context, key string) (string, error)
 
 	// Authorize used for AuthZ gRPC server implementation and Things authorization.
+	// The subject is the thing key, or the thing ID if the subject kind is
+	// things, as used for the things authenticated by the client certificate.
 	Authorize(ctx context.Context, req *magistrala.AuthorizeReq) (string, error)
 
 	// DeleteClient deletes client with given ID.
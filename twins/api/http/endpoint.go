@@ -74,6 +74,7 @@ func viewTwinEndpoint(svc twins.Service) endpoint.Endpoint {
 
 		res := viewTwinRes{
 			Owner:       twin.Owner,
+			Domain:      twin.Domain,
 			ID:          twin.ID,
 			Name:        twin.Name,
 			Created:     twin.Created,
@@ -81,6 +82,7 @@ func viewTwinEndpoint(svc twins.Service) endpoint.Endpoint {
 			Revision:    twin.Revision,
 			Definitions: twin.Definitions,
 			Metadata:    twin.Metadata,
+			Desired:     desired(twin),
 		}
 		return res, nil
 	}
@@ -110,6 +112,7 @@ func listTwinsEndpoint(svc twins.Service) endpoint.Endpoint {
 		for _, twin := range page.Twins {
 			view := viewTwinRes{
 				Owner:       twin.Owner,
+				Domain:      twin.Domain,
 				ID:          twin.ID,
 				Name:        twin.Name,
 				Created:     twin.Created,
@@ -117,6 +120,7 @@ func listTwinsEndpoint(svc twins.Service) endpoint.Endpoint {
 				Revision:    twin.Revision,
 				Definitions: twin.Definitions,
 				Metadata:    twin.Metadata,
+				Desired:     desired(twin),
 			}
 			res.Twins = append(res.Twins, view)
 		}
@@ -150,7 +154,7 @@ func listStatesEndpoint(svc twins.Service) endpoint.Endpoint {
 			return nil, errors.Wrap(apiutil.ErrValidation, err)
 		}
 
-		page, err := svc.ListStates(ctx, req.token, req.offset, req.limit, req.id)
+		page, err := svc.ListStates(ctx, req.token, req.offset, req.limit, req.id, req.filter)
 		if err != nil {
 			return nil, err
 		}
@@ -177,3 +181,111 @@ func listStatesEndpoint(svc twins.Service) endpoint.Endpoint {
 		return res, nil
 	}
 }
+
+func viewSnapshotEndpoint(svc twins.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(snapshotReq)
+
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		snap, err := svc.ViewSnapshot(ctx, req.token, req.id, req.at, req.attributes)
+		if err != nil {
+			return nil, err
+		}
+
+		res := snapshotRes{
+			State: viewStateRes{
+				TwinID:     snap.State.TwinID,
+				ID:         snap.State.ID,
+				Definition: snap.State.Definition,
+				Created:    snap.State.Created,
+				Payload:    snap.State.Payload,
+			},
+			Definition: snap.Definition,
+		}
+
+		return res, nil
+	}
+}
+
+func setDesiredEndpoint(svc twins.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(setDesiredReq)
+
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		desired := twins.Desired{
+			Attributes: req.Attributes,
+			Channel:    req.Channel,
+			Subtopic:   req.Subtopic,
+		}
+		delta, err := svc.SetDesired(ctx, req.token, req.id, desired)
+		if err != nil {
+			return nil, err
+		}
+
+		return deltaRes{Delta: delta}, nil
+	}
+}
+
+func viewDeltaEndpoint(svc twins.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewTwinReq)
+
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		delta, err := svc.ViewDelta(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return deltaRes{Delta: delta}, nil
+	}
+}
+
+// desired returns the twin desired state, or nil if it was never set.
+func desired(twin twins.Twin) *twins.Desired {
+	if twin.Desired.Version == 0 {
+		return nil
+	}
+
+	return &twin.Desired
+}
+
+func shareTwinEndpoint(svc twins.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(shareTwinReq)
+
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		if err := svc.ShareTwin(ctx, req.token, req.id, req.Relation, req.UserIDs...); err != nil {
+			return nil, err
+		}
+
+		return shareRes{}, nil
+	}
+}
+
+func unshareTwinEndpoint(svc twins.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(shareTwinReq)
+
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		if err := svc.UnshareTwin(ctx, req.token, req.id, req.Relation, req.UserIDs...); err != nil {
+			return nil, err
+		}
+
+		return unshareRes{}, nil
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/absmach/magistrala"
+	authmocks "github.com/absmach/magistrala/auth/mocks"
+	svcerr "github.com/absmach/magistrala/pkg/errors/service"
+	"github.com/absmach/magistrala/twins"
+	"github.com/absmach/magistrala/twins/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type desiredReq struct {
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Channel    string                 `json:"channel,omitempty"`
+	Subtopic   string                 `json:"subtopic,omitempty"`
+}
+
+type deltaRes struct {
+	TwinID     string                 `json:"twin_id"`
+	Version    uint64                 `json:"version"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+func TestSetDesired(t *testing.T) {
+	svc, auth := mocks.NewService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	def := mocks.CreateDefinition([]string{"chanID"}, []string{"engine"})
+	def.Attributes[0].Name = "temperature"
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
+	stw, err := svc.AddTwin(context.Background(), token, twins.Twin{}, def)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
+
+	data, err := toJSON(desiredReq{Attributes: map[string]interface{}{"temperature": 25.0}, Channel: "control", Subtopic: "commands"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	unknownData, err := toJSON(desiredReq{Attributes: map[string]interface{}{"pressure": 1.0}, Channel: "control"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	noChanData, err := toJSON(desiredReq{Attributes: map[string]interface{}{"temperature": 25.0}, Subtopic: "commands"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc        string
+		req         string
+		id          string
+		contentType string
+		auth        string
+		authErr     error
+		status      int
+		res         deltaRes
+	}{
+		{
+			desc:        "set desired state of existing twin",
+			req:         data,
+			id:          stw.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			res:         deltaRes{TwinID: stw.ID, Version: 1, Attributes: map[string]interface{}{"temperature": 25.0}},
+		},
+		{
+			desc:        "set desired state of unknown attribute",
+			req:         unknownData,
+			id:          stw.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "set desired state with subtopic and without channel",
+			req:         noChanData,
+			id:          stw.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "set desired state of non-existent twin",
+			req:         data,
+			id:          strconv.FormatUint(wrongID, 10),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "set desired state with invalid token",
+			req:         data,
+			id:          stw.ID,
+			contentType: contentType,
+			auth:        authmocks.InvalidValue,
+			authErr:     svcerr.ErrAuthentication,
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "set desired state with empty token",
+			req:         data,
+			id:          stw.ID,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "set desired state with invalid data format",
+			req:         "{",
+			id:          stw.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "set desired state without content type",
+			req:         data,
+			id:          stw.ID,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.auth}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, tc.authErr)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/twins/%s/desired", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status == http.StatusOK {
+			var resData deltaRes
+			err := json.NewDecoder(res.Body).Decode(&resData)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			assert.Equal(t, tc.res, resData, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, resData))
+		}
+		repoCall.Unset()
+		repoCall1.Unset()
+	}
+}
+
+func TestViewDelta(t *testing.T) {
+	svc, auth := mocks.NewService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	def := mocks.CreateDefinition([]string{"chanID"}, []string{"engine"})
+	def.Attributes[0].Name = "temperature"
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
+	stw, err := svc.AddTwin(context.Background(), token, twins.Twin{}, def)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = svc.SetDesired(context.Background(), token, stw.ID, twins.Desired{Attributes: map[string]interface{}{"temperature": 25.0}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
+
+	cases := []struct {
+		desc    string
+		id      string
+		auth    string
+		authErr error
+		status  int
+		res     deltaRes
+	}{
+		{
+			desc:   "view delta of existing twin",
+			id:     stw.ID,
+			auth:   token,
+			status: http.StatusOK,
+			res:    deltaRes{TwinID: stw.ID, Version: 1, Attributes: map[string]interface{}{"temperature": 25.0}},
+		},
+		{
+			desc:   "view delta of non-existent twin",
+			id:     strconv.FormatUint(wrongID, 10),
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:    "view delta with invalid token",
+			id:      stw.ID,
+			auth:    authmocks.InvalidValue,
+			authErr: svcerr.ErrAuthentication,
+			status:  http.StatusUnauthorized,
+		},
+		{
+			desc:   "view delta with empty token",
+			id:     stw.ID,
+			auth:   "",
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.auth}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, tc.authErr)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/twins/%s/delta", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status == http.StatusOK {
+			var resData deltaRes
+			err := json.NewDecoder(res.Body).Decode(&resData)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			assert.Equal(t, tc.res, resData, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, resData))
+		}
+		repoCall.Unset()
+		repoCall1.Unset()
+	}
+}
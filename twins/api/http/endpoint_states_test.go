@@ -8,11 +8,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/absmach/magistrala"
 	authmocks "github.com/absmach/magistrala/auth/mocks"
-	"github.com/absmach/magistrala/internal/testsutil"
 	"github.com/absmach/magistrala/twins"
 	"github.com/absmach/magistrala/twins/mocks"
 	"github.com/absmach/senml"
@@ -40,6 +41,11 @@ type statesPageRes struct {
 	States []stateRes `json:"states"`
 }
 
+type snapshotRes struct {
+	State      stateRes         `json:"state"`
+	Definition twins.Definition `json:"definition"`
+}
+
 func TestListStates(t *testing.T) {
 	svc, auth := mocks.NewService()
 	ts := newServer(svc)
@@ -49,10 +55,14 @@ func TestListStates(t *testing.T) {
 		Owner: email,
 	}
 	def := mocks.CreateDefinition(channels[0:2], subtopics[0:2])
-	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
 	tw, err := svc.AddTwin(context.Background(), token, twin, def)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
 	attr := def.Attributes[0]
 
 	recs := make([]senml.Record, numRecs)
@@ -70,6 +80,8 @@ func TestListStates(t *testing.T) {
 
 	baseURL := fmt.Sprintf("%s/states/%s", ts.URL, tw.ID)
 	queryFmt := "%s?offset=%d&limit=%d"
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
 	cases := []struct {
 		desc   string
 		auth   string
@@ -175,6 +187,55 @@ func TestListStates(t *testing.T) {
 			url:    fmt.Sprintf("%s%s", baseURL, "?offset=4&limit=4&limit=5&offset=5"),
 			res:    nil,
 		},
+		{
+			desc:   "get a list of states created within time range",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?limit=%d&from=%s&to=%s", baseURL, 5, past, future),
+			res:    data[0:5],
+		},
+		{
+			desc:   "get a list of states created from future time",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?from=%s", baseURL, future),
+			res:    []stateRes{},
+		},
+		{
+			desc:   "get a list of states with invalid time range",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?from=%s&to=%s", baseURL, future, past),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of states with invalid from time",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?from=invalid", baseURL),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of states recorded with definition",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?limit=%d&definition=%d", baseURL, 5, 0),
+			res:    data[0:5],
+		},
+		{
+			desc:   "get a list of states recorded with unknown definition",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?definition=%d", baseURL, 1),
+			res:    []stateRes{},
+		},
+		{
+			desc:   "get a list of states with invalid definition",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?definition=invalid", baseURL),
+			res:    nil,
+		},
 		{
 			desc:   "get a list of states with redundant query parameters",
 			auth:   token,
@@ -185,7 +246,8 @@ func TestListStates(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		repoCall := auth.On("Identify", mock.Anything, mock.Anything).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+		repoCall := auth.On("Identify", mock.Anything, mock.Anything).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
 		req := testRequest{
 			client: ts.Client(),
 			method: http.MethodGet,
@@ -204,6 +266,124 @@ func TestListStates(t *testing.T) {
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
 		assert.ElementsMatch(t, tc.res, resData.States, fmt.Sprintf("%s: got incorrect body from response", tc.desc))
 		repoCall.Unset()
+		repoCall1.Unset()
+	}
+}
+
+func TestViewSnapshot(t *testing.T) {
+	svc, auth := mocks.NewService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	def := mocks.CreateDefinition(channels[0:1], subtopics[0:1])
+	def.Attributes[0].Name = "temperature"
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
+	tw, err := svc.AddTwin(context.Background(), token, twins.Twin{Owner: email}, def)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
+
+	t0 := time.Now().Add(-time.Hour).Truncate(time.Second)
+	recs := make([]senml.Record, 3)
+	for i := range recs {
+		v := float64(i)
+		recs[i] = senml.Record{BaseTime: float64(t0.Unix()), Time: float64(i * 60), Value: &v}
+	}
+	message, err := mocks.CreateMessage(def.Attributes[0], recs)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.SaveStates(context.Background(), message)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	baseURL := fmt.Sprintf("%s/states/%s/snapshot", ts.URL, tw.ID)
+	cases := []struct {
+		desc   string
+		auth   string
+		status int
+		url    string
+		state  int64
+		value  float64
+	}{
+		{
+			desc:   "view snapshot at time",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?at=%s", baseURL, t0.Add(90*time.Second).UTC().Format(time.RFC3339)),
+			state:  1,
+			value:  1,
+		},
+		{
+			desc:   "view snapshot without time",
+			auth:   token,
+			status: http.StatusOK,
+			url:    baseURL,
+			state:  2,
+			value:  2,
+		},
+		{
+			desc:   "view snapshot limited to attributes",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?attributes=temperature", baseURL),
+			state:  2,
+			value:  2,
+		},
+		{
+			desc:   "view snapshot before the first state",
+			auth:   token,
+			status: http.StatusNotFound,
+			url:    fmt.Sprintf("%s?at=%s", baseURL, t0.Add(-time.Minute).UTC().Format(time.RFC3339)),
+		},
+		{
+			desc:   "view snapshot with invalid time",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?at=invalid", baseURL),
+		},
+		{
+			desc:   "view snapshot of non-existent twin",
+			auth:   token,
+			status: http.StatusNotFound,
+			url:    fmt.Sprintf("%s/states/%s/snapshot", ts.URL, strconv.FormatUint(wrongID, 10)),
+		},
+		{
+			desc:   "view snapshot with invalid token",
+			auth:   authmocks.InvalidValue,
+			status: http.StatusUnauthorized,
+			url:    baseURL,
+		},
+		{
+			desc:   "view snapshot with empty token",
+			auth:   "",
+			status: http.StatusUnauthorized,
+			url:    baseURL,
+		},
+	}
+
+	for _, tc := range cases {
+		repoCall := auth.On("Identify", mock.Anything, mock.Anything).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status == http.StatusOK {
+			var resData snapshotRes
+			err = json.NewDecoder(res.Body).Decode(&resData)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			assert.Equal(t, tc.state, resData.State.ID, fmt.Sprintf("%s: expected state %d got %d", tc.desc, tc.state, resData.State.ID))
+			assert.Equal(t, tc.value, resData.State.Payload["temperature"], fmt.Sprintf("%s: expected value %v got %v", tc.desc, tc.value, resData.State.Payload["temperature"]))
+			assert.Equal(t, def.Attributes, resData.Definition.Attributes, fmt.Sprintf("%s: expected attributes %v got %v", tc.desc, def.Attributes, resData.Definition.Attributes))
+		}
+		repoCall.Unset()
+		repoCall1.Unset()
 	}
 }
 
@@ -37,7 +37,11 @@ const (
 	instanceID  = "5de9b29a-feb9-11ed-be56-0242ac120002"
 )
 
-var invalidName = strings.Repeat("m", maxNameSize+1)
+var (
+	invalidName = strings.Repeat("m", maxNameSize+1)
+	validID     = testsutil.GenerateUUID(&testing.T{})
+	domainID    = testsutil.GenerateUUID(&testing.T{})
+)
 
 type twinReq struct {
 	Name     string                 `json:"name,omitempty"`
@@ -113,6 +117,8 @@ func TestAddTwin(t *testing.T) {
 	invalidData, err := toJSON(tw)
 	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	invalidExpr := `{"definition":{"attributes":[{"name":"voltage","channel":"chan","subtopic":"volt","persist_state":true},{"name":"power","expression":"voltage *","persist_state":true}]}}`
+
 	cases := []struct {
 		desc        string
 		req         string
@@ -185,10 +191,20 @@ func TestAddTwin(t *testing.T) {
 			status:      http.StatusBadRequest,
 			location:    "",
 		},
+		{
+			desc:        "add twin with invalid computed attribute expression",
+			req:         invalidExpr,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
 	}
 
 	for _, tc := range cases {
-		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.auth}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.auth}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
 		req := testRequest{
 			client:      ts.Client(),
 			method:      http.MethodPost,
@@ -204,6 +220,8 @@ func TestAddTwin(t *testing.T) {
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
 		assert.Equal(t, tc.location, location, fmt.Sprintf("%s: expected location %s got %s", tc.desc, tc.location, location))
 		repoCall.Unset()
+		repoCall1.Unset()
+		repoCall2.Unset()
 	}
 }
 
@@ -214,10 +232,14 @@ func TestUpdateTwin(t *testing.T) {
 
 	twin := twins.Twin{}
 	def := twins.Definition{}
-	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
 	stw, err := svc.AddTwin(context.Background(), token, twin, def)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
 
 	twin.Name = twinName
 	data, err := toJSON(twin)
@@ -310,7 +332,8 @@ func TestUpdateTwin(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.auth}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.auth}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
 		req := testRequest{
 			client:      ts.Client(),
 			method:      http.MethodPut,
@@ -323,6 +346,7 @@ func TestUpdateTwin(t *testing.T) {
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
 		repoCall.Unset()
+		repoCall1.Unset()
 	}
 }
 
@@ -333,10 +357,14 @@ func TestViewTwin(t *testing.T) {
 
 	def := twins.Definition{}
 	twin := twins.Twin{}
-	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
 	stw, err := svc.AddTwin(context.Background(), token, twin, def)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
 
 	twres := twinRes{
 		Owner:    stw.Owner,
@@ -384,7 +412,8 @@ func TestViewTwin(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.auth}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.auth}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
 		req := testRequest{
 			client: ts.Client(),
 			method: http.MethodGet,
@@ -400,6 +429,7 @@ func TestViewTwin(t *testing.T) {
 		assert.Nil(t, err, fmt.Sprintf("%s: got unexpected error while decoding response body: %s\n", tc.desc, err))
 		assert.Equal(t, tc.res, resData, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, resData))
 		repoCall.Unset()
+		repoCall1.Unset()
 	}
 }
 
@@ -416,10 +446,14 @@ func TestListTwins(t *testing.T) {
 			Owner: email,
 			Name:  name,
 		}
-		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: userID}, nil)
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: userID, UserId: userID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
 		tw, err := svc.AddTwin(context.Background(), token, twin, twins.Definition{})
 		assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 		repoCall.Unset()
+		repoCall1.Unset()
+		repoCall2.Unset()
 		twres := twinRes{
 			Owner:    tw.Owner,
 			ID:       tw.ID,
@@ -561,7 +595,8 @@ func TestListTwins(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.auth}).Return(&magistrala.IdentityRes{Id: userID}, nil)
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.auth}).Return(&magistrala.IdentityRes{Id: userID, UserId: userID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
 		req := testRequest{
 			client: ts.Client(),
 			method: http.MethodGet,
@@ -580,6 +615,7 @@ func TestListTwins(t *testing.T) {
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
 		assert.ElementsMatch(t, tc.res, resData.Twins, fmt.Sprintf("%s: got incorrect list of twins", tc.desc))
 		repoCall.Unset()
+		repoCall1.Unset()
 	}
 }
 
@@ -590,10 +626,14 @@ func TestRemoveTwin(t *testing.T) {
 
 	def := twins.Definition{}
 	twin := twins.Twin{}
-	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
 	stw, err := svc.AddTwin(context.Background(), token, twin, def)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
 
 	cases := []struct {
 		desc   string
@@ -634,7 +674,9 @@ func TestRemoveTwin(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.auth}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.auth}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		repoCall3 := auth.On("DeletePolicy", mock.Anything, mock.Anything).Return(&magistrala.DeletePolicyRes{Deleted: true}, nil)
 		req := testRequest{
 			client: ts.Client(),
 			method: http.MethodDelete,
@@ -645,5 +687,120 @@ func TestRemoveTwin(t *testing.T) {
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
 		repoCall.Unset()
+		repoCall1.Unset()
+		repoCall3.Unset()
+	}
+}
+
+func TestShareTwin(t *testing.T) {
+	svc, auth := mocks.NewService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
+	stw, err := svc.AddTwin(context.Background(), token, twins.Twin{}, twins.Definition{})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
+
+	data := fmt.Sprintf(`{"relation": "viewer", "user_ids": ["%s"]}`, testsutil.GenerateUUID(t))
+
+	cases := []struct {
+		desc        string
+		action      string
+		req         string
+		contentType string
+		auth        string
+		status      int
+	}{
+		{
+			desc:        "share twin",
+			action:      "share",
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+		},
+		{
+			desc:        "unshare twin",
+			action:      "unshare",
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNoContent,
+		},
+		{
+			desc:        "share twin with invalid token",
+			action:      "share",
+			req:         data,
+			contentType: contentType,
+			auth:        authmocks.InvalidValue,
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "share twin with invalid relation",
+			action:      "share",
+			req:         fmt.Sprintf(`{"relation": "owner", "user_ids": ["%s"]}`, validID),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "share twin without relation",
+			action:      "share",
+			req:         fmt.Sprintf(`{"user_ids": ["%s"]}`, validID),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "share twin without users",
+			action:      "share",
+			req:         `{"relation": "viewer"}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "share twin with invalid request format",
+			action:      "share",
+			req:         "}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "share twin without content type",
+			action:      "share",
+			req:         data,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.auth}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
+		repoCall3 := auth.On("DeletePolicies", mock.Anything, mock.Anything).Return(&magistrala.DeletePoliciesRes{Deleted: true}, nil)
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/twins/%s/%s", ts.URL, stw.ID, tc.action),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		repoCall.Unset()
+		repoCall1.Unset()
+		repoCall2.Unset()
+		repoCall3.Unset()
 	}
 }
@@ -4,7 +4,11 @@
 package http
 
 import (
+	"time"
+
 	"github.com/absmach/magistrala/internal/apiutil"
+	"github.com/absmach/magistrala/pkg/errors"
+	svcerr "github.com/absmach/magistrala/pkg/errors/service"
 	"github.com/absmach/magistrala/twins"
 )
 
@@ -13,6 +17,8 @@ const (
 	maxLimitSize = 100
 )
 
+var errMissingControlChannel = errors.New("missing desired state control channel")
+
 type addTwinReq struct {
 	token      string
 	Name       string                 `json:"name,omitempty"`
@@ -102,6 +108,7 @@ type listStatesReq struct {
 	offset uint64
 	limit  uint64
 	id     string
+	filter twins.StatesFilter
 }
 
 func (req *listStatesReq) validate() error {
@@ -117,5 +124,79 @@ func (req *listStatesReq) validate() error {
 		return apiutil.ErrLimitSize
 	}
 
+	if !req.filter.From.IsZero() && !req.filter.To.IsZero() && req.filter.From.After(req.filter.To) {
+		return apiutil.ErrInvalidQueryParams
+	}
+
+	return nil
+}
+
+type snapshotReq struct {
+	token      string
+	id         string
+	at         time.Time
+	attributes []string
+}
+
+func (req snapshotReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.id == "" {
+		return apiutil.ErrMissingID
+	}
+
+	return nil
+}
+
+type setDesiredReq struct {
+	token      string
+	id         string
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Channel    string                 `json:"channel,omitempty"`
+	Subtopic   string                 `json:"subtopic,omitempty"`
+}
+
+func (req setDesiredReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.id == "" {
+		return apiutil.ErrMissingID
+	}
+
+	if req.Channel == "" && req.Subtopic != "" {
+		return errors.Wrap(svcerr.ErrMalformedEntity, errMissingControlChannel)
+	}
+
+	return nil
+}
+
+type shareTwinReq struct {
+	token    string
+	id       string
+	Relation string   `json:"relation,omitempty"`
+	UserIDs  []string `json:"user_ids,omitempty"`
+}
+
+func (req shareTwinReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.id == "" {
+		return apiutil.ErrMissingID
+	}
+
+	if req.Relation == "" {
+		return apiutil.ErrMissingRelation
+	}
+
+	if len(req.UserIDs) == 0 {
+		return apiutil.ErrEmptyList
+	}
+
 	return nil
 }
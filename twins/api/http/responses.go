@@ -19,6 +19,10 @@ var (
 	_ magistrala.Response = (*twinsPageRes)(nil)
 	_ magistrala.Response = (*statesPageRes)(nil)
 	_ magistrala.Response = (*removeRes)(nil)
+	_ magistrala.Response = (*deltaRes)(nil)
+	_ magistrala.Response = (*snapshotRes)(nil)
+	_ magistrala.Response = (*shareRes)(nil)
+	_ magistrala.Response = (*unshareRes)(nil)
 )
 
 type twinRes struct {
@@ -50,6 +54,7 @@ func (res twinRes) Empty() bool {
 
 type viewTwinRes struct {
 	Owner       string                 `json:"owner,omitempty"`
+	Domain      string                 `json:"domain_id,omitempty"`
 	ID          string                 `json:"id"`
 	Name        string                 `json:"name,omitempty"`
 	Revision    int                    `json:"revision"`
@@ -57,6 +62,7 @@ type viewTwinRes struct {
 	Updated     time.Time              `json:"updated"`
 	Definitions []twins.Definition     `json:"definitions,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Desired     *twins.Desired         `json:"desired,omitempty"`
 }
 
 func (res viewTwinRes) Code() int {
@@ -144,3 +150,64 @@ func (res removeRes) Headers() map[string]string {
 func (res removeRes) Empty() bool {
 	return true
 }
+
+type deltaRes struct {
+	twins.Delta
+}
+
+func (res deltaRes) Code() int {
+	return http.StatusOK
+}
+
+func (res deltaRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res deltaRes) Empty() bool {
+	return false
+}
+
+type snapshotRes struct {
+	State      viewStateRes     `json:"state"`
+	Definition twins.Definition `json:"definition"`
+}
+
+func (res snapshotRes) Code() int {
+	return http.StatusOK
+}
+
+func (res snapshotRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res snapshotRes) Empty() bool {
+	return false
+}
+
+type shareRes struct{}
+
+func (res shareRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res shareRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res shareRes) Empty() bool {
+	return true
+}
+
+type unshareRes struct{}
+
+func (res unshareRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res unshareRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res unshareRes) Empty() bool {
+	return true
+}
@@ -9,6 +9,7 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/absmach/magistrala"
 	"github.com/absmach/magistrala/internal/apiutil"
@@ -27,6 +28,11 @@ const (
 	limitKey    = "limit"
 	nameKey     = "name"
 	metadataKey = "metadata"
+	fromKey     = "from"
+	toKey       = "to"
+	atKey       = "at"
+	defKey      = "definition"
+	attrsKey    = "attributes"
 	defLimit    = 10
 	defOffset   = 0
 )
@@ -70,6 +76,30 @@ func MakeHandler(svc twins.Service, logger *slog.Logger, instanceID string) http
 			encodeResponse,
 			opts...,
 		), "remove_twin").ServeHTTP)
+		r.Put("/{twinID}/desired", otelhttp.NewHandler(kithttp.NewServer(
+			setDesiredEndpoint(svc),
+			decodeSetDesired,
+			encodeResponse,
+			opts...,
+		), "set_desired").ServeHTTP)
+		r.Get("/{twinID}/delta", otelhttp.NewHandler(kithttp.NewServer(
+			viewDeltaEndpoint(svc),
+			decodeView,
+			encodeResponse,
+			opts...,
+		), "view_delta").ServeHTTP)
+		r.Post("/{twinID}/share", otelhttp.NewHandler(kithttp.NewServer(
+			shareTwinEndpoint(svc),
+			decodeShareTwin,
+			encodeResponse,
+			opts...,
+		), "share_twin").ServeHTTP)
+		r.Post("/{twinID}/unshare", otelhttp.NewHandler(kithttp.NewServer(
+			unshareTwinEndpoint(svc),
+			decodeShareTwin,
+			encodeResponse,
+			opts...,
+		), "unshare_twin").ServeHTTP)
 	})
 	r.Get("/states/{twinID}", otelhttp.NewHandler(kithttp.NewServer(
 		listStatesEndpoint(svc),
@@ -77,6 +107,12 @@ func MakeHandler(svc twins.Service, logger *slog.Logger, instanceID string) http
 		encodeResponse,
 		opts...,
 	), "list_states").ServeHTTP)
+	r.Get("/states/{twinID}/snapshot", otelhttp.NewHandler(kithttp.NewServer(
+		viewSnapshotEndpoint(svc),
+		decodeSnapshot,
+		encodeResponse,
+		opts...,
+	), "view_snapshot").ServeHTTP)
 
 	r.Get("/health", magistrala.Health("twins", instanceID))
 	r.Handle("/metrics", promhttp.Handler())
@@ -113,6 +149,38 @@ func decodeTwinUpdate(_ context.Context, r *http.Request) (interface{}, error) {
 	return req, nil
 }
 
+func decodeSetDesired(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.Wrap(apiutil.ErrValidation, apiutil.ErrUnsupportedContentType)
+	}
+
+	req := setDesiredReq{
+		token: apiutil.ExtractBearerToken(r),
+		id:    chi.URLParam(r, "twinID"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, errors.Wrap(svcerr.ErrMalformedEntity, err))
+	}
+
+	return req, nil
+}
+
+func decodeShareTwin(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.Wrap(apiutil.ErrValidation, apiutil.ErrUnsupportedContentType)
+	}
+
+	req := shareTwinReq{
+		token: apiutil.ExtractBearerToken(r),
+		id:    chi.URLParam(r, "twinID"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, errors.Wrap(svcerr.ErrMalformedEntity, err))
+	}
+
+	return req, nil
+}
+
 func decodeView(_ context.Context, r *http.Request) (interface{}, error) {
 	req := viewTwinReq{
 		token: apiutil.ExtractBearerToken(r),
@@ -165,16 +233,102 @@ func decodeListStates(_ context.Context, r *http.Request) (interface{}, error) {
 		return nil, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
+	from, err := readTimeQuery(r, fromKey)
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	to, err := readTimeQuery(r, toKey)
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	d, err := apiutil.ReadNumQuery[int64](r, defKey, -1)
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	attrs, err := readAttributesQuery(r)
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
 	req := listStatesReq{
 		token:  apiutil.ExtractBearerToken(r),
 		limit:  l,
 		offset: o,
 		id:     chi.URLParam(r, "twinID"),
+		filter: twins.StatesFilter{
+			From:       from,
+			To:         to,
+			Attributes: attrs,
+		},
+	}
+	if d >= 0 {
+		def := int(d)
+		req.filter.Definition = &def
 	}
 
 	return req, nil
 }
 
+func decodeSnapshot(_ context.Context, r *http.Request) (interface{}, error) {
+	at, err := readTimeQuery(r, atKey)
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	attrs, err := readAttributesQuery(r)
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	req := snapshotReq{
+		token:      apiutil.ExtractBearerToken(r),
+		id:         chi.URLParam(r, "twinID"),
+		at:         at,
+		attributes: attrs,
+	}
+
+	return req, nil
+}
+
+// readTimeQuery reads the RFC3339 formatted time query parameter. Zero time
+// is returned if the parameter is not set.
+func readTimeQuery(r *http.Request, key string) (time.Time, error) {
+	val, err := apiutil.ReadStringQuery(r, key, "")
+	if err != nil || val == "" {
+		return time.Time{}, err
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, val)
+	if err != nil {
+		return time.Time{}, errors.Wrap(apiutil.ErrInvalidQueryParams, err)
+	}
+
+	return t, nil
+}
+
+// readAttributesQuery reads the comma separated list of attribute names.
+func readAttributesQuery(r *http.Request) ([]string, error) {
+	val, err := apiutil.ReadStringQuery(r, attrsKey, "")
+	if err != nil || val == "" {
+		return nil, err
+	}
+
+	var attrs []string
+	for _, attr := range strings.Split(val, ",") {
+		if attr = strings.TrimSpace(attr); attr != "" {
+			attrs = append(attrs, attr)
+		}
+	}
+
+	return attrs, nil
+}
+
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", contentType)
 
@@ -203,6 +357,9 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	case errors.Contains(err, svcerr.ErrAuthentication),
 		errors.Contains(err, apiutil.ErrBearerToken):
 		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, svcerr.ErrAuthorization),
+		errors.Contains(err, errors.ErrDomainAuthorization):
+		w.WriteHeader(http.StatusForbidden)
 	case errors.Contains(err, apiutil.ErrInvalidQueryParams):
 		w.WriteHeader(http.StatusBadRequest)
 	case errors.Contains(err, apiutil.ErrUnsupportedContentType):
@@ -210,7 +367,9 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	case errors.Contains(err, svcerr.ErrMalformedEntity),
 		errors.Contains(err, apiutil.ErrMissingID),
 		errors.Contains(err, apiutil.ErrNameSize),
-		errors.Contains(err, apiutil.ErrLimitSize):
+		errors.Contains(err, apiutil.ErrLimitSize),
+		errors.Contains(err, apiutil.ErrMissingRelation),
+		errors.Contains(err, apiutil.ErrEmptyList):
 		w.WriteHeader(http.StatusBadRequest)
 	case errors.Contains(err, svcerr.ErrNotFound):
 		w.WriteHeader(http.StatusNotFound)
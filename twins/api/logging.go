@@ -107,6 +107,47 @@ func (lm *loggingMiddleware) ListTwins(ctx context.Context, token string, offset
 	return lm.svc.ListTwins(ctx, token, offset, limit, name, metadata)
 }
 
+func (lm *loggingMiddleware) SetDesired(ctx context.Context, token, twinID string, desired twins.Desired) (delta twins.Delta, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("twin_id", twinID),
+			slog.Group("desired",
+				slog.String("channel", desired.Channel),
+				slog.String("subtopic", desired.Subtopic),
+				slog.Uint64("version", delta.Version),
+			),
+			slog.Int("delta_size", len(delta.Attributes)),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Set desired state failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Set desired state completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.SetDesired(ctx, token, twinID, desired)
+}
+
+func (lm *loggingMiddleware) ViewDelta(ctx context.Context, token, twinID string) (delta twins.Delta, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("twin_id", twinID),
+			slog.Int("delta_size", len(delta.Attributes)),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("View delta failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("View delta completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.ViewDelta(ctx, token, twinID)
+}
+
 func (lm *loggingMiddleware) SaveStates(ctx context.Context, msg *messaging.Message) (err error) {
 	defer func(begin time.Time) {
 		args := []any{
@@ -128,7 +169,45 @@ func (lm *loggingMiddleware) SaveStates(ctx context.Context, msg *messaging.Mess
 	return lm.svc.SaveStates(ctx, msg)
 }
 
-func (lm *loggingMiddleware) ListStates(ctx context.Context, token string, offset, limit uint64, twinID string) (page twins.StatesPage, err error) {
+func (lm *loggingMiddleware) ShareTwin(ctx context.Context, token, twinID, relation string, userIDs ...string) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("twin_id", twinID),
+			slog.Any("user_ids", userIDs),
+			slog.String("relation", relation),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Share twin failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Share twin completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.ShareTwin(ctx, token, twinID, relation, userIDs...)
+}
+
+func (lm *loggingMiddleware) UnshareTwin(ctx context.Context, token, twinID, relation string, userIDs ...string) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("twin_id", twinID),
+			slog.Any("user_ids", userIDs),
+			slog.String("relation", relation),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Unshare twin failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Unshare twin completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.UnshareTwin(ctx, token, twinID, relation, userIDs...)
+}
+
+func (lm *loggingMiddleware) ListStates(ctx context.Context, token string, offset, limit uint64, twinID string, filter twins.StatesFilter) (page twins.StatesPage, err error) {
 	defer func(begin time.Time) {
 		args := []any{
 			slog.String("duration", time.Since(begin).String()),
@@ -139,6 +218,18 @@ func (lm *loggingMiddleware) ListStates(ctx context.Context, token string, offse
 				slog.Uint64("total", page.Total),
 			),
 		}
+		if !filter.From.IsZero() {
+			args = append(args, slog.Time("from", filter.From))
+		}
+		if !filter.To.IsZero() {
+			args = append(args, slog.Time("to", filter.To))
+		}
+		if filter.Definition != nil {
+			args = append(args, slog.Int("definition", *filter.Definition))
+		}
+		if len(filter.Attributes) > 0 {
+			args = append(args, slog.Any("attributes", filter.Attributes))
+		}
 		if err != nil {
 			args = append(args, slog.Any("error", err))
 			lm.logger.Warn("List states failed to complete successfully", args...)
@@ -147,7 +238,28 @@ func (lm *loggingMiddleware) ListStates(ctx context.Context, token string, offse
 		lm.logger.Info("List states completed successfully", args...)
 	}(time.Now())
 
-	return lm.svc.ListStates(ctx, token, offset, limit, twinID)
+	return lm.svc.ListStates(ctx, token, offset, limit, twinID, filter)
+}
+
+func (lm *loggingMiddleware) ViewSnapshot(ctx context.Context, token, twinID string, at time.Time, attributes []string) (snap twins.Snapshot, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("twin_id", twinID),
+			slog.Time("at", at),
+		}
+		if len(attributes) > 0 {
+			args = append(args, slog.Any("attributes", attributes))
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("View snapshot failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("View snapshot completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.ViewSnapshot(ctx, token, twinID, at, attributes)
 }
 
 func (lm *loggingMiddleware) RemoveTwin(ctx context.Context, token, twinID string) (err error) {
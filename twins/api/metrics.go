@@ -67,6 +67,24 @@ func (ms *metricsMiddleware) ListTwins(ctx context.Context, token string, offset
 	return ms.svc.ListTwins(ctx, token, offset, limit, name, metadata)
 }
 
+func (ms *metricsMiddleware) SetDesired(ctx context.Context, token, twinID string, desired twins.Desired) (delta twins.Delta, err error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "set_desired").Add(1)
+		ms.latency.With("method", "set_desired").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.SetDesired(ctx, token, twinID, desired)
+}
+
+func (ms *metricsMiddleware) ViewDelta(ctx context.Context, token, twinID string) (delta twins.Delta, err error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_delta").Add(1)
+		ms.latency.With("method", "view_delta").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewDelta(ctx, token, twinID)
+}
+
 func (ms *metricsMiddleware) SaveStates(ctx context.Context, msg *messaging.Message) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "save_states").Add(1)
@@ -76,13 +94,40 @@ func (ms *metricsMiddleware) SaveStates(ctx context.Context, msg *messaging.Mess
 	return ms.svc.SaveStates(ctx, msg)
 }
 
-func (ms *metricsMiddleware) ListStates(ctx context.Context, token string, offset, limit uint64, twinID string) (st twins.StatesPage, err error) {
+func (ms *metricsMiddleware) ShareTwin(ctx context.Context, token, twinID, relation string, userIDs ...string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "share_twin").Add(1)
+		ms.latency.With("method", "share_twin").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ShareTwin(ctx, token, twinID, relation, userIDs...)
+}
+
+func (ms *metricsMiddleware) UnshareTwin(ctx context.Context, token, twinID, relation string, userIDs ...string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "unshare_twin").Add(1)
+		ms.latency.With("method", "unshare_twin").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UnshareTwin(ctx, token, twinID, relation, userIDs...)
+}
+
+func (ms *metricsMiddleware) ListStates(ctx context.Context, token string, offset, limit uint64, twinID string, filter twins.StatesFilter) (st twins.StatesPage, err error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "list_states").Add(1)
 		ms.latency.With("method", "list_states").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.ListStates(ctx, token, offset, limit, twinID)
+	return ms.svc.ListStates(ctx, token, offset, limit, twinID, filter)
+}
+
+func (ms *metricsMiddleware) ViewSnapshot(ctx context.Context, token, twinID string, at time.Time, attributes []string) (snap twins.Snapshot, err error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_snapshot").Add(1)
+		ms.latency.With("method", "view_snapshot").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewSnapshot(ctx, token, twinID, at, attributes)
 }
 
 func (ms *metricsMiddleware) RemoveTwin(ctx context.Context, token, twinID string) (err error) {
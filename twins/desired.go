@@ -0,0 +1,87 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package twins
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/absmach/magistrala/pkg/errors"
+)
+
+var (
+	// ErrUnknownAttribute indicates desired value of the attribute which is
+	// not a part of the current twin definition.
+	ErrUnknownAttribute = errors.New("attribute not found in twin definition")
+
+	// ErrPublishDelta indicates failure to publish the delta to the twin
+	// control channel.
+	ErrPublishDelta = errors.New("failed to publish twin delta")
+)
+
+// Desired stores the attribute values the twin devices should converge to,
+// keyed by the attribute name, and the control channel and subtopic the
+// delta is published to. The control channel should not be used by any
+// of the twin attributes, since the delta would be reported back as state.
+type Desired struct {
+	Attributes map[string]interface{} `json:"attributes"`
+	Channel    string                 `json:"channel,omitempty"`
+	Subtopic   string                 `json:"subtopic,omitempty"`
+	Version    uint64                 `json:"version"`
+	Updated    time.Time              `json:"updated"`
+}
+
+// Delta contains the desired attribute values which differ from the values
+// in the last reported twin state.
+type Delta struct {
+	TwinID     string                 `json:"twin_id"`
+	Version    uint64                 `json:"version"`
+	Attributes map[string]interface{} `json:"attributes"`
+	Created    time.Time              `json:"created"`
+}
+
+// Empty reports whether the reported state matches the desired state.
+func (d Delta) Empty() bool {
+	return len(d.Attributes) == 0
+}
+
+// delta computes the delta between the twin desired and reported state.
+// Desired values of the attributes removed from the definition are ignored.
+func delta(tw Twin, st State) Delta {
+	d := Delta{
+		TwinID:     tw.ID,
+		Version:    tw.Desired.Version,
+		Attributes: make(map[string]interface{}),
+		Created:    time.Now(),
+	}
+	def := tw.Definitions[len(tw.Definitions)-1]
+	for name, want := range tw.Desired.Attributes {
+		if findAttribute(name, def.Attributes) < 0 {
+			continue
+		}
+		if got, ok := st.Payload[name]; ok && equal(want, got) {
+			continue
+		}
+		d.Attributes[name] = want
+	}
+
+	return d
+}
+
+// equal compares values by their JSON representation, since reported values
+// are stored as pointers to SenML values and desired values are decoded
+// from JSON.
+func equal(a, b interface{}) bool {
+	ab, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(ab, bb)
+}
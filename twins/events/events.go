@@ -5,6 +5,7 @@ package events
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/absmach/magistrala/pkg/events"
@@ -21,6 +22,11 @@ const (
 	twinList       = twinPrefix + "list"
 	twinListStates = twinPrefix + "list_states"
 	twinSaveStates = twinPrefix + "save_states"
+	twinSetDesired = twinPrefix + "set_desired"
+	twinViewDelta  = twinPrefix + "view_delta"
+	twinViewSnap   = twinPrefix + "view_snapshot"
+	twinShare      = twinPrefix + "share"
+	twinUnshare    = twinPrefix + "unshare"
 )
 
 var (
@@ -31,6 +37,10 @@ var (
 	_ events.Event = (*listTwinsEvent)(nil)
 	_ events.Event = (*listStatesEvent)(nil)
 	_ events.Event = (*saveStatesEvent)(nil)
+	_ events.Event = (*setDesiredEvent)(nil)
+	_ events.Event = (*viewDeltaEvent)(nil)
+	_ events.Event = (*viewSnapshotEvent)(nil)
+	_ events.Event = (*shareTwinEvent)(nil)
 )
 
 type addTwinEvent struct {
@@ -48,6 +58,9 @@ func (ate addTwinEvent) Encode() (map[string]interface{}, error) {
 	if ate.Twin.Owner != "" {
 		val["owner"] = ate.Twin.Owner
 	}
+	if ate.Twin.Domain != "" {
+		val["domain"] = ate.Twin.Domain
+	}
 	if ate.Twin.Name != "" {
 		val["name"] = ate.Twin.Name
 	}
@@ -210,6 +223,7 @@ type listStatesEvent struct {
 	offset uint64
 	limit  uint64
 	id     string
+	filter twins.StatesFilter
 }
 
 func (lsge listStatesEvent) Encode() (map[string]interface{}, error) {
@@ -226,6 +240,18 @@ func (lsge listStatesEvent) Encode() (map[string]interface{}, error) {
 	if lsge.id != "" {
 		val["id"] = lsge.id
 	}
+	if !lsge.filter.From.IsZero() {
+		val["from"] = lsge.filter.From
+	}
+	if !lsge.filter.To.IsZero() {
+		val["to"] = lsge.filter.To
+	}
+	if lsge.filter.Definition != nil {
+		val["definition"] = *lsge.filter.Definition
+	}
+	if len(lsge.filter.Attributes) > 0 {
+		val["attributes"] = strings.Join(lsge.filter.Attributes, ",")
+	}
 
 	return val, nil
 }
@@ -250,3 +276,81 @@ func (ice saveStatesEvent) Encode() (map[string]interface{}, error) {
 
 	return val, nil
 }
+
+type setDesiredEvent struct {
+	id      string
+	desired twins.Desired
+	delta   twins.Delta
+}
+
+func (sde setDesiredEvent) Encode() (map[string]interface{}, error) {
+	val := map[string]interface{}{
+		"operation": twinSetDesired,
+		"id":        sde.id,
+		"version":   sde.delta.Version,
+	}
+
+	if sde.desired.Channel != "" {
+		val["channel"] = sde.desired.Channel
+	}
+	if sde.desired.Subtopic != "" {
+		val["subtopic"] = sde.desired.Subtopic
+	}
+	if sde.desired.Attributes != nil {
+		attributes, err := json.Marshal(sde.desired.Attributes)
+		if err != nil {
+			return map[string]interface{}{}, err
+		}
+
+		val["attributes"] = attributes
+	}
+
+	return val, nil
+}
+
+type viewDeltaEvent struct {
+	id string
+}
+
+func (vde viewDeltaEvent) Encode() (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"operation": twinViewDelta,
+		"id":        vde.id,
+	}, nil
+}
+
+type viewSnapshotEvent struct {
+	id         string
+	at         time.Time
+	attributes []string
+}
+
+func (vse viewSnapshotEvent) Encode() (map[string]interface{}, error) {
+	val := map[string]interface{}{
+		"operation": twinViewSnap,
+		"id":        vse.id,
+		"at":        vse.at,
+	}
+
+	if len(vse.attributes) > 0 {
+		val["attributes"] = strings.Join(vse.attributes, ",")
+	}
+
+	return val, nil
+}
+
+type shareTwinEvent struct {
+	operation string
+	id        string
+	relation  string
+	userIDs   []string
+}
+
+func (ste shareTwinEvent) Encode() (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"operation": ste.operation,
+		"id":        ste.id,
+		"relation":  ste.relation,
+		"user_ids":  strings.Join(ste.userIDs, ","),
+	}, nil
+}
@@ -5,6 +5,7 @@ package events
 
 import (
 	"context"
+	"time"
 
 	"github.com/absmach/magistrala/pkg/events"
 	"github.com/absmach/magistrala/pkg/events/store"
@@ -120,8 +121,38 @@ func (es eventStore) ListTwins(ctx context.Context, token string, offset, limit
 	return tp, nil
 }
 
-func (es eventStore) ListStates(ctx context.Context, token string, offset, limit uint64, id string) (twins.StatesPage, error) {
-	sp, err := es.svc.ListStates(ctx, token, offset, limit, id)
+func (es eventStore) ShareTwin(ctx context.Context, token, id, relation string, userIDs ...string) error {
+	if err := es.svc.ShareTwin(ctx, token, id, relation, userIDs...); err != nil {
+		return err
+	}
+
+	event := shareTwinEvent{
+		operation: twinShare,
+		id:        id,
+		relation:  relation,
+		userIDs:   userIDs,
+	}
+
+	return es.Publish(ctx, event)
+}
+
+func (es eventStore) UnshareTwin(ctx context.Context, token, id, relation string, userIDs ...string) error {
+	if err := es.svc.UnshareTwin(ctx, token, id, relation, userIDs...); err != nil {
+		return err
+	}
+
+	event := shareTwinEvent{
+		operation: twinUnshare,
+		id:        id,
+		relation:  relation,
+		userIDs:   userIDs,
+	}
+
+	return es.Publish(ctx, event)
+}
+
+func (es eventStore) ListStates(ctx context.Context, token string, offset, limit uint64, id string, filter twins.StatesFilter) (twins.StatesPage, error) {
+	sp, err := es.svc.ListStates(ctx, token, offset, limit, id, filter)
 	if err != nil {
 		return sp, err
 	}
@@ -130,6 +161,7 @@ func (es eventStore) ListStates(ctx context.Context, token string, offset, limit
 		offset,
 		limit,
 		id,
+		filter,
 	}
 
 	if err := es.Publish(ctx, event); err != nil {
@@ -153,3 +185,56 @@ func (es eventStore) SaveStates(ctx context.Context, msg *messaging.Message) err
 
 	return nil
 }
+
+func (es eventStore) SetDesired(ctx context.Context, token, id string, desired twins.Desired) (twins.Delta, error) {
+	delta, err := es.svc.SetDesired(ctx, token, id, desired)
+	if err != nil {
+		return delta, err
+	}
+
+	event := setDesiredEvent{
+		id, desired, delta,
+	}
+
+	if err := es.Publish(ctx, event); err != nil {
+		return delta, err
+	}
+
+	return delta, nil
+}
+
+func (es eventStore) ViewDelta(ctx context.Context, token, id string) (twins.Delta, error) {
+	delta, err := es.svc.ViewDelta(ctx, token, id)
+	if err != nil {
+		return delta, err
+	}
+
+	event := viewDeltaEvent{
+		id,
+	}
+
+	if err := es.Publish(ctx, event); err != nil {
+		return delta, err
+	}
+
+	return delta, nil
+}
+
+func (es eventStore) ViewSnapshot(ctx context.Context, token, id string, at time.Time, attributes []string) (twins.Snapshot, error) {
+	snap, err := es.svc.ViewSnapshot(ctx, token, id, at, attributes)
+	if err != nil {
+		return snap, err
+	}
+
+	event := viewSnapshotEvent{
+		id,
+		at,
+		attributes,
+	}
+
+	if err := es.Publish(ctx, event); err != nil {
+		return snap, err
+	}
+
+	return snap, nil
+}
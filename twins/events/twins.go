@@ -92,6 +92,9 @@ func (tc *twinCache) save(ctx context.Context, twin twins.Twin) error {
 	}
 	attributes := twin.Definitions[len(twin.Definitions)-1].Attributes
 	for _, attr := range attributes {
+		if attr.Computed() {
+			continue
+		}
 		if err := tc.client.SAdd(ctx, attrKey(attr.Channel, attr.Subtopic), twin.ID).Err(); err != nil {
 			return errors.Wrap(ErrRedisTwinSave, err)
 		}
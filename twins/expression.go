@@ -0,0 +1,188 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package twins
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/pkg/expr"
+)
+
+// ErrInvalidExpression indicates malformed computed attribute expression.
+var ErrInvalidExpression = errors.New("invalid attribute expression")
+
+// errNotFinite indicates that the computed value is not a finite number.
+var errNotFinite = errors.New("value is not a finite number")
+
+// language is the language of the computed attribute expressions: numbers,
+// attribute names, operators +, -, * and /, parentheses and functions abs,
+// sqrt, pow, min, max and ema.
+var language = expr.Language{
+	Ops: finiteOps(expr.Arithmetic),
+	Funcs: map[string]expr.Func{
+		"abs":  finiteFunc(expr.Math["abs"]),
+		"sqrt": finiteFunc(expr.Math["sqrt"]),
+		"pow":  finiteFunc(expr.Math["pow"]),
+		"min":  finiteFunc(expr.Math["min"]),
+		"max":  finiteFunc(expr.Math["max"]),
+		"ema":  finiteFunc(expr.Func{Arity: 2, Eval: ema}),
+	},
+}
+
+// evalEnv provides the attribute values to the evaluated expression.
+type evalEnv struct {
+	values map[string]interface{}
+	// prev is the previous value of the computed attribute, used by the
+	// exponential moving average.
+	prev interface{}
+}
+
+func (env *evalEnv) Value(name string) (float64, error) {
+	v, ok := toFloat(env.values[name])
+	if !ok {
+		return 0, expr.ErrMissingValue
+	}
+
+	return v, nil
+}
+
+// ema is the exponential moving average of the first argument, using the
+// second argument as the smoothing factor.
+func ema(env expr.Env, args []float64) (float64, error) {
+	if e, ok := env.(*evalEnv); ok {
+		if prev, ok := toFloat(e.prev); ok {
+			return args[1]*args[0] + (1-args[1])*prev, nil
+		}
+	}
+
+	return args[0], nil
+}
+
+// finiteOps wraps the operators so that the evaluation fails as soon as any
+// intermediate result is not a finite number.
+func finiteOps(ops map[string]expr.Op) map[string]expr.Op {
+	ret := make(map[string]expr.Op, len(ops))
+	for name, op := range ops {
+		eval := op.Eval
+		op.Eval = func(l, r float64) (float64, error) {
+			v, err := eval(l, r)
+			if err == nil && !finite(v) {
+				return 0, errNotFinite
+			}
+			return v, err
+		}
+		ret[name] = op
+	}
+
+	return ret
+}
+
+func finiteFunc(fn expr.Func) expr.Func {
+	eval := fn.Eval
+	fn.Eval = func(env expr.Env, args []float64) (float64, error) {
+		v, err := eval(env, args)
+		if err == nil && !finite(v) {
+			return 0, errNotFinite
+		}
+		return v, err
+	}
+
+	return fn
+}
+
+// validate checks the computed attributes of the definition. Computed
+// attributes are evaluated in the definition order, so they may only refer to
+// the persisted attributes and the computed attributes defined before them.
+func (def Definition) validate() error {
+	defined := make(map[string]bool)
+	for _, attr := range def.Attributes {
+		if !attr.Computed() {
+			defined[attr.Name] = attr.PersistState
+		}
+	}
+
+	for _, attr := range def.Attributes {
+		if !attr.Computed() {
+			continue
+		}
+		if attr.Channel != "" {
+			return errors.Wrap(ErrInvalidExpression, fmt.Errorf("computed attribute %s must not have a channel", attr.Name))
+		}
+		x, err := language.Parse(attr.Expression)
+		if err != nil {
+			return errors.Wrap(ErrInvalidExpression, fmt.Errorf("attribute %s: %w", attr.Name, err))
+		}
+		for _, ref := range expr.Vars(x) {
+			if !defined[ref] {
+				return errors.Wrap(ErrInvalidExpression, fmt.Errorf("attribute %s refers to unknown or not persisted attribute %s", attr.Name, ref))
+			}
+		}
+		defined[attr.Name] = attr.PersistState
+	}
+
+	return nil
+}
+
+// compute evaluates the computed attributes depending on the changed
+// attribute and stores their values to the state payload. Attributes whose
+// inputs have no numeric value are left unchanged.
+func compute(def Definition, payload map[string]interface{}, changed string) {
+	changes := map[string]bool{changed: true}
+	for _, attr := range def.Attributes {
+		if !attr.Computed() || !attr.PersistState {
+			continue
+		}
+		x, err := language.Parse(attr.Expression)
+		if err != nil {
+			continue
+		}
+		if !dependsOn(expr.Vars(x), changes) {
+			continue
+		}
+		v, err := language.Eval(x, &evalEnv{values: payload, prev: payload[attr.Name]})
+		if err != nil {
+			continue
+		}
+		payload[attr.Name] = v
+		changes[attr.Name] = true
+	}
+}
+
+func dependsOn(refs []string, changes map[string]bool) bool {
+	for _, ref := range refs {
+		if changes[ref] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// toFloat converts the state payload value to number. Payload values are
+// pointers to SenML values when saved, and plain values when retrieved.
+func toFloat(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case *float64:
+		if val == nil {
+			return 0, false
+		}
+		return *val, true
+	case int:
+		return float64(val), true
+	case int32:
+		return float64(val), true
+	case int64:
+		return float64(val), true
+	default:
+		return 0, false
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/absmach/magistrala/pkg/errors"
 	"github.com/absmach/magistrala/twins"
 )
 
@@ -32,7 +34,7 @@ func (srm *stateRepositoryMock) Save(ctx context.Context, st twins.State) error
 	srm.mu.Lock()
 	defer srm.mu.Unlock()
 
-	srm.states[key(st.TwinID, strconv.FormatInt(st.ID, 10))] = st
+	srm.states[key(st.TwinID, strconv.FormatInt(st.ID, 10))] = project(st, nil)
 
 	return nil
 }
@@ -42,7 +44,7 @@ func (srm *stateRepositoryMock) Update(ctx context.Context, st twins.State) erro
 	srm.mu.Lock()
 	defer srm.mu.Unlock()
 
-	srm.states[key(st.TwinID, strconv.FormatInt(st.ID, 10))] = st
+	srm.states[key(st.TwinID, strconv.FormatInt(st.ID, 10))] = project(st, nil)
 
 	return nil
 }
@@ -52,7 +54,7 @@ func (srm *stateRepositoryMock) Count(ctx context.Context, tw twins.Twin) (int64
 	return int64(len(srm.states)), nil
 }
 
-func (srm *stateRepositoryMock) RetrieveAll(ctx context.Context, offset, limit uint64, twinID string) (twins.StatesPage, error) {
+func (srm *stateRepositoryMock) RetrieveAll(ctx context.Context, offset, limit uint64, twinID string, filter twins.StatesFilter) (twins.StatesPage, error) {
 	srm.mu.Lock()
 	defer srm.mu.Unlock()
 
@@ -61,27 +63,33 @@ func (srm *stateRepositoryMock) RetrieveAll(ctx context.Context, offset, limit u
 	}
 
 	var items []twins.State
-	for k, v := range srm.states {
-		if (uint64)(len(items)) >= limit {
-			break
+	for _, v := range srm.sorted(twinID) {
+		if !filter.From.IsZero() && v.Created.Before(filter.From) {
+			continue
 		}
-		if !strings.HasPrefix(k, twinID) {
+		if !filter.To.IsZero() && v.Created.After(filter.To) {
 			continue
 		}
-		id := uint64(v.ID)
-		if id >= offset && id < offset+limit {
-			items = append(items, v)
+		if filter.Definition != nil && v.Definition != *filter.Definition {
+			continue
 		}
+		items = append(items, project(v, filter.Attributes))
 	}
 
-	sort.SliceStable(items, func(i, j int) bool {
-		return items[i].ID < items[j].ID
-	})
+	total := uint64(len(items))
+	switch {
+	case offset >= total:
+		items = nil
+	case offset+limit >= total:
+		items = items[offset:]
+	default:
+		items = items[offset : offset+limit]
+	}
 
 	page := twins.StatesPage{
 		States: items,
 		PageMetadata: twins.PageMetadata{
-			Total:  srm.total(twinID),
+			Total:  total,
 			Offset: offset,
 			Limit:  limit,
 		},
@@ -90,6 +98,71 @@ func (srm *stateRepositoryMock) RetrieveAll(ctx context.Context, offset, limit u
 	return page, nil
 }
 
+// RetrieveAt returns the last state of the twin created at or before the time.
+func (srm *stateRepositoryMock) RetrieveAt(ctx context.Context, twinID string, at time.Time, attributes []string) (twins.State, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	var st *twins.State
+	for _, v := range srm.sorted(twinID) {
+		if v.Created.After(at) {
+			continue
+		}
+		if st == nil || !v.Created.Before(st.Created) {
+			v := v
+			st = &v
+		}
+	}
+	if st == nil {
+		return twins.State{}, errors.ErrNotFound
+	}
+
+	return project(*st, attributes), nil
+}
+
+// sorted returns the twin states ordered by the state ID.
+func (srm *stateRepositoryMock) sorted(twinID string) []twins.State {
+	var items []twins.State
+	for _, v := range srm.states {
+		if v.TwinID == twinID {
+			items = append(items, v)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+
+	return items
+}
+
+// project copies the state limiting the payload to the attributes, so
+// that the stored states don't share the payload with the caller.
+func project(st twins.State, attributes []string) twins.State {
+	if st.Payload == nil {
+		return st
+	}
+	payload := make(map[string]interface{})
+	for k, v := range st.Payload {
+		if len(attributes) > 0 && !contains(attributes, k) {
+			continue
+		}
+		payload[k] = v
+	}
+	st.Payload = payload
+
+	return st
+}
+
+func contains(items []string, item string) bool {
+	for _, it := range items {
+		if it == item {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (srm *stateRepositoryMock) total(twinID string) uint64 {
 	var total uint64
 	for k := range srm.states {
@@ -116,7 +189,7 @@ func (srm *stateRepositoryMock) RetrieveLast(ctx context.Context, twinID string)
 	})
 
 	if len(items) > 0 {
-		return items[len(items)-1], nil
+		return project(items[len(items)-1], nil), nil
 	}
 	return twins.State{}, nil
 }
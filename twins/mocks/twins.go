@@ -5,9 +5,9 @@ package mocks
 
 import (
 	"context"
+	"slices"
 	"sort"
 	"strconv"
-	"strings"
 	"sync"
 
 	"github.com/absmach/magistrala/pkg/errors"
@@ -85,7 +85,7 @@ func (trm *twinRepositoryMock) RetrieveByAttribute(ctx context.Context, channel,
 	return ids, nil
 }
 
-func (trm *twinRepositoryMock) RetrieveAll(_ context.Context, owner string, offset, limit uint64, name string, metadata twins.Metadata) (twins.Page, error) {
+func (trm *twinRepositoryMock) RetrieveAll(_ context.Context, domain string, ids []string, offset, limit uint64, name string, metadata twins.Metadata) (twins.Page, error) {
 	trm.mu.Lock()
 	defer trm.mu.Unlock()
 
@@ -95,14 +95,17 @@ func (trm *twinRepositoryMock) RetrieveAll(_ context.Context, owner string, offs
 		return twins.Page{}, nil
 	}
 
-	for k, v := range trm.twins {
+	for _, v := range trm.twins {
 		if (uint64)(len(items)) >= limit {
 			break
 		}
 		if len(name) > 0 && v.Name != name {
 			continue
 		}
-		if !strings.HasPrefix(k, owner) {
+		if domain != "" && v.Domain != domain {
+			continue
+		}
+		if ids != nil && !slices.Contains(ids, v.ID) {
 			continue
 		}
 		suffix := string(v.ID[len(uuid.Prefix):])
@@ -244,6 +247,9 @@ func (tcm *twinCacheMock) remove(twinID string) error {
 
 func (tcm *twinCacheMock) save(def twins.Definition, twinID string) {
 	for _, attr := range def.Attributes {
+		if attr.Computed() {
+			continue
+		}
 		attrKey := attr.Channel + attr.Subtopic
 		if _, ok := tcm.attrIds[attrKey]; !ok {
 			tcm.attrIds[attrKey] = make(map[string]bool)
@@ -5,7 +5,9 @@ package mongodb
 
 import (
 	"context"
+	"time"
 
+	"github.com/absmach/magistrala/pkg/errors"
 	"github.com/absmach/magistrala/twins"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -15,6 +17,8 @@ import (
 const (
 	statesCollection string = "states"
 	twinid           string = "twinid"
+	created          string = "created"
+	payload          string = "payload"
 )
 
 type stateRepository struct {
@@ -69,14 +73,31 @@ func (sr *stateRepository) Count(ctx context.Context, tw twins.Twin) (int64, err
 }
 
 // RetrieveAll retrieves the subset of states related to twin specified by id.
-func (sr *stateRepository) RetrieveAll(ctx context.Context, offset, limit uint64, twinID string) (twins.StatesPage, error) {
+func (sr *stateRepository) RetrieveAll(ctx context.Context, offset, limit uint64, twinID string, sf twins.StatesFilter) (twins.StatesPage, error) {
 	coll := sr.db.Collection(statesCollection)
 
 	findOptions := options.Find()
 	findOptions.SetSkip(int64(offset))
 	findOptions.SetLimit(int64(limit))
+	findOptions.SetSort(bson.D{{Key: "id", Value: 1}})
+	if prj := projection(sf.Attributes); prj != nil {
+		findOptions.SetProjection(prj)
+	}
 
 	filter := bson.M{twinid: twinID}
+	rng := bson.M{}
+	if !sf.From.IsZero() {
+		rng["$gte"] = sf.From
+	}
+	if !sf.To.IsZero() {
+		rng["$lte"] = sf.To
+	}
+	if len(rng) > 0 {
+		filter[created] = rng
+	}
+	if sf.Definition != nil {
+		filter["definition"] = *sf.Definition
+	}
 
 	cur, err := coll.Find(ctx, filter, findOptions)
 	if err != nil {
@@ -103,6 +124,29 @@ func (sr *stateRepository) RetrieveAll(ctx context.Context, offset, limit uint64
 	}, nil
 }
 
+// RetrieveAt returns the last state of the twin created at or before the time.
+func (sr *stateRepository) RetrieveAt(ctx context.Context, twinID string, at time.Time, attributes []string) (twins.State, error) {
+	coll := sr.db.Collection(statesCollection)
+
+	findOptions := options.FindOne()
+	findOptions.SetSort(bson.D{{Key: created, Value: -1}, {Key: "id", Value: -1}})
+	if prj := projection(attributes); prj != nil {
+		findOptions.SetProjection(prj)
+	}
+
+	filter := bson.M{twinid: twinID, created: bson.M{"$lte": at}}
+
+	var st twins.State
+	if err := coll.FindOne(ctx, filter, findOptions).Decode(&st); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return twins.State{}, errors.ErrNotFound
+		}
+		return twins.State{}, err
+	}
+
+	return st, nil
+}
+
 // RetrieveLast returns the last state related to twin spec by id.
 func (sr *stateRepository) RetrieveLast(ctx context.Context, twinID string) (twins.State, error) {
 	coll := sr.db.Collection(statesCollection)
@@ -137,6 +181,21 @@ func (sr *stateRepository) RetrieveLast(ctx context.Context, twinID string) (twi
 	return results[0], nil
 }
 
+// projection limits the state payload to the attributes. Nil projection
+// returns the whole state.
+func projection(attributes []string) bson.M {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	prj := bson.M{twinid: 1, "id": 1, "definition": 1, created: 1}
+	for _, attr := range attributes {
+		prj[payload+"."+attr] = 1
+	}
+
+	return prj
+}
+
 func decodeStates(ctx context.Context, cur *mongo.Cursor) ([]twins.State, error) {
 	defer cur.Close(ctx)
 
@@ -131,7 +131,7 @@ func (tr *twinRepository) RetrieveByAttribute(ctx context.Context, channel, subt
 	return ids, nil
 }
 
-func (tr *twinRepository) RetrieveAll(ctx context.Context, owner string, offset, limit uint64, name string, metadata twins.Metadata) (twins.Page, error) {
+func (tr *twinRepository) RetrieveAll(ctx context.Context, domain string, ids []string, offset, limit uint64, name string, metadata twins.Metadata) (twins.Page, error) {
 	coll := tr.db.Collection(twinsCollection)
 
 	findOptions := options.Find()
@@ -140,8 +140,11 @@ func (tr *twinRepository) RetrieveAll(ctx context.Context, owner string, offset,
 
 	filter := bson.M{}
 
-	if owner != "" {
-		filter["owner"] = owner
+	if domain != "" {
+		filter["domain"] = domain
+	}
+	if ids != nil {
+		filter["id"] = bson.M{"$in": ids}
 	}
 	if name != "" {
 		filter["name"] = name
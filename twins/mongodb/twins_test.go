@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/absmach/magistrala/internal/testsutil"
 	mglog "github.com/absmach/magistrala/logger"
 	"github.com/absmach/magistrala/pkg/errors"
 	repoerr "github.com/absmach/magistrala/pkg/errors/repository"
@@ -239,6 +240,7 @@ func TestTwinsRetrieveByAttribute(t *testing.T) {
 func TestTwinsRetrieveAll(t *testing.T) {
 	email := "twin-multi-retrieval@example.com"
 	name := "magistrala"
+	domainID := testsutil.GenerateUUID(t)
 	metadata := twins.Metadata{
 		"type": "test",
 	}
@@ -256,12 +258,14 @@ func TestTwinsRetrieveAll(t *testing.T) {
 	twinRepo := mongodb.NewTwinRepository(db)
 
 	n := uint64(10)
+	var ids []string
 	for i := uint64(0); i < n; i++ {
 		twid, err := idProvider.ID()
 		assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
 
 		tw := twins.Twin{
 			Owner:    email,
+			Domain:   domainID,
 			ID:       twid,
 			Metadata: metadata,
 		}
@@ -270,13 +274,18 @@ func TestTwinsRetrieveAll(t *testing.T) {
 		if i < 2 {
 			tw.Name = name
 		}
+		// Use first three Twins for the retrieval by IDs.
+		if i < 3 {
+			ids = append(ids, twid)
+		}
 
 		_, err = twinRepo.Save(context.Background(), tw)
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 	}
 
 	cases := map[string]struct {
-		owner    string
+		domain   string
+		ids      []string
 		limit    uint64
 		offset   uint64
 		name     string
@@ -284,22 +293,37 @@ func TestTwinsRetrieveAll(t *testing.T) {
 		total    uint64
 		metadata twins.Metadata
 	}{
-		"retrieve all twins with existing owner": {
-			owner:  email,
+		"retrieve all twins with existing domain": {
+			domain: domainID,
 			offset: 0,
 			limit:  n,
 			size:   n,
 			total:  n,
 		},
-		"retrieve subset of twins with existing owner": {
-			owner:  email,
+		"retrieve subset of twins with existing domain": {
+			domain: domainID,
 			offset: 0,
 			limit:  n / 2,
 			size:   n / 2,
 			total:  n,
 		},
-		"retrieve twins with non-existing owner": {
-			owner:  wrongValue,
+		"retrieve twins with non-existing domain": {
+			domain: wrongValue,
+			offset: 0,
+			limit:  n,
+			size:   0,
+			total:  0,
+		},
+		"retrieve twins with existing ids": {
+			domain: domainID,
+			ids:    ids,
+			offset: 0,
+			limit:  n,
+			size:   3,
+			total:  3,
+		},
+		"retrieve twins with empty ids": {
+			ids:    []string{},
 			offset: 0,
 			limit:  n,
 			size:   0,
@@ -336,7 +360,7 @@ func TestTwinsRetrieveAll(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		page, err := twinRepo.RetrieveAll(context.Background(), tc.owner, tc.offset, tc.limit, tc.name, tc.metadata)
+		page, err := twinRepo.RetrieveAll(context.Background(), tc.domain, tc.ids, tc.offset, tc.limit, tc.name, tc.metadata)
 		size := uint64(len(page.Twins))
 		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
 		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.total, page.Total))
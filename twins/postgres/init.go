@@ -0,0 +1,60 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	_ "github.com/jackc/pgx/v5/stdlib" // required for SQL access
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Migration of Twins service.
+func Migration() *migrate.MemoryMigrationSource {
+	return &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "twins_01",
+				// Definitions, metadata and desired state are stored as JSONB,
+				// the same way they are stored as documents in MongoDB.
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS twins (
+						id			VARCHAR(36) PRIMARY KEY,
+						owner		VARCHAR(254),
+						name		VARCHAR(1024),
+						created		TIMESTAMP,
+						updated		TIMESTAMP,
+						revision	INTEGER NOT NULL DEFAULT 0,
+						definitions	JSONB NOT NULL DEFAULT '[]',
+						metadata	JSONB,
+						desired		JSONB
+					)`,
+					`CREATE INDEX IF NOT EXISTS twins_owner_idx ON twins (owner)`,
+					`CREATE TABLE IF NOT EXISTS states (
+						twin_id		VARCHAR(36) NOT NULL,
+						id			BIGINT NOT NULL,
+						definition	INTEGER NOT NULL DEFAULT 0,
+						created		TIMESTAMP,
+						payload		JSONB,
+						PRIMARY KEY (twin_id, id)
+					)`,
+					`CREATE INDEX IF NOT EXISTS states_twin_created_idx ON states (twin_id, created)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS states`,
+					`DROP TABLE IF EXISTS twins`,
+				},
+			},
+			{
+				Id: "twins_02",
+				Up: []string{
+					`ALTER TABLE twins ADD COLUMN IF NOT EXISTS domain_id VARCHAR(36) NOT NULL DEFAULT ''`,
+					`CREATE INDEX IF NOT EXISTS twins_domain_idx ON twins (domain_id)`,
+				},
+				Down: []string{
+					`DROP INDEX IF EXISTS twins_domain_idx`,
+					`ALTER TABLE twins DROP COLUMN IF EXISTS domain_id`,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/absmach/magistrala/internal/postgres"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/twins"
+)
+
+var _ twins.StateRepository = (*stateRepository)(nil)
+
+type stateRepository struct {
+	db postgres.Database
+}
+
+// NewStateRepository instantiates a PostgreSQL implementation of state
+// repository.
+func NewStateRepository(db postgres.Database) twins.StateRepository {
+	return &stateRepository{
+		db: db,
+	}
+}
+
+func (sr *stateRepository) Save(ctx context.Context, st twins.State) error {
+	q := `INSERT INTO states (twin_id, id, definition, created, payload)
+		VALUES (:twin_id, :id, :definition, :created, :payload)`
+
+	dbst, err := toDBState(st)
+	if err != nil {
+		return errors.Wrap(errors.ErrCreateEntity, err)
+	}
+
+	if _, err := sr.db.NamedExecContext(ctx, q, dbst); err != nil {
+		return errors.Wrap(errors.ErrCreateEntity, err)
+	}
+
+	return nil
+}
+
+func (sr *stateRepository) Update(ctx context.Context, st twins.State) error {
+	q := `UPDATE states SET definition = :definition, created = :created, payload = :payload
+		WHERE twin_id = :twin_id AND id = :id`
+
+	dbst, err := toDBState(st)
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	if _, err := sr.db.NamedExecContext(ctx, q, dbst); err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	return nil
+}
+
+func (sr *stateRepository) Count(ctx context.Context, tw twins.Twin) (int64, error) {
+	q := `SELECT COUNT(*) FROM states WHERE twin_id = $1`
+
+	var total int64
+	if err := sr.db.QueryRowxContext(ctx, q, tw.ID).Scan(&total); err != nil {
+		return 0, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	return total, nil
+}
+
+func (sr *stateRepository) RetrieveAll(ctx context.Context, offset, limit uint64, twinID string, sf twins.StatesFilter) (twins.StatesPage, error) {
+	query := []string{"twin_id = :twin_id"}
+	params := map[string]interface{}{
+		"twin_id": twinID,
+		"offset":  offset,
+		"limit":   limit,
+	}
+	if !sf.From.IsZero() {
+		query = append(query, "created >= :from")
+		params["from"] = sf.From.UTC()
+	}
+	if !sf.To.IsZero() {
+		query = append(query, "created <= :to")
+		params["to"] = sf.To.UTC()
+	}
+	if sf.Definition != nil {
+		query = append(query, "definition = :definition")
+		params["definition"] = *sf.Definition
+	}
+	where := fmt.Sprintf("WHERE %s", strings.Join(query, " AND "))
+
+	q := fmt.Sprintf(`SELECT twin_id, id, definition, created, payload
+		FROM states %s ORDER BY id LIMIT :limit OFFSET :offset`, where)
+
+	rows, err := sr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return twins.StatesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+
+	var items []twins.State
+	for rows.Next() {
+		var dbst dbState
+		if err := rows.StructScan(&dbst); err != nil {
+			return twins.StatesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		st, err := toState(dbst, sf.Attributes)
+		if err != nil {
+			return twins.StatesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		items = append(items, st)
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM states %s`, where)
+	total, err := total(ctx, sr.db, cq, params)
+	if err != nil {
+		return twins.StatesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	return twins.StatesPage{
+		States: items,
+		PageMetadata: twins.PageMetadata{
+			Total:  total,
+			Offset: offset,
+			Limit:  limit,
+		},
+	}, nil
+}
+
+func (sr *stateRepository) RetrieveAt(ctx context.Context, twinID string, at time.Time, attributes []string) (twins.State, error) {
+	q := `SELECT twin_id, id, definition, created, payload FROM states
+		WHERE twin_id = $1 AND created <= $2 ORDER BY created DESC, id DESC LIMIT 1`
+
+	var dbst dbState
+	if err := sr.db.QueryRowxContext(ctx, q, twinID, at.UTC()).StructScan(&dbst); err != nil {
+		if err == sql.ErrNoRows {
+			return twins.State{}, errors.ErrNotFound
+		}
+		return twins.State{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	return toState(dbst, attributes)
+}
+
+func (sr *stateRepository) RetrieveLast(ctx context.Context, twinID string) (twins.State, error) {
+	q := `SELECT twin_id, id, definition, created, payload FROM states
+		WHERE twin_id = $1 ORDER BY id DESC LIMIT 1`
+
+	var dbst dbState
+	if err := sr.db.QueryRowxContext(ctx, q, twinID).StructScan(&dbst); err != nil {
+		// Twin without states starts from the empty state.
+		if err == sql.ErrNoRows {
+			return twins.State{}, nil
+		}
+		return twins.State{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	return toState(dbst, nil)
+}
+
+type dbState struct {
+	TwinID     string    `db:"twin_id"`
+	ID         int64     `db:"id"`
+	Definition int       `db:"definition"`
+	Created    time.Time `db:"created"`
+	Payload    []byte    `db:"payload"`
+}
+
+func toDBState(st twins.State) (dbState, error) {
+	payload, err := json.Marshal(st.Payload)
+	if err != nil {
+		return dbState{}, err
+	}
+
+	return dbState{
+		TwinID:     st.TwinID,
+		ID:         st.ID,
+		Definition: st.Definition,
+		Created:    st.Created.UTC(),
+		Payload:    payload,
+	}, nil
+}
+
+// toState converts the database state, limiting the payload to the
+// attributes, if any.
+func toState(dbst dbState, attributes []string) (twins.State, error) {
+	st := twins.State{
+		TwinID:     dbst.TwinID,
+		ID:         dbst.ID,
+		Definition: dbst.Definition,
+		Created:    dbst.Created,
+	}
+
+	if dbst.Payload != nil {
+		if err := json.Unmarshal(dbst.Payload, &st.Payload); err != nil {
+			return twins.State{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+
+	if len(attributes) > 0 && st.Payload != nil {
+		payload := make(map[string]interface{}, len(attributes))
+		for _, attr := range attributes {
+			if val, ok := st.Payload[attr]; ok {
+				payload[attr] = val
+			}
+		}
+		st.Payload = payload
+	}
+
+	return st, nil
+}
@@ -0,0 +1,291 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/twins"
+	twpostgres "github.com/absmach/magistrala/twins/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateSave(t *testing.T) {
+	repo := twpostgres.NewStateRepository(database)
+
+	twid, err := idProvider.ID()
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	var id int64
+	state := twins.State{
+		TwinID:  twid,
+		ID:      id,
+		Created: time.Now(),
+	}
+
+	cases := []struct {
+		desc  string
+		state twins.State
+		err   error
+	}{
+		{
+			desc:  "save state",
+			state: state,
+			err:   nil,
+		},
+		{
+			desc:  "save state with existing id",
+			state: state,
+			err:   errors.ErrCreateEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(context.Background(), tc.state)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestStatesRetrieveAll(t *testing.T) {
+	_, err := db.Exec("DELETE FROM states")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	repo := twpostgres.NewStateRepository(database)
+
+	twid, err := idProvider.ID()
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	n := uint64(10)
+	for i := uint64(0); i < n; i++ {
+		st := twins.State{
+			TwinID:  twid,
+			ID:      int64(i),
+			Created: time.Now(),
+		}
+
+		err = repo.Save(context.Background(), st)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	cases := map[string]struct {
+		twid   string
+		limit  uint64
+		offset uint64
+		size   uint64
+		total  uint64
+	}{
+		"retrieve all states with existing twin": {
+			twid:   twid,
+			offset: 0,
+			limit:  n,
+			size:   n,
+			total:  n,
+		},
+		"retrieve subset of states with existing twin": {
+			twid:   twid,
+			offset: 0,
+			limit:  n / 2,
+			size:   n / 2,
+			total:  n,
+		},
+		"retrieve states with non-existing twin": {
+			twid:   wrongValue,
+			offset: 0,
+			limit:  n,
+			size:   0,
+			total:  0,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := repo.RetrieveAll(context.Background(), tc.offset, tc.limit, tc.twid, twins.StatesFilter{})
+		size := uint64(len(page.States))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.total, page.Total))
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %d\n", desc, err))
+	}
+}
+
+func TestStatesRetrieveLast(t *testing.T) {
+	_, err := db.Exec("DELETE FROM states")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	repo := twpostgres.NewStateRepository(database)
+
+	twid, err := idProvider.ID()
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	n := int64(10)
+	for i := int64(1); i <= n; i++ {
+		st := twins.State{
+			TwinID:  twid,
+			ID:      i,
+			Created: time.Now(),
+		}
+
+		err = repo.Save(context.Background(), st)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	cases := map[string]struct {
+		twid string
+		id   int64
+	}{
+		"retrieve last state with existing twin": {
+			twid: twid,
+			id:   n,
+		},
+		"retrieve states with non-existing owner": {
+			twid: wrongValue,
+			id:   0,
+		},
+	}
+
+	for desc, tc := range cases {
+		state, err := repo.RetrieveLast(context.Background(), tc.twid)
+		assert.Equal(t, tc.id, state.ID, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.id, state.ID))
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %d\n", desc, err))
+	}
+}
+
+func TestStatesRetrieveAllFiltered(t *testing.T) {
+	_, err := db.Exec("DELETE FROM states")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	repo := twpostgres.NewStateRepository(database)
+
+	twid, err := idProvider.ID()
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	t0 := time.Now().UTC().Truncate(time.Millisecond)
+	n := 10
+	for i := 0; i < n; i++ {
+		st := twins.State{
+			TwinID:     twid,
+			ID:         int64(i),
+			Definition: i / 5,
+			Created:    t0.Add(time.Duration(i) * time.Minute),
+			Payload:    map[string]interface{}{"temperature": float64(i), "humidity": float64(i * 10)},
+		}
+
+		err = repo.Save(context.Background(), st)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	def := 1
+	cases := map[string]struct {
+		filter twins.StatesFilter
+		size   int
+		total  uint64
+	}{
+		"retrieve states created from time": {
+			filter: twins.StatesFilter{From: t0.Add(3 * time.Minute)},
+			size:   7,
+			total:  7,
+		},
+		"retrieve states created within time range": {
+			filter: twins.StatesFilter{From: t0.Add(2 * time.Minute), To: t0.Add(4 * time.Minute)},
+			size:   3,
+			total:  3,
+		},
+		"retrieve states recorded with definition": {
+			filter: twins.StatesFilter{Definition: &def},
+			size:   5,
+			total:  5,
+		},
+		"retrieve states limited to attributes": {
+			filter: twins.StatesFilter{To: t0, Attributes: []string{"humidity"}},
+			size:   1,
+			total:  1,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := repo.RetrieveAll(context.Background(), 0, uint64(n), twid, tc.filter)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s\n", desc, err))
+		assert.Equal(t, tc.size, len(page.States), fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, len(page.States)))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.total, page.Total))
+		if len(tc.filter.Attributes) > 0 && len(page.States) > 0 {
+			_, ok := page.States[0].Payload["temperature"]
+			assert.False(t, ok, fmt.Sprintf("%s: expected payload without temperature got %v\n", desc, page.States[0].Payload))
+		}
+	}
+}
+
+func TestStatesRetrieveAt(t *testing.T) {
+	_, err := db.Exec("DELETE FROM states")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	repo := twpostgres.NewStateRepository(database)
+
+	twid, err := idProvider.ID()
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	t0 := time.Now().UTC().Truncate(time.Millisecond)
+	n := 10
+	for i := 0; i < n; i++ {
+		st := twins.State{
+			TwinID:  twid,
+			ID:      int64(i),
+			Created: t0.Add(time.Duration(i) * time.Minute),
+			Payload: map[string]interface{}{"temperature": float64(i), "humidity": float64(i * 10)},
+		}
+
+		err = repo.Save(context.Background(), st)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	cases := map[string]struct {
+		twid       string
+		at         time.Time
+		attributes []string
+		id         int64
+		payload    map[string]interface{}
+		err        error
+	}{
+		"retrieve state between states": {
+			twid:    twid,
+			at:      t0.Add(150 * time.Second),
+			id:      2,
+			payload: map[string]interface{}{"temperature": float64(2), "humidity": float64(20)},
+		},
+		"retrieve state at state creation time": {
+			twid:    twid,
+			at:      t0.Add(4 * time.Minute),
+			id:      4,
+			payload: map[string]interface{}{"temperature": float64(4), "humidity": float64(40)},
+		},
+		"retrieve state limited to attributes": {
+			twid:       twid,
+			at:         t0.Add(time.Hour),
+			attributes: []string{"humidity"},
+			id:         int64(n - 1),
+			payload:    map[string]interface{}{"humidity": float64((n - 1) * 10)},
+		},
+		"retrieve state before the first state": {
+			twid: twid,
+			at:   t0.Add(-time.Second),
+			err:  errors.ErrNotFound,
+		},
+		"retrieve state with non-existing twin": {
+			twid: wrongValue,
+			at:   t0.Add(time.Hour),
+			err:  errors.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		state, err := repo.RetrieveAt(context.Background(), tc.twid, tc.at, tc.attributes)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.id, state.ID, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.id, state.ID))
+			assert.Equal(t, tc.payload, state.Payload, fmt.Sprintf("%s: expected %v got %v\n", desc, tc.payload, state.Payload))
+		}
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/absmach/magistrala/internal/postgres"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/twins"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const maxNameSize = 1024
+
+var _ twins.TwinRepository = (*twinRepository)(nil)
+
+type twinRepository struct {
+	db postgres.Database
+}
+
+// NewTwinRepository instantiates a PostgreSQL implementation of twin
+// repository.
+func NewTwinRepository(db postgres.Database) twins.TwinRepository {
+	return &twinRepository{
+		db: db,
+	}
+}
+
+func (tr *twinRepository) Save(ctx context.Context, tw twins.Twin) (string, error) {
+	if len(tw.Name) > maxNameSize {
+		return "", errors.ErrMalformedEntity
+	}
+
+	q := `INSERT INTO twins (id, owner, domain_id, name, created, updated, revision, definitions, metadata, desired)
+		VALUES (:id, :owner, :domain_id, :name, :created, :updated, :revision, :definitions, :metadata, :desired)`
+
+	dbtw, err := toDBTwin(tw)
+	if err != nil {
+		return "", errors.Wrap(errors.ErrCreateEntity, err)
+	}
+
+	if _, err := tr.db.NamedExecContext(ctx, q, dbtw); err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == pgerrcode.UniqueViolation {
+			return "", errors.Wrap(errors.ErrConflict, err)
+		}
+		return "", errors.Wrap(errors.ErrCreateEntity, err)
+	}
+
+	return tw.ID, nil
+}
+
+func (tr *twinRepository) Update(ctx context.Context, tw twins.Twin) error {
+	if len(tw.Name) > maxNameSize {
+		return errors.ErrMalformedEntity
+	}
+
+	q := `UPDATE twins SET owner = :owner, name = :name, created = :created, updated = :updated,
+		revision = :revision, definitions = :definitions, metadata = :metadata, desired = :desired
+		WHERE id = :id`
+
+	dbtw, err := toDBTwin(tw)
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	res, err := tr.db.NamedExecContext(ctx, q, dbtw)
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	if cnt < 1 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (tr *twinRepository) RetrieveByID(ctx context.Context, twinID string) (twins.Twin, error) {
+	q := `SELECT id, owner, domain_id, name, created, updated, revision, definitions, metadata, desired
+		FROM twins WHERE id = $1`
+
+	var dbtw dbTwin
+	if err := tr.db.QueryRowxContext(ctx, q, twinID).StructScan(&dbtw); err != nil {
+		if err == sql.ErrNoRows {
+			return twins.Twin{}, errors.ErrNotFound
+		}
+		return twins.Twin{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	return toTwin(dbtw)
+}
+
+func (tr *twinRepository) RetrieveByAttribute(ctx context.Context, channel, subtopic string) ([]string, error) {
+	// Only the attributes of the latest twin definition are matched.
+	q := `SELECT id FROM twins t WHERE EXISTS (
+			SELECT 1 FROM jsonb_array_elements(t.definitions -> -1 -> 'attributes') attr
+			WHERE attr ->> 'channel' = $1 AND (attr ->> 'subtopic' = $2 OR attr ->> 'subtopic' = $3)
+		)`
+
+	rows, err := tr.db.QueryxContext(ctx, q, channel, subtopic, twins.SubtopicWildcard)
+	if err != nil {
+		return []string{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return []string{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (tr *twinRepository) RetrieveAll(ctx context.Context, domain string, ids []string, offset, limit uint64, name string, metadata twins.Metadata) (twins.Page, error) {
+	var query []string
+	params := map[string]interface{}{
+		"offset": offset,
+		"limit":  limit,
+	}
+	if domain != "" {
+		query = append(query, "domain_id = :domain_id")
+		params["domain_id"] = domain
+	}
+	if ids != nil {
+		query = append(query, fmt.Sprintf("id IN ('%s')", strings.Join(ids, "','")))
+	}
+	if name != "" {
+		query = append(query, "name = :name")
+		params["name"] = name
+	}
+	if len(metadata) > 0 {
+		b, err := json.Marshal(metadata)
+		if err != nil {
+			return twins.Page{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		query = append(query, "metadata @> :metadata")
+		params["metadata"] = b
+	}
+
+	var where string
+	if len(query) > 0 {
+		where = fmt.Sprintf("WHERE %s", strings.Join(query, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT id, owner, domain_id, name, created, updated, revision, definitions, metadata, desired
+		FROM twins %s ORDER BY created, id LIMIT :limit OFFSET :offset`, where)
+
+	rows, err := tr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return twins.Page{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+
+	var items []twins.Twin
+	for rows.Next() {
+		var dbtw dbTwin
+		if err := rows.StructScan(&dbtw); err != nil {
+			return twins.Page{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		tw, err := toTwin(dbtw)
+		if err != nil {
+			return twins.Page{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		items = append(items, tw)
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM twins %s`, where)
+	total, err := total(ctx, tr.db, cq, params)
+	if err != nil {
+		return twins.Page{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	return twins.Page{
+		Twins: items,
+		PageMetadata: twins.PageMetadata{
+			Total:  total,
+			Offset: offset,
+			Limit:  limit,
+		},
+	}, nil
+}
+
+func (tr *twinRepository) Remove(ctx context.Context, twinID string) error {
+	q := `DELETE FROM twins WHERE id = $1`
+
+	res, err := tr.db.ExecContext(ctx, q, twinID)
+	if err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+	if cnt < 1 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+func total(ctx context.Context, db postgres.Database, query string, params interface{}) (uint64, error) {
+	rows, err := db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var total uint64
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+
+	return total, nil
+}
+
+type dbTwin struct {
+	ID          string         `db:"id"`
+	Owner       string         `db:"owner"`
+	Domain      string         `db:"domain_id"`
+	Name        sql.NullString `db:"name"`
+	Created     time.Time      `db:"created"`
+	Updated     time.Time      `db:"updated"`
+	Revision    int            `db:"revision"`
+	Definitions []byte         `db:"definitions"`
+	Metadata    []byte         `db:"metadata"`
+	Desired     []byte         `db:"desired"`
+}
+
+func toDBTwin(tw twins.Twin) (dbTwin, error) {
+	defs := tw.Definitions
+	if defs == nil {
+		defs = []twins.Definition{}
+	}
+	definitions, err := json.Marshal(defs)
+	if err != nil {
+		return dbTwin{}, err
+	}
+
+	metadata := []byte("{}")
+	if len(tw.Metadata) > 0 {
+		if metadata, err = json.Marshal(tw.Metadata); err != nil {
+			return dbTwin{}, err
+		}
+	}
+
+	desired, err := json.Marshal(tw.Desired)
+	if err != nil {
+		return dbTwin{}, err
+	}
+
+	return dbTwin{
+		ID:          tw.ID,
+		Owner:       tw.Owner,
+		Domain:      tw.Domain,
+		Name:        sql.NullString{String: tw.Name, Valid: tw.Name != ""},
+		Created:     tw.Created.UTC(),
+		Updated:     tw.Updated.UTC(),
+		Revision:    tw.Revision,
+		Definitions: definitions,
+		Metadata:    metadata,
+		Desired:     desired,
+	}, nil
+}
+
+func toTwin(dbtw dbTwin) (twins.Twin, error) {
+	tw := twins.Twin{
+		ID:       dbtw.ID,
+		Owner:    dbtw.Owner,
+		Domain:   dbtw.Domain,
+		Name:     dbtw.Name.String,
+		Created:  dbtw.Created,
+		Updated:  dbtw.Updated,
+		Revision: dbtw.Revision,
+	}
+
+	if err := json.Unmarshal(dbtw.Definitions, &tw.Definitions); err != nil {
+		return twins.Twin{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	if dbtw.Metadata != nil {
+		if err := json.Unmarshal(dbtw.Metadata, &tw.Metadata); err != nil {
+			return twins.Twin{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+	if dbtw.Desired != nil {
+		if err := json.Unmarshal(dbtw.Desired, &tw.Desired); err != nil {
+			return twins.Twin{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+
+	return tw, nil
+}
@@ -0,0 +1,406 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/internal/testsutil"
+	"github.com/absmach/magistrala/pkg/errors"
+	repoerr "github.com/absmach/magistrala/pkg/errors/repository"
+	"github.com/absmach/magistrala/pkg/uuid"
+	"github.com/absmach/magistrala/twins"
+	"github.com/absmach/magistrala/twins/mocks"
+	twpostgres "github.com/absmach/magistrala/twins/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	maxNameSize = 1024
+	email       = "mgx_twin@example.com"
+	validName   = "mgx_twin"
+	subtopic    = "engine"
+	wrongValue  = "wrong-value"
+)
+
+var (
+	idProvider  = uuid.New()
+	invalidName = strings.Repeat("m", maxNameSize+1)
+)
+
+func TestTwinsSave(t *testing.T) {
+	repo := twpostgres.NewTwinRepository(database)
+
+	twid, err := idProvider.ID()
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	nonexistentTwinID, err := idProvider.ID()
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	twin := twins.Twin{
+		Owner: email,
+		ID:    twid,
+	}
+
+	cases := []struct {
+		desc string
+		twin twins.Twin
+		err  error
+	}{
+		{
+			desc: "create new twin",
+			twin: twin,
+			err:  nil,
+		},
+		{
+			desc: "create twin with existing id",
+			twin: twin,
+			err:  repoerr.ErrConflict,
+		},
+		{
+			desc: "create twin with invalid name",
+			twin: twins.Twin{
+				ID:    nonexistentTwinID,
+				Owner: email,
+				Name:  invalidName,
+			},
+			err: repoerr.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := repo.Save(context.Background(), tc.twin)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestTwinsUpdate(t *testing.T) {
+	repo := twpostgres.NewTwinRepository(database)
+
+	twid, err := idProvider.ID()
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	nonexistentTwinID, err := idProvider.ID()
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	twin := twins.Twin{
+		ID:   twid,
+		Name: validName,
+	}
+
+	_, err = repo.Save(context.Background(), twin)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	twin.Name = "new_name"
+	cases := []struct {
+		desc string
+		twin twins.Twin
+		err  error
+	}{
+		{
+			desc: "update existing twin",
+			twin: twin,
+			err:  nil,
+		},
+		{
+			desc: "update non-existing twin",
+			twin: twins.Twin{
+				ID: nonexistentTwinID,
+			},
+			err: repoerr.ErrNotFound,
+		},
+		{
+			desc: "update twin with invalid name",
+			twin: twins.Twin{
+				ID:    twid,
+				Owner: email,
+				Name:  invalidName,
+			},
+			err: repoerr.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Update(context.Background(), tc.twin)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestTwinsRetrieveByID(t *testing.T) {
+	repo := twpostgres.NewTwinRepository(database)
+
+	nonexistentTwinID, err := idProvider.ID()
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	twin := mocks.CreateTwin([]string{"chanID"}, []string{subtopic})
+	twin.Owner = email
+	twin.Name = validName
+	twin.Created = time.Now().UTC().Truncate(time.Millisecond)
+	twin.Updated = twin.Created
+	twin.Definitions[0].Created = twin.Created
+	twin.Metadata = twins.Metadata{"type": "test"}
+	twin.Desired = twins.Desired{
+		Attributes: map[string]interface{}{"temperature": 21.5},
+		Channel:    "control",
+		Version:    1,
+		Updated:    twin.Created,
+	}
+
+	_, err = repo.Save(context.Background(), twin)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc string
+		id   string
+		twin twins.Twin
+		err  error
+	}{
+		{
+			desc: "retrieve an existing twin",
+			id:   twin.ID,
+			twin: twin,
+			err:  nil,
+		},
+		{
+			desc: "retrieve a non-existing twin",
+			id:   nonexistentTwinID,
+			err:  repoerr.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		tw, err := repo.RetrieveByID(context.Background(), tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.twin, tw, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.twin, tw))
+		}
+	}
+}
+
+func TestTwinsRetrieveByAttribute(t *testing.T) {
+	repo := twpostgres.NewTwinRepository(database)
+
+	chID, err := idProvider.ID()
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	empty := mocks.CreateTwin([]string{chID}, []string{""})
+	_, err = repo.Save(context.Background(), empty)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	wildcard := mocks.CreateTwin([]string{chID}, []string{twins.SubtopicWildcard})
+	_, err = repo.Save(context.Background(), wildcard)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	nonEmpty := mocks.CreateTwin([]string{chID}, []string{subtopic})
+	_, err = repo.Save(context.Background(), nonEmpty)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	// Attributes of the previous definitions are not matched.
+	outdated := mocks.CreateTwin([]string{chID}, []string{subtopic})
+	outdated.Definitions = append(outdated.Definitions, mocks.CreateDefinition([]string{wrongValue}, []string{subtopic}))
+	_, err = repo.Save(context.Background(), outdated)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		subtopic string
+		ids      []string
+	}{
+		{
+			desc:     "retrieve empty subtopic",
+			subtopic: "",
+			ids:      []string{wildcard.ID, empty.ID},
+		},
+		{
+			desc:     "retrieve wildcard subtopic",
+			subtopic: twins.SubtopicWildcard,
+			ids:      []string{wildcard.ID},
+		},
+		{
+			desc:     "retrieve non-empty subtopic",
+			subtopic: subtopic,
+			ids:      []string{wildcard.ID, nonEmpty.ID},
+		},
+	}
+
+	for _, tc := range cases {
+		ids, err := repo.RetrieveByAttribute(context.Background(), chID, tc.subtopic)
+		assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		assert.ElementsMatch(t, ids, tc.ids, fmt.Sprintf("%s: expected ids %v do not match received ids %v", tc.desc, tc.ids, ids))
+	}
+}
+
+func TestTwinsRetrieveAll(t *testing.T) {
+	email := "twin-multi-retrieval@example.com"
+	name := "magistrala"
+	domainID := testsutil.GenerateUUID(t)
+	metadata := twins.Metadata{
+		"type": "test",
+	}
+	wrongMetadata := twins.Metadata{
+		"wrong": "wrong",
+	}
+
+	_, err := db.Exec("DELETE FROM twins")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	twinRepo := twpostgres.NewTwinRepository(database)
+
+	n := uint64(10)
+	var ids []string
+	for i := uint64(0); i < n; i++ {
+		twid, err := idProvider.ID()
+		assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+		tw := twins.Twin{
+			Owner:    email,
+			Domain:   domainID,
+			ID:       twid,
+			Metadata: metadata,
+		}
+
+		// Create first two Twins with name.
+		if i < 2 {
+			tw.Name = name
+		}
+		// Use first three Twins for the retrieval by IDs.
+		if i < 3 {
+			ids = append(ids, twid)
+		}
+
+		_, err = twinRepo.Save(context.Background(), tw)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	cases := map[string]struct {
+		domain   string
+		ids      []string
+		limit    uint64
+		offset   uint64
+		name     string
+		size     uint64
+		total    uint64
+		metadata twins.Metadata
+	}{
+		"retrieve all twins with existing domain": {
+			domain: domainID,
+			offset: 0,
+			limit:  n,
+			size:   n,
+			total:  n,
+		},
+		"retrieve subset of twins with existing domain": {
+			domain: domainID,
+			offset: 0,
+			limit:  n / 2,
+			size:   n / 2,
+			total:  n,
+		},
+		"retrieve twins with non-existing domain": {
+			domain: wrongValue,
+			offset: 0,
+			limit:  n,
+			size:   0,
+			total:  0,
+		},
+		"retrieve twins with existing ids": {
+			domain: domainID,
+			ids:    ids,
+			offset: 0,
+			limit:  n,
+			size:   3,
+			total:  3,
+		},
+		"retrieve twins with empty ids": {
+			ids:    []string{},
+			offset: 0,
+			limit:  n,
+			size:   0,
+			total:  0,
+		},
+		"retrieve twins with existing name": {
+			offset: 0,
+			limit:  1,
+			name:   name,
+			size:   1,
+			total:  2,
+		},
+		"retrieve twins with non-existing name": {
+			offset: 0,
+			limit:  n,
+			name:   "wrong",
+			size:   0,
+			total:  0,
+		},
+		"retrieve twins with metadata": {
+			offset:   0,
+			limit:    n,
+			size:     n,
+			total:    n,
+			metadata: metadata,
+		},
+		"retrieve twins with wrong metadata": {
+			offset:   0,
+			limit:    n,
+			size:     0,
+			total:    0,
+			metadata: wrongMetadata,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := twinRepo.RetrieveAll(context.Background(), tc.domain, tc.ids, tc.offset, tc.limit, tc.name, tc.metadata)
+		size := uint64(len(page.Twins))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.total, page.Total))
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %d\n", desc, err))
+	}
+}
+
+func TestTwinsRemove(t *testing.T) {
+	repo := twpostgres.NewTwinRepository(database)
+
+	twid, err := idProvider.ID()
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	nonexistentTwinID, err := idProvider.ID()
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	twin := twins.Twin{
+		ID: twid,
+	}
+
+	_, err = repo.Save(context.Background(), twin)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc string
+		id   string
+		err  error
+	}{
+		{
+			desc: "remove an existing twin",
+			id:   twin.ID,
+			err:  nil,
+		},
+		{
+			desc: "remove a removed twin",
+			id:   twin.ID,
+			err:  repoerr.ErrNotFound,
+		},
+		{
+			desc: "remove a non-existing twin",
+			id:   nonexistentTwinID,
+			err:  repoerr.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Remove(context.Background(), tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/absmach/magistrala"
+	"github.com/absmach/magistrala/auth"
 	"github.com/absmach/magistrala/pkg/errors"
 	svcerr "github.com/absmach/magistrala/pkg/errors/service"
 	"github.com/absmach/magistrala/pkg/messaging"
@@ -20,31 +21,62 @@ import (
 
 const publisher = "twins"
 
+var (
+	errAddPolicies    = errors.New("failed to add policies")
+	errRemovePolicies = errors.New("failed to remove the policies")
+
+	// ErrInvalidRelation indicates the twin sharing relation other than
+	// administrator, editor or viewer.
+	ErrInvalidRelation = errors.New("invalid twin sharing relation")
+)
+
 // Service specifies an API that must be fullfiled by the domain service
 // implementation, and all of its decorators (e.g. logging & metrics).
 type Service interface {
-	// AddTwin adds new twin related to user identified by the provided key.
+	// AddTwin adds new twin to the domain of the user identified by the
+	// provided key. The user becomes the twin administrator.
 	AddTwin(ctx context.Context, token string, twin Twin, def Definition) (tw Twin, err error)
 
-	// UpdateTwin updates twin identified by the provided Twin that
-	// belongs to the user identified by the provided key.
+	// UpdateTwin updates twin identified by the provided Twin, if the user
+	// identified by the provided key is allowed to edit it.
 	UpdateTwin(ctx context.Context, token string, twin Twin, def Definition) (err error)
 
-	// ViewTwin retrieves data about twin with the provided
-	// ID belonging to the user identified by the provided key.
+	// ViewTwin retrieves data about twin with the provided ID, if the user
+	// identified by the provided key is allowed to view it.
 	ViewTwin(ctx context.Context, token, twinID string) (tw Twin, err error)
 
-	// RemoveTwin removes the twin identified with the provided ID, that
-	// belongs to the user identified by the provided key.
+	// RemoveTwin removes the twin identified with the provided ID, if the
+	// user identified by the provided key is allowed to delete it.
 	RemoveTwin(ctx context.Context, token, twinID string) (err error)
 
-	// ListTwins retrieves data about subset of twins that belongs to the
-	// user identified by the provided key.
+	// ListTwins retrieves data about subset of twins that the user
+	// identified by the provided key is allowed to view.
 	ListTwins(ctx context.Context, token string, offset uint64, limit uint64, name string, metadata Metadata) (Page, error)
 
+	// ShareTwin grants the users of the same domain the relation to the twin
+	// identified by the provided ID. Administrator relation allows deleting
+	// and sharing, editor relation allows editing and viewer relation allows
+	// viewing the twin.
+	ShareTwin(ctx context.Context, token, twinID, relation string, userIDs ...string) error
+
+	// UnshareTwin revokes the relation to the twin from the users.
+	UnshareTwin(ctx context.Context, token, twinID, relation string, userIDs ...string) error
+
 	// ListStates retrieves data about subset of states that belongs to the
-	// twin identified by the id.
-	ListStates(ctx context.Context, token string, offset uint64, limit uint64, twinID string) (StatesPage, error)
+	// twin identified by the id and matches the filter.
+	ListStates(ctx context.Context, token string, offset uint64, limit uint64, twinID string, filter StatesFilter) (StatesPage, error)
+
+	// ViewSnapshot retrieves the state of the twin identified by the id as it
+	// was at the provided time, along with the definition it was recorded with.
+	ViewSnapshot(ctx context.Context, token, twinID string, at time.Time, attributes []string) (Snapshot, error)
+
+	// SetDesired replaces the desired state of the twin identified by the
+	// provided ID and publishes the resulting delta to the control channel.
+	SetDesired(ctx context.Context, token, twinID string, desired Desired) (Delta, error)
+
+	// ViewDelta retrieves the difference between the desired state and
+	// the last reported state of the twin identified by the provided ID.
+	ViewDelta(ctx context.Context, token, twinID string) (Delta, error)
 
 	// SaveStates persists states into database
 	SaveStates(ctx context.Context, msg *messaging.Message) error
@@ -60,16 +92,18 @@ const (
 )
 
 var crudOp = map[string]string{
-	"createSucc": "create.success",
-	"createFail": "create.failure",
-	"updateSucc": "update.success",
-	"updateFail": "update.failure",
-	"getSucc":    "get.success",
-	"getFail":    "get.failure",
-	"removeSucc": "remove.success",
-	"removeFail": "remove.failure",
-	"stateSucc":  "save.success",
-	"stateFail":  "save.failure",
+	"createSucc":  "create.success",
+	"createFail":  "create.failure",
+	"updateSucc":  "update.success",
+	"updateFail":  "update.failure",
+	"getSucc":     "get.success",
+	"getFail":     "get.failure",
+	"removeSucc":  "remove.success",
+	"removeFail":  "remove.failure",
+	"stateSucc":   "save.success",
+	"stateFail":   "save.failure",
+	"desiredSucc": "desired.success",
+	"desiredFail": "desired.failure",
 }
 
 type twinsService struct {
@@ -103,9 +137,13 @@ func (ts *twinsService) AddTwin(ctx context.Context, token string, twin Twin, de
 	var id string
 	var b []byte
 	defer ts.publish(ctx, &id, &err, crudOp["createSucc"], crudOp["createFail"], &b)
-	res, err := ts.auth.Identify(ctx, &magistrala.IdentityReq{Token: token})
+	res, err := ts.identify(ctx, token)
 	if err != nil {
-		return Twin{}, errors.Wrap(svcerr.ErrAuthentication, err)
+		return Twin{}, err
+	}
+	// If domain is disabled, then this authorization will fail for all non-admin domain users.
+	if err := ts.authorize(ctx, res.GetId(), auth.MembershipPermission, auth.DomainType, res.GetDomainId()); err != nil {
+		return Twin{}, err
 	}
 
 	twin.ID, err = ts.idProvider.ID()
@@ -113,7 +151,8 @@ func (ts *twinsService) AddTwin(ctx context.Context, token string, twin Twin, de
 		return Twin{}, errors.Wrap(svcerr.ErrUniqueID, err)
 	}
 
-	twin.Owner = res.GetId()
+	twin.Owner = res.GetUserId()
+	twin.Domain = res.GetDomainId()
 
 	t := time.Now()
 	twin.Created = t
@@ -125,6 +164,9 @@ func (ts *twinsService) AddTwin(ctx context.Context, token string, twin Twin, de
 	if def.Delta == 0 {
 		def.Delta = millisec
 	}
+	if err := def.validate(); err != nil {
+		return Twin{}, errors.Wrap(svcerr.ErrMalformedEntity, err)
+	}
 
 	def.Created = time.Now()
 	def.ID = 0
@@ -135,6 +177,13 @@ func (ts *twinsService) AddTwin(ctx context.Context, token string, twin Twin, de
 		return Twin{}, errors.Wrap(svcerr.ErrCreateEntity, err)
 	}
 
+	if err = ts.addPolicies(ctx, res, twin.ID); err != nil {
+		if errRollback := ts.twins.Remove(ctx, twin.ID); errRollback != nil {
+			err = errors.Wrap(err, errRollback)
+		}
+		return Twin{}, err
+	}
+
 	id = twin.ID
 	b, err = json.Marshal(twin)
 
@@ -146,9 +195,8 @@ func (ts *twinsService) UpdateTwin(ctx context.Context, token string, twin Twin,
 	var id string
 	defer ts.publish(ctx, &id, &err, crudOp["updateSucc"], crudOp["updateFail"], &b)
 
-	_, err = ts.auth.Identify(ctx, &magistrala.IdentityReq{Token: token})
-	if err != nil {
-		return errors.Wrap(svcerr.ErrAuthentication, err)
+	if err = ts.authorizeTwin(ctx, token, auth.EditPermission, twin.ID); err != nil {
+		return err
 	}
 
 	tw, err := ts.twins.RetrieveByID(ctx, twin.ID)
@@ -164,6 +212,9 @@ func (ts *twinsService) UpdateTwin(ctx context.Context, token string, twin Twin,
 	}
 
 	if len(def.Attributes) > 0 {
+		if err := def.validate(); err != nil {
+			return errors.Wrap(svcerr.ErrMalformedEntity, err)
+		}
 		revision = true
 		def.Created = time.Now()
 		def.ID = tw.Definitions[len(tw.Definitions)-1].ID + 1
@@ -196,9 +247,8 @@ func (ts *twinsService) ViewTwin(ctx context.Context, token, twinID string) (tw
 	var b []byte
 	defer ts.publish(ctx, &twinID, &err, crudOp["getSucc"], crudOp["getFail"], &b)
 
-	_, err = ts.auth.Identify(ctx, &magistrala.IdentityReq{Token: token})
-	if err != nil {
-		return Twin{}, errors.Wrap(svcerr.ErrAuthorization, err)
+	if err = ts.authorizeTwin(ctx, token, auth.ViewPermission, twinID); err != nil {
+		return Twin{}, err
 	}
 
 	twin, err := ts.twins.RetrieveByID(ctx, twinID)
@@ -215,34 +265,228 @@ func (ts *twinsService) RemoveTwin(ctx context.Context, token, twinID string) (e
 	var b []byte
 	defer ts.publish(ctx, &twinID, &err, crudOp["removeSucc"], crudOp["removeFail"], &b)
 
-	_, err = ts.auth.Identify(ctx, &magistrala.IdentityReq{Token: token})
-	if err != nil {
-		return errors.Wrap(svcerr.ErrAuthentication, err)
+	if err = ts.authorizeTwin(ctx, token, auth.DeletePermission, twinID); err != nil {
+		return err
 	}
 
 	if err := ts.twins.Remove(ctx, twinID); err != nil {
 		return errors.Wrap(svcerr.ErrRemoveEntity, err)
 	}
 
-	return ts.twinCache.Remove(ctx, twinID)
+	if err := ts.twinCache.Remove(ctx, twinID); err != nil {
+		return err
+	}
+
+	return ts.removePolicies(ctx, twinID)
 }
 
 func (ts *twinsService) ListTwins(ctx context.Context, token string, offset, limit uint64, name string, metadata Metadata) (Page, error) {
-	res, err := ts.auth.Identify(ctx, &magistrala.IdentityReq{Token: token})
+	res, err := ts.identify(ctx, token)
+	if err != nil {
+		return Page{}, err
+	}
+
+	// Super admin lists all the twins of the domain.
+	if err := ts.authorize(ctx, res.GetUserId(), auth.AdminPermission, auth.PlatformType, auth.MagistralaObject); err == nil {
+		return ts.twins.RetrieveAll(ctx, res.GetDomainId(), nil, offset, limit, name, metadata)
+	}
+
+	// If domain is disabled, then this authorization will fail for all non-admin domain users.
+	if err := ts.authorize(ctx, res.GetId(), auth.MembershipPermission, auth.DomainType, res.GetDomainId()); err != nil {
+		return Page{}, err
+	}
+	ids, err := ts.auth.ListAllObjects(ctx, &magistrala.ListObjectsReq{
+		SubjectType: auth.UserType,
+		Subject:     res.GetId(),
+		Permission:  auth.ViewPermission,
+		ObjectType:  auth.TwinType,
+	})
+	if err != nil {
+		return Page{}, errors.Wrap(svcerr.ErrNotFound, err)
+	}
+	if len(ids.GetPolicies()) == 0 {
+		return Page{PageMetadata: PageMetadata{Offset: offset, Limit: limit}}, nil
+	}
+
+	return ts.twins.RetrieveAll(ctx, res.GetDomainId(), ids.GetPolicies(), offset, limit, name, metadata)
+}
+
+func (ts *twinsService) ShareTwin(ctx context.Context, token, twinID, relation string, userIDs ...string) error {
+	res, err := ts.authorizeShare(ctx, token, twinID, relation)
+	if err != nil {
+		return err
+	}
+
+	policies := magistrala.AddPoliciesReq{}
+	for _, userID := range userIDs {
+		policies.AddPoliciesReq = append(policies.AddPoliciesReq, &magistrala.AddPolicyReq{
+			Domain:      res.GetDomainId(),
+			SubjectType: auth.UserType,
+			Subject:     auth.EncodeDomainUserID(res.GetDomainId(), userID),
+			Relation:    relation,
+			ObjectType:  auth.TwinType,
+			Object:      twinID,
+		})
+	}
+	pr, err := ts.auth.AddPolicies(ctx, &policies)
+	if err != nil {
+		return errors.Wrap(errAddPolicies, err)
+	}
+	if !pr.GetAdded() {
+		return errAddPolicies
+	}
+
+	return nil
+}
+
+func (ts *twinsService) UnshareTwin(ctx context.Context, token, twinID, relation string, userIDs ...string) error {
+	res, err := ts.authorizeShare(ctx, token, twinID, relation)
+	if err != nil {
+		return err
+	}
+
+	policies := magistrala.DeletePoliciesReq{}
+	for _, userID := range userIDs {
+		policies.DeletePoliciesReq = append(policies.DeletePoliciesReq, &magistrala.DeletePolicyReq{
+			Domain:      res.GetDomainId(),
+			SubjectType: auth.UserType,
+			Subject:     auth.EncodeDomainUserID(res.GetDomainId(), userID),
+			Relation:    relation,
+			ObjectType:  auth.TwinType,
+			Object:      twinID,
+		})
+	}
+	pr, err := ts.auth.DeletePolicies(ctx, &policies)
+	if err != nil {
+		return errors.Wrap(errRemovePolicies, err)
+	}
+	if !pr.GetDeleted() {
+		return errRemovePolicies
+	}
+
+	return nil
+}
+
+func (ts *twinsService) ListStates(ctx context.Context, token string, offset, limit uint64, twinID string, filter StatesFilter) (StatesPage, error) {
+	if err := ts.authorizeTwin(ctx, token, auth.ViewPermission, twinID); err != nil {
+		return StatesPage{}, err
+	}
+
+	return ts.states.RetrieveAll(ctx, offset, limit, twinID, filter)
+}
+
+func (ts *twinsService) ViewSnapshot(ctx context.Context, token, twinID string, at time.Time, attributes []string) (Snapshot, error) {
+	if err := ts.authorizeTwin(ctx, token, auth.ViewPermission, twinID); err != nil {
+		return Snapshot{}, err
+	}
+
+	tw, err := ts.twins.RetrieveByID(ctx, twinID)
+	if err != nil {
+		return Snapshot{}, errors.Wrap(svcerr.ErrNotFound, err)
+	}
+
+	st, err := ts.states.RetrieveAt(ctx, tw.ID, at, attributes)
+	if err != nil {
+		if errors.Contains(err, errors.ErrNotFound) {
+			return Snapshot{}, errors.Wrap(svcerr.ErrNotFound, err)
+		}
+		return Snapshot{}, errors.Wrap(svcerr.ErrViewEntity, err)
+	}
+
+	snap := Snapshot{State: st}
+	for _, def := range tw.Definitions {
+		if def.ID == st.Definition {
+			snap.Definition = def
+			break
+		}
+	}
+
+	return snap, nil
+}
+
+func (ts *twinsService) SetDesired(ctx context.Context, token, twinID string, desired Desired) (d Delta, err error) {
+	var b []byte
+	defer ts.publish(ctx, &twinID, &err, crudOp["desiredSucc"], crudOp["desiredFail"], &b)
+
+	res, err := ts.identify(ctx, token)
+	if err != nil {
+		return Delta{}, err
+	}
+	if err = ts.authorize(ctx, res.GetId(), auth.EditPermission, auth.TwinType, twinID); err != nil {
+		return Delta{}, err
+	}
+	// Delta is published to the channel on behalf of the user, so the user
+	// has to be allowed to edit the channel as well.
+	if desired.Channel != "" {
+		if err = ts.authorize(ctx, res.GetId(), auth.EditPermission, auth.GroupType, desired.Channel); err != nil {
+			return Delta{}, err
+		}
+	}
+
+	tw, err := ts.twins.RetrieveByID(ctx, twinID)
+	if err != nil {
+		return Delta{}, errors.Wrap(svcerr.ErrNotFound, err)
+	}
+
+	def := tw.Definitions[len(tw.Definitions)-1]
+	for name := range desired.Attributes {
+		if findAttribute(name, def.Attributes) < 0 {
+			return Delta{}, errors.Wrap(svcerr.ErrMalformedEntity, ErrUnknownAttribute)
+		}
+	}
+
+	desired.Version = tw.Desired.Version + 1
+	desired.Updated = time.Now()
+	tw.Desired = desired
+	if err := ts.twins.Update(ctx, tw); err != nil {
+		return Delta{}, errors.Wrap(svcerr.ErrUpdateEntity, err)
+	}
+
+	st, err := ts.states.RetrieveLast(ctx, tw.ID)
 	if err != nil {
-		return Page{}, errors.Wrap(svcerr.ErrAuthentication, err)
+		return Delta{}, errors.Wrap(svcerr.ErrViewEntity, err)
+	}
+	d = delta(tw, st)
+
+	b, err = json.Marshal(d)
+	if err != nil {
+		return Delta{}, err
+	}
+
+	if d.Empty() || desired.Channel == "" {
+		return d, nil
+	}
+
+	msg := messaging.Message{
+		Channel:   desired.Channel,
+		Subtopic:  desired.Subtopic,
+		Payload:   b,
+		Publisher: publisher,
+		Created:   time.Now().UnixNano(),
+	}
+	if err := ts.publisher.Publish(ctx, msg.GetChannel(), &msg); err != nil {
+		return Delta{}, errors.Wrap(ErrPublishDelta, err)
 	}
 
-	return ts.twins.RetrieveAll(ctx, res.GetId(), offset, limit, name, metadata)
+	return d, nil
 }
 
-func (ts *twinsService) ListStates(ctx context.Context, token string, offset, limit uint64, twinID string) (StatesPage, error) {
-	_, err := ts.auth.Identify(ctx, &magistrala.IdentityReq{Token: token})
+func (ts *twinsService) ViewDelta(ctx context.Context, token, twinID string) (Delta, error) {
+	if err := ts.authorizeTwin(ctx, token, auth.ViewPermission, twinID); err != nil {
+		return Delta{}, err
+	}
+
+	tw, err := ts.twins.RetrieveByID(ctx, twinID)
+	if err != nil {
+		return Delta{}, errors.Wrap(svcerr.ErrNotFound, err)
+	}
+
+	st, err := ts.states.RetrieveLast(ctx, tw.ID)
 	if err != nil {
-		return StatesPage{}, svcerr.ErrAuthentication
+		return Delta{}, errors.Wrap(svcerr.ErrViewEntity, err)
 	}
 
-	return ts.states.RetrieveAll(ctx, offset, limit, twinID)
+	return delta(tw, st), nil
 }
 
 func (ts *twinsService) SaveStates(ctx context.Context, msg *messaging.Message) error {
@@ -342,7 +586,7 @@ func (ts *twinsService) prepareState(st *State, tw *Twin, rec senml.Record, msg
 
 	action := noop
 	for _, attr := range def.Attributes {
-		if !attr.PersistState {
+		if !attr.PersistState || attr.Computed() {
 			continue
 		}
 		if attr.Channel == msg.GetChannel() && (attr.Subtopic == SubtopicWildcard || attr.Subtopic == msg.GetSubtopic()) {
@@ -358,6 +602,7 @@ func (ts *twinsService) prepareState(st *State, tw *Twin, rec senml.Record, msg
 			}
 			val := findValue(rec)
 			st.Payload[attr.Name] = val
+			compute(def, st.Payload, attr.Name)
 
 			break
 		}
@@ -366,6 +611,111 @@ func (ts *twinsService) prepareState(st *State, tw *Twin, rec senml.Record, msg
 	return action
 }
 
+func (ts *twinsService) identify(ctx context.Context, token string) (*magistrala.IdentityRes, error) {
+	res, err := ts.auth.Identify(ctx, &magistrala.IdentityReq{Token: token})
+	if err != nil {
+		return nil, errors.Wrap(svcerr.ErrAuthentication, err)
+	}
+	if res.GetId() == "" || res.GetDomainId() == "" {
+		return nil, errors.ErrDomainAuthorization
+	}
+
+	return res, nil
+}
+
+func (ts *twinsService) authorize(ctx context.Context, subject, permission, objectType, object string) error {
+	res, err := ts.auth.Authorize(ctx, &magistrala.AuthorizeReq{
+		SubjectType: auth.UserType,
+		SubjectKind: auth.UsersKind,
+		Subject:     subject,
+		Permission:  permission,
+		ObjectType:  objectType,
+		Object:      object,
+	})
+	if err != nil {
+		return errors.Wrap(svcerr.ErrAuthorization, err)
+	}
+	if !res.GetAuthorized() {
+		return svcerr.ErrAuthorization
+	}
+
+	return nil
+}
+
+// authorizeTwin checks if the user identified by the token has the
+// permission on the twin.
+func (ts *twinsService) authorizeTwin(ctx context.Context, token, permission, twinID string) error {
+	res, err := ts.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return ts.authorize(ctx, res.GetId(), permission, auth.TwinType, twinID)
+}
+
+func (ts *twinsService) authorizeShare(ctx context.Context, token, twinID, relation string) (*magistrala.IdentityRes, error) {
+	switch relation {
+	case auth.AdministratorRelation, auth.EditorRelation, auth.ViewerRelation:
+	default:
+		return nil, errors.Wrap(svcerr.ErrMalformedEntity, ErrInvalidRelation)
+	}
+
+	res, err := ts.identify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := ts.authorize(ctx, res.GetId(), auth.SharePermission, auth.TwinType, twinID); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// addPolicies makes the user the administrator of the new twin and adds the
+// twin to the user domain.
+func (ts *twinsService) addPolicies(ctx context.Context, user *magistrala.IdentityRes, twinID string) error {
+	policies := magistrala.AddPoliciesReq{
+		AddPoliciesReq: []*magistrala.AddPolicyReq{
+			{
+				Domain:      user.GetDomainId(),
+				SubjectType: auth.UserType,
+				Subject:     user.GetId(),
+				Relation:    auth.AdministratorRelation,
+				ObjectType:  auth.TwinType,
+				Object:      twinID,
+			},
+			{
+				Domain:      user.GetDomainId(),
+				SubjectType: auth.DomainType,
+				Subject:     user.GetDomainId(),
+				Relation:    auth.DomainRelation,
+				ObjectType:  auth.TwinType,
+				Object:      twinID,
+			},
+		},
+	}
+	if _, err := ts.auth.AddPolicies(ctx, &policies); err != nil {
+		return errors.Wrap(errAddPolicies, err)
+	}
+
+	return nil
+}
+
+// removePolicies removes all the domain and user relations of the twin.
+func (ts *twinsService) removePolicies(ctx context.Context, twinID string) error {
+	for _, subjectType := range []string{auth.DomainType, auth.UserType} {
+		if _, err := ts.auth.DeletePolicy(ctx, &magistrala.DeletePolicyReq{
+			SubjectType: subjectType,
+			ObjectType:  auth.TwinType,
+			Object:      twinID,
+		}); err != nil {
+			return errors.Wrap(errRemovePolicies, err)
+		}
+	}
+
+	return nil
+}
+
 func findValue(rec senml.Record) interface{} {
 	if rec.Value != nil {
 		return rec.Value
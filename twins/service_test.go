@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/absmach/magistrala"
 	authmocks "github.com/absmach/magistrala/auth/mocks"
@@ -23,13 +24,17 @@ import (
 
 const (
 	twinName = "name"
-	wrongID  = ""
 	token    = "token"
 	email    = "user@example.com"
 	numRecs  = 100
+
+	unauthorizedChannel = "unauthorized"
 )
 
 var (
+	validID   = testsutil.GenerateUUID(&testing.T{})
+	domainID  = testsutil.GenerateUUID(&testing.T{})
+	wrongID   = testsutil.GenerateUUID(&testing.T{})
 	subtopics = []string{"engine", "chassis", "wheel_2"}
 	channels  = []string{"01ec3c3e-0e66-4e69-9751-a0545b44e08f", "48061e4f-7c23-4f5c-9012-0f9b7cd9d18d", "5b2180e4-e96b-4469-9dc1-b6745078d0b6"}
 )
@@ -39,31 +44,85 @@ func TestAddTwin(t *testing.T) {
 	twin := twins.Twin{}
 	def := twins.Definition{}
 
+	computed := mocks.CreateDefinition(channels[0:2], subtopics[0:2])
+	computed.Attributes[0].Name = "voltage"
+	computed.Attributes[1].Name = "current"
+	power := twins.Attribute{Name: "power", Expression: "voltage * current", PersistState: true}
+
 	cases := []struct {
 		desc  string
 		twin  twins.Twin
+		def   twins.Definition
 		token string
 		err   error
 	}{
 		{
 			desc:  "add new twin",
 			twin:  twin,
+			def:   def,
 			token: token,
 			err:   nil,
 		},
 		{
 			desc:  "add twin with wrong credentials",
 			twin:  twin,
+			def:   def,
 			token: authmocks.InvalidValue,
 			err:   svcerr.ErrAuthentication,
 		},
+		{
+			desc:  "add twin with computed attribute",
+			twin:  twin,
+			def:   withAttributes(computed, power),
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "add twin with malformed computed attribute expression",
+			twin:  twin,
+			def:   withAttributes(computed, twins.Attribute{Name: "power", Expression: "voltage * (current", PersistState: true}),
+			token: token,
+			err:   svcerr.ErrMalformedEntity,
+		},
+		{
+			desc:  "add twin with computed attribute over unknown attribute",
+			twin:  twin,
+			def:   withAttributes(computed, twins.Attribute{Name: "power", Expression: "voltage * resistance", PersistState: true}),
+			token: token,
+			err:   twins.ErrInvalidExpression,
+		},
+		{
+			desc:  "add twin with computed attribute having channel",
+			twin:  twin,
+			def:   withAttributes(computed, twins.Attribute{Name: "power", Channel: channels[2], Expression: "voltage * current", PersistState: true}),
+			token: token,
+			err:   twins.ErrInvalidExpression,
+		},
+		{
+			desc:  "add twin with computed attributes in reverse order",
+			twin:  twin,
+			def:   withAttributes(computed, twins.Attribute{Name: "avg", Expression: "ema(power, 0.5)", PersistState: true}, power),
+			token: token,
+			err:   twins.ErrInvalidExpression,
+		},
+		{
+			desc:  "add twin with unknown function",
+			twin:  twin,
+			def:   withAttributes(computed, twins.Attribute{Name: "power", Expression: "avg(voltage)", PersistState: true}),
+			token: token,
+			err:   twins.ErrInvalidExpression,
+		},
 	}
 
 	for _, tc := range cases {
-		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
-		_, err := svc.AddTwin(context.Background(), tc.token, tc.twin, def)
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
+		_, err := svc.AddTwin(context.Background(), tc.token, tc.twin, tc.def)
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 		repoCall.Unset()
+		repoCall1.Unset()
+		repoCall2.Unset()
 	}
 }
 
@@ -74,10 +133,14 @@ func TestUpdateTwin(t *testing.T) {
 	def := twins.Definition{}
 
 	other.ID = wrongID
-	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
 	saved, err := svc.AddTwin(context.Background(), token, twin, def)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
 
 	saved.Name = twinName
 
@@ -105,13 +168,21 @@ func TestUpdateTwin(t *testing.T) {
 			token: token,
 			err:   svcerr.ErrNotFound,
 		},
+		{
+			desc:  "update twin without permission",
+			twin:  twins.Twin{ID: authmocks.InvalidValue},
+			token: token,
+			err:   svcerr.ErrAuthorization,
+		},
 	}
 
 	for _, tc := range cases {
-		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
 		err := svc.UpdateTwin(context.Background(), tc.token, tc.twin, def)
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 		repoCall.Unset()
+		repoCall1.Unset()
 	}
 }
 
@@ -119,10 +190,14 @@ func TestViewTwin(t *testing.T) {
 	svc, auth := mocks.NewService()
 	twin := twins.Twin{}
 	def := twins.Definition{}
-	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
 	saved, err := svc.AddTwin(context.Background(), token, twin, def)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
 
 	cases := []struct {
 		desc  string
@@ -148,13 +223,21 @@ func TestViewTwin(t *testing.T) {
 			token: token,
 			err:   svcerr.ErrNotFound,
 		},
+		{
+			desc:  "view twin without permission",
+			id:    authmocks.InvalidValue,
+			token: token,
+			err:   svcerr.ErrAuthorization,
+		},
 	}
 
 	for _, tc := range cases {
-		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
 		_, err := svc.ViewTwin(context.Background(), tc.token, tc.id)
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 		repoCall.Unset()
+		repoCall1.Unset()
 	}
 }
 
@@ -168,10 +251,14 @@ func TestListTwins(t *testing.T) {
 
 	n := uint64(10)
 	for i := uint64(0); i < n; i++ {
-		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
 		_, err := svc.AddTwin(context.Background(), token, twin, def)
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 		repoCall.Unset()
+		repoCall1.Unset()
+		repoCall2.Unset()
 	}
 
 	cases := []struct {
@@ -217,10 +304,12 @@ func TestListTwins(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
 		_, err := svc.ListTwins(context.Background(), tc.token, tc.offset, tc.limit, twinName, tc.metadata)
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 		repoCall.Unset()
+		repoCall1.Unset()
 	}
 }
 
@@ -228,10 +317,14 @@ func TestRemoveTwin(t *testing.T) {
 	svc, auth := mocks.NewService()
 	twin := twins.Twin{}
 	def := twins.Definition{}
-	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
 	saved, err := svc.AddTwin(context.Background(), token, twin, def)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
 
 	cases := []struct {
 		desc  string
@@ -263,13 +356,23 @@ func TestRemoveTwin(t *testing.T) {
 			token: token,
 			err:   nil,
 		},
+		{
+			desc:  "remove twin without permission",
+			id:    authmocks.InvalidValue,
+			token: token,
+			err:   svcerr.ErrAuthorization,
+		},
 	}
 
 	for _, tc := range cases {
-		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		repoCall3 := auth.On("DeletePolicy", mock.Anything, mock.Anything).Return(&magistrala.DeletePolicyRes{Deleted: true}, nil)
 		err := svc.RemoveTwin(context.Background(), tc.token, tc.id)
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 		repoCall.Unset()
+		repoCall1.Unset()
+		repoCall3.Unset()
 	}
 }
 
@@ -280,16 +383,24 @@ func TestSaveStates(t *testing.T) {
 	def := mocks.CreateDefinition(channels[0:2], subtopics[0:2])
 	attr := def.Attributes[0]
 	attrSansTwin := mocks.CreateDefinition(channels[2:3], subtopics[2:3]).Attributes[0]
-	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
 	tw, err := svc.AddTwin(context.Background(), token, twin, def)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
 
 	defWildcard := mocks.CreateDefinition(channels[0:2], []string{twins.SubtopicWildcard, twins.SubtopicWildcard})
-	repoCall = auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+	repoCall = auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 = auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 = auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
 	twWildcard, err := svc.AddTwin(context.Background(), token, twin, defWildcard)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
 
 	recs := make([]senml.Record, numRecs)
 	mocks.CreateSenML(recs)
@@ -334,7 +445,8 @@ func TestSaveStates(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
 		message, err := mocks.CreateMessage(tc.attr, tc.recs)
 		assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
@@ -342,14 +454,85 @@ func TestSaveStates(t *testing.T) {
 		assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 		ttlAdded += tc.size
-		page, err := svc.ListStates(context.TODO(), token, 0, 10, tw.ID)
+		page, err := svc.ListStates(context.TODO(), token, 0, 10, tw.ID, twins.StatesFilter{})
 		assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 		assert.Equal(t, ttlAdded, page.Total, fmt.Sprintf("%s: expected %d total got %d total\n", tc.desc, ttlAdded, page.Total))
 
-		page, err = svc.ListStates(context.TODO(), token, 0, 10, twWildcard.ID)
+		page, err = svc.ListStates(context.TODO(), token, 0, 10, twWildcard.ID, twins.StatesFilter{})
 		assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 		assert.Equal(t, ttlAdded, page.Total, fmt.Sprintf("%s: expected %d total got %d total\n", tc.desc, ttlAdded, page.Total))
 		repoCall.Unset()
+		repoCall1.Unset()
+	}
+}
+
+func TestSaveStatesComputed(t *testing.T) {
+	svc, auth := mocks.NewService()
+
+	def := mocks.CreateDefinition(channels[0:2], subtopics[0:2])
+	def.Attributes[0].Name = "voltage"
+	def.Attributes[1].Name = "current"
+	def = withAttributes(def,
+		twins.Attribute{Name: "power", Expression: "voltage * current", PersistState: true},
+		twins.Attribute{Name: "avg_power", Expression: "ema(power, 0.5)", PersistState: true},
+	)
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
+	tw, err := svc.AddTwin(context.Background(), token, twins.Twin{Owner: email}, def)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
+
+	cases := []struct {
+		desc     string
+		attr     twins.Attribute
+		value    float64
+		computed map[string]interface{}
+	}{
+		{
+			desc:     "save state without all inputs",
+			attr:     def.Attributes[0],
+			value:    230,
+			computed: map[string]interface{}{},
+		},
+		{
+			desc:     "save state with all inputs",
+			attr:     def.Attributes[1],
+			value:    2,
+			computed: map[string]interface{}{"power": 460.0, "avg_power": 460.0},
+		},
+		{
+			desc:     "save state with changed input",
+			attr:     def.Attributes[1],
+			value:    3,
+			computed: map[string]interface{}{"power": 690.0, "avg_power": 575.0},
+		},
+	}
+
+	for i, tc := range cases {
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		value := tc.value
+		message, err := mocks.CreateMessage(tc.attr, []senml.Record{{Value: &value}})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		err = svc.SaveStates(context.Background(), message)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		page, err := svc.ListStates(context.Background(), token, uint64(i), 1, tw.ID, twins.StatesFilter{})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		require.Len(t, page.States, 1, fmt.Sprintf("%s: expected one state", tc.desc))
+		for _, name := range []string{"power", "avg_power"} {
+			want, ok := tc.computed[name]
+			got, found := page.States[0].Payload[name]
+			assert.Equal(t, ok, found, fmt.Sprintf("%s: expected %s to be present %t got %t\n", tc.desc, name, ok, found))
+			if ok {
+				assert.Equal(t, want, got, fmt.Sprintf("%s: expected %s %v got %v\n", tc.desc, name, want, got))
+			}
+		}
+		repoCall.Unset()
+		repoCall1.Unset()
 	}
 }
 
@@ -359,23 +542,31 @@ func TestListStates(t *testing.T) {
 	twin := twins.Twin{Owner: email}
 	def := mocks.CreateDefinition(channels[0:2], subtopics[0:2])
 	attr := def.Attributes[0]
-	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
 	tw, err := svc.AddTwin(context.Background(), token, twin, def)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
 
-	repoCall = auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+	repoCall = auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 = auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 = auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
 	tw2, err := svc.AddTwin(context.Background(), token,
 		twins.Twin{Owner: email},
 		mocks.CreateDefinition(channels[2:3], subtopics[2:3]))
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
 
 	recs := make([]senml.Record, numRecs)
 	mocks.CreateSenML(recs)
 	message, err := mocks.CreateMessage(attr, recs)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-	repoCall = auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
+	repoCall = auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
 	err = svc.SaveStates(context.Background(), message)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	repoCall.Unset()
@@ -434,6 +625,15 @@ func TestListStates(t *testing.T) {
 			size:   0,
 			err:    svcerr.ErrAuthentication,
 		},
+		{
+			desc:   "get a list without permission",
+			id:     authmocks.InvalidValue,
+			token:  token,
+			offset: 0,
+			limit:  10,
+			size:   0,
+			err:    svcerr.ErrAuthorization,
+		},
 		{
 			desc:   "get a list with id of non-existent twin",
 			id:     "1234567890",
@@ -455,10 +655,491 @@ func TestListStates(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: testsutil.GenerateUUID(t)}, nil)
-		page, err := svc.ListStates(context.TODO(), tc.token, tc.offset, tc.limit, tc.id)
-		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		page, err := svc.ListStates(context.TODO(), tc.token, tc.offset, tc.limit, tc.id, twins.StatesFilter{})
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 		assert.Equal(t, tc.size, len(page.States), fmt.Sprintf("%s: expected %d total got %d total\n", tc.desc, tc.size, len(page.States)))
 		repoCall.Unset()
+		repoCall1.Unset()
+	}
+}
+
+// saveHistory saves the states of the twin attributes named temperature and
+// humidity, recorded with two twin definitions, starting from the time t0.
+func saveHistory(t *testing.T, svc twins.Service, auth *authmocks.AuthClient, t0 time.Time) twins.Twin {
+	def := mocks.CreateDefinition(channels[0:2], subtopics[0:2])
+	def.Attributes[0].Name = "temperature"
+	def.Attributes[1].Name = "humidity"
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
+	defer repoCall.Unset()
+	defer repoCall1.Unset()
+	defer repoCall2.Unset()
+
+	tw, err := svc.AddTwin(context.Background(), token, twins.Twin{Owner: email}, def)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	save := func(attr twins.Attribute, start, n int, value float64) {
+		recs := make([]senml.Record, n)
+		for i := range recs {
+			v := value + float64(i)
+			recs[i] = senml.Record{BaseTime: float64(t0.Unix()), Time: float64(start + i*10), Value: &v}
+		}
+		message, err := mocks.CreateMessage(attr, recs)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		err = svc.SaveStates(context.Background(), message)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	// States 0-9 recorded with definition 0 every 10 seconds.
+	save(def.Attributes[0], 0, 10, 0)
+	err = svc.UpdateTwin(context.Background(), token, twins.Twin{ID: tw.ID}, def)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	// States 10-14 recorded with definition 1 starting from 100 seconds.
+	save(def.Attributes[0], 100, 5, 100)
+	// State 15 recorded with definition 1 at 200 seconds.
+	save(def.Attributes[1], 200, 1, 50)
+
+	return tw
+}
+
+func TestListStatesFiltered(t *testing.T) {
+	svc, auth := mocks.NewService()
+	t0 := time.Now().Add(-time.Hour).Truncate(time.Second)
+	tw := saveHistory(t, svc, auth, t0)
+
+	def0, def1 := 0, 1
+
+	cases := []struct {
+		desc   string
+		filter twins.StatesFilter
+		size   int
+		total  uint64
+	}{
+		{
+			desc:   "list states created from time",
+			filter: twins.StatesFilter{From: t0.Add(50 * time.Second)},
+			size:   11,
+			total:  11,
+		},
+		{
+			desc:   "list states created to time",
+			filter: twins.StatesFilter{To: t0.Add(50 * time.Second)},
+			size:   6,
+			total:  6,
+		},
+		{
+			desc:   "list states created within time range",
+			filter: twins.StatesFilter{From: t0.Add(20 * time.Second), To: t0.Add(40 * time.Second)},
+			size:   3,
+			total:  3,
+		},
+		{
+			desc:   "list states recorded with the first definition",
+			filter: twins.StatesFilter{Definition: &def0},
+			size:   10,
+			total:  10,
+		},
+		{
+			desc:   "list states recorded with the second definition",
+			filter: twins.StatesFilter{Definition: &def1},
+			size:   6,
+			total:  6,
+		},
+		{
+			desc:   "list states with time range out of history",
+			filter: twins.StatesFilter{From: t0.Add(time.Hour)},
+			size:   0,
+			total:  0,
+		},
+		{
+			desc:   "list states limited to attributes",
+			filter: twins.StatesFilter{Definition: &def1, Attributes: []string{"humidity"}},
+			size:   6,
+			total:  6,
+		},
+	}
+
+	for _, tc := range cases {
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		page, err := svc.ListStates(context.Background(), token, 0, 100, tw.ID, tc.filter)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.size, len(page.States), fmt.Sprintf("%s: expected %d states got %d\n", tc.desc, tc.size, len(page.States)))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected %d total got %d\n", tc.desc, tc.total, page.Total))
+		for _, st := range page.States {
+			for name := range st.Payload {
+				if len(tc.filter.Attributes) > 0 {
+					assert.Contains(t, tc.filter.Attributes, name, fmt.Sprintf("%s: unexpected attribute %s\n", tc.desc, name))
+				}
+			}
+		}
+		repoCall.Unset()
+		repoCall1.Unset()
+	}
+}
+
+func TestViewSnapshot(t *testing.T) {
+	svc, auth := mocks.NewService()
+	t0 := time.Now().Add(-time.Hour).Truncate(time.Second)
+	tw := saveHistory(t, svc, auth, t0)
+
+	temp2, temp102, hum := 2.0, 102.0, 50.0
+
+	cases := []struct {
+		desc       string
+		id         string
+		token      string
+		at         time.Time
+		attributes []string
+		state      int64
+		definition int
+		payload    map[string]interface{}
+		err        error
+	}{
+		{
+			desc:       "view snapshot between states",
+			id:         tw.ID,
+			token:      token,
+			at:         t0.Add(25 * time.Second),
+			state:      2,
+			definition: 0,
+			payload:    map[string]interface{}{"temperature": &temp2},
+			err:        nil,
+		},
+		{
+			desc:       "view snapshot at state creation time",
+			id:         tw.ID,
+			token:      token,
+			at:         t0.Add(120 * time.Second),
+			state:      12,
+			definition: 1,
+			payload:    map[string]interface{}{"temperature": &temp102},
+			err:        nil,
+		},
+		{
+			desc:       "view snapshot limited to attributes",
+			id:         tw.ID,
+			token:      token,
+			at:         t0.Add(time.Hour),
+			attributes: []string{"humidity"},
+			state:      15,
+			definition: 1,
+			payload:    map[string]interface{}{"humidity": &hum},
+			err:        nil,
+		},
+		{
+			desc:  "view snapshot before the first state",
+			id:    tw.ID,
+			token: token,
+			at:    t0.Add(-time.Second),
+			err:   svcerr.ErrNotFound,
+		},
+		{
+			desc:  "view snapshot of non-existing twin",
+			id:    wrongID,
+			token: token,
+			at:    t0.Add(time.Hour),
+			err:   svcerr.ErrNotFound,
+		},
+		{
+			desc:  "view snapshot with invalid token",
+			id:    tw.ID,
+			token: authmocks.InvalidValue,
+			at:    t0.Add(time.Hour),
+			err:   svcerr.ErrAuthentication,
+		},
+	}
+
+	for _, tc := range cases {
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		snap, err := svc.ViewSnapshot(context.Background(), tc.token, tc.id, tc.at, tc.attributes)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.state, snap.State.ID, fmt.Sprintf("%s: expected state %d got %d\n", tc.desc, tc.state, snap.State.ID))
+			assert.Equal(t, tc.definition, snap.Definition.ID, fmt.Sprintf("%s: expected definition %d got %d\n", tc.desc, tc.definition, snap.Definition.ID))
+			assert.Equal(t, tc.payload, snap.State.Payload, fmt.Sprintf("%s: expected payload %v got %v\n", tc.desc, tc.payload, snap.State.Payload))
+		}
+		repoCall.Unset()
+		repoCall1.Unset()
+	}
+}
+
+func TestSetDesired(t *testing.T) {
+	svc, auth := mocks.NewService()
+
+	def := mocks.CreateDefinition(channels[0:2], subtopics[0:2])
+	def.Attributes[0].Name = "temperature"
+	def.Attributes[1].Name = "mode"
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
+	tw, err := svc.AddTwin(context.Background(), token, twins.Twin{Owner: email}, def)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
+
+	temp := 21.0
+	message, err := mocks.CreateMessage(def.Attributes[0], []senml.Record{{Value: &temp}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.SaveStates(context.Background(), message)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		id      string
+		token   string
+		desired twins.Desired
+		delta   map[string]interface{}
+		version uint64
+		authErr error
+		err     error
+	}{
+		{
+			desc:    "set desired state different from reported state",
+			id:      tw.ID,
+			token:   token,
+			desired: twins.Desired{Attributes: map[string]interface{}{"temperature": 25.0}, Channel: "control"},
+			delta:   map[string]interface{}{"temperature": 25.0},
+			version: 1,
+			err:     nil,
+		},
+		{
+			desc:    "set desired state of attribute without reported state",
+			id:      tw.ID,
+			token:   token,
+			desired: twins.Desired{Attributes: map[string]interface{}{"temperature": 21.0, "mode": "eco"}, Channel: "control", Subtopic: "commands"},
+			delta:   map[string]interface{}{"mode": "eco"},
+			version: 2,
+			err:     nil,
+		},
+		{
+			desc:    "set desired state matching reported state",
+			id:      tw.ID,
+			token:   token,
+			desired: twins.Desired{Attributes: map[string]interface{}{"temperature": 21}, Channel: "control"},
+			delta:   map[string]interface{}{},
+			version: 3,
+			err:     nil,
+		},
+		{
+			desc:    "set desired state of unknown attribute",
+			id:      tw.ID,
+			token:   token,
+			desired: twins.Desired{Attributes: map[string]interface{}{"pressure": 1.0}},
+			err:     svcerr.ErrMalformedEntity,
+		},
+		{
+			desc:    "set desired state with unauthorized channel",
+			id:      tw.ID,
+			token:   token,
+			desired: twins.Desired{Attributes: map[string]interface{}{"temperature": 30.0}, Channel: unauthorizedChannel},
+			err:     svcerr.ErrAuthorization,
+		},
+		{
+			desc:    "set desired state after unauthorized channel",
+			id:      tw.ID,
+			token:   token,
+			desired: twins.Desired{Attributes: map[string]interface{}{"temperature": 25.0}, Channel: "control"},
+			delta:   map[string]interface{}{"temperature": 25.0},
+			version: 4,
+			err:     nil,
+		},
+		{
+			desc:    "set desired state with invalid token",
+			id:      tw.ID,
+			token:   authmocks.InvalidValue,
+			desired: twins.Desired{Attributes: map[string]interface{}{"temperature": 25.0}},
+			authErr: svcerr.ErrAuthentication,
+			err:     svcerr.ErrAuthentication,
+		},
+		{
+			desc:    "set desired state of non-existing twin",
+			id:      wrongID,
+			token:   token,
+			desired: twins.Desired{Attributes: map[string]interface{}{"temperature": 25.0}},
+			err:     svcerr.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, tc.authErr)
+		auth.On("Authorize", mock.Anything, mock.MatchedBy(func(req *magistrala.AuthorizeReq) bool {
+			return req.GetObject() == unauthorizedChannel
+		})).Return(&magistrala.AuthorizeRes{Authorized: false}, nil)
+		auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		delta, err := svc.SetDesired(context.Background(), tc.token, tc.id, tc.desired)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.delta, delta.Attributes, fmt.Sprintf("%s: expected delta %v got %v\n", tc.desc, tc.delta, delta.Attributes))
+			assert.Equal(t, tc.version, delta.Version, fmt.Sprintf("%s: expected version %d got %d\n", tc.desc, tc.version, delta.Version))
+		}
+		// Unset can not match the MatchedBy argument, so the expectations
+		// are reset instead.
+		auth.ExpectedCalls = nil
+	}
+}
+
+func TestViewDelta(t *testing.T) {
+	svc, auth := mocks.NewService()
+
+	def := mocks.CreateDefinition(channels[0:1], subtopics[0:1])
+	def.Attributes[0].Name = "temperature"
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
+	tw, err := svc.AddTwin(context.Background(), token, twins.Twin{Owner: email}, def)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = svc.SetDesired(context.Background(), token, tw.ID, twins.Desired{Attributes: map[string]interface{}{"temperature": 25.0}, Channel: "control"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
+
+	cases := []struct {
+		desc    string
+		id      string
+		token   string
+		temp    float64
+		delta   map[string]interface{}
+		authErr error
+		err     error
+	}{
+		{
+			desc:  "view delta of twin reporting different state",
+			id:    tw.ID,
+			token: token,
+			temp:  21,
+			delta: map[string]interface{}{"temperature": 25.0},
+			err:   nil,
+		},
+		{
+			desc:  "view delta of twin reporting desired state",
+			id:    tw.ID,
+			token: token,
+			temp:  25,
+			delta: map[string]interface{}{},
+			err:   nil,
+		},
+		{
+			desc:    "view delta with invalid token",
+			id:      tw.ID,
+			token:   authmocks.InvalidValue,
+			authErr: svcerr.ErrAuthentication,
+			err:     svcerr.ErrAuthentication,
+		},
+		{
+			desc:  "view delta of non-existing twin",
+			id:    wrongID,
+			token: token,
+			err:   svcerr.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		if tc.delta != nil {
+			temp := tc.temp
+			message, err := mocks.CreateMessage(def.Attributes[0], []senml.Record{{Value: &temp}})
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+			err = svc.SaveStates(context.Background(), message)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		}
+
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, tc.authErr)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		delta, err := svc.ViewDelta(context.Background(), tc.token, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.delta, delta.Attributes, fmt.Sprintf("%s: expected delta %v got %v\n", tc.desc, tc.delta, delta.Attributes))
+		}
+		repoCall.Unset()
+		repoCall1.Unset()
 	}
 }
+
+func TestShareTwin(t *testing.T) {
+	svc, auth := mocks.NewService()
+	repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+	repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+	repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(&magistrala.AddPoliciesRes{Added: true}, nil)
+	tw, err := svc.AddTwin(context.Background(), token, twins.Twin{}, twins.Definition{})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	repoCall.Unset()
+	repoCall1.Unset()
+	repoCall2.Unset()
+
+	userID := testsutil.GenerateUUID(t)
+
+	cases := []struct {
+		desc     string
+		id       string
+		token    string
+		relation string
+		res      *magistrala.AddPoliciesRes
+		err      error
+	}{
+		{
+			desc:     "share twin with viewer relation",
+			id:       tw.ID,
+			token:    token,
+			relation: "viewer",
+			res:      &magistrala.AddPoliciesRes{Added: true},
+			err:      nil,
+		},
+		{
+			desc:     "share twin with invalid relation",
+			id:       tw.ID,
+			token:    token,
+			relation: "owner",
+			err:      twins.ErrInvalidRelation,
+		},
+		{
+			desc:     "share twin with wrong credentials",
+			id:       tw.ID,
+			token:    authmocks.InvalidValue,
+			relation: "editor",
+			err:      svcerr.ErrAuthentication,
+		},
+		{
+			desc:     "share twin without permission",
+			id:       authmocks.InvalidValue,
+			token:    token,
+			relation: "editor",
+			err:      svcerr.ErrAuthorization,
+		},
+		{
+			desc:     "share twin with failed policies",
+			id:       tw.ID,
+			token:    token,
+			relation: "administrator",
+			res:      &magistrala.AddPoliciesRes{Added: false},
+			err:      errors.New("failed to add policies"),
+		},
+	}
+
+	for _, tc := range cases {
+		repoCall := auth.On("Identify", mock.Anything, &magistrala.IdentityReq{Token: tc.token}).Return(&magistrala.IdentityRes{Id: validID, UserId: validID, DomainId: domainID}, nil)
+		repoCall1 := auth.On("Authorize", mock.Anything, mock.Anything).Return(&magistrala.AuthorizeRes{Authorized: true}, nil)
+		repoCall2 := auth.On("AddPolicies", mock.Anything, mock.Anything).Return(tc.res, nil)
+		repoCall3 := auth.On("DeletePolicies", mock.Anything, mock.Anything).Return(&magistrala.DeletePoliciesRes{Deleted: true}, nil)
+		err := svc.ShareTwin(context.Background(), tc.token, tc.id, tc.relation, userID)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err == nil {
+			err = svc.UnshareTwin(context.Background(), tc.token, tc.id, tc.relation, userID)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected unshare error: %s\n", tc.desc, err))
+		}
+		repoCall.Unset()
+		repoCall1.Unset()
+		repoCall2.Unset()
+		repoCall3.Unset()
+	}
+}
+
+func withAttributes(def twins.Definition, attrs ...twins.Attribute) twins.Definition {
+	def.Attributes = append(append([]twins.Attribute{}, def.Attributes...), attrs...)
+	return def
+}
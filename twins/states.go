@@ -17,6 +17,29 @@ type State struct {
 	Payload    map[string]interface{}
 }
 
+// StatesFilter narrows the states retrieved from the twin states history.
+type StatesFilter struct {
+	// From and To bound the state creation time, inclusively. Zero value
+	// leaves the corresponding side of the range open.
+	From time.Time
+	To   time.Time
+
+	// Definition, if set, selects the states recorded using the twin
+	// definition having the provided ID.
+	Definition *int
+
+	// Attributes, if not empty, limits the state payloads to the provided
+	// attributes.
+	Attributes []string
+}
+
+// Snapshot represents the twin state in effect at the given moment, along
+// with the twin definition the state was recorded with.
+type Snapshot struct {
+	State      State
+	Definition Definition
+}
+
 // StatesPage contains page related metadata as well as a list of twins that
 // belong to this page.
 type StatesPage struct {
@@ -36,7 +59,12 @@ type StateRepository interface {
 	Count(ctx context.Context, twin Twin) (int64, error)
 
 	// RetrieveAll retrieves the subset of states related to twin specified by id
-	RetrieveAll(ctx context.Context, offset uint64, limit uint64, twinID string) (StatesPage, error)
+	// and matching the filter, ordered by the state ID.
+	RetrieveAll(ctx context.Context, offset uint64, limit uint64, twinID string, filter StatesFilter) (StatesPage, error)
+
+	// RetrieveAt retrieves the last state of the twin created at or before the
+	// provided time. Payload is limited to the provided attributes, if any.
+	RetrieveAt(ctx context.Context, twinID string, at time.Time, attributes []string) (State, error)
 
 	// RetrieveLast retrieves the last saved state
 	RetrieveLast(ctx context.Context, twinID string) (State, error)
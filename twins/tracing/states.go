@@ -5,6 +5,7 @@ package tracing
 
 import (
 	"context"
+	"time"
 
 	"github.com/absmach/magistrala/twins"
 	"go.opentelemetry.io/otel/trace"
@@ -15,6 +16,8 @@ const (
 	updateStateOp       = "update_state"
 	countStatesOp       = "count_states"
 	retrieveAllStatesOp = "retrieve_all_states"
+	retrieveStateAtOp   = "retrieve_state_at"
+	retrieveLastStateOp = "retrieve_last_state"
 )
 
 var _ twins.StateRepository = (*stateRepositoryMiddleware)(nil)
@@ -54,15 +57,22 @@ func (trm stateRepositoryMiddleware) Count(ctx context.Context, tw twins.Twin) (
 	return trm.repo.Count(ctx, tw)
 }
 
-func (trm stateRepositoryMiddleware) RetrieveAll(ctx context.Context, offset, limit uint64, twinID string) (twins.StatesPage, error) {
+func (trm stateRepositoryMiddleware) RetrieveAll(ctx context.Context, offset, limit uint64, twinID string, filter twins.StatesFilter) (twins.StatesPage, error) {
 	ctx, span := createSpan(ctx, trm.tracer, retrieveAllStatesOp)
 	defer span.End()
 
-	return trm.repo.RetrieveAll(ctx, offset, limit, twinID)
+	return trm.repo.RetrieveAll(ctx, offset, limit, twinID, filter)
+}
+
+func (trm stateRepositoryMiddleware) RetrieveAt(ctx context.Context, twinID string, at time.Time, attributes []string) (twins.State, error) {
+	ctx, span := createSpan(ctx, trm.tracer, retrieveStateAtOp)
+	defer span.End()
+
+	return trm.repo.RetrieveAt(ctx, twinID, at, attributes)
 }
 
 func (trm stateRepositoryMiddleware) RetrieveLast(ctx context.Context, twinID string) (twins.State, error) {
-	ctx, span := createSpan(ctx, trm.tracer, retrieveAllStatesOp)
+	ctx, span := createSpan(ctx, trm.tracer, retrieveLastStateOp)
 	defer span.End()
 
 	return trm.repo.RetrieveLast(ctx, twinID)
@@ -56,11 +56,11 @@ func (trm twinRepositoryMiddleware) RetrieveByID(ctx context.Context, twinID str
 	return trm.repo.RetrieveByID(ctx, twinID)
 }
 
-func (trm twinRepositoryMiddleware) RetrieveAll(ctx context.Context, owner string, offset, limit uint64, name string, metadata twins.Metadata) (twins.Page, error) {
+func (trm twinRepositoryMiddleware) RetrieveAll(ctx context.Context, domain string, ids []string, offset, limit uint64, name string, metadata twins.Metadata) (twins.Page, error) {
 	ctx, span := createSpan(ctx, trm.tracer, retrieveAllTwinsOp)
 	defer span.End()
 
-	return trm.repo.RetrieveAll(ctx, owner, offset, limit, name, metadata)
+	return trm.repo.RetrieveAll(ctx, domain, ids, offset, limit, name, metadata)
 }
 
 func (trm twinRepositoryMiddleware) RetrieveByAttribute(ctx context.Context, channel, subtopic string) ([]string, error) {
@@ -11,12 +11,21 @@ import (
 // Metadata stores arbitrary twin data.
 type Metadata map[string]interface{}
 
-// Attribute stores individual attribute data.
+// Attribute stores individual attribute data. Computed attributes have no
+// channel and their value is evaluated from the expression over the other
+// attributes of the same definition.
 type Attribute struct {
 	Name         string `json:"name"`
 	Channel      string `json:"channel"`
 	Subtopic     string `json:"subtopic"`
 	PersistState bool   `json:"persist_state"`
+	Expression   string `json:"expression,omitempty"`
+}
+
+// Computed reports whether the attribute value is computed from the other
+// attributes.
+func (attr Attribute) Computed() bool {
+	return attr.Expression != ""
 }
 
 // Definition stores entity's attributes.
@@ -27,10 +36,12 @@ type Definition struct {
 	Delta      int64       `json:"delta"`
 }
 
-// Twin is a Magistrala data system representation. Each twin is owned
-// by a single user, and is assigned with the unique identifier.
+// Twin is a Magistrala data system representation. Each twin belongs to
+// a domain, is created by a single user, and is assigned with the unique
+// identifier.
 type Twin struct {
 	Owner       string
+	Domain      string
 	ID          string
 	Name        string
 	Created     time.Time
@@ -38,6 +49,7 @@ type Twin struct {
 	Revision    int
 	Definitions []Definition
 	Metadata    Metadata
+	Desired     Desired
 }
 
 // PageMetadata contains page metadata that helps navigation.
@@ -70,8 +82,10 @@ type TwinRepository interface {
 	// the attribute with given channel and subtopic
 	RetrieveByAttribute(ctx context.Context, channel, subtopic string) ([]string, error)
 
-	// RetrieveAll retrieves the subset of twins owned by the specified user.
-	RetrieveAll(ctx context.Context, owner string, offset, limit uint64, name string, metadata Metadata) (Page, error)
+	// RetrieveAll retrieves the subset of twins belonging to the domain and
+	// having one of the provided IDs. Empty domain and nil IDs are not used
+	// for filtering.
+	RetrieveAll(ctx context.Context, domain string, ids []string, offset, limit uint64, name string, metadata Metadata) (Page, error)
 
 	// Remove removes the twin having the provided identifier.
 	Remove(ctx context.Context, twinID string) error
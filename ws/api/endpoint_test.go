@@ -94,7 +94,7 @@ func TestHandshake(t *testing.T) {
 	svc, pubsub := newService(auth)
 	target := newHTTPServer(svc)
 	defer target.Close()
-	handler := ws.NewHandler(pubsub, mglog.NewMock(), auth)
+	handler := ws.NewHandler(pubsub, mglog.NewMock(), auth, nil)
 	ts, err := newProxyHTPPServer(handler, target)
 	require.Nil(t, err)
 	defer ts.Close()
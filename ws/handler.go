@@ -14,6 +14,7 @@ import (
 
 	"github.com/absmach/magistrala"
 	"github.com/absmach/magistrala/auth"
+	"github.com/absmach/magistrala/pkg/certauth"
 	"github.com/absmach/magistrala/pkg/errors"
 	svcerr "github.com/absmach/magistrala/pkg/errors/service"
 	"github.com/absmach/magistrala/pkg/messaging"
@@ -55,17 +56,22 @@ var channelRegExp = regexp.MustCompile(`^\/?channels\/([\w\-]+)\/messages(\/[^?]
 
 // Event implements events.Event interface.
 type handler struct {
-	pubsub messaging.PubSub
-	auth   magistrala.AuthzServiceClient
-	logger *slog.Logger
+	pubsub   messaging.PubSub
+	auth     magistrala.AuthzServiceClient
+	certAuth certauth.Authenticator
+	logger   *slog.Logger
 }
 
-// NewHandler creates new Handler entity.
-func NewHandler(pubsub messaging.PubSub, logger *slog.Logger, authClient magistrala.AuthzServiceClient) session.Handler {
+// NewHandler creates new Handler entity. Unless the certificate authenticator
+// is nil, the things presenting the client certificate are authenticated
+// using the certificate instead of the thing key, and authorized by the ID of
+// the thing the certificate is issued to.
+func NewHandler(pubsub messaging.PubSub, logger *slog.Logger, authClient magistrala.AuthzServiceClient, certAuth certauth.Authenticator) session.Handler {
 	return &handler{
-		logger: logger,
-		pubsub: pubsub,
-		auth:   authClient,
+		logger:   logger,
+		pubsub:   pubsub,
+		auth:     authClient,
+		certAuth: certAuth,
 	}
 }
 
@@ -86,15 +92,12 @@ func (h *handler) AuthPublish(ctx context.Context, topic *string, payload *[]byt
 		return ErrClientNotInitialized
 	}
 
-	var token string
-	switch {
-	case strings.HasPrefix(string(s.Password), "Thing"):
-		token = strings.ReplaceAll(string(s.Password), "Thing ", "")
-	default:
-		token = string(s.Password)
+	token, kind, err := h.subject(ctx, s)
+	if err != nil {
+		return err
 	}
 
-	return h.authAccess(ctx, token, *topic, auth.PublishPermission)
+	return h.authAccess(ctx, token, kind, *topic, auth.PublishPermission)
 }
 
 // AuthSubscribe is called on device publish,
@@ -108,16 +111,13 @@ func (h *handler) AuthSubscribe(ctx context.Context, topics *[]string) error {
 		return ErrMissingTopicSub
 	}
 
-	var token string
-	switch {
-	case strings.HasPrefix(string(s.Password), "Thing"):
-		token = strings.ReplaceAll(string(s.Password), "Thing ", "")
-	default:
-		token = string(s.Password)
+	token, kind, err := h.subject(ctx, s)
+	if err != nil {
+		return err
 	}
 
 	for _, v := range *topics {
-		if err := h.authAccess(ctx, token, v, auth.SubscribePermission); err != nil {
+		if err := h.authAccess(ctx, token, kind, v, auth.SubscribePermission); err != nil {
 			return err
 		}
 	}
@@ -157,16 +157,14 @@ func (h *handler) Publish(ctx context.Context, topic *string, payload *[]byte) e
 		return errors.Wrap(ErrFailedParseSubtopic, err)
 	}
 
-	var token string
-	switch {
-	case strings.HasPrefix(string(s.Password), "Thing"):
-		token = strings.ReplaceAll(string(s.Password), "Thing ", "")
-	default:
-		token = string(s.Password)
+	token, kind, err := h.subject(ctx, s)
+	if err != nil {
+		return err
 	}
 
 	ar := &magistrala.AuthorizeReq{
 		SubjectType: auth.ThingType,
+		SubjectKind: kind,
 		Permission:  auth.PublishPermission,
 		Subject:     token,
 		Object:      chanID,
@@ -222,7 +220,27 @@ func (h *handler) Disconnect(ctx context.Context) error {
 	return nil
 }
 
-func (h *handler) authAccess(ctx context.Context, password, topic, action string) error {
+// subject returns the authorization subject and subject kind of the session
+// thing. Things presenting the client certificate are identified by the ID
+// of the thing the certificate is issued to, and the rest by the thing key
+// of the session password.
+func (h *handler) subject(ctx context.Context, s *session.Session) (string, string, error) {
+	if cert := certauth.ClientCert(ctx, s.Cert); h.certAuth != nil && cert != nil {
+		id, err := h.certAuth.Authenticate(ctx, cert)
+		if err != nil {
+			return "", "", err
+		}
+		return id, auth.ThingsKind, nil
+	}
+
+	if strings.HasPrefix(string(s.Password), "Thing") {
+		return strings.ReplaceAll(string(s.Password), "Thing ", ""), "", nil
+	}
+
+	return string(s.Password), "", nil
+}
+
+func (h *handler) authAccess(ctx context.Context, password, kind, topic, action string) error {
 	// Topics are in the format:
 	// channels/<channel_id>/messages/<subtopic>/.../ct/<content_type>
 	if !channelRegExp.MatchString(topic) {
@@ -238,6 +256,7 @@ func (h *handler) authAccess(ctx context.Context, password, topic, action string
 
 	ar := &magistrala.AuthorizeReq{
 		SubjectType: auth.ThingType,
+		SubjectKind: kind,
 		Permission:  action,
 		Subject:     password,
 		Object:      chanID,